@@ -0,0 +1,225 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/cjdelisle/matterfoss-server/v6/audit"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+func (api *API) InitChannelBookmark() {
+	api.BaseRoutes.ChannelBookmarks.Handle("", api.APISessionRequired(getChannelBookmarks)).Methods("GET")
+	api.BaseRoutes.ChannelBookmarks.Handle("", api.APISessionRequired(createChannelBookmark)).Methods("POST")
+	api.BaseRoutes.ChannelBookmark.Handle("", api.APISessionRequired(patchChannelBookmark)).Methods("PATCH")
+	api.BaseRoutes.ChannelBookmark.Handle("/sort_order", api.APISessionRequired(updateChannelBookmarkSortOrder)).Methods("POST")
+	api.BaseRoutes.ChannelBookmark.Handle("", api.APISessionRequired(deleteChannelBookmark)).Methods("DELETE")
+}
+
+func getChannelBookmarks(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	var since int64
+	if sinceString := r.URL.Query().Get("since"); sinceString != "" {
+		var parseError error
+		since, parseError = strconv.ParseInt(sinceString, 10, 64)
+		if parseError != nil {
+			c.SetInvalidParam("since")
+			return
+		}
+	}
+
+	if !c.App.SessionHasPermissionToChannel(*c.AppContext.Session(), c.Params.ChannelId, model.PermissionReadChannel) {
+		c.SetPermissionError(model.PermissionReadChannel)
+		return
+	}
+
+	bookmarks, err := c.App.GetChannelBookmarks(c.Params.ChannelId, since)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(bookmarks); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func createChannelBookmark(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId()
+	if c.Err != nil {
+		return
+	}
+
+	var bookmark *model.ChannelBookmark
+	if jsonErr := json.NewDecoder(r.Body).Decode(&bookmark); jsonErr != nil || bookmark == nil {
+		c.SetInvalidParam("bookmark")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("createChannelBookmark", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("channel_id", c.Params.ChannelId)
+
+	if !c.App.SessionHasPermissionToChannel(*c.AppContext.Session(), c.Params.ChannelId, model.PermissionManageChannelBookmarks) {
+		c.SetPermissionError(model.PermissionManageChannelBookmarks)
+		return
+	}
+
+	bookmark.Id = ""
+	bookmark.CreateAt = 0
+	bookmark.ChannelId = c.Params.ChannelId
+	bookmark.OwnerId = c.AppContext.Session().UserId
+	bookmark.RemoteId = nil
+	bookmark.FileInfo = nil
+
+	savedBookmark, err := c.App.CreateChannelBookmark(c.AppContext, bookmark)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddMeta("bookmark", savedBookmark)
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(savedBookmark); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func patchChannelBookmark(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId().RequireBookmarkId()
+	if c.Err != nil {
+		return
+	}
+
+	var patch *model.ChannelBookmarkPatch
+	if jsonErr := json.NewDecoder(r.Body).Decode(&patch); jsonErr != nil || patch == nil {
+		c.SetInvalidParam("bookmark")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchChannelBookmark", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("channel_id", c.Params.ChannelId)
+	auditRec.AddMeta("bookmark_id", c.Params.BookmarkId)
+
+	if !c.App.SessionHasPermissionToChannel(*c.AppContext.Session(), c.Params.ChannelId, model.PermissionManageChannelBookmarks) {
+		c.SetPermissionError(model.PermissionManageChannelBookmarks)
+		return
+	}
+
+	bookmark, err := c.App.GetChannelBookmark(c.Params.BookmarkId, false)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if bookmark.ChannelId != c.Params.ChannelId {
+		c.SetInvalidURLParam("bookmark_id")
+		return
+	}
+
+	bookmark.Patch(patch)
+	// the bookmark is changed here, whether or not it came from a remote cluster.
+	bookmark.RemoteId = nil
+
+	patchedBookmark, err := c.App.UpdateChannelBookmark(c.AppContext, bookmark)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddMeta("bookmark", patchedBookmark)
+
+	if err := json.NewEncoder(w).Encode(patchedBookmark); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func updateChannelBookmarkSortOrder(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId().RequireBookmarkId()
+	if c.Err != nil {
+		return
+	}
+
+	var newIndex int64
+	if jsonErr := json.NewDecoder(r.Body).Decode(&newIndex); jsonErr != nil {
+		c.SetInvalidParam("sort_order")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("updateChannelBookmarkSortOrder", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("channel_id", c.Params.ChannelId)
+	auditRec.AddMeta("bookmark_id", c.Params.BookmarkId)
+	auditRec.AddMeta("sort_order", newIndex)
+
+	if !c.App.SessionHasPermissionToChannel(*c.AppContext.Session(), c.Params.ChannelId, model.PermissionManageChannelBookmarks) {
+		c.SetPermissionError(model.PermissionManageChannelBookmarks)
+		return
+	}
+
+	bookmarks, err := c.App.UpdateChannelBookmarkSortOrder(c.Params.BookmarkId, c.Params.ChannelId, newIndex)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+
+	if err := json.NewEncoder(w).Encode(bookmarks); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteChannelBookmark(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireChannelId().RequireBookmarkId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteChannelBookmark", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("channel_id", c.Params.ChannelId)
+	auditRec.AddMeta("bookmark_id", c.Params.BookmarkId)
+
+	if !c.App.SessionHasPermissionToChannel(*c.AppContext.Session(), c.Params.ChannelId, model.PermissionManageChannelBookmarks) {
+		c.SetPermissionError(model.PermissionManageChannelBookmarks)
+		return
+	}
+
+	bookmark, err := c.App.GetChannelBookmark(c.Params.BookmarkId, false)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if bookmark.ChannelId != c.Params.ChannelId {
+		c.SetInvalidURLParam("bookmark_id")
+		return
+	}
+
+	deletedBookmark, err := c.App.DeleteChannelBookmark(bookmark.Id)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddMeta("bookmark", deletedBookmark)
+
+	if err := json.NewEncoder(w).Encode(deletedBookmark); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestChannelBookmarks(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+	channelID := th.BasicChannel.Id
+
+	link, resp, err := client.CreateChannelBookmark(&model.ChannelBookmark{
+		ChannelId:   channelID,
+		DisplayName: "Docs",
+		LinkUrl:     "https://example.com/docs",
+		Type:        model.ChannelBookmarkLink,
+	})
+	require.NoError(t, err)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.BasicUser.Id, link.OwnerId)
+	assert.Equal(t, int64(0), link.SortOrder)
+
+	fileResp, _, err := client.UploadFile([]byte("handbook"), channelID, "handbook.txt")
+	require.NoError(t, err)
+	fileID := fileResp.FileInfos[0].Id
+
+	file, _, err := client.CreateChannelBookmark(&model.ChannelBookmark{
+		ChannelId:   channelID,
+		DisplayName: "Handbook",
+		FileId:      fileID,
+		Type:        model.ChannelBookmarkFile,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), file.SortOrder)
+	require.NotNil(t, file.FileInfo)
+	assert.Equal(t, fileID, file.FileInfo.Id)
+
+	t.Run("get", func(t *testing.T) {
+		bookmarks, _, err := client.GetChannelBookmarks(channelID, 0)
+		require.NoError(t, err)
+		require.Len(t, bookmarks, 2)
+		assert.Equal(t, link.Id, bookmarks[0].Id)
+		assert.Equal(t, file.Id, bookmarks[1].Id)
+	})
+
+	t.Run("channel members can read the file", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, _, err := th.Client.GetFileInfo(fileID)
+		require.NoError(t, err)
+	})
+
+	t.Run("cannot bookmark the file of another user", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp, err := th.Client.CreateChannelBookmark(&model.ChannelBookmark{
+			ChannelId:   channelID,
+			DisplayName: "Handbook",
+			FileId:      fileID,
+			Type:        model.ChannelBookmarkFile,
+		})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		patched, _, err := client.PatchChannelBookmark(channelID, link.Id, &model.ChannelBookmarkPatch{
+			DisplayName: model.NewString("Documentation"),
+			Emoji:       model.NewString("books"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Documentation", patched.DisplayName)
+		assert.Equal(t, "books", patched.Emoji)
+		assert.Equal(t, link.LinkUrl, patched.LinkUrl)
+
+		_, resp, err := client.PatchChannelBookmark(th.BasicChannel2.Id, link.Id, &model.ChannelBookmarkPatch{DisplayName: model.NewString("Other")})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("sort order", func(t *testing.T) {
+		bookmarks, _, err := client.UpdateChannelBookmarkSortOrder(channelID, file.Id, 0)
+		require.NoError(t, err)
+		require.Len(t, bookmarks, 2)
+		assert.Equal(t, file.Id, bookmarks[0].Id)
+		assert.Equal(t, link.Id, bookmarks[1].Id)
+
+		_, resp, err := client.UpdateChannelBookmarkSortOrder(channelID, file.Id, 5)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("without permission", func(t *testing.T) {
+		th.RemovePermissionFromRole(model.PermissionManageChannelBookmarks.Id, model.ChannelUserRoleId)
+		defer th.AddPermissionToRole(model.PermissionManageChannelBookmarks.Id, model.ChannelUserRoleId)
+
+		_, resp, err := client.DeleteChannelBookmark(channelID, link.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		deleted, _, err := client.DeleteChannelBookmark(channelID, link.Id)
+		require.NoError(t, err)
+		assert.NotZero(t, deleted.DeleteAt)
+
+		bookmarks, _, err := client.GetChannelBookmarks(channelID, 0)
+		require.NoError(t, err)
+		require.Len(t, bookmarks, 1)
+		assert.Equal(t, file.Id, bookmarks[0].Id)
+
+		bookmarks, _, err = client.GetChannelBookmarks(channelID, deleted.DeleteAt)
+		require.NoError(t, err)
+		require.Len(t, bookmarks, 1)
+		assert.Equal(t, link.Id, bookmarks[0].Id)
+		assert.NotZero(t, bookmarks[0].DeleteAt)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		channel := th.CreatePrivateChannel()
+		th.RemoveUserFromChannel(th.BasicUser, channel)
+
+		_, resp, err := client.GetChannelBookmarks(channel.Id, 0)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+}
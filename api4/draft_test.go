@@ -0,0 +1,82 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestDrafts(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	draft, resp, err := client.UpsertDraft(&model.Draft{
+		UserId:    th.BasicUser2.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "draft",
+	})
+	require.NoError(t, err)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.BasicUser.Id, draft.UserId, "the draft is the current user's")
+
+	reply, _, err := client.UpsertDraft(&model.Draft{
+		ChannelId: th.BasicChannel.Id,
+		RootId:    th.BasicPost.Id,
+		Message:   "reply",
+	})
+	require.NoError(t, err)
+
+	drafts, _, err := client.GetDrafts(th.BasicUser.Id, th.BasicTeam.Id)
+	require.NoError(t, err)
+	require.Len(t, drafts, 2)
+
+	t.Run("other users", func(t *testing.T) {
+		_, resp, err := client.GetDrafts(th.BasicUser2.Id, th.BasicTeam.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		resp, err = client.DeleteDraft(th.BasicUser2.Id, th.BasicChannel.Id, "")
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("channel without permission", func(t *testing.T) {
+		channel := th.CreatePrivateChannel()
+		th.RemoveUserFromChannel(th.BasicUser, channel)
+
+		_, resp, err := client.UpsertDraft(&model.Draft{ChannelId: channel.Id, Message: "draft"})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := client.DeleteDraft(th.BasicUser.Id, th.BasicChannel.Id, reply.RootId)
+		require.NoError(t, err)
+		_, err = client.DeleteDraft(th.BasicUser.Id, th.BasicChannel.Id, "")
+		require.NoError(t, err)
+
+		resp, err := client.DeleteDraft(th.BasicUser.Id, th.BasicChannel.Id, "")
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		drafts, _, err := client.GetDrafts(th.BasicUser.Id, th.BasicTeam.Id)
+		require.NoError(t, err)
+		assert.Empty(t, drafts)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.AllowSyncedDrafts = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.AllowSyncedDrafts = true })
+
+		_, resp, err := client.GetDrafts(th.BasicUser.Id, th.BasicTeam.Id)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+}
@@ -485,7 +485,7 @@ func getFile(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 	auditRec.AddMeta("file", info)
 
-	if info.CreatorId != c.AppContext.Session().UserId && !c.App.SessionHasPermissionToChannelByPost(*c.AppContext.Session(), info.PostId, model.PermissionReadChannel) {
+	if !c.App.SessionHasPermissionToReadFile(*c.AppContext.Session(), info) {
 		c.SetPermissionError(model.PermissionReadChannel)
 		return
 	}
@@ -516,7 +516,7 @@ func getFileThumbnail(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if info.CreatorId != c.AppContext.Session().UserId && !c.App.SessionHasPermissionToChannelByPost(*c.AppContext.Session(), info.PostId, model.PermissionReadChannel) {
+	if !c.App.SessionHasPermissionToReadFile(*c.AppContext.Session(), info) {
 		c.SetPermissionError(model.PermissionReadChannel)
 		return
 	}
@@ -591,7 +591,7 @@ func getFilePreview(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if info.CreatorId != c.AppContext.Session().UserId && !c.App.SessionHasPermissionToChannelByPost(*c.AppContext.Session(), info.PostId, model.PermissionReadChannel) {
+	if !c.App.SessionHasPermissionToReadFile(*c.AppContext.Session(), info) {
 		c.SetPermissionError(model.PermissionReadChannel)
 		return
 	}
@@ -624,7 +624,7 @@ func getFileInfo(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if info.CreatorId != c.AppContext.Session().UserId && !c.App.SessionHasPermissionToChannelByPost(*c.AppContext.Session(), info.PostId, model.PermissionReadChannel) {
+	if !c.App.SessionHasPermissionToReadFile(*c.AppContext.Session(), info) {
 		c.SetPermissionError(model.PermissionReadChannel)
 		return
 	}
@@ -5,6 +5,7 @@ package api4
 
 import (
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -616,3 +617,96 @@ func TestNilAuthorizeOAuthApp(t *testing.T) {
 	require.Error(t, err)
 	CheckErrorID(t, err, "api.context.invalid_body_param.app_error")
 }
+
+func TestOAuthScopes(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableOAuthServiceProvider = true })
+
+	oapp, appErr := th.App.CreateOAuthApp(&model.OAuthApp{
+		Name:         GenerateTestAppName(),
+		Homepage:     "https://nowhere.com",
+		Description:  "test",
+		CallbackUrls: []string{"https://nowhere.com"},
+		CreatorId:    th.SystemAdminUser.Id,
+	})
+	require.Nil(t, appErr)
+
+	getToken := func(t *testing.T, scope string) *model.AccessResponse {
+		t.Helper()
+		redirect, _, err := th.Client.AuthorizeOAuthApp(&model.AuthorizeRequest{
+			ResponseType: model.AuthCodeResponseType,
+			ClientId:     oapp.Id,
+			RedirectURI:  oapp.CallbackUrls[0],
+			Scope:        scope,
+			State:        "123",
+		})
+		require.NoError(t, err)
+		rurl, err := url.Parse(redirect)
+		require.NoError(t, err)
+
+		rsp, _, err := th.CreateClient().GetOAuthAccessToken(url.Values{
+			"grant_type":    []string{model.AccessTokenGrantType},
+			"client_id":     []string{oapp.Id},
+			"client_secret": []string{oapp.ClientSecret},
+			"code":          []string{rurl.Query().Get("code")},
+			"redirect_uri":  []string{oapp.CallbackUrls[0]},
+		})
+		require.NoError(t, err)
+		return rsp
+	}
+
+	rsp := getToken(t, "read:users junk")
+	require.Equal(t, model.OAuthScopeReadUsers, rsp.Scope)
+
+	client := th.CreateClient()
+	client.SetOAuthToken(rsp.AccessToken)
+
+	me, _, err := client.GetMe("")
+	require.NoError(t, err)
+	assert.Equal(t, th.BasicUser.Id, me.Id)
+
+	nickname := "scoped"
+	_, resp, err := client.PatchUser(th.BasicUser.Id, &model.UserPatch{Nickname: &nickname})
+	require.Error(t, err, "should have failed - write:users not granted")
+	CheckForbiddenStatus(t, resp)
+	CheckErrorID(t, err, "api.context.oauth_scope.app_error")
+
+	_, resp, err = client.GetTeamsForUser(th.BasicUser.Id, "")
+	require.Error(t, err, "should have failed - read:teams not granted")
+	CheckForbiddenStatus(t, resp)
+
+	_, resp, err = client.GetPostsForChannel(th.BasicChannel.Id, 0, 10, "", false)
+	require.Error(t, err, "should have failed - read:posts not granted")
+	CheckForbiddenStatus(t, resp)
+
+	t.Run("authorizing other scopes replaces the token", func(t *testing.T) {
+		rsp := getToken(t, "read write:posts")
+		require.Equal(t, "read write:posts", rsp.Scope)
+
+		client := th.CreateClient()
+		client.SetOAuthToken(rsp.AccessToken)
+
+		_, _, err := client.GetTeamsForUser(th.BasicUser.Id, "")
+		require.NoError(t, err)
+
+		_, _, err = client.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, Message: "scoped"})
+		require.NoError(t, err)
+
+		_, resp, err := client.PatchUser(th.BasicUser.Id, &model.UserPatch{Nickname: &nickname})
+		require.Error(t, err, "should have failed - write:users not granted")
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("sessions without a scope have full access", func(t *testing.T) {
+		session, appErr := th.App.CreateSession(&model.Session{UserId: th.BasicUser.Id, IsOAuth: true})
+		require.Nil(t, appErr)
+
+		client := th.CreateClient()
+		client.SetOAuthToken(session.Token)
+
+		_, _, err := client.PatchUser(th.BasicUser.Id, &model.UserPatch{Nickname: &nickname})
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/audit"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+func (api *API) InitPoll() {
+	api.BaseRoutes.Post.Handle("/poll", api.APISessionRequired(getPollResults)).Methods("GET")
+	api.BaseRoutes.Post.Handle("/poll/votes", api.APISessionRequired(votePoll)).Methods("PUT")
+	api.BaseRoutes.Post.Handle("/poll/close", api.APISessionRequired(closePoll)).Methods("POST")
+}
+
+func getPollResults(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToChannelByPost(*c.AppContext.Session(), c.Params.PostId, model.PermissionReadChannel) {
+		c.SetPermissionError(model.PermissionReadChannel)
+		return
+	}
+
+	results, err := c.App.GetPollResults(c.Params.PostId, c.AppContext.Session().UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func votePoll(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	var optionIndexes []int
+	if jsonErr := json.NewDecoder(r.Body).Decode(&optionIndexes); jsonErr != nil {
+		c.SetInvalidParam("option_indexes")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("votePoll", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	auditRec.AddMeta("post_id", c.Params.PostId)
+
+	if !c.App.SessionHasPermissionToChannelByPost(*c.AppContext.Session(), c.Params.PostId, model.PermissionReadChannel) {
+		c.SetPermissionError(model.PermissionReadChannel)
+		return
+	}
+
+	results, err := c.App.VotePoll(c.AppContext, c.Params.PostId, c.AppContext.Session().UserId, optionIndexes)
+	if err != nil {
+		c.Err = err
+		return
+	}
+	auditRec.Success()
+
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func closePoll(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("closePoll", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	auditRec.AddMeta("post_id", c.Params.PostId)
+
+	post, err := c.App.GetPostIfAuthorized(c.Params.PostId, c.AppContext.Session())
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if post.UserId != c.AppContext.Session().UserId && !c.App.SessionHasPermissionToChannel(*c.AppContext.Session(), post.ChannelId, model.PermissionEditOthersPosts) {
+		c.SetPermissionError(model.PermissionEditOthersPosts)
+		return
+	}
+
+	closedPost, err := c.App.ClosePoll(c.AppContext, post.Id)
+	if err != nil {
+		c.Err = err
+		return
+	}
+	auditRec.Success()
+
+	if err := closedPost.EncodeJSON(w); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestPolls(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	post := &model.Post{ChannelId: th.BasicChannel.Id, Message: "Lunch?"}
+	post.SetPoll(&model.Poll{Options: []string{"Pizza", "Sushi"}})
+	post, _, err := client.CreatePost(post)
+	require.NoError(t, err)
+
+	results, _, err := client.VotePoll(post.Id, []int{0})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 0}, results.Counts)
+	assert.Equal(t, []int{0}, results.UserVotes)
+
+	t.Run("invalid vote", func(t *testing.T) {
+		_, resp, err := client.VotePoll(post.Id, []int{0, 1})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("other users", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		results, _, err := th.Client.GetPollResults(post.Id)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 0}, results.Counts)
+		assert.Empty(t, results.UserVotes)
+
+		_, resp, err := th.Client.ClosePoll(post.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("without permission", func(t *testing.T) {
+		channel := th.CreatePrivateChannel()
+		privatePost := &model.Post{ChannelId: channel.Id, Message: "Lunch?"}
+		privatePost.SetPoll(&model.Poll{Options: []string{"Pizza", "Sushi"}})
+		privatePost, _, err := client.CreatePost(privatePost)
+		require.NoError(t, err)
+		th.RemoveUserFromChannel(th.BasicUser, channel)
+
+		_, resp, err := client.VotePoll(privatePost.Id, []int{0})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		closed, _, err := client.ClosePoll(post.Id)
+		require.NoError(t, err)
+		assert.NotZero(t, closed.GetPoll().ClosedAt)
+		require.NotNil(t, closed.Metadata.Poll)
+		assert.Equal(t, []int{1, 0}, closed.Metadata.Poll.Counts)
+
+		_, resp, err := client.VotePoll(post.Id, []int{1})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+}
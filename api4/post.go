@@ -23,6 +23,7 @@ func (api *API) InitPost() {
 	api.BaseRoutes.Posts.Handle("/ephemeral", api.APISessionRequired(createEphemeralPost)).Methods("POST")
 	api.BaseRoutes.Post.Handle("/thread", api.APISessionRequired(getPostThread)).Methods("GET")
 	api.BaseRoutes.Post.Handle("/files/info", api.APISessionRequired(getFileInfosForPost)).Methods("GET")
+	api.BaseRoutes.Post.Handle("/edit_history", api.APISessionRequired(getPostEditHistory)).Methods("GET")
 	api.BaseRoutes.PostsForChannel.Handle("", api.APISessionRequired(getPostsForChannel)).Methods("GET")
 	api.BaseRoutes.PostsForUser.Handle("/flagged", api.APISessionRequired(getFlaggedPostsForUser)).Methods("GET")
 
@@ -409,6 +410,39 @@ func getPost(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// getPostEditHistory returns the revisions of a post to its author, and to the users who
+// can edit the posts of others in the channel.
+func getPostEditHistory(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequirePostId()
+	if c.Err != nil {
+		return
+	}
+
+	post, err := c.App.GetPostIfAuthorized(c.Params.PostId, c.AppContext.Session())
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if post.UserId != c.AppContext.Session().UserId && !c.App.SessionHasPermissionToChannel(*c.AppContext.Session(), post.ChannelId, model.PermissionEditOthersPosts) {
+		c.SetPermissionError(model.PermissionEditOthersPosts)
+		return
+	}
+
+	revisions, err := c.App.GetPostEditHistory(post.Id)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	js, jsonErr := json.Marshal(revisions)
+	if jsonErr != nil {
+		c.Err = model.NewAppError("getPostEditHistory", "api.marshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(js)
+}
+
 func getPostsByIds(c *Context, w http.ResponseWriter, r *http.Request) {
 	postIDs := model.ArrayFromJSON(r.Body)
 
@@ -2585,6 +2585,77 @@ func TestGetFileInfosForPost(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestGetPostEditHistory(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	th.App.Srv().SetLicense(model.NewTestLicense())
+
+	WebSocketClient, err := th.CreateWebSocketClient()
+	require.NoError(t, err)
+	WebSocketClient.Listen()
+	defer WebSocketClient.Close()
+
+	post, _, err := client.CreatePost(&model.Post{ChannelId: th.BasicChannel.Id, Message: "original"})
+	require.NoError(t, err)
+	original := post.Message
+	_, _, err = client.PatchPost(post.Id, &model.PostPatch{Message: model.NewString("edited")})
+	require.NoError(t, err)
+
+	var received bool
+	for !received {
+		select {
+		case event := <-WebSocketClient.EventChannel:
+			if event.EventType() == model.WebsocketEventPostRevisionCreated {
+				assert.Equal(t, post.Id, event.GetData()["post_id"])
+				var revision model.PostRevision
+				require.NoError(t, json.Unmarshal([]byte(event.GetData()["revision"].(string)), &revision))
+				assert.Equal(t, original, revision.Message)
+				received = true
+			}
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "did not receive the post revision")
+		}
+	}
+
+	revisions, _, err := client.GetPostEditHistory(post.Id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, original, revisions[0].Message)
+	assert.Equal(t, th.BasicUser.Id, revisions[0].EditorId)
+
+	t.Run("other users cannot see the history", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp, err := client.GetPostEditHistory(post.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("moderators can see the history", func(t *testing.T) {
+		revisions, _, err := th.SystemAdminClient.GetPostEditHistory(post.Id)
+		require.NoError(t, err)
+		assert.Len(t, revisions, 1)
+	})
+
+	t.Run("no edits", func(t *testing.T) {
+		revisions, _, err := client.GetPostEditHistory(th.CreatePost().Id)
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+	})
+
+	_, resp, err := client.GetPostEditHistory("junk")
+	require.Error(t, err)
+	CheckBadRequestStatus(t, resp)
+
+	client.Logout()
+	_, resp, err = client.GetPostEditHistory(post.Id)
+	require.Error(t, err)
+	CheckUnauthorizedStatus(t, resp)
+}
+
 func TestSetChannelUnread(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
@@ -0,0 +1,93 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/audit"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+func (api *API) InitReminder() {
+	api.BaseRoutes.Reminders.Handle("", api.APISessionRequired(createReminder)).Methods("POST")
+	api.BaseRoutes.Reminder.Handle("", api.APISessionRequired(deleteReminder)).Methods("DELETE")
+	api.BaseRoutes.User.Handle("/reminders", api.APISessionRequired(getRemindersForUser)).Methods("GET")
+}
+
+func createReminder(c *Context, w http.ResponseWriter, r *http.Request) {
+	var reminder model.Reminder
+	if jsonErr := json.NewDecoder(r.Body).Decode(&reminder); jsonErr != nil {
+		c.SetInvalidParam("reminder")
+		return
+	}
+
+	reminder.UserId = c.AppContext.Session().UserId
+
+	auditRec := c.MakeAuditRecord("createReminder", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	auditRec.AddMeta("post_id", reminder.PostId)
+
+	if reminder.PostId != "" && !c.App.SessionHasPermissionToChannelByPost(*c.AppContext.Session(), reminder.PostId, model.PermissionReadChannel) {
+		c.SetPermissionError(model.PermissionReadChannel)
+		return
+	}
+
+	savedReminder, err := c.App.SaveReminder(&reminder)
+	if err != nil {
+		c.Err = err
+		return
+	}
+	auditRec.Success()
+	auditRec.AddMeta("reminder_id", savedReminder.Id)
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(savedReminder); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getRemindersForUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	reminders, err := c.App.GetRemindersForUser(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(reminders); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteReminder(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireReminderId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteReminder", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	auditRec.AddMeta("reminder_id", c.Params.ReminderId)
+
+	if _, err := c.App.DeleteReminder(c.AppContext.Session().UserId, c.Params.ReminderId); err != nil {
+		c.Err = err
+		return
+	}
+	auditRec.Success()
+
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestReminders(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	client := th.Client
+
+	reminder, resp, err := client.CreateReminder(&model.Reminder{
+		PostId:   th.BasicPost.Id,
+		TargetAt: model.GetMillis() + 60*1000,
+	})
+	require.NoError(t, err)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.BasicUser.Id, reminder.UserId)
+
+	reminders, _, err := client.GetReminders(th.BasicUser.Id)
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, reminder.Id, reminders[0].Id)
+
+	t.Run("in the past", func(t *testing.T) {
+		_, resp, err := client.CreateReminder(&model.Reminder{Message: "message", TargetAt: model.GetMillis() - 1000})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("post without permission", func(t *testing.T) {
+		channel := th.CreatePrivateChannel()
+		post := th.CreatePostWithClient(th.Client, channel)
+		th.RemoveUserFromChannel(th.BasicUser, channel)
+
+		_, resp, err := client.CreateReminder(&model.Reminder{PostId: post.Id, TargetAt: model.GetMillis() + 60*1000})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("other users", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp, err := th.Client.GetReminders(th.BasicUser.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		resp, err = th.Client.DeleteReminder(reminder.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	_, err = client.DeleteReminder(reminder.Id)
+	require.NoError(t, err)
+	reminders, _, err = client.GetReminders(th.BasicUser.Id)
+	require.NoError(t, err)
+	assert.Empty(t, reminders)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/audit"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+func (api *API) InitScheduledPost() {
+	api.BaseRoutes.ScheduledPosts.Handle("", api.APISessionRequired(createScheduledPost)).Methods("POST")
+	api.BaseRoutes.ScheduledPost.Handle("", api.APISessionRequired(updateScheduledPost)).Methods("PUT")
+	api.BaseRoutes.ScheduledPost.Handle("", api.APISessionRequired(deleteScheduledPost)).Methods("DELETE")
+	api.BaseRoutes.TeamForUser.Handle("/scheduled_posts", api.APISessionRequired(getScheduledPostsForUser)).Methods("GET")
+}
+
+func createScheduledPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	var scheduledPost model.ScheduledPost
+	if jsonErr := json.NewDecoder(r.Body).Decode(&scheduledPost); jsonErr != nil {
+		c.SetInvalidParam("scheduled_post")
+		return
+	}
+
+	scheduledPost.UserId = c.AppContext.Session().UserId
+
+	auditRec := c.MakeAuditRecord("createScheduledPost", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	auditRec.AddMeta("channel_id", scheduledPost.ChannelId)
+
+	if !c.App.SessionHasPermissionToChannel(*c.AppContext.Session(), scheduledPost.ChannelId, model.PermissionCreatePost) {
+		c.SetPermissionError(model.PermissionCreatePost)
+		return
+	}
+
+	savedScheduledPost, err := c.App.SaveScheduledPost(&scheduledPost)
+	if err != nil {
+		c.Err = err
+		return
+	}
+	auditRec.Success()
+	auditRec.AddMeta("scheduled_post_id", savedScheduledPost.Id)
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(savedScheduledPost); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getScheduledPostsForUser(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireTeamId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	if !c.App.SessionHasPermissionToTeam(*c.AppContext.Session(), c.Params.TeamId, model.PermissionViewTeam) {
+		c.SetPermissionError(model.PermissionViewTeam)
+		return
+	}
+
+	scheduledPosts, err := c.App.GetScheduledPostsForUser(c.Params.UserId, c.Params.TeamId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(scheduledPosts); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func updateScheduledPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireScheduledPostId()
+	if c.Err != nil {
+		return
+	}
+
+	var scheduledPost model.ScheduledPost
+	if jsonErr := json.NewDecoder(r.Body).Decode(&scheduledPost); jsonErr != nil {
+		c.SetInvalidParam("scheduled_post")
+		return
+	}
+	scheduledPost.Id = c.Params.ScheduledPostId
+
+	auditRec := c.MakeAuditRecord("updateScheduledPost", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	auditRec.AddMeta("scheduled_post_id", scheduledPost.Id)
+
+	updatedScheduledPost, err := c.App.UpdateScheduledPost(c.AppContext.Session().UserId, &scheduledPost)
+	if err != nil {
+		c.Err = err
+		return
+	}
+	auditRec.Success()
+
+	if err := json.NewEncoder(w).Encode(updatedScheduledPost); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteScheduledPost(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireScheduledPostId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteScheduledPost", audit.Fail)
+	defer c.LogAuditRecWithLevel(auditRec, app.LevelContent)
+	auditRec.AddMeta("scheduled_post_id", c.Params.ScheduledPostId)
+
+	if _, err := c.App.DeleteScheduledPost(c.AppContext.Session().UserId, c.Params.ScheduledPostId); err != nil {
+		c.Err = err
+		return
+	}
+	auditRec.Success()
+
+	ReturnStatusOK(w)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestScheduledPosts(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableScheduledPosts = true })
+	client := th.Client
+
+	scheduledPost, resp, err := client.CreateScheduledPost(&model.ScheduledPost{
+		Draft: model.Draft{
+			ChannelId: th.BasicChannel.Id,
+			Message:   "scheduled",
+		},
+		ScheduledAt: model.GetMillis() + 60*1000,
+	})
+	require.NoError(t, err)
+	CheckCreatedStatus(t, resp)
+	assert.Equal(t, th.BasicUser.Id, scheduledPost.UserId)
+
+	scheduledPosts, _, err := client.GetScheduledPosts(th.BasicUser.Id, th.BasicTeam.Id)
+	require.NoError(t, err)
+	require.Len(t, scheduledPosts, 1)
+	assert.Equal(t, scheduledPost.Id, scheduledPosts[0].Id)
+
+	t.Run("in the past", func(t *testing.T) {
+		_, resp, err := client.CreateScheduledPost(&model.ScheduledPost{
+			Draft:       model.Draft{ChannelId: th.BasicChannel.Id, Message: "scheduled"},
+			ScheduledAt: model.GetMillis() - 1000,
+		})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("channel without permission", func(t *testing.T) {
+		channel := th.CreatePrivateChannel()
+		th.RemoveUserFromChannel(th.BasicUser, channel)
+
+		_, resp, err := client.CreateScheduledPost(&model.ScheduledPost{
+			Draft:       model.Draft{ChannelId: channel.Id, Message: "scheduled"},
+			ScheduledAt: model.GetMillis() + 60*1000,
+		})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		scheduledPost.Message = "updated"
+		updated, _, err := client.UpdateScheduledPost(scheduledPost)
+		require.NoError(t, err)
+		assert.Equal(t, "updated", updated.Message)
+	})
+
+	t.Run("other users", func(t *testing.T) {
+		th.LoginBasic2()
+		defer th.LoginBasic()
+
+		_, resp, err := th.Client.GetScheduledPosts(th.BasicUser.Id, th.BasicTeam.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.Client.UpdateScheduledPost(scheduledPost)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+
+		resp, err = th.Client.DeleteScheduledPost(scheduledPost.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := client.DeleteScheduledPost(scheduledPost.Id)
+		require.NoError(t, err)
+
+		scheduledPosts, _, err := client.GetScheduledPosts(th.BasicUser.Id, th.BasicTeam.Id)
+		require.NoError(t, err)
+		assert.Empty(t, scheduledPosts)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableScheduledPosts = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableScheduledPosts = true })
+
+		_, resp, err := client.GetScheduledPosts(th.BasicUser.Id, th.BasicTeam.Id)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+}
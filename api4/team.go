@@ -4,8 +4,7 @@
 package api4
 
 import (
-	"bytes"
-	"encoding/base64"
+	"archive/zip"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -1235,26 +1234,33 @@ func importTeam(c *Context, w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("filesize", fileSize)
 	auditRec.AddMeta("from", importFrom)
 
-	var log *bytes.Buffer
-	data := map[string]string{}
+	if _, err := zip.NewReader(fileData, fileInfo.Size); err != nil {
+		c.Err = model.NewAppError("importTeam", "api.team.import_team.zip.app_error", nil, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The export is imported by an import job, since importing it may take longer than
+	// the request is allowed to.
+	var job *model.Job
 	switch importFrom {
 	case "slack":
-		var err *model.AppError
-		if err, log = c.App.SlackImport(c.AppContext, fileData, fileSize, c.Params.TeamId); err != nil {
-			c.Err = err
-			c.Err.StatusCode = http.StatusBadRequest
+		var appErr *model.AppError
+		if job, appErr = c.App.CreateTeamImportJob(fileData, fileInfo.Filename, importFrom, c.Params.TeamId); appErr != nil {
+			c.Err = appErr
+			return
 		}
-		data["results"] = base64.StdEncoding.EncodeToString(log.Bytes())
 	default:
 		c.Err = model.NewAppError("importTeam", "api.team.import_team.unknown_import_from.app_error", nil, "", http.StatusBadRequest)
-	}
-
-	if c.Err != nil {
-		w.WriteHeader(c.Err.StatusCode)
 		return
 	}
+
 	auditRec.Success()
-	w.Write([]byte(model.MapToJSON(data)))
+	auditRec.AddMeta("job", job)
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
 }
 
 func inviteUsersToTeam(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -4,7 +4,6 @@
 package api4
 
 import (
-	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
@@ -12,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -2908,15 +2908,23 @@ func TestImportTeam(t *testing.T) {
 
 		require.False(t, err != nil && len(data) == 0, "Error while reading the test file.")
 
+		require.NoError(t, th.App.Srv().Jobs.StartWorkers())
+		defer th.App.Srv().Jobs.StopWorkers()
+
 		// Import the channels/users/posts
-		fileResp, _, err := th.SystemAdminClient.ImportTeam(data, binary.Size(data), "slack", "Fake_Team_Import.zip", th.BasicTeam.Id)
+		job, resp, err := th.SystemAdminClient.ImportTeam(data, binary.Size(data), "slack", "Fake_Team_Import.zip", th.BasicTeam.Id)
 		require.NoError(t, err)
+		CheckCreatedStatus(t, resp)
+		require.Equal(t, model.JobTypeImportProcess, job.Type)
+		require.Equal(t, "slack", job.Data["import_from"])
+		require.Equal(t, th.BasicTeam.Id, job.Data["team_id"])
 
-		fileData, err := base64.StdEncoding.DecodeString(fileResp["results"])
-		require.NoError(t, err, "failed to decode base64 results data")
-
-		fileReturned := string(fileData)
-		require.Truef(t, strings.Contains(fileReturned, "darth.vader@stardeath.com"), "failed to report the user was imported, fileReturned: %s", fileReturned)
+		require.Eventually(t, func() bool {
+			job, _, err = th.SystemAdminClient.GetJob(job.Id)
+			require.NoError(t, err)
+			require.NotEqual(t, model.JobStatusError, job.Status, job.Data["error"])
+			return job.Status == model.JobStatusSuccess
+		}, 30*time.Second, 100*time.Millisecond)
 
 		// Checking the imported users
 		importedUser, _, err := th.SystemAdminClient.GetUserByUsername("bot_test", "")
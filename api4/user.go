@@ -60,6 +60,12 @@ func (api *API) InitUser() {
 
 	api.BaseRoutes.User.Handle("/mfa", api.APISessionRequiredMfa(updateUserMfa)).Methods("PUT")
 	api.BaseRoutes.User.Handle("/mfa/generate", api.APISessionRequiredMfa(generateMfaSecret)).Methods("POST")
+	api.BaseRoutes.User.Handle("/mfa/recovery_codes", api.APISessionRequired(regenerateMfaRecoveryCodes)).Methods("POST")
+	api.BaseRoutes.User.Handle("/mfa/reset/approve", api.APISessionRequired(approveMfaReset)).Methods("POST")
+	api.BaseRoutes.User.Handle("/mfa/reset", api.APISessionRequired(denyMfaReset)).Methods("DELETE")
+	api.BaseRoutes.Users.Handle("/mfa/reset", api.APIHandler(resetMfa)).Methods("POST")
+	api.BaseRoutes.Users.Handle("/mfa/reset/send", api.APIHandler(sendMfaReset)).Methods("POST")
+	api.BaseRoutes.Users.Handle("/mfa/reset/requests", api.APISessionRequired(getMfaResetRequests)).Methods("GET")
 
 	api.BaseRoutes.Users.Handle("/login", api.APIHandler(login)).Methods("POST")
 	api.BaseRoutes.Users.Handle("/login/switch", api.APIHandler(switchAccountType)).Methods("POST")
@@ -1605,7 +1611,23 @@ func updateUserMfa(c *Context, w http.ResponseWriter, r *http.Request) {
 	auditRec.AddMeta("activate", activate)
 	c.LogAudit("success - mfa updated")
 
-	ReturnStatusOK(w)
+	if !activate {
+		ReturnStatusOK(w)
+		return
+	}
+
+	// The recovery codes are shown once, when the multi-factor authentication is activated
+	codes, err := c.App.GenerateMfaRecoveryCodes(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+	c.LogAudit("success - mfa recovery codes generated")
+
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := json.NewEncoder(w).Encode(&model.MfaRecoveryCodes{Codes: codes}); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
 }
 
 func generateMfaSecret(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -1639,6 +1661,212 @@ func generateMfaSecret(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func regenerateMfaRecoveryCodes(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("regenerateMfaRecoveryCodes", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
+	if c.AppContext.Session().IsOAuth {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		c.Err.DetailedError += ", attempted access by oauth app"
+		return
+	}
+
+	// The codes are only ever shown to their user
+	if c.AppContext.Session().UserId != c.Params.UserId {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	props := model.MapFromJSON(r.Body)
+	code := props["code"]
+	if code == "" {
+		c.SetInvalidParam("code")
+		return
+	}
+
+	codes, err := c.App.RegenerateMfaRecoveryCodes(c.Params.UserId, code)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	c.LogAudit("success - mfa recovery codes generated")
+
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := json.NewEncoder(w).Encode(&model.MfaRecoveryCodes{Codes: codes}); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func sendMfaReset(c *Context, w http.ResponseWriter, r *http.Request) {
+	props := model.MapFromJSON(r.Body)
+
+	email := strings.ToLower(props["email"])
+	if email == "" {
+		c.SetInvalidParam("email")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("sendMfaReset", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("email", email)
+
+	sent, err := c.App.SendMfaReset(email, c.App.GetSiteURL())
+	if err != nil {
+		if *c.App.Config().ServiceSettings.ExperimentalEnableHardenedMode {
+			ReturnStatusOK(w)
+		} else {
+			c.Err = err
+		}
+		return
+	}
+
+	if sent {
+		auditRec.Success()
+		c.LogAudit("sent=" + email)
+	}
+	ReturnStatusOK(w)
+}
+
+func resetMfa(c *Context, w http.ResponseWriter, r *http.Request) {
+	props := model.MapFromJSON(r.Body)
+
+	token := props["token"]
+	if len(token) != model.TokenSize {
+		c.SetInvalidParam("token")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("resetMfa", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	c.LogAudit("attempt")
+
+	status, err := c.App.ResetMfaFromToken(token)
+	if err != nil {
+		c.LogAudit("fail")
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddMeta("status", status)
+	c.LogAudit("success - status=" + status)
+
+	w.Write([]byte(model.MapToJSON(map[string]string{"status": status})))
+}
+
+func getMfaResetRequests(c *Context, w http.ResponseWriter, r *http.Request) {
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionEditOtherUsers) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	requests, err := c.App.GetMfaResetRequests()
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(requests); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func approveMfaReset(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("approveMfaReset", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
+	if c.AppContext.Session().IsOAuth {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		c.Err.DetailedError += ", attempted access by oauth app"
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionEditOtherUsers) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	user, err := c.App.GetUser(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	// Cannot reset the MFA of a system admin unless user making request is a systemadmin also
+	if user.IsSystemAdmin() && !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	if err := c.App.ApproveMfaReset(c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	c.LogAudit("success - mfa reset approved for user_id=" + c.Params.UserId)
+
+	ReturnStatusOK(w)
+}
+
+func denyMfaReset(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("denyMfaReset", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
+	if c.AppContext.Session().IsOAuth {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		c.Err.DetailedError += ", attempted access by oauth app"
+		return
+	}
+
+	if !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionEditOtherUsers) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	user, err := c.App.GetUser(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	// Cannot reset the MFA of a system admin unless user making request is a systemadmin also
+	if user.IsSystemAdmin() && !c.App.SessionHasPermissionTo(*c.AppContext.Session(), model.PermissionManageSystem) {
+		c.SetPermissionError(model.PermissionManageSystem)
+		return
+	}
+
+	if err := c.App.DenyMfaReset(c.Params.UserId); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	c.LogAudit("success - mfa reset denied for user_id=" + c.Params.UserId)
+
+	ReturnStatusOK(w)
+}
+
 func updatePassword(c *Context, w http.ResponseWriter, r *http.Request) {
 	c.RequireUserId()
 	if c.Err != nil {
@@ -1772,6 +2000,7 @@ func login(c *Context, w http.ResponseWriter, r *http.Request) {
 		unmaskedErrors := []string{
 			"mfa.validate_token.authenticate.app_error",
 			"api.user.check_user_mfa.bad_code.app_error",
+			"api.user.check_user_mfa.webauthn_required.app_error",
 			"api.user.login.blank_pwd.app_error",
 			"api.user.login.bot_login_forbidden.app_error",
 			"api.user.login.client_side_cert.certificate.app_error",
@@ -2874,6 +2874,188 @@ func TestUserLoginMFAFlow(t *testing.T) {
 	})
 }
 
+func TestMfaRecoveryCodes(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableMultifactorAuthentication = true })
+
+	secret, _, err := th.Client.GenerateMfaSecret(th.BasicUser.Id)
+	require.NoError(t, err)
+	code := dgoogauth.ComputeCode(secret.Secret, time.Now().UTC().Unix()/30)
+
+	codes, resp, err := th.Client.ActivateUserMfa(th.BasicUser.Id, fmt.Sprintf("%06d", code))
+	require.NoError(t, err)
+	CheckOKStatus(t, resp)
+	require.Len(t, codes.Codes, 10)
+
+	t.Run("log in with a recovery code", func(t *testing.T) {
+		user, _, err := th.CreateClient().LoginWithMFA(th.BasicUser.Email, th.BasicUser.Password, strings.ToUpper(codes.Codes[0]))
+		require.NoError(t, err)
+		assert.Equal(t, th.BasicUser.Id, user.Id)
+
+		_, _, err = th.CreateClient().LoginWithMFA(th.BasicUser.Email, th.BasicUser.Password, codes.Codes[0])
+		require.Error(t, err)
+		CheckErrorID(t, err, "api.user.check_user_mfa.bad_code.app_error")
+	})
+
+	t.Run("regenerate the recovery codes", func(t *testing.T) {
+		_, resp, err := th.Client.RegenerateMfaRecoveryCodes(th.BasicUser.Id, "123456")
+		require.Error(t, err)
+		CheckUnauthorizedStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.RegenerateMfaRecoveryCodes(th.BasicUser.Id, codes.Codes[1])
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		newCodes, _, err := th.Client.RegenerateMfaRecoveryCodes(th.BasicUser.Id, codes.Codes[1])
+		require.NoError(t, err)
+		require.Len(t, newCodes.Codes, 10)
+
+		_, _, err = th.CreateClient().LoginWithMFA(th.BasicUser.Email, th.BasicUser.Password, codes.Codes[2])
+		require.Error(t, err, "the previous codes should be replaced")
+
+		_, _, err = th.CreateClient().LoginWithMFA(th.BasicUser.Email, th.BasicUser.Password, newCodes.Codes[0])
+		require.NoError(t, err)
+		codes = newCodes
+	})
+
+	t.Run("recovery codes are removed with the MFA", func(t *testing.T) {
+		_, err := th.SystemAdminClient.UpdateUserMfa(th.BasicUser.Id, "", false)
+		require.NoError(t, err)
+
+		require.NoError(t, th.App.Srv().Store.User().UpdateMfaActive(th.BasicUser.Id, true))
+		th.App.InvalidateCacheForUser(th.BasicUser.Id)
+
+		_, _, err = th.CreateClient().LoginWithMFA(th.BasicUser.Email, th.BasicUser.Password, codes.Codes[1])
+		require.Error(t, err)
+	})
+}
+
+func TestMfaReset(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableMultifactorAuthentication = true
+		*cfg.ServiceSettings.EnableMfaSelfServiceReset = true
+	})
+
+	activateMfa := func(t *testing.T) {
+		t.Helper()
+		require.NoError(t, th.App.Srv().Store.User().UpdateMfaActive(th.BasicUser.Id, true))
+		th.App.InvalidateCacheForUser(th.BasicUser.Id)
+	}
+	// The token is emailed to the user, it is made here as no email is received in tests
+	newResetToken := func(t *testing.T) string {
+		t.Helper()
+		extra := model.MapToJSON(map[string]string{"UserId": th.BasicUser.Id, "Email": th.BasicUser.Email})
+		token := model.NewToken(app.TokenTypeMfaReset, extra)
+		require.NoError(t, th.App.Srv().Store.Token().Save(token))
+		return token.Token
+	}
+	mfaActive := func(t *testing.T) bool {
+		t.Helper()
+		user, appErr := th.App.GetUser(th.BasicUser.Id)
+		require.Nil(t, appErr)
+		return user.MfaActive
+	}
+
+	t.Run("should not reset with an invalid token", func(t *testing.T) {
+		_, resp, err := th.Client.ResetMfa(model.NewRandomString(model.TokenSize))
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("should not be usable when disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableMfaSelfServiceReset = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableMfaSelfServiceReset = true })
+
+		resp, err := th.Client.SendMfaResetEmail(th.BasicUser.Email)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+
+	t.Run("reset with the approval of an administrator", func(t *testing.T) {
+		activateMfa(t)
+
+		status, _, err := th.Client.ResetMfa(newResetToken(t))
+		require.NoError(t, err)
+		assert.Equal(t, model.MfaResetStatusPending, status)
+		assert.True(t, mfaActive(t))
+
+		_, resp, err := th.Client.GetMfaResetRequests()
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		requests, _, err := th.SystemAdminClient.GetMfaResetRequests()
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.Equal(t, th.BasicUser.Id, requests[0].UserId)
+
+		resp, err = th.Client.ApproveMfaReset(th.BasicUser.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, err = th.SystemAdminClient.ApproveMfaReset(th.BasicUser.Id)
+		require.NoError(t, err)
+		assert.False(t, mfaActive(t))
+
+		requests, _, err = th.SystemAdminClient.GetMfaResetRequests()
+		require.NoError(t, err)
+		assert.Empty(t, requests)
+
+		resp, err = th.SystemAdminClient.ApproveMfaReset(th.BasicUser.Id)
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("deny a reset", func(t *testing.T) {
+		activateMfa(t)
+
+		status, _, err := th.Client.ResetMfa(newResetToken(t))
+		require.NoError(t, err)
+		assert.Equal(t, model.MfaResetStatusPending, status)
+
+		_, err = th.SystemAdminClient.DenyMfaReset(th.BasicUser.Id)
+		require.NoError(t, err)
+		assert.True(t, mfaActive(t))
+
+		requests, _, err := th.SystemAdminClient.GetMfaResetRequests()
+		require.NoError(t, err)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("reset without approval", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.MfaResetRequiresApproval = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.MfaResetRequiresApproval = true })
+		activateMfa(t)
+
+		token := newResetToken(t)
+		status, _, err := th.Client.ResetMfa(token)
+		require.NoError(t, err)
+		assert.Equal(t, model.MfaResetStatusCompleted, status)
+		assert.False(t, mfaActive(t))
+
+		_, resp, err := th.Client.ResetMfa(token)
+		require.Error(t, err, "the token should only be usable once")
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("user managers can't reset the MFA of a system admin", func(t *testing.T) {
+		th.AddPermissionToRole(model.PermissionEditOtherUsers.Id, model.SystemUserRoleId)
+		defer th.RemovePermissionFromRole(model.PermissionEditOtherUsers.Id, model.SystemUserRoleId)
+
+		resp, err := th.Client.ApproveMfaReset(th.SystemAdminUser.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		resp, err = th.Client.DenyMfaReset(th.SystemAdminUser.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+}
+
 func TestGenerateMfaSecret(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
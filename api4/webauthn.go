@@ -0,0 +1,285 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cjdelisle/matterfoss-server/v6/audit"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+func (api *API) InitWebAuthn() {
+	api.BaseRoutes.User.Handle("/webauthn/register/begin", api.APISessionRequiredMfa(beginWebAuthnRegistration)).Methods("POST")
+	api.BaseRoutes.User.Handle("/webauthn/register/finish", api.APISessionRequiredMfa(finishWebAuthnRegistration)).Methods("POST")
+	api.BaseRoutes.User.Handle("/webauthn/credentials", api.APISessionRequiredMfa(getWebAuthnCredentials)).Methods("GET")
+	api.BaseRoutes.User.Handle("/webauthn/credentials/{webauthn_credential_id:[A-Za-z0-9]+}", api.APISessionRequiredMfa(patchWebAuthnCredential)).Methods("PUT")
+	api.BaseRoutes.User.Handle("/webauthn/credentials/{webauthn_credential_id:[A-Za-z0-9]+}", api.APISessionRequiredMfa(deleteWebAuthnCredential)).Methods("DELETE")
+
+	api.BaseRoutes.Users.Handle("/webauthn/login/begin", api.APIHandler(beginWebAuthnLogin)).Methods("POST")
+	api.BaseRoutes.Users.Handle("/webauthn/login/finish", api.APIHandler(loginByWebAuthn)).Methods("POST")
+}
+
+// checkWebAuthnRegistrationPermission only lets users register credentials for themselves,
+// as the authenticator of the user is needed.
+func checkWebAuthnRegistrationPermission(c *Context) bool {
+	if c.AppContext.Session().IsOAuth {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		c.Err.DetailedError += ", attempted access by oauth app"
+		return false
+	}
+
+	if c.AppContext.Session().UserId != c.Params.UserId {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return false
+	}
+	return true
+}
+
+// getWebAuthnCredentialForUser returns the credential in the URL, provided it is registered
+// for the user in the URL and the session is allowed to manage the credentials of the user.
+func getWebAuthnCredentialForUser(c *Context) *model.WebAuthnCredential {
+	if c.AppContext.Session().IsOAuth {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		c.Err.DetailedError += ", attempted access by oauth app"
+		return nil
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return nil
+	}
+
+	credential, err := c.App.GetWebAuthnCredential(c.Params.WebAuthnCredentialId)
+	if err != nil {
+		c.Err = err
+		return nil
+	}
+
+	if credential.UserId != c.Params.UserId {
+		c.Err = model.NewAppError("getWebAuthnCredentialForUser", "app.webauthn.get.not_found.app_error", nil, "", http.StatusNotFound)
+		return nil
+	}
+	return credential
+}
+
+func beginWebAuthnRegistration(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !checkWebAuthnRegistrationPermission(c) {
+		return
+	}
+
+	options, err := c.App.BeginWebAuthnRegistration(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(options); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func finishWebAuthnRegistration(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	var registration *model.WebAuthnRegistrationRequest
+	if jsonErr := json.NewDecoder(r.Body).Decode(&registration); jsonErr != nil || registration == nil || registration.Credential == nil {
+		c.SetInvalidParam("credential")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("finishWebAuthnRegistration", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
+	if !checkWebAuthnRegistrationPermission(c) {
+		return
+	}
+
+	credential, err := c.App.FinishWebAuthnRegistration(c.Params.UserId, registration)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddMeta("webauthn_credential", credential)
+	c.LogAudit("name=" + credential.Name)
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(credential); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func getWebAuthnCredentials(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId()
+	if c.Err != nil {
+		return
+	}
+
+	if !c.App.SessionHasPermissionToUser(*c.AppContext.Session(), c.Params.UserId) {
+		c.SetPermissionError(model.PermissionEditOtherUsers)
+		return
+	}
+
+	credentials, err := c.App.GetWebAuthnCredentialsForUser(c.Params.UserId)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(credentials); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func patchWebAuthnCredential(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireWebAuthnCredentialId()
+	if c.Err != nil {
+		return
+	}
+
+	var patch *model.WebAuthnCredentialPatch
+	if jsonErr := json.NewDecoder(r.Body).Decode(&patch); jsonErr != nil || patch == nil {
+		c.SetInvalidParam("webauthn_credential")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("patchWebAuthnCredential", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
+	credential := getWebAuthnCredentialForUser(c)
+	if c.Err != nil {
+		return
+	}
+	auditRec.AddMeta("webauthn_credential", credential)
+
+	updated, err := c.App.PatchWebAuthnCredential(credential, patch)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	auditRec.AddMeta("patch", updated)
+
+	if err := json.NewEncoder(w).Encode(updated); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func deleteWebAuthnCredential(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.RequireUserId().RequireWebAuthnCredentialId()
+	if c.Err != nil {
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("deleteWebAuthnCredential", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("user_id", c.Params.UserId)
+
+	credential := getWebAuthnCredentialForUser(c)
+	if c.Err != nil {
+		return
+	}
+	auditRec.AddMeta("webauthn_credential", credential)
+
+	if err := c.App.DeleteWebAuthnCredential(credential.Id); err != nil {
+		c.Err = err
+		return
+	}
+
+	auditRec.Success()
+	c.LogAudit("name=" + credential.Name)
+
+	ReturnStatusOK(w)
+}
+
+func beginWebAuthnLogin(c *Context, w http.ResponseWriter, r *http.Request) {
+	props := model.MapFromJSON(r.Body)
+
+	options, err := c.App.BeginWebAuthnLogin(props["login_id"])
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(options); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
+
+func loginByWebAuthn(c *Context, w http.ResponseWriter, r *http.Request) {
+	var login *model.WebAuthnLoginRequest
+	if jsonErr := json.NewDecoder(r.Body).Decode(&login); jsonErr != nil || login == nil || login.Credential == nil {
+		c.SetInvalidParam("credential")
+		return
+	}
+
+	auditRec := c.MakeAuditRecord("loginByWebAuthn", audit.Fail)
+	defer c.LogAuditRec(auditRec)
+	auditRec.AddMeta("device_id", login.DeviceId)
+
+	user, err := c.App.AuthenticateUserForWebAuthnLogin(login)
+	if err != nil {
+		c.Err = err
+		return
+	}
+	auditRec.AddMeta("user", user)
+
+	if user.IsGuest() {
+		if c.App.Channels().License() == nil {
+			c.Err = model.NewAppError("loginByWebAuthn", "api.user.login.guest_accounts.license.error", nil, "", http.StatusUnauthorized)
+			return
+		}
+		if !*c.App.Config().GuestAccountsSettings.Enable {
+			c.Err = model.NewAppError("loginByWebAuthn", "api.user.login.guest_accounts.disabled.error", nil, "", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	c.LogAuditWithUserId(user.Id, "authenticated")
+
+	err = c.App.DoLogin(c.AppContext, w, r, user, login.DeviceId, false, false, false)
+	if err != nil {
+		c.Err = err
+		return
+	}
+
+	c.LogAuditWithUserId(user.Id, "success")
+
+	if r.Header.Get(model.HeaderRequestedWith) == model.HeaderRequestedWithXML {
+		c.App.AttachSessionCookies(c.AppContext, w, r)
+	}
+
+	userTermsOfService, err := c.App.GetUserTermsOfService(user.Id)
+	if err != nil && err.StatusCode != http.StatusNotFound {
+		c.Err = err
+		return
+	}
+
+	if userTermsOfService != nil {
+		user.TermsOfServiceId = userTermsOfService.TermsOfServiceId
+		user.TermsOfServiceCreateAt = userTermsOfService.CreateAt
+	}
+
+	user.Sanitize(map[string]bool{})
+
+	auditRec.Success()
+	if err := json.NewEncoder(w).Encode(user); err != nil {
+		mlog.Warn("Error while writing response", mlog.Err(err))
+	}
+}
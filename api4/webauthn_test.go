@@ -0,0 +1,209 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/webauthn/webauthntest"
+)
+
+func TestWebAuthn(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	siteURL := "http://localhost:8065"
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.SiteURL = siteURL
+		*cfg.ServiceSettings.EnableWebAuthn = true
+	})
+	authenticator := webauthntest.NewAuthenticator(siteURL)
+
+	register := func(t *testing.T, name string) *model.WebAuthnCredential {
+		t.Helper()
+		options, _, err := th.Client.BeginWebAuthnRegistration(th.BasicUser.Id)
+		require.NoError(t, err)
+		response, err := authenticator.Register(options)
+		require.NoError(t, err)
+		credential, resp, err := th.Client.FinishWebAuthnRegistration(th.BasicUser.Id, &model.WebAuthnRegistrationRequest{
+			Name:       name,
+			Credential: response,
+		})
+		require.NoError(t, err)
+		CheckCreatedStatus(t, resp)
+		return credential
+	}
+
+	credential := register(t, "Security key")
+	assert.Equal(t, th.BasicUser.Id, credential.UserId)
+	assert.Equal(t, "Security key", credential.Name)
+
+	t.Run("should not register a credential twice", func(t *testing.T) {
+		options, _, err := th.Client.BeginWebAuthnRegistration(th.BasicUser.Id)
+		require.NoError(t, err)
+		require.Len(t, options.ExcludeCredentials, 1)
+		assert.Equal(t, credential.CredentialId, options.ExcludeCredentials[0].Id)
+	})
+
+	t.Run("should not complete a registration twice", func(t *testing.T) {
+		options, _, err := th.Client.BeginWebAuthnRegistration(th.BasicUser.Id)
+		require.NoError(t, err)
+		response, err := webauthntest.NewAuthenticator(siteURL).Register(options)
+		require.NoError(t, err)
+		registration := &model.WebAuthnRegistrationRequest{Name: "Phone", Credential: response}
+
+		second, _, err := th.Client.FinishWebAuthnRegistration(th.BasicUser.Id, registration)
+		require.NoError(t, err)
+
+		_, resp, err := th.Client.FinishWebAuthnRegistration(th.BasicUser.Id, registration)
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+		CheckErrorID(t, err, "app.webauthn.invalid_challenge.app_error")
+
+		_, err = th.Client.DeleteWebAuthnCredential(th.BasicUser.Id, second.Id)
+		require.NoError(t, err)
+	})
+
+	t.Run("should not register from another origin", func(t *testing.T) {
+		options, _, err := th.Client.BeginWebAuthnRegistration(th.BasicUser.Id)
+		require.NoError(t, err)
+		response, err := webauthntest.NewAuthenticator("http://evil.example.com").Register(options)
+		require.NoError(t, err)
+
+		_, resp, err := th.Client.FinishWebAuthnRegistration(th.BasicUser.Id, &model.WebAuthnRegistrationRequest{Name: "Evil", Credential: response})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("should not register a credential for another user", func(t *testing.T) {
+		_, resp, err := th.Client.BeginWebAuthnRegistration(th.BasicUser2.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.BeginWebAuthnRegistration(th.BasicUser.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+	})
+
+	t.Run("get credentials", func(t *testing.T) {
+		credentials, _, err := th.Client.GetWebAuthnCredentials(th.BasicUser.Id)
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+		assert.Equal(t, credential.Id, credentials[0].Id)
+
+		_, resp, err := th.Client.GetWebAuthnCredentials(th.BasicUser2.Id)
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		credentials, _, err = th.SystemAdminClient.GetWebAuthnCredentials(th.BasicUser.Id)
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+	})
+
+	t.Run("rename a credential", func(t *testing.T) {
+		name := "Laptop"
+		patched, _, err := th.Client.PatchWebAuthnCredential(th.BasicUser.Id, credential.Id, &model.WebAuthnCredentialPatch{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, "Laptop", patched.Name)
+
+		_, resp, err := th.Client.PatchWebAuthnCredential(th.BasicUser2.Id, credential.Id, &model.WebAuthnCredentialPatch{Name: &name})
+		require.Error(t, err)
+		CheckForbiddenStatus(t, resp)
+
+		_, resp, err = th.SystemAdminClient.PatchWebAuthnCredential(th.BasicUser2.Id, credential.Id, &model.WebAuthnCredentialPatch{Name: &name})
+		require.Error(t, err)
+		CheckNotFoundStatus(t, resp)
+	})
+
+	t.Run("log in without a password", func(t *testing.T) {
+		client := th.CreateClient()
+		options, _, err := client.BeginWebAuthnLogin(th.BasicUser.Email)
+		require.NoError(t, err)
+		require.Len(t, options.AllowCredentials, 1)
+
+		response, err := authenticator.Login(options)
+		require.NoError(t, err)
+		user, _, err := client.LoginByWebAuthn(&model.WebAuthnLoginRequest{Credential: response})
+		require.NoError(t, err)
+		assert.Equal(t, th.BasicUser.Id, user.Id)
+
+		me, _, err := client.GetMe("")
+		require.NoError(t, err)
+		assert.Equal(t, th.BasicUser.Id, me.Id)
+
+		_, resp, err := client.LoginByWebAuthn(&model.WebAuthnLoginRequest{Credential: response})
+		require.Error(t, err)
+		CheckBadRequestStatus(t, resp)
+	})
+
+	t.Run("log in with a discoverable credential", func(t *testing.T) {
+		client := th.CreateClient()
+		options, _, err := client.BeginWebAuthnLogin("")
+		require.NoError(t, err)
+		assert.Empty(t, options.AllowCredentials)
+
+		response, err := authenticator.Login(options)
+		require.NoError(t, err)
+		user, _, err := client.LoginByWebAuthn(&model.WebAuthnLoginRequest{Credential: response})
+		require.NoError(t, err)
+		assert.Equal(t, th.BasicUser.Id, user.Id)
+	})
+
+	t.Run("should not log in without user verification", func(t *testing.T) {
+		client := th.CreateClient()
+		options, _, err := client.BeginWebAuthnLogin(th.BasicUser.Email)
+		require.NoError(t, err)
+
+		authenticator.UserVerified = false
+		defer func() { authenticator.UserVerified = true }()
+		response, err := authenticator.Login(options)
+		require.NoError(t, err)
+		_, resp, err := client.LoginByWebAuthn(&model.WebAuthnLoginRequest{Credential: response})
+		require.Error(t, err)
+		CheckUnauthorizedStatus(t, resp)
+	})
+
+	t.Run("should require the credential to log in with a password when MFA is enabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableMultifactorAuthentication = true })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableMultifactorAuthentication = false })
+
+		_, _, err := th.CreateClient().Login(th.BasicUser.Email, th.BasicUser.Password)
+		require.Error(t, err)
+		CheckErrorID(t, err, "api.user.check_user_mfa.webauthn_required.app_error")
+
+		_, _, err = th.CreateClient().Login(th.BasicUser2.Email, th.BasicUser2.Password)
+		require.NoError(t, err)
+	})
+
+	t.Run("delete a credential", func(t *testing.T) {
+		_, err := th.Client.DeleteWebAuthnCredential(th.BasicUser.Id, credential.Id)
+		require.NoError(t, err)
+
+		credentials, _, err := th.Client.GetWebAuthnCredentials(th.BasicUser.Id)
+		require.NoError(t, err)
+		assert.Empty(t, credentials)
+
+		client := th.CreateClient()
+		options, _, err := client.BeginWebAuthnLogin("")
+		require.NoError(t, err)
+		response, err := authenticator.Login(options)
+		require.NoError(t, err)
+		_, resp, err := client.LoginByWebAuthn(&model.WebAuthnLoginRequest{Credential: response})
+		require.Error(t, err)
+		CheckUnauthorizedStatus(t, resp)
+	})
+
+	t.Run("should not be available when disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableWebAuthn = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableWebAuthn = true })
+
+		_, resp, err := th.Client.BeginWebAuthnRegistration(th.BasicUser.Id)
+		require.Error(t, err)
+		CheckNotImplementedStatus(t, resp)
+	})
+}
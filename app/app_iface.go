@@ -31,6 +31,7 @@ import (
 	"github.com/cjdelisle/matterfoss-server/v6/shared/filestore"
 	"github.com/cjdelisle/matterfoss-server/v6/shared/i18n"
 	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/webauthn"
 	"github.com/cjdelisle/matterfoss-server/v6/store"
 )
 
@@ -53,6 +54,24 @@ type AppIface interface {
 	AddPublicKey(name string, key io.Reader) *model.AppError
 	// AddUserToChannel adds a user to a given channel.
 	AddUserToChannel(user *model.User, channel *model.Channel, skipTeamMemberIntegrityCheck bool) (*model.ChannelMember, *model.AppError)
+	// ApproveMfaReset resets the multi-factor authentication of the user, as requested by the
+	// user.
+	ApproveMfaReset(userID string) *model.AppError
+	// AuthenticateUserForWebAuthnLogin verifies the assertion made by an authenticator for the
+	// options returned by BeginWebAuthnLogin, and returns the user owning the credential. As
+	// the authenticator verified the user, the assertion satisfies the multi-factor
+	// authentication on its own.
+	AuthenticateUserForWebAuthnLogin(login *model.WebAuthnLoginRequest) (*model.User, *model.AppError)
+	// BeginWebAuthnLogin returns the options with which an authenticator makes an assertion to
+	// log in. When the login id of a user is given, only the credentials of the user are
+	// allowed, otherwise the user picks a discoverable credential.
+	BeginWebAuthnLogin(loginID string) (*webauthn.RequestOptions, *model.AppError)
+	// BeginWebAuthnRegistration returns the options with which an authenticator creates a new
+	// credential for the user.
+	BeginWebAuthnRegistration(userID string) (*webauthn.CreationOptions, *model.AppError)
+	// BulkImportWithOpts imports the data file with the given options, which allow to
+	// follow the progress of the import, to resume it and to go on past failing lines.
+	BulkImportWithOpts(c *request.Context, jsonlReader io.Reader, attachmentsReader *zip.Reader, opts model.BulkImportOpts) (*model.AppError, int)
 	// Caller must close the first return value
 	FileReader(path string) (filestore.ReadCloseSeeker, *model.AppError)
 	// ChannelMembersMinusGroupMembers returns the set of users in the given channel minus the set of users in the given
@@ -74,12 +93,17 @@ type AppIface interface {
 	CheckProviderAttributes(user *model.User, patch *model.UserPatch) string
 	// ClientConfigWithComputed gets the configuration in a format suitable for sending to the client.
 	ClientConfigWithComputed() map[string]string
+	// ClosePoll stops the voting on the poll post.
+	ClosePoll(c *request.Context, postID string) (*model.Post, *model.AppError)
 	// ConvertBotToUser converts a bot to user.
 	ConvertBotToUser(bot *model.Bot, userPatch *model.UserPatch, sysadmin bool) (*model.User, *model.AppError)
 	// ConvertUserToBot converts a user to bot.
 	ConvertUserToBot(user *model.User) (*model.Bot, *model.AppError)
 	// CreateBot creates the given bot and corresponding user.
 	CreateBot(c *request.Context, bot *model.Bot) (*model.Bot, *model.AppError)
+	// CreateChannelBookmark adds the bookmark after the other bookmarks of its channel. The
+	// bookmarks synchronized from a remote cluster keep their sort order.
+	CreateChannelBookmark(c *request.Context, bookmark *model.ChannelBookmark) (*model.ChannelBookmark, *model.AppError)
 	// CreateChannelScheme creates a new Scheme of scope channel and assigns it to the channel.
 	CreateChannelScheme(channel *model.Channel) (*model.Scheme, *model.AppError)
 	// CreateDefaultMemberships adds users to teams and channels based on their group memberships and how those groups
@@ -90,6 +114,9 @@ type AppIface interface {
 	// CreateGuest creates a guest and sets several fields of the returned User struct to
 	// their zero values.
 	CreateGuest(c *request.Context, user *model.User) (*model.User, *model.AppError)
+	// CreateTeamImportJob saves the export file into the import directory and creates the
+	// import job importing it into the team, so that the export is imported in the background.
+	CreateTeamImportJob(fileData io.Reader, fileName, importFrom, teamID string) (*model.Job, *model.AppError)
 	// CreateUser creates a user and sets several fields of the returned User struct to
 	// their zero values.
 	CreateUser(c *request.Context, user *model.User) (*model.User, *model.AppError)
@@ -104,16 +131,27 @@ type AppIface interface {
 	//	['town-square', 'game-of-thrones', 'wow']
 	//
 	DefaultChannelNames() []string
+	// DeleteChannelBookmark deletes the bookmark and returns it as deleted.
+	DeleteChannelBookmark(bookmarkID string) (*model.ChannelBookmark, *model.AppError)
 	// DeleteChannelScheme deletes a channels scheme and sets its SchemeId to nil.
 	DeleteChannelScheme(channel *model.Channel) (*model.Channel, *model.AppError)
+	// DeleteDraft deletes the draft of the user in the channel, or in the thread of the
+	// channel if the root id isn't empty.
+	DeleteDraft(userID, channelID, rootID string) *model.AppError
 	// DeleteGroupConstrainedMemberships deletes team and channel memberships of users who aren't members of the allowed
 	// groups of all group-constrained teams and channels.
 	DeleteGroupConstrainedMemberships(c *request.Context) error
 	// DeletePublicKey will delete plugin public key from the config.
 	DeletePublicKey(name string) *model.AppError
+	// DeleteReminder deletes the reminder of the user, whether it fired or not.
+	DeleteReminder(userID, id string) (*model.Reminder, *model.AppError)
+	// DeleteScheduledPost deletes the post scheduled by the user.
+	DeleteScheduledPost(userID, id string) (*model.ScheduledPost, *model.AppError)
 	// DemoteUserToGuest Convert user's roles and all his membership's roles from
 	// regular user roles to guest roles.
 	DemoteUserToGuest(user *model.User) *model.AppError
+	// DenyMfaReset rejects the reset of the multi-factor authentication requested by the user.
+	DenyMfaReset(userID string) *model.AppError
 	// DisablePlugin will set the config for an installed plugin to disabled, triggering deactivation if active.
 	// Notifies cluster peers through config change.
 	DisablePlugin(id string) *model.AppError
@@ -143,6 +181,12 @@ type AppIface interface {
 	// FilterNonGroupTeamMembers returns the subset of the given user IDs of the users who are not members of groups
 	// associated to the team excluding bots.
 	FilterNonGroupTeamMembers(userIDs []string, team *model.Team) ([]string, error)
+	// FinishWebAuthnRegistration verifies the credential created by an authenticator for the
+	// options returned by BeginWebAuthnRegistration, and registers it for the user.
+	FinishWebAuthnRegistration(userID string, registration *model.WebAuthnRegistrationRequest) (*model.WebAuthnCredential, *model.AppError)
+	// GenerateMfaRecoveryCodes generates new recovery codes for the user, replacing the previous
+	// ones. The codes are only returned here, as only their hashes are stored.
+	GenerateMfaRecoveryCodes(userID string) ([]string, *model.AppError)
 	// GetAllLdapGroupsPage retrieves all LDAP groups under the configured base DN using the default or configured group
 	// filter.
 	GetAllLdapGroupsPage(page int, perPage int, opts model.LdapGroupSearchOpts) ([]*model.Group, int, *model.AppError)
@@ -150,6 +194,10 @@ type AppIface interface {
 	GetBot(botUserId string, includeDeleted bool) (*model.Bot, *model.AppError)
 	// GetBots returns the requested page of bots.
 	GetBots(options *model.BotGetOptions) (model.BotList, *model.AppError)
+	// GetChannelBookmarks returns the bookmarks of the channel in their sort order. When since
+	// is not zero, the bookmarks updated at or after that time are returned instead, the
+	// deleted ones included.
+	GetChannelBookmarks(channelID string, since int64) ([]*model.ChannelBookmark, *model.AppError)
 	// GetChannelGroupUsers returns the users who are associated to the channel via GroupChannels and GroupMembers.
 	GetChannelGroupUsers(channelID string) ([]*model.User, *model.AppError)
 	// GetChannelModerationsForChannel Gets a channels ChannelModerations from either the higherScoped roles or from the channel scheme roles.
@@ -158,6 +206,9 @@ type AppIface interface {
 	GetClusterPluginStatuses() (model.PluginStatuses, *model.AppError)
 	// GetConfigFile proxies access to the given configuration file to the underlying config store.
 	GetConfigFile(name string) ([]byte, error)
+	// GetDraftsForUser returns the drafts of the user in the channels of the team, and in
+	// the direct and group message channels.
+	GetDraftsForUser(userID, teamID string) ([]*model.Draft, *model.AppError)
 	// GetEmojiStaticURL returns a relative static URL for system default emojis,
 	// and the API route for custom ones. Errors if not found or if custom and deleted.
 	GetEmojiStaticURL(emojiName string) (string, *model.AppError)
@@ -177,6 +228,17 @@ type AppIface interface {
 	// GetMarketplacePlugins returns a list of plugins from the marketplace-server,
 	// and plugins that are installed locally.
 	GetMarketplacePlugins(filter *model.MarketplacePluginFilter) ([]*model.MarketplacePlugin, *model.AppError)
+	// GetMfaResetRequests returns the resets of the multi-factor authentication awaiting the
+	// approval of an administrator.
+	GetMfaResetRequests() ([]*model.MfaResetRequest, *model.AppError)
+	// GetOpenIDJSONWebKeySet returns the public keys the ID tokens are verified with. Besides the
+	// current key, the key set has the previous one, for the ID tokens issued before the last
+	// rotation, and the next one, so that the clients caching the key set know it beforehand.
+	GetOpenIDJSONWebKeySet() (*model.JSONWebKeySet, *model.AppError)
+	// GetOpenIDProviderMetadata returns the discovery document of the OpenID Provider.
+	GetOpenIDProviderMetadata() (*model.OpenIDProviderMetadata, *model.AppError)
+	// GetOpenIDUserInfo returns the claims of the user allowed by the scope granted to the OAuth app.
+	GetOpenIDUserInfo(userID, scope string) (*model.OpenIDUserInfo, *model.AppError)
 	// GetPluginStatus returns the status for a plugin installed on this server.
 	GetPluginStatus(id string) (*model.PluginStatus, *model.AppError)
 	// GetPluginStatuses returns the status for plugins installed on this server.
@@ -187,12 +249,23 @@ type AppIface interface {
 	// To get the plugins environment when the plugins are disabled, manually acquire the plugins
 	// lock instead.
 	GetPluginsEnvironment() *plugin.Environment
+	// GetPollResults returns the tallies of the poll post along with the options the user voted for.
+	GetPollResults(postID, userID string) (*model.PollResults, *model.AppError)
+	// GetPostEditHistory returns the revisions of the post, most recent first.
+	GetPostEditHistory(postID string) ([]*model.PostRevision, *model.AppError)
 	// GetProductNotices is called from the frontend to fetch the product notices that are relevant to the caller
 	GetProductNotices(c *request.Context, userID, teamID string, client model.NoticeClientType, clientVersion string, locale string) (model.NoticeMessages, *model.AppError)
 	// GetPublicKey will return the actual public key saved in the `name` file.
 	GetPublicKey(name string) ([]byte, *model.AppError)
+	// GetRemindersForUser returns the reminders of the user ordered by their target time,
+	// including the ones which fired and were neither snoozed nor completed.
+	GetRemindersForUser(userID string) ([]*model.Reminder, *model.AppError)
 	// GetSanitizedConfig gets the configuration for a system admin without any secrets.
 	GetSanitizedConfig() *model.Config
+	// GetScheduledPostsForUser returns the posts the user scheduled in the channels of the
+	// team, and in the direct and group message channels, including the ones which couldn't
+	// be sent.
+	GetScheduledPostsForUser(userID, teamID string) ([]*model.ScheduledPost, *model.AppError)
 	// GetSchemeRolesForChannel Checks if a channel or its team has an override scheme for channel roles and returns the scheme roles or default channel roles.
 	GetSchemeRolesForChannel(channelID string) (guestRoleName string, userRoleName string, adminRoleName string, err *model.AppError)
 	// GetSessionLengthInMillis returns the session length, in milliseconds,
@@ -208,12 +281,18 @@ type AppIface interface {
 	GetTotalUsersStats(viewRestrictions *model.ViewUsersRestrictions) (*model.UsersStats, *model.AppError)
 	// HasRemote returns whether a given channelID is present in the channel remotes or not.
 	HasRemote(channelID string, remoteID string) (bool, error)
+	// HasWebAuthnCredentials tells whether the user registered a credential with which to
+	// sign in, which is then the second factor of the user.
+	HasWebAuthnCredentials(userID string) (bool, *model.AppError)
 	// HubRegister registers a connection to a hub.
 	HubRegister(webConn *WebConn)
 	// HubUnregister unregisters a connection from a hub.
 	HubUnregister(webConn *WebConn)
 	// InstallPlugin unpacks and installs a plugin but does not enable or activate it.
 	InstallPlugin(pluginFile io.ReadSeeker, replace bool) (*model.Manifest, *model.AppError)
+	// IntrospectOAuthToken returns the state of an access or refresh token issued to the app, as
+	// defined by RFC 7662. The tokens issued to other apps are reported as inactive.
+	IntrospectOAuthToken(clientId, secret, token string) (*model.IntrospectionResponse, *model.AppError)
 	// LimitedClientConfigWithComputed gets the configuration in a format suitable for sending to the client.
 	LimitedClientConfigWithComputed() map[string]string
 	// LogAuditRec logs an audit record using default LvlAuditCLI.
@@ -253,18 +332,45 @@ type AppIface interface {
 	// PopulateWebConnConfig checks if the connection id already exists in the hub,
 	// and if so, accordingly populates the other fields of the webconn.
 	PopulateWebConnConfig(s *model.Session, cfg *WebConnConfig, seqVal string) (*WebConnConfig, error)
+	// ProcessReminders fires the reminders which are due. The reminders of the users who were
+	// deactivated, or about posts they can no longer read, are deleted.
+	ProcessReminders() *model.AppError
+	// ProcessScheduledPosts sends the scheduled posts which are due. A post is sent only if
+	// its user can still post in its channel, otherwise it's kept with the reason it couldn't
+	// be sent.
+	ProcessScheduledPosts() *model.AppError
 	// PromoteGuestToUser Convert user's roles and all his membership's roles from
 	// guest roles to regular user roles.
 	PromoteGuestToUser(c *request.Context, user *model.User, requestorId string) *model.AppError
+	// RegenerateMfaRecoveryCodes generates new recovery codes for the user, who must confirm it
+	// with a multi-factor authentication token or an unused recovery code.
+	RegenerateMfaRecoveryCodes(userID, token string) ([]string, *model.AppError)
 	// RenameChannel is used to rename the channel Name and the DisplayName fields
 	RenameChannel(channel *model.Channel, newChannelName string, newDisplayName string) (*model.Channel, *model.AppError)
 	// RenameTeam is used to rename the team Name and the DisplayName fields
 	RenameTeam(team *model.Team, newTeamName string, newDisplayName string) (*model.Team, *model.AppError)
+	// ResetMfaFromToken resets the multi-factor authentication of the user the given token
+	// was emailed to, and returns model.MfaResetStatusCompleted. When the reset requires the
+	// approval of an administrator, it is only requested and model.MfaResetStatusPending is
+	// returned.
+	ResetMfaFromToken(tokenString string) (string, *model.AppError)
+	// RevokeOAuthToken revokes an access or refresh token issued to the app, along with the token
+	// issued with it, as defined by RFC 7009. Unknown tokens are ignored.
+	RevokeOAuthToken(clientId, secret, token string) *model.AppError
+	// RocketChatConvertToBulkImport converts the Rocket.Chat export into a bulk import data
+	// file for the team, written to w, and returns the number of lines written. The
+	// Rocket.Chat users are merged with the existing users sharing their email address.
+	RocketChatConvertToBulkImport(zipReader *zip.Reader, w io.Writer, teamID string) (int, *model.AppError)
 	// RevokeSessionsFromAllUsers will go through all the sessions active
 	// in the server and revoke them
 	RevokeSessionsFromAllUsers() *model.AppError
 	// SaveConfig replaces the active configuration, optionally notifying cluster peers.
 	SaveConfig(newCfg *model.Config, sendConfigChangeClusterMessage bool) (*model.Config, *model.Config, *model.AppError)
+	// SaveReminder reminds the user of the post or of the message of the reminder at its
+	// target time.
+	SaveReminder(reminder *model.Reminder) (*model.Reminder, *model.AppError)
+	// SaveScheduledPost schedules the post of the user to be sent at its scheduled time.
+	SaveScheduledPost(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, *model.AppError)
 	// SearchAllChannels returns a list of channels, the total count of the results of the search (if the paginate search option is true), and an error.
 	SearchAllChannels(term string, opts model.ChannelSearchOpts) (model.ChannelListWithTeamData, int64, *model.AppError)
 	// SearchAllTeams returns a team list and the total count of the results
@@ -272,12 +378,19 @@ type AppIface interface {
 	// SendAdminUpgradeRequestEmail takes the username of user trying to alert admins and then applies rate limit of n (number of admins) emails per user per day
 	// before sending the emails.
 	SendAdminUpgradeRequestEmail(username string, subscription *model.Subscription, action string) *model.AppError
+	// SendMfaReset emails the user with the given email a link to reset the multi-factor
+	// authentication. Whether the user exists is not disclosed, false is returned when no
+	// email was sent.
+	SendMfaReset(email string, siteURL string) (bool, *model.AppError)
 	// SendNoCardPaymentFailedEmail
 	SendNoCardPaymentFailedEmail() *model.AppError
 	// SessionHasPermissionToManageBot returns nil if the session has access to manage the given bot.
 	// This function deviates from other authorization checks in returning an error instead of just
 	// a boolean, allowing the permission failure to be exposed with more granularity.
 	SessionHasPermissionToManageBot(session model.Session, botUserId string) *model.AppError
+	// SessionHasPermissionToReadFile tells whether the session can read the file: its creator
+	// can, as can the members of the channel of the post or of the bookmarks it is attached to.
+	SessionHasPermissionToReadFile(session model.Session, info *model.FileInfo) bool
 	// SessionIsRegistered determines if a specific session has been registered
 	SessionIsRegistered(session model.Session) bool
 	// SetSessionExpireInDays sets the session's expiry the specified number of days
@@ -291,6 +404,12 @@ type AppIface interface {
 	// status to away if needed. Used by the WS to set status to away if an 'online' device disconnects
 	// while an 'away' device is still connected
 	SetStatusLastActivityAt(userID string, activityAt int64)
+	// SlackConvertToBulkImport converts the Slack export into a bulk import data file for
+	// the team, written to w, and returns the number of lines written. The Slack users are
+	// merged with the existing users sharing their email address.
+	SlackConvertToBulkImport(zipReader *zip.Reader, w io.Writer, teamID string) (int, *model.AppError)
+	// SnoozeReminder makes the reminder of the user fire again after the given duration.
+	SnoozeReminder(userID, id string, duration time.Duration) (*model.Reminder, *model.AppError)
 	// SyncLdap starts an LDAP sync job.
 	// If includeRemovedMembers is true, then members who left or were removed from a team/channel will
 	// be re-added; otherwise, they will not be re-added.
@@ -331,6 +450,12 @@ type AppIface interface {
 	UpdateBotOwner(botUserId, newOwnerId string) (*model.Bot, *model.AppError)
 	// UpdateChannel updates a given channel by its Id. It also publishes the CHANNEL_UPDATED event.
 	UpdateChannel(channel *model.Channel) (*model.Channel, *model.AppError)
+	// UpdateChannelBookmark saves the changes to the bookmark. The channel, owner and type of
+	// a bookmark cannot change.
+	UpdateChannelBookmark(c *request.Context, bookmark *model.ChannelBookmark) (*model.ChannelBookmark, *model.AppError)
+	// UpdateChannelBookmarkSortOrder moves the bookmark to the given index among the bookmarks
+	// of the channel, and returns the bookmarks of the channel in their new order.
+	UpdateChannelBookmarkSortOrder(bookmarkID, channelID string, newIndex int64) ([]*model.ChannelBookmark, *model.AppError)
 	// UpdateChannelScheme saves the new SchemeId of the channel passed.
 	UpdateChannelScheme(channel *model.Channel) (*model.Channel, *model.AppError)
 	// UpdateDNDStatusOfUsers is a recurring task which is started when server starts
@@ -338,6 +463,9 @@ type AppIface interface {
 	UpdateDNDStatusOfUsers()
 	// UpdateProductNotices is called periodically from a scheduled worker to fetch new notices and update the cache
 	UpdateProductNotices() *model.AppError
+	// UpdateScheduledPost updates the message, files, props and scheduled time of the post
+	// scheduled by the user. A post which couldn't be sent is scheduled again.
+	UpdateScheduledPost(userID string, scheduledPost *model.ScheduledPost) (*model.ScheduledPost, *model.AppError)
 	// UpdateViewedProductNotices is called from the frontend to mark a set of notices as 'viewed' by user
 	UpdateViewedProductNotices(userID string, noticeIds []string) *model.AppError
 	// UpdateViewedProductNoticesForNewUser is called when new user is created to mark all current notices for this
@@ -357,11 +485,21 @@ type AppIface interface {
 	// the same length. clientIds should either not be provided or have the same length as files and filenames.
 	// The provided files should be closed by the caller so that they are not leaked.
 	UploadFiles(c *request.Context, teamID string, channelID string, userID string, files []io.ReadCloser, filenames []string, clientIds []string, now time.Time) (*model.FileUploadResponse, *model.AppError)
+	// UpsertDraft saves the draft of the user in the channel or thread, replacing any
+	// previous one, and syncs it to the other devices of the user.
+	UpsertDraft(draft *model.Draft) (*model.Draft, *model.AppError)
 	// UserIsInAdminRoleGroup returns true at least one of the user's groups are configured to set the members as
 	// admins in the given syncable.
 	UserIsInAdminRoleGroup(userID, syncableID string, syncableType model.GroupSyncableType) (bool, *model.AppError)
 	// VerifyPlugin checks that the given signature corresponds to the given plugin and matches a trusted certificate.
 	VerifyPlugin(plugin, signature io.ReadSeeker) *model.AppError
+	// VotePoll replaces the votes of the user for the poll post with votes for the options at
+	// the given indexes. An empty list of options withdraws the votes of the user.
+	VotePoll(c *request.Context, postID, userID string, optionIndexes []int) (*model.PollResults, *model.AppError)
+	// ZulipConvertToBulkImport converts the Zulip realm export into a bulk import data file
+	// for the team, written to w, and returns the number of lines written. The Zulip users
+	// are merged with the existing users sharing their email address.
+	ZulipConvertToBulkImport(zipReader *zip.Reader, w io.Writer, teamID string) (int, *model.AppError)
 	//GetUserStatusesByIds used by apiV4
 	GetUserStatusesByIds(userIDs []string) ([]*model.Status, *model.AppError)
 	AccountMigration() einterfaces.AccountMigrationInterface
@@ -402,7 +540,7 @@ type AppIface interface {
 	AutocompleteUsersInChannel(teamID string, channelID string, term string, options *model.UserSearchOptions) (*model.UserAutocompleteInChannel, *model.AppError)
 	AutocompleteUsersInTeam(teamID string, term string, options *model.UserSearchOptions) (*model.UserAutocompleteInTeam, *model.AppError)
 	BroadcastStatus(status *model.Status)
-	BuildPostReactions(postID string) (*[]ReactionImportData, *model.AppError)
+	BuildPostReactions(postID string) (*[]model.ReactionImportData, *model.AppError)
 	BuildPushNotificationMessage(contentsConfig string, post *model.Post, user *model.User, channel *model.Channel, channelName string, senderName string, explicitMention bool, channelWideMention bool, replyToThreadType string) (*model.PushNotification, *model.AppError)
 	BuildSamlMetadataObject(idpMetadata []byte) (*model.SamlMetadataResponse, *model.AppError)
 	BulkExport(writer io.Writer, outPath string, opts model.BulkExportOpts) *model.AppError
@@ -508,6 +646,7 @@ type AppIface interface {
 	DeleteSharedChannelRemote(id string) (bool, error)
 	DeleteSidebarCategory(userID, teamID, categoryId string) *model.AppError
 	DeleteToken(token *model.Token) *model.AppError
+	DeleteWebAuthnCredential(credentialID string) *model.AppError
 	DisableAutoResponder(userID string, asAdmin bool) *model.AppError
 	DisableUserAccessToken(token *model.UserAccessToken) *model.AppError
 	DoAppMigrations()
@@ -558,6 +697,7 @@ type AppIface interface {
 	GetBrandImage() ([]byte, *model.AppError)
 	GetBulkReactionsForPosts(postIDs []string) (map[string][]*model.Reaction, *model.AppError)
 	GetChannel(channelID string) (*model.Channel, *model.AppError)
+	GetChannelBookmark(bookmarkID string, includeDeleted bool) (*model.ChannelBookmark, *model.AppError)
 	GetChannelByName(channelName, teamID string, includeDeleted bool) (*model.Channel, *model.AppError)
 	GetChannelByNameForTeamName(channelName, teamName string, includeDeleted bool) (*model.Channel, *model.AppError)
 	GetChannelCounts(teamID string, userID string) (*model.ChannelCounts, *model.AppError)
@@ -646,7 +786,7 @@ type AppIface interface {
 	GetNextPostIdFromPostList(postList *model.PostList, collapsedThreads bool) string
 	GetNotificationNameFormat(user *model.User) string
 	GetNumberOfChannelsOnTeam(teamID string) (int, *model.AppError)
-	GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectURI, code, secret, refreshToken string) (*model.AccessResponse, *model.AppError)
+	GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectURI, code, codeVerifier, secret, refreshToken string) (*model.AccessResponse, *model.AppError)
 	GetOAuthAccessTokenForImplicitFlow(userID string, authRequest *model.AuthorizeRequest) (*model.Session, *model.AppError)
 	GetOAuthApp(appID string) (*model.OAuthApp, *model.AppError)
 	GetOAuthApps(page, perPage int) ([]*model.OAuthApp, *model.AppError)
@@ -695,6 +835,7 @@ type AppIface interface {
 	GetReactionsForPost(postID string) ([]*model.Reaction, *model.AppError)
 	GetRecentlyActiveUsersForTeam(teamID string) (map[string]*model.User, *model.AppError)
 	GetRecentlyActiveUsersForTeamPage(teamID string, page, perPage int, asAdmin bool, viewRestrictions *model.ViewUsersRestrictions) ([]*model.User, *model.AppError)
+	GetReminder(id string) (*model.Reminder, *model.AppError)
 	GetRemoteCluster(remoteClusterId string) (*model.RemoteCluster, *model.AppError)
 	GetRemoteClusterForUser(remoteID string, userID string) (*model.RemoteCluster, *model.AppError)
 	GetRemoteClusterService() (remotecluster.RemoteClusterServiceIFace, *model.AppError)
@@ -709,6 +850,7 @@ type AppIface interface {
 	GetSamlMetadata() (string, *model.AppError)
 	GetSamlMetadataFromIdp(idpMetadataURL string) (*model.SamlMetadataResponse, *model.AppError)
 	GetSanitizeOptions(asAdmin bool) map[string]bool
+	GetScheduledPost(id string) (*model.ScheduledPost, *model.AppError)
 	GetScheme(id string) (*model.Scheme, *model.AppError)
 	GetSchemeByName(name string) (*model.Scheme, *model.AppError)
 	GetSchemeRolesForTeam(teamID string) (string, string, string, *model.AppError)
@@ -797,6 +939,8 @@ type AppIface interface {
 	GetViewUsersRestrictions(userID string) (*model.ViewUsersRestrictions, *model.AppError)
 	GetWarnMetricsBot() (*model.Bot, *model.AppError)
 	GetWarnMetricsStatus() (map[string]*model.WarnMetricStatus, *model.AppError)
+	GetWebAuthnCredential(credentialID string) (*model.WebAuthnCredential, *model.AppError)
+	GetWebAuthnCredentialsForUser(userID string) ([]*model.WebAuthnCredential, *model.AppError)
 	HTTPService() httpservice.HTTPService
 	Handle404(w http.ResponseWriter, r *http.Request)
 	HandleCommandResponse(c *request.Context, command *model.Command, args *model.CommandArgs, response *model.CommandResponse, builtIn bool) (*model.CommandResponse, *model.AppError)
@@ -871,6 +1015,7 @@ type AppIface interface {
 	PatchScheme(scheme *model.Scheme, patch *model.SchemePatch) (*model.Scheme, *model.AppError)
 	PatchTeam(teamID string, patch *model.TeamPatch) (*model.Team, *model.AppError)
 	PatchUser(userID string, patch *model.UserPatch, asAdmin bool) (*model.User, *model.AppError)
+	PatchWebAuthnCredential(credential *model.WebAuthnCredential, patch *model.WebAuthnCredentialPatch) (*model.WebAuthnCredential, *model.AppError)
 	PermanentDeleteAllUsers(c *request.Context) *model.AppError
 	PermanentDeleteChannel(channel *model.Channel) *model.AppError
 	PermanentDeleteTeam(team *model.Team) *model.AppError
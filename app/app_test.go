@@ -118,6 +118,7 @@ func TestDoAdvancedPermissionsMigration(t *testing.T) {
 			model.PermissionManagePrivateChannelMembers.Id,
 			model.PermissionDeletePost.Id,
 			model.PermissionEditPost.Id,
+			model.PermissionManageChannelBookmarks.Id,
 		},
 		"channel_admin": {
 			model.PermissionManageChannelRoles.Id,
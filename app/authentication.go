@@ -203,6 +203,18 @@ func (a *App) CheckUserPostflightAuthenticationCriteria(user *model.User) *model
 }
 
 func (a *App) CheckUserMfa(user *model.User, token string) *model.AppError {
+	if !user.MfaActive && *a.Config().ServiceSettings.EnableMultifactorAuthentication {
+		// The credentials registered by the user are the second factor of the user, so
+		// the user can only sign in with them.
+		hasCredentials, appErr := a.HasWebAuthnCredentials(user.Id)
+		if appErr != nil {
+			return appErr
+		}
+		if hasCredentials {
+			return model.NewAppError("CheckUserMfa", "api.user.check_user_mfa.webauthn_required.app_error", nil, "", http.StatusUnauthorized)
+		}
+	}
+
 	if !user.MfaActive || !*a.Config().ServiceSettings.EnableMultifactorAuthentication {
 		return nil
 	}
@@ -211,6 +223,11 @@ func (a *App) CheckUserMfa(user *model.User, token string) *model.AppError {
 		return model.NewAppError("CheckUserMfa", "mfa.mfa_disabled.app_error", nil, "", http.StatusNotImplemented)
 	}
 
+	// A user without the device generating the tokens can use a recovery code instead
+	if mfa.IsRecoveryCode(token) {
+		return a.useMfaRecoveryCode(user, token)
+	}
+
 	ok, err := mfa.New(a.Srv().Store.User()).ValidateToken(user.MfaSecret, token)
 	if err != nil {
 		return model.NewAppError("CheckUserMfa", "mfa.validate_token.authenticate.app_error", nil, err.Error(), http.StatusBadRequest)
@@ -2976,10 +2976,18 @@ func (a *App) PermanentDeleteChannel(channel *model.Channel) *model.AppError {
 		return model.NewAppError("PermanentDeleteChannel", "app.post.permanent_delete_by_channel.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
+	if err := a.Srv().Store.PostRevision().PermanentDeleteByChannel(channel.Id); err != nil {
+		return model.NewAppError("PermanentDeleteChannel", "app.post_revision.permanent_delete_by_channel.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
 	if err := a.Srv().Store.Channel().PermanentDeleteMembersByChannel(channel.Id); err != nil {
 		return model.NewAppError("PermanentDeleteChannel", "app.channel.remove_member.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
+	if err := a.Srv().Store.ChannelBookmark().PermanentDeleteByChannel(channel.Id); err != nil {
+		return model.NewAppError("PermanentDeleteChannel", "app.channel_bookmark.permanent_delete_by_channel.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
 	if err := a.Srv().Store.Webhook().PermanentDeleteIncomingByChannel(channel.Id); err != nil {
 		return model.NewAppError("PermanentDeleteChannel", "app.webhooks.permanent_delete_incoming_by_channel.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
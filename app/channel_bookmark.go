@@ -0,0 +1,267 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+// channelBookmarkStoreError converts an error of the channel bookmark store to an app error.
+func channelBookmarkStoreError(where, id string, err error) *model.AppError {
+	var appErr *model.AppError
+	var nfErr *store.ErrNotFound
+	var invErr *store.ErrInvalidInput
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.As(err, &nfErr):
+		return model.NewAppError(where, "app.channel_bookmark.get.not_found.app_error", nil, nfErr.Error(), http.StatusNotFound)
+	case errors.As(err, &invErr):
+		return model.NewAppError(where, id, nil, invErr.Error(), http.StatusBadRequest)
+	default:
+		return model.NewAppError(where, id, nil, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetChannelBookmarks returns the bookmarks of the channel in their sort order. When since
+// is not zero, the bookmarks updated at or after that time are returned instead, the
+// deleted ones included.
+func (a *App) GetChannelBookmarks(channelID string, since int64) ([]*model.ChannelBookmark, *model.AppError) {
+	bookmarks, err := a.Srv().Store.ChannelBookmark().GetBookmarksForChannelSince(channelID, since)
+	if err != nil {
+		return nil, model.NewAppError("GetChannelBookmarks", "app.channel_bookmark.get_for_channel.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	a.populateChannelBookmarkFiles(bookmarks...)
+	return bookmarks, nil
+}
+
+func (a *App) GetChannelBookmark(bookmarkID string, includeDeleted bool) (*model.ChannelBookmark, *model.AppError) {
+	bookmark, err := a.Srv().Store.ChannelBookmark().Get(bookmarkID, includeDeleted)
+	if err != nil {
+		return nil, channelBookmarkStoreError("GetChannelBookmark", "app.channel_bookmark.get.app_error", err)
+	}
+
+	a.populateChannelBookmarkFiles(bookmark)
+	return bookmark, nil
+}
+
+// CreateChannelBookmark adds the bookmark after the other bookmarks of its channel. The
+// bookmarks synchronized from a remote cluster keep their sort order.
+func (a *App) CreateChannelBookmark(c *request.Context, bookmark *model.ChannelBookmark) (*model.ChannelBookmark, *model.AppError) {
+	if appErr := a.checkChannelBookmarkChannel("CreateChannelBookmark", bookmark.ChannelId); appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr := a.checkChannelBookmarkFile("CreateChannelBookmark", bookmark); appErr != nil {
+		return nil, appErr
+	}
+
+	existing, err := a.Srv().Store.ChannelBookmark().GetBookmarksForChannelSince(bookmark.ChannelId, 0)
+	if err != nil {
+		return nil, model.NewAppError("CreateChannelBookmark", "app.channel_bookmark.get_for_channel.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if len(existing) >= model.ChannelBookmarksPerChannelMax {
+		return nil, model.NewAppError("CreateChannelBookmark", "app.channel_bookmark.create.limit_reached.app_error", map[string]interface{}{"Max": model.ChannelBookmarksPerChannelMax}, "channel_id="+bookmark.ChannelId, http.StatusBadRequest)
+	}
+
+	if !bookmark.IsRemote() {
+		bookmark.SortOrder = int64(len(existing))
+	}
+
+	savedBookmark, err := a.Srv().Store.ChannelBookmark().Save(bookmark)
+	if err != nil {
+		return nil, channelBookmarkStoreError("CreateChannelBookmark", "app.channel_bookmark.save.app_error", err)
+	}
+
+	a.populateChannelBookmarkFiles(savedBookmark)
+	a.publishChannelBookmarkEvent(model.WebsocketEventChannelBookmarkCreated, savedBookmark.ChannelId, "bookmark", savedBookmark)
+
+	return savedBookmark, nil
+}
+
+// UpdateChannelBookmark saves the changes to the bookmark. The channel, owner and type of
+// a bookmark cannot change.
+func (a *App) UpdateChannelBookmark(c *request.Context, bookmark *model.ChannelBookmark) (*model.ChannelBookmark, *model.AppError) {
+	oldBookmark, err := a.Srv().Store.ChannelBookmark().Get(bookmark.Id, false)
+	if err != nil {
+		return nil, channelBookmarkStoreError("UpdateChannelBookmark", "app.channel_bookmark.get.app_error", err)
+	}
+
+	if appErr := a.checkChannelBookmarkChannel("UpdateChannelBookmark", oldBookmark.ChannelId); appErr != nil {
+		return nil, appErr
+	}
+
+	newBookmark := bookmark.Clone()
+	newBookmark.ChannelId = oldBookmark.ChannelId
+	newBookmark.OwnerId = oldBookmark.OwnerId
+	newBookmark.Type = oldBookmark.Type
+	newBookmark.CreateAt = oldBookmark.CreateAt
+	newBookmark.FileInfo = nil
+
+	if newBookmark.FileId != oldBookmark.FileId {
+		if appErr := a.checkChannelBookmarkFile("UpdateChannelBookmark", newBookmark); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	if err := a.Srv().Store.ChannelBookmark().Update(newBookmark); err != nil {
+		return nil, channelBookmarkStoreError("UpdateChannelBookmark", "app.channel_bookmark.update.app_error", err)
+	}
+
+	a.populateChannelBookmarkFiles(newBookmark)
+	a.publishChannelBookmarkEvent(model.WebsocketEventChannelBookmarkUpdated, newBookmark.ChannelId, "bookmark", newBookmark)
+
+	return newBookmark, nil
+}
+
+// DeleteChannelBookmark deletes the bookmark and returns it as deleted.
+func (a *App) DeleteChannelBookmark(bookmarkID string) (*model.ChannelBookmark, *model.AppError) {
+	bookmark, err := a.Srv().Store.ChannelBookmark().Get(bookmarkID, false)
+	if err != nil {
+		return nil, channelBookmarkStoreError("DeleteChannelBookmark", "app.channel_bookmark.get.app_error", err)
+	}
+
+	if appErr := a.checkChannelBookmarkChannel("DeleteChannelBookmark", bookmark.ChannelId); appErr != nil {
+		return nil, appErr
+	}
+
+	if err := a.Srv().Store.ChannelBookmark().Delete(bookmarkID); err != nil {
+		return nil, channelBookmarkStoreError("DeleteChannelBookmark", "app.channel_bookmark.delete.app_error", err)
+	}
+
+	bookmark, err = a.Srv().Store.ChannelBookmark().Get(bookmarkID, true)
+	if err != nil {
+		return nil, channelBookmarkStoreError("DeleteChannelBookmark", "app.channel_bookmark.get.app_error", err)
+	}
+
+	a.publishChannelBookmarkEvent(model.WebsocketEventChannelBookmarkDeleted, bookmark.ChannelId, "bookmark", bookmark)
+
+	return bookmark, nil
+}
+
+// UpdateChannelBookmarkSortOrder moves the bookmark to the given index among the bookmarks
+// of the channel, and returns the bookmarks of the channel in their new order.
+func (a *App) UpdateChannelBookmarkSortOrder(bookmarkID, channelID string, newIndex int64) ([]*model.ChannelBookmark, *model.AppError) {
+	if appErr := a.checkChannelBookmarkChannel("UpdateChannelBookmarkSortOrder", channelID); appErr != nil {
+		return nil, appErr
+	}
+
+	bookmarks, err := a.Srv().Store.ChannelBookmark().UpdateSortOrder(bookmarkID, channelID, newIndex)
+	if err != nil {
+		return nil, channelBookmarkStoreError("UpdateChannelBookmarkSortOrder", "app.channel_bookmark.update_sort_order.app_error", err)
+	}
+
+	a.populateChannelBookmarkFiles(bookmarks...)
+	a.publishChannelBookmarkEvent(model.WebsocketEventChannelBookmarkSorted, channelID, "bookmarks", bookmarks)
+
+	return bookmarks, nil
+}
+
+// checkChannelBookmarkChannel checks the bookmarks of the channel can be changed, which is
+// not the case once the channel is archived.
+func (a *App) checkChannelBookmarkChannel(where, channelID string) *model.AppError {
+	channel, appErr := a.GetChannel(channelID)
+	if appErr != nil {
+		return appErr
+	}
+
+	if channel.DeleteAt != 0 {
+		return model.NewAppError(where, "app.channel_bookmark.archived_channel.app_error", nil, "channel_id="+channelID, http.StatusForbidden)
+	}
+	return nil
+}
+
+// checkChannelBookmarkFile checks the file of a file bookmark exists and was uploaded by the
+// owner of the bookmark, so that a bookmark cannot expose the files of other users. The
+// bookmarks synchronized from a remote cluster were checked there, and their file may not
+// have been received yet.
+func (a *App) checkChannelBookmarkFile(where string, bookmark *model.ChannelBookmark) *model.AppError {
+	if bookmark.Type != model.ChannelBookmarkFile || bookmark.IsRemote() {
+		return nil
+	}
+
+	info, err := a.Srv().Store.FileInfo().Get(bookmark.FileId)
+	if err != nil || info.DeleteAt != 0 || info.CreatorId != bookmark.OwnerId {
+		return model.NewAppError(where, "app.channel_bookmark.invalid_file.app_error", nil, "file_id="+bookmark.FileId, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// populateChannelBookmarkFiles sets the info of the files of the file bookmarks.
+func (a *App) populateChannelBookmarkFiles(bookmarks ...*model.ChannelBookmark) {
+	var fileIDs []string
+	for _, bookmark := range bookmarks {
+		if bookmark.FileId != "" {
+			fileIDs = append(fileIDs, bookmark.FileId)
+		}
+	}
+	if len(fileIDs) == 0 {
+		return
+	}
+
+	infos, err := a.Srv().Store.FileInfo().GetByIds(fileIDs)
+	if err != nil {
+		mlog.Warn("Failed to get the files of the channel bookmarks", mlog.Err(err))
+		return
+	}
+
+	infosByID := make(map[string]*model.FileInfo, len(infos))
+	for _, info := range infos {
+		infosByID[info.Id] = info
+	}
+	for _, bookmark := range bookmarks {
+		bookmark.FileInfo = infosByID[bookmark.FileId]
+	}
+}
+
+// publishChannelBookmarkEvent sends the changed bookmarks to the members of the channel.
+func (a *App) publishChannelBookmarkEvent(event, channelID, key string, data interface{}) {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		mlog.Warn("Failed to encode channel bookmarks to JSON", mlog.String("channel_id", channelID), mlog.Err(err))
+		return
+	}
+
+	message := model.NewWebSocketEvent(event, "", channelID, "", nil)
+	message.Add(key, string(dataJSON))
+	a.Publish(message)
+}
+
+// SessionHasPermissionToReadFile tells whether the session can read the file: its creator
+// can, as can the members of the channel of the post or of the bookmarks it is attached to,
+// and the sessions allowed to read any channel.
+func (a *App) SessionHasPermissionToReadFile(session model.Session, info *model.FileInfo) bool {
+	if info.CreatorId == session.UserId {
+		return true
+	}
+
+	if info.PostId != "" {
+		return a.SessionHasPermissionToChannelByPost(session, info.PostId, model.PermissionReadChannel)
+	}
+
+	bookmarks, err := a.Srv().Store.ChannelBookmark().GetForFile(info.Id)
+	if err != nil {
+		mlog.Warn("Failed to get the channel bookmarks of the file", mlog.String("file_id", info.Id), mlog.Err(err))
+	}
+	for _, bookmark := range bookmarks {
+		// the owner of a bookmark can only share their own files.
+		if bookmark.OwnerId != info.CreatorId {
+			continue
+		}
+		if a.SessionHasPermissionToChannel(session, bookmark.ChannelId, model.PermissionReadChannel) {
+			return true
+		}
+	}
+
+	return a.SessionHasPermissionTo(session, model.PermissionReadChannel)
+}
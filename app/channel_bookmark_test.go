@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func createLinkBookmark(t *testing.T, th *TestHelper, channelID string) *model.ChannelBookmark {
+	bookmark, appErr := th.App.CreateChannelBookmark(th.Context, &model.ChannelBookmark{
+		ChannelId:   channelID,
+		OwnerId:     th.BasicUser.Id,
+		DisplayName: "Docs",
+		LinkUrl:     "https://example.com/docs",
+		Type:        model.ChannelBookmarkLink,
+	})
+	require.Nil(t, appErr)
+	return bookmark
+}
+
+func TestCreateChannelBookmark(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	first := createLinkBookmark(t, th, th.BasicChannel.Id)
+	second := createLinkBookmark(t, th, th.BasicChannel.Id)
+	assert.Equal(t, int64(0), first.SortOrder)
+	assert.Equal(t, int64(1), second.SortOrder)
+
+	t.Run("limit", func(t *testing.T) {
+		channel := th.CreateChannel(th.BasicTeam)
+		for i := 0; i < model.ChannelBookmarksPerChannelMax; i++ {
+			createLinkBookmark(t, th, channel.Id)
+		}
+
+		_, appErr := th.App.CreateChannelBookmark(th.Context, &model.ChannelBookmark{
+			ChannelId:   channel.Id,
+			OwnerId:     th.BasicUser.Id,
+			DisplayName: "Docs",
+			LinkUrl:     "https://example.com/docs",
+			Type:        model.ChannelBookmarkLink,
+		})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.channel_bookmark.create.limit_reached.app_error", appErr.Id)
+	})
+
+	t.Run("archived channel", func(t *testing.T) {
+		channel := th.CreateChannel(th.BasicTeam)
+		bookmark := createLinkBookmark(t, th, channel.Id)
+		require.Nil(t, th.App.DeleteChannel(th.Context, channel, th.BasicUser.Id))
+
+		_, appErr := th.App.DeleteChannelBookmark(bookmark.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	})
+}
+
+func TestSessionHasPermissionToReadFile(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	th.AddUserToChannel(th.BasicUser2, th.BasicChannel)
+
+	info, err := th.App.Srv().Store.FileInfo().Save(&model.FileInfo{
+		CreatorId: th.BasicUser.Id,
+		Path:      "path.txt",
+		Name:      "path.txt",
+	})
+	require.NoError(t, err)
+
+	session := model.Session{UserId: th.BasicUser2.Id, Roles: model.SystemUserRoleId}
+	assert.False(t, th.App.SessionHasPermissionToReadFile(session, info))
+
+	bookmark, appErr := th.App.CreateChannelBookmark(th.Context, &model.ChannelBookmark{
+		ChannelId:   th.BasicChannel.Id,
+		OwnerId:     th.BasicUser.Id,
+		DisplayName: "Handbook",
+		FileId:      info.Id,
+		Type:        model.ChannelBookmarkFile,
+	})
+	require.Nil(t, appErr)
+	assert.True(t, th.App.SessionHasPermissionToReadFile(session, info))
+
+	outsider := model.Session{UserId: th.CreateUser().Id, Roles: model.SystemUserRoleId}
+	assert.False(t, th.App.SessionHasPermissionToReadFile(outsider, info))
+
+	_, appErr = th.App.DeleteChannelBookmark(bookmark.Id)
+	require.Nil(t, appErr)
+	assert.False(t, th.App.SessionHasPermissionToReadFile(session, info))
+}
@@ -67,6 +67,10 @@ type Channels struct {
 	clientConfigHash     atomic.Value
 	limitedClientConfig  atomic.Value
 
+	// The keys signing the OpenID Connect ID tokens by rotation period, loaded on demand.
+	openIDSigningKeysMut sync.Mutex
+	openIDSigningKeys    map[int64]*openIDSigningKey
+
 	// cached counts that are used during notice condition validation
 	cachedPostCount   int64
 	cachedUserCount   int64
@@ -104,9 +108,10 @@ func init() {
 func NewChannels(s *Server, services map[ServiceKey]interface{}) (*Channels, error) {
 
 	ch := &Channels{
-		srv:           s,
-		imageProxy:    imageproxy.MakeImageProxy(s, s.httpService, s.Log),
-		uploadLockMap: map[string]bool{},
+		srv:               s,
+		imageProxy:        imageproxy.MakeImageProxy(s, s.httpService, s.Log),
+		uploadLockMap:     map[string]bool{},
+		openIDSigningKeys: map[int64]*openIDSigningKey{},
 	}
 
 	// To get another service:
@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func (a *App) checkSyncedDraftsEnabled(where string) *model.AppError {
+	if !*a.Config().ServiceSettings.AllowSyncedDrafts {
+		return model.NewAppError(where, "app.draft.feature_disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+// UpsertDraft saves the draft of the user in the channel or thread, replacing any
+// previous one, and syncs it to the other devices of the user.
+func (a *App) UpsertDraft(draft *model.Draft) (*model.Draft, *model.AppError) {
+	if appErr := a.checkSyncedDraftsEnabled("UpsertDraft"); appErr != nil {
+		return nil, appErr
+	}
+
+	if _, appErr := a.GetChannelMember(context.Background(), draft.ChannelId, draft.UserId); appErr != nil {
+		return nil, model.NewAppError("UpsertDraft", "app.draft.upsert.not_member.app_error", nil, appErr.Error(), http.StatusForbidden)
+	}
+
+	if draft.RootId != "" {
+		if _, appErr := a.GetSinglePost(draft.RootId); appErr != nil {
+			return nil, model.NewAppError("UpsertDraft", "app.draft.upsert.root_id.app_error", nil, appErr.Error(), http.StatusBadRequest)
+		}
+	}
+
+	draft.CreateAt = 0
+	savedDraft, err := a.Srv().Store.Draft().Upsert(draft)
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("UpsertDraft", "app.draft.upsert.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	event := model.WebsocketEventDraftUpdated
+	if savedDraft.CreateAt == savedDraft.UpdateAt {
+		event = model.WebsocketEventDraftCreated
+	}
+	a.publishDraftEvent(event, savedDraft)
+
+	return savedDraft, nil
+}
+
+// GetDraftsForUser returns the drafts of the user in the channels of the team, and in
+// the direct and group message channels.
+func (a *App) GetDraftsForUser(userID, teamID string) ([]*model.Draft, *model.AppError) {
+	if appErr := a.checkSyncedDraftsEnabled("GetDraftsForUser"); appErr != nil {
+		return nil, appErr
+	}
+
+	drafts, err := a.Srv().Store.Draft().GetDraftsForUser(userID, teamID)
+	if err != nil {
+		return nil, model.NewAppError("GetDraftsForUser", "app.draft.get_for_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return drafts, nil
+}
+
+// DeleteDraft deletes the draft of the user in the channel, or in the thread of the
+// channel if the root id isn't empty.
+func (a *App) DeleteDraft(userID, channelID, rootID string) *model.AppError {
+	if appErr := a.checkSyncedDraftsEnabled("DeleteDraft"); appErr != nil {
+		return appErr
+	}
+
+	draft, err := a.Srv().Store.Draft().Get(userID, channelID, rootID)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return model.NewAppError("DeleteDraft", "app.draft.get.not_found.app_error", nil, nfErr.Error(), http.StatusNotFound)
+		default:
+			return model.NewAppError("DeleteDraft", "app.draft.get.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	if err := a.Srv().Store.Draft().Delete(userID, channelID, rootID); err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return model.NewAppError("DeleteDraft", "app.draft.get.not_found.app_error", nil, nfErr.Error(), http.StatusNotFound)
+		default:
+			return model.NewAppError("DeleteDraft", "app.draft.delete.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	a.publishDraftEvent(model.WebsocketEventDraftDeleted, draft)
+
+	return nil
+}
+
+// publishDraftEvent sends the draft to the devices of its user.
+func (a *App) publishDraftEvent(event string, draft *model.Draft) {
+	draftJSON, err := json.Marshal(draft)
+	if err != nil {
+		mlog.Warn("Failed to encode draft to JSON", mlog.Err(err))
+		return
+	}
+
+	message := model.NewWebSocketEvent(event, "", draft.ChannelId, draft.UserId, nil)
+	message.Add("draft", string(draftJSON))
+	a.Publish(message)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestUpsertDraft(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	draft, appErr := th.App.UpsertDraft(&model.Draft{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "draft",
+	})
+	require.Nil(t, appErr)
+	assert.Equal(t, "draft", draft.Message)
+
+	t.Run("update", func(t *testing.T) {
+		updated, appErr := th.App.UpsertDraft(&model.Draft{
+			UserId:    th.BasicUser.Id,
+			ChannelId: th.BasicChannel.Id,
+			Message:   "updated",
+		})
+		require.Nil(t, appErr)
+		assert.Equal(t, draft.CreateAt, updated.CreateAt)
+
+		drafts, appErr := th.App.GetDraftsForUser(th.BasicUser.Id, th.BasicTeam.Id)
+		require.Nil(t, appErr)
+		require.Len(t, drafts, 1)
+		assert.Equal(t, "updated", drafts[0].Message)
+	})
+
+	t.Run("not a channel member", func(t *testing.T) {
+		_, appErr := th.App.UpsertDraft(&model.Draft{
+			UserId:    th.BasicUser2.Id,
+			ChannelId: th.CreatePrivateChannel(th.BasicTeam).Id,
+			Message:   "draft",
+		})
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	})
+
+	t.Run("missing root post", func(t *testing.T) {
+		_, appErr := th.App.UpsertDraft(&model.Draft{
+			UserId:    th.BasicUser.Id,
+			ChannelId: th.BasicChannel.Id,
+			RootId:    model.NewId(),
+			Message:   "draft",
+		})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.draft.upsert.root_id.app_error", appErr.Id)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.AllowSyncedDrafts = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.AllowSyncedDrafts = true })
+
+		_, appErr := th.App.UpsertDraft(&model.Draft{UserId: th.BasicUser.Id, ChannelId: th.BasicChannel.Id})
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotImplemented, appErr.StatusCode)
+	})
+}
+
+func TestDeleteDraft(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	_, appErr := th.App.UpsertDraft(&model.Draft{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		RootId:    th.BasicPost.Id,
+		Message:   "reply",
+	})
+	require.Nil(t, appErr)
+
+	appErr = th.App.DeleteDraft(th.BasicUser.Id, th.BasicChannel.Id, "")
+	require.NotNil(t, appErr)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+	require.Nil(t, th.App.DeleteDraft(th.BasicUser.Id, th.BasicChannel.Id, th.BasicPost.Id))
+
+	drafts, appErr := th.App.GetDraftsForUser(th.BasicUser.Id, th.BasicTeam.Id)
+	require.Nil(t, appErr)
+	assert.Empty(t, drafts)
+}
@@ -398,6 +398,38 @@ func (es *Service) SendPasswordResetEmail(email string, token *model.Token, loca
 	return true, nil
 }
 
+// SendMfaResetEmail sends the link with which a user who lost the device generating the
+// multi-factor authentication tokens resets the multi-factor authentication.
+func (es *Service) SendMfaResetEmail(email string, token *model.Token, locale, siteURL string) (bool, error) {
+	T := i18n.GetUserTranslations(locale)
+
+	link := fmt.Sprintf("%s/reset_mfa_complete?token=%s", siteURL, url.QueryEscape(token.Token))
+
+	subject := T("api.templates.mfa_reset_subject",
+		map[string]interface{}{"SiteName": es.config().TeamSettings.SiteName})
+
+	data := es.NewEmailTemplateData(locale)
+	data.Props["SiteURL"] = siteURL
+	data.Props["Title"] = T("api.templates.mfa_reset_body.title")
+	data.Props["SubTitle"] = T("api.templates.mfa_reset_body.subTitle")
+	data.Props["Info"] = T("api.templates.mfa_reset_body.info")
+	data.Props["ButtonURL"] = link
+	data.Props["Button"] = T("api.templates.mfa_reset_body.button")
+	data.Props["QuestionTitle"] = T("api.templates.questions_footer.title")
+	data.Props["QuestionInfo"] = T("api.templates.questions_footer.info")
+
+	body, err := es.templatesContainer.RenderToString("reset_body", data)
+	if err != nil {
+		return false, err
+	}
+
+	if err := es.sendMail(email, subject, body); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (es *Service) SendMfaChangeEmail(email string, activated bool, locale, siteURL string) error {
 	T := i18n.GetUserTranslations(locale)
 
@@ -314,6 +314,27 @@ func (_m *ServiceInterface) SendMfaChangeEmail(_a0 string, activated bool, local
 	return r0
 }
 
+// SendMfaResetEmail provides a mock function with given fields: _a0, token, locale, siteURL
+func (_m *ServiceInterface) SendMfaResetEmail(_a0 string, token *model.Token, locale string, siteURL string) (bool, error) {
+	ret := _m.Called(_a0, token, locale, siteURL)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, *model.Token, string, string) bool); ok {
+		r0 = rf(_a0, token, locale, siteURL)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *model.Token, string, string) error); ok {
+		r1 = rf(_a0, token, locale, siteURL)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SendNoCardPaymentFailedEmail provides a mock function with given fields: _a0, locale, siteURL
 func (_m *ServiceInterface) SendNoCardPaymentFailedEmail(_a0 string, locale string, siteURL string) error {
 	ret := _m.Called(_a0, locale, siteURL)
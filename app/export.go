@@ -68,29 +68,53 @@ func (a *App) BulkExport(writer io.Writer, outPath string, opts model.BulkExport
 		}
 	}
 
+	var scope *exportScope
+	if len(opts.TeamIds) > 0 || len(opts.ChannelIds) > 0 {
+		var appErr *model.AppError
+		if scope, appErr = a.buildExportScope(opts.TeamIds, opts.ChannelIds); appErr != nil {
+			return appErr
+		}
+	}
+
 	mlog.Info("Bulk export: exporting version")
 	if err := a.exportVersion(writer); err != nil {
 		return err
 	}
 
+	mlog.Info("Bulk export: exporting roles")
+	if err := a.exportAllRoles(writer); err != nil {
+		return err
+	}
+
+	mlog.Info("Bulk export: exporting schemes")
+	if err := a.exportAllSchemes(writer); err != nil {
+		return err
+	}
+
 	mlog.Info("Bulk export: exporting teams")
-	teamNames, err := a.exportAllTeams(writer)
+	teamNames, err := a.exportAllTeams(writer, scope)
 	if err != nil {
 		return err
 	}
 
 	mlog.Info("Bulk export: exporting channels")
-	if err = a.exportAllChannels(writer, teamNames); err != nil {
+	channelIds, err := a.exportAllChannels(writer, teamNames, scope)
+	if err != nil {
 		return err
 	}
 
 	mlog.Info("Bulk export: exporting users")
-	if err = a.exportAllUsers(writer); err != nil {
+	if err = a.exportAllUsers(writer, scope); err != nil {
+		return err
+	}
+
+	mlog.Info("Bulk export: exporting bots")
+	if err = a.exportAllBots(writer, scope); err != nil {
 		return err
 	}
 
 	mlog.Info("Bulk export: exporting posts")
-	attachments, err := a.exportAllPosts(writer, opts.IncludeAttachments)
+	attachments, err := a.exportAllPosts(writer, channelIds, opts.Since, opts.IncludeAttachments)
 	if err != nil {
 		return err
 	}
@@ -101,16 +125,51 @@ func (a *App) BulkExport(writer io.Writer, outPath string, opts model.BulkExport
 		return err
 	}
 
-	mlog.Info("Bulk export: exporting direct channels")
-	if err = a.exportAllDirectChannels(writer); err != nil {
+	// The direct and group messages don't belong to any team, so they are
+	// only part of the unscoped exports.
+	var directAttachments []model.AttachmentImportData
+	if scope == nil {
+		mlog.Info("Bulk export: exporting direct channels")
+		if err = a.exportAllDirectChannels(writer); err != nil {
+			return err
+		}
+
+		mlog.Info("Bulk export: exporting direct posts")
+		directAttachments, err = a.exportAllDirectPosts(writer, opts.Since, opts.IncludeAttachments)
+		if err != nil {
+			return err
+		}
+	}
+
+	mlog.Info("Bulk export: exporting slash commands")
+	if err = a.exportAllCommands(writer, teamNames); err != nil {
 		return err
 	}
 
-	mlog.Info("Bulk export: exporting direct posts")
-	directAttachments, err := a.exportAllDirectPosts(writer, opts.IncludeAttachments)
+	mlog.Info("Bulk export: exporting outgoing webhooks")
+	if err = a.exportAllOutgoingWebhooks(writer, teamNames, scope); err != nil {
+		return err
+	}
+
+	mlog.Info("Bulk export: exporting incoming webhooks")
+	if err = a.exportAllIncomingWebhooks(writer, teamNames, scope); err != nil {
+		return err
+	}
+
+	mlog.Info("Bulk export: exporting channel bookmarks")
+	bookmarkAttachments, err := a.exportAllChannelBookmarks(writer, teamNames, scope, opts.IncludeAttachments)
 	if err != nil {
 		return err
 	}
+	attachments = append(attachments, bookmarkAttachments...)
+
+	// The OAuth apps don't belong to any team either.
+	if scope == nil {
+		mlog.Info("Bulk export: exporting OAuth apps")
+		if err = a.exportAllOAuthApps(writer); err != nil {
+			return err
+		}
+	}
 
 	if opts.IncludeAttachments {
 		mlog.Info("Bulk export: exporting file attachments")
@@ -134,7 +193,58 @@ func (a *App) BulkExport(writer io.Writer, outPath string, opts model.BulkExport
 	return nil
 }
 
-func (a *App) exportWriteLine(writer io.Writer, line *LineImportData) *model.AppError {
+// exportScope holds the teams and channels a bulk export is restricted to.
+type exportScope struct {
+	// teamIds are all the exported teams, including the teams of the exported channels.
+	teamIds map[string]bool
+	// fullTeamIds are the teams whose channels are all exported.
+	fullTeamIds map[string]bool
+	channelIds  map[string]bool
+}
+
+func (a *App) buildExportScope(teamIds, channelIds []string) (*exportScope, *model.AppError) {
+	scope := &exportScope{
+		teamIds:     make(map[string]bool),
+		fullTeamIds: make(map[string]bool),
+		channelIds:  make(map[string]bool),
+	}
+	for _, teamId := range teamIds {
+		scope.teamIds[teamId] = true
+		scope.fullTeamIds[teamId] = true
+	}
+
+	if len(channelIds) > 0 {
+		channels, err := a.Srv().Store.Channel().GetChannelsByIds(channelIds, false)
+		if err != nil {
+			return nil, model.NewAppError("buildExportScope", "app.channel.get_channels_by_ids.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		for _, channel := range channels {
+			// Direct and group channels have no team to be exported with.
+			if channel.TeamId == "" {
+				continue
+			}
+			scope.teamIds[channel.TeamId] = true
+			scope.channelIds[channel.Id] = true
+		}
+		for _, channelId := range channelIds {
+			if !scope.channelIds[channelId] {
+				return nil, model.NewAppError("buildExportScope", "app.export.scope.channel.app_error", nil, "channel_id="+channelId, http.StatusBadRequest)
+			}
+		}
+	}
+
+	return scope, nil
+}
+
+func (s *exportScope) hasTeam(teamId string) bool {
+	return s == nil || s.teamIds[teamId]
+}
+
+func (s *exportScope) hasChannel(channel *model.Channel) bool {
+	return s == nil || s.fullTeamIds[channel.TeamId] || s.channelIds[channel.Id]
+}
+
+func (a *App) exportWriteLine(writer io.Writer, line *model.LineImportData) *model.AppError {
 	b, err := json.Marshal(line)
 	if err != nil {
 		return model.NewAppError("BulkExport", "app.export.export_write_line.json_marshall.error", nil, "err="+err.Error(), http.StatusBadRequest)
@@ -149,7 +259,7 @@ func (a *App) exportWriteLine(writer io.Writer, line *LineImportData) *model.App
 
 func (a *App) exportVersion(writer io.Writer) *model.AppError {
 	version := 1
-	versionLine := &LineImportData{
+	versionLine := &model.LineImportData{
 		Type:    "version",
 		Version: &version,
 	}
@@ -157,9 +267,78 @@ func (a *App) exportVersion(writer io.Writer) *model.AppError {
 	return a.exportWriteLine(writer, versionLine)
 }
 
-func (a *App) exportAllTeams(writer io.Writer) (map[string]bool, *model.AppError) {
+// exportAllRoles exports the custom roles, the built-in and the scheme roles being created by the
+// server and the schemes themselves.
+func (a *App) exportAllRoles(writer io.Writer) *model.AppError {
+	roles, err := a.Srv().Store.Role().GetAll()
+	if err != nil {
+		return model.NewAppError("exportAllRoles", "app.role.get_all.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	for _, role := range roles {
+		// Skip deleted, built-in and scheme roles.
+		if role.DeleteAt != 0 || role.BuiltIn || role.SchemeManaged {
+			continue
+		}
+
+		roleLine := ImportLineFromRole(role)
+		if err := a.exportWriteLine(writer, roleLine); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *App) exportAllSchemes(writer io.Writer) *model.AppError {
+	offset := 0
+	for {
+		schemes, err := a.Srv().Store.Scheme().GetAllPage("", offset, 100)
+		if err != nil {
+			return model.NewAppError("exportAllSchemes", "app.scheme.get.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		if len(schemes) == 0 {
+			break
+		}
+		offset += len(schemes)
+
+		for _, scheme := range schemes {
+			// Skip deleted.
+			if scheme.DeleteAt != 0 {
+				continue
+			}
+
+			roles, err := a.Srv().Store.Role().GetByNames([]string{
+				scheme.DefaultTeamAdminRole,
+				scheme.DefaultTeamUserRole,
+				scheme.DefaultTeamGuestRole,
+				scheme.DefaultChannelAdminRole,
+				scheme.DefaultChannelUserRole,
+				scheme.DefaultChannelGuestRole,
+			})
+			if err != nil {
+				return model.NewAppError("exportAllSchemes", "app.role.get_by_names.app_error", nil, err.Error(), http.StatusInternalServerError)
+			}
+			rolesByName := make(map[string]*model.Role, len(roles))
+			for _, role := range roles {
+				rolesByName[role.Name] = role
+			}
+
+			schemeLine := ImportLineFromScheme(scheme, rolesByName)
+			if err := a.exportWriteLine(writer, schemeLine); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// exportAllTeams returns the ids of the exported teams keyed by name.
+func (a *App) exportAllTeams(writer io.Writer, scope *exportScope) (map[string]string, *model.AppError) {
 	afterId := strings.Repeat("0", 26)
-	teamNames := make(map[string]bool)
+	teamNames := make(map[string]string)
 	for {
 		teams, err := a.Srv().Store.Team().GetAllForExportAfter(1000, afterId)
 		if err != nil {
@@ -177,7 +356,11 @@ func (a *App) exportAllTeams(writer io.Writer) (map[string]bool, *model.AppError
 			if team.DeleteAt != 0 {
 				continue
 			}
-			teamNames[team.Name] = true
+			// Skip teams out of the export scope.
+			if !scope.hasTeam(team.Id) {
+				continue
+			}
+			teamNames[team.Name] = team.Id
 
 			teamLine := ImportLineFromTeam(team)
 			if err := a.exportWriteLine(writer, teamLine); err != nil {
@@ -189,13 +372,20 @@ func (a *App) exportAllTeams(writer io.Writer) (map[string]bool, *model.AppError
 	return teamNames, nil
 }
 
-func (a *App) exportAllChannels(writer io.Writer, teamNames map[string]bool) *model.AppError {
+// exportAllChannels returns the ids of the exported channels when the export is scoped,
+// and nil otherwise.
+func (a *App) exportAllChannels(writer io.Writer, teamNames map[string]string, scope *exportScope) ([]string, *model.AppError) {
+	var channelIds []string
+	if scope != nil {
+		channelIds = []string{}
+	}
+
 	afterId := strings.Repeat("0", 26)
 	for {
 		channels, err := a.Srv().Store.Channel().GetAllChannelsForExportAfter(1000, afterId)
 
 		if err != nil {
-			return model.NewAppError("exportAllChannels", "app.channel.get_all.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return nil, model.NewAppError("exportAllChannels", "app.channel.get_all.app_error", nil, err.Error(), http.StatusInternalServerError)
 		}
 
 		if len(channels) == 0 {
@@ -210,21 +400,28 @@ func (a *App) exportAllChannels(writer io.Writer, teamNames map[string]bool) *mo
 				continue
 			}
 			// Skip channels on deleted teams.
-			if ok := teamNames[channel.TeamName]; !ok {
+			if _, ok := teamNames[channel.TeamName]; !ok {
+				continue
+			}
+			// Skip channels out of the export scope.
+			if !scope.hasChannel(&channel.Channel) {
 				continue
 			}
 
 			channelLine := ImportLineFromChannel(channel)
 			if err := a.exportWriteLine(writer, channelLine); err != nil {
-				return err
+				return nil, err
+			}
+			if scope != nil {
+				channelIds = append(channelIds, channel.Id)
 			}
 		}
 	}
 
-	return nil
+	return channelIds, nil
 }
 
-func (a *App) exportAllUsers(writer io.Writer) *model.AppError {
+func (a *App) exportAllUsers(writer io.Writer, scope *exportScope) *model.AppError {
 	afterId := strings.Repeat("0", 26)
 	for {
 		users, err := a.Srv().Store.User().GetAllAfter(1000, afterId)
@@ -240,6 +437,22 @@ func (a *App) exportAllUsers(writer io.Writer) *model.AppError {
 		for _, user := range users {
 			afterId = user.Id
 
+			// Skip bots, they are exported apart with their owner.
+			if user.IsBot {
+				continue
+			}
+
+			// Skip users who never were members of the exported teams.
+			if scope != nil {
+				inScope, err := a.isUserInExportScope(user.Id, scope)
+				if err != nil {
+					return err
+				}
+				if !inScope {
+					continue
+				}
+			}
+
 			// Gathering here the exportable preferences to pass them on to ImportLineFromUser
 			exportedPrefs := make(map[string]*string)
 			allPrefs, err := a.GetPreferencesForUser(user.Id)
@@ -283,7 +496,7 @@ func (a *App) exportAllUsers(writer io.Writer) *model.AppError {
 			userLine.User.NotifyProps = a.buildUserNotifyProps(user.NotifyProps)
 
 			// Do the Team Memberships.
-			members, err := a.buildUserTeamAndChannelMemberships(user.Id)
+			members, err := a.buildUserTeamAndChannelMemberships(user.Id, scope)
 			if err != nil {
 				return err
 			}
@@ -299,8 +512,24 @@ func (a *App) exportAllUsers(writer io.Writer) *model.AppError {
 	return nil
 }
 
-func (a *App) buildUserTeamAndChannelMemberships(userID string) (*[]UserTeamImportData, *model.AppError) {
-	var memberships []UserTeamImportData
+// isUserInExportScope tells whether the user is, or was, a member of one of the exported teams,
+// so that the posts they left in the exported channels can be imported.
+func (a *App) isUserInExportScope(userID string, scope *exportScope) (bool, *model.AppError) {
+	members, err := a.Srv().Store.Team().GetTeamMembersForExport(userID)
+	if err != nil {
+		return false, model.NewAppError("isUserInExportScope", "app.team.get_members.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	for _, member := range members {
+		if scope.hasTeam(member.TeamId) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (a *App) buildUserTeamAndChannelMemberships(userID string, scope *exportScope) (*[]model.UserTeamImportData, *model.AppError) {
+	var memberships []model.UserTeamImportData
 
 	members, err := a.Srv().Store.Team().GetTeamMembersForExport(userID)
 
@@ -313,11 +542,15 @@ func (a *App) buildUserTeamAndChannelMemberships(userID string) (*[]UserTeamImpo
 		if member.DeleteAt != 0 {
 			continue
 		}
+		// Skip teams out of the export scope.
+		if !scope.hasTeam(member.TeamId) {
+			continue
+		}
 
 		memberData := ImportUserTeamDataFromTeamMember(member)
 
 		// Do the Channel Memberships.
-		channelMembers, err := a.buildUserChannelMemberships(userID, member.TeamId)
+		channelMembers, err := a.buildUserChannelMemberships(userID, member.TeamId, scope)
 		if err != nil {
 			return nil, err
 		}
@@ -330,14 +563,22 @@ func (a *App) buildUserTeamAndChannelMemberships(userID string) (*[]UserTeamImpo
 
 		memberData.Channels = channelMembers
 
+		categories, err := a.buildUserSidebarCategories(userID, member.TeamId, scope)
+		if err != nil {
+			return nil, err
+		}
+		if len(categories) > 0 {
+			memberData.Categories = &categories
+		}
+
 		memberships = append(memberships, *memberData)
 	}
 
 	return &memberships, nil
 }
 
-func (a *App) buildUserChannelMemberships(userID string, teamID string) (*[]UserChannelImportData, *model.AppError) {
-	var memberships []UserChannelImportData
+func (a *App) buildUserChannelMemberships(userID string, teamID string, scope *exportScope) (*[]model.UserChannelImportData, *model.AppError) {
+	var memberships []model.UserChannelImportData
 
 	members, nErr := a.Srv().Store.Channel().GetChannelMembersForExport(userID, teamID)
 	if nErr != nil {
@@ -351,12 +592,59 @@ func (a *App) buildUserChannelMemberships(userID string, teamID string) (*[]User
 	}
 
 	for _, member := range members {
+		// Skip channels out of the export scope.
+		if !scope.hasChannel(&model.Channel{Id: member.ChannelId, TeamId: teamID}) {
+			continue
+		}
 		memberships = append(memberships, *ImportUserChannelDataFromChannelMemberAndPreferences(member, &preferences))
 	}
 	return &memberships, nil
 }
 
-func (a *App) buildUserNotifyProps(notifyProps model.StringMap) *UserNotifyPropsImportData {
+// buildUserSidebarCategories returns the custom sidebar categories of the user, with the channels
+// out of the export scope left out.
+func (a *App) buildUserSidebarCategories(userID string, teamID string, scope *exportScope) ([]model.SidebarCategoryImportData, *model.AppError) {
+	categories, err := a.Srv().Store.Channel().GetSidebarCategories(userID, teamID)
+	if err != nil {
+		return nil, model.NewAppError("buildUserSidebarCategories", "app.channel.sidebar_categories.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	customCategories := []*model.SidebarCategoryWithChannels{}
+	for _, category := range categories.Categories {
+		if category.Type == model.SidebarCategoryCustom {
+			customCategories = append(customCategories, category)
+		}
+	}
+	if len(customCategories) == 0 {
+		return nil, nil
+	}
+
+	members, err := a.Srv().Store.Channel().GetChannelMembersForExport(userID, teamID)
+	if err != nil {
+		return nil, model.NewAppError("buildUserSidebarCategories", "app.channel.get_members.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	channelNames := make(map[string]string, len(members))
+	for _, member := range members {
+		if scope.hasChannel(&model.Channel{Id: member.ChannelId, TeamId: teamID}) {
+			channelNames[member.ChannelId] = member.ChannelName
+		}
+	}
+
+	var result []model.SidebarCategoryImportData
+	for _, category := range customCategories {
+		names := []string{}
+		for _, channelId := range category.Channels {
+			if name, ok := channelNames[channelId]; ok {
+				names = append(names, name)
+			}
+		}
+		result = append(result, *ImportSidebarCategoryDataFromCategory(category, names))
+	}
+
+	return result, nil
+}
+
+func (a *App) buildUserNotifyProps(notifyProps model.StringMap) *model.UserNotifyPropsImportData {
 
 	getProp := func(key string) *string {
 		if v, ok := notifyProps[key]; ok {
@@ -365,7 +653,7 @@ func (a *App) buildUserNotifyProps(notifyProps model.StringMap) *UserNotifyProps
 		return nil
 	}
 
-	return &UserNotifyPropsImportData{
+	return &model.UserNotifyPropsImportData{
 		Desktop:          getProp(model.DesktopNotifyProp),
 		DesktopSound:     getProp(model.DesktopSoundNotifyProp),
 		Email:            getProp(model.EmailNotifyProp),
@@ -377,12 +665,62 @@ func (a *App) buildUserNotifyProps(notifyProps model.StringMap) *UserNotifyProps
 	}
 }
 
-func (a *App) exportAllPosts(writer io.Writer, withAttachments bool) ([]AttachmentImportData, *model.AppError) {
-	var attachments []AttachmentImportData
+// exportAllPosts exports the threads of the given channels, or of all of them if channelIds is nil,
+// which were created or edited at or after since.
+func (a *App) exportAllBots(writer io.Writer, scope *exportScope) *model.AppError {
+	page := 0
+	for {
+		bots, err := a.Srv().Store.Bot().GetAll(&model.BotGetOptions{Page: page, PerPage: 1000, IncludeDeleted: true})
+		if err != nil {
+			return model.NewAppError("exportAllBots", "app.bot.getbots.internal_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		if len(bots) == 0 {
+			break
+		}
+		page++
+
+		for _, bot := range bots {
+			// Skip bots which never were members of the exported teams.
+			if scope != nil {
+				inScope, err := a.isUserInExportScope(bot.UserId, scope)
+				if err != nil {
+					return err
+				}
+				if !inScope {
+					continue
+				}
+			}
+
+			// Bots created by plugins are owned by the plugin id.
+			owner := bot.OwnerId
+			if user, err := a.Srv().Store.User().Get(context.Background(), bot.OwnerId); err == nil {
+				owner = user.Username
+			}
+
+			botLine := ImportLineFromBot(bot, owner)
+
+			members, err := a.buildUserTeamAndChannelMemberships(bot.UserId, scope)
+			if err != nil {
+				return err
+			}
+			botLine.Bot.Teams = members
+
+			if err := a.exportWriteLine(writer, botLine); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *App) exportAllPosts(writer io.Writer, channelIds []string, since int64, withAttachments bool) ([]model.AttachmentImportData, *model.AppError) {
+	var attachments []model.AttachmentImportData
 	afterId := strings.Repeat("0", 26)
 
 	for {
-		posts, nErr := a.Srv().Store.Post().GetParentsForExportAfter(1000, afterId)
+		posts, nErr := a.Srv().Store.Post().GetParentsForExportAfter(1000, afterId, channelIds, since)
 		if nErr != nil {
 			return nil, model.NewAppError("exportAllPosts", "app.post.get_posts.app_error", nil, nErr.Error(), http.StatusInternalServerError)
 		}
@@ -411,7 +749,7 @@ func (a *App) exportAllPosts(writer io.Writer, withAttachments bool) ([]Attachme
 			}
 
 			postLine.Post.Replies = &replies
-			postLine.Post.Reactions = &[]ReactionImportData{}
+			postLine.Post.Reactions = &[]model.ReactionImportData{}
 			if post.HasReactions {
 				postLine.Post.Reactions, err = a.BuildPostReactions(post.Id)
 				if err != nil {
@@ -419,6 +757,20 @@ func (a *App) exportAllPosts(writer io.Writer, withAttachments bool) ([]Attachme
 				}
 			}
 
+			if post.EditAt != 0 {
+				postLine.Post.EditHistory, err = a.buildPostEditHistory(post.Id)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if post.Type == model.PostTypePoll {
+				postLine.Post.PollVotes, err = a.buildPollVotes(post.Id)
+				if err != nil {
+					return nil, err
+				}
+			}
+
 			if len(post.FileIds) > 0 {
 				postAttachments, err := a.buildPostAttachments(post.Id)
 				if err != nil {
@@ -438,9 +790,9 @@ func (a *App) exportAllPosts(writer io.Writer, withAttachments bool) ([]Attachme
 	}
 }
 
-func (a *App) buildPostReplies(postID string, withAttachments bool) ([]ReplyImportData, []AttachmentImportData, *model.AppError) {
-	var replies []ReplyImportData
-	var attachments []AttachmentImportData
+func (a *App) buildPostReplies(postID string, withAttachments bool) ([]model.ReplyImportData, []model.AttachmentImportData, *model.AppError) {
+	var replies []model.ReplyImportData
+	var attachments []model.AttachmentImportData
 
 	replyPosts, nErr := a.Srv().Store.Post().GetRepliesForExport(postID)
 	if nErr != nil {
@@ -456,6 +808,20 @@ func (a *App) buildPostReplies(postID string, withAttachments bool) ([]ReplyImpo
 				return nil, nil, appErr
 			}
 		}
+		if reply.EditAt != 0 {
+			var appErr *model.AppError
+			replyImportObject.EditHistory, appErr = a.buildPostEditHistory(reply.Id)
+			if appErr != nil {
+				return nil, nil, appErr
+			}
+		}
+		if reply.Type == model.PostTypePoll {
+			var appErr *model.AppError
+			replyImportObject.PollVotes, appErr = a.buildPollVotes(reply.Id)
+			if appErr != nil {
+				return nil, nil, appErr
+			}
+		}
 		if len(reply.FileIds) > 0 {
 			postAttachments, appErr := a.buildPostAttachments(reply.Id)
 			if appErr != nil {
@@ -473,8 +839,8 @@ func (a *App) buildPostReplies(postID string, withAttachments bool) ([]ReplyImpo
 	return replies, attachments, nil
 }
 
-func (a *App) BuildPostReactions(postID string) (*[]ReactionImportData, *model.AppError) {
-	var reactionsOfPost []ReactionImportData
+func (a *App) BuildPostReactions(postID string) (*[]model.ReactionImportData, *model.AppError) {
+	var reactionsOfPost []model.ReactionImportData
 
 	reactions, nErr := a.Srv().Store.Reaction().GetForPost(postID, true)
 	if nErr != nil {
@@ -498,15 +864,56 @@ func (a *App) BuildPostReactions(postID string) (*[]ReactionImportData, *model.A
 
 }
 
-func (a *App) buildPostAttachments(postID string) ([]AttachmentImportData, *model.AppError) {
+// buildPostEditHistory returns the revisions of the post, oldest first, or nil if it
+// has none.
+func (a *App) buildPostEditHistory(postID string) (*[]model.PostRevisionImportData, *model.AppError) {
+	revisions, err := a.Srv().Store.PostRevision().GetForPost(postID)
+	if err != nil {
+		return nil, model.NewAppError("buildPostEditHistory", "app.post_revision.get_for_post.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+
+	usernames := exportUsernames{}
+	history := make([]model.PostRevisionImportData, 0, len(revisions))
+	for i := len(revisions) - 1; i >= 0; i-- {
+		var editor string
+		if revisions[i].EditorId != "" {
+			editor, _ = a.exportUsername(usernames, revisions[i].EditorId)
+		}
+		history = append(history, *ImportPostRevisionFromRevision(revisions[i], editor))
+	}
+	return &history, nil
+}
+
+// buildPollVotes returns the votes for the poll of the post, or nil if it has none.
+func (a *App) buildPollVotes(postID string) (*[]model.PollVoteImportData, *model.AppError) {
+	votes, err := a.Srv().Store.PollVote().GetForPost(postID)
+	if err != nil {
+		return nil, model.NewAppError("buildPollVotes", "app.poll.get_votes.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	if len(votes) == 0 {
+		return nil, nil
+	}
+
+	usernames := exportUsernames{}
+	for _, vote := range votes {
+		a.exportUsername(usernames, vote.UserId)
+	}
+	data := ImportPollVotesFromVotes(votes, usernames)
+	return &data, nil
+}
+
+func (a *App) buildPostAttachments(postID string) ([]model.AttachmentImportData, *model.AppError) {
 	infos, nErr := a.Srv().Store.FileInfo().GetForPost(postID, false, false, false)
 	if nErr != nil {
 		return nil, model.NewAppError("buildPostAttachments", "app.file_info.get_for_post.app_error", nil, nErr.Error(), http.StatusInternalServerError)
 	}
 
-	attachments := make([]AttachmentImportData, 0, len(infos))
+	attachments := make([]model.AttachmentImportData, 0, len(infos))
 	for _, info := range infos {
-		attachments = append(attachments, AttachmentImportData{Path: &info.Path})
+		attachments = append(attachments, model.AttachmentImportData{Path: &info.Path})
 	}
 
 	return attachments, nil
@@ -628,11 +1035,11 @@ func (a *App) exportAllDirectChannels(writer io.Writer) *model.AppError {
 	return nil
 }
 
-func (a *App) exportAllDirectPosts(writer io.Writer, withAttachments bool) ([]AttachmentImportData, *model.AppError) {
-	var attachments []AttachmentImportData
+func (a *App) exportAllDirectPosts(writer io.Writer, since int64, withAttachments bool) ([]model.AttachmentImportData, *model.AppError) {
+	var attachments []model.AttachmentImportData
 	afterId := strings.Repeat("0", 26)
 	for {
-		posts, err := a.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, afterId)
+		posts, err := a.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, afterId, since)
 		if err != nil {
 			return nil, model.NewAppError("exportAllDirectPosts", "app.post.get_direct_posts.app_error", nil, err.Error(), http.StatusInternalServerError)
 		}
@@ -650,7 +1057,7 @@ func (a *App) exportAllDirectPosts(writer io.Writer, withAttachments bool) ([]At
 			}
 
 			// Handle attachments.
-			var postAttachments []AttachmentImportData
+			var postAttachments []model.AttachmentImportData
 			var err *model.AppError
 			if len(post.FileIds) > 0 {
 				postAttachments, err = a.buildPostAttachments(post.Id)
@@ -675,6 +1082,18 @@ func (a *App) exportAllDirectPosts(writer io.Writer, withAttachments bool) ([]At
 
 			postLine := ImportLineForDirectPost(post)
 			postLine.DirectPost.Replies = &replies
+			if post.EditAt != 0 {
+				postLine.DirectPost.EditHistory, err = a.buildPostEditHistory(post.Id)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if post.Type == model.PostTypePoll {
+				postLine.DirectPost.PollVotes, err = a.buildPollVotes(post.Id)
+				if err != nil {
+					return nil, err
+				}
+			}
 			if len(postAttachments) > 0 {
 				postLine.DirectPost.Attachments = &postAttachments
 			}
@@ -686,6 +1105,284 @@ func (a *App) exportAllDirectPosts(writer io.Writer, withAttachments bool) ([]At
 	return attachments, nil
 }
 
+// exportUsernames resolves the ids of the creators of the integrations to usernames.
+type exportUsernames map[string]string
+
+func (a *App) exportUsername(usernames exportUsernames, userID string) (string, bool) {
+	username, ok := usernames[userID]
+	if !ok {
+		user, err := a.Srv().Store.User().Get(context.Background(), userID)
+		if err == nil {
+			username = user.Username
+		}
+		usernames[userID] = username
+	}
+	return username, username != ""
+}
+
+func (a *App) exportAllCommands(writer io.Writer, teamNames map[string]string) *model.AppError {
+	usernames := exportUsernames{}
+	for teamName, teamId := range teamNames {
+		commands, err := a.Srv().Store.Command().GetByTeam(teamId)
+		if err != nil {
+			return model.NewAppError("exportAllCommands", "app.command.listallcommands.internal_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		for _, command := range commands {
+			// Skip deleted, and the commands registered by plugins.
+			if command.DeleteAt != 0 || command.PluginId != "" {
+				continue
+			}
+
+			creator, ok := a.exportUsername(usernames, command.CreatorId)
+			if !ok {
+				mlog.Warn("Bulk export: skipping slash command with unknown creator", mlog.String("command_id", command.Id))
+				continue
+			}
+
+			commandLine := ImportLineFromCommand(command, teamName, creator)
+			if err := a.exportWriteLine(writer, commandLine); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// exportChannelNames returns the names of the channels of the exported teams which are in the
+// export scope, keyed by id.
+func (a *App) exportChannelNames(channelIds []string, scope *exportScope) (map[string]string, *model.AppError) {
+	channelNames := make(map[string]string)
+	if len(channelIds) == 0 {
+		return channelNames, nil
+	}
+
+	channels, err := a.Srv().Store.Channel().GetChannelsByIds(channelIds, true)
+	if err != nil {
+		return nil, model.NewAppError("exportChannelNames", "app.channel.get_channels_by_ids.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	for _, channel := range channels {
+		if channel.DeleteAt == 0 && scope.hasChannel(channel) {
+			channelNames[channel.Id] = channel.Name
+		}
+	}
+	return channelNames, nil
+}
+
+func (a *App) exportAllOutgoingWebhooks(writer io.Writer, teamNames map[string]string, scope *exportScope) *model.AppError {
+	teamIds := make(map[string]string, len(teamNames))
+	for name, id := range teamNames {
+		teamIds[id] = name
+	}
+	usernames := exportUsernames{}
+
+	offset := 0
+	for {
+		hooks, err := a.Srv().Store.Webhook().GetOutgoingList(offset, 1000)
+		if err != nil {
+			return model.NewAppError("exportAllOutgoingWebhooks", "app.webhooks.get_outgoing_list.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		if len(hooks) == 0 {
+			break
+		}
+		offset += len(hooks)
+
+		channelIds := []string{}
+		for _, hook := range hooks {
+			if hook.ChannelId != "" {
+				channelIds = append(channelIds, hook.ChannelId)
+			}
+		}
+		channelNames, appErr := a.exportChannelNames(channelIds, scope)
+		if appErr != nil {
+			return appErr
+		}
+
+		for _, hook := range hooks {
+			teamName, ok := teamIds[hook.TeamId]
+			if !ok {
+				continue
+			}
+
+			// The webhooks triggered in any channel of the team are only
+			// exported along with the whole team.
+			var channelName string
+			if hook.ChannelId != "" {
+				if channelName, ok = channelNames[hook.ChannelId]; !ok {
+					continue
+				}
+			} else if scope != nil && !scope.fullTeamIds[hook.TeamId] {
+				continue
+			}
+
+			creator, ok := a.exportUsername(usernames, hook.CreatorId)
+			if !ok {
+				mlog.Warn("Bulk export: skipping outgoing webhook with unknown creator", mlog.String("hook_id", hook.Id))
+				continue
+			}
+
+			hookLine := ImportLineFromOutgoingWebhook(hook, teamName, channelName, creator)
+			if err := a.exportWriteLine(writer, hookLine); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *App) exportAllIncomingWebhooks(writer io.Writer, teamNames map[string]string, scope *exportScope) *model.AppError {
+	teamIds := make(map[string]string, len(teamNames))
+	for name, id := range teamNames {
+		teamIds[id] = name
+	}
+	usernames := exportUsernames{}
+
+	offset := 0
+	for {
+		hooks, err := a.Srv().Store.Webhook().GetIncomingList(offset, 1000)
+		if err != nil {
+			return model.NewAppError("exportAllIncomingWebhooks", "app.webhooks.get_incoming_list.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		if len(hooks) == 0 {
+			break
+		}
+		offset += len(hooks)
+
+		channelIds := make([]string, 0, len(hooks))
+		for _, hook := range hooks {
+			channelIds = append(channelIds, hook.ChannelId)
+		}
+		channelNames, appErr := a.exportChannelNames(channelIds, scope)
+		if appErr != nil {
+			return appErr
+		}
+
+		for _, hook := range hooks {
+			teamName, ok := teamIds[hook.TeamId]
+			if !ok {
+				continue
+			}
+
+			channelName, ok := channelNames[hook.ChannelId]
+			if !ok {
+				continue
+			}
+
+			creator, ok := a.exportUsername(usernames, hook.UserId)
+			if !ok {
+				mlog.Warn("Bulk export: skipping incoming webhook with unknown creator", mlog.String("hook_id", hook.Id))
+				continue
+			}
+
+			hookLine := ImportLineFromIncomingWebhook(hook, teamName, channelName, creator)
+			if err := a.exportWriteLine(writer, hookLine); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *App) exportAllChannelBookmarks(writer io.Writer, teamNames map[string]string, scope *exportScope, withAttachments bool) ([]model.AttachmentImportData, *model.AppError) {
+	var attachments []model.AttachmentImportData
+	usernames := exportUsernames{}
+
+	afterId := strings.Repeat("0", 26)
+	for {
+		channels, err := a.Srv().Store.Channel().GetAllChannelsForExportAfter(1000, afterId)
+		if err != nil {
+			return nil, model.NewAppError("exportAllChannelBookmarks", "app.channel.get_all.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		if len(channels) == 0 {
+			return attachments, nil
+		}
+
+		for _, channel := range channels {
+			afterId = channel.Id
+
+			if channel.DeleteAt != 0 {
+				continue
+			}
+			if _, ok := teamNames[channel.TeamName]; !ok {
+				continue
+			}
+			if !scope.hasChannel(&channel.Channel) {
+				continue
+			}
+
+			bookmarks, err := a.Srv().Store.ChannelBookmark().GetBookmarksForChannelSince(channel.Id, 0)
+			if err != nil {
+				return nil, model.NewAppError("exportAllChannelBookmarks", "app.channel_bookmark.get_for_channel.app_error", nil, err.Error(), http.StatusInternalServerError)
+			}
+			a.populateChannelBookmarkFiles(bookmarks...)
+
+			for _, bookmark := range bookmarks {
+				owner, ok := a.exportUsername(usernames, bookmark.OwnerId)
+				if !ok {
+					mlog.Warn("Bulk export: skipping channel bookmark with unknown owner", mlog.String("bookmark_id", bookmark.Id))
+					continue
+				}
+
+				var file *model.AttachmentImportData
+				if bookmark.Type == model.ChannelBookmarkFile {
+					if bookmark.FileInfo == nil {
+						mlog.Warn("Bulk export: skipping channel bookmark with unknown file", mlog.String("bookmark_id", bookmark.Id))
+						continue
+					}
+					file = &model.AttachmentImportData{Path: &bookmark.FileInfo.Path}
+					if withAttachments {
+						attachments = append(attachments, *file)
+					}
+				}
+
+				bookmarkLine := ImportLineFromChannelBookmark(bookmark, channel.TeamName, channel.Name, owner, file)
+				if err := a.exportWriteLine(writer, bookmarkLine); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+func (a *App) exportAllOAuthApps(writer io.Writer) *model.AppError {
+	usernames := exportUsernames{}
+
+	offset := 0
+	for {
+		apps, err := a.Srv().Store.OAuth().GetApps(offset, 1000)
+		if err != nil {
+			return model.NewAppError("exportAllOAuthApps", "app.oauth.get_apps.find.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		if len(apps) == 0 {
+			break
+		}
+		offset += len(apps)
+
+		for _, app := range apps {
+			creator, ok := a.exportUsername(usernames, app.CreatorId)
+			if !ok {
+				mlog.Warn("Bulk export: skipping OAuth app with unknown creator", mlog.String("app_id", app.Id))
+				continue
+			}
+
+			appLine := ImportLineFromOAuthApp(app, creator)
+			if err := a.exportWriteLine(writer, appLine); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (a *App) exportFile(outPath, filePath string, zipWr *zip.Writer) *model.AppError {
 	var wr io.Writer
 	var err error
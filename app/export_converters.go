@@ -9,10 +9,10 @@ import (
 	"github.com/cjdelisle/matterfoss-server/v6/model"
 )
 
-func ImportLineFromTeam(team *model.TeamForExport) *LineImportData {
-	return &LineImportData{
+func ImportLineFromTeam(team *model.TeamForExport) *model.LineImportData {
+	return &model.LineImportData{
 		Type: "team",
-		Team: &TeamImportData{
+		Team: &model.TeamImportData{
 			Name:            &team.Name,
 			DisplayName:     &team.DisplayName,
 			Type:            &team.Type,
@@ -23,10 +23,10 @@ func ImportLineFromTeam(team *model.TeamForExport) *LineImportData {
 	}
 }
 
-func ImportLineFromChannel(channel *model.ChannelForExport) *LineImportData {
-	return &LineImportData{
+func ImportLineFromChannel(channel *model.ChannelForExport) *model.LineImportData {
+	return &model.LineImportData{
 		Type: "channel",
-		Channel: &ChannelImportData{
+		Channel: &model.ChannelImportData{
 			Team:        &channel.TeamName,
 			Name:        &channel.Name,
 			DisplayName: &channel.DisplayName,
@@ -38,30 +38,30 @@ func ImportLineFromChannel(channel *model.ChannelForExport) *LineImportData {
 	}
 }
 
-func ImportLineFromDirectChannel(channel *model.DirectChannelForExport) *LineImportData {
+func ImportLineFromDirectChannel(channel *model.DirectChannelForExport) *model.LineImportData {
 	channelMembers := *channel.Members
 	if len(channelMembers) == 1 {
 		channelMembers = []string{channelMembers[0], channelMembers[0]}
 	}
-	return &LineImportData{
+	return &model.LineImportData{
 		Type: "direct_channel",
-		DirectChannel: &DirectChannelImportData{
+		DirectChannel: &model.DirectChannelImportData{
 			Header:  &channel.Header,
 			Members: &channelMembers,
 		},
 	}
 }
 
-func ImportLineFromUser(user *model.User, exportedPrefs map[string]*string) *LineImportData {
+func ImportLineFromUser(user *model.User, exportedPrefs map[string]*string) *model.LineImportData {
 	// Bulk Importer doesn't accept "empty string" for AuthService.
 	var authService *string
 	if user.AuthService != "" {
 		authService = &user.AuthService
 	}
 
-	return &LineImportData{
+	return &model.LineImportData{
 		Type: "user",
-		User: &UserImportData{
+		User: &model.UserImportData{
 			Username:           &user.Username,
 			Email:              &user.Email,
 			AuthService:        authService,
@@ -87,7 +87,7 @@ func ImportLineFromUser(user *model.User, exportedPrefs map[string]*string) *Lin
 	}
 }
 
-func ImportUserTeamDataFromTeamMember(member *model.TeamMemberForExport) *UserTeamImportData {
+func ImportUserTeamDataFromTeamMember(member *model.TeamMemberForExport) *model.UserTeamImportData {
 	rolesList := strings.Fields(member.Roles)
 	if member.SchemeAdmin {
 		rolesList = append(rolesList, model.TeamAdminRoleId)
@@ -99,13 +99,13 @@ func ImportUserTeamDataFromTeamMember(member *model.TeamMemberForExport) *UserTe
 		rolesList = append(rolesList, model.TeamGuestRoleId)
 	}
 	roles := strings.Join(rolesList, " ")
-	return &UserTeamImportData{
+	return &model.UserTeamImportData{
 		Name:  &member.TeamName,
 		Roles: &roles,
 	}
 }
 
-func ImportUserChannelDataFromChannelMemberAndPreferences(member *model.ChannelMemberForExport, preferences *model.Preferences) *UserChannelImportData {
+func ImportUserChannelDataFromChannelMemberAndPreferences(member *model.ChannelMemberForExport, preferences *model.Preferences) *model.UserChannelImportData {
 	rolesList := strings.Fields(member.Roles)
 	if member.SchemeAdmin {
 		rolesList = append(rolesList, model.ChannelAdminRoleId)
@@ -117,7 +117,7 @@ func ImportUserChannelDataFromChannelMemberAndPreferences(member *model.ChannelM
 		rolesList = append(rolesList, model.ChannelGuestRoleId)
 	}
 	props := member.NotifyProps
-	notifyProps := UserChannelNotifyPropsImportData{}
+	notifyProps := model.UserChannelNotifyPropsImportData{}
 
 	desktop, exist := props[model.DesktopNotifyProp]
 	if exist {
@@ -140,7 +140,7 @@ func ImportUserChannelDataFromChannelMemberAndPreferences(member *model.ChannelM
 	}
 
 	roles := strings.Join(rolesList, " ")
-	return &UserChannelImportData{
+	return &model.UserChannelImportData{
 		Name:        &member.ChannelName,
 		Roles:       &roles,
 		NotifyProps: &notifyProps,
@@ -148,10 +148,10 @@ func ImportUserChannelDataFromChannelMemberAndPreferences(member *model.ChannelM
 	}
 }
 
-func ImportLineForPost(post *model.PostForExport) *LineImportData {
-	return &LineImportData{
+func ImportLineForPost(post *model.PostForExport) *model.LineImportData {
+	return &model.LineImportData{
 		Type: "post",
-		Post: &PostImportData{
+		Post: &model.PostImportData{
 			Team:     &post.TeamName,
 			Channel:  &post.ChannelName,
 			User:     &post.Username,
@@ -160,18 +160,19 @@ func ImportLineForPost(post *model.PostForExport) *LineImportData {
 			Props:    &post.Props,
 			CreateAt: &post.CreateAt,
 			EditAt:   &post.EditAt,
+			IsPinned: &post.IsPinned,
 		},
 	}
 }
 
-func ImportLineForDirectPost(post *model.DirectPostForExport) *LineImportData {
+func ImportLineForDirectPost(post *model.DirectPostForExport) *model.LineImportData {
 	channelMembers := *post.ChannelMembers
 	if len(channelMembers) == 1 {
 		channelMembers = []string{channelMembers[0], channelMembers[0]}
 	}
-	return &LineImportData{
+	return &model.LineImportData{
 		Type: "direct_post",
-		DirectPost: &DirectPostImportData{
+		DirectPost: &model.DirectPostImportData{
 			ChannelMembers: &channelMembers,
 			User:           &post.User,
 			Type:           &post.Type,
@@ -179,34 +180,261 @@ func ImportLineForDirectPost(post *model.DirectPostForExport) *LineImportData {
 			Props:          &post.Props,
 			CreateAt:       &post.CreateAt,
 			EditAt:         &post.EditAt,
+			IsPinned:       &post.IsPinned,
 		},
 	}
 }
 
-func ImportReplyFromPost(post *model.ReplyForExport) *ReplyImportData {
-	return &ReplyImportData{
+func ImportReplyFromPost(post *model.ReplyForExport) *model.ReplyImportData {
+	return &model.ReplyImportData{
 		User:     &post.Username,
 		Type:     &post.Type,
 		Message:  &post.Message,
+		Props:    &post.Props,
 		CreateAt: &post.CreateAt,
 		EditAt:   &post.EditAt,
+		IsPinned: &post.IsPinned,
 	}
 }
 
-func ImportReactionFromPost(user *model.User, reaction *model.Reaction) *ReactionImportData {
-	return &ReactionImportData{
+func ImportReactionFromPost(user *model.User, reaction *model.Reaction) *model.ReactionImportData {
+	return &model.ReactionImportData{
 		User:      &user.Username,
 		EmojiName: &reaction.EmojiName,
 		CreateAt:  &reaction.CreateAt,
 	}
 }
 
-func ImportLineFromEmoji(emoji *model.Emoji, filePath string) *LineImportData {
-	return &LineImportData{
+// ImportPollVotesFromVotes converts the votes for a poll, grouping the votes of each user.
+// The usernames map the ids of the users who voted to their usernames; the votes of users
+// missing from it are skipped.
+func ImportPollVotesFromVotes(votes []*model.PollVote, usernames map[string]string) []model.PollVoteImportData {
+	var data []model.PollVoteImportData
+	byUser := map[string]int{}
+	for _, vote := range votes {
+		username, ok := usernames[vote.UserId]
+		if !ok || username == "" {
+			continue
+		}
+		i, ok := byUser[vote.UserId]
+		if !ok {
+			i = len(data)
+			byUser[vote.UserId] = i
+			createAt := vote.CreateAt
+			data = append(data, model.PollVoteImportData{User: &username, Options: &[]int{}, CreateAt: &createAt})
+		}
+		*data[i].Options = append(*data[i].Options, vote.OptionIndex)
+	}
+	return data
+}
+
+// ImportPostRevisionFromRevision converts a revision, editor being the username of the
+// user who made the edit or empty when not known.
+func ImportPostRevisionFromRevision(revision *model.PostRevision, editor string) *model.PostRevisionImportData {
+	data := &model.PostRevisionImportData{
+		Message:  &revision.Message,
+		Props:    &revision.Props,
+		CreateAt: &revision.CreateAt,
+	}
+	if editor != "" {
+		data.Editor = &editor
+	}
+	return data
+}
+
+func ImportLineFromEmoji(emoji *model.Emoji, filePath string) *model.LineImportData {
+	return &model.LineImportData{
 		Type: "emoji",
-		Emoji: &EmojiImportData{
+		Emoji: &model.EmojiImportData{
 			Name:  &emoji.Name,
 			Image: &filePath,
 		},
 	}
 }
+
+func ImportLineFromRole(role *model.Role) *model.LineImportData {
+	return &model.LineImportData{
+		Type: "role",
+		Role: ImportRoleDataFromRole(role),
+	}
+}
+
+// ImportRoleDataFromRole leaves out the permissions the importer doesn't know about.
+func ImportRoleDataFromRole(role *model.Role) *model.RoleImportData {
+	permissions := []string{}
+	for _, permission := range role.Permissions {
+		for _, p := range append(model.AllPermissions, model.DeprecatedPermissions...) {
+			if permission == p.Id {
+				permissions = append(permissions, permission)
+				break
+			}
+		}
+	}
+
+	return &model.RoleImportData{
+		Name:        &role.Name,
+		DisplayName: &role.DisplayName,
+		Description: &role.Description,
+		Permissions: &permissions,
+	}
+}
+
+// ImportLineFromScheme takes the default roles of the scheme keyed by name.
+func ImportLineFromScheme(scheme *model.Scheme, roles map[string]*model.Role) *model.LineImportData {
+	schemeRole := func(name string) *model.RoleImportData {
+		role, ok := roles[name]
+		if !ok {
+			return nil
+		}
+		return ImportRoleDataFromRole(role)
+	}
+
+	data := &model.SchemeImportData{
+		Name:                    &scheme.Name,
+		DisplayName:             &scheme.DisplayName,
+		Description:             &scheme.Description,
+		Scope:                   &scheme.Scope,
+		DefaultChannelAdminRole: schemeRole(scheme.DefaultChannelAdminRole),
+		DefaultChannelUserRole:  schemeRole(scheme.DefaultChannelUserRole),
+		DefaultChannelGuestRole: schemeRole(scheme.DefaultChannelGuestRole),
+	}
+	if scheme.Scope == model.SchemeScopeTeam {
+		data.DefaultTeamAdminRole = schemeRole(scheme.DefaultTeamAdminRole)
+		data.DefaultTeamUserRole = schemeRole(scheme.DefaultTeamUserRole)
+		data.DefaultTeamGuestRole = schemeRole(scheme.DefaultTeamGuestRole)
+	}
+
+	return &model.LineImportData{
+		Type:   "scheme",
+		Scheme: data,
+	}
+}
+
+func ImportLineFromBot(bot *model.Bot, owner string) *model.LineImportData {
+	return &model.LineImportData{
+		Type: "bot",
+		Bot: &model.BotImportData{
+			Username:    &bot.Username,
+			DisplayName: &bot.DisplayName,
+			Description: &bot.Description,
+			Owner:       &owner,
+			DeleteAt:    &bot.DeleteAt,
+		},
+	}
+}
+
+func ImportSidebarCategoryDataFromCategory(category *model.SidebarCategoryWithChannels, channelNames []string) *model.SidebarCategoryImportData {
+	sorting := string(category.Sorting)
+	return &model.SidebarCategoryImportData{
+		DisplayName: &category.DisplayName,
+		Sorting:     &sorting,
+		Muted:       &category.Muted,
+		Collapsed:   &category.Collapsed,
+		Channels:    &channelNames,
+	}
+}
+
+func ImportLineFromCommand(command *model.Command, teamName, creator string) *model.LineImportData {
+	return &model.LineImportData{
+		Type: "command",
+		Command: &model.CommandImportData{
+			Team:             &teamName,
+			Creator:          &creator,
+			Trigger:          &command.Trigger,
+			Token:            &command.Token,
+			URL:              &command.URL,
+			Method:           &command.Method,
+			Username:         &command.Username,
+			IconURL:          &command.IconURL,
+			AutoComplete:     &command.AutoComplete,
+			AutoCompleteDesc: &command.AutoCompleteDesc,
+			AutoCompleteHint: &command.AutoCompleteHint,
+			DisplayName:      &command.DisplayName,
+			Description:      &command.Description,
+		},
+	}
+}
+
+func ImportLineFromIncomingWebhook(hook *model.IncomingWebhook, teamName, channelName, creator string) *model.LineImportData {
+	return &model.LineImportData{
+		Type: "incoming_webhook",
+		IncomingWebhook: &model.IncomingWebhookImportData{
+			Team:          &teamName,
+			Channel:       &channelName,
+			Creator:       &creator,
+			DisplayName:   &hook.DisplayName,
+			Description:   &hook.Description,
+			Username:      &hook.Username,
+			IconURL:       &hook.IconURL,
+			ChannelLocked: &hook.ChannelLocked,
+		},
+	}
+}
+
+// ImportLineFromChannelBookmark takes the exported file of the file bookmarks.
+func ImportLineFromChannelBookmark(bookmark *model.ChannelBookmark, teamName, channelName, owner string, file *model.AttachmentImportData) *model.LineImportData {
+	bookmarkType := string(bookmark.Type)
+	data := &model.ChannelBookmarkImportData{
+		Team:        &teamName,
+		Channel:     &channelName,
+		Owner:       &owner,
+		DisplayName: &bookmark.DisplayName,
+		Type:        &bookmarkType,
+		File:        file,
+		ImageUrl:    &bookmark.ImageUrl,
+		Emoji:       &bookmark.Emoji,
+		SortOrder:   &bookmark.SortOrder,
+	}
+	if bookmark.Type == model.ChannelBookmarkLink {
+		data.LinkUrl = &bookmark.LinkUrl
+	}
+
+	return &model.LineImportData{
+		Type:            "channel_bookmark",
+		ChannelBookmark: data,
+	}
+}
+
+// ImportLineFromOutgoingWebhook takes an empty channel name for the webhooks triggered in any channel of the team.
+func ImportLineFromOutgoingWebhook(hook *model.OutgoingWebhook, teamName, channelName, creator string) *model.LineImportData {
+	var channel *string
+	if channelName != "" {
+		channel = &channelName
+	}
+	callbackURLs := []string(hook.CallbackURLs)
+	triggerWords := []string(hook.TriggerWords)
+	return &model.LineImportData{
+		Type: "outgoing_webhook",
+		OutgoingWebhook: &model.OutgoingWebhookImportData{
+			Team:         &teamName,
+			Channel:      channel,
+			Creator:      &creator,
+			Token:        &hook.Token,
+			CallbackURLs: &callbackURLs,
+			TriggerWords: &triggerWords,
+			TriggerWhen:  &hook.TriggerWhen,
+			DisplayName:  &hook.DisplayName,
+			Description:  &hook.Description,
+			ContentType:  &hook.ContentType,
+			Username:     &hook.Username,
+			IconURL:      &hook.IconURL,
+		},
+	}
+}
+
+func ImportLineFromOAuthApp(app *model.OAuthApp, creator string) *model.LineImportData {
+	callbackURLs := []string(app.CallbackUrls)
+	return &model.LineImportData{
+		Type: "oauth_app",
+		OAuthApp: &model.OAuthAppImportData{
+			Creator:      &creator,
+			Name:         &app.Name,
+			ClientSecret: &app.ClientSecret,
+			CallbackURLs: &callbackURLs,
+			Homepage:     &app.Homepage,
+			Description:  &app.Description,
+			IconURL:      &app.IconURL,
+			IsTrusted:    &app.IsTrusted,
+		},
+	}
+}
@@ -5,12 +5,16 @@ package app
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -98,7 +102,7 @@ func TestExportUserChannels(t *testing.T) {
 	require.NoError(t, err)
 
 	th.App.UpdateChannelMemberNotifyProps(notifyProps, channel.Id, user.Id)
-	exportData, appErr := th.App.buildUserChannelMemberships(user.Id, team.Id)
+	exportData, appErr := th.App.buildUserChannelMemberships(user.Id, team.Id, nil)
 	require.Nil(t, appErr)
 	assert.Equal(t, len(*exportData), 3)
 	for _, data := range *exportData {
@@ -440,7 +444,7 @@ func TestExportDMandGMPost(t *testing.T) {
 	}
 	th1.App.CreatePost(th1.Context, p4, gmChannel, false, true)
 
-	posts, err := th1.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000")
+	posts, err := th1.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000", 0)
 	require.NoError(t, err)
 	assert.Equal(t, 4, len(posts))
 
@@ -453,7 +457,7 @@ func TestExportDMandGMPost(t *testing.T) {
 	th2 := Setup(t)
 	defer th2.TearDown()
 
-	posts, err = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000")
+	posts, err = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000", 0)
 	require.NoError(t, err)
 	assert.Equal(t, 0, len(posts))
 
@@ -462,7 +466,7 @@ func TestExportDMandGMPost(t *testing.T) {
 	assert.Nil(t, appErr)
 	assert.Equal(t, 0, i)
 
-	posts, err = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000")
+	posts, err = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000", 0)
 	require.NoError(t, err)
 
 	// Adding some determinism so its possible to assert on slice index
@@ -513,7 +517,7 @@ func TestExportPostWithProps(t *testing.T) {
 	}
 	th1.App.CreatePost(th1.Context, p2, gmChannel, false, true)
 
-	posts, err := th1.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000")
+	posts, err := th1.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000", 0)
 	require.NoError(t, err)
 	assert.Len(t, posts, 2)
 	require.NotEmpty(t, posts[0].Props)
@@ -528,7 +532,7 @@ func TestExportPostWithProps(t *testing.T) {
 	th2 := Setup(t)
 	defer th2.TearDown()
 
-	posts, err = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000")
+	posts, err = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000", 0)
 	require.NoError(t, err)
 	assert.Len(t, posts, 0)
 
@@ -537,7 +541,7 @@ func TestExportPostWithProps(t *testing.T) {
 	assert.Nil(t, appErr)
 	assert.Equal(t, 0, i)
 
-	posts, err = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000")
+	posts, err = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000", 0)
 	require.NoError(t, err)
 
 	// Adding some determinism so its possible to assert on slice index
@@ -561,7 +565,7 @@ func TestExportDMPostWithSelf(t *testing.T) {
 	err := th1.App.BulkExport(&b, "somePath", model.BulkExportOpts{})
 	require.Nil(t, err)
 
-	posts, nErr := th1.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000")
+	posts, nErr := th1.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000", 0)
 	require.NoError(t, nErr)
 	assert.Equal(t, 1, len(posts))
 
@@ -570,7 +574,7 @@ func TestExportDMPostWithSelf(t *testing.T) {
 	th2 := Setup(t)
 	defer th2.TearDown()
 
-	posts, nErr = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000")
+	posts, nErr = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000", 0)
 	require.NoError(t, nErr)
 	assert.Equal(t, 0, len(posts))
 
@@ -579,7 +583,7 @@ func TestExportDMPostWithSelf(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 0, i)
 
-	posts, nErr = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000")
+	posts, nErr = th2.App.Srv().Store.Post().GetDirectPostParentsForExportAfter(1000, "0000000", 0)
 	require.NoError(t, nErr)
 	assert.Equal(t, 1, len(posts))
 	assert.Equal(t, 1, len((*posts[0].ChannelMembers)))
@@ -640,6 +644,121 @@ func TestBulkExport(t *testing.T) {
 	require.Nil(t, appErr)
 }
 
+func TestBulkExportScoped(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	otherTeam := th.CreateTeam()
+	otherUser := th.CreateUser()
+	th.LinkUserToTeam(otherUser, otherTeam)
+	otherChannel := th.CreateChannel(otherTeam)
+	otherPost := th.CreatePost(otherChannel)
+
+	oldPost, err := th.App.Srv().Store.Post().Save(&model.Post{
+		ChannelId: th.BasicChannel.Id,
+		UserId:    th.BasicUser.Id,
+		Message:   "old",
+		CreateAt:  1000,
+	})
+	require.NoError(t, err)
+	newPost := th.CreatePost(th.BasicChannel)
+	channel2Post := th.CreatePost(th.CreateChannel(th.BasicTeam))
+	dmChannel := th.CreateDmChannel(th.BasicUser2)
+	th.CreatePost(dmChannel)
+
+	export := func(opts model.BulkExportOpts) map[string][]*model.LineImportData {
+		var b bytes.Buffer
+		appErr := th.App.BulkExport(&b, "somePath", opts)
+		require.Nil(t, appErr)
+
+		lines := map[string][]*model.LineImportData{}
+		decoder := json.NewDecoder(&b)
+		for decoder.More() {
+			var line model.LineImportData
+			require.NoError(t, decoder.Decode(&line))
+			lines[line.Type] = append(lines[line.Type], &line)
+		}
+		return lines
+	}
+
+	postMessages := func(lines map[string][]*model.LineImportData) []string {
+		messages := []string{}
+		for _, line := range lines["post"] {
+			messages = append(messages, *line.Post.Message)
+		}
+		return messages
+	}
+
+	t.Run("team", func(t *testing.T) {
+		lines := export(model.BulkExportOpts{TeamIds: []string{th.BasicTeam.Id}})
+
+		require.Len(t, lines["team"], 1)
+		assert.Equal(t, th.BasicTeam.Name, *lines["team"][0].Team.Name)
+		for _, line := range lines["channel"] {
+			assert.Equal(t, th.BasicTeam.Name, *line.Channel.Team)
+		}
+		for _, line := range lines["user"] {
+			assert.NotEqual(t, otherUser.Username, *line.User.Username)
+			for _, team := range *line.User.Teams {
+				assert.Equal(t, th.BasicTeam.Name, *team.Name)
+			}
+		}
+		messages := postMessages(lines)
+		assert.Contains(t, messages, oldPost.Message)
+		assert.Contains(t, messages, newPost.Message)
+		assert.Contains(t, messages, channel2Post.Message)
+		assert.NotContains(t, messages, otherPost.Message)
+		assert.Empty(t, lines["direct_channel"])
+		assert.Empty(t, lines["direct_post"])
+	})
+
+	t.Run("channel", func(t *testing.T) {
+		lines := export(model.BulkExportOpts{ChannelIds: []string{th.BasicChannel.Id}})
+
+		require.Len(t, lines["team"], 1)
+		assert.Equal(t, th.BasicTeam.Name, *lines["team"][0].Team.Name)
+		require.Len(t, lines["channel"], 1)
+		assert.Equal(t, th.BasicChannel.Name, *lines["channel"][0].Channel.Name)
+		for _, line := range lines["user"] {
+			for _, team := range *line.User.Teams {
+				if team.Channels == nil {
+					continue
+				}
+				for _, channel := range *team.Channels {
+					assert.Equal(t, th.BasicChannel.Name, *channel.Name)
+				}
+			}
+		}
+		messages := postMessages(lines)
+		assert.Contains(t, messages, oldPost.Message)
+		assert.Contains(t, messages, newPost.Message)
+		assert.NotContains(t, messages, channel2Post.Message)
+		assert.Empty(t, lines["direct_post"])
+	})
+
+	t.Run("direct channel", func(t *testing.T) {
+		var b bytes.Buffer
+		appErr := th.App.BulkExport(&b, "somePath", model.BulkExportOpts{ChannelIds: []string{dmChannel.Id}})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.export.scope.channel.app_error", appErr.Id)
+	})
+
+	t.Run("since", func(t *testing.T) {
+		lines := export(model.BulkExportOpts{
+			ChannelIds: []string{th.BasicChannel.Id},
+			Since:      newPost.CreateAt,
+		})
+		messages := postMessages(lines)
+		assert.Contains(t, messages, newPost.Message)
+		assert.NotContains(t, messages, oldPost.Message)
+
+		lines = export(model.BulkExportOpts{Since: newPost.CreateAt})
+		assert.NotContains(t, postMessages(lines), oldPost.Message)
+		assert.Len(t, lines["team"], 2)
+		assert.Len(t, lines["direct_post"], 1)
+	})
+}
+
 func TestBuildPostReplies(t *testing.T) {
 	th := Setup(t).InitBasic()
 	defer th.TearDown()
@@ -704,6 +823,127 @@ func TestBuildPostReplies(t *testing.T) {
 	})
 }
 
+func TestExportImportPostEditHistory(t *testing.T) {
+	th1 := Setup(t).InitBasic()
+
+	post := th1.CreatePost(th1.BasicChannel)
+	original := post.Message
+	th1.Context.Session().UserId = th1.BasicUser2.Id
+	for _, message := range []string{"first edit", "second edit"} {
+		edit := post.Clone()
+		edit.Message = message
+		var appErr *model.AppError
+		post, appErr = th1.App.UpdatePost(th1.Context, edit, false)
+		require.Nil(t, appErr)
+	}
+
+	var b bytes.Buffer
+	appErr := th1.App.BulkExport(&b, "somePath", model.BulkExportOpts{})
+	require.Nil(t, appErr)
+	exported := b.Bytes()
+	th1.TearDown()
+
+	th2 := Setup(t)
+	defer th2.TearDown()
+
+	importExport := func() {
+		appErr, i := th2.App.BulkImport(th2.Context, bytes.NewReader(exported), nil, false, 5)
+		require.Nil(t, appErr)
+		require.Equal(t, 0, i)
+	}
+	importExport()
+
+	posts, err := th2.App.Srv().Store.Post().GetParentsForExportAfter(1000, strings.Repeat("0", 26), nil, 0)
+	require.NoError(t, err)
+	var imported *model.PostForExport
+	for _, p := range posts {
+		if p.Message == "second edit" {
+			imported = p
+		}
+	}
+	require.NotNil(t, imported)
+
+	editor, err := th2.App.Srv().Store.User().GetByUsername(th1.BasicUser2.Username)
+	require.NoError(t, err)
+
+	revisions, appErr := th2.App.GetPostEditHistory(imported.Id)
+	require.Nil(t, appErr)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "first edit", revisions[0].Message)
+	assert.Equal(t, original, revisions[1].Message)
+	assert.Equal(t, editor.Id, revisions[0].EditorId)
+
+	// Importing again does not duplicate the revisions.
+	importExport()
+	revisions, appErr = th2.App.GetPostEditHistory(imported.Id)
+	require.Nil(t, appErr)
+	assert.Len(t, revisions, 2)
+}
+
+func TestExportImportPollVotes(t *testing.T) {
+	th1 := Setup(t).InitBasic()
+
+	post := createPollPost(t, th1, &model.Poll{Options: []string{"Pizza", "Sushi", "Tacos"}, MultiChoice: true})
+	_, appErr := th1.App.VotePoll(th1.Context, post.Id, th1.BasicUser.Id, []int{0, 2})
+	require.Nil(t, appErr)
+	_, appErr = th1.App.VotePoll(th1.Context, post.Id, th1.BasicUser2.Id, []int{2})
+	require.Nil(t, appErr)
+	_, appErr = th1.App.ClosePoll(th1.Context, post.Id)
+	require.Nil(t, appErr)
+
+	reply := &model.Post{UserId: th1.BasicUser.Id, ChannelId: th1.BasicChannel.Id, RootId: post.Id, Message: "Dessert?"}
+	reply.SetPoll(&model.Poll{Options: []string{"Cake", "Fruit"}, Anonymous: true})
+	reply, appErr = th1.App.CreatePost(th1.Context, reply, th1.BasicChannel, false, true)
+	require.Nil(t, appErr)
+	_, appErr = th1.App.VotePoll(th1.Context, reply.Id, th1.BasicUser2.Id, []int{1})
+	require.Nil(t, appErr)
+
+	var b bytes.Buffer
+	appErr = th1.App.BulkExport(&b, "somePath", model.BulkExportOpts{})
+	require.Nil(t, appErr)
+	th1.TearDown()
+
+	th2 := Setup(t)
+	defer th2.TearDown()
+
+	appErr, i := th2.App.BulkImport(th2.Context, bytes.NewReader(b.Bytes()), nil, false, 5)
+	require.Nil(t, appErr)
+	require.Equal(t, 0, i)
+
+	posts, err := th2.App.Srv().Store.Post().GetParentsForExportAfter(1000, strings.Repeat("0", 26), nil, 0)
+	require.NoError(t, err)
+	var imported *model.PostForExport
+	for _, p := range posts {
+		if p.Message == "Lunch?" {
+			imported = p
+		}
+	}
+	require.NotNil(t, imported)
+
+	poll := imported.GetPoll()
+	require.NotNil(t, poll)
+	assert.True(t, poll.MultiChoice)
+	assert.NotZero(t, poll.ClosedAt)
+
+	voter, err := th2.App.Srv().Store.User().GetByUsername(th1.BasicUser.Username)
+	require.NoError(t, err)
+	results, appErr := th2.App.GetPollResults(imported.Id, voter.Id)
+	require.Nil(t, appErr)
+	assert.Equal(t, []int{1, 0, 2}, results.Counts)
+	assert.Equal(t, 2, results.TotalVoters)
+	assert.Equal(t, []int{0, 2}, results.UserVotes)
+
+	replies, err := th2.App.Srv().Store.Post().GetRepliesForExport(imported.Id)
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	replyPoll := replies[0].GetPoll()
+	require.NotNil(t, replyPoll)
+	assert.True(t, replyPoll.Anonymous)
+	results, appErr = th2.App.GetPollResults(replies[0].Id, voter.Id)
+	require.Nil(t, appErr)
+	assert.Equal(t, []int{0, 1}, results.Counts)
+}
+
 func TestExportDeletedTeams(t *testing.T) {
 	th1 := Setup(t).InitBasic()
 	defer th1.TearDown()
@@ -744,3 +984,210 @@ func TestExportDeletedTeams(t *testing.T) {
 		assert.NotContains(t, team.Id, team1.Id)
 	}
 }
+
+func TestExportImportIntegrations(t *testing.T) {
+	th1 := Setup(t).InitBasic()
+	th1.App.Srv().Store.System().Save(&model.System{Name: model.MigrationKeyAdvancedPermissionsPhase2, Value: "true"})
+
+	role, err := th1.App.Srv().Store.Role().Save(&model.Role{
+		Name:        "custom_" + model.NewId(),
+		DisplayName: "Custom Role",
+		Permissions: []string{model.PermissionCreatePost.Id},
+	})
+	require.NoError(t, err)
+
+	scheme, appErr := th1.App.CreateScheme(&model.Scheme{
+		Name:        model.NewId(),
+		DisplayName: "Team Scheme",
+		Scope:       model.SchemeScopeTeam,
+	})
+	require.Nil(t, appErr)
+
+	bot := th1.CreateBot()
+	th1.LinkUserToTeam(&model.User{Id: bot.UserId}, th1.BasicTeam)
+
+	category, appErr := th1.App.CreateSidebarCategory(th1.BasicUser.Id, th1.BasicTeam.Id, &model.SidebarCategoryWithChannels{
+		SidebarCategory: model.SidebarCategory{
+			UserId:      th1.BasicUser.Id,
+			TeamId:      th1.BasicTeam.Id,
+			DisplayName: "Work",
+		},
+		Channels: []string{th1.BasicChannel.Id},
+	})
+	require.Nil(t, appErr)
+
+	command, err := th1.App.Srv().Store.Command().Save(&model.Command{
+		CreatorId: th1.BasicUser.Id,
+		TeamId:    th1.BasicTeam.Id,
+		Trigger:   "trigger",
+		Method:    model.CommandMethodPost,
+		URL:       "http://example.com/command",
+	})
+	require.NoError(t, err)
+
+	incomingHook, err := th1.App.Srv().Store.Webhook().SaveIncoming(&model.IncomingWebhook{
+		UserId:      th1.BasicUser.Id,
+		TeamId:      th1.BasicTeam.Id,
+		ChannelId:   th1.BasicChannel.Id,
+		DisplayName: "Incoming",
+	})
+	require.NoError(t, err)
+
+	outgoingHook, err := th1.App.Srv().Store.Webhook().SaveOutgoing(&model.OutgoingWebhook{
+		CreatorId:    th1.BasicUser.Id,
+		TeamId:       th1.BasicTeam.Id,
+		TriggerWords: []string{"word"},
+		CallbackURLs: []string{"http://example.com/hook"},
+		DisplayName:  "Outgoing",
+	})
+	require.NoError(t, err)
+
+	oauthApp, err := th1.App.Srv().Store.OAuth().SaveApp(&model.OAuthApp{
+		CreatorId:    th1.BasicUser.Id,
+		Name:         "app",
+		CallbackUrls: []string{"http://example.com/callback"},
+		Homepage:     "http://example.com",
+	})
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	appErr = th1.App.BulkExport(&b, "somePath", model.BulkExportOpts{})
+	require.Nil(t, appErr)
+
+	th1.TearDown()
+
+	th2 := Setup(t)
+	defer th2.TearDown()
+	th2.App.Srv().Store.System().Save(&model.System{Name: model.MigrationKeyAdvancedPermissionsPhase2, Value: "true"})
+
+	appErr, i := th2.App.BulkImport(th2.Context, &b, nil, false, 1)
+	require.Nil(t, appErr)
+	assert.Equal(t, 0, i)
+
+	importedRole, err := th2.App.Srv().Store.Role().GetByName(context.Background(), role.Name)
+	require.NoError(t, err)
+	assert.Equal(t, role.Permissions, importedRole.Permissions)
+
+	importedScheme, err := th2.App.Srv().Store.Scheme().GetByName(scheme.Name)
+	require.NoError(t, err)
+	assert.Equal(t, scheme.DisplayName, importedScheme.DisplayName)
+
+	team, err := th2.App.Srv().Store.Team().GetByName(th1.BasicTeam.Name)
+	require.NoError(t, err)
+	channel, err := th2.App.Srv().Store.Channel().GetByName(team.Id, th1.BasicChannel.Name, false)
+	require.NoError(t, err)
+	owner, err := th2.App.Srv().Store.User().GetByUsername(th1.BasicUser.Username)
+	require.NoError(t, err)
+
+	importedBotUser, err := th2.App.Srv().Store.User().GetByUsername(bot.Username)
+	require.NoError(t, err)
+	importedBot, err := th2.App.Srv().Store.Bot().Get(importedBotUser.Id, false)
+	require.NoError(t, err)
+	assert.Equal(t, owner.Id, importedBot.OwnerId)
+	assert.Equal(t, bot.DisplayName, importedBot.DisplayName)
+	_, err = th2.App.Srv().Store.Team().GetMember(context.Background(), team.Id, importedBot.UserId)
+	require.NoError(t, err)
+
+	categories, appErr := th2.App.GetSidebarCategories(owner.Id, team.Id)
+	require.Nil(t, appErr)
+	var importedCategory *model.SidebarCategoryWithChannels
+	for _, c := range categories.Categories {
+		if c.Type == model.SidebarCategoryCustom {
+			importedCategory = c
+		}
+	}
+	require.NotNil(t, importedCategory)
+	assert.Equal(t, category.DisplayName, importedCategory.DisplayName)
+	assert.Equal(t, []string{channel.Id}, importedCategory.Channels)
+
+	importedCommand, err := th2.App.Srv().Store.Command().GetByTrigger(team.Id, command.Trigger)
+	require.NoError(t, err)
+	assert.Equal(t, command.Token, importedCommand.Token)
+	assert.Equal(t, owner.Id, importedCommand.CreatorId)
+
+	incomingHooks, err := th2.App.Srv().Store.Webhook().GetIncomingByChannel(channel.Id)
+	require.NoError(t, err)
+	require.Len(t, incomingHooks, 1)
+	assert.Equal(t, incomingHook.DisplayName, incomingHooks[0].DisplayName)
+	assert.Equal(t, owner.Id, incomingHooks[0].UserId)
+
+	outgoingHooks, err := th2.App.Srv().Store.Webhook().GetOutgoingByTeam(team.Id, -1, -1)
+	require.NoError(t, err)
+	require.Len(t, outgoingHooks, 1)
+	assert.Equal(t, outgoingHook.Token, outgoingHooks[0].Token)
+	assert.Equal(t, outgoingHook.TriggerWords, outgoingHooks[0].TriggerWords)
+
+	apps, err := th2.App.Srv().Store.OAuth().GetAppByUser(owner.Id, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, oauthApp.ClientSecret, apps[0].ClientSecret)
+}
+
+func TestExportImportChannelBookmarks(t *testing.T) {
+	th1 := Setup(t).InitBasic()
+
+	dir, err := ioutil.TempDir("", "export_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	info, appErr := th1.App.DoUploadFile(th1.Context, time.Now(), th1.BasicTeam.Id, th1.BasicChannel.Id, th1.BasicUser.Id, "handbook.txt", []byte("handbook"))
+	require.Nil(t, appErr)
+
+	_, appErr = th1.App.CreateChannelBookmark(th1.Context, &model.ChannelBookmark{
+		ChannelId:   th1.BasicChannel.Id,
+		OwnerId:     th1.BasicUser.Id,
+		DisplayName: "Docs",
+		LinkUrl:     "https://example.com/docs",
+		Emoji:       "books",
+		Type:        model.ChannelBookmarkLink,
+	})
+	require.Nil(t, appErr)
+	_, appErr = th1.App.CreateChannelBookmark(th1.Context, &model.ChannelBookmark{
+		ChannelId:   th1.BasicChannel.Id,
+		OwnerId:     th1.BasicUser.Id,
+		DisplayName: "Handbook",
+		FileId:      info.Id,
+		Type:        model.ChannelBookmarkFile,
+	})
+	require.Nil(t, appErr)
+
+	var b bytes.Buffer
+	appErr = th1.App.BulkExport(&b, dir, model.BulkExportOpts{IncludeAttachments: true})
+	require.Nil(t, appErr)
+	th1.TearDown()
+
+	th2 := Setup(t)
+	defer th2.TearDown()
+
+	for i := 0; i < 2; i++ {
+		appErr, line := th2.App.BulkImportWithPath(th2.Context, bytes.NewReader(b.Bytes()), nil, false, 1, filepath.Join(dir, model.ExportDataDir))
+		require.Nil(t, appErr)
+		require.Equal(t, 0, line)
+	}
+
+	team, err := th2.App.Srv().Store.Team().GetByName(th1.BasicTeam.Name)
+	require.NoError(t, err)
+	channel, err := th2.App.Srv().Store.Channel().GetByName(team.Id, th1.BasicChannel.Name, false)
+	require.NoError(t, err)
+	owner, err := th2.App.Srv().Store.User().GetByUsername(th1.BasicUser.Username)
+	require.NoError(t, err)
+
+	bookmarks, appErr := th2.App.GetChannelBookmarks(channel.Id, 0)
+	require.Nil(t, appErr)
+	require.Len(t, bookmarks, 2)
+
+	assert.Equal(t, "Docs", bookmarks[0].DisplayName)
+	assert.Equal(t, "https://example.com/docs", bookmarks[0].LinkUrl)
+	assert.Equal(t, "books", bookmarks[0].Emoji)
+	assert.Equal(t, owner.Id, bookmarks[0].OwnerId)
+
+	assert.Equal(t, "Handbook", bookmarks[1].DisplayName)
+	assert.Equal(t, model.ChannelBookmarkFile, bookmarks[1].Type)
+	require.NotNil(t, bookmarks[1].FileInfo)
+	assert.Equal(t, "handbook.txt", bookmarks[1].FileInfo.Name)
+	assert.Equal(t, owner.Id, bookmarks[1].FileInfo.CreatorId)
+
+	data, appErr := th2.App.GetFile(bookmarks[1].FileId)
+	require.Nil(t, appErr)
+	assert.Equal(t, []byte("handbook"), data)
+}
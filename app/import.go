@@ -38,7 +38,7 @@ func stopOnError(err LineImportWorkerError) bool {
 	}
 }
 
-func processAttachmentPaths(files *[]AttachmentImportData, basePath string, filesMap map[string]*zip.File) error {
+func processAttachmentPaths(files *[]model.AttachmentImportData, basePath string, filesMap map[string]*zip.File) error {
 	if files == nil {
 		return nil
 	}
@@ -58,11 +58,11 @@ func processAttachmentPaths(files *[]AttachmentImportData, basePath string, file
 	return nil
 }
 
-func processAttachments(line *LineImportData, basePath string, filesMap map[string]*zip.File) error {
+func processAttachments(line *model.LineImportData, basePath string, filesMap map[string]*zip.File) error {
 	var ok bool
 	switch line.Type {
 	case "post", "direct_post":
-		var replies []ReplyImportData
+		var replies []model.ReplyImportData
 		if line.Type == "direct_post" {
 			if err := processAttachmentPaths(line.DirectPost.Attachments, basePath, filesMap); err != nil {
 				return err
@@ -93,6 +93,16 @@ func processAttachments(line *LineImportData, basePath string, filesMap map[stri
 				}
 			}
 		}
+	case "channel_bookmark":
+		if line.ChannelBookmark != nil && line.ChannelBookmark.File != nil && line.ChannelBookmark.File.Path != nil {
+			path := filepath.Join(basePath, *line.ChannelBookmark.File.Path)
+			*line.ChannelBookmark.File.Path = path
+			if len(filesMap) > 0 {
+				if line.ChannelBookmark.File.Data, ok = filesMap[path]; !ok {
+					return fmt.Errorf("attachment %q not found in map", path)
+				}
+			}
+		}
 	case "emoji":
 		if line.Emoji.Image != nil {
 			path := filepath.Join(basePath, *line.Emoji.Image)
@@ -108,80 +118,155 @@ func processAttachments(line *LineImportData, basePath string, filesMap map[stri
 	return nil
 }
 
-func (a *App) bulkImportWorker(c *request.Context, dryRun bool, wg *sync.WaitGroup, lines <-chan LineImportWorkerData, errors chan<- LineImportWorkerError) {
+func (a *App) bulkImportWorker(c *request.Context, opts model.BulkImportOpts, wg *sync.WaitGroup, lines <-chan LineImportWorkerData, errors chan<- LineImportWorkerError) {
 	postLines := []LineImportWorkerData{}
 	directPostLines := []LineImportWorkerData{}
+
+	// importBatch imports a batch of post lines. When the import goes on past failing
+	// lines, a failing batch is imported again line by line, so that only the failing
+	// lines are lost.
+	importBatch := func(batch []LineImportWorkerData, importFn func(*request.Context, []LineImportWorkerData, bool) (int, *model.AppError)) {
+		errLine, err := importFn(c, batch, opts.DryRun)
+		if err == nil {
+			return
+		}
+		if len(batch) == 1 {
+			errors <- LineImportWorkerError{err, batch[0].LineNumber}
+			return
+		}
+		if opts.OnLineError == nil {
+			errors <- LineImportWorkerError{err, errLine}
+			return
+		}
+		for _, line := range batch {
+			if _, err := importFn(c, []LineImportWorkerData{line}, opts.DryRun); err != nil {
+				errors <- LineImportWorkerError{err, line.LineNumber}
+			}
+		}
+	}
+
 	for line := range lines {
 		switch {
 		case line.LineImportData.Type == "post":
-			postLines = append(postLines, line)
 			if line.Post == nil {
 				errors <- LineImportWorkerError{model.NewAppError("BulkImport", "app.import.import_line.null_post.error", nil, "", http.StatusBadRequest), line.LineNumber}
+				continue
 			}
+			postLines = append(postLines, line)
 			if len(postLines) >= importMultiplePostsThreshold {
-				if errLine, err := a.importMultiplePostLines(c, postLines, dryRun); err != nil {
-					errors <- LineImportWorkerError{err, errLine}
-				}
+				importBatch(postLines, a.importMultiplePostLines)
 				postLines = []LineImportWorkerData{}
 			}
 		case line.LineImportData.Type == "direct_post":
-			directPostLines = append(directPostLines, line)
 			if line.DirectPost == nil {
 				errors <- LineImportWorkerError{model.NewAppError("BulkImport", "app.import.import_line.null_direct_post.error", nil, "", http.StatusBadRequest), line.LineNumber}
+				continue
 			}
+			directPostLines = append(directPostLines, line)
 			if len(directPostLines) >= importMultiplePostsThreshold {
-				if errLine, err := a.importMultipleDirectPostLines(c, directPostLines, dryRun); err != nil {
-					errors <- LineImportWorkerError{err, errLine}
-				}
+				importBatch(directPostLines, a.importMultipleDirectPostLines)
 				directPostLines = []LineImportWorkerData{}
 			}
 		default:
-			if err := a.importLine(c, line.LineImportData, dryRun); err != nil {
+			if err := a.importLine(c, line.LineImportData, opts.DryRun); err != nil {
 				errors <- LineImportWorkerError{err, line.LineNumber}
 			}
 		}
 	}
 
 	if len(postLines) > 0 {
-		if errLine, err := a.importMultiplePostLines(c, postLines, dryRun); err != nil {
-			errors <- LineImportWorkerError{err, errLine}
-		}
+		importBatch(postLines, a.importMultiplePostLines)
 	}
 	if len(directPostLines) > 0 {
-		if errLine, err := a.importMultipleDirectPostLines(c, directPostLines, dryRun); err != nil {
-			errors <- LineImportWorkerError{err, errLine}
-		}
+		importBatch(directPostLines, a.importMultipleDirectPostLines)
 	}
 	wg.Done()
 }
 
 func (a *App) BulkImport(c *request.Context, jsonlReader io.Reader, attachmentsReader *zip.Reader, dryRun bool, workers int) (*model.AppError, int) {
-	return a.bulkImport(c, jsonlReader, attachmentsReader, dryRun, workers, "")
+	return a.bulkImport(c, jsonlReader, attachmentsReader, model.BulkImportOpts{DryRun: dryRun, Workers: workers})
 }
 
 func (a *App) BulkImportWithPath(c *request.Context, jsonlReader io.Reader, attachmentsReader *zip.Reader, dryRun bool, workers int, importPath string) (*model.AppError, int) {
-	return a.bulkImport(c, jsonlReader, attachmentsReader, dryRun, workers, importPath)
+	return a.bulkImport(c, jsonlReader, attachmentsReader, model.BulkImportOpts{DryRun: dryRun, Workers: workers, ImportPath: importPath})
+}
+
+// BulkImportWithOpts imports the data file with the given options, which allow to
+// follow the progress of the import, to resume it and to go on past failing lines.
+func (a *App) BulkImportWithOpts(c *request.Context, jsonlReader io.Reader, attachmentsReader *zip.Reader, opts model.BulkImportOpts) (*model.AppError, int) {
+	return a.bulkImport(c, jsonlReader, attachmentsReader, opts)
 }
 
 // bulkImport will extract attachments from attachmentsReader if it is
 // not nil. If it is nil, it will look for attachments on the
 // filesystem in the locations specified by the JSONL file according
 // to the older behavior
-func (a *App) bulkImport(c *request.Context, jsonlReader io.Reader, attachmentsReader *zip.Reader, dryRun bool, workers int, importPath string) (*model.AppError, int) {
+func (a *App) bulkImport(c *request.Context, jsonlReader io.Reader, attachmentsReader *zip.Reader, opts model.BulkImportOpts) (*model.AppError, int) {
 	scanner := bufio.NewScanner(jsonlReader)
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, maxScanTokenSize)
 
 	lineNumber := 0
+	lastCheckpoint := 0
 
 	a.Srv().Store.LockToMaster()
 	defer a.Srv().Store.UnlockFromMaster()
 
+	workers := opts.Workers
 	errorsChan := make(chan LineImportWorkerError, (2*workers)+1) // size chosen to ensure it never gets filled up completely.
 	var wg sync.WaitGroup
 	var linesChan chan LineImportWorkerData
 	lastLineType := ""
 
+	// handleError returns whether the import has to stop on the error.
+	handleError := func(err LineImportWorkerError) bool {
+		if opts.OnLineError != nil {
+			opts.OnLineError(err.LineNumber, err.Error)
+			return false
+		}
+		return stopOnError(err)
+	}
+
+	// handleLineError handles the errors found before sending the line to the workers.
+	handleLineError := func(appErr *model.AppError) bool {
+		return handleError(LineImportWorkerError{appErr, lineNumber})
+	}
+
+	// flush clears out the worker queue, and returns the error the import has to stop on, if any.
+	flush := func() *LineImportWorkerError {
+		if linesChan == nil {
+			return nil
+		}
+		close(linesChan)
+		linesChan = nil
+
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		// Keep handling the errors while waiting for the queue to empty, as the
+		// workers block once the errors channel is full.
+		var stopErr *LineImportWorkerError
+		for {
+			select {
+			case err := <-errorsChan:
+				if stopErr == nil && handleError(err) {
+					stopErr = &err
+				}
+			case <-done:
+				for len(errorsChan) != 0 {
+					err := <-errorsChan
+					if stopErr == nil && handleError(err) {
+						stopErr = &err
+					}
+				}
+				return stopErr
+			}
+		}
+	}
+
 	var attachedFiles map[string]*zip.File
 	if attachmentsReader != nil {
 		attachedFiles = make(map[string]*zip.File, len(attachmentsReader.File))
@@ -191,16 +276,30 @@ func (a *App) bulkImport(c *request.Context, jsonlReader io.Reader, attachmentsR
 	}
 
 	for scanner.Scan() {
-		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
 		lineNumber++
 
-		var line LineImportData
+		// Skip the lines imported by a previous run.
+		if lineNumber > 1 && lineNumber <= opts.SkipLines {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+
+		var line model.LineImportData
 		if err := decoder.Decode(&line); err != nil {
-			return model.NewAppError("BulkImport", "app.import.bulk_import.json_decode.error", nil, err.Error(), http.StatusBadRequest), lineNumber
+			appErr := model.NewAppError("BulkImport", "app.import.bulk_import.json_decode.error", nil, err.Error(), http.StatusBadRequest)
+			if lineNumber == 1 || handleLineError(appErr) {
+				return appErr, lineNumber
+			}
+			continue
 		}
 
-		if err := processAttachments(&line, importPath, attachedFiles); err != nil {
-			return model.NewAppError("BulkImport", "app.import.bulk_import.process_attachments.error", nil, err.Error(), http.StatusBadRequest), lineNumber
+		if err := processAttachments(&line, opts.ImportPath, attachedFiles); err != nil {
+			appErr := model.NewAppError("BulkImport", "app.import.bulk_import.process_attachments.error", nil, err.Error(), http.StatusBadRequest)
+			if handleLineError(appErr) {
+				return appErr, lineNumber
+			}
+			continue
 		}
 
 		if lineNumber == 1 {
@@ -216,20 +315,16 @@ func (a *App) bulkImport(c *request.Context, jsonlReader io.Reader, attachmentsR
 			continue
 		}
 
-		if line.Type != lastLineType {
-			// Only clear the worker queue if is not the first data entry
-			if lineNumber != 2 {
-				// Changing type. Clear out the worker queue before continuing.
-				close(linesChan)
-				wg.Wait()
+		checkpoint := opts.CheckpointInterval > 0 && lineNumber-lastCheckpoint > opts.CheckpointInterval
+		if line.Type != lastLineType || checkpoint {
+			// Changing type or reaching a checkpoint. Clear out the worker queue before continuing.
+			if err := flush(); err != nil {
+				return err.Error, err.LineNumber
+			}
 
-				// Check no errors occurred while waiting for the queue to empty.
-				if len(errorsChan) != 0 {
-					err := <-errorsChan
-					if stopOnError(err) {
-						return err.Error, err.LineNumber
-					}
-				}
+			lastCheckpoint = lineNumber - 1
+			if opts.OnProgress != nil {
+				opts.OnProgress(lastCheckpoint)
 			}
 
 			// Set up the workers and channel for this type.
@@ -237,43 +332,40 @@ func (a *App) bulkImport(c *request.Context, jsonlReader io.Reader, attachmentsR
 			linesChan = make(chan LineImportWorkerData, workers)
 			for i := 0; i < workers; i++ {
 				wg.Add(1)
-				go a.bulkImportWorker(c, dryRun, &wg, linesChan, errorsChan)
+				go a.bulkImportWorker(c, opts, &wg, linesChan, errorsChan)
 			}
 		}
 
-		select {
-		case linesChan <- LineImportWorkerData{line, lineNumber}:
-		case err := <-errorsChan:
-			if stopOnError(err) {
-				close(linesChan)
-				wg.Wait()
-				return err.Error, err.LineNumber
+		for sent := false; !sent; {
+			select {
+			case linesChan <- LineImportWorkerData{line, lineNumber}:
+				sent = true
+			case err := <-errorsChan:
+				if handleError(err) {
+					flush()
+					return err.Error, err.LineNumber
+				}
 			}
 		}
 	}
 
 	// No more lines. Clear out the worker queue before continuing.
-	if linesChan != nil {
-		close(linesChan)
-	}
-	wg.Wait()
-
-	// Check no errors occurred while waiting for the queue to empty.
-	if len(errorsChan) != 0 {
-		err := <-errorsChan
-		if stopOnError(err) {
-			return err.Error, err.LineNumber
-		}
+	if err := flush(); err != nil {
+		return err.Error, err.LineNumber
 	}
 
 	if err := scanner.Err(); err != nil {
 		return model.NewAppError("BulkImport", "app.import.bulk_import.file_scan.error", nil, err.Error(), http.StatusInternalServerError), 0
 	}
 
+	if opts.OnProgress != nil {
+		opts.OnProgress(lineNumber)
+	}
+
 	return nil, 0
 }
 
-func processImportDataFileVersionLine(line LineImportData) (int, *model.AppError) {
+func processImportDataFileVersionLine(line model.LineImportData) (int, *model.AppError) {
 	if line.Type != "version" || line.Version == nil {
 		return -1, model.NewAppError("BulkImport", "app.import.process_import_data_file_version_line.invalid_version.error", nil, "", http.StatusBadRequest)
 	}
@@ -281,8 +373,13 @@ func processImportDataFileVersionLine(line LineImportData) (int, *model.AppError
 	return *line.Version, nil
 }
 
-func (a *App) importLine(c *request.Context, line LineImportData, dryRun bool) *model.AppError {
+func (a *App) importLine(c *request.Context, line model.LineImportData, dryRun bool) *model.AppError {
 	switch {
+	case line.Type == "role":
+		if line.Role == nil {
+			return model.NewAppError("BulkImport", "app.import.import_line.null_role.error", nil, "", http.StatusBadRequest)
+		}
+		return a.importRole(line.Role, dryRun, false)
 	case line.Type == "scheme":
 		if line.Scheme == nil {
 			return model.NewAppError("BulkImport", "app.import.import_line.null_scheme.error", nil, "", http.StatusBadRequest)
@@ -303,6 +400,11 @@ func (a *App) importLine(c *request.Context, line LineImportData, dryRun bool) *
 			return model.NewAppError("BulkImport", "app.import.import_line.null_user.error", nil, "", http.StatusBadRequest)
 		}
 		return a.importUser(line.User, dryRun)
+	case line.Type == "bot":
+		if line.Bot == nil {
+			return model.NewAppError("BulkImport", "app.import.import_line.null_bot.error", nil, "", http.StatusBadRequest)
+		}
+		return a.importBot(c, line.Bot, dryRun)
 	case line.Type == "direct_channel":
 		if line.DirectChannel == nil {
 			return model.NewAppError("BulkImport", "app.import.import_line.null_direct_channel.error", nil, "", http.StatusBadRequest)
@@ -313,6 +415,31 @@ func (a *App) importLine(c *request.Context, line LineImportData, dryRun bool) *
 			return model.NewAppError("BulkImport", "app.import.import_line.null_emoji.error", nil, "", http.StatusBadRequest)
 		}
 		return a.importEmoji(line.Emoji, dryRun)
+	case line.Type == "command":
+		if line.Command == nil {
+			return model.NewAppError("BulkImport", "app.import.import_line.null_command.error", nil, "", http.StatusBadRequest)
+		}
+		return a.importCommand(line.Command, dryRun)
+	case line.Type == "incoming_webhook":
+		if line.IncomingWebhook == nil {
+			return model.NewAppError("BulkImport", "app.import.import_line.null_incoming_webhook.error", nil, "", http.StatusBadRequest)
+		}
+		return a.importIncomingWebhook(line.IncomingWebhook, dryRun)
+	case line.Type == "outgoing_webhook":
+		if line.OutgoingWebhook == nil {
+			return model.NewAppError("BulkImport", "app.import.import_line.null_outgoing_webhook.error", nil, "", http.StatusBadRequest)
+		}
+		return a.importOutgoingWebhook(line.OutgoingWebhook, dryRun)
+	case line.Type == "oauth_app":
+		if line.OAuthApp == nil {
+			return model.NewAppError("BulkImport", "app.import.import_line.null_oauth_app.error", nil, "", http.StatusBadRequest)
+		}
+		return a.importOAuthApp(line.OAuthApp, dryRun)
+	case line.Type == "channel_bookmark":
+		if line.ChannelBookmark == nil {
+			return model.NewAppError("BulkImport", "app.import.import_line.null_channel_bookmark.error", nil, "", http.StatusBadRequest)
+		}
+		return a.importChannelBookmark(c, line.ChannelBookmark, dryRun)
 	default:
 		return model.NewAppError("BulkImport", "app.import.import_line.unknown_line_type.error", map[string]interface{}{"Type": line.Type}, "", http.StatusBadRequest)
 	}
@@ -334,3 +461,30 @@ func (a *App) ListImports() ([]string, *model.AppError) {
 
 	return results, nil
 }
+
+// CreateTeamImportJob saves the export file into the import directory and creates the
+// import job importing it into the team, so that the export is imported in the background.
+func (a *App) CreateTeamImportJob(fileData io.Reader, fileName, importFrom, teamID string) (*model.Job, *model.AppError) {
+	importFileName := model.NewId() + "_" + filepath.Base(fileName)
+	importFilePath := filepath.Join(*a.Config().ImportSettings.Directory, importFileName)
+	if _, appErr := a.WriteFile(fileData, importFilePath); appErr != nil {
+		return nil, appErr
+	}
+
+	job, appErr := a.CreateJob(&model.Job{
+		Type: model.JobTypeImportProcess,
+		Data: map[string]string{
+			"import_file": importFileName,
+			"import_from": importFrom,
+			"team_id":     teamID,
+		},
+	})
+	if appErr != nil {
+		if err := a.RemoveFile(importFilePath); err != nil {
+			mlog.Warn("Failed to remove the import file", mlog.String("path", importFilePath), mlog.Err(err))
+		}
+		return nil, appErr
+	}
+
+	return job, nil
+}
@@ -31,7 +31,7 @@ import (
 // still enforced.
 //
 
-func (a *App) importScheme(data *SchemeImportData, dryRun bool) *model.AppError {
+func (a *App) importScheme(data *model.SchemeImportData, dryRun bool) *model.AppError {
 	if err := validateSchemeImportData(data); err != nil {
 		return err
 	}
@@ -78,7 +78,7 @@ func (a *App) importScheme(data *SchemeImportData, dryRun bool) *model.AppError
 		}
 
 		if data.DefaultTeamGuestRole == nil {
-			data.DefaultTeamGuestRole = &RoleImportData{
+			data.DefaultTeamGuestRole = &model.RoleImportData{
 				DisplayName: model.NewString("Team Guest Role for Scheme"),
 			}
 		}
@@ -100,7 +100,7 @@ func (a *App) importScheme(data *SchemeImportData, dryRun bool) *model.AppError
 		}
 
 		if data.DefaultChannelGuestRole == nil {
-			data.DefaultChannelGuestRole = &RoleImportData{
+			data.DefaultChannelGuestRole = &model.RoleImportData{
 				DisplayName: model.NewString("Channel Guest Role for Scheme"),
 			}
 		}
@@ -113,7 +113,7 @@ func (a *App) importScheme(data *SchemeImportData, dryRun bool) *model.AppError
 	return nil
 }
 
-func (a *App) importRole(data *RoleImportData, dryRun bool, isSchemeRole bool) *model.AppError {
+func (a *App) importRole(data *model.RoleImportData, dryRun bool, isSchemeRole bool) *model.AppError {
 	if !isSchemeRole {
 		if err := validateRoleImportData(data); err != nil {
 			return err
@@ -159,7 +159,7 @@ func (a *App) importRole(data *RoleImportData, dryRun bool, isSchemeRole bool) *
 	return err
 }
 
-func (a *App) importTeam(c *request.Context, data *TeamImportData, dryRun bool) *model.AppError {
+func (a *App) importTeam(c *request.Context, data *model.TeamImportData, dryRun bool) *model.AppError {
 	if err := validateTeamImportData(data); err != nil {
 		return err
 	}
@@ -227,7 +227,7 @@ func (a *App) importTeam(c *request.Context, data *TeamImportData, dryRun bool)
 	return nil
 }
 
-func (a *App) importChannel(c *request.Context, data *ChannelImportData, dryRun bool) *model.AppError {
+func (a *App) importChannel(c *request.Context, data *model.ChannelImportData, dryRun bool) *model.AppError {
 	if err := validateChannelImportData(data); err != nil {
 		return err
 	}
@@ -292,7 +292,7 @@ func (a *App) importChannel(c *request.Context, data *ChannelImportData, dryRun
 	return nil
 }
 
-func (a *App) importUser(data *UserImportData, dryRun bool) *model.AppError {
+func (a *App) importUser(data *model.UserImportData, dryRun bool) *model.AppError {
 	if err := validateUserImportData(data); err != nil {
 		return err
 	}
@@ -349,6 +349,12 @@ func (a *App) importUser(data *UserImportData, dryRun bool) *model.AppError {
 	} else if data.Password != nil {
 		password = *data.Password
 		authData = nil
+	} else if user.Id != "" {
+		// An existing user keeps their credentials if no AuthData or Password is specified,
+		// so that importing the same user again does not lock them out.
+		authService = user.AuthService
+		authData = user.AuthData
+		hasUserAuthDataChanged = false
 	} else {
 		var err error
 		// If no AuthData or Password is specified, we must generate a password.
@@ -713,7 +719,7 @@ func (a *App) importUser(data *UserImportData, dryRun bool) *model.AppError {
 	return a.importUserTeams(savedUser, data.Teams)
 }
 
-func (a *App) importUserTeams(user *model.User, data *[]UserTeamImportData) *model.AppError {
+func (a *App) importUserTeams(user *model.User, data *[]model.UserTeamImportData) *model.AppError {
 	if data == nil {
 		return nil
 	}
@@ -728,7 +734,8 @@ func (a *App) importUserTeams(user *model.User, data *[]UserTeamImportData) *mod
 	}
 
 	teamThemePreferencesByID := map[string]model.Preferences{}
-	channels := map[string][]UserChannelImportData{}
+	channels := map[string][]model.UserChannelImportData{}
+	categories := map[string][]model.SidebarCategoryImportData{}
 	teamsByID := map[string]*model.Team{}
 	teamMemberByTeamID := map[string]*model.TeamMember{}
 	newTeamMembers := []*model.TeamMember{}
@@ -802,7 +809,10 @@ func (a *App) importUserTeams(user *model.User, data *[]UserTeamImportData) *mod
 			channels[team.Id] = append(channels[team.Id], *tdata.Channels...)
 		}
 		if !user.IsGuest() {
-			channels[team.Id] = append(channels[team.Id], UserChannelImportData{Name: model.NewString(model.DefaultChannelName)})
+			channels[team.Id] = append(channels[team.Id], model.UserChannelImportData{Name: model.NewString(model.DefaultChannelName)})
+		}
+		if tdata.Categories != nil {
+			categories[team.Id] = append(categories[team.Id], *tdata.Categories...)
 		}
 
 		teamsByID[team.Id] = team
@@ -867,12 +877,15 @@ func (a *App) importUserTeams(user *model.User, data *[]UserTeamImportData) *mod
 		if err := a.importUserChannels(user, team, &channelsToImport); err != nil {
 			return err
 		}
+		if err := a.importUserSidebarCategories(user, team, categories[team.Id]); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (a *App) importUserChannels(user *model.User, team *model.Team, data *[]UserChannelImportData) *model.AppError {
+func (a *App) importUserChannels(user *model.User, team *model.Team, data *[]model.UserChannelImportData) *model.AppError {
 	if data == nil {
 		return nil
 	}
@@ -1042,7 +1055,97 @@ func (a *App) importUserChannels(user *model.User, team *model.Team, data *[]Use
 	return nil
 }
 
-func (a *App) importReaction(data *ReactionImportData, post *model.Post) *model.AppError {
+// importUserSidebarCategories creates the custom sidebar categories of the user, or updates the
+// ones having the same display name. Channels the user isn't a member of are left out.
+func (a *App) importUserSidebarCategories(user *model.User, team *model.Team, data []model.SidebarCategoryImportData) *model.AppError {
+	if len(data) == 0 {
+		return nil
+	}
+
+	existingCategories, appErr := a.GetSidebarCategories(user.Id, team.Id)
+	if appErr != nil {
+		return appErr
+	}
+	customCategories := map[string]*model.SidebarCategoryWithChannels{}
+	for _, category := range existingCategories.Categories {
+		if category.Type == model.SidebarCategoryCustom {
+			customCategories[category.DisplayName] = category
+		}
+	}
+
+	members, nErr := a.Srv().Store.Channel().GetMembersForUser(team.Id, user.Id)
+	if nErr != nil {
+		return model.NewAppError("importUserSidebarCategories", "app.channel.get_members.app_error", nil, nErr.Error(), http.StatusInternalServerError)
+	}
+	isMember := map[string]bool{}
+	for _, member := range members {
+		isMember[member.ChannelId] = true
+	}
+
+	channelNames := []string{}
+	for _, cdata := range data {
+		if cdata.Channels != nil {
+			channelNames = append(channelNames, *cdata.Channels...)
+		}
+	}
+	allChannels, appErr := a.getChannelsByNames(channelNames, team.Id)
+	if appErr != nil {
+		return appErr
+	}
+
+	updatedCategories := []*model.SidebarCategoryWithChannels{}
+	for _, cdata := range data {
+		channelIds := []string{}
+		if cdata.Channels != nil {
+			for _, name := range *cdata.Channels {
+				if channel, ok := allChannels[name]; ok && isMember[channel.Id] {
+					channelIds = append(channelIds, channel.Id)
+				}
+			}
+		}
+
+		category, exists := customCategories[*cdata.DisplayName]
+		if !exists {
+			category = &model.SidebarCategoryWithChannels{
+				SidebarCategory: model.SidebarCategory{
+					UserId:      user.Id,
+					TeamId:      team.Id,
+					Type:        model.SidebarCategoryCustom,
+					DisplayName: *cdata.DisplayName,
+				},
+			}
+		}
+		category.Channels = channelIds
+
+		if cdata.Sorting != nil {
+			category.Sorting = model.SidebarCategorySorting(*cdata.Sorting)
+		}
+
+		if cdata.Muted != nil {
+			category.Muted = *cdata.Muted
+		}
+
+		if cdata.Collapsed != nil {
+			category.Collapsed = *cdata.Collapsed
+		}
+
+		if exists {
+			updatedCategories = append(updatedCategories, category)
+		} else if _, appErr := a.CreateSidebarCategory(user.Id, team.Id, category); appErr != nil {
+			return appErr
+		}
+	}
+
+	if len(updatedCategories) > 0 {
+		if _, appErr := a.UpdateSidebarCategories(user.Id, team.Id, updatedCategories); appErr != nil {
+			return appErr
+		}
+	}
+
+	return nil
+}
+
+func (a *App) importReaction(data *model.ReactionImportData, post *model.Post) *model.AppError {
 	if err := validateReactionImportData(data, post.CreateAt); err != nil {
 		return err
 	}
@@ -1072,7 +1175,102 @@ func (a *App) importReaction(data *ReactionImportData, post *model.Post) *model.
 	return nil
 }
 
-func (a *App) importReplies(c *request.Context, data []ReplyImportData, post *model.Post, teamID string) *model.AppError {
+// importEditHistory saves the revisions of the post which it does not have yet.
+func (a *App) importEditHistory(data []model.PostRevisionImportData, post *model.Post) *model.AppError {
+	existing, nErr := a.Srv().Store.PostRevision().GetForPost(post.Id)
+	if nErr != nil {
+		return model.NewAppError("importEditHistory", "app.post_revision.get_for_post.app_error", nil, nErr.Error(), http.StatusInternalServerError)
+	}
+	type revisionKey struct {
+		createAt int64
+		message  string
+	}
+	imported := make(map[revisionKey]bool, len(existing))
+	for _, revision := range existing {
+		imported[revisionKey{revision.CreateAt, revision.Message}] = true
+	}
+
+	for _, revisionData := range data {
+		revisionData := revisionData
+		if err := validatePostRevisionImportData(&revisionData, post.CreateAt, a.MaxPostSize()); err != nil {
+			return err
+		}
+		if imported[revisionKey{*revisionData.CreateAt, *revisionData.Message}] {
+			continue
+		}
+
+		revision := &model.PostRevision{
+			PostId:    post.Id,
+			ChannelId: post.ChannelId,
+			UserId:    post.UserId,
+			CreateAt:  *revisionData.CreateAt,
+			Message:   *revisionData.Message,
+		}
+		if revisionData.Props != nil {
+			revision.Props = *revisionData.Props
+		}
+		// The editor may not have been exported along with the post.
+		if revisionData.Editor != nil {
+			if editor, err := a.Srv().Store.User().GetByUsername(*revisionData.Editor); err == nil {
+				revision.EditorId = editor.Id
+			}
+		}
+
+		if _, nErr := a.Srv().Store.PostRevision().Save(revision); nErr != nil {
+			var appErr *model.AppError
+			switch {
+			case errors.As(nErr, &appErr):
+				return appErr
+			default:
+				return model.NewAppError("importEditHistory", "app.post_revision.save.app_error", nil, nErr.Error(), http.StatusInternalServerError)
+			}
+		}
+	}
+
+	return nil
+}
+
+// importPollVotes saves the votes for the poll of the post, replacing the votes of the same
+// users.
+func (a *App) importPollVotes(data []model.PollVoteImportData, post *model.Post) *model.AppError {
+	poll := post.GetPoll()
+	if poll == nil {
+		return model.NewAppError("BulkImport", "app.import.import_poll_votes.not_a_poll.error", nil, "", http.StatusBadRequest)
+	}
+
+	for _, voteData := range data {
+		voteData := voteData
+		if err := validatePollVoteImportData(&voteData, post.CreateAt); err != nil {
+			return err
+		}
+		if !poll.IsValidVote(*voteData.Options) {
+			return model.NewAppError("BulkImport", "app.import.import_poll_votes.invalid_vote.error", map[string]interface{}{"Username": *voteData.User}, "", http.StatusBadRequest)
+		}
+
+		user, nErr := a.Srv().Store.User().GetByUsername(*voteData.User)
+		if nErr != nil {
+			return model.NewAppError("BulkImport", "app.import.import_post.user_not_found.error", map[string]interface{}{"Username": *voteData.User}, nErr.Error(), http.StatusBadRequest)
+		}
+
+		votes := make([]*model.PollVote, 0, len(*voteData.Options))
+		for _, index := range *voteData.Options {
+			votes = append(votes, &model.PollVote{PostId: post.Id, UserId: user.Id, OptionIndex: index, CreateAt: *voteData.CreateAt})
+		}
+		if nErr := a.Srv().Store.PollVote().SetUserVotes(post.Id, user.Id, votes); nErr != nil {
+			var appErr *model.AppError
+			switch {
+			case errors.As(nErr, &appErr):
+				return appErr
+			default:
+				return model.NewAppError("importPollVotes", "app.poll.save_votes.app_error", nil, nErr.Error(), http.StatusInternalServerError)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *App) importReplies(c *request.Context, data []model.ReplyImportData, post *model.Post, teamID string) *model.AppError {
 	var err *model.AppError
 	usernames := []string{}
 	for _, replyData := range data {
@@ -1121,9 +1319,15 @@ func (a *App) importReplies(c *request.Context, data []ReplyImportData, post *mo
 		if replyData.Type != nil {
 			reply.Type = *replyData.Type
 		}
+		if replyData.Props != nil {
+			reply.Props = *replyData.Props
+		}
 		if replyData.EditAt != nil {
 			reply.EditAt = *replyData.EditAt
 		}
+		if replyData.IsPinned != nil {
+			reply.IsPinned = *replyData.IsPinned
+		}
 
 		fileIDs := a.uploadAttachments(c, replyData.Attachments, reply, teamID)
 		for _, fileID := range reply.FileIds {
@@ -1165,12 +1369,22 @@ func (a *App) importReplies(c *request.Context, data []ReplyImportData, post *mo
 
 	for _, postWithData := range postsWithData {
 		a.updateFileInfoWithPostId(postWithData.post)
+		if postWithData.replyData.EditHistory != nil {
+			if err := a.importEditHistory(*postWithData.replyData.EditHistory, postWithData.post); err != nil {
+				return err
+			}
+		}
+		if postWithData.replyData.PollVotes != nil {
+			if err := a.importPollVotes(*postWithData.replyData.PollVotes, postWithData.post); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
-func (a *App) importAttachment(c *request.Context, data *AttachmentImportData, post *model.Post, teamID string) (*model.FileInfo, *model.AppError) {
+func (a *App) importAttachment(c *request.Context, data *model.AttachmentImportData, post *model.Post, teamID string) (*model.FileInfo, *model.AppError) {
 	var (
 		name string
 		file io.Reader
@@ -1242,9 +1456,9 @@ func (a *App) importAttachment(c *request.Context, data *AttachmentImportData, p
 
 type postAndData struct {
 	post           *model.Post
-	postData       *PostImportData
-	directPostData *DirectPostImportData
-	replyData      *ReplyImportData
+	postData       *model.PostImportData
+	directPostData *model.DirectPostImportData
+	replyData      *model.ReplyImportData
 	team           *model.Team
 	lineNumber     int
 }
@@ -1294,7 +1508,7 @@ func (a *App) getChannelsByNames(names []string, teamID string) (map[string]*mod
 }
 
 // getChannelsForPosts returns map[teamName]map[channelName]*model.Channel
-func (a *App) getChannelsForPosts(teams map[string]*model.Team, data []*PostImportData) (map[string]map[string]*model.Channel, *model.AppError) {
+func (a *App) getChannelsForPosts(teams map[string]*model.Team, data []*model.PostImportData) (map[string]map[string]*model.Channel, *model.AppError) {
 	teamChannels := make(map[string]map[string]*model.Channel)
 	for _, postData := range data {
 		teamName := *postData.Team
@@ -1339,7 +1553,7 @@ func (a *App) importMultiplePostLines(c *request.Context, lines []LineImportWork
 
 	usernames := []string{}
 	teamNames := make([]string, len(lines))
-	postsData := make([]*PostImportData, len(lines))
+	postsData := make([]*model.PostImportData, len(lines))
 	for i, line := range lines {
 		usernames = append(usernames, *line.Post.User)
 		if line.Post.FlaggedBy != nil {
@@ -1404,6 +1618,9 @@ func (a *App) importMultiplePostLines(c *request.Context, lines []LineImportWork
 		if line.Post.EditAt != nil {
 			post.EditAt = *line.Post.EditAt
 		}
+		if line.Post.IsPinned != nil {
+			post.IsPinned = *line.Post.IsPinned
+		}
 		if line.Post.Props != nil {
 			post.Props = *line.Post.Props
 		}
@@ -1495,6 +1712,18 @@ func (a *App) importMultiplePostLines(c *request.Context, lines []LineImportWork
 			}
 		}
 
+		if postWithData.postData.EditHistory != nil {
+			if err := a.importEditHistory(*postWithData.postData.EditHistory, postWithData.post); err != nil {
+				return postWithData.lineNumber, err
+			}
+		}
+
+		if postWithData.postData.PollVotes != nil {
+			if err := a.importPollVotes(*postWithData.postData.PollVotes, postWithData.post); err != nil {
+				return postWithData.lineNumber, err
+			}
+		}
+
 		if postWithData.postData.Replies != nil && len(*postWithData.postData.Replies) > 0 {
 			err := a.importReplies(c, *postWithData.postData.Replies, postWithData.post, postWithData.team.Id)
 			if err != nil {
@@ -1507,7 +1736,7 @@ func (a *App) importMultiplePostLines(c *request.Context, lines []LineImportWork
 }
 
 // uploadAttachments imports new attachments and returns current attachments of the post as a map
-func (a *App) uploadAttachments(c *request.Context, attachments *[]AttachmentImportData, post *model.Post, teamID string) map[string]bool {
+func (a *App) uploadAttachments(c *request.Context, attachments *[]model.AttachmentImportData, post *model.Post, teamID string) map[string]bool {
 	if attachments == nil {
 		return nil
 	}
@@ -1539,7 +1768,7 @@ func (a *App) updateFileInfoWithPostId(post *model.Post) {
 		}
 	}
 }
-func (a *App) importDirectChannel(data *DirectChannelImportData, dryRun bool) *model.AppError {
+func (a *App) importDirectChannel(data *model.DirectChannelImportData, dryRun bool) *model.AppError {
 	var err *model.AppError
 	if err = validateDirectChannelImportData(data); err != nil {
 		return err
@@ -1712,6 +1941,9 @@ func (a *App) importMultipleDirectPostLines(c *request.Context, lines []LineImpo
 		if line.DirectPost.EditAt != nil {
 			post.EditAt = *line.DirectPost.EditAt
 		}
+		if line.DirectPost.IsPinned != nil {
+			post.IsPinned = *line.DirectPost.IsPinned
+		}
 		if line.DirectPost.Props != nil {
 			post.Props = *line.DirectPost.Props
 		}
@@ -1801,6 +2033,18 @@ func (a *App) importMultipleDirectPostLines(c *request.Context, lines []LineImpo
 			}
 		}
 
+		if postWithData.directPostData.EditHistory != nil {
+			if err := a.importEditHistory(*postWithData.directPostData.EditHistory, postWithData.post); err != nil {
+				return postWithData.lineNumber, err
+			}
+		}
+
+		if postWithData.directPostData.PollVotes != nil {
+			if err := a.importPollVotes(*postWithData.directPostData.PollVotes, postWithData.post); err != nil {
+				return postWithData.lineNumber, err
+			}
+		}
+
 		if postWithData.directPostData.Replies != nil {
 			if err := a.importReplies(c, *postWithData.directPostData.Replies, postWithData.post, "noteam"); err != nil {
 				return postWithData.lineNumber, err
@@ -1812,7 +2056,7 @@ func (a *App) importMultipleDirectPostLines(c *request.Context, lines []LineImpo
 	return 0, nil
 }
 
-func (a *App) importEmoji(data *EmojiImportData, dryRun bool) *model.AppError {
+func (a *App) importEmoji(data *model.EmojiImportData, dryRun bool) *model.AppError {
 	aerr := validateEmojiImportData(data)
 	if aerr != nil {
 		if aerr.Id == "model.emoji.system_emoji_name.app_error" {
@@ -1869,3 +2113,528 @@ func (a *App) importEmoji(data *EmojiImportData, dryRun bool) *model.AppError {
 
 	return nil
 }
+
+func (a *App) importBot(c *request.Context, data *model.BotImportData, dryRun bool) *model.AppError {
+	if err := validateBotImportData(data); err != nil {
+		return err
+	}
+
+	// If this is a Dry Run, do not continue any further.
+	if dryRun {
+		return nil
+	}
+
+	// The owner is either a user or a plugin.
+	ownerId := *data.Owner
+	if owner, err := a.Srv().Store.User().GetByUsername(*data.Owner); err == nil {
+		ownerId = owner.Id
+	}
+
+	bot := &model.Bot{
+		Username: strings.ToLower(*data.Username),
+		OwnerId:  ownerId,
+	}
+	if data.DisplayName != nil {
+		bot.DisplayName = *data.DisplayName
+	}
+	if data.Description != nil {
+		bot.Description = *data.Description
+	}
+
+	user, nErr := a.Srv().Store.User().GetByUsername(bot.Username)
+	if nErr != nil {
+		// The user is saved directly, so that no message is sent to the owner of the new bot.
+		if err := bot.IsValidCreate(); err != nil {
+			return err
+		}
+
+		if user, nErr = a.Srv().Store.User().Save(model.UserFromBot(bot)); nErr != nil {
+			var appErr *model.AppError
+			switch {
+			case errors.As(nErr, &appErr):
+				return appErr
+			default:
+				return model.NewAppError("importBot", "app.user.save.app_error", nil, nErr.Error(), http.StatusInternalServerError)
+			}
+		}
+		bot.UserId = user.Id
+
+		if _, nErr = a.Srv().Store.Bot().Save(bot); nErr != nil {
+			a.Srv().Store.User().PermanentDelete(user.Id)
+			var appErr *model.AppError
+			switch {
+			case errors.As(nErr, &appErr):
+				return appErr
+			default:
+				return model.NewAppError("importBot", "app.bot.createbot.internal_error", nil, nErr.Error(), http.StatusInternalServerError)
+			}
+		}
+	} else {
+		if !user.IsBot {
+			return model.NewAppError("BulkImport", "app.import.import_bot.user_not_bot.error", map[string]interface{}{"Username": user.Username}, "", http.StatusBadRequest)
+		}
+
+		existingBot, appErr := a.PatchBot(user.Id, &model.BotPatch{
+			DisplayName: &bot.DisplayName,
+			Description: &bot.Description,
+		})
+		if appErr != nil {
+			return appErr
+		}
+
+		if existingBot.OwnerId != bot.OwnerId {
+			if _, appErr := a.UpdateBotOwner(user.Id, bot.OwnerId); appErr != nil {
+				return appErr
+			}
+		}
+	}
+
+	if data.DeleteAt != nil {
+		active := *data.DeleteAt == 0
+		if active != (user.DeleteAt == 0) {
+			if _, appErr := a.UpdateBotActive(c, user.Id, active); appErr != nil {
+				return appErr
+			}
+		}
+	}
+
+	return a.importUserTeams(user, data.Teams)
+}
+
+func (a *App) importCommand(data *model.CommandImportData, dryRun bool) *model.AppError {
+	if err := validateCommandImportData(data); err != nil {
+		return err
+	}
+
+	// If this is a Dry Run, do not continue any further.
+	if dryRun {
+		return nil
+	}
+
+	team, err := a.Srv().Store.Team().GetByName(*data.Team)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_command.team_not_found.error", map[string]interface{}{"TeamName": *data.Team}, err.Error(), http.StatusBadRequest)
+	}
+
+	creator, err := a.Srv().Store.User().GetByUsername(*data.Creator)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_command.creator_not_found.error", map[string]interface{}{"Username": *data.Creator}, err.Error(), http.StatusBadRequest)
+	}
+
+	trigger := strings.ToLower(*data.Trigger)
+	command, err := a.Srv().Store.Command().GetByTrigger(team.Id, trigger)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if !errors.As(err, &nfErr) {
+			return model.NewAppError("importCommand", "app.command.getcommand.internal_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		command = &model.Command{TeamId: team.Id}
+	}
+
+	command.CreatorId = creator.Id
+	command.Trigger = trigger
+	command.URL = *data.URL
+	command.Method = *data.Method
+
+	if data.Token != nil {
+		command.Token = *data.Token
+	}
+
+	if data.Username != nil {
+		command.Username = *data.Username
+	}
+
+	if data.IconURL != nil {
+		command.IconURL = *data.IconURL
+	}
+
+	if data.AutoComplete != nil {
+		command.AutoComplete = *data.AutoComplete
+	}
+
+	if data.AutoCompleteDesc != nil {
+		command.AutoCompleteDesc = *data.AutoCompleteDesc
+	}
+
+	if data.AutoCompleteHint != nil {
+		command.AutoCompleteHint = *data.AutoCompleteHint
+	}
+
+	if data.DisplayName != nil {
+		command.DisplayName = *data.DisplayName
+	}
+
+	if data.Description != nil {
+		command.Description = *data.Description
+	}
+
+	if command.Id == "" {
+		_, err = a.Srv().Store.Command().Save(command)
+	} else {
+		command.UpdateAt = model.GetMillis()
+		_, err = a.Srv().Store.Command().Update(command)
+	}
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return appErr
+		default:
+			return model.NewAppError("importCommand", "app.command.createcommand.internal_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	return nil
+}
+
+func (a *App) importIncomingWebhook(data *model.IncomingWebhookImportData, dryRun bool) *model.AppError {
+	if err := validateIncomingWebhookImportData(data); err != nil {
+		return err
+	}
+
+	// If this is a Dry Run, do not continue any further.
+	if dryRun {
+		return nil
+	}
+
+	team, err := a.Srv().Store.Team().GetByName(*data.Team)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_incoming_webhook.team_not_found.error", map[string]interface{}{"TeamName": *data.Team}, err.Error(), http.StatusBadRequest)
+	}
+
+	channel, err := a.Srv().Store.Channel().GetByName(team.Id, *data.Channel, true)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_incoming_webhook.channel_not_found.error", map[string]interface{}{"ChannelName": *data.Channel}, err.Error(), http.StatusBadRequest)
+	}
+
+	creator, err := a.Srv().Store.User().GetByUsername(*data.Creator)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_incoming_webhook.creator_not_found.error", map[string]interface{}{"Username": *data.Creator}, err.Error(), http.StatusBadRequest)
+	}
+
+	// The webhooks have no natural key, so the ones of the channel with the same display
+	// name are taken as the same webhook.
+	hooks, err := a.Srv().Store.Webhook().GetIncomingByChannel(channel.Id)
+	if err != nil {
+		return model.NewAppError("importIncomingWebhook", "app.webhooks.get_incoming_by_channel.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	hook := &model.IncomingWebhook{}
+	for _, existingHook := range hooks {
+		if data.DisplayName != nil && existingHook.DisplayName == *data.DisplayName {
+			hook = existingHook
+			break
+		}
+	}
+
+	hook.UserId = creator.Id
+	hook.TeamId = team.Id
+	hook.ChannelId = channel.Id
+
+	if data.DisplayName != nil {
+		hook.DisplayName = *data.DisplayName
+	}
+
+	if data.Description != nil {
+		hook.Description = *data.Description
+	}
+
+	if data.Username != nil {
+		hook.Username = *data.Username
+	}
+
+	if data.IconURL != nil {
+		hook.IconURL = *data.IconURL
+	}
+
+	if data.ChannelLocked != nil {
+		hook.ChannelLocked = *data.ChannelLocked
+	}
+
+	if hook.Id == "" {
+		_, err = a.Srv().Store.Webhook().SaveIncoming(hook)
+	} else {
+		hook.UpdateAt = model.GetMillis()
+		_, err = a.Srv().Store.Webhook().UpdateIncoming(hook)
+		a.invalidateCacheForWebhook(hook.Id)
+	}
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return appErr
+		default:
+			return model.NewAppError("importIncomingWebhook", "app.webhooks.save_incoming.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	return nil
+}
+
+func (a *App) importOutgoingWebhook(data *model.OutgoingWebhookImportData, dryRun bool) *model.AppError {
+	if err := validateOutgoingWebhookImportData(data); err != nil {
+		return err
+	}
+
+	// If this is a Dry Run, do not continue any further.
+	if dryRun {
+		return nil
+	}
+
+	team, err := a.Srv().Store.Team().GetByName(*data.Team)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_outgoing_webhook.team_not_found.error", map[string]interface{}{"TeamName": *data.Team}, err.Error(), http.StatusBadRequest)
+	}
+
+	var channelId string
+	if data.Channel != nil {
+		channel, err := a.Srv().Store.Channel().GetByName(team.Id, *data.Channel, true)
+		if err != nil {
+			return model.NewAppError("BulkImport", "app.import.import_outgoing_webhook.channel_not_found.error", map[string]interface{}{"ChannelName": *data.Channel}, err.Error(), http.StatusBadRequest)
+		}
+		channelId = channel.Id
+	}
+
+	creator, err := a.Srv().Store.User().GetByUsername(*data.Creator)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_outgoing_webhook.creator_not_found.error", map[string]interface{}{"Username": *data.Creator}, err.Error(), http.StatusBadRequest)
+	}
+
+	// The webhooks have no natural key, so the one of the team with the same token, or
+	// else with the same display name, is taken as the same webhook.
+	hooks, err := a.Srv().Store.Webhook().GetOutgoingByTeam(team.Id, -1, -1)
+	if err != nil {
+		return model.NewAppError("importOutgoingWebhook", "app.webhooks.get_outgoing_by_team.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	hook := &model.OutgoingWebhook{}
+	for _, existingHook := range hooks {
+		if data.Token != nil && existingHook.Token == *data.Token {
+			hook = existingHook
+			break
+		}
+		if data.Token == nil && data.DisplayName != nil && existingHook.DisplayName == *data.DisplayName {
+			hook = existingHook
+			break
+		}
+	}
+
+	hook.CreatorId = creator.Id
+	hook.TeamId = team.Id
+	hook.ChannelId = channelId
+	hook.CallbackURLs = *data.CallbackURLs
+
+	if data.Token != nil {
+		hook.Token = *data.Token
+	}
+
+	if data.TriggerWords != nil {
+		hook.TriggerWords = *data.TriggerWords
+	}
+
+	if data.TriggerWhen != nil {
+		hook.TriggerWhen = *data.TriggerWhen
+	}
+
+	if data.DisplayName != nil {
+		hook.DisplayName = *data.DisplayName
+	}
+
+	if data.Description != nil {
+		hook.Description = *data.Description
+	}
+
+	if data.ContentType != nil {
+		hook.ContentType = *data.ContentType
+	}
+
+	if data.Username != nil {
+		hook.Username = *data.Username
+	}
+
+	if data.IconURL != nil {
+		hook.IconURL = *data.IconURL
+	}
+
+	if hook.Id == "" {
+		_, err = a.Srv().Store.Webhook().SaveOutgoing(hook)
+	} else {
+		hook.UpdateAt = model.GetMillis()
+		_, err = a.Srv().Store.Webhook().UpdateOutgoing(hook)
+	}
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return appErr
+		default:
+			return model.NewAppError("importOutgoingWebhook", "app.webhooks.save_outgoing.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	return nil
+}
+
+func (a *App) importOAuthApp(data *model.OAuthAppImportData, dryRun bool) *model.AppError {
+	if err := validateOAuthAppImportData(data); err != nil {
+		return err
+	}
+
+	// If this is a Dry Run, do not continue any further.
+	if dryRun {
+		return nil
+	}
+
+	creator, err := a.Srv().Store.User().GetByUsername(*data.Creator)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_oauth_app.creator_not_found.error", map[string]interface{}{"Username": *data.Creator}, err.Error(), http.StatusBadRequest)
+	}
+
+	// The apps of the creator with the same name are taken as the same app.
+	apps, err := a.Srv().Store.OAuth().GetAppByUser(creator.Id, 0, 10000)
+	if err != nil {
+		return model.NewAppError("importOAuthApp", "app.oauth.get_app_by_user.find.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	app := &model.OAuthApp{}
+	for _, existingApp := range apps {
+		if existingApp.Name == *data.Name {
+			app = existingApp
+			break
+		}
+	}
+
+	app.CreatorId = creator.Id
+	app.Name = *data.Name
+	app.CallbackUrls = *data.CallbackURLs
+	app.Homepage = *data.Homepage
+
+	if data.ClientSecret != nil {
+		app.ClientSecret = *data.ClientSecret
+	}
+
+	if data.Description != nil {
+		app.Description = *data.Description
+	}
+
+	if data.IconURL != nil {
+		app.IconURL = *data.IconURL
+	}
+
+	if data.IsTrusted != nil {
+		app.IsTrusted = *data.IsTrusted
+	}
+
+	if app.Id == "" {
+		_, err = a.Srv().Store.OAuth().SaveApp(app)
+	} else {
+		_, err = a.Srv().Store.OAuth().UpdateApp(app)
+	}
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return appErr
+		default:
+			return model.NewAppError("importOAuthApp", "app.oauth.save_app.save.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	return nil
+}
+
+func (a *App) importChannelBookmark(c *request.Context, data *model.ChannelBookmarkImportData, dryRun bool) *model.AppError {
+	if err := validateChannelBookmarkImportData(data); err != nil {
+		return err
+	}
+
+	// If this is a Dry Run, do not continue any further.
+	if dryRun {
+		return nil
+	}
+
+	team, err := a.Srv().Store.Team().GetByName(*data.Team)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_channel_bookmark.team_not_found.error", map[string]interface{}{"TeamName": *data.Team}, err.Error(), http.StatusBadRequest)
+	}
+
+	channel, err := a.Srv().Store.Channel().GetByName(team.Id, *data.Channel, true)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_channel_bookmark.channel_not_found.error", map[string]interface{}{"ChannelName": *data.Channel}, err.Error(), http.StatusBadRequest)
+	}
+
+	owner, err := a.Srv().Store.User().GetByUsername(*data.Owner)
+	if err != nil {
+		return model.NewAppError("BulkImport", "app.import.import_channel_bookmark.owner_not_found.error", map[string]interface{}{"Username": *data.Owner}, err.Error(), http.StatusBadRequest)
+	}
+
+	// The bookmarks have no natural key, so the ones of the channel with the same display
+	// name are taken as the same bookmark.
+	bookmarks, err := a.Srv().Store.ChannelBookmark().GetBookmarksForChannelSince(channel.Id, 0)
+	if err != nil {
+		return model.NewAppError("importChannelBookmark", "app.channel_bookmark.get_for_channel.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	bookmark := &model.ChannelBookmark{SortOrder: int64(len(bookmarks))}
+	for _, existingBookmark := range bookmarks {
+		if existingBookmark.DisplayName == *data.DisplayName {
+			bookmark = existingBookmark
+			break
+		}
+	}
+
+	bookmark.ChannelId = channel.Id
+	bookmark.OwnerId = owner.Id
+	bookmark.DisplayName = *data.DisplayName
+	bookmark.Type = model.ChannelBookmarkType(*data.Type)
+	bookmark.LinkUrl = ""
+
+	if data.LinkUrl != nil {
+		bookmark.LinkUrl = *data.LinkUrl
+	}
+
+	if data.ImageUrl != nil {
+		bookmark.ImageUrl = *data.ImageUrl
+	}
+
+	if data.Emoji != nil {
+		bookmark.Emoji = *data.Emoji
+	}
+
+	if data.SortOrder != nil {
+		bookmark.SortOrder = *data.SortOrder
+	}
+
+	if bookmark.Type == model.ChannelBookmarkFile {
+		// The file of an existing bookmark is kept when it has the same name.
+		var info *model.FileInfo
+		if bookmark.FileId != "" {
+			info, err = a.Srv().Store.FileInfo().Get(bookmark.FileId)
+			if err != nil || info.Name != path.Base(*data.File.Path) {
+				info = nil
+			}
+		}
+
+		if info == nil {
+			var appErr *model.AppError
+			pseudoPost := &model.Post{ChannelId: channel.Id, UserId: owner.Id, CreateAt: model.GetMillis()}
+			if info, appErr = a.importAttachment(c, data.File, pseudoPost, team.Id); appErr != nil {
+				return appErr
+			}
+		}
+		bookmark.FileId = info.Id
+	} else {
+		bookmark.FileId = ""
+	}
+
+	if bookmark.Id == "" {
+		_, err = a.Srv().Store.ChannelBookmark().Save(bookmark)
+	} else {
+		err = a.Srv().Store.ChannelBookmark().Update(bookmark)
+	}
+	if err != nil {
+		return channelBookmarkStoreError("importChannelBookmark", "app.channel_bookmark.save.app_error", err)
+	}
+
+	return nil
+}
@@ -32,30 +32,30 @@ func TestImportImportScheme(t *testing.T) {
 	}()
 
 	// Try importing an invalid scheme in dryRun mode.
-	data := SchemeImportData{
+	data := model.SchemeImportData{
 		Name:  ptrStr(model.NewId()),
 		Scope: ptrStr("team"),
-		DefaultTeamGuestRole: &RoleImportData{
+		DefaultTeamGuestRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultTeamUserRole: &RoleImportData{
+		DefaultTeamUserRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultTeamAdminRole: &RoleImportData{
+		DefaultTeamAdminRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultChannelGuestRole: &RoleImportData{
+		DefaultChannelGuestRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultChannelUserRole: &RoleImportData{
+		DefaultChannelUserRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultChannelAdminRole: &RoleImportData{
+		DefaultChannelAdminRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
@@ -227,22 +227,22 @@ func TestImportImportSchemeWithoutGuestRoles(t *testing.T) {
 	}()
 
 	// Try importing an invalid scheme in dryRun mode.
-	data := SchemeImportData{
+	data := model.SchemeImportData{
 		Name:  ptrStr(model.NewId()),
 		Scope: ptrStr("team"),
-		DefaultTeamUserRole: &RoleImportData{
+		DefaultTeamUserRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultTeamAdminRole: &RoleImportData{
+		DefaultTeamAdminRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultChannelUserRole: &RoleImportData{
+		DefaultChannelUserRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultChannelAdminRole: &RoleImportData{
+		DefaultChannelAdminRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
@@ -407,7 +407,7 @@ func TestImportImportRole(t *testing.T) {
 
 	// Try importing an invalid role in dryRun mode.
 	rid1 := model.NewId()
-	data := RoleImportData{
+	data := model.RoleImportData{
 		Name: &rid1,
 	}
 
@@ -472,7 +472,7 @@ func TestImportImportRole(t *testing.T) {
 	assert.True(t, role.SchemeManaged)
 
 	// Check that re-importing with only required fields doesn't update the others.
-	data2 := RoleImportData{
+	data2 := model.RoleImportData{
 		Name:        &rid1,
 		DisplayName: ptrStr("new display name again"),
 	}
@@ -509,7 +509,7 @@ func TestImportImportTeam(t *testing.T) {
 	teamsCount, err := th.App.Srv().Store.Team().AnalyticsTeamCount(nil)
 	require.NoError(t, err, "Failed to get team count.")
 
-	data := TeamImportData{
+	data := model.TeamImportData{
 		Name:            ptrStr(model.NewId()),
 		DisplayName:     ptrStr("Display Name"),
 		Type:            ptrStr("XYZ"),
@@ -597,7 +597,7 @@ func TestImportImportChannel(t *testing.T) {
 
 	// Import a Team.
 	teamName := model.NewRandomTeamName()
-	th.App.importTeam(th.Context, &TeamImportData{
+	th.App.importTeam(th.Context, &model.TeamImportData{
 		Name:        &teamName,
 		DisplayName: ptrStr("Display Name"),
 		Type:        ptrStr("O"),
@@ -611,7 +611,7 @@ func TestImportImportChannel(t *testing.T) {
 
 	// Do an invalid channel in dry-run mode.
 	chanOpen := model.ChannelTypeOpen
-	data := ChannelImportData{
+	data := model.ChannelImportData{
 		Team:        &teamName,
 		DisplayName: ptrStr("Display Name"),
 		Type:        &chanOpen,
@@ -715,7 +715,7 @@ func TestImportImportUser(t *testing.T) {
 	require.NoError(t, err, "Failed to get user count.")
 
 	// Do an invalid user in dry-run mode.
-	data := UserImportData{
+	data := model.UserImportData{
 		Username: ptrStr(model.NewId()),
 	}
 	err = th.App.importUser(&data, true)
@@ -730,7 +730,7 @@ func TestImportImportUser(t *testing.T) {
 	assert.Equal(t, userCount, userCount2, "Unexpected number of users")
 
 	// Do a valid user in dry-run mode.
-	data = UserImportData{
+	data = model.UserImportData{
 		Username: ptrStr(model.NewId()),
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}
@@ -746,7 +746,7 @@ func TestImportImportUser(t *testing.T) {
 	assert.Equal(t, userCount, userCount3, "Unexpected number of users")
 
 	// Do an invalid user in apply mode.
-	data = UserImportData{
+	data = model.UserImportData{
 		Username: ptrStr(model.NewId()),
 	}
 	err = th.App.importUser(&data, false)
@@ -763,7 +763,7 @@ func TestImportImportUser(t *testing.T) {
 	// Do a valid user in apply mode.
 	username := model.NewId()
 	testsDir, _ := fileutils.FindDir("tests")
-	data = UserImportData{
+	data = model.UserImportData{
 		ProfileImage: ptrStr(filepath.Join(testsDir, "test.png")),
 		Username:     &username,
 		Email:        ptrStr(model.NewId() + "@example.com"),
@@ -867,7 +867,7 @@ func TestImportImportUser(t *testing.T) {
 
 	// Test team and channel memberships
 	teamName := model.NewRandomTeamName()
-	th.App.importTeam(th.Context, &TeamImportData{
+	th.App.importTeam(th.Context, &model.TeamImportData{
 		Name:        &teamName,
 		DisplayName: ptrStr("Display Name"),
 		Type:        ptrStr("O"),
@@ -877,7 +877,7 @@ func TestImportImportUser(t *testing.T) {
 
 	channelName := model.NewId()
 	chanTypeOpen := model.ChannelTypeOpen
-	th.App.importChannel(th.Context, &ChannelImportData{
+	th.App.importChannel(th.Context, &model.ChannelImportData{
 		Team:        &teamName,
 		Name:        &channelName,
 		DisplayName: ptrStr("Display Name"),
@@ -887,7 +887,7 @@ func TestImportImportUser(t *testing.T) {
 	require.Nil(t, appErr, "Failed to get channel from database.")
 
 	username = model.NewId()
-	data = UserImportData{
+	data = model.UserImportData{
 		Username:  &username,
 		Email:     ptrStr(model.NewId() + "@example.com"),
 		Nickname:  ptrStr(model.NewId()),
@@ -904,10 +904,10 @@ func TestImportImportUser(t *testing.T) {
 	require.Nil(t, appErr, "Failed to get channel member count")
 
 	// Test with an invalid team & channel membership in dry-run mode.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Roles: ptrStr("invalid"),
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Roles: ptrStr("invalid"),
 				},
@@ -918,10 +918,10 @@ func TestImportImportUser(t *testing.T) {
 	assert.NotNil(t, appErr)
 
 	// Test with an unknown team name & invalid channel membership in dry-run mode.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Name: ptrStr(model.NewId()),
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Roles: ptrStr("invalid"),
 				},
@@ -932,10 +932,10 @@ func TestImportImportUser(t *testing.T) {
 	assert.NotNil(t, appErr)
 
 	// Test with a valid team & invalid channel membership in dry-run mode.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Name: &teamName,
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Roles: ptrStr("invalid"),
 				},
@@ -946,10 +946,10 @@ func TestImportImportUser(t *testing.T) {
 	assert.NotNil(t, appErr)
 
 	// Test with a valid team & unknown channel name in dry-run mode.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Name: &teamName,
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Name: ptrStr(model.NewId()),
 				},
@@ -960,10 +960,10 @@ func TestImportImportUser(t *testing.T) {
 	assert.Nil(t, appErr)
 
 	// Test with a valid team & valid channel name in dry-run mode.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Name: &teamName,
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Name: &channelName,
 				},
@@ -983,10 +983,10 @@ func TestImportImportUser(t *testing.T) {
 	require.Equal(t, channelMemberCount, cmc, "Number of channel members not as expected")
 
 	// Test with an invalid team & channel membership in apply mode.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Roles: ptrStr("invalid"),
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Roles: ptrStr("invalid"),
 				},
@@ -997,10 +997,10 @@ func TestImportImportUser(t *testing.T) {
 	assert.NotNil(t, appErr)
 
 	// Test with an unknown team name & invalid channel membership in apply mode.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Name: ptrStr(model.NewId()),
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Roles: ptrStr("invalid"),
 				},
@@ -1011,10 +1011,10 @@ func TestImportImportUser(t *testing.T) {
 	assert.NotNil(t, appErr)
 
 	// Test with a valid team & invalid channel membership in apply mode.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Name: &teamName,
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Roles: ptrStr("invalid"),
 				},
@@ -1034,10 +1034,10 @@ func TestImportImportUser(t *testing.T) {
 	require.Equal(t, channelMemberCount, cmc)
 
 	// Test with a valid team & unknown channel name in apply mode.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Name: &teamName,
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Name: ptrStr(model.NewId()),
 				},
@@ -1065,10 +1065,10 @@ func TestImportImportUser(t *testing.T) {
 	require.Equal(t, "team_user", teamMember.Roles)
 
 	// Test with a valid team & valid channel name in apply mode.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Name: &teamName,
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Name: &channelName,
 				},
@@ -1096,16 +1096,16 @@ func TestImportImportUser(t *testing.T) {
 	assert.Equal(t, "all", channelMember.NotifyProps[model.MarkUnreadNotifyProp])
 
 	// Test with the properties of the team and channel membership changed.
-	data.Teams = &[]UserTeamImportData{
+	data.Teams = &[]model.UserTeamImportData{
 		{
 			Name:  &teamName,
 			Theme: ptrStr(`{"awayIndicator":"#DBBD4E","buttonBg":"#23A1FF","buttonColor":"#FFFFFF","centerChannelBg":"#ffffff","centerChannelColor":"#333333","codeTheme":"github","image":"/static/files/a4a388b38b32678e83823ef1b3e17766.png","linkColor":"#2389d7","mentionBg":"#2389d7","mentionColor":"#ffffff","mentionHighlightBg":"#fff2bb","mentionHighlightLink":"#2f81b7","newMessageSeparator":"#FF8800","onlineIndicator":"#7DBE00","sidebarBg":"#fafafa","sidebarHeaderBg":"#3481B9","sidebarHeaderTextColor":"#ffffff","sidebarText":"#333333","sidebarTextActiveBorder":"#378FD2","sidebarTextActiveColor":"#111111","sidebarTextHoverBg":"#e6f2fa","sidebarUnreadText":"#333333","type":"Matterfoss"}`),
 			Roles: ptrStr("team_user team_admin"),
-			Channels: &[]UserChannelImportData{
+			Channels: &[]model.UserChannelImportData{
 				{
 					Name:  &channelName,
 					Roles: ptrStr("channel_user channel_admin"),
-					NotifyProps: &UserChannelNotifyPropsImportData{
+					NotifyProps: &model.UserChannelNotifyPropsImportData{
 						Desktop:    ptrStr(model.UserNotifyMention),
 						Mobile:     ptrStr(model.UserNotifyMention),
 						MarkUnread: ptrStr(model.UserNotifyMention),
@@ -1144,7 +1144,7 @@ func TestImportImportUser(t *testing.T) {
 
 	// Add a user with some preferences.
 	username = model.NewId()
-	data = UserImportData{
+	data = model.UserImportData{
 		Username:           &username,
 		Email:              ptrStr(model.NewId() + "@example.com"),
 		Theme:              ptrStr(`{"awayIndicator":"#DCBD4E","buttonBg":"#23A2FF","buttonColor":"#FFFFFF","centerChannelBg":"#ffffff","centerChannelColor":"#333333","codeTheme":"github","image":"/static/files/a4a388b38b32678e83823ef1b3e17766.png","linkColor":"#2389d7","mentionBg":"#2389d7","mentionColor":"#ffffff","mentionHighlightBg":"#fff2bb","mentionHighlightLink":"#2f81b7","newMessageSeparator":"#FF8800","onlineIndicator":"#7DBE00","sidebarBg":"#fafafa","sidebarHeaderBg":"#3481B9","sidebarHeaderTextColor":"#ffffff","sidebarText":"#333333","sidebarTextActiveBorder":"#378FD2","sidebarTextActiveColor":"#111111","sidebarTextHoverBg":"#e6f2fa","sidebarUnreadText":"#333333","type":"Matterfoss"}`),
@@ -1177,7 +1177,7 @@ func TestImportImportUser(t *testing.T) {
 	checkPreference(t, th.App, user.Id, model.PreferenceCategoryNotifications, model.PreferenceNameEmailInterval, "30")
 
 	// Change those preferences.
-	data = UserImportData{
+	data = model.UserImportData{
 		Username:           &username,
 		Email:              ptrStr(model.NewId() + "@example.com"),
 		Theme:              ptrStr(`{"awayIndicator":"#123456","buttonBg":"#23A2FF","buttonColor":"#FFFFFF","centerChannelBg":"#ffffff","centerChannelColor":"#333333","codeTheme":"github","image":"/static/files/a4a388b38b32678e83823ef1b3e17766.png","linkColor":"#2389d7","mentionBg":"#2389d7","mentionColor":"#ffffff","mentionHighlightBg":"#fff2bb","mentionHighlightLink":"#2f81b7","newMessageSeparator":"#FF8800","onlineIndicator":"#7DBE00","sidebarBg":"#fafafa","sidebarHeaderBg":"#3481B9","sidebarHeaderTextColor":"#ffffff","sidebarText":"#333333","sidebarTextActiveBorder":"#378FD2","sidebarTextActiveColor":"#111111","sidebarTextHoverBg":"#e6f2fa","sidebarUnreadText":"#333333","type":"Matterfoss"}`),
@@ -1201,7 +1201,7 @@ func TestImportImportUser(t *testing.T) {
 	checkPreference(t, th.App, user.Id, model.PreferenceCategoryNotifications, model.PreferenceNameEmailInterval, "3600")
 
 	// Set Notify Without mention keys
-	data.NotifyProps = &UserNotifyPropsImportData{
+	data.NotifyProps = &model.UserNotifyPropsImportData{
 		Desktop:          ptrStr(model.UserNotifyAll),
 		DesktopSound:     ptrStr("true"),
 		Email:            ptrStr("true"),
@@ -1226,7 +1226,7 @@ func TestImportImportUser(t *testing.T) {
 	checkNotifyProp(t, user, model.MentionKeysNotifyProp, "")
 
 	// Set Notify Props with Mention keys
-	data.NotifyProps = &UserNotifyPropsImportData{
+	data.NotifyProps = &model.UserNotifyPropsImportData{
 		Desktop:          ptrStr(model.UserNotifyAll),
 		DesktopSound:     ptrStr("true"),
 		Email:            ptrStr("true"),
@@ -1252,7 +1252,7 @@ func TestImportImportUser(t *testing.T) {
 	checkNotifyProp(t, user, model.MentionKeysNotifyProp, "valid,misc")
 
 	// Change Notify Props with mention keys
-	data.NotifyProps = &UserNotifyPropsImportData{
+	data.NotifyProps = &model.UserNotifyPropsImportData{
 		Desktop:          ptrStr(model.UserNotifyMention),
 		DesktopSound:     ptrStr("false"),
 		Email:            ptrStr("false"),
@@ -1278,7 +1278,7 @@ func TestImportImportUser(t *testing.T) {
 	checkNotifyProp(t, user, model.MentionKeysNotifyProp, "misc")
 
 	// Change Notify Props without mention keys
-	data.NotifyProps = &UserNotifyPropsImportData{
+	data.NotifyProps = &model.UserNotifyPropsImportData{
 		Desktop:          ptrStr(model.UserNotifyMention),
 		DesktopSound:     ptrStr("false"),
 		Email:            ptrStr("false"),
@@ -1304,11 +1304,11 @@ func TestImportImportUser(t *testing.T) {
 
 	// Check Notify Props get set on *create* user.
 	username = model.NewId()
-	data = UserImportData{
+	data = model.UserImportData{
 		Username: &username,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}
-	data.NotifyProps = &UserNotifyPropsImportData{
+	data.NotifyProps = &model.UserNotifyPropsImportData{
 		Desktop:          ptrStr(model.UserNotifyMention),
 		DesktopSound:     ptrStr("false"),
 		Email:            ptrStr("false"),
@@ -1345,31 +1345,31 @@ func TestImportImportUser(t *testing.T) {
 		th.App.Srv().Store.System().PermanentDeleteByName(model.MigrationKeyAdvancedPermissionsPhase2)
 	}()
 
-	teamSchemeData := &SchemeImportData{
+	teamSchemeData := &model.SchemeImportData{
 		Name:        ptrStr(model.NewId()),
 		DisplayName: ptrStr(model.NewId()),
 		Scope:       ptrStr("team"),
-		DefaultTeamGuestRole: &RoleImportData{
+		DefaultTeamGuestRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultTeamUserRole: &RoleImportData{
+		DefaultTeamUserRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultTeamAdminRole: &RoleImportData{
+		DefaultTeamAdminRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultChannelGuestRole: &RoleImportData{
+		DefaultChannelGuestRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultChannelUserRole: &RoleImportData{
+		DefaultChannelUserRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
-		DefaultChannelAdminRole: &RoleImportData{
+		DefaultChannelAdminRole: &model.RoleImportData{
 			Name:        ptrStr(model.NewId()),
 			DisplayName: ptrStr(model.NewId()),
 		},
@@ -1382,7 +1382,7 @@ func TestImportImportUser(t *testing.T) {
 	teamScheme, nErr := th.App.Srv().Store.Scheme().GetByName(*teamSchemeData.Name)
 	require.NoError(t, nErr, "Failed to import scheme")
 
-	teamData := &TeamImportData{
+	teamData := &model.TeamImportData{
 		Name:            ptrStr(model.NewId()),
 		DisplayName:     ptrStr("Display Name"),
 		Type:            ptrStr("O"),
@@ -1395,7 +1395,7 @@ func TestImportImportUser(t *testing.T) {
 	team, appErr = th.App.GetTeamByName(teamName)
 	require.Nil(t, appErr, "Failed to get team from database.")
 
-	channelData := &ChannelImportData{
+	channelData := &model.ChannelImportData{
 		Team:        &teamName,
 		Name:        ptrStr(model.NewId()),
 		DisplayName: ptrStr("Display Name"),
@@ -1409,14 +1409,14 @@ func TestImportImportUser(t *testing.T) {
 	require.Nil(t, appErr, "Failed to get channel from database")
 
 	// Test with a valid team & valid channel name in apply mode.
-	userData := &UserImportData{
+	userData := &model.UserImportData{
 		Username: &username,
 		Email:    ptrStr(model.NewId() + "@example.com"),
-		Teams: &[]UserTeamImportData{
+		Teams: &[]model.UserTeamImportData{
 			{
 				Name:  &team.Name,
 				Roles: ptrStr("team_user team_admin"),
-				Channels: &[]UserChannelImportData{
+				Channels: &[]model.UserChannelImportData{
 					{
 						Name:  &channel.Name,
 						Roles: ptrStr("channel_admin channel_user"),
@@ -1450,15 +1450,15 @@ func TestImportImportUser(t *testing.T) {
 	// Test importing deleted user with a valid team & valid channel name in apply mode.
 	username = model.NewId()
 	deleteAt := model.GetMillis()
-	deletedUserData := &UserImportData{
+	deletedUserData := &model.UserImportData{
 		Username: &username,
 		DeleteAt: &deleteAt,
 		Email:    ptrStr(model.NewId() + "@example.com"),
-		Teams: &[]UserTeamImportData{
+		Teams: &[]model.UserTeamImportData{
 			{
 				Name:  &team.Name,
 				Roles: ptrStr("team_user"),
-				Channels: &[]UserChannelImportData{
+				Channels: &[]model.UserChannelImportData{
 					{
 						Name:  &channel.Name,
 						Roles: ptrStr("channel_user"),
@@ -1492,15 +1492,15 @@ func TestImportImportUser(t *testing.T) {
 	// Test importing deleted guest with a valid team & valid channel name in apply mode.
 	username = model.NewId()
 	deleteAt = model.GetMillis()
-	deletedGuestData := &UserImportData{
+	deletedGuestData := &model.UserImportData{
 		Username: &username,
 		DeleteAt: &deleteAt,
 		Email:    ptrStr(model.NewId() + "@example.com"),
-		Teams: &[]UserTeamImportData{
+		Teams: &[]model.UserTeamImportData{
 			{
 				Name:  &team.Name,
 				Roles: ptrStr("team_guest"),
-				Channels: &[]UserChannelImportData{
+				Channels: &[]model.UserChannelImportData{
 					{
 						Name:  &channel.Name,
 						Roles: ptrStr("channel_guest"),
@@ -1543,7 +1543,7 @@ func TestImportUserTeams(t *testing.T) {
 
 	tt := []struct {
 		name                  string
-		data                  *[]UserTeamImportData
+		data                  *[]model.UserTeamImportData
 		expectedError         bool
 		expectedUserTeams     int
 		expectedUserChannels  int
@@ -1553,7 +1553,7 @@ func TestImportUserTeams(t *testing.T) {
 	}{
 		{
 			name: "Not existing team should fail",
-			data: &[]UserTeamImportData{
+			data: &[]model.UserTeamImportData{
 				{
 					Name: model.NewString("not-existing-team-name"),
 				},
@@ -1568,7 +1568,7 @@ func TestImportUserTeams(t *testing.T) {
 		},
 		{
 			name: "Should fail if one of the roles doesn't exists",
-			data: &[]UserTeamImportData{
+			data: &[]model.UserTeamImportData{
 				{
 					Name:  &th.BasicTeam.Name,
 					Roles: model.NewString("not-existing-role"),
@@ -1582,7 +1582,7 @@ func TestImportUserTeams(t *testing.T) {
 		},
 		{
 			name: "Should success to import explicit role",
-			data: &[]UserTeamImportData{
+			data: &[]model.UserTeamImportData{
 				{
 					Name:  &th.BasicTeam.Name,
 					Roles: &customRole.Name,
@@ -1596,7 +1596,7 @@ func TestImportUserTeams(t *testing.T) {
 		},
 		{
 			name: "Should success to import admin role",
-			data: &[]UserTeamImportData{
+			data: &[]model.UserTeamImportData{
 				{
 					Name:  &th.BasicTeam.Name,
 					Roles: model.NewString(model.TeamAdminRoleId),
@@ -1610,7 +1610,7 @@ func TestImportUserTeams(t *testing.T) {
 		},
 		{
 			name: "Should success to import with theme",
-			data: &[]UserTeamImportData{
+			data: &[]model.UserTeamImportData{
 				{
 					Name:  &th.BasicTeam.Name,
 					Theme: &sampleTheme,
@@ -1625,7 +1625,7 @@ func TestImportUserTeams(t *testing.T) {
 		},
 		{
 			name: "Team without channels must add the default channel",
-			data: &[]UserTeamImportData{
+			data: &[]model.UserTeamImportData{
 				{
 					Name: &th.BasicTeam.Name,
 				},
@@ -1638,10 +1638,10 @@ func TestImportUserTeams(t *testing.T) {
 		},
 		{
 			name: "Team with default channel must add only the default channel",
-			data: &[]UserTeamImportData{
+			data: &[]model.UserTeamImportData{
 				{
 					Name: &th.BasicTeam.Name,
-					Channels: &[]UserChannelImportData{
+					Channels: &[]model.UserChannelImportData{
 						{
 							Name: ptrStr(model.DefaultChannelName),
 						},
@@ -1656,10 +1656,10 @@ func TestImportUserTeams(t *testing.T) {
 		},
 		{
 			name: "Team with non default channel must add default channel and the other channel",
-			data: &[]UserTeamImportData{
+			data: &[]model.UserTeamImportData{
 				{
 					Name: &th.BasicTeam.Name,
-					Channels: &[]UserChannelImportData{
+					Channels: &[]model.UserChannelImportData{
 						{
 							Name: &th.BasicChannel.Name,
 						},
@@ -1674,10 +1674,10 @@ func TestImportUserTeams(t *testing.T) {
 		},
 		{
 			name: "Multiple teams with multiple channels each",
-			data: &[]UserTeamImportData{
+			data: &[]model.UserTeamImportData{
 				{
 					Name: &th.BasicTeam.Name,
-					Channels: &[]UserChannelImportData{
+					Channels: &[]model.UserChannelImportData{
 						{
 							Name: &th.BasicChannel.Name,
 						},
@@ -1688,7 +1688,7 @@ func TestImportUserTeams(t *testing.T) {
 				},
 				{
 					Name: &team2.Name,
-					Channels: &[]UserChannelImportData{
+					Channels: &[]model.UserChannelImportData{
 						{
 							Name: &channel3.Name,
 						},
@@ -1742,7 +1742,7 @@ func TestImportUserTeams(t *testing.T) {
 
 	t.Run("Should fail if the MaxUserPerTeam is reached", func(t *testing.T) {
 		user := th.CreateUser()
-		data := &[]UserTeamImportData{
+		data := &[]model.UserTeamImportData{
 			{
 				Name: &th.BasicTeam.Name,
 			},
@@ -1759,7 +1759,7 @@ func TestImportUserChannels(t *testing.T) {
 	defer th.TearDown()
 	channel2 := th.CreateChannel(th.BasicTeam)
 	customRole := th.CreateRole("test_custom_role")
-	sampleNotifyProps := UserChannelNotifyPropsImportData{
+	sampleNotifyProps := model.UserChannelNotifyPropsImportData{
 		Desktop:    model.NewString("all"),
 		Mobile:     model.NewString("none"),
 		MarkUnread: model.NewString("all"),
@@ -1767,16 +1767,16 @@ func TestImportUserChannels(t *testing.T) {
 
 	tt := []struct {
 		name                  string
-		data                  *[]UserChannelImportData
+		data                  *[]model.UserChannelImportData
 		expectedError         bool
 		expectedUserChannels  int
 		expectedExplicitRoles string
 		expectedRoles         string
-		expectedNotifyProps   *UserChannelNotifyPropsImportData
+		expectedNotifyProps   *model.UserChannelNotifyPropsImportData
 	}{
 		{
 			name: "Not existing channel should fail",
-			data: &[]UserChannelImportData{
+			data: &[]model.UserChannelImportData{
 				{
 					Name: model.NewString("not-existing-channel-name"),
 				},
@@ -1790,7 +1790,7 @@ func TestImportUserChannels(t *testing.T) {
 		},
 		{
 			name: "Should fail if one of the roles doesn't exists",
-			data: &[]UserChannelImportData{
+			data: &[]model.UserChannelImportData{
 				{
 					Name:  &th.BasicChannel.Name,
 					Roles: model.NewString("not-existing-role"),
@@ -1803,7 +1803,7 @@ func TestImportUserChannels(t *testing.T) {
 		},
 		{
 			name: "Should success to import explicit role",
-			data: &[]UserChannelImportData{
+			data: &[]model.UserChannelImportData{
 				{
 					Name:  &th.BasicChannel.Name,
 					Roles: &customRole.Name,
@@ -1816,7 +1816,7 @@ func TestImportUserChannels(t *testing.T) {
 		},
 		{
 			name: "Should success to import admin role",
-			data: &[]UserChannelImportData{
+			data: &[]model.UserChannelImportData{
 				{
 					Name:  &th.BasicChannel.Name,
 					Roles: model.NewString(model.ChannelAdminRoleId),
@@ -1829,7 +1829,7 @@ func TestImportUserChannels(t *testing.T) {
 		},
 		{
 			name: "Should success to import with notifyProps",
-			data: &[]UserChannelImportData{
+			data: &[]model.UserChannelImportData{
 				{
 					Name:        &th.BasicChannel.Name,
 					NotifyProps: &sampleNotifyProps,
@@ -1843,7 +1843,7 @@ func TestImportUserChannels(t *testing.T) {
 		},
 		{
 			name: "Should import properly multiple channels",
-			data: &[]UserChannelImportData{
+			data: &[]model.UserChannelImportData{
 				{
 					Name: &th.BasicChannel.Name,
 				},
@@ -1894,10 +1894,10 @@ func TestImportUserDefaultNotifyProps(t *testing.T) {
 
 	// Create a valid new user with some, but not all, notify props populated.
 	username := model.NewId()
-	data := UserImportData{
+	data := model.UserImportData{
 		Username: &username,
 		Email:    ptrStr(model.NewId() + "@example.com"),
-		NotifyProps: &UserNotifyPropsImportData{
+		NotifyProps: &model.UserNotifyPropsImportData{
 			Email:       ptrStr("false"),
 			MentionKeys: ptrStr(""),
 		},
@@ -1933,7 +1933,7 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Create a Team.
 	teamName := model.NewRandomTeamName()
-	th.App.importTeam(th.Context, &TeamImportData{
+	th.App.importTeam(th.Context, &model.TeamImportData{
 		Name:        &teamName,
 		DisplayName: ptrStr("Display Name"),
 		Type:        ptrStr("O"),
@@ -1944,7 +1944,7 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 	// Create a Channel.
 	channelName := model.NewId()
 	chanTypeOpen := model.ChannelTypeOpen
-	th.App.importChannel(th.Context, &ChannelImportData{
+	th.App.importChannel(th.Context, &model.ChannelImportData{
 		Team:        &teamName,
 		Name:        &channelName,
 		DisplayName: ptrStr("Display Name"),
@@ -1955,7 +1955,7 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Create a user.
 	username := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -1968,8 +1968,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Try adding an invalid post in dry run mode.
 	data := LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:    &teamName,
 				Channel: &channelName,
 				User:    &username,
@@ -1984,8 +1984,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Try adding a valid post in dry run mode.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
@@ -2002,8 +2002,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Try adding an invalid post in apply mode.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
@@ -2019,8 +2019,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Try adding a valid post with invalid team in apply mode.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     ptrStr(model.NewId()),
 				Channel:  &channelName,
 				User:     &username,
@@ -2039,8 +2039,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Try adding a valid post with invalid channel in apply mode.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  ptrStr(model.NewId()),
 				User:     &username,
@@ -2059,8 +2059,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Try adding a valid post with invalid user in apply mode.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     ptrStr(model.NewId()),
@@ -2080,8 +2080,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 	// Try adding a valid post in apply mode.
 	time := model.GetMillis()
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
@@ -2108,8 +2108,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Update the post.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
@@ -2137,8 +2137,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 	// Save the post with a different time.
 	newTime := time + 1
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
@@ -2155,8 +2155,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Save the post with a different message.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
@@ -2174,8 +2174,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 	// Test with hashtags
 	hashtagTime := time + 2
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
@@ -2203,7 +2203,7 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Post with flags.
 	username2 := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username2,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -2212,8 +2212,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	flagsTime := hashtagTime + 1
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
@@ -2251,14 +2251,14 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 	reactionPostTime := hashtagTime + 2
 	reactionTime := hashtagTime + 3
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
 				Message:  ptrStr("Message with reaction"),
 				CreateAt: &reactionPostTime,
-				Reactions: &[]ReactionImportData{{
+				Reactions: &[]model.ReactionImportData{{
 					User:      &user2.Username,
 					EmojiName: ptrStr("+1"),
 					CreateAt:  &reactionTime,
@@ -2292,14 +2292,14 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 	replyPostTime := hashtagTime + 4
 	replyTime := hashtagTime + 5
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
 				Message:  ptrStr("Message with reply"),
 				CreateAt: &replyPostTime,
-				Replies: &[]ReplyImportData{{
+				Replies: &[]model.ReplyImportData{{
 					User:     &user2.Username,
 					Message:  ptrStr("Message reply"),
 					CreateAt: &replyTime,
@@ -2338,14 +2338,14 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Update post with replies.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &user2.Username,
 				Message:  ptrStr("Message with reply"),
 				CreateAt: &replyPostTime,
-				Replies: &[]ReplyImportData{{
+				Replies: &[]model.ReplyImportData{{
 					User:     &username,
 					Message:  ptrStr("Message reply"),
 					CreateAt: &replyTime,
@@ -2362,14 +2362,14 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Create new post with replies based on the previous one.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &user2.Username,
 				Message:  ptrStr("Message with reply 2"),
 				CreateAt: &replyPostTime,
-				Replies: &[]ReplyImportData{{
+				Replies: &[]model.ReplyImportData{{
 					User:     &username,
 					Message:  ptrStr("Message reply"),
 					CreateAt: &replyTime,
@@ -2386,14 +2386,14 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Create new reply for existing post with replies.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &user2.Username,
 				Message:  ptrStr("Message with reply"),
 				CreateAt: &replyPostTime,
-				Replies: &[]ReplyImportData{{
+				Replies: &[]model.ReplyImportData{{
 					User:     &username,
 					Message:  ptrStr("Message reply 2"),
 					CreateAt: &replyTime,
@@ -2416,14 +2416,14 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 	editedReplyEditTime := hashtagTime + 8
 
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &user2.Username,
 				Message:  ptrStr("Message with reply"),
 				CreateAt: &editedReplyPostTime,
-				Replies: &[]ReplyImportData{{
+				Replies: &[]model.ReplyImportData{{
 					User:     &username,
 					Type:     ptrStr(model.PostTypeSystemGeneric),
 					Message:  ptrStr("Message reply 3"),
@@ -2450,7 +2450,7 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Create another Team.
 	teamName2 := model.NewRandomTeamName()
-	th.App.importTeam(th.Context, &TeamImportData{
+	th.App.importTeam(th.Context, &model.TeamImportData{
 		Name:        &teamName2,
 		DisplayName: ptrStr("Display Name 2"),
 		Type:        ptrStr("O"),
@@ -2459,7 +2459,7 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 	require.Nil(t, err, "Failed to get team from database.")
 
 	// Create another Channel for the another team.
-	th.App.importChannel(th.Context, &ChannelImportData{
+	th.App.importChannel(th.Context, &model.ChannelImportData{
 		Team:        &teamName2,
 		Name:        &channelName,
 		DisplayName: ptrStr("Display Name"),
@@ -2474,8 +2474,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 
 	// Try adding two valid posts in apply mode.
 	data = LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName,
 				Channel:  &channelName,
 				User:     &username,
@@ -2486,8 +2486,8 @@ func TestImportimportMultiplePostLines(t *testing.T) {
 		1,
 	}
 	data2 := LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:     &teamName2,
 				Channel:  &channelName,
 				User:     &username,
@@ -2512,7 +2512,7 @@ func TestImportImportPost(t *testing.T) {
 
 	// Create a Team.
 	teamName := model.NewRandomTeamName()
-	th.App.importTeam(th.Context, &TeamImportData{
+	th.App.importTeam(th.Context, &model.TeamImportData{
 		Name:        &teamName,
 		DisplayName: ptrStr("Display Name"),
 		Type:        ptrStr("O"),
@@ -2523,7 +2523,7 @@ func TestImportImportPost(t *testing.T) {
 	// Create a Channel.
 	channelName := model.NewId()
 	chanTypeOpen := model.ChannelTypeOpen
-	th.App.importChannel(th.Context, &ChannelImportData{
+	th.App.importChannel(th.Context, &model.ChannelImportData{
 		Team:        &teamName,
 		Name:        &channelName,
 		DisplayName: ptrStr("Display Name"),
@@ -2534,7 +2534,7 @@ func TestImportImportPost(t *testing.T) {
 
 	// Create a user.
 	username := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -2542,7 +2542,7 @@ func TestImportImportPost(t *testing.T) {
 	require.Nil(t, appErr, "Failed to get user from database.")
 
 	username2 := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username2,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -2563,8 +2563,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Try adding an invalid post in dry run mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:    &teamName,
 					Channel: &channelName,
 					User:    &username,
@@ -2580,8 +2580,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Try adding a valid post in dry run mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
@@ -2599,8 +2599,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Try adding an invalid post in apply mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
@@ -2617,8 +2617,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Try adding a valid post with invalid team in apply mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     ptrStr(model.NewId()),
 					Channel:  &channelName,
 					User:     &username,
@@ -2636,8 +2636,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Try adding a valid post with invalid channel in apply mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  ptrStr(model.NewId()),
 					User:     &username,
@@ -2655,8 +2655,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Try adding a valid post with invalid user in apply mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     ptrStr(model.NewId()),
@@ -2674,8 +2674,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Try adding a valid post in apply mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
@@ -2703,8 +2703,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Update the post", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username2,
@@ -2733,8 +2733,8 @@ func TestImportImportPost(t *testing.T) {
 	t.Run("Save the post with a different time", func(t *testing.T) {
 		newTime := time + 1
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
@@ -2752,8 +2752,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Save the post with a different message", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
@@ -2771,8 +2771,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Test with hashtag", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
@@ -2802,8 +2802,8 @@ func TestImportImportPost(t *testing.T) {
 	t.Run("Post with flags", func(t *testing.T) {
 		flagsTime := hashtagTime + 1
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
@@ -2842,14 +2842,14 @@ func TestImportImportPost(t *testing.T) {
 		reactionPostTime := hashtagTime + 2
 		reactionTime := hashtagTime + 3
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
 					Message:  ptrStr("Message with reaction"),
 					CreateAt: &reactionPostTime,
-					Reactions: &[]ReactionImportData{{
+					Reactions: &[]model.ReactionImportData{{
 						User:      &user2.Username,
 						EmojiName: ptrStr("+1"),
 						CreateAt:  &reactionTime,
@@ -2882,14 +2882,14 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Post with reply", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
 					Message:  ptrStr("Message with reply"),
 					CreateAt: &replyPostTime,
-					Replies: &[]ReplyImportData{{
+					Replies: &[]model.ReplyImportData{{
 						User:     &user2.Username,
 						Message:  ptrStr("Message reply"),
 						CreateAt: &replyTime,
@@ -2929,14 +2929,14 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Update post with replies", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &user2.Username,
 					Message:  ptrStr("Message with reply"),
 					CreateAt: &replyPostTime,
-					Replies: &[]ReplyImportData{{
+					Replies: &[]model.ReplyImportData{{
 						User:     &username,
 						Message:  ptrStr("Message reply"),
 						CreateAt: &replyTime,
@@ -2954,14 +2954,14 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Create new post with replies based on the previous one", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &user2.Username,
 					Message:  ptrStr("Message with reply 2"),
 					CreateAt: &replyPostTime,
-					Replies: &[]ReplyImportData{{
+					Replies: &[]model.ReplyImportData{{
 						User:     &username,
 						Message:  ptrStr("Message reply"),
 						CreateAt: &replyTime,
@@ -2979,14 +2979,14 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Create new reply for existing post with replies", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &user2.Username,
 					Message:  ptrStr("Message with reply"),
 					CreateAt: &replyPostTime,
-					Replies: &[]ReplyImportData{{
+					Replies: &[]model.ReplyImportData{{
 						User:     &username,
 						Message:  ptrStr("Message reply 2"),
 						CreateAt: &replyTime,
@@ -3004,8 +3004,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Post with Type", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
@@ -3036,8 +3036,8 @@ func TestImportImportPost(t *testing.T) {
 
 	t.Run("Post with EditAt", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				Post: &PostImportData{
+			model.LineImportData{
+				Post: &model.PostImportData{
 					Team:     &teamName,
 					Channel:  &channelName,
 					User:     &username,
@@ -3079,7 +3079,7 @@ func TestImportImportDirectChannel(t *testing.T) {
 	require.NoError(t, err, "Failed to get group channel count.")
 
 	// Do an invalid channel in dry-run mode.
-	data := DirectChannelImportData{
+	data := model.DirectChannelImportData{
 		Members: &[]string{
 			model.NewId(),
 		},
@@ -3240,7 +3240,7 @@ func TestImportImportDirectPost(t *testing.T) {
 	defer th.TearDown()
 
 	// Create the DIRECT channel.
-	channelData := DirectChannelImportData{
+	channelData := model.DirectChannelImportData{
 		Members: &[]string{
 			th.BasicUser.Username,
 			th.BasicUser2.Username,
@@ -3267,8 +3267,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Try adding an invalid post in dry run mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3287,8 +3287,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Try adding a valid post in dry run mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3308,8 +3308,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Try adding an invalid post in apply mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						model.NewId(),
@@ -3329,8 +3329,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Try adding a valid post in apply mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3360,8 +3360,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Import the post again", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3391,8 +3391,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Save the post with a different time", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3412,8 +3412,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Save the post with a different message", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3433,8 +3433,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Test with hashtag", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3464,8 +3464,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Test with some flags", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3499,8 +3499,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Test with Type", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3531,8 +3531,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Test with EditAt", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3565,7 +3565,7 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	// Create the GROUP channel.
 	user3 := th.CreateUser()
-	channelData = DirectChannelImportData{
+	channelData = model.DirectChannelImportData{
 		Members: &[]string{
 			th.BasicUser.Username,
 			th.BasicUser2.Username,
@@ -3593,8 +3593,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Try adding an invalid post in dry run mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3614,8 +3614,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Try adding a valid post in dry run mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3636,8 +3636,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Try adding an invalid post in apply mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3659,8 +3659,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Try adding a valid post in apply mode", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3691,8 +3691,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Import the post again", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3723,8 +3723,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Save the post with a different time", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3745,8 +3745,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Save the post with a different message", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3767,8 +3767,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Test with hashtag", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3799,8 +3799,8 @@ func TestImportImportDirectPost(t *testing.T) {
 
 	t.Run("Test with some flags", func(t *testing.T) {
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3838,8 +3838,8 @@ func TestImportImportDirectPost(t *testing.T) {
 		reactionPostTime := ptrInt64(initialDate + 22)
 		reactionTime := ptrInt64(initialDate + 23)
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3848,7 +3848,7 @@ func TestImportImportDirectPost(t *testing.T) {
 					User:     ptrStr(th.BasicUser.Username),
 					Message:  ptrStr("Message with reaction"),
 					CreateAt: reactionPostTime,
-					Reactions: &[]ReactionImportData{{
+					Reactions: &[]model.ReactionImportData{{
 						User:      ptrStr(th.BasicUser2.Username),
 						EmojiName: ptrStr("+1"),
 						CreateAt:  reactionTime,
@@ -3883,8 +3883,8 @@ func TestImportImportDirectPost(t *testing.T) {
 		replyPostTime := ptrInt64(initialDate + 25)
 		replyTime := ptrInt64(initialDate + 26)
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3893,7 +3893,7 @@ func TestImportImportDirectPost(t *testing.T) {
 					User:     ptrStr(th.BasicUser.Username),
 					Message:  ptrStr("Message with reply"),
 					CreateAt: replyPostTime,
-					Replies: &[]ReplyImportData{{
+					Replies: &[]model.ReplyImportData{{
 						User:     ptrStr(th.BasicUser2.Username),
 						Message:  ptrStr("Message reply"),
 						CreateAt: replyTime,
@@ -3935,8 +3935,8 @@ func TestImportImportDirectPost(t *testing.T) {
 		replyPostTime := ptrInt64(initialDate + 25)
 		replyTime := ptrInt64(initialDate + 26)
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3945,7 +3945,7 @@ func TestImportImportDirectPost(t *testing.T) {
 					User:     ptrStr(th.BasicUser2.Username),
 					Message:  ptrStr("Message with reply"),
 					CreateAt: replyPostTime,
-					Replies: &[]ReplyImportData{{
+					Replies: &[]model.ReplyImportData{{
 						User:     ptrStr(th.BasicUser.Username),
 						Message:  ptrStr("Message reply"),
 						CreateAt: replyTime,
@@ -3965,8 +3965,8 @@ func TestImportImportDirectPost(t *testing.T) {
 		replyPostTime := ptrInt64(initialDate + 27)
 		replyTime := ptrInt64(initialDate + 28)
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -3975,7 +3975,7 @@ func TestImportImportDirectPost(t *testing.T) {
 					User:     ptrStr(th.BasicUser2.Username),
 					Message:  ptrStr("Message with reply 2"),
 					CreateAt: replyPostTime,
-					Replies: &[]ReplyImportData{{
+					Replies: &[]model.ReplyImportData{{
 						User:     ptrStr(th.BasicUser.Username),
 						Message:  ptrStr("Message reply"),
 						CreateAt: replyTime,
@@ -3996,8 +3996,8 @@ func TestImportImportDirectPost(t *testing.T) {
 		replyTime := ptrInt64(initialDate + 30)
 		replyEditTime := ptrInt64(initialDate + 31)
 		data := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						th.BasicUser.Username,
 						th.BasicUser2.Username,
@@ -4006,7 +4006,7 @@ func TestImportImportDirectPost(t *testing.T) {
 					User:     ptrStr(th.BasicUser2.Username),
 					Message:  ptrStr("Message with reply"),
 					CreateAt: replyPostTime,
-					Replies: &[]ReplyImportData{{
+					Replies: &[]model.ReplyImportData{{
 						User:     ptrStr(th.BasicUser.Username),
 						Type:     ptrStr(model.PostTypeSystemGeneric),
 						Message:  ptrStr("Message reply 2"),
@@ -4045,7 +4045,7 @@ func TestImportImportEmoji(t *testing.T) {
 	testsDir, _ := fileutils.FindDir("tests")
 	testImage := filepath.Join(testsDir, "test.png")
 
-	data := EmojiImportData{Name: ptrStr(model.NewId())}
+	data := model.EmojiImportData{Name: ptrStr(model.NewId())}
 	err := th.App.importEmoji(&data, true)
 	assert.NotNil(t, err, "Invalid emoji should have failed dry run")
 
@@ -4057,7 +4057,7 @@ func TestImportImportEmoji(t *testing.T) {
 	err = th.App.importEmoji(&data, true)
 	assert.Nil(t, err, "Valid emoji should have passed dry run")
 
-	data = EmojiImportData{Name: ptrStr(model.NewId())}
+	data = model.EmojiImportData{Name: ptrStr(model.NewId())}
 	err = th.App.importEmoji(&data, false)
 	assert.NotNil(t, err, "Invalid emoji should have failed apply mode")
 
@@ -4076,7 +4076,7 @@ func TestImportImportEmoji(t *testing.T) {
 	err = th.App.importEmoji(&data, false)
 	assert.Nil(t, err, "Second run should have succeeded apply mode")
 
-	data = EmojiImportData{Name: ptrStr("smiley"), Image: ptrStr(testImage)}
+	data = model.EmojiImportData{Name: ptrStr("smiley"), Image: ptrStr(testImage)}
 	err = th.App.importEmoji(&data, false)
 	assert.Nil(t, err, "System emoji should not fail")
 }
@@ -4090,14 +4090,14 @@ func TestImportAttachment(t *testing.T) {
 	invalidPath := "some-invalid-path"
 
 	userID := model.NewId()
-	data := AttachmentImportData{Path: &testImage}
+	data := model.AttachmentImportData{Path: &testImage}
 	_, err := th.App.importAttachment(th.Context, &data, &model.Post{UserId: userID, ChannelId: "some-channel"}, "some-team")
 	assert.Nil(t, err, "sample run without errors")
 
 	attachments := GetAttachments(userID, th, t)
 	assert.Len(t, attachments, 1)
 
-	data = AttachmentImportData{Path: &invalidPath}
+	data = model.AttachmentImportData{Path: &invalidPath}
 	_, err = th.App.importAttachment(th.Context, &data, &model.Post{UserId: model.NewId(), ChannelId: "some-channel"}, "some-team")
 	assert.NotNil(t, err, "should have failed when opening the file")
 	assert.Equal(t, err.Id, "app.import.attachment.bad_file.error")
@@ -4109,7 +4109,7 @@ func TestImportPostAndRepliesWithAttachments(t *testing.T) {
 
 	// Create a Team.
 	teamName := model.NewRandomTeamName()
-	th.App.importTeam(th.Context, &TeamImportData{
+	th.App.importTeam(th.Context, &model.TeamImportData{
 		Name:        &teamName,
 		DisplayName: ptrStr("Display Name"),
 		Type:        ptrStr("O"),
@@ -4120,7 +4120,7 @@ func TestImportPostAndRepliesWithAttachments(t *testing.T) {
 	// Create a Channel.
 	channelName := model.NewId()
 	chanTypeOpen := model.ChannelTypeOpen
-	th.App.importChannel(th.Context, &ChannelImportData{
+	th.App.importChannel(th.Context, &model.ChannelImportData{
 		Team:        &teamName,
 		Name:        &channelName,
 		DisplayName: ptrStr("Display Name"),
@@ -4131,7 +4131,7 @@ func TestImportPostAndRepliesWithAttachments(t *testing.T) {
 
 	// Create a user3.
 	username := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -4140,7 +4140,7 @@ func TestImportPostAndRepliesWithAttachments(t *testing.T) {
 	require.NotNil(t, user3)
 
 	username2 := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username2,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -4149,7 +4149,7 @@ func TestImportPostAndRepliesWithAttachments(t *testing.T) {
 
 	// Create direct post users.
 	username3 := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username3,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -4157,7 +4157,7 @@ func TestImportPostAndRepliesWithAttachments(t *testing.T) {
 	require.Nil(t, appErr, "Failed to get user3 from database.")
 
 	username4 := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username4,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -4173,19 +4173,19 @@ func TestImportPostAndRepliesWithAttachments(t *testing.T) {
 	testImage := filepath.Join(testsDir, "test.png")
 	testMarkDown := filepath.Join(testsDir, "test-attachments.md")
 	data := LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:        &teamName,
 				Channel:     &channelName,
 				User:        &username3,
 				Message:     ptrStr("Message with reply"),
 				CreateAt:    &attachmentsPostTime,
-				Attachments: &[]AttachmentImportData{{Path: &testImage}, {Path: &testMarkDown}},
-				Replies: &[]ReplyImportData{{
+				Attachments: &[]model.AttachmentImportData{{Path: &testImage}, {Path: &testMarkDown}},
+				Replies: &[]model.ReplyImportData{{
 					User:        &user4.Username,
 					Message:     ptrStr("Message reply"),
 					CreateAt:    &attachmentsReplyTime,
-					Attachments: &[]AttachmentImportData{{Path: &testImage}},
+					Attachments: &[]model.AttachmentImportData{{Path: &testImage}},
 				}},
 			},
 		},
@@ -4210,7 +4210,7 @@ func TestImportPostAndRepliesWithAttachments(t *testing.T) {
 	})
 
 	t.Run("import existing post with new attachment", func(t *testing.T) {
-		data.Post.Attachments = &[]AttachmentImportData{{Path: &testImage}}
+		data.Post.Attachments = &[]model.AttachmentImportData{{Path: &testImage}}
 		errLine, err := th.App.importMultiplePostLines(th.Context, []LineImportWorkerData{data}, false)
 		require.Nil(t, err)
 		require.Equal(t, 0, errLine)
@@ -4228,8 +4228,8 @@ func TestImportPostAndRepliesWithAttachments(t *testing.T) {
 
 	t.Run("Reply with Attachments in Direct Post", func(t *testing.T) {
 		directImportData := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						user3.Username,
 						user2.Username,
@@ -4237,11 +4237,11 @@ func TestImportPostAndRepliesWithAttachments(t *testing.T) {
 					User:     &user3.Username,
 					Message:  ptrStr("Message with Replies"),
 					CreateAt: ptrInt64(model.GetMillis()),
-					Replies: &[]ReplyImportData{{
+					Replies: &[]model.ReplyImportData{{
 						User:        &user2.Username,
 						Message:     ptrStr("Message reply with attachment"),
 						CreateAt:    ptrInt64(model.GetMillis()),
-						Attachments: &[]AttachmentImportData{{Path: &testImage}},
+						Attachments: &[]model.AttachmentImportData{{Path: &testImage}},
 					}},
 				},
 			},
@@ -4276,7 +4276,7 @@ func TestImportDirectPostWithAttachments(t *testing.T) {
 
 	// Create a user.
 	username := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -4284,7 +4284,7 @@ func TestImportDirectPostWithAttachments(t *testing.T) {
 	require.Nil(t, appErr, "Failed to get user1 from database.")
 
 	username2 := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username2,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -4293,8 +4293,8 @@ func TestImportDirectPostWithAttachments(t *testing.T) {
 	require.Nil(t, appErr, "Failed to get user2 from database.")
 
 	directImportData := LineImportWorkerData{
-		LineImportData{
-			DirectPost: &DirectPostImportData{
+		model.LineImportData{
+			DirectPost: &model.DirectPostImportData{
 				ChannelMembers: &[]string{
 					user1.Username,
 					user2.Username,
@@ -4302,7 +4302,7 @@ func TestImportDirectPostWithAttachments(t *testing.T) {
 				User:        &user1.Username,
 				Message:     ptrStr("Direct message"),
 				CreateAt:    ptrInt64(model.GetMillis()),
-				Attachments: &[]AttachmentImportData{{Path: &testImage}},
+				Attachments: &[]model.AttachmentImportData{{Path: &testImage}},
 			},
 		},
 		3,
@@ -4330,8 +4330,8 @@ func TestImportDirectPostWithAttachments(t *testing.T) {
 
 	t.Run("Attempt to import again with same name and size but different content, SHOULD add an attachment", func(t *testing.T) {
 		directImportDataFake := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						user1.Username,
 						user2.Username,
@@ -4339,7 +4339,7 @@ func TestImportDirectPostWithAttachments(t *testing.T) {
 					User:        &user1.Username,
 					Message:     ptrStr("Direct message"),
 					CreateAt:    ptrInt64(model.GetMillis()),
-					Attachments: &[]AttachmentImportData{{Path: &testImageFake}},
+					Attachments: &[]model.AttachmentImportData{{Path: &testImageFake}},
 				},
 			},
 			2,
@@ -4355,8 +4355,8 @@ func TestImportDirectPostWithAttachments(t *testing.T) {
 
 	t.Run("Attempt to import again with same data, SHOULD add an attachment, since it's different name", func(t *testing.T) {
 		directImportData2 := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						user1.Username,
 						user2.Username,
@@ -4364,7 +4364,7 @@ func TestImportDirectPostWithAttachments(t *testing.T) {
 					User:        &user1.Username,
 					Message:     ptrStr("Direct message"),
 					CreateAt:    ptrInt64(model.GetMillis()),
-					Attachments: &[]AttachmentImportData{{Path: &testImage2}},
+					Attachments: &[]model.AttachmentImportData{{Path: &testImage2}},
 				},
 			},
 			2,
@@ -4385,7 +4385,7 @@ func TestZippedImportPostAndRepliesWithAttachments(t *testing.T) {
 
 	// Create a Team.
 	teamName := model.NewRandomTeamName()
-	th.App.importTeam(th.Context, &TeamImportData{
+	th.App.importTeam(th.Context, &model.TeamImportData{
 		Name:        &teamName,
 		DisplayName: ptrStr("Display Name"),
 		Type:        ptrStr("O"),
@@ -4396,7 +4396,7 @@ func TestZippedImportPostAndRepliesWithAttachments(t *testing.T) {
 	// Create a Channel.
 	channelName := model.NewId()
 	chanTypeOpen := model.ChannelTypeOpen
-	th.App.importChannel(th.Context, &ChannelImportData{
+	th.App.importChannel(th.Context, &model.ChannelImportData{
 		Team:        &teamName,
 		Name:        &channelName,
 		DisplayName: ptrStr("Display Name"),
@@ -4407,7 +4407,7 @@ func TestZippedImportPostAndRepliesWithAttachments(t *testing.T) {
 
 	// Create users
 	username2 := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username2,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -4416,7 +4416,7 @@ func TestZippedImportPostAndRepliesWithAttachments(t *testing.T) {
 
 	// Create direct post users.
 	username3 := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username3,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -4424,7 +4424,7 @@ func TestZippedImportPostAndRepliesWithAttachments(t *testing.T) {
 	require.Nil(t, appErr, "Failed to get user3 from database.")
 
 	username4 := model.NewId()
-	th.App.importUser(&UserImportData{
+	th.App.importUser(&model.UserImportData{
 		Username: &username4,
 		Email:    ptrStr(model.NewId() + "@example.com"),
 	}, false)
@@ -4452,19 +4452,19 @@ func TestZippedImportPostAndRepliesWithAttachments(t *testing.T) {
 
 	testMarkDown := filepath.Join(testsDir, "test-attachments.md")
 	data := LineImportWorkerData{
-		LineImportData{
-			Post: &PostImportData{
+		model.LineImportData{
+			Post: &model.PostImportData{
 				Team:        &teamName,
 				Channel:     &channelName,
 				User:        &username3,
 				Message:     ptrStr("Message with reply"),
 				CreateAt:    &attachmentsPostTime,
-				Attachments: &[]AttachmentImportData{{Path: &testImage}, {Path: &testMarkDown}},
-				Replies: &[]ReplyImportData{{
+				Attachments: &[]model.AttachmentImportData{{Path: &testImage}, {Path: &testMarkDown}},
+				Replies: &[]model.ReplyImportData{{
 					User:        &user4.Username,
 					Message:     ptrStr("Message reply"),
 					CreateAt:    &attachmentsReplyTime,
-					Attachments: &[]AttachmentImportData{{Path: &testImage, Data: imageData}},
+					Attachments: &[]model.AttachmentImportData{{Path: &testImage, Data: imageData}},
 				}},
 			},
 		},
@@ -4489,7 +4489,7 @@ func TestZippedImportPostAndRepliesWithAttachments(t *testing.T) {
 	})
 
 	t.Run("import existing post with new attachment", func(t *testing.T) {
-		data.Post.Attachments = &[]AttachmentImportData{{Path: &testImage}}
+		data.Post.Attachments = &[]model.AttachmentImportData{{Path: &testImage}}
 		errLine, err := th.App.importMultiplePostLines(th.Context, []LineImportWorkerData{data}, false)
 		require.Nil(t, err)
 		require.Equal(t, 0, errLine)
@@ -4507,8 +4507,8 @@ func TestZippedImportPostAndRepliesWithAttachments(t *testing.T) {
 
 	t.Run("Reply with Attachments in Direct Post", func(t *testing.T) {
 		directImportData := LineImportWorkerData{
-			LineImportData{
-				DirectPost: &DirectPostImportData{
+			model.LineImportData{
+				DirectPost: &model.DirectPostImportData{
 					ChannelMembers: &[]string{
 						user3.Username,
 						user2.Username,
@@ -4516,11 +4516,11 @@ func TestZippedImportPostAndRepliesWithAttachments(t *testing.T) {
 					User:     &user3.Username,
 					Message:  ptrStr("Message with Replies"),
 					CreateAt: ptrInt64(model.GetMillis()),
-					Replies: &[]ReplyImportData{{
+					Replies: &[]model.ReplyImportData{{
 						User:        &user2.Username,
 						Message:     ptrStr("Message reply with attachment"),
 						CreateAt:    ptrInt64(model.GetMillis()),
-						Attachments: &[]AttachmentImportData{{Path: &testImage}},
+						Attachments: &[]model.AttachmentImportData{{Path: &testImage}},
 					}},
 				},
 			},
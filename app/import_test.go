@@ -83,7 +83,7 @@ func TestImportImportLine(t *testing.T) {
 	defer th.TearDown()
 
 	// Try import line with an invalid type.
-	line := LineImportData{
+	line := model.LineImportData{
 		Type: "gibberish",
 	}
 
@@ -243,7 +243,7 @@ func TestImportBulkImport(t *testing.T) {
 }
 
 func TestImportProcessImportDataFileVersionLine(t *testing.T) {
-	data := LineImportData{
+	data := model.LineImportData{
 		Type:    "version",
 		Version: ptrInt(1),
 	}
@@ -281,8 +281,8 @@ func AssertFileIdsInPost(files []*model.FileInfo, th *TestHelper, t *testing.T)
 }
 
 func TestProcessAttachments(t *testing.T) {
-	genAttachments := func() *[]AttachmentImportData {
-		return &[]AttachmentImportData{
+	genAttachments := func() *[]model.AttachmentImportData {
+		return &[]model.AttachmentImportData{
 			{
 				Path: model.NewString("file.jpg"),
 			},
@@ -292,36 +292,36 @@ func TestProcessAttachments(t *testing.T) {
 		}
 	}
 
-	line := LineImportData{
+	line := model.LineImportData{
 		Type: "post",
-		Post: &PostImportData{
+		Post: &model.PostImportData{
 			Attachments: genAttachments(),
 		},
 	}
 
-	line2 := LineImportData{
+	line2 := model.LineImportData{
 		Type: "direct_post",
-		DirectPost: &DirectPostImportData{
+		DirectPost: &model.DirectPostImportData{
 			Attachments: genAttachments(),
 		},
 	}
 
-	userLine := LineImportData{
+	userLine := model.LineImportData{
 		Type: "user",
-		User: &UserImportData{
+		User: &model.UserImportData{
 			ProfileImage: model.NewString("profile.jpg"),
 		},
 	}
 
-	emojiLine := LineImportData{
+	emojiLine := model.LineImportData{
 		Type: "emoji",
-		Emoji: &EmojiImportData{
+		Emoji: &model.EmojiImportData{
 			Image: model.NewString("emoji.png"),
 		},
 	}
 
 	t.Run("empty path", func(t *testing.T) {
-		expected := &[]AttachmentImportData{
+		expected := &[]model.AttachmentImportData{
 			{
 				Path: model.NewString("file.jpg"),
 			},
@@ -338,7 +338,7 @@ func TestProcessAttachments(t *testing.T) {
 	})
 
 	t.Run("valid path", func(t *testing.T) {
-		expected := &[]AttachmentImportData{
+		expected := &[]model.AttachmentImportData{
 			{
 				Path: model.NewString("/tmp/file.jpg"),
 			},
@@ -498,3 +498,65 @@ func TestImportBulkImportWithAttachments(t *testing.T) {
 	files := GetAttachments(adminUser.Id, th, t)
 	require.Len(t, files, 11)
 }
+
+func TestImportBulkImportWithOpts(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	teamName := model.NewRandomTeamName()
+	channelName := model.NewId()
+	username := model.NewId()
+
+	data := `{"type": "version", "version": 1}
+{"type": "team", "team": {"type": "O", "display_name": "Team", "name": "` + teamName + `"}}
+{"type": "channel", "channel": {"type": "O", "display_name": "Channel", "team": "` + teamName + `", "name": "` + channelName + `"}}
+{"type": "user", "user": {"username": "` + username + `", "email": "` + username + `@example.com", "teams": [{"name": "` + teamName + `", "channels": [{"name": "` + channelName + `"}]}]}}
+{"type": "post", "post": {"team": "` + teamName + `", "channel": "` + channelName + `", "user": "` + username + `", "message": "First", "create_at": 123456789012}}
+{"type": "post", "post": {"team": "` + teamName + `", "channel": "` + channelName + `", "user": "unknown", "message": "Unknown user", "create_at": 123456789013}}
+{"type": "post", "post": {"team": "` + teamName + `", "channel": "` + channelName + `", "user": "` + username + `", "message": "Second", "create_at": 123456789014}}
+{"type": "post", "post": {"team": "` + teamName + `", "channel": "` + channelName + `"`
+
+	t.Run("stops on the first failing line by default", func(t *testing.T) {
+		appErr, line := th.App.BulkImportWithOpts(th.Context, strings.NewReader(data), nil, model.BulkImportOpts{Workers: 2})
+		require.NotNil(t, appErr)
+		require.Equal(t, "app.import.bulk_import.json_decode.error", appErr.Id)
+		require.Equal(t, 8, line)
+	})
+
+	t.Run("goes on past failing lines and reports the progress", func(t *testing.T) {
+		failedLines := map[int]string{}
+		var progress []int
+		appErr, _ := th.App.BulkImportWithOpts(th.Context, strings.NewReader(data), nil, model.BulkImportOpts{
+			Workers:            2,
+			CheckpointInterval: 1,
+			OnProgress:         func(lines int) { progress = append(progress, lines) },
+			OnLineError:        func(lineNumber int, err *model.AppError) { failedLines[lineNumber] = err.Id },
+		})
+		require.Nil(t, appErr)
+		assert.Equal(t, map[int]string{
+			6: "app.import.get_users_by_username.some_users_not_found.error",
+			8: "app.import.bulk_import.json_decode.error",
+		}, failedLines)
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 8}, progress)
+
+		team, appErr := th.App.GetTeamByName(teamName)
+		require.Nil(t, appErr)
+		channel, appErr := th.App.GetChannelByName(channelName, team.Id, false)
+		require.Nil(t, appErr)
+		posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: channel.Id, PerPage: 10})
+		require.Nil(t, appErr)
+		require.Len(t, posts.Order, 2)
+	})
+
+	t.Run("resumes after the processed lines", func(t *testing.T) {
+		var progress []int
+		appErr, _ := th.App.BulkImportWithOpts(th.Context, strings.NewReader(data), nil, model.BulkImportOpts{
+			Workers:     2,
+			SkipLines:   6,
+			OnProgress:  func(lines int) { progress = append(progress, lines) },
+			OnLineError: func(lineNumber int, err *model.AppError) {},
+		})
+		require.Nil(t, appErr)
+		assert.Equal(t, []int{6, 8}, progress)
+	})
+}
@@ -13,7 +13,7 @@ import (
 	"github.com/cjdelisle/matterfoss-server/v6/model"
 )
 
-func validateSchemeImportData(data *SchemeImportData) *model.AppError {
+func validateSchemeImportData(data *model.SchemeImportData) *model.AppError {
 
 	if data.Scope == nil {
 		return model.NewAppError("BulkImport", "app.import.validate_scheme_import_data.null_scope.error", nil, "", http.StatusBadRequest)
@@ -83,7 +83,7 @@ func validateSchemeImportData(data *SchemeImportData) *model.AppError {
 	return nil
 }
 
-func validateRoleImportData(data *RoleImportData) *model.AppError {
+func validateRoleImportData(data *model.RoleImportData) *model.AppError {
 
 	if data.Name == nil || !model.IsValidRoleName(*data.Name) {
 		return model.NewAppError("BulkImport", "app.import.validate_role_import_data.name_invalid.error", nil, "", http.StatusBadRequest)
@@ -116,7 +116,7 @@ func validateRoleImportData(data *RoleImportData) *model.AppError {
 	return nil
 }
 
-func validateTeamImportData(data *TeamImportData) *model.AppError {
+func validateTeamImportData(data *model.TeamImportData) *model.AppError {
 
 	if data.Name == nil {
 		return model.NewAppError("BulkImport", "app.import.validate_team_import_data.name_missing.error", nil, "", http.StatusBadRequest)
@@ -151,7 +151,7 @@ func validateTeamImportData(data *TeamImportData) *model.AppError {
 	return nil
 }
 
-func validateChannelImportData(data *ChannelImportData) *model.AppError {
+func validateChannelImportData(data *model.ChannelImportData) *model.AppError {
 
 	if data.Team == nil {
 		return model.NewAppError("BulkImport", "app.import.validate_channel_import_data.team_missing.error", nil, "", http.StatusBadRequest)
@@ -192,7 +192,7 @@ func validateChannelImportData(data *ChannelImportData) *model.AppError {
 	return nil
 }
 
-func validateUserImportData(data *UserImportData) *model.AppError {
+func validateUserImportData(data *model.UserImportData) *model.AppError {
 	if data.ProfileImage != nil {
 		if _, err := os.Stat(*data.ProfileImage); os.IsNotExist(err) {
 			return model.NewAppError("BulkImport", "app.import.validate_user_import_data.profile_image.error", nil, "", http.StatusBadRequest)
@@ -311,7 +311,7 @@ func validateUserImportData(data *UserImportData) *model.AppError {
 	return nil
 }
 
-func validateUserTeamsImportData(data *[]UserTeamImportData) *model.AppError {
+func validateUserTeamsImportData(data *[]model.UserTeamImportData) *model.AppError {
 	if data == nil {
 		return nil
 	}
@@ -331,6 +331,12 @@ func validateUserTeamsImportData(data *[]UserTeamImportData) *model.AppError {
 			}
 		}
 
+		if tdata.Categories != nil {
+			if err := validateSidebarCategoriesImportData(tdata.Categories); err != nil {
+				return err
+			}
+		}
+
 		if tdata.Theme != nil && 0 < len(strings.Trim(*tdata.Theme, " \t\r")) {
 			var unused map[string]string
 			if err := json.NewDecoder(strings.NewReader(*tdata.Theme)).Decode(&unused); err != nil {
@@ -342,7 +348,7 @@ func validateUserTeamsImportData(data *[]UserTeamImportData) *model.AppError {
 	return nil
 }
 
-func validateUserChannelsImportData(data *[]UserChannelImportData) *model.AppError {
+func validateUserChannelsImportData(data *[]model.UserChannelImportData) *model.AppError {
 	if data == nil {
 		return nil
 	}
@@ -374,7 +380,166 @@ func validateUserChannelsImportData(data *[]UserChannelImportData) *model.AppErr
 	return nil
 }
 
-func validateReactionImportData(data *ReactionImportData, parentCreateAt int64) *model.AppError {
+func validateSidebarCategoriesImportData(data *[]model.SidebarCategoryImportData) *model.AppError {
+	for _, cdata := range *data {
+		if cdata.DisplayName == nil || *cdata.DisplayName == "" {
+			return model.NewAppError("BulkImport", "app.import.validate_sidebar_categories_import_data.display_name_missing.error", nil, "", http.StatusBadRequest)
+		}
+
+		if cdata.Sorting != nil {
+			switch model.SidebarCategorySorting(*cdata.Sorting) {
+			case model.SidebarCategorySortDefault, model.SidebarCategorySortManual, model.SidebarCategorySortRecent, model.SidebarCategorySortAlphabetical:
+			default:
+				return model.NewAppError("BulkImport", "app.import.validate_sidebar_categories_import_data.invalid_sorting.error", nil, "", http.StatusBadRequest)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateBotImportData(data *model.BotImportData) *model.AppError {
+	if data.Username == nil || !model.IsValidUsername(*data.Username) {
+		return model.NewAppError("BulkImport", "app.import.validate_bot_import_data.username_invalid.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Owner == nil || *data.Owner == "" {
+		return model.NewAppError("BulkImport", "app.import.validate_bot_import_data.owner_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.DisplayName != nil && utf8.RuneCountInString(*data.DisplayName) > model.BotDisplayNameMaxRunes {
+		return model.NewAppError("BulkImport", "app.import.validate_bot_import_data.display_name_length.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Description != nil && utf8.RuneCountInString(*data.Description) > model.BotDescriptionMaxRunes {
+		return model.NewAppError("BulkImport", "app.import.validate_bot_import_data.description_length.error", nil, "", http.StatusBadRequest)
+	}
+
+	return validateUserTeamsImportData(data.Teams)
+}
+
+func validateCommandImportData(data *model.CommandImportData) *model.AppError {
+	if data.Team == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_command_import_data.team_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Creator == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_command_import_data.creator_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Trigger == nil || *data.Trigger == "" {
+		return model.NewAppError("BulkImport", "app.import.validate_command_import_data.trigger_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.URL == nil || !model.IsValidHTTPURL(*data.URL) {
+		return model.NewAppError("BulkImport", "app.import.validate_command_import_data.url_invalid.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Method == nil || (*data.Method != model.CommandMethodGet && *data.Method != model.CommandMethodPost) {
+		return model.NewAppError("BulkImport", "app.import.validate_command_import_data.method_invalid.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateIncomingWebhookImportData(data *model.IncomingWebhookImportData) *model.AppError {
+	if data.Team == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_incoming_webhook_import_data.team_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Channel == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_incoming_webhook_import_data.channel_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Creator == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_incoming_webhook_import_data.creator_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateOutgoingWebhookImportData(data *model.OutgoingWebhookImportData) *model.AppError {
+	if data.Team == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_outgoing_webhook_import_data.team_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Creator == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_outgoing_webhook_import_data.creator_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.CallbackURLs == nil || len(*data.CallbackURLs) == 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_outgoing_webhook_import_data.callback_urls_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if (data.TriggerWords == nil || len(*data.TriggerWords) == 0) && data.Channel == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_outgoing_webhook_import_data.trigger_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateOAuthAppImportData(data *model.OAuthAppImportData) *model.AppError {
+	if data.Creator == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_oauth_app_import_data.creator_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Name == nil || *data.Name == "" {
+		return model.NewAppError("BulkImport", "app.import.validate_oauth_app_import_data.name_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.CallbackURLs == nil || len(*data.CallbackURLs) == 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_oauth_app_import_data.callback_urls_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Homepage == nil || !model.IsValidHTTPURL(*data.Homepage) {
+		return model.NewAppError("BulkImport", "app.import.validate_oauth_app_import_data.homepage_invalid.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateChannelBookmarkImportData(data *model.ChannelBookmarkImportData) *model.AppError {
+	if data.Team == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_channel_bookmark_import_data.team_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Channel == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_channel_bookmark_import_data.channel_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Owner == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_channel_bookmark_import_data.owner_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.DisplayName == nil || *data.DisplayName == "" || utf8.RuneCountInString(*data.DisplayName) > model.ChannelBookmarkDisplayNameMaxRunes {
+		return model.NewAppError("BulkImport", "app.import.validate_channel_bookmark_import_data.display_name_invalid.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Type == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_channel_bookmark_import_data.type_invalid.error", nil, "", http.StatusBadRequest)
+	}
+
+	switch model.ChannelBookmarkType(*data.Type) {
+	case model.ChannelBookmarkLink:
+		if data.LinkUrl == nil || !model.IsValidHTTPURL(*data.LinkUrl) {
+			return model.NewAppError("BulkImport", "app.import.validate_channel_bookmark_import_data.link_url_invalid.error", nil, "", http.StatusBadRequest)
+		}
+	case model.ChannelBookmarkFile:
+		if data.File == nil || data.File.Path == nil {
+			return model.NewAppError("BulkImport", "app.import.validate_channel_bookmark_import_data.file_missing.error", nil, "", http.StatusBadRequest)
+		}
+	default:
+		return model.NewAppError("BulkImport", "app.import.validate_channel_bookmark_import_data.type_invalid.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.SortOrder != nil && *data.SortOrder < 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_channel_bookmark_import_data.sort_order_invalid.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateReactionImportData(data *model.ReactionImportData, parentCreateAt int64) *model.AppError {
 	if data.User == nil {
 		return model.NewAppError("BulkImport", "app.import.validate_reaction_import_data.user_missing.error", nil, "", http.StatusBadRequest)
 	}
@@ -396,7 +561,71 @@ func validateReactionImportData(data *ReactionImportData, parentCreateAt int64)
 	return nil
 }
 
-func validateReplyImportData(data *ReplyImportData, parentCreateAt int64, maxPostSize int) *model.AppError {
+func validatePostRevisionImportData(data *model.PostRevisionImportData, parentCreateAt int64, maxPostSize int) *model.AppError {
+	if data.Message == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_post_revision_import_data.message_missing.error", nil, "", http.StatusBadRequest)
+	} else if utf8.RuneCountInString(*data.Message) > maxPostSize {
+		return model.NewAppError("BulkImport", "app.import.validate_post_revision_import_data.message_length.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.CreateAt == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_post_revision_import_data.create_at_missing.error", nil, "", http.StatusBadRequest)
+	} else if *data.CreateAt < parentCreateAt {
+		return model.NewAppError("BulkImport", "app.import.validate_post_revision_import_data.create_at_before_parent.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Props != nil && utf8.RuneCountInString(model.StringInterfaceToJSON(*data.Props)) > model.PostPropsMaxRunes {
+		return model.NewAppError("BulkImport", "app.import.validate_post_revision_import_data.props_too_large.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validateEditHistoryImportData(data *[]model.PostRevisionImportData, parentCreateAt int64, maxPostSize int) *model.AppError {
+	if data == nil {
+		return nil
+	}
+	for _, revision := range *data {
+		revision := revision
+		if err := validatePostRevisionImportData(&revision, parentCreateAt, maxPostSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePollVoteImportData(data *model.PollVoteImportData, postCreateAt int64) *model.AppError {
+	if data.User == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_poll_vote_import_data.user_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.Options == nil || len(*data.Options) == 0 {
+		return model.NewAppError("BulkImport", "app.import.validate_poll_vote_import_data.options_missing.error", nil, "", http.StatusBadRequest)
+	}
+
+	if data.CreateAt == nil {
+		return model.NewAppError("BulkImport", "app.import.validate_poll_vote_import_data.create_at_missing.error", nil, "", http.StatusBadRequest)
+	} else if *data.CreateAt < postCreateAt {
+		return model.NewAppError("BulkImport", "app.import.validate_poll_vote_import_data.create_at_before_post.error", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func validatePollVotesImportData(data *[]model.PollVoteImportData, postCreateAt int64) *model.AppError {
+	if data == nil {
+		return nil
+	}
+	for _, vote := range *data {
+		vote := vote
+		if err := validatePollVoteImportData(&vote, postCreateAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateReplyImportData(data *model.ReplyImportData, parentCreateAt int64, maxPostSize int) *model.AppError {
 	if data.User == nil {
 		return model.NewAppError("BulkImport", "app.import.validate_reply_import_data.user_missing.error", nil, "", http.StatusBadRequest)
 	}
@@ -415,10 +644,18 @@ func validateReplyImportData(data *ReplyImportData, parentCreateAt int64, maxPos
 		return model.NewAppError("BulkImport", "app.import.validate_reply_import_data.create_at_before_parent.error", nil, "", http.StatusBadRequest)
 	}
 
-	return nil
+	if data.Props != nil && utf8.RuneCountInString(model.StringInterfaceToJSON(*data.Props)) > model.PostPropsMaxRunes {
+		return model.NewAppError("BulkImport", "app.import.validate_reply_import_data.props_too_large.error", nil, "", http.StatusBadRequest)
+	}
+
+	if err := validatePollVotesImportData(data.PollVotes, *data.CreateAt); err != nil {
+		return err
+	}
+
+	return validateEditHistoryImportData(data.EditHistory, *data.CreateAt, maxPostSize)
 }
 
-func validatePostImportData(data *PostImportData, maxPostSize int) *model.AppError {
+func validatePostImportData(data *model.PostImportData, maxPostSize int) *model.AppError {
 	if data.Team == nil {
 		return model.NewAppError("BulkImport", "app.import.validate_post_import_data.team_missing.error", nil, "", http.StatusBadRequest)
 	}
@@ -461,10 +698,14 @@ func validatePostImportData(data *PostImportData, maxPostSize int) *model.AppErr
 		return model.NewAppError("BulkImport", "app.import.validate_post_import_data.props_too_large.error", nil, "", http.StatusBadRequest)
 	}
 
-	return nil
+	if err := validatePollVotesImportData(data.PollVotes, *data.CreateAt); err != nil {
+		return err
+	}
+
+	return validateEditHistoryImportData(data.EditHistory, *data.CreateAt, maxPostSize)
 }
 
-func validateDirectChannelImportData(data *DirectChannelImportData) *model.AppError {
+func validateDirectChannelImportData(data *model.DirectChannelImportData) *model.AppError {
 	if data.Members == nil {
 		return model.NewAppError("BulkImport", "app.import.validate_direct_channel_import_data.members_required.error", nil, "", http.StatusBadRequest)
 	}
@@ -499,7 +740,7 @@ func validateDirectChannelImportData(data *DirectChannelImportData) *model.AppEr
 	return nil
 }
 
-func validateDirectPostImportData(data *DirectPostImportData, maxPostSize int) *model.AppError {
+func validateDirectPostImportData(data *model.DirectPostImportData, maxPostSize int) *model.AppError {
 	if data.ChannelMembers == nil {
 		return model.NewAppError("BulkImport", "app.import.validate_direct_post_import_data.channel_members_required.error", nil, "", http.StatusBadRequest)
 	}
@@ -557,12 +798,16 @@ func validateDirectPostImportData(data *DirectPostImportData, maxPostSize int) *
 		}
 	}
 
-	return nil
+	if err := validatePollVotesImportData(data.PollVotes, *data.CreateAt); err != nil {
+		return err
+	}
+
+	return validateEditHistoryImportData(data.EditHistory, *data.CreateAt, maxPostSize)
 }
 
 // validateEmojiImportData validates emoji data and returns if the import name
 // conflicts with a system emoji.
-func validateEmojiImportData(data *EmojiImportData) *model.AppError {
+func validateEmojiImportData(data *model.EmojiImportData) *model.AppError {
 	if data == nil {
 		return model.NewAppError("BulkImport", "app.import.validate_emoji_import_data.empty.error", nil, "", http.StatusBadRequest)
 	}
@@ -18,36 +18,36 @@ import (
 
 func TestImportValidateSchemeImportData(t *testing.T) {
 	// Test with minimum required valid properties and team scope.
-	data := SchemeImportData{
+	data := model.SchemeImportData{
 		Name:        ptrStr("name"),
 		DisplayName: ptrStr("display name"),
 		Scope:       ptrStr("team"),
-		DefaultTeamAdminRole: &RoleImportData{
+		DefaultTeamAdminRole: &model.RoleImportData{
 			Name:        ptrStr("name"),
 			DisplayName: ptrStr("display name"),
 			Permissions: &[]string{"invite_user"},
 		},
-		DefaultTeamUserRole: &RoleImportData{
+		DefaultTeamUserRole: &model.RoleImportData{
 			Name:        ptrStr("name"),
 			DisplayName: ptrStr("display name"),
 			Permissions: &[]string{"invite_user"},
 		},
-		DefaultTeamGuestRole: &RoleImportData{
+		DefaultTeamGuestRole: &model.RoleImportData{
 			Name:        ptrStr("name"),
 			DisplayName: ptrStr("display name"),
 			Permissions: &[]string{"invite_user"},
 		},
-		DefaultChannelAdminRole: &RoleImportData{
+		DefaultChannelAdminRole: &model.RoleImportData{
 			Name:        ptrStr("name"),
 			DisplayName: ptrStr("display name"),
 			Permissions: &[]string{"invite_user"},
 		},
-		DefaultChannelUserRole: &RoleImportData{
+		DefaultChannelUserRole: &model.RoleImportData{
 			Name:        ptrStr("name"),
 			DisplayName: ptrStr("display name"),
 			Permissions: &[]string{"invite_user"},
 		},
-		DefaultChannelGuestRole: &RoleImportData{
+		DefaultChannelGuestRole: &model.RoleImportData{
 			Name:        ptrStr("name"),
 			DisplayName: ptrStr("display name"),
 			Permissions: &[]string{"invite_user"},
@@ -96,7 +96,7 @@ func TestImportValidateSchemeImportData(t *testing.T) {
 	err = validateSchemeImportData(&data)
 	require.NotNil(t, err, "Should have failed due to missing role.")
 
-	data.DefaultTeamAdminRole = &RoleImportData{
+	data.DefaultTeamAdminRole = &model.RoleImportData{
 		Name:        ptrStr("name"),
 		DisplayName: ptrStr("display name"),
 		Permissions: &[]string{"invite_user"},
@@ -106,7 +106,7 @@ func TestImportValidateSchemeImportData(t *testing.T) {
 	err = validateSchemeImportData(&data)
 	require.NotNil(t, err, "Should have failed due to missing role.")
 
-	data.DefaultTeamUserRole = &RoleImportData{
+	data.DefaultTeamUserRole = &model.RoleImportData{
 		Name:        ptrStr("name"),
 		DisplayName: ptrStr("display name"),
 		Permissions: &[]string{"invite_user"},
@@ -115,7 +115,7 @@ func TestImportValidateSchemeImportData(t *testing.T) {
 	err = validateSchemeImportData(&data)
 	require.NotNil(t, err, "Should have failed due to missing role.")
 
-	data.DefaultChannelAdminRole = &RoleImportData{
+	data.DefaultChannelAdminRole = &model.RoleImportData{
 		Name:        ptrStr("name"),
 		DisplayName: ptrStr("display name"),
 		Permissions: &[]string{"invite_user"},
@@ -124,7 +124,7 @@ func TestImportValidateSchemeImportData(t *testing.T) {
 	err = validateSchemeImportData(&data)
 	require.NotNil(t, err, "Should have failed due to missing role.")
 
-	data.DefaultChannelUserRole = &RoleImportData{
+	data.DefaultChannelUserRole = &model.RoleImportData{
 		Name:        ptrStr("name"),
 		DisplayName: ptrStr("display name"),
 		Permissions: &[]string{"invite_user"},
@@ -158,7 +158,7 @@ func TestImportValidateSchemeImportData(t *testing.T) {
 	err = validateSchemeImportData(&data)
 	require.NotNil(t, err, "Should have failed due to spurious role.")
 
-	data.DefaultTeamAdminRole = &RoleImportData{
+	data.DefaultTeamAdminRole = &model.RoleImportData{
 		Name:        ptrStr("name"),
 		DisplayName: ptrStr("display name"),
 		Permissions: &[]string{"invite_user"},
@@ -167,7 +167,7 @@ func TestImportValidateSchemeImportData(t *testing.T) {
 	err = validateSchemeImportData(&data)
 	require.NotNil(t, err, "Should have failed due to spurious role.")
 
-	data.DefaultTeamUserRole = &RoleImportData{
+	data.DefaultTeamUserRole = &model.RoleImportData{
 		Name:        ptrStr("name"),
 		DisplayName: ptrStr("display name"),
 		Permissions: &[]string{"invite_user"},
@@ -194,7 +194,7 @@ func TestImportValidateSchemeImportData(t *testing.T) {
 
 func TestImportValidateRoleImportData(t *testing.T) {
 	// Test with minimum required valid properties.
-	data := RoleImportData{
+	data := model.RoleImportData{
 		Name:        ptrStr("name"),
 		DisplayName: ptrStr("display name"),
 	}
@@ -259,7 +259,7 @@ func TestImportValidateRoleImportData(t *testing.T) {
 func TestImportValidateTeamImportData(t *testing.T) {
 
 	// Test with minimum required valid properties.
-	data := TeamImportData{
+	data := model.TeamImportData{
 		Name:        ptrStr("teamname"),
 		DisplayName: ptrStr("Display Name"),
 		Type:        ptrStr("O"),
@@ -268,7 +268,7 @@ func TestImportValidateTeamImportData(t *testing.T) {
 	require.Nil(t, err, "Validation failed but should have been valid.")
 
 	// Test with various invalid names.
-	data = TeamImportData{
+	data = model.TeamImportData{
 		DisplayName: ptrStr("Display Name"),
 		Type:        ptrStr("O"),
 	}
@@ -292,7 +292,7 @@ func TestImportValidateTeamImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to short name.")
 
 	// Test team various invalid display names.
-	data = TeamImportData{
+	data = model.TeamImportData{
 		Name: ptrStr("teamname"),
 		Type: ptrStr("O"),
 	}
@@ -308,7 +308,7 @@ func TestImportValidateTeamImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to too long display_name.")
 
 	// Test with various valid and invalid types.
-	data = TeamImportData{
+	data = model.TeamImportData{
 		Name:        ptrStr("teamname"),
 		DisplayName: ptrStr("Display Name"),
 	}
@@ -324,7 +324,7 @@ func TestImportValidateTeamImportData(t *testing.T) {
 	require.Nil(t, err, "Should have succeeded with valid type.")
 
 	// Test with all the combinations of optional parameters.
-	data = TeamImportData{
+	data = model.TeamImportData{
 		Name:            ptrStr("teamname"),
 		DisplayName:     ptrStr("Display Name"),
 		Type:            ptrStr("O"),
@@ -358,7 +358,7 @@ func TestImportValidateChannelImportData(t *testing.T) {
 
 	// Test with minimum required valid properties.
 	chanTypeOpen := model.ChannelTypeOpen
-	data := ChannelImportData{
+	data := model.ChannelImportData{
 		Team:        ptrStr("teamname"),
 		Name:        ptrStr("channelname"),
 		DisplayName: ptrStr("Display Name"),
@@ -368,7 +368,7 @@ func TestImportValidateChannelImportData(t *testing.T) {
 	require.Nil(t, err, "Validation failed but should have been valid.")
 
 	// Test with missing team.
-	data = ChannelImportData{
+	data = model.ChannelImportData{
 		Name:        ptrStr("channelname"),
 		DisplayName: ptrStr("Display Name"),
 		Type:        &chanTypeOpen,
@@ -377,7 +377,7 @@ func TestImportValidateChannelImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to missing team.")
 
 	// Test with various invalid names.
-	data = ChannelImportData{
+	data = model.ChannelImportData{
 		Team:        ptrStr("teamname"),
 		DisplayName: ptrStr("Display Name"),
 		Type:        &chanTypeOpen,
@@ -398,7 +398,7 @@ func TestImportValidateChannelImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to short name.")
 
 	// Test team various invalid display names.
-	data = ChannelImportData{
+	data = model.ChannelImportData{
 		Team: ptrStr("teamname"),
 		Name: ptrStr("channelname"),
 		Type: &chanTypeOpen,
@@ -417,7 +417,7 @@ func TestImportValidateChannelImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to too long display_name.")
 
 	// Test with various valid and invalid types.
-	data = ChannelImportData{
+	data = model.ChannelImportData{
 		Team:        ptrStr("teamname"),
 		Name:        ptrStr("channelname"),
 		DisplayName: ptrStr("Display Name"),
@@ -436,7 +436,7 @@ func TestImportValidateChannelImportData(t *testing.T) {
 	require.Nil(t, err, "Should have succeeded with valid type.")
 
 	// Test with all the combinations of optional parameters.
-	data = ChannelImportData{
+	data = model.ChannelImportData{
 		Team:        ptrStr("teamname"),
 		Name:        ptrStr("channelname"),
 		DisplayName: ptrStr("Display Name"),
@@ -471,7 +471,7 @@ func TestImportValidateChannelImportData(t *testing.T) {
 func TestImportValidateUserImportData(t *testing.T) {
 
 	// Test with minimum required valid properties.
-	data := UserImportData{
+	data := model.UserImportData{
 		Username: ptrStr("bob"),
 		Email:    ptrStr("bob@example.com"),
 	}
@@ -534,7 +534,7 @@ func TestImportValidateUserImportData(t *testing.T) {
 
 	// Test a valid User with all fields populated.
 	testsDir, _ := fileutils.FindDir("tests")
-	data = UserImportData{
+	data = model.UserImportData{
 		ProfileImage: ptrStr(filepath.Join(testsDir, "test.png")),
 		Username:     ptrStr("bob"),
 		Email:        ptrStr("bob@example.com"),
@@ -586,7 +586,7 @@ func TestImportValidateUserImportData(t *testing.T) {
 	data.Roles = ptrStr("system_user")
 
 	// Try various valid/invalid notify props.
-	data.NotifyProps = &UserNotifyPropsImportData{}
+	data.NotifyProps = &model.UserNotifyPropsImportData{}
 
 	data.NotifyProps.Desktop = ptrStr("invalid")
 	checkError(t, validateUserImportData(&data))
@@ -657,7 +657,7 @@ func TestImportValidateUserAuth(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		data := UserImportData{
+		data := model.UserImportData{
 			Username:    ptrStr("bob"),
 			Email:       ptrStr("bob@example.com"),
 			AuthService: test.authService,
@@ -678,7 +678,7 @@ func TestImportValidateUserAuth(t *testing.T) {
 func TestImportValidateUserTeamsImportData(t *testing.T) {
 
 	// Invalid Name.
-	data := []UserTeamImportData{
+	data := []model.UserTeamImportData{
 		{
 			Roles: ptrStr("team_admin team_user"),
 		},
@@ -724,7 +724,7 @@ func TestImportValidateUserTeamsImportData(t *testing.T) {
 func TestImportValidateUserChannelsImportData(t *testing.T) {
 
 	// Invalid Name.
-	data := []UserChannelImportData{
+	data := []model.UserChannelImportData{
 		{
 			Roles: ptrStr("channel_admin channel_user"),
 		},
@@ -749,7 +749,7 @@ func TestImportValidateUserChannelsImportData(t *testing.T) {
 	require.Nil(t, err, "Should have succeeded with valid roles.")
 
 	// Empty notify props.
-	data[0].NotifyProps = &UserChannelNotifyPropsImportData{}
+	data[0].NotifyProps = &model.UserChannelNotifyPropsImportData{}
 	err = validateUserChannelsImportData(&data)
 	require.Nil(t, err, "Should have succeeded with empty notify props.")
 
@@ -779,7 +779,7 @@ func TestImportValidateUserChannelsImportData(t *testing.T) {
 func TestImportValidateReactionImportData(t *testing.T) {
 	// Test with minimum required valid properties.
 	parentCreateAt := model.GetMillis() - 100
-	data := ReactionImportData{
+	data := model.ReactionImportData{
 		User:      ptrStr("username"),
 		EmojiName: ptrStr("emoji"),
 		CreateAt:  ptrInt64(model.GetMillis()),
@@ -788,21 +788,21 @@ func TestImportValidateReactionImportData(t *testing.T) {
 	require.Nil(t, err, "Validation failed but should have been valid.")
 
 	// Test with missing required properties.
-	data = ReactionImportData{
+	data = model.ReactionImportData{
 		EmojiName: ptrStr("emoji"),
 		CreateAt:  ptrInt64(model.GetMillis()),
 	}
 	err = validateReactionImportData(&data, parentCreateAt)
 	require.NotNil(t, err, "Should have failed due to missing required property.")
 
-	data = ReactionImportData{
+	data = model.ReactionImportData{
 		User:     ptrStr("username"),
 		CreateAt: ptrInt64(model.GetMillis()),
 	}
 	err = validateReactionImportData(&data, parentCreateAt)
 	require.NotNil(t, err, "Should have failed due to missing required property.")
 
-	data = ReactionImportData{
+	data = model.ReactionImportData{
 		User:      ptrStr("username"),
 		EmojiName: ptrStr("emoji"),
 	}
@@ -810,7 +810,7 @@ func TestImportValidateReactionImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to missing required property.")
 
 	// Test with invalid emoji name.
-	data = ReactionImportData{
+	data = model.ReactionImportData{
 		User:      ptrStr("username"),
 		EmojiName: ptrStr(strings.Repeat("1234567890", 500)),
 		CreateAt:  ptrInt64(model.GetMillis()),
@@ -819,7 +819,7 @@ func TestImportValidateReactionImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to too long emoji name.")
 
 	// Test with invalid CreateAt
-	data = ReactionImportData{
+	data = model.ReactionImportData{
 		User:      ptrStr("username"),
 		EmojiName: ptrStr("emoji"),
 		CreateAt:  ptrInt64(0),
@@ -827,7 +827,7 @@ func TestImportValidateReactionImportData(t *testing.T) {
 	err = validateReactionImportData(&data, parentCreateAt)
 	require.NotNil(t, err, "Should have failed due to 0 create-at value.")
 
-	data = ReactionImportData{
+	data = model.ReactionImportData{
 		User:      ptrStr("username"),
 		EmojiName: ptrStr("emoji"),
 		CreateAt:  ptrInt64(parentCreateAt - 100),
@@ -836,11 +836,88 @@ func TestImportValidateReactionImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due parent with newer create-at value.")
 }
 
+func TestImportValidatePostRevisionImportData(t *testing.T) {
+	parentCreateAt := model.GetMillis() - 100
+	maxPostSize := 10000
+
+	data := model.PostRevisionImportData{
+		Message:  ptrStr("message"),
+		CreateAt: ptrInt64(model.GetMillis()),
+	}
+	err := validatePostRevisionImportData(&data, parentCreateAt, maxPostSize)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	data.Editor = ptrStr("username")
+	err = validatePostRevisionImportData(&data, parentCreateAt, maxPostSize)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	data = model.PostRevisionImportData{CreateAt: ptrInt64(model.GetMillis())}
+	err = validatePostRevisionImportData(&data, parentCreateAt, maxPostSize)
+	require.NotNil(t, err, "Should have failed due to missing required property.")
+
+	data = model.PostRevisionImportData{Message: ptrStr("message")}
+	err = validatePostRevisionImportData(&data, parentCreateAt, maxPostSize)
+	require.NotNil(t, err, "Should have failed due to missing required property.")
+
+	data = model.PostRevisionImportData{
+		Message:  ptrStr(strings.Repeat("0", maxPostSize+1)),
+		CreateAt: ptrInt64(model.GetMillis()),
+	}
+	err = validatePostRevisionImportData(&data, parentCreateAt, maxPostSize)
+	require.NotNil(t, err, "Should have failed due to too long message.")
+
+	data = model.PostRevisionImportData{
+		Message:  ptrStr("message"),
+		CreateAt: ptrInt64(parentCreateAt - 100),
+	}
+	err = validatePostRevisionImportData(&data, parentCreateAt, maxPostSize)
+	require.NotNil(t, err, "Should have failed due to create at before the parent.")
+
+	post := model.PostImportData{
+		Team:        ptrStr("teamname"),
+		Channel:     ptrStr("channelname"),
+		User:        ptrStr("username"),
+		Message:     ptrStr("message"),
+		CreateAt:    ptrInt64(parentCreateAt),
+		EditHistory: &[]model.PostRevisionImportData{data},
+	}
+	err = validatePostImportData(&post, maxPostSize)
+	require.NotNil(t, err, "Should have failed due to an invalid revision.")
+}
+
+func TestImportValidatePollVoteImportData(t *testing.T) {
+	postCreateAt := model.GetMillis() - 100
+
+	data := model.PollVoteImportData{
+		User:     ptrStr("username"),
+		Options:  &[]int{0, 2},
+		CreateAt: ptrInt64(model.GetMillis()),
+	}
+	err := validatePollVoteImportData(&data, postCreateAt)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	data = model.PollVoteImportData{Options: &[]int{0}, CreateAt: ptrInt64(model.GetMillis())}
+	err = validatePollVoteImportData(&data, postCreateAt)
+	require.NotNil(t, err, "Should have failed due to missing required property.")
+
+	data = model.PollVoteImportData{User: ptrStr("username"), Options: &[]int{}, CreateAt: ptrInt64(model.GetMillis())}
+	err = validatePollVoteImportData(&data, postCreateAt)
+	require.NotNil(t, err, "Should have failed due to no options.")
+
+	data = model.PollVoteImportData{User: ptrStr("username"), Options: &[]int{0}}
+	err = validatePollVoteImportData(&data, postCreateAt)
+	require.NotNil(t, err, "Should have failed due to missing required property.")
+
+	data = model.PollVoteImportData{User: ptrStr("username"), Options: &[]int{0}, CreateAt: ptrInt64(postCreateAt - 100)}
+	err = validatePollVoteImportData(&data, postCreateAt)
+	require.NotNil(t, err, "Should have failed due to create at before the post.")
+}
+
 func TestImportValidateReplyImportData(t *testing.T) {
 	// Test with minimum required valid properties.
 	parentCreateAt := model.GetMillis() - 100
 	maxPostSize := 10000
-	data := ReplyImportData{
+	data := model.ReplyImportData{
 		User:     ptrStr("username"),
 		Message:  ptrStr("message"),
 		CreateAt: ptrInt64(model.GetMillis()),
@@ -849,21 +926,21 @@ func TestImportValidateReplyImportData(t *testing.T) {
 	require.Nil(t, err, "Validation failed but should have been valid.")
 
 	// Test with missing required properties.
-	data = ReplyImportData{
+	data = model.ReplyImportData{
 		Message:  ptrStr("message"),
 		CreateAt: ptrInt64(model.GetMillis()),
 	}
 	err = validateReplyImportData(&data, parentCreateAt, maxPostSize)
 	require.NotNil(t, err, "Should have failed due to missing required property.")
 
-	data = ReplyImportData{
+	data = model.ReplyImportData{
 		User:     ptrStr("username"),
 		CreateAt: ptrInt64(model.GetMillis()),
 	}
 	err = validateReplyImportData(&data, parentCreateAt, maxPostSize)
 	require.NotNil(t, err, "Should have failed due to missing required property.")
 
-	data = ReplyImportData{
+	data = model.ReplyImportData{
 		User:    ptrStr("username"),
 		Message: ptrStr("message"),
 	}
@@ -871,7 +948,7 @@ func TestImportValidateReplyImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to missing required property.")
 
 	// Test with invalid message.
-	data = ReplyImportData{
+	data = model.ReplyImportData{
 		User:     ptrStr("username"),
 		Message:  ptrStr(strings.Repeat("0", maxPostSize+1)),
 		CreateAt: ptrInt64(model.GetMillis()),
@@ -880,7 +957,7 @@ func TestImportValidateReplyImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to too long message.")
 
 	// Test with invalid CreateAt
-	data = ReplyImportData{
+	data = model.ReplyImportData{
 		User:     ptrStr("username"),
 		Message:  ptrStr("message"),
 		CreateAt: ptrInt64(0),
@@ -888,7 +965,7 @@ func TestImportValidateReplyImportData(t *testing.T) {
 	err = validateReplyImportData(&data, parentCreateAt, maxPostSize)
 	require.NotNil(t, err, "Should have failed due to 0 create-at value.")
 
-	data = ReplyImportData{
+	data = model.ReplyImportData{
 		User:     ptrStr("username"),
 		Message:  ptrStr("message"),
 		CreateAt: ptrInt64(parentCreateAt - 100),
@@ -901,7 +978,7 @@ func TestImportValidatePostImportData(t *testing.T) {
 	maxPostSize := 10000
 
 	t.Run("Test with minimum required valid properties", func(t *testing.T) {
-		data := PostImportData{
+		data := model.PostImportData{
 			Team:     ptrStr("teamname"),
 			Channel:  ptrStr("channelname"),
 			User:     ptrStr("username"),
@@ -913,7 +990,7 @@ func TestImportValidatePostImportData(t *testing.T) {
 	})
 
 	t.Run("Test with missing required properties", func(t *testing.T) {
-		data := PostImportData{
+		data := model.PostImportData{
 			Channel:  ptrStr("channelname"),
 			User:     ptrStr("username"),
 			Message:  ptrStr("message"),
@@ -923,7 +1000,7 @@ func TestImportValidatePostImportData(t *testing.T) {
 		require.NotNil(t, err, "Should have failed due to missing required property.")
 		assert.Equal(t, err.Id, "app.import.validate_post_import_data.team_missing.error")
 
-		data = PostImportData{
+		data = model.PostImportData{
 			Team:     ptrStr("teamname"),
 			User:     ptrStr("username"),
 			Message:  ptrStr("message"),
@@ -933,7 +1010,7 @@ func TestImportValidatePostImportData(t *testing.T) {
 		require.NotNil(t, err, "Should have failed due to missing required property.")
 		assert.Equal(t, err.Id, "app.import.validate_post_import_data.channel_missing.error")
 
-		data = PostImportData{
+		data = model.PostImportData{
 			Team:     ptrStr("teamname"),
 			Channel:  ptrStr("channelname"),
 			Message:  ptrStr("message"),
@@ -943,7 +1020,7 @@ func TestImportValidatePostImportData(t *testing.T) {
 		require.NotNil(t, err, "Should have failed due to missing required property.")
 		assert.Equal(t, err.Id, "app.import.validate_post_import_data.user_missing.error")
 
-		data = PostImportData{
+		data = model.PostImportData{
 			Team:     ptrStr("teamname"),
 			Channel:  ptrStr("channelname"),
 			User:     ptrStr("username"),
@@ -953,7 +1030,7 @@ func TestImportValidatePostImportData(t *testing.T) {
 		require.NotNil(t, err, "Should have failed due to missing required property.")
 		assert.Equal(t, err.Id, "app.import.validate_post_import_data.message_missing.error")
 
-		data = PostImportData{
+		data = model.PostImportData{
 			Team:    ptrStr("teamname"),
 			Channel: ptrStr("channelname"),
 			User:    ptrStr("username"),
@@ -965,7 +1042,7 @@ func TestImportValidatePostImportData(t *testing.T) {
 	})
 
 	t.Run("Test with invalid message", func(t *testing.T) {
-		data := PostImportData{
+		data := model.PostImportData{
 			Team:     ptrStr("teamname"),
 			Channel:  ptrStr("channelname"),
 			User:     ptrStr("username"),
@@ -978,7 +1055,7 @@ func TestImportValidatePostImportData(t *testing.T) {
 	})
 
 	t.Run("Test with invalid CreateAt", func(t *testing.T) {
-		data := PostImportData{
+		data := model.PostImportData{
 			Team:     ptrStr("teamname"),
 			Channel:  ptrStr("channelname"),
 			User:     ptrStr("username"),
@@ -991,19 +1068,19 @@ func TestImportValidatePostImportData(t *testing.T) {
 	})
 
 	t.Run("Test with valid all optional parameters", func(t *testing.T) {
-		reactions := []ReactionImportData{{
+		reactions := []model.ReactionImportData{{
 			User:      ptrStr("username"),
 			EmojiName: ptrStr("emoji"),
 			CreateAt:  ptrInt64(model.GetMillis()),
 		}}
 
-		replies := []ReplyImportData{{
+		replies := []model.ReplyImportData{{
 			User:     ptrStr("username"),
 			Message:  ptrStr("message"),
 			CreateAt: ptrInt64(model.GetMillis()),
 		}}
 
-		data := PostImportData{
+		data := model.PostImportData{
 			Team:      ptrStr("teamname"),
 			Channel:   ptrStr("channelname"),
 			User:      ptrStr("username"),
@@ -1021,7 +1098,7 @@ func TestImportValidatePostImportData(t *testing.T) {
 			"attachment": strings.Repeat("a", model.PostPropsMaxRunes),
 		}
 
-		data := PostImportData{
+		data := model.PostImportData{
 			Team:     ptrStr("teamname"),
 			Channel:  ptrStr("channelname"),
 			User:     ptrStr("username"),
@@ -1038,7 +1115,7 @@ func TestImportValidatePostImportData(t *testing.T) {
 func TestImportValidateDirectChannelImportData(t *testing.T) {
 
 	// Test with valid number of members for direct message.
-	data := DirectChannelImportData{
+	data := model.DirectChannelImportData{
 		Members: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1048,7 +1125,7 @@ func TestImportValidateDirectChannelImportData(t *testing.T) {
 	require.Nil(t, err, "Validation failed but should have been valid.")
 
 	// Test with valid number of members for group message.
-	data = DirectChannelImportData{
+	data = model.DirectChannelImportData{
 		Members: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1059,7 +1136,7 @@ func TestImportValidateDirectChannelImportData(t *testing.T) {
 	require.Nil(t, err, "Validation failed but should have been valid.")
 
 	// Test with all the combinations of optional parameters.
-	data = DirectChannelImportData{
+	data = model.DirectChannelImportData{
 		Members: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1075,13 +1152,13 @@ func TestImportValidateDirectChannelImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to too long header.")
 
 	// Test with different combinations of invalid member counts.
-	data = DirectChannelImportData{
+	data = model.DirectChannelImportData{
 		Members: &[]string{},
 	}
 	err = validateDirectChannelImportData(&data)
 	require.NotNil(t, err, "Validation should have failed due to invalid number of members.")
 
-	data = DirectChannelImportData{
+	data = model.DirectChannelImportData{
 		Members: &[]string{
 			model.NewId(),
 		},
@@ -1089,7 +1166,7 @@ func TestImportValidateDirectChannelImportData(t *testing.T) {
 	err = validateDirectChannelImportData(&data)
 	require.NotNil(t, err, "Validation should have failed due to invalid number of members.")
 
-	data = DirectChannelImportData{
+	data = model.DirectChannelImportData{
 		Members: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1108,7 +1185,7 @@ func TestImportValidateDirectChannelImportData(t *testing.T) {
 	// Test with invalid FavoritedBy
 	member1 := model.NewId()
 	member2 := model.NewId()
-	data = DirectChannelImportData{
+	data = model.DirectChannelImportData{
 		Members: &[]string{
 			member1,
 			member2,
@@ -1122,7 +1199,7 @@ func TestImportValidateDirectChannelImportData(t *testing.T) {
 	require.NotNil(t, err, "Validation should have failed due to non-member favorited.")
 
 	// Test with valid FavoritedBy
-	data = DirectChannelImportData{
+	data = model.DirectChannelImportData{
 		Members: &[]string{
 			member1,
 			member2,
@@ -1140,7 +1217,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	maxPostSize := 10000
 
 	// Test with minimum required valid properties.
-	data := DirectPostImportData{
+	data := model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1153,7 +1230,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	require.Nil(t, err, "Validation failed but should have been valid.")
 
 	// Test with missing required properties.
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		User:     ptrStr("username"),
 		Message:  ptrStr("message"),
 		CreateAt: ptrInt64(model.GetMillis()),
@@ -1161,7 +1238,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	err = validateDirectPostImportData(&data, maxPostSize)
 	require.NotNil(t, err, "Should have failed due to missing required property.")
 
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1172,7 +1249,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	err = validateDirectPostImportData(&data, maxPostSize)
 	require.NotNil(t, err, "Should have failed due to missing required property.")
 
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1183,7 +1260,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	err = validateDirectPostImportData(&data, maxPostSize)
 	require.NotNil(t, err, "Should have failed due to missing required property.")
 
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1195,7 +1272,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to missing required property.")
 
 	// Test with invalid numbers of channel members.
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{},
 		User:           ptrStr("username"),
 		Message:        ptrStr("message"),
@@ -1204,7 +1281,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	err = validateDirectPostImportData(&data, maxPostSize)
 	require.NotNil(t, err, "Should have failed due to unsuitable number of members.")
 
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			model.NewId(),
 		},
@@ -1215,7 +1292,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	err = validateDirectPostImportData(&data, maxPostSize)
 	require.NotNil(t, err, "Should have failed due to unsuitable number of members.")
 
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1236,7 +1313,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to unsuitable number of members.")
 
 	// Test with group message number of members.
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1250,7 +1327,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	require.Nil(t, err, "Validation failed but should have been valid.")
 
 	// Test with invalid message.
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1263,7 +1340,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	require.NotNil(t, err, "Should have failed due to too long message.")
 
 	// Test with invalid CreateAt
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			model.NewId(),
 			model.NewId(),
@@ -1278,7 +1355,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	// Test with invalid FlaggedBy
 	member1 := model.NewId()
 	member2 := model.NewId()
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			member1,
 			member2,
@@ -1295,7 +1372,7 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	require.NotNil(t, err, "Validation should have failed due to non-member flagged.")
 
 	// Test with valid FlaggedBy
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			member1,
 			member2,
@@ -1312,19 +1389,19 @@ func TestImportValidateDirectPostImportData(t *testing.T) {
 	require.Nil(t, err, "Validation should succeed with post flagged by members")
 
 	// Test with valid all optional parameters.
-	reactions := []ReactionImportData{{
+	reactions := []model.ReactionImportData{{
 		User:      ptrStr("username"),
 		EmojiName: ptrStr("emoji"),
 		CreateAt:  ptrInt64(model.GetMillis()),
 	}}
 
-	replies := []ReplyImportData{{
+	replies := []model.ReplyImportData{{
 		User:     ptrStr("username"),
 		Message:  ptrStr("message"),
 		CreateAt: ptrInt64(model.GetMillis()),
 	}}
 
-	data = DirectPostImportData{
+	data = model.DirectPostImportData{
 		ChannelMembers: &[]string{
 			member1,
 			member2,
@@ -1364,7 +1441,7 @@ func TestImportValidateEmojiImportData(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.testName, func(t *testing.T) {
-			data := EmojiImportData{
+			data := model.EmojiImportData{
 				Name:  tc.name,
 				Image: tc.image,
 			}
@@ -1379,3 +1456,182 @@ func TestImportValidateEmojiImportData(t *testing.T) {
 		})
 	}
 }
+
+func TestImportValidateSidebarCategoriesImportData(t *testing.T) {
+	var testCases = []struct {
+		testName    string
+		displayName *string
+		sorting     *string
+		expectError bool
+	}{
+		{"success", ptrStr("Work"), ptrStr(string(model.SidebarCategorySortManual)), false},
+		{"no sorting", ptrStr("Work"), nil, false},
+		{"nil display name", nil, nil, true},
+		{"empty display name", ptrStr(""), nil, true},
+		{"invalid sorting", ptrStr("Work"), ptrStr("sideways"), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			data := []model.SidebarCategoryImportData{{
+				DisplayName: tc.displayName,
+				Sorting:     tc.sorting,
+			}}
+
+			err := validateSidebarCategoriesImportData(&data)
+			if tc.expectError {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestImportValidateBotImportData(t *testing.T) {
+	data := model.BotImportData{
+		Username: ptrStr("bot1"),
+		Owner:    ptrStr("owner1"),
+	}
+	err := validateBotImportData(&data)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	data.Username = ptrStr("not a username")
+	err = validateBotImportData(&data)
+	require.NotNil(t, err, "Should have failed due to invalid username.")
+
+	data.Username = ptrStr("bot1")
+	data.Owner = nil
+	err = validateBotImportData(&data)
+	require.NotNil(t, err, "Should have failed due to missing owner.")
+
+	data.Owner = ptrStr("owner1")
+	data.DisplayName = ptrStr(strings.Repeat("a", model.BotDisplayNameMaxRunes+1))
+	err = validateBotImportData(&data)
+	require.NotNil(t, err, "Should have failed due to too long display name.")
+
+	data.DisplayName = nil
+	data.Teams = &[]model.UserTeamImportData{{}}
+	err = validateBotImportData(&data)
+	require.NotNil(t, err, "Should have failed due to invalid team.")
+}
+
+func TestImportValidateCommandImportData(t *testing.T) {
+	data := model.CommandImportData{
+		Team:    ptrStr("team1"),
+		Creator: ptrStr("user1"),
+		Trigger: ptrStr("trigger"),
+		URL:     ptrStr("http://example.com"),
+		Method:  ptrStr(model.CommandMethodPost),
+	}
+	err := validateCommandImportData(&data)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	data.Team = nil
+	err = validateCommandImportData(&data)
+	require.NotNil(t, err, "Should have failed due to missing team.")
+
+	data.Team = ptrStr("team1")
+	data.Trigger = ptrStr("")
+	err = validateCommandImportData(&data)
+	require.NotNil(t, err, "Should have failed due to missing trigger.")
+
+	data.Trigger = ptrStr("trigger")
+	data.URL = ptrStr("example")
+	err = validateCommandImportData(&data)
+	require.NotNil(t, err, "Should have failed due to invalid url.")
+
+	data.URL = ptrStr("http://example.com")
+	data.Method = ptrStr("PUT")
+	err = validateCommandImportData(&data)
+	require.NotNil(t, err, "Should have failed due to invalid method.")
+}
+
+func TestImportValidateWebhookImportData(t *testing.T) {
+	incoming := model.IncomingWebhookImportData{
+		Team:    ptrStr("team1"),
+		Channel: ptrStr("channel1"),
+		Creator: ptrStr("user1"),
+	}
+	err := validateIncomingWebhookImportData(&incoming)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	incoming.Channel = nil
+	err = validateIncomingWebhookImportData(&incoming)
+	require.NotNil(t, err, "Should have failed due to missing channel.")
+
+	outgoing := model.OutgoingWebhookImportData{
+		Team:         ptrStr("team1"),
+		Creator:      ptrStr("user1"),
+		CallbackURLs: &[]string{"http://example.com"},
+		TriggerWords: &[]string{"word"},
+	}
+	err = validateOutgoingWebhookImportData(&outgoing)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	outgoing.TriggerWords = nil
+	err = validateOutgoingWebhookImportData(&outgoing)
+	require.NotNil(t, err, "Should have failed due to missing channel and trigger words.")
+
+	outgoing.Channel = ptrStr("channel1")
+	err = validateOutgoingWebhookImportData(&outgoing)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	outgoing.CallbackURLs = &[]string{}
+	err = validateOutgoingWebhookImportData(&outgoing)
+	require.NotNil(t, err, "Should have failed due to missing callback urls.")
+}
+
+func TestImportValidateChannelBookmarkImportData(t *testing.T) {
+	data := model.ChannelBookmarkImportData{
+		Team:        ptrStr("team1"),
+		Channel:     ptrStr("channel1"),
+		Owner:       ptrStr("user1"),
+		DisplayName: ptrStr("Docs"),
+		Type:        ptrStr(string(model.ChannelBookmarkLink)),
+		LinkUrl:     ptrStr("https://example.com/docs"),
+	}
+	err := validateChannelBookmarkImportData(&data)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	data.LinkUrl = ptrStr("example")
+	err = validateChannelBookmarkImportData(&data)
+	require.NotNil(t, err, "Should have failed due to invalid link url.")
+
+	data.Type = ptrStr(string(model.ChannelBookmarkFile))
+	err = validateChannelBookmarkImportData(&data)
+	require.NotNil(t, err, "Should have failed due to missing file.")
+
+	data.File = &model.AttachmentImportData{Path: ptrStr("handbook.txt")}
+	err = validateChannelBookmarkImportData(&data)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	data.Type = ptrStr("folder")
+	err = validateChannelBookmarkImportData(&data)
+	require.NotNil(t, err, "Should have failed due to invalid type.")
+
+	data.Type = ptrStr(string(model.ChannelBookmarkFile))
+	data.DisplayName = ptrStr("")
+	err = validateChannelBookmarkImportData(&data)
+	require.NotNil(t, err, "Should have failed due to missing display name.")
+}
+
+func TestImportValidateOAuthAppImportData(t *testing.T) {
+	data := model.OAuthAppImportData{
+		Creator:      ptrStr("user1"),
+		Name:         ptrStr("app"),
+		CallbackURLs: &[]string{"http://example.com/callback"},
+		Homepage:     ptrStr("http://example.com"),
+	}
+	err := validateOAuthAppImportData(&data)
+	require.Nil(t, err, "Validation failed but should have been valid.")
+
+	data.Name = ptrStr("")
+	err = validateOAuthAppImportData(&data)
+	require.NotNil(t, err, "Should have failed due to missing name.")
+
+	data.Name = ptrStr("app")
+	data.Homepage = ptrStr("example")
+	err = validateOAuthAppImportData(&data)
+	require.NotNil(t, err, "Should have failed due to invalid homepage.")
+}
@@ -242,6 +242,12 @@ func (a *App) DoPostActionWithCookie(c *request.Context, postID, actionId, userI
 		}
 		return "", nil
 	}
+	if strings.HasPrefix(upstreamURL, model.ReminderActionPathPrefix) {
+		if appErr = a.doLocalReminderRequest(c, upstreamURL, upstreamRequest); appErr != nil {
+			return "", appErr
+		}
+		return "", nil
+	}
 	requestJSON, jsonErr := json.Marshal(upstreamRequest)
 	if jsonErr != nil {
 		return "", model.NewAppError("DoPostActionWithCookie", "api.marshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
 	"github.com/cjdelisle/matterfoss-server/v6/store"
 )
 
@@ -65,9 +66,46 @@ func (a *App) GetJobsByTypes(jobTypes []string, offset int, limit int) ([]*model
 }
 
 func (a *App) CreateJob(job *model.Job) (*model.Job, *model.AppError) {
+	if job.Type == model.JobTypeImportProcess {
+		a.resumeImportJob(job)
+	}
+
 	return a.Srv().Jobs.CreateJob(job.Type, job.Data)
 }
 
+// resumeImportJob gives the import job the progress of the last failed job importing the
+// same file, so that the new job resumes the import where the failed one stopped.
+func (a *App) resumeImportJob(job *model.Job) {
+	if job.Data["import_file"] == "" {
+		return
+	}
+	if _, ok := job.Data["processed_lines"]; ok {
+		return
+	}
+
+	failedJobs, err := a.Srv().Store.Job().GetAllByTypeAndStatus(model.JobTypeImportProcess, model.JobStatusError)
+	if err != nil {
+		mlog.Warn("Failed to get the failed import jobs", mlog.Err(err))
+		return
+	}
+
+	// The failed jobs are sorted from the newest.
+	for _, failedJob := range failedJobs {
+		if failedJob.Data["import_file"] != job.Data["import_file"] ||
+			failedJob.Data["import_from"] != job.Data["import_from"] ||
+			failedJob.Data["team_id"] != job.Data["team_id"] {
+			continue
+		}
+
+		for _, key := range []string{"processed_lines", "failed_lines"} {
+			if value, ok := failedJob.Data[key]; ok {
+				job.Data[key] = value
+			}
+		}
+		return
+	}
+}
+
 func (a *App) CancelJob(jobId string) *model.AppError {
 	return a.Srv().Jobs.RequestCancellation(jobId)
 }
@@ -97,7 +135,9 @@ func (a *App) SessionHasPermissionToCreateJob(session model.Session, job *model.
 		model.JobTypeExportProcess,
 		model.JobTypeExportDelete,
 		model.JobTypeCloud,
-		model.JobTypeExtractContent:
+		model.JobTypeExtractContent,
+		model.JobTypeScheduledPosts,
+		model.JobTypeReminders:
 		return a.SessionHasPermissionTo(session, model.PermissionManageJobs), model.PermissionManageJobs
 	}
 
@@ -128,7 +168,9 @@ func (a *App) SessionHasPermissionToReadJob(session model.Session, jobType strin
 		model.JobTypeExportProcess,
 		model.JobTypeExportDelete,
 		model.JobTypeCloud,
-		model.JobTypeExtractContent:
+		model.JobTypeExtractContent,
+		model.JobTypeScheduledPosts,
+		model.JobTypeReminders:
 		return a.SessionHasPermissionTo(session, model.PermissionReadJobs), model.PermissionReadJobs
 	}
 
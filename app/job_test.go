@@ -306,3 +306,57 @@ func TestGetJobsByTypes(t *testing.T) {
 	require.Equal(t, statuses[2], received[0], "received wrong job type")
 	require.Equal(t, statuses[1], received[1], "received wrong job type")
 }
+
+func TestCreateImportJobResumesFailedJob(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	importFile := model.NewId() + "_export.zip"
+	failedJob, err := th.App.Srv().Store.Job().Save(&model.Job{
+		Id:     model.NewId(),
+		Type:   model.JobTypeImportProcess,
+		Status: model.JobStatusError,
+		Data: map[string]string{
+			"import_file":     importFile,
+			"import_from":     "slack",
+			"team_id":         th.BasicTeam.Id,
+			"processed_lines": "120",
+			"failed_lines":    "3",
+			"line_number":     "121",
+		},
+	})
+	require.NoError(t, err)
+	defer th.App.Srv().Store.Job().Delete(failedJob.Id)
+
+	t.Run("same file", func(t *testing.T) {
+		job, appErr := th.App.CreateJob(&model.Job{
+			Type: model.JobTypeImportProcess,
+			Data: map[string]string{
+				"import_file": importFile,
+				"import_from": "slack",
+				"team_id":     th.BasicTeam.Id,
+			},
+		})
+		require.Nil(t, appErr)
+		defer th.App.Srv().Store.Job().Delete(job.Id)
+
+		assert.Equal(t, "120", job.Data["processed_lines"])
+		assert.Equal(t, "3", job.Data["failed_lines"])
+		assert.Empty(t, job.Data["line_number"])
+	})
+
+	t.Run("other file", func(t *testing.T) {
+		job, appErr := th.App.CreateJob(&model.Job{
+			Type: model.JobTypeImportProcess,
+			Data: map[string]string{
+				"import_file": model.NewId() + "_export.zip",
+				"import_from": "slack",
+				"team_id":     th.BasicTeam.Id,
+			},
+		})
+		require.Nil(t, appErr)
+		defer th.App.Srv().Store.Job().Delete(job.Id)
+
+		assert.Empty(t, job.Data["processed_lines"])
+	})
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/cjdelisle/matterfoss-server/v6/audit"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mfa"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+const (
+	TokenTypeMfaReset         = "mfa_reset"
+	TokenTypeMfaResetApproval = "mfa_reset_approval"
+	MfaResetExpiryTime        = 1000 * 60 * 60 // 1 hour
+)
+
+type mfaResetTokenExtra struct {
+	UserId string
+	Email  string
+}
+
+// GenerateMfaRecoveryCodes generates new recovery codes for the user, replacing the previous
+// ones. The codes are only returned here, as only their hashes are stored.
+func (a *App) GenerateMfaRecoveryCodes(userID string) ([]string, *model.AppError) {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if !*a.Config().ServiceSettings.EnableMultifactorAuthentication {
+		return nil, model.NewAppError("GenerateMfaRecoveryCodes", "mfa.mfa_disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	if !user.MfaActive {
+		return nil, model.NewAppError("GenerateMfaRecoveryCodes", "app.mfa_recovery_code.mfa_inactive.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	codes := mfa.GenerateRecoveryCodes()
+	recoveryCodes := make([]*model.MfaRecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		recoveryCodes = append(recoveryCodes, &model.MfaRecoveryCode{CodeHash: mfa.HashRecoveryCode(code)})
+	}
+
+	if err := a.Srv().Store.MfaRecoveryCode().Replace(userID, recoveryCodes); err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("GenerateMfaRecoveryCodes", "app.mfa_recovery_code.save.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	return codes, nil
+}
+
+// RegenerateMfaRecoveryCodes generates new recovery codes for the user, who must confirm it
+// with a multi-factor authentication token or an unused recovery code.
+func (a *App) RegenerateMfaRecoveryCodes(userID, token string) ([]string, *model.AppError) {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if !user.MfaActive {
+		return nil, model.NewAppError("RegenerateMfaRecoveryCodes", "app.mfa_recovery_code.mfa_inactive.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if appErr := a.CheckUserMfa(user, token); appErr != nil {
+		return nil, appErr
+	}
+
+	return a.GenerateMfaRecoveryCodes(userID)
+}
+
+// useMfaRecoveryCode completes the multi-factor authentication of the user with one of the
+// recovery codes, which can't be used again.
+func (a *App) useMfaRecoveryCode(user *model.User, code string) *model.AppError {
+	auditRec := a.MakeAuditRecord("useMfaRecoveryCode", audit.Fail)
+	defer a.LogAuditRec(auditRec, nil)
+	auditRec.AddMeta("user_id", user.Id)
+
+	if err := a.Srv().Store.MfaRecoveryCode().Use(user.Id, mfa.HashRecoveryCode(code), model.GetMillis()); err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return model.NewAppError("useMfaRecoveryCode", "api.user.check_user_mfa.bad_code.app_error", nil, "", http.StatusUnauthorized)
+		default:
+			return model.NewAppError("useMfaRecoveryCode", "app.mfa_recovery_code.use.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	if remaining, err := a.Srv().Store.MfaRecoveryCode().CountUnusedForUser(user.Id); err == nil {
+		auditRec.AddMeta("remaining", remaining)
+	}
+	auditRec.Success()
+
+	return nil
+}
+
+func (a *App) checkMfaSelfServiceResetEnabled(where string) *model.AppError {
+	if !*a.Config().ServiceSettings.EnableMultifactorAuthentication || !*a.Config().ServiceSettings.EnableMfaSelfServiceReset {
+		return model.NewAppError(where, "app.mfa_reset.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+// SendMfaReset emails the user with the given email a link to reset the multi-factor
+// authentication. Whether the user exists is not disclosed, false is returned when no
+// email was sent.
+func (a *App) SendMfaReset(email string, siteURL string) (bool, *model.AppError) {
+	if appErr := a.checkMfaSelfServiceResetEnabled("SendMfaReset"); appErr != nil {
+		return false, appErr
+	}
+
+	user, appErr := a.GetUserByEmail(email)
+	if appErr != nil {
+		return false, nil
+	}
+
+	if !user.MfaActive || user.IsSSOUser() && !user.IsLDAPUser() {
+		return false, nil
+	}
+
+	extra, err := json.Marshal(&mfaResetTokenExtra{UserId: user.Id, Email: user.Email})
+	if err != nil {
+		return false, model.NewAppError("SendMfaReset", "app.mfa_reset.save_token.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	token := model.NewToken(TokenTypeMfaReset, string(extra))
+	if err := a.Srv().Store.Token().Save(token); err != nil {
+		return false, model.NewAppError("SendMfaReset", "app.mfa_reset.save_token.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	sent, err := a.Srv().EmailService.SendMfaResetEmail(user.Email, token, user.Locale, siteURL)
+	if err != nil {
+		return sent, model.NewAppError("SendMfaReset", "app.mfa_reset.send.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return sent, nil
+}
+
+// ResetMfaFromToken resets the multi-factor authentication of the user the given token
+// was emailed to, and returns model.MfaResetStatusCompleted. When the reset requires the
+// approval of an administrator, it is only requested and model.MfaResetStatusPending is
+// returned.
+func (a *App) ResetMfaFromToken(tokenString string) (string, *model.AppError) {
+	if appErr := a.checkMfaSelfServiceResetEnabled("ResetMfaFromToken"); appErr != nil {
+		return "", appErr
+	}
+
+	token, err := a.Srv().Store.Token().GetByToken(tokenString)
+	if err != nil || token.Type != TokenTypeMfaReset {
+		return "", model.NewAppError("ResetMfaFromToken", "app.mfa_reset.invalid_link.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if err := a.Srv().Store.Token().Delete(token.Token); err != nil {
+		return "", model.NewAppError("ResetMfaFromToken", "app.mfa_reset.delete_token.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if model.GetMillis()-token.CreateAt >= MfaResetExpiryTime {
+		return "", model.NewAppError("ResetMfaFromToken", "app.mfa_reset.link_expired.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	var extra mfaResetTokenExtra
+	if err := json.Unmarshal([]byte(token.Extra), &extra); err != nil {
+		return "", model.NewAppError("ResetMfaFromToken", "app.mfa_reset.invalid_link.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	user, appErr := a.GetUser(extra.UserId)
+	if appErr != nil {
+		return "", appErr
+	}
+
+	if user.Email != extra.Email {
+		return "", model.NewAppError("ResetMfaFromToken", "app.mfa_reset.link_expired.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !*a.Config().ServiceSettings.MfaResetRequiresApproval {
+		if appErr := a.UpdateMfa(false, user.Id, ""); appErr != nil {
+			return "", appErr
+		}
+		return model.MfaResetStatusCompleted, nil
+	}
+
+	requests, appErr := a.getMfaResetApprovalTokens(user.Id)
+	if appErr != nil {
+		return "", appErr
+	}
+	if len(requests) > 0 {
+		return model.MfaResetStatusPending, nil
+	}
+
+	approval := model.NewToken(TokenTypeMfaResetApproval, token.Extra)
+	if err := a.Srv().Store.Token().Save(approval); err != nil {
+		return "", model.NewAppError("ResetMfaFromToken", "app.mfa_reset.save_token.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return model.MfaResetStatusPending, nil
+}
+
+// getMfaResetApprovalTokens returns the tokens of the resets awaiting an approval, of the
+// given user or of all users when no user is given.
+func (a *App) getMfaResetApprovalTokens(userID string) (map[*model.Token]*mfaResetTokenExtra, *model.AppError) {
+	tokens, err := a.Srv().Store.Token().GetAllTokensByType(TokenTypeMfaResetApproval)
+	if err != nil {
+		return nil, model.NewAppError("getMfaResetApprovalTokens", "app.mfa_reset.get_requests.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	requests := map[*model.Token]*mfaResetTokenExtra{}
+	for _, token := range tokens {
+		var extra mfaResetTokenExtra
+		if err := json.Unmarshal([]byte(token.Extra), &extra); err != nil {
+			mlog.Warn("Failed to parse an MFA reset request", mlog.Err(err))
+			continue
+		}
+		if userID == "" || extra.UserId == userID {
+			requests[token] = &extra
+		}
+	}
+	return requests, nil
+}
+
+// GetMfaResetRequests returns the resets of the multi-factor authentication awaiting the
+// approval of an administrator.
+func (a *App) GetMfaResetRequests() ([]*model.MfaResetRequest, *model.AppError) {
+	tokens, appErr := a.getMfaResetApprovalTokens("")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	requests := make([]*model.MfaResetRequest, 0, len(tokens))
+	for token, extra := range tokens {
+		requests = append(requests, &model.MfaResetRequest{UserId: extra.UserId, CreateAt: token.CreateAt})
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].CreateAt < requests[j].CreateAt
+	})
+	return requests, nil
+}
+
+// deleteMfaResetRequest deletes the reset of the user awaiting an approval, failing when
+// there is none.
+func (a *App) deleteMfaResetRequest(where, userID string) *model.AppError {
+	tokens, appErr := a.getMfaResetApprovalTokens(userID)
+	if appErr != nil {
+		return appErr
+	}
+	if len(tokens) == 0 {
+		return model.NewAppError(where, "app.mfa_reset.request_not_found.app_error", nil, "user_id="+userID, http.StatusNotFound)
+	}
+
+	for token := range tokens {
+		if err := a.Srv().Store.Token().Delete(token.Token); err != nil {
+			return model.NewAppError(where, "app.mfa_reset.delete_token.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+	return nil
+}
+
+// ApproveMfaReset resets the multi-factor authentication of the user, as requested by the
+// user.
+func (a *App) ApproveMfaReset(userID string) *model.AppError {
+	if appErr := a.deleteMfaResetRequest("ApproveMfaReset", userID); appErr != nil {
+		return appErr
+	}
+
+	return a.UpdateMfa(false, userID, "")
+}
+
+// DenyMfaReset rejects the reset of the multi-factor authentication requested by the user.
+func (a *App) DenyMfaReset(userID string) *model.AppError {
+	return a.deleteMfaResetRequest("DenyMfaReset", userID)
+}
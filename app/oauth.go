@@ -6,7 +6,10 @@ package app
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
 	b64 "encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -32,6 +35,11 @@ const (
 	OAuthCookieMaxAgeSeconds = 30 * 60 // 30 minutes
 	CookieOAuth              = "MMOAUTH"
 	OpenIDScope              = "openid"
+
+	// TokenTypeOAuthUsedRefreshToken marks the refresh tokens which were rotated, so that a
+	// stolen refresh token used again is detected. As refresh tokens don't expire, the marks
+	// are kept as long as the app is authorized for the user.
+	TokenTypeOAuthUsedRefreshToken = "oauth_used_refresh_token"
 )
 
 func (a *App) CreateOAuthApp(app *model.OAuthApp) (*model.OAuthApp, *model.AppError) {
@@ -163,7 +171,7 @@ func (a *App) GetOAuthImplicitRedirect(userID string, authRequest *model.Authori
 }
 
 func (a *App) GetOAuthCodeRedirect(userID string, authRequest *model.AuthorizeRequest) (string, *model.AppError) {
-	authData := &model.AuthData{UserId: userID, ClientId: authRequest.ClientId, CreateAt: model.GetMillis(), RedirectUri: authRequest.RedirectURI, State: authRequest.State, Scope: authRequest.Scope}
+	authData := &model.AuthData{UserId: userID, ClientId: authRequest.ClientId, CreateAt: model.GetMillis(), RedirectUri: authRequest.RedirectURI, State: authRequest.State, Scope: authRequest.Scope, CodeChallenge: authRequest.CodeChallenge, CodeChallengeMethod: authRequest.CodeChallengeMethod, Nonce: authRequest.Nonce}
 	authData.Code = model.NewId() + model.NewId()
 
 	if _, err := a.Srv().Store.OAuth().SaveAuthData(authData); err != nil {
@@ -178,9 +186,7 @@ func (a *App) AllowOAuthAppAccessToUser(userID string, authRequest *model.Author
 		return "", model.NewAppError("AllowOAuthAppAccessToUser", "api.oauth.allow_oauth.turn_off.app_error", nil, "", http.StatusNotImplemented)
 	}
 
-	if authRequest.Scope == "" {
-		authRequest.Scope = model.DefaultScope
-	}
+	authRequest.Scope = model.NormalizeOAuthScope(authRequest.Scope)
 
 	oauthApp, nErr := a.Srv().Store.OAuth().GetApp(authRequest.ClientId)
 	if nErr != nil {
@@ -197,6 +203,11 @@ func (a *App) AllowOAuthAppAccessToUser(userID string, authRequest *model.Author
 		return "", model.NewAppError("AllowOAuthAppAccessToUser", "api.oauth.allow_oauth.redirect_callback.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	// Public apps can't authenticate the exchange of the code without PKCE
+	if oauthApp.IsPublic && authRequest.ResponseType == model.AuthCodeResponseType && authRequest.CodeChallenge == "" {
+		return authRequest.RedirectURI + "?error=invalid_request&state=" + authRequest.State, nil
+	}
+
 	var redirectURI string
 	var err *model.AppError
 	switch authRequest.ResponseType {
@@ -244,12 +255,13 @@ func (a *App) GetOAuthAccessTokenForImplicitFlow(userID string, authRequest *mod
 		return nil, err
 	}
 
-	session, err := a.newSession(oauthApp, user)
+	scope := model.NormalizeOAuthScope(authRequest.Scope)
+	session, err := a.newSession(oauthApp, user, scope)
 	if err != nil {
 		return nil, err
 	}
 
-	accessData := &model.AccessData{ClientId: authRequest.ClientId, UserId: user.Id, Token: session.Token, RefreshToken: "", RedirectUri: authRequest.RedirectURI, ExpiresAt: session.ExpiresAt, Scope: authRequest.Scope}
+	accessData := &model.AccessData{ClientId: authRequest.ClientId, UserId: user.Id, Token: session.Token, RefreshToken: "", RedirectUri: authRequest.RedirectURI, ExpiresAt: session.ExpiresAt, Scope: scope}
 
 	if _, err := a.Srv().Store.OAuth().SaveAccessData(accessData); err != nil {
 		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_saving.app_error", nil, "", http.StatusInternalServerError)
@@ -258,27 +270,52 @@ func (a *App) GetOAuthAccessTokenForImplicitFlow(userID string, authRequest *mod
 	return session, nil
 }
 
-func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectURI, code, secret, refreshToken string) (*model.AccessResponse, *model.AppError) {
-	if !*a.Config().ServiceSettings.EnableOAuthServiceProvider {
-		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.disabled.app_error", nil, "", http.StatusNotImplemented)
+// authenticateOAuthClient returns the OAuth app with the given client id once authenticated
+// with its client secret. Public apps, whose client secret isn't confidential, are only
+// accepted when allowPublic is set.
+func (a *App) authenticateOAuthClient(where, clientId, secret string, allowPublic bool) (*model.OAuthApp, *model.AppError) {
+	oauthApp, err := a.Srv().Store.OAuth().GetApp(clientId)
+	if err != nil {
+		return nil, model.NewAppError(where, "api.oauth.get_access_token.credentials.app_error", nil, "", http.StatusNotFound)
 	}
 
-	oauthApp, nErr := a.Srv().Store.OAuth().GetApp(clientId)
-	if nErr != nil {
-		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.credentials.app_error", nil, "", http.StatusNotFound)
+	if oauthApp.IsPublic {
+		if !allowPublic {
+			return nil, model.NewAppError(where, "api.oauth.get_access_token.credentials.app_error", nil, "public client", http.StatusUnauthorized)
+		}
+		return oauthApp, nil
 	}
 
-	if oauthApp.ClientSecret != secret {
-		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.credentials.app_error", nil, "", http.StatusForbidden)
+	if secret == "" {
+		return nil, model.NewAppError(where, "api.oauth.get_access_token.bad_client_secret.app_error", nil, "", http.StatusBadRequest)
 	}
 
+	if subtle.ConstantTimeCompare([]byte(oauthApp.ClientSecret), []byte(secret)) != 1 {
+		return nil, model.NewAppError(where, "api.oauth.get_access_token.credentials.app_error", nil, "", http.StatusForbidden)
+	}
+
+	return oauthApp, nil
+}
+
+func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectURI, code, codeVerifier, secret, refreshToken string) (*model.AccessResponse, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableOAuthServiceProvider {
+		return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	oauthApp, appErr := a.authenticateOAuthClient("GetOAuthAccessToken", clientId, secret, true)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var nErr error
 	var accessData *model.AccessData
 	var accessRsp *model.AccessResponse
 	var user *model.User
+	var nonce string
 	if grantType == model.AccessTokenGrantType {
 		var authData *model.AuthData
 		authData, nErr = a.Srv().Store.OAuth().GetAuthData(code)
-		if nErr != nil {
+		if nErr != nil || authData.ClientId != clientId {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.expired_code.app_error", nil, "", http.StatusBadRequest)
 		}
 
@@ -293,6 +330,10 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectURI, c
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.redirect_uri.app_error", nil, "", http.StatusBadRequest)
 		}
 
+		if (oauthApp.IsPublic && authData.CodeChallenge == "") || !authData.VerifyCodeVerifier(codeVerifier) {
+			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.code_verifier.app_error", nil, "", http.StatusBadRequest)
+		}
+
 		user, nErr = a.Srv().Store.User().Get(context.Background(), authData.UserId)
 		if nErr != nil {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_user.app_error", nil, "", http.StatusNotFound)
@@ -304,7 +345,9 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectURI, c
 		}
 
 		if accessData != nil {
-			if accessData.IsExpired() {
+			if accessData.IsExpired() || accessData.Scope != authData.Scope {
+				// The session is replaced as well when the user authorized other scopes
+				accessData.Scope = authData.Scope
 				var access *model.AccessResponse
 				access, err := a.newSessionUpdateToken(oauthApp, accessData, user)
 				if err != nil {
@@ -318,12 +361,13 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectURI, c
 					TokenType:    model.AccessTokenType,
 					RefreshToken: accessData.RefreshToken,
 					ExpiresIn:    int32((accessData.ExpiresAt - model.GetMillis()) / 1000),
+					Scope:        accessData.Scope,
 				}
 			}
 		} else {
 			var session *model.Session
 			// Create a new session and return new access token
-			session, err := a.newSession(oauthApp, user)
+			session, err := a.newSession(oauthApp, user, authData.Scope)
 			if err != nil {
 				return nil, err
 			}
@@ -339,20 +383,29 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectURI, c
 				TokenType:    model.AccessTokenType,
 				RefreshToken: accessData.RefreshToken,
 				ExpiresIn:    int32(*a.Config().ServiceSettings.SessionLengthSSOInDays * 60 * 60 * 24),
+				Scope:        accessData.Scope,
 			}
 		}
 
 		if nErr = a.Srv().Store.OAuth().RemoveAuthData(authData.Code); nErr != nil {
 			mlog.Warn("unable to remove auth data", mlog.Err(nErr))
 		}
+		nonce = authData.Nonce
 	} else {
 		// When grantType is refresh_token
 		accessData, nErr = a.Srv().Store.OAuth().GetAccessDataByRefreshToken(refreshToken)
 		if nErr != nil {
+			if appErr := a.checkOAuthRefreshTokenReuse(oauthApp, refreshToken); appErr != nil {
+				return nil, appErr
+			}
+			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.refresh_token.app_error", nil, "", http.StatusNotFound)
+		}
+
+		if accessData.ClientId != oauthApp.Id {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.refresh_token.app_error", nil, "", http.StatusNotFound)
 		}
 
-		user, nErr := a.Srv().Store.User().Get(context.Background(), accessData.UserId)
+		user, nErr = a.Srv().Store.User().Get(context.Background(), accessData.UserId)
 		if nErr != nil {
 			return nil, model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.internal_user.app_error", nil, "", http.StatusNotFound)
 		}
@@ -364,10 +417,19 @@ func (a *App) GetOAuthAccessTokenForCodeFlow(clientId, grantType, redirectURI, c
 		accessRsp = access
 	}
 
+	// OpenID Connect clients are told who the user is by an ID token
+	if model.IsOAuthScopeGranted(accessRsp.Scope, model.OAuthScopeOpenID) {
+		idToken, appErr := a.newOpenIDToken(oauthApp, user, accessRsp.Scope, nonce)
+		if appErr != nil {
+			return nil, appErr
+		}
+		accessRsp.IdToken = idToken
+	}
+
 	return accessRsp, nil
 }
 
-func (a *App) newSession(app *model.OAuthApp, user *model.User) (*model.Session, *model.AppError) {
+func (a *App) newSession(app *model.OAuthApp, user *model.User, scope string) (*model.Session, *model.AppError) {
 	// Set new token an session
 	session := &model.Session{UserId: user.Id, Roles: user.Roles, IsOAuth: true}
 	session.GenerateCSRF()
@@ -377,6 +439,7 @@ func (a *App) newSession(app *model.OAuthApp, user *model.User) (*model.Session,
 	session.AddProp(model.SessionPropMatterfossAppID, app.MatterfossAppID)
 	session.AddProp(model.SessionPropOs, "OAuth2")
 	session.AddProp(model.SessionPropBrowser, "OAuth2")
+	session.AddProp(model.SessionPropOAuthScope, scope)
 
 	session, err := a.Srv().Store.Session().Save(session)
 	if err != nil {
@@ -394,11 +457,17 @@ func (a *App) newSessionUpdateToken(app *model.OAuthApp, accessData *model.Acces
 		mlog.Warn("error removing access data token from session", mlog.Err(err))
 	}
 
-	session, err := a.newSession(app, user)
+	session, err := a.newSession(app, user, accessData.Scope)
 	if err != nil {
 		return nil, err
 	}
 
+	if accessData.RefreshToken != "" {
+		if appErr := a.saveUsedOAuthRefreshToken(accessData); appErr != nil {
+			mlog.Warn("Unable to save the rotated OAuth refresh token", mlog.String("client_id", accessData.ClientId), mlog.Err(appErr))
+		}
+	}
+
 	accessData.Token = session.Token
 	accessData.RefreshToken = model.NewId()
 	accessData.ExpiresAt = session.ExpiresAt
@@ -411,11 +480,178 @@ func (a *App) newSessionUpdateToken(app *model.OAuthApp, accessData *model.Acces
 		RefreshToken: accessData.RefreshToken,
 		TokenType:    model.AccessTokenType,
 		ExpiresIn:    int32(*a.Config().ServiceSettings.SessionLengthSSOInDays * 60 * 60 * 24),
+		Scope:        accessData.Scope,
 	}
 
 	return accessRsp, nil
 }
 
+func hashOAuthRefreshToken(refreshToken string) string {
+	hash := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(hash[:])
+}
+
+// saveUsedOAuthRefreshToken remembers the refresh token of the access data, which is about to
+// be rotated. Only its hash is saved.
+func (a *App) saveUsedOAuthRefreshToken(accessData *model.AccessData) *model.AppError {
+	token := &model.Token{
+		Token:    hashOAuthRefreshToken(accessData.RefreshToken),
+		CreateAt: model.GetMillis(),
+		Type:     TokenTypeOAuthUsedRefreshToken,
+		Extra:    model.MapToJSON(map[string]string{"client_id": accessData.ClientId, "user_id": accessData.UserId}),
+	}
+
+	if err := a.Srv().Store.Token().Save(token); err != nil {
+		return model.NewAppError("saveUsedOAuthRefreshToken", "app.oauth.save_used_refresh_token.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// cleanupUsedOAuthRefreshTokens removes the marks of the rotated refresh tokens of the apps
+// which are no longer authorized for their user, as no token issued to them is left.
+func cleanupUsedOAuthRefreshTokens(s *Server) {
+	tokens, err := s.Store.Token().GetAllTokensByType(TokenTypeOAuthUsedRefreshToken)
+	if err != nil {
+		mlog.Warn("Failed to get the used OAuth refresh tokens", mlog.Err(err))
+		return
+	}
+
+	authorized := map[string]bool{}
+	for _, token := range tokens {
+		extra := model.MapFromJSON(strings.NewReader(token.Extra))
+		key := extra["client_id"] + ":" + extra["user_id"]
+		if _, ok := authorized[key]; !ok {
+			accessData, err := s.Store.OAuth().GetAccessDataByUserForApp(extra["user_id"], extra["client_id"])
+			if err != nil {
+				mlog.Warn("Failed to get the OAuth access data of a used refresh token", mlog.String("client_id", extra["client_id"]), mlog.Err(err))
+				continue
+			}
+			authorized[key] = len(accessData) > 0
+		}
+
+		if authorized[key] {
+			continue
+		}
+		if err := s.Store.Token().Delete(token.Token); err != nil {
+			mlog.Warn("Failed to delete a used OAuth refresh token", mlog.Err(err))
+		}
+	}
+}
+
+// checkOAuthRefreshTokenReuse fails when the refresh token was already rotated. As either the
+// app or whoever stole the token used it again, every token issued to the app for the user is
+// revoked.
+func (a *App) checkOAuthRefreshTokenReuse(oauthApp *model.OAuthApp, refreshToken string) *model.AppError {
+	token, err := a.Srv().Store.Token().GetByToken(hashOAuthRefreshToken(refreshToken))
+	if err != nil || token.Type != TokenTypeOAuthUsedRefreshToken {
+		return nil
+	}
+
+	extra := model.MapFromJSON(strings.NewReader(token.Extra))
+	if extra["client_id"] != oauthApp.Id {
+		return nil
+	}
+
+	mlog.Warn("A rotated OAuth refresh token was used again, revoking the tokens of the app", mlog.String("client_id", oauthApp.Id), mlog.String("user_id", extra["user_id"]))
+
+	accessData, err := a.Srv().Store.OAuth().GetAccessDataByUserForApp(extra["user_id"], oauthApp.Id)
+	if err != nil {
+		return model.NewAppError("checkOAuthRefreshTokenReuse", "app.oauth.get_access_data_by_user_for_app.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	for _, ad := range accessData {
+		if appErr := a.RevokeAccessToken(ad.Token); appErr != nil {
+			return appErr
+		}
+	}
+
+	return model.NewAppError("GetOAuthAccessToken", "api.oauth.get_access_token.refresh_token_reused.app_error", nil, "", http.StatusBadRequest)
+}
+
+// getOAuthAccessDataForToken returns the access data of the given access or refresh token
+// issued to the app, or nil when there is none. isAccessToken is set when the token is an
+// access token.
+func (a *App) getOAuthAccessDataForToken(oauthApp *model.OAuthApp, token string) (accessData *model.AccessData, isAccessToken bool) {
+	accessData, err := a.Srv().Store.OAuth().GetAccessData(token)
+	isAccessToken = err == nil
+	if err != nil {
+		if accessData, err = a.Srv().Store.OAuth().GetAccessDataByRefreshToken(token); err != nil {
+			return nil, false
+		}
+	}
+
+	if accessData.ClientId != oauthApp.Id {
+		return nil, false
+	}
+	return accessData, isAccessToken
+}
+
+// IntrospectOAuthToken returns the state of an access or refresh token issued to the app, as
+// defined by RFC 7662. The tokens issued to other apps are reported as inactive.
+func (a *App) IntrospectOAuthToken(clientId, secret, token string) (*model.IntrospectionResponse, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableOAuthServiceProvider {
+		return nil, model.NewAppError("IntrospectOAuthToken", "api.oauth.allow_oauth.turn_off.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	oauthApp, appErr := a.authenticateOAuthClient("IntrospectOAuthToken", clientId, secret, false)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	inactive := &model.IntrospectionResponse{Active: false}
+
+	accessData, isAccessToken := a.getOAuthAccessDataForToken(oauthApp, token)
+	if accessData == nil {
+		return inactive, nil
+	}
+
+	user, appErr := a.GetUser(accessData.UserId)
+	if appErr != nil || user.DeleteAt != 0 {
+		return inactive, nil
+	}
+
+	rsp := &model.IntrospectionResponse{
+		Active:   true,
+		Scope:    accessData.Scope,
+		ClientId: accessData.ClientId,
+		Username: user.Username,
+		Subject:  user.Id,
+	}
+
+	if isAccessToken {
+		session, err := a.Srv().Store.Session().Get(context.Background(), accessData.Token)
+		if err != nil || session.IsExpired() {
+			return inactive, nil
+		}
+
+		rsp.TokenType = model.AccessTokenType
+		rsp.ExpiresAt = session.ExpiresAt / 1000
+		rsp.IssuedAt = session.CreateAt / 1000
+	}
+
+	return rsp, nil
+}
+
+// RevokeOAuthToken revokes an access or refresh token issued to the app, along with the token
+// issued with it, as defined by RFC 7009. Unknown tokens are ignored.
+func (a *App) RevokeOAuthToken(clientId, secret, token string) *model.AppError {
+	if !*a.Config().ServiceSettings.EnableOAuthServiceProvider {
+		return model.NewAppError("RevokeOAuthToken", "api.oauth.allow_oauth.turn_off.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	oauthApp, appErr := a.authenticateOAuthClient("RevokeOAuthToken", clientId, secret, true)
+	if appErr != nil {
+		return appErr
+	}
+
+	accessData, _ := a.getOAuthAccessDataForToken(oauthApp, token)
+	if accessData == nil {
+		return nil
+	}
+
+	return a.RevokeAccessToken(accessData.Token)
+}
+
 func (a *App) GetOAuthLoginEndpoint(w http.ResponseWriter, r *http.Request, service, teamID, action, redirectTo, loginHint string, isMobile bool) (string, *model.AppError) {
 	stateProps := map[string]string{}
 	stateProps["action"] = action
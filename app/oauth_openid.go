@@ -0,0 +1,239 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const (
+	// openIDSigningKeyRotationPeriod is how long a key signs the ID tokens before being
+	// replaced. Each period has a key of its own, saved in the System table by the first
+	// server needing it, so that all the servers of a cluster agree on the keys.
+	openIDSigningKeyRotationPeriod = 30 * 24 * time.Hour
+
+	openIDSigningKeyBits = 2048
+)
+
+type openIDSigningKey struct {
+	kid string
+	key *rsa.PrivateKey
+}
+
+func openIDSigningKeyPeriod(t time.Time) int64 {
+	return t.Unix() / int64(openIDSigningKeyRotationPeriod/time.Second)
+}
+
+// getOpenIDSigningKey returns the signing key of the given rotation period. When the key
+// doesn't exist yet, it is generated if create is set, and nil is returned otherwise.
+func (ch *Channels) getOpenIDSigningKey(period int64, create bool) (*openIDSigningKey, error) {
+	ch.openIDSigningKeysMut.Lock()
+	defer ch.openIDSigningKeysMut.Unlock()
+
+	if key, ok := ch.openIDSigningKeys[period]; ok {
+		return key, nil
+	}
+
+	name := model.SystemOpenIDSigningKeyPrefix + strconv.FormatInt(period, 10)
+	var key *model.SystemOpenIDSigningKey
+
+	value, err := ch.srv.Store.System().GetByName(name)
+	if err == nil {
+		if err := json.Unmarshal([]byte(value.Value), &key); err != nil {
+			return nil, err
+		}
+	}
+
+	if key == nil && !create {
+		return nil, nil
+	}
+
+	// If we don't already have a key, try to generate one.
+	if key == nil {
+		newRSAKey, err := rsa.GenerateKey(rand.Reader, openIDSigningKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		newKey := &model.SystemOpenIDSigningKey{
+			Kid:      model.NewId(),
+			CreateAt: model.GetMillis(),
+			RSAKey:   x509.MarshalPKCS1PrivateKey(newRSAKey),
+		}
+		system := &model.System{
+			Name: name,
+		}
+		v, err := json.Marshal(newKey)
+		if err != nil {
+			return nil, err
+		}
+		system.Value = string(v)
+		// If we were able to save the key, use it, otherwise log the error.
+		if err = ch.srv.Store.System().Save(system); err != nil {
+			mlog.Warn("Failed to save OpenIDSigningKey", mlog.String("name", name), mlog.Err(err))
+		} else {
+			key = newKey
+			ch.removeOpenIDSigningKey(period - 3)
+		}
+	}
+
+	// If we weren't able to save a new key above, another server must have beat us to it. Get the
+	// key from the database, and if that fails, error out.
+	if key == nil {
+		value, err := ch.srv.Store.System().GetByName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(value.Value), &key); err != nil {
+			return nil, err
+		}
+	}
+
+	rsaKey, err := x509.ParsePKCS1PrivateKey(key.RSAKey)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKey := &openIDSigningKey{kid: key.Kid, key: rsaKey}
+	for p := range ch.openIDSigningKeys {
+		if p < period-2 {
+			delete(ch.openIDSigningKeys, p)
+		}
+	}
+	ch.openIDSigningKeys[period] = signingKey
+
+	return signingKey, nil
+}
+
+// removeOpenIDSigningKey deletes the key of a period which is no longer published. The key
+// set has the keys of the period before the current one up to the next one, and the keys are
+// generated at the latest for the period after the current one.
+func (ch *Channels) removeOpenIDSigningKey(period int64) {
+	name := model.SystemOpenIDSigningKeyPrefix + strconv.FormatInt(period, 10)
+	if _, err := ch.srv.Store.System().PermanentDeleteByName(name); err != nil {
+		mlog.Debug("Failed to remove OpenIDSigningKey", mlog.String("name", name), mlog.Err(err))
+	}
+}
+
+// openIDIssuer returns the issuer of the ID tokens, which is the site URL.
+func (a *App) openIDIssuer(where string) (string, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableOAuthServiceProvider {
+		return "", model.NewAppError(where, "api.oauth.openid.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	siteURL := a.GetSiteURL()
+	if siteURL == "" {
+		return "", model.NewAppError(where, "api.oauth.openid.site_url.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	return siteURL, nil
+}
+
+// GetOpenIDProviderMetadata returns the discovery document of the OpenID Provider.
+func (a *App) GetOpenIDProviderMetadata() (*model.OpenIDProviderMetadata, *model.AppError) {
+	issuer, appErr := a.openIDIssuer("GetOpenIDProviderMetadata")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return model.NewOpenIDProviderMetadata(issuer), nil
+}
+
+// GetOpenIDJSONWebKeySet returns the public keys the ID tokens are verified with. Besides the
+// current key, the key set has the previous one, for the ID tokens issued before the last
+// rotation, and the next one, so that the clients caching the key set know it beforehand.
+func (a *App) GetOpenIDJSONWebKeySet() (*model.JSONWebKeySet, *model.AppError) {
+	if _, appErr := a.openIDIssuer("GetOpenIDJSONWebKeySet"); appErr != nil {
+		return nil, appErr
+	}
+
+	keySet := &model.JSONWebKeySet{Keys: []*model.JSONWebKey{}}
+	current := openIDSigningKeyPeriod(time.Now())
+	for period := current - 1; period <= current+1; period++ {
+		key, err := a.ch.getOpenIDSigningKey(period, period >= current)
+		if err != nil {
+			return nil, model.NewAppError("GetOpenIDJSONWebKeySet", "api.oauth.openid.signing_key.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		if key == nil {
+			continue
+		}
+
+		keySet.Keys = append(keySet.Keys, &model.JSONWebKey{
+			Kid: key.kid,
+			Kty: "RSA",
+			Use: "sig",
+			Alg: model.OpenIDSigningAlgorithm,
+			N:   base64.RawURLEncoding.EncodeToString(key.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.key.E)).Bytes()),
+		})
+	}
+
+	return keySet, nil
+}
+
+// GetOpenIDUserInfo returns the claims of the user allowed by the scope granted to the OAuth app.
+func (a *App) GetOpenIDUserInfo(userID, scope string) (*model.OpenIDUserInfo, *model.AppError) {
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return model.NewOpenIDUserInfo(user, scope, a.GetSiteURL()), nil
+}
+
+// newOpenIDToken returns an ID token telling the OAuth app who the user is, signed with the
+// key of the current rotation period.
+func (a *App) newOpenIDToken(oauthApp *model.OAuthApp, user *model.User, scope, nonce string) (string, *model.AppError) {
+	issuer, appErr := a.openIDIssuer("newOpenIDToken")
+	if appErr != nil {
+		return "", appErr
+	}
+
+	now := time.Now()
+	signingKey, err := a.ch.getOpenIDSigningKey(openIDSigningKeyPeriod(now), true)
+	if err != nil {
+		return "", model.NewAppError("newOpenIDToken", "api.oauth.openid.signing_key.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	// The claims of the user are added to the registered ones
+	userInfo, err := json.Marshal(model.NewOpenIDUserInfo(user, scope, issuer))
+	if err != nil {
+		return "", model.NewAppError("newOpenIDToken", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	claims := jwt.MapClaims{}
+	if err = json.Unmarshal(userInfo, &claims); err != nil {
+		return "", model.NewAppError("newOpenIDToken", "api.unmarshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	claims["iss"] = issuer
+	claims["aud"] = oauthApp.Id
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Unix() + model.OpenIDTokenExpireTime
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = signingKey.kid
+
+	idToken, err := token.SignedString(signingKey.key)
+	if err != nil {
+		return "", model.NewAppError("newOpenIDToken", "api.oauth.openid.sign.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return idToken, nil
+}
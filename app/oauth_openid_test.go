@@ -0,0 +1,84 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestGetOpenIDSigningKey(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	ch := th.App.ch
+	period := openIDSigningKeyPeriod(time.Now())
+	keyName := func(period int64) string {
+		return model.SystemOpenIDSigningKeyPrefix + strconv.FormatInt(period, 10)
+	}
+
+	key, err := ch.getOpenIDSigningKey(period-1, false)
+	require.NoError(t, err)
+	assert.Nil(t, key, "should not have generated a key")
+
+	key, err = ch.getOpenIDSigningKey(period, true)
+	require.NoError(t, err)
+	require.NotNil(t, key)
+	_, err = th.App.Srv().Store.System().GetByName(keyName(period))
+	require.NoError(t, err, "the key should have been saved")
+
+	cached, err := ch.getOpenIDSigningKey(period, true)
+	require.NoError(t, err)
+	assert.Same(t, key, cached)
+
+	t.Run("the key saved by another server is used", func(t *testing.T) {
+		ch.openIDSigningKeysMut.Lock()
+		ch.openIDSigningKeys = map[int64]*openIDSigningKey{}
+		ch.openIDSigningKeysMut.Unlock()
+
+		loaded, err := ch.getOpenIDSigningKey(period, true)
+		require.NoError(t, err)
+		assert.Equal(t, key.kid, loaded.kid)
+		assert.True(t, key.key.Equal(loaded.key))
+	})
+
+	t.Run("the keys no longer published are removed", func(t *testing.T) {
+		_, err := ch.getOpenIDSigningKey(period+3, true)
+		require.NoError(t, err)
+
+		_, err = th.App.Srv().Store.System().GetByName(keyName(period))
+		require.Error(t, err)
+	})
+}
+
+func TestGetOpenIDJSONWebKeySet(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	th.App.UpdateConfig(func(cfg *model.Config) {
+		*cfg.ServiceSettings.EnableOAuthServiceProvider = true
+		*cfg.ServiceSettings.SiteURL = "http://localhost:8065"
+	})
+
+	period := openIDSigningKeyPeriod(time.Now())
+	previous, err := th.App.ch.getOpenIDSigningKey(period-1, true)
+	require.NoError(t, err)
+
+	keySet, appErr := th.App.GetOpenIDJSONWebKeySet()
+	require.Nil(t, appErr)
+	require.Len(t, keySet.Keys, 3)
+	assert.Equal(t, previous.kid, keySet.Keys[0].Kid)
+
+	current, err := th.App.ch.getOpenIDSigningKey(period, false)
+	require.NoError(t, err)
+	assert.Equal(t, current.kid, keySet.Keys[1].Kid)
+	assert.Equal(t, "RSA", keySet.Keys[1].Kty)
+	assert.Equal(t, "AQAB", keySet.Keys[1].E)
+}
@@ -588,3 +588,39 @@ func TestGetAuthorizationCode(t *testing.T) {
 		}
 	})
 }
+
+func TestCleanupUsedOAuthRefreshTokens(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	accessData := &model.AccessData{
+		ClientId:    model.NewId(),
+		UserId:      model.NewId(),
+		Token:       model.NewId(),
+		RedirectUri: "http://example.com",
+		ExpiresAt:   model.GetMillis() + 60*60*1000,
+	}
+	_, err := th.App.Srv().Store.OAuth().SaveAccessData(accessData)
+	require.NoError(t, err)
+
+	saveUsedRefreshToken := func(clientID, userID string) *model.Token {
+		t.Helper()
+		token := &model.Token{
+			Token:    hashOAuthRefreshToken(model.NewId()),
+			CreateAt: model.GetMillis() - model.MaxTokenExipryTime - 1000,
+			Type:     TokenTypeOAuthUsedRefreshToken,
+			Extra:    model.MapToJSON(map[string]string{"client_id": clientID, "user_id": userID}),
+		}
+		require.NoError(t, th.App.Srv().Store.Token().Save(token))
+		return token
+	}
+	authorized := saveUsedRefreshToken(accessData.ClientId, accessData.UserId)
+	deauthorized := saveUsedRefreshToken(accessData.ClientId, model.NewId())
+
+	doTokenCleanup(th.Server)
+
+	_, err = th.App.Srv().Store.Token().GetByToken(authorized.Token)
+	assert.NoError(t, err, "the used refresh tokens of an authorized app should be kept")
+	_, err = th.App.Srv().Store.Token().GetByToken(deauthorized.Token)
+	assert.Error(t, err)
+}
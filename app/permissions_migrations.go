@@ -991,6 +991,18 @@ func (a *App) getPlaybooksPermissionsAddManageRoles() (permissionsMap, error) {
 	return transformations, nil
 }
 
+func (a *App) getAddChannelBookmarksPermissions() (permissionsMap, error) {
+	return permissionsMap{
+		permissionTransformation{
+			On: permissionOr(
+				permissionExists(PermissionManagePublicChannelProperties),
+				permissionExists(PermissionManagePrivateChannelProperties),
+			),
+			Add: []string{model.PermissionManageChannelBookmarks.Id},
+		},
+	}, nil
+}
+
 // DoPermissionsMigrations execute all the permissions migrations need by the current version.
 func (a *App) DoPermissionsMigrations() error {
 	return a.Srv().doPermissionsMigrations()
@@ -1032,6 +1044,7 @@ func (s *Server) doPermissionsMigrations() error {
 		{Key: model.MigrationKeyAddPlaybooksPermissions, Migration: a.getAddPlaybooksPermissions},
 		{Key: model.MigrationKeyAddCustomUserGroupsPermissions, Migration: a.getAddCustomUserGroupsPermissions},
 		{Key: model.MigrationKeyAddPlayboosksManageRolesPermissions, Migration: a.getPlaybooksPermissionsAddManageRoles},
+		{Key: model.MigrationKeyAddChannelBookmarksPermissions, Migration: a.getAddChannelBookmarksPermissions},
 	}
 
 	roles, err := s.Store.Role().GetAll()
@@ -0,0 +1,169 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// getPoll returns the poll post with the given id along with its poll.
+func (a *App) getPoll(postID string) (*model.Post, *model.Poll, *model.AppError) {
+	post, appErr := a.GetSinglePost(postID)
+	if appErr != nil {
+		return nil, nil, appErr
+	}
+
+	poll := post.GetPoll()
+	if poll == nil {
+		return nil, nil, model.NewAppError("getPoll", "app.poll.not_a_poll.app_error", nil, "post_id="+postID, http.StatusBadRequest)
+	}
+	return post, poll, nil
+}
+
+// getPollResults tallies the votes for the poll of the post.
+func (a *App) getPollResults(post *model.Post, poll *model.Poll) (*model.PollResults, []*model.PollVote, *model.AppError) {
+	votes, err := a.Srv().Store.PollVote().GetForPost(post.Id)
+	if err != nil {
+		return nil, nil, model.NewAppError("getPollResults", "app.poll.get_votes.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return model.NewPollResults(poll, votes), votes, nil
+}
+
+// GetPollResults returns the tallies of the poll post along with the options the user voted for.
+func (a *App) GetPollResults(postID, userID string) (*model.PollResults, *model.AppError) {
+	post, poll, appErr := a.getPoll(postID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	results, votes, appErr := a.getPollResults(post, poll)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	results.UserVotes = []int{}
+	for _, vote := range votes {
+		if vote.UserId == userID {
+			results.UserVotes = append(results.UserVotes, vote.OptionIndex)
+		}
+	}
+	return results, nil
+}
+
+// VotePoll replaces the votes of the user for the poll post with votes for the options at
+// the given indexes. An empty list of options withdraws the votes of the user.
+func (a *App) VotePoll(c *request.Context, postID, userID string, optionIndexes []int) (*model.PollResults, *model.AppError) {
+	post, poll, appErr := a.getPoll(postID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if post.DeleteAt != 0 {
+		return nil, model.NewAppError("VotePoll", "app.poll.deleted.app_error", nil, "post_id="+postID, http.StatusBadRequest)
+	}
+
+	channel, appErr := a.GetChannel(post.ChannelId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if channel.DeleteAt != 0 {
+		return nil, model.NewAppError("VotePoll", "app.poll.archived_channel.app_error", nil, "post_id="+postID, http.StatusForbidden)
+	}
+
+	if poll.ClosedAt != 0 {
+		return nil, model.NewAppError("VotePoll", "app.poll.closed.app_error", nil, "post_id="+postID, http.StatusBadRequest)
+	}
+
+	if !poll.IsValidVote(optionIndexes) {
+		return nil, model.NewAppError("VotePoll", "app.poll.invalid_vote.app_error", nil, "post_id="+postID, http.StatusBadRequest)
+	}
+
+	now := model.GetMillis()
+	votes := make([]*model.PollVote, 0, len(optionIndexes))
+	for _, index := range optionIndexes {
+		votes = append(votes, &model.PollVote{PostId: postID, UserId: userID, OptionIndex: index, CreateAt: now})
+	}
+
+	if err := a.Srv().Store.PollVote().SetUserVotes(postID, userID, votes); err != nil {
+		var appErr *model.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		return nil, model.NewAppError("VotePoll", "app.poll.save_votes.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	a.publishPollUpdate(post)
+
+	return a.GetPollResults(postID, userID)
+}
+
+// ClosePoll stops the voting on the poll post.
+func (a *App) ClosePoll(c *request.Context, postID string) (*model.Post, *model.AppError) {
+	post, poll, appErr := a.getPoll(postID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if post.DeleteAt != 0 {
+		return nil, model.NewAppError("ClosePoll", "app.poll.deleted.app_error", nil, "post_id="+postID, http.StatusBadRequest)
+	}
+
+	if poll.ClosedAt != 0 {
+		return a.PreparePostForClient(post, false, true), nil
+	}
+
+	// The poll is closed without going through UpdatePost, which keeps the poll of a post
+	// from being changed by its edits, and closing it is not an edit of the post.
+	newPost := post.Clone()
+	poll.ClosedAt = model.GetMillis()
+	newPost.SetPoll(poll)
+
+	rpost, err := a.Srv().Store.Post().Overwrite(newPost)
+	if err != nil {
+		var appErr *model.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		return nil, model.NewAppError("ClosePoll", "app.post.update.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	a.publishPollUpdate(rpost)
+
+	return a.PreparePostForClient(rpost, false, true), nil
+}
+
+// keepPoll keeps edits of a poll post from changing its poll, which could otherwise swap
+// the options the users voted for.
+func keepPoll(newPost, oldPost *model.Post) {
+	if poll := oldPost.GetPoll(); poll != nil {
+		newPost.Type = oldPost.Type
+		newPost.AddProp(model.PostPropsPoll, poll)
+	}
+}
+
+// publishPollUpdate sends the poll post with its latest tallies to the channel of the post.
+func (a *App) publishPollUpdate(post *model.Post) {
+	rpost := a.PreparePostForClientWithEmbedsAndImages(post, false, true)
+
+	// The post is broadcast to all users, so IsFollowing is not populated.
+	rpost.IsFollowing = nil
+
+	postJSON, err := rpost.ToJSON()
+	if err != nil {
+		mlog.Warn("Failed to encode poll post to JSON", mlog.String("post_id", post.Id), mlog.Err(err))
+		return
+	}
+
+	message := model.NewWebSocketEvent(model.WebsocketEventPostEdited, "", rpost.ChannelId, "", nil)
+	message.Add("post", postJSON)
+	a.Publish(message)
+
+	a.invalidateCacheForChannelPosts(rpost.ChannelId)
+}
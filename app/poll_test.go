@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func createPollPost(t *testing.T, th *TestHelper, poll *model.Poll) *model.Post {
+	post := &model.Post{
+		UserId:    th.BasicUser.Id,
+		ChannelId: th.BasicChannel.Id,
+		Message:   "Lunch?",
+	}
+	post.SetPoll(poll)
+
+	post, appErr := th.App.CreatePost(th.Context, post, th.BasicChannel, false, true)
+	require.Nil(t, appErr)
+	return post
+}
+
+func TestVotePoll(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	post := createPollPost(t, th, &model.Poll{Options: []string{"Pizza", "Sushi", "Tacos"}})
+
+	results, appErr := th.App.VotePoll(th.Context, post.Id, th.BasicUser.Id, []int{1})
+	require.Nil(t, appErr)
+	assert.Equal(t, []int{0, 1, 0}, results.Counts)
+	assert.Equal(t, []int{1}, results.UserVotes)
+	assert.Equal(t, []string{th.BasicUser.Id}, results.Voters[1])
+
+	_, appErr = th.App.VotePoll(th.Context, post.Id, th.BasicUser2.Id, []int{1})
+	require.Nil(t, appErr)
+
+	t.Run("changing the vote", func(t *testing.T) {
+		results, appErr := th.App.VotePoll(th.Context, post.Id, th.BasicUser.Id, []int{2})
+		require.Nil(t, appErr)
+		assert.Equal(t, []int{0, 1, 1}, results.Counts)
+		assert.Equal(t, 2, results.TotalVoters)
+	})
+
+	t.Run("invalid votes", func(t *testing.T) {
+		_, appErr := th.App.VotePoll(th.Context, post.Id, th.BasicUser.Id, []int{0, 1})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.poll.invalid_vote.app_error", appErr.Id)
+
+		_, appErr = th.App.VotePoll(th.Context, post.Id, th.BasicUser.Id, []int{3})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.poll.invalid_vote.app_error", appErr.Id)
+
+		_, appErr = th.App.VotePoll(th.Context, th.BasicPost.Id, th.BasicUser.Id, []int{0})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.poll.not_a_poll.app_error", appErr.Id)
+	})
+
+	t.Run("tallies in the metadata", func(t *testing.T) {
+		clientPost := th.App.PreparePostForClient(post, false, false)
+		require.NotNil(t, clientPost.Metadata.Poll)
+		assert.Equal(t, []int{0, 1, 1}, clientPost.Metadata.Poll.Counts)
+		assert.Nil(t, clientPost.Metadata.Poll.UserVotes)
+	})
+
+	t.Run("edits keep the poll", func(t *testing.T) {
+		edit := post.Clone()
+		edit.Message = "Dinner?"
+		edit.SetPoll(&model.Poll{Options: []string{"Soup", "Salad"}})
+		edited, appErr := th.App.UpdatePost(th.Context, edit, false)
+		require.Nil(t, appErr)
+		assert.Equal(t, "Dinner?", edited.Message)
+		assert.Equal(t, []string{"Pizza", "Sushi", "Tacos"}, edited.GetPoll().Options)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		open, appErr := th.App.GetSinglePost(post.Id)
+		require.Nil(t, appErr)
+
+		closed, appErr := th.App.ClosePoll(th.Context, post.Id)
+		require.Nil(t, appErr)
+		assert.NotZero(t, closed.GetPoll().ClosedAt)
+		assert.Equal(t, open.EditAt, closed.EditAt, "closing is not an edit")
+
+		_, appErr = th.App.VotePoll(th.Context, post.Id, th.BasicUser.Id, []int{0})
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		assert.Equal(t, "app.poll.closed.app_error", appErr.Id)
+
+		results, appErr := th.App.GetPollResults(post.Id, th.BasicUser2.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, []int{0, 1, 1}, results.Counts)
+		assert.Equal(t, []int{1}, results.UserVotes)
+	})
+}
+
+func TestVotePollAnonymousMultiChoice(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	post := createPollPost(t, th, &model.Poll{Options: []string{"Monday", "Friday"}, Anonymous: true, MultiChoice: true})
+
+	results, appErr := th.App.VotePoll(th.Context, post.Id, th.BasicUser.Id, []int{0, 1})
+	require.Nil(t, appErr)
+	assert.Equal(t, []int{1, 1}, results.Counts)
+	assert.Equal(t, 1, results.TotalVoters)
+	assert.Nil(t, results.Voters)
+	assert.ElementsMatch(t, []int{0, 1}, results.UserVotes)
+
+	results, appErr = th.App.VotePoll(th.Context, post.Id, th.BasicUser.Id, []int{})
+	require.Nil(t, appErr)
+	assert.Equal(t, []int{0, 0}, results.Counts)
+	assert.Empty(t, results.UserVotes)
+}
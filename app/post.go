@@ -617,6 +617,7 @@ func (a *App) UpdatePost(c *request.Context, post *model.Post, safeUpdate bool)
 		newPost.HasReactions = post.HasReactions
 		newPost.FileIds = post.FileIds
 		newPost.SetProps(post.GetProps())
+		keepPoll(newPost, oldPost)
 	}
 
 	// Avoid deep-equal checks if EditAt was already modified through message change
@@ -644,8 +645,19 @@ func (a *App) UpdatePost(c *request.Context, post *model.Post, safeUpdate bool)
 		}
 	}
 
+	// The revision is kept before the post is updated, which changes oldPost.
+	var revision *model.PostRevision
+	if newPost.EditAt != oldPost.EditAt {
+		if revision, err = a.savePostRevision(c, oldPost, newPost.EditAt); err != nil {
+			return nil, err
+		}
+	}
+
 	rpost, nErr := a.Srv().Store.Post().Update(newPost, oldPost)
 	if nErr != nil {
+		if revision != nil {
+			a.deletePostRevision(revision)
+		}
 		var appErr *model.AppError
 		switch {
 		case errors.As(nErr, &appErr):
@@ -692,6 +704,10 @@ func (a *App) UpdatePost(c *request.Context, post *model.Post, safeUpdate bool)
 		a.Publish(message)
 	}
 
+	if revision != nil {
+		a.publishPostRevision(revision)
+	}
+
 	a.invalidateCacheForChannelPosts(rpost.ChannelId)
 
 	return rpost, nil
@@ -127,6 +127,15 @@ func (a *App) PreparePostForClient(originalPost *model.Post, isNewPost, isEditPo
 		post.Metadata.Files = fileInfos
 	}
 
+	// Poll tallies
+	if poll := post.GetPoll(); poll != nil {
+		if results, _, err := a.getPollResults(post, poll); err != nil {
+			mlog.Warn("Failed to get poll results for a post", mlog.String("post_id", post.Id), mlog.Err(err))
+		} else {
+			post.Metadata.Poll = results
+		}
+	}
+
 	return post
 }
 
@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// savePostRevision keeps the content of oldPost before it is replaced by the edit made
+// at editAt in the context of c.
+func (a *App) savePostRevision(c *request.Context, oldPost *model.Post, editAt int64) (*model.PostRevision, *model.AppError) {
+	revision, err := a.Srv().Store.PostRevision().Save(model.NewPostRevision(oldPost, c.Session().UserId, editAt))
+	if err != nil {
+		var appErr *model.AppError
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		return nil, model.NewAppError("savePostRevision", "app.post_revision.save.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return revision, nil
+}
+
+// deletePostRevision removes a revision kept for an edit which could not be saved.
+func (a *App) deletePostRevision(revision *model.PostRevision) {
+	if err := a.Srv().Store.PostRevision().PermanentDelete(revision.Id); err != nil {
+		mlog.Warn("Failed to delete the revision of a post which was not updated", mlog.String("post_id", revision.PostId), mlog.Err(err))
+	}
+}
+
+// GetPostEditHistory returns the revisions of the post, most recent first.
+func (a *App) GetPostEditHistory(postID string) ([]*model.PostRevision, *model.AppError) {
+	revisions, err := a.Srv().Store.PostRevision().GetForPost(postID)
+	if err != nil {
+		return nil, model.NewAppError("GetPostEditHistory", "app.post_revision.get_for_post.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return revisions, nil
+}
+
+// publishPostRevision lets the author of the post know that its edit history changed.
+func (a *App) publishPostRevision(revision *model.PostRevision) {
+	revisionJSON, err := json.Marshal(revision)
+	if err != nil {
+		mlog.Warn("Failed to encode post revision to JSON", mlog.Err(err))
+		return
+	}
+
+	message := model.NewWebSocketEvent(model.WebsocketEventPostRevisionCreated, "", revision.ChannelId, revision.UserId, nil)
+	message.Add("post_id", revision.PostId)
+	message.Add("revision", string(revisionJSON))
+	a.Publish(message)
+}
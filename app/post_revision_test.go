@@ -0,0 +1,65 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatePostKeepsRevisions(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	post := th.CreatePost(th.BasicChannel)
+	original := post.Message
+	th.Context.Session().UserId = th.BasicUser.Id
+
+	edit := post.Clone()
+	edit.Message = "first edit"
+	edited, appErr := th.App.UpdatePost(th.Context, edit, false)
+	require.Nil(t, appErr)
+
+	revisions, appErr := th.App.GetPostEditHistory(post.Id)
+	require.Nil(t, appErr)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, original, revisions[0].Message)
+	assert.Equal(t, post.UserId, revisions[0].UserId)
+	assert.Equal(t, th.BasicUser.Id, revisions[0].EditorId)
+	assert.Equal(t, edited.EditAt, revisions[0].CreateAt)
+
+	t.Run("pinning keeps no revision", func(t *testing.T) {
+		pin := edited.Clone()
+		pin.IsPinned = true
+		_, appErr := th.App.UpdatePost(th.Context, pin, false)
+		require.Nil(t, appErr)
+
+		revisions, appErr := th.App.GetPostEditHistory(post.Id)
+		require.Nil(t, appErr)
+		assert.Len(t, revisions, 1)
+	})
+
+	t.Run("most recent first", func(t *testing.T) {
+		edit := edited.Clone()
+		edit.Message = "second edit"
+		_, appErr := th.App.UpdatePost(th.Context, edit, false)
+		require.Nil(t, appErr)
+
+		revisions, appErr := th.App.GetPostEditHistory(post.Id)
+		require.Nil(t, appErr)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, "first edit", revisions[0].Message)
+		assert.Equal(t, original, revisions[1].Message)
+	})
+
+	t.Run("deleted with the channel", func(t *testing.T) {
+		require.Nil(t, th.App.PermanentDeleteChannel(th.BasicChannel))
+
+		revisions, appErr := th.App.GetPostEditHistory(post.Id)
+		require.Nil(t, appErr)
+		assert.Empty(t, revisions)
+	})
+}
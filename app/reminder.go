@@ -0,0 +1,341 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/i18n"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+// remindersBatchSize is the number of due reminders fired per query.
+const remindersBatchSize = 100
+
+// reminderSnoozeOptions are the snooze durations offered on the reminder messages, in
+// minutes, with the id of their translation.
+var reminderSnoozeOptions = []struct {
+	minutes int
+	textID  string
+}{
+	{20, "app.reminder.snooze.minutes_20"},
+	{60, "app.reminder.snooze.hour_1"},
+	{180, "app.reminder.snooze.hours_3"},
+	{24 * 60, "app.reminder.snooze.day_1"},
+}
+
+func (a *App) checkRemindersEnabled(where string) *model.AppError {
+	if !*a.Config().ServiceSettings.EnableReminders {
+		return model.NewAppError(where, "app.reminder.feature_disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+// SaveReminder reminds the user of the post or of the message of the reminder at its
+// target time.
+func (a *App) SaveReminder(reminder *model.Reminder) (*model.Reminder, *model.AppError) {
+	if appErr := a.checkRemindersEnabled("SaveReminder"); appErr != nil {
+		return nil, appErr
+	}
+
+	if reminder.TargetAt < model.GetMillis() {
+		return nil, model.NewAppError("SaveReminder", "app.reminder.target_at_past.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if reminder.PostId != "" {
+		if _, appErr := a.GetSinglePost(reminder.PostId); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	reminder.Id = ""
+	reminder.CreateAt = 0
+	savedReminder, err := a.Srv().Store.Reminder().Save(reminder)
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("SaveReminder", "app.reminder.save.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+	return savedReminder, nil
+}
+
+func (a *App) GetReminder(id string) (*model.Reminder, *model.AppError) {
+	reminder, err := a.Srv().Store.Reminder().Get(id)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("GetReminder", "app.reminder.get.not_found.app_error", nil, nfErr.Error(), http.StatusNotFound)
+		default:
+			return nil, model.NewAppError("GetReminder", "app.reminder.get.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+	return reminder, nil
+}
+
+// GetRemindersForUser returns the reminders of the user ordered by their target time,
+// including the ones which fired and were neither snoozed nor completed.
+func (a *App) GetRemindersForUser(userID string) ([]*model.Reminder, *model.AppError) {
+	if appErr := a.checkRemindersEnabled("GetRemindersForUser"); appErr != nil {
+		return nil, appErr
+	}
+
+	reminders, err := a.Srv().Store.Reminder().GetForUser(userID)
+	if err != nil {
+		return nil, model.NewAppError("GetRemindersForUser", "app.reminder.get_for_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return reminders, nil
+}
+
+// SnoozeReminder makes the reminder of the user fire again after the given duration.
+func (a *App) SnoozeReminder(userID, id string, duration time.Duration) (*model.Reminder, *model.AppError) {
+	reminder, appErr := a.GetReminder(id)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if reminder.UserId != userID {
+		return nil, model.NewAppError("SnoozeReminder", "app.reminder.get.not_found.app_error", nil, "", http.StatusNotFound)
+	}
+
+	reminder.TargetAt = model.GetMillis() + duration.Milliseconds()
+	reminder.FiredAt = 0
+	if err := a.Srv().Store.Reminder().Update(reminder); err != nil {
+		return nil, model.NewAppError("SnoozeReminder", "app.reminder.update.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return reminder, nil
+}
+
+// DeleteReminder deletes the reminder of the user, whether it fired or not.
+func (a *App) DeleteReminder(userID, id string) (*model.Reminder, *model.AppError) {
+	reminder, appErr := a.GetReminder(id)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if reminder.UserId != userID {
+		return nil, model.NewAppError("DeleteReminder", "app.reminder.get.not_found.app_error", nil, "", http.StatusNotFound)
+	}
+
+	if err := a.Srv().Store.Reminder().Delete(id); err != nil {
+		return nil, model.NewAppError("DeleteReminder", "app.reminder.delete.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return reminder, nil
+}
+
+// ProcessReminders fires the reminders which are due. The reminders of the users who were
+// deactivated, or about posts they can no longer read, are deleted. A reminder failing to
+// fire is logged and doesn't stop the others from firing.
+func (a *App) ProcessReminders() *model.AppError {
+	now := model.GetMillis()
+	var afterTime int64
+	afterID := ""
+	for {
+		reminders, err := a.Srv().Store.Reminder().GetDueReminders(now, afterTime, afterID, remindersBatchSize)
+		if err != nil {
+			return model.NewAppError("ProcessReminders", "app.reminder.get_due.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		for _, reminder := range reminders {
+			if appErr := a.fireReminder(reminder); appErr != nil {
+				mlog.Error("Failed to fire reminder", mlog.String("reminder_id", reminder.Id), mlog.Err(appErr))
+			}
+		}
+
+		if len(reminders) < remindersBatchSize {
+			return nil
+		}
+		afterTime = reminders[len(reminders)-1].TargetAt
+		afterID = reminders[len(reminders)-1].Id
+	}
+}
+
+// fireReminder sends the reminder to its user as a direct message from the system bot. The
+// reminder is marked as fired before being sent, so that it's never sent twice, and is
+// marked back as due when it can't be sent.
+func (a *App) fireReminder(reminder *model.Reminder) *model.AppError {
+	user, err := a.Srv().Store.User().Get(context.Background(), reminder.UserId)
+	if err != nil || user.DeleteAt != 0 {
+		return a.dropReminder(reminder)
+	}
+
+	var post *model.Post
+	if reminder.PostId != "" {
+		post, err = a.Srv().Store.Post().GetSingle(reminder.PostId, false)
+		if err != nil || !a.HasPermissionToChannel(user.Id, post.ChannelId, model.PermissionReadChannel) {
+			return a.dropReminder(reminder)
+		}
+	}
+
+	bot, appErr := a.GetSystemBot()
+	if appErr != nil {
+		return appErr
+	}
+
+	channel, appErr := a.GetOrCreateDirectChannel(request.EmptyContext(), user.Id, bot.UserId)
+	if appErr != nil {
+		return appErr
+	}
+
+	botPost := &model.Post{
+		UserId:    bot.UserId,
+		ChannelId: channel.Id,
+	}
+	model.ParseSlackAttachment(botPost, []*model.SlackAttachment{a.reminderAttachment(user, reminder, post)})
+
+	reminder.FiredAt = model.GetMillis()
+	if err := a.Srv().Store.Reminder().Update(reminder); err != nil {
+		return model.NewAppError("ProcessReminders", "app.reminder.update.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if _, appErr := a.CreatePostAsUser(request.EmptyContext(), botPost, "", false); appErr != nil {
+		reminder.FiredAt = 0
+		if err := a.Srv().Store.Reminder().Update(reminder); err != nil {
+			mlog.Warn("Failed to mark reminder as due", mlog.String("reminder_id", reminder.Id), mlog.Err(err))
+		}
+		return appErr
+	}
+	return nil
+}
+
+// dropReminder deletes a reminder which can no longer be fired.
+func (a *App) dropReminder(reminder *model.Reminder) *model.AppError {
+	mlog.Debug("Deleting a reminder which can no longer be fired", mlog.String("reminder_id", reminder.Id))
+	if err := a.Srv().Store.Reminder().Delete(reminder.Id); err != nil {
+		return model.NewAppError("ProcessReminders", "app.reminder.delete.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// reminderAttachment builds the message of the reminder, with the actions to snooze and
+// complete it.
+func (a *App) reminderAttachment(user *model.User, reminder *model.Reminder, post *model.Post) *model.SlackAttachment {
+	T := i18n.GetUserTranslations(user.Locale)
+
+	text := T("app.reminder.message", map[string]interface{}{"Message": reminder.Message})
+	if post != nil {
+		text = T("app.reminder.post.message", map[string]interface{}{"Permalink": a.reminderPermalink(user.Id, post)})
+		if reminder.Message != "" {
+			text += "\n" + reminder.Message
+		}
+	}
+
+	actionContext := model.StringInterface{"reminder_id": reminder.Id}
+	options := make([]*model.PostActionOptions, 0, len(reminderSnoozeOptions))
+	for _, option := range reminderSnoozeOptions {
+		options = append(options, &model.PostActionOptions{
+			Text:  T(option.textID),
+			Value: strconv.Itoa(option.minutes),
+		})
+	}
+
+	return &model.SlackAttachment{
+		Text: text,
+		Actions: []*model.PostAction{
+			{
+				Id:      "snooze",
+				Name:    T("app.reminder.snooze.name"),
+				Type:    model.PostActionTypeSelect,
+				Options: options,
+				Integration: &model.PostActionIntegration{
+					URL:     model.ReminderActionSnooze,
+					Context: actionContext,
+				},
+			},
+			{
+				Id:   "complete",
+				Name: T("app.reminder.complete.name"),
+				Type: model.PostActionTypeButton,
+				Integration: &model.PostActionIntegration{
+					URL:     model.ReminderActionComplete,
+					Context: actionContext,
+				},
+			},
+		},
+	}
+}
+
+// reminderPermalink returns the link to the post in the team of its channel, or in a team
+// of the user for the direct and group message channels.
+func (a *App) reminderPermalink(userID string, post *model.Post) string {
+	teamName := ""
+	if channel, err := a.Srv().Store.Channel().Get(post.ChannelId, true); err == nil && channel.TeamId != "" {
+		if team, err := a.Srv().Store.Team().Get(channel.TeamId); err == nil {
+			teamName = team.Name
+		}
+	}
+	if teamName == "" {
+		if teams, err := a.Srv().Store.Team().GetTeamsByUserId(userID); err == nil && len(teams) > 0 {
+			teamName = teams[0].Name
+		}
+	}
+	return a.GetSiteURL() + "/" + teamName + "/pl/" + post.Id
+}
+
+// doLocalReminderRequest handles the snooze and complete actions of a reminder message.
+// The message is updated to tell what was done and loses its actions.
+func (a *App) doLocalReminderRequest(c *request.Context, rawURL string, upstreamRequest *model.PostActionIntegrationRequest) *model.AppError {
+	reminderID, _ := upstreamRequest.Context["reminder_id"].(string)
+
+	user, appErr := a.GetUser(upstreamRequest.UserId)
+	if appErr != nil {
+		return appErr
+	}
+	T := i18n.GetUserTranslations(user.Locale)
+
+	var footer string
+	switch rawURL {
+	case model.ReminderActionSnooze:
+		selectedOption, _ := upstreamRequest.Context["selected_option"].(string)
+		minutes, err := strconv.Atoi(selectedOption)
+		if err != nil || minutes <= 0 {
+			return model.NewAppError("doLocalReminderRequest", "api.post.do_action.action_integration.app_error", nil, "invalid snooze option", http.StatusBadRequest)
+		}
+		reminder, appErr := a.SnoozeReminder(user.Id, reminderID, time.Duration(minutes)*time.Minute)
+		if appErr != nil {
+			return appErr
+		}
+		footer = T("app.reminder.snoozed", map[string]interface{}{"Time": FormatReminderTime(user, reminder.TargetAt)})
+	case model.ReminderActionComplete:
+		if _, appErr := a.DeleteReminder(user.Id, reminderID); appErr != nil {
+			return appErr
+		}
+		footer = T("app.reminder.completed")
+	default:
+		return model.NewAppError("doLocalReminderRequest", "api.post.do_action.action_integration.app_error", nil, "url="+rawURL, http.StatusBadRequest)
+	}
+
+	post, appErr := a.GetSinglePost(upstreamRequest.PostId)
+	if appErr != nil {
+		return appErr
+	}
+	attachments := post.Attachments()
+	for _, attachment := range attachments {
+		attachment.Actions = nil
+		attachment.Footer = footer
+	}
+	model.ParseSlackAttachment(post, attachments)
+	if _, appErr := a.UpdatePost(c, post, false); appErr != nil {
+		return appErr
+	}
+	return nil
+}
+
+// FormatReminderTime formats the time of a reminder in the timezone of its user.
+func FormatReminderTime(user *model.User, millis int64) string {
+	t := time.Unix(0, millis*int64(time.Millisecond))
+	if loc, err := time.LoadLocation(user.GetPreferredTimezone()); err == nil {
+		t = t.In(loc)
+	}
+	return t.Format("Mon Jan 2, 2006 15:04 MST")
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func TestSaveReminder(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	t.Run("target time in the past", func(t *testing.T) {
+		_, appErr := th.App.SaveReminder(&model.Reminder{UserId: th.BasicUser.Id, Message: "message", TargetAt: 1000})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.reminder.target_at_past.app_error", appErr.Id)
+	})
+
+	t.Run("unknown post", func(t *testing.T) {
+		_, appErr := th.App.SaveReminder(&model.Reminder{UserId: th.BasicUser.Id, PostId: model.NewId(), TargetAt: model.GetMillis() + 60000})
+		require.NotNil(t, appErr)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableReminders = false })
+		defer th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableReminders = true })
+
+		_, appErr := th.App.SaveReminder(&model.Reminder{UserId: th.BasicUser.Id, Message: "message", TargetAt: model.GetMillis() + 60000})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "app.reminder.feature_disabled.app_error", appErr.Id)
+	})
+
+	reminder, appErr := th.App.SaveReminder(&model.Reminder{UserId: th.BasicUser.Id, PostId: th.BasicPost.Id, TargetAt: model.GetMillis() + 60000})
+	require.Nil(t, appErr)
+
+	_, appErr = th.App.DeleteReminder(th.BasicUser2.Id, reminder.Id)
+	require.NotNil(t, appErr)
+	_, appErr = th.App.DeleteReminder(th.BasicUser.Id, reminder.Id)
+	require.Nil(t, appErr)
+}
+
+func TestProcessReminders(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	bot, appErr := th.App.GetSystemBot()
+	require.Nil(t, appErr)
+	dm, appErr := th.App.GetOrCreateDirectChannel(th.Context, th.BasicUser.Id, bot.UserId)
+	require.Nil(t, appErr)
+
+	// The reminders are saved through the store, as due reminders can't be scheduled.
+	reminder, err := th.App.Srv().Store.Reminder().Save(&model.Reminder{UserId: th.BasicUser.Id, PostId: th.BasicPost.Id, TargetAt: model.GetMillis() - 1000})
+	require.NoError(t, err)
+	lostReminder, err := th.App.Srv().Store.Reminder().Save(&model.Reminder{UserId: th.BasicUser.Id, PostId: model.NewId(), TargetAt: model.GetMillis() - 1000})
+	require.NoError(t, err)
+
+	require.Nil(t, th.App.ProcessReminders())
+
+	reminder, appErr = th.App.GetReminder(reminder.Id)
+	require.Nil(t, appErr)
+	assert.NotZero(t, reminder.FiredAt)
+
+	_, appErr = th.App.GetReminder(lostReminder.Id)
+	require.NotNil(t, appErr, "the reminder about a missing post should be deleted")
+
+	posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: dm.Id, PerPage: 10})
+	require.Nil(t, appErr)
+	require.Len(t, posts.Order, 1)
+	botPost := posts.Posts[posts.Order[0]]
+	assert.Equal(t, bot.UserId, botPost.UserId)
+	attachments := botPost.Attachments()
+	require.Len(t, attachments, 1)
+	assert.Contains(t, attachments[0].Text, "/"+th.BasicTeam.Name+"/pl/"+th.BasicPost.Id)
+	require.Len(t, attachments[0].Actions, 2)
+
+	t.Run("fired once", func(t *testing.T) {
+		require.Nil(t, th.App.ProcessReminders())
+		posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: dm.Id, PerPage: 10})
+		require.Nil(t, appErr)
+		assert.Len(t, posts.Order, 1)
+	})
+
+	t.Run("snooze", func(t *testing.T) {
+		before := model.GetMillis()
+		_, appErr := th.App.DoPostActionWithCookie(th.Context, botPost.Id, "snooze", th.BasicUser.Id, "60", nil)
+		require.Nil(t, appErr)
+
+		snoozed, appErr := th.App.GetReminder(reminder.Id)
+		require.Nil(t, appErr)
+		assert.Zero(t, snoozed.FiredAt)
+		assert.GreaterOrEqual(t, snoozed.TargetAt, before+time.Hour.Milliseconds())
+
+		updated, appErr := th.App.GetSinglePost(botPost.Id)
+		require.Nil(t, appErr)
+		require.Len(t, updated.Attachments(), 1)
+		assert.Empty(t, updated.Attachments()[0].Actions)
+		assert.NotEmpty(t, updated.Attachments()[0].Footer)
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		snoozed, appErr := th.App.GetReminder(reminder.Id)
+		require.Nil(t, appErr)
+		snoozed.TargetAt = model.GetMillis() - 1000
+		require.NoError(t, th.App.Srv().Store.Reminder().Update(snoozed))
+		require.Nil(t, th.App.ProcessReminders())
+
+		posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: dm.Id, PerPage: 10})
+		require.Nil(t, appErr)
+		require.Len(t, posts.Order, 2)
+
+		_, appErr = th.App.DoPostActionWithCookie(th.Context, posts.Order[0], "complete", th.BasicUser2.Id, "", nil)
+		require.NotNil(t, appErr, "only the user of the reminder can complete it")
+
+		_, appErr = th.App.DoPostActionWithCookie(th.Context, posts.Order[0], "complete", th.BasicUser.Id, "", nil)
+		require.Nil(t, appErr)
+		_, appErr = th.App.GetReminder(reminder.Id)
+		require.NotNil(t, appErr)
+	})
+
+	t.Run("deleted with the user", func(t *testing.T) {
+		_, appErr := th.App.SaveReminder(&model.Reminder{UserId: th.BasicUser2.Id, Message: "message", TargetAt: model.GetMillis() + 60000})
+		require.Nil(t, appErr)
+		require.Nil(t, th.App.PermanentDeleteUser(th.Context, th.BasicUser2))
+
+		reminders, appErr := th.App.GetRemindersForUser(th.BasicUser2.Id)
+		require.Nil(t, appErr)
+		assert.Empty(t, reminders)
+	})
+}
+
+func TestProcessRemindersWithFailure(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	bot, appErr := th.App.GetSystemBot()
+	require.Nil(t, appErr)
+
+	// The failing reminder is due first, so that it's fired before the other one.
+	failing, err := th.App.Srv().Store.Reminder().Save(&model.Reminder{UserId: th.BasicUser.Id, Message: "failing", TargetAt: model.GetMillis() - 2000})
+	require.NoError(t, err)
+	other, err := th.App.Srv().Store.Reminder().Save(&model.Reminder{UserId: th.BasicUser2.Id, Message: "other", TargetAt: model.GetMillis() - 1000})
+	require.NoError(t, err)
+
+	sqlStore := th.App.Srv().Store
+	th.App.Srv().Store = &failingUpdateStore{Store: sqlStore, reminderID: failing.Id}
+	require.Nil(t, th.App.ProcessReminders())
+	th.App.Srv().Store = sqlStore
+
+	fired, appErr := th.App.GetReminder(other.Id)
+	require.Nil(t, appErr)
+	assert.NotZero(t, fired.FiredAt, "the other reminders should fire")
+
+	notFired, appErr := th.App.GetReminder(failing.Id)
+	require.Nil(t, appErr)
+	assert.Zero(t, notFired.FiredAt)
+
+	dm, appErr := th.App.GetOrCreateDirectChannel(th.Context, th.BasicUser.Id, bot.UserId)
+	require.Nil(t, appErr)
+	posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: dm.Id, PerPage: 10})
+	require.Nil(t, appErr)
+	assert.Empty(t, posts.Order, "a reminder which can't be marked as fired shouldn't be sent")
+
+	require.Nil(t, th.App.ProcessReminders())
+	fired, appErr = th.App.GetReminder(failing.Id)
+	require.Nil(t, appErr)
+	assert.NotZero(t, fired.FiredAt)
+	posts, appErr = th.App.GetPostsPage(model.GetPostsOptions{ChannelId: dm.Id, PerPage: 10})
+	require.Nil(t, appErr)
+	assert.Len(t, posts.Order, 1)
+}
+
+// failingUpdateStore is a store failing to update the given reminder.
+type failingUpdateStore struct {
+	store.Store
+	reminderID string
+}
+
+func (s *failingUpdateStore) Reminder() store.ReminderStore {
+	return &failingUpdateReminderStore{ReminderStore: s.Store.Reminder(), reminderID: s.reminderID}
+}
+
+type failingUpdateReminderStore struct {
+	store.ReminderStore
+	reminderID string
+}
+
+func (s *failingUpdateReminderStore) Update(reminder *model.Reminder) error {
+	if reminder.Id == s.reminderID {
+		return errors.New("failed to update")
+	}
+	return s.ReminderStore.Update(reminder)
+}
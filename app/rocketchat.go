@@ -0,0 +1,24 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"io"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/services/rocketchatimport"
+)
+
+// RocketChatConvertToBulkImport converts the Rocket.Chat export into a bulk import data
+// file for the team, written to w, and returns the number of lines written. The
+// Rocket.Chat users are merged with the existing users sharing their email address.
+func (a *App) RocketChatConvertToBulkImport(zipReader *zip.Reader, w io.Writer, teamID string) (int, *model.AppError) {
+	opts, appErr := a.bulkConvertOptions(teamID)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	return rocketchatimport.ConvertToBulkImport(zipReader, w, opts)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestRocketChatConvertToBulkImport(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for name, content := range map[string]string{
+		"users.json": `[
+			{"_id": "u1", "username": "rc.alice", "name": "Alice", "emails": [{"address": "rc.alice@example.com"}], "active": true, "type": "user"},
+			{"_id": "u2", "username": "rc.bob", "name": "Bob", "emails": [{"address": "` + th.BasicUser2.Email + `"}], "active": true, "type": "user"}
+		]`,
+		"rocketchat_room.json": `[
+			{"_id": "r1", "t": "c", "name": "rc-general", "topic": "Topic"},
+			{"_id": "r2", "t": "d", "uids": ["u1", "u2"]}
+		]`,
+		"rocketchat_subscription.json": `[{"rid": "r1", "u": {"_id": "u1"}}, {"rid": "r1", "u": {"_id": "u2"}}]`,
+		"rocketchat_message.json": `[
+			{"_id": "m1", "rid": "r1", "msg": "Root", "ts": {"$date": "2021-01-01T00:00:00Z"}, "u": {"_id": "u1"},
+				"pinned": true, "reactions": {":smile:": {"usernames": ["rc.bob"]}}},
+			{"_id": "m2", "rid": "r1", "msg": "Reply", "ts": {"$date": "2021-01-01T00:00:01Z"}, "u": {"_id": "u2"}, "tmid": "m1"},
+			{"_id": "m3", "rid": "r1", "msg": "File", "ts": {"$date": "2021-01-01T00:00:02Z"}, "u": {"_id": "u1"}, "file": {"_id": "f1", "name": "file.txt"}},
+			{"_id": "m4", "rid": "r1", "msg": "rc.bob", "t": "au", "ts": {"$date": "2021-01-01T00:00:03Z"}, "u": {"_id": "u1"}},
+			{"_id": "m5", "rid": "r2", "msg": "Direct", "ts": {"$date": "2021-01-01T00:00:04Z"}, "u": {"_id": "u2"}}
+		]`,
+		"uploads/f1/file.txt": "content",
+	} {
+		w, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	zipReader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	data := &bytes.Buffer{}
+	lines, appErr := th.App.RocketChatConvertToBulkImport(zipReader, data, th.BasicTeam.Id)
+	require.Nil(t, appErr)
+	require.Equal(t, 9, lines)
+
+	var failedLines []int
+	appErr, _ = th.App.BulkImportWithOpts(th.Context, data, zipReader, model.BulkImportOpts{
+		Workers:     1,
+		OnLineError: func(lineNumber int, err *model.AppError) { failedLines = append(failedLines, lineNumber) },
+	})
+	require.Nil(t, appErr)
+	require.Empty(t, failedLines)
+
+	alice, appErr := th.App.GetUserByUsername("rc.alice")
+	require.Nil(t, appErr)
+
+	channel, appErr := th.App.GetChannelByName("rc-general", th.BasicTeam.Id, false)
+	require.Nil(t, appErr)
+	assert.Equal(t, "Topic", channel.Header)
+	_, appErr = th.App.GetChannelMember(context.Background(), channel.Id, th.BasicUser2.Id)
+	require.Nil(t, appErr, "the Rocket.Chat user should be merged with the user sharing their email")
+
+	posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: channel.Id, PerPage: 10})
+	require.Nil(t, appErr)
+	require.Len(t, posts.Order, 4)
+
+	root := posts.Posts[posts.Order[3]]
+	assert.Equal(t, "Root", root.Message)
+	assert.Equal(t, alice.Id, root.UserId)
+	assert.True(t, root.IsPinned)
+
+	reply := posts.Posts[posts.Order[2]]
+	assert.Equal(t, root.Id, reply.RootId)
+	assert.Equal(t, th.BasicUser2.Id, reply.UserId)
+
+	reactions, appErr := th.App.GetReactionsForPost(root.Id)
+	require.Nil(t, appErr)
+	require.Len(t, reactions, 1)
+	assert.Equal(t, "smile", reactions[0].EmojiName)
+
+	file := posts.Posts[posts.Order[1]]
+	assert.Len(t, file.FileIds, 1)
+
+	added := posts.Posts[posts.Order[0]]
+	assert.Equal(t, model.PostTypeAddToChannel, added.Type)
+}
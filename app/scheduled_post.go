@@ -0,0 +1,275 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+// scheduledPostsBatchSize is the number of due scheduled posts sent per query.
+const scheduledPostsBatchSize = 100
+
+func (a *App) checkScheduledPostsEnabled(where string) *model.AppError {
+	if !*a.Config().ServiceSettings.EnableScheduledPosts {
+		return model.NewAppError(where, "app.scheduled_post.feature_disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+// checkScheduledPost checks the scheduled post can be scheduled by its user: it must be
+// scheduled in the future, fit in a post and be in a channel the user is a member of.
+func (a *App) checkScheduledPost(where string, scheduledPost *model.ScheduledPost) *model.AppError {
+	if scheduledPost.ScheduledAt < model.GetMillis() {
+		return model.NewAppError(where, "model.scheduled_post.is_valid.scheduled_at_past.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(scheduledPost.Message) > a.MaxPostSize() {
+		return model.NewAppError(where, "model.draft.is_valid.msg.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if _, appErr := a.GetChannelMember(context.Background(), scheduledPost.ChannelId, scheduledPost.UserId); appErr != nil {
+		return model.NewAppError(where, "app.scheduled_post.not_member.app_error", nil, appErr.Error(), http.StatusForbidden)
+	}
+
+	if scheduledPost.RootId != "" {
+		if _, appErr := a.GetSinglePost(scheduledPost.RootId); appErr != nil {
+			return model.NewAppError(where, "app.scheduled_post.root_id.app_error", nil, appErr.Error(), http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// SaveScheduledPost schedules the post of the user to be sent at its scheduled time.
+func (a *App) SaveScheduledPost(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, *model.AppError) {
+	if appErr := a.checkScheduledPostsEnabled("SaveScheduledPost"); appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr := a.checkScheduledPost("SaveScheduledPost", scheduledPost); appErr != nil {
+		return nil, appErr
+	}
+
+	scheduledPost.Id = ""
+	scheduledPost.CreateAt = 0
+	savedScheduledPost, err := a.Srv().Store.ScheduledPost().Save(scheduledPost)
+	if err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("SaveScheduledPost", "app.scheduled_post.save.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	a.publishScheduledPostEvent(model.WebsocketEventScheduledPostCreated, savedScheduledPost)
+
+	return savedScheduledPost, nil
+}
+
+func (a *App) GetScheduledPost(id string) (*model.ScheduledPost, *model.AppError) {
+	scheduledPost, err := a.Srv().Store.ScheduledPost().Get(id)
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		switch {
+		case errors.As(err, &nfErr):
+			return nil, model.NewAppError("GetScheduledPost", "app.scheduled_post.get.not_found.app_error", nil, nfErr.Error(), http.StatusNotFound)
+		default:
+			return nil, model.NewAppError("GetScheduledPost", "app.scheduled_post.get.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+	return scheduledPost, nil
+}
+
+// GetScheduledPostsForUser returns the posts the user scheduled in the channels of the
+// team, and in the direct and group message channels, including the ones which couldn't
+// be sent.
+func (a *App) GetScheduledPostsForUser(userID, teamID string) ([]*model.ScheduledPost, *model.AppError) {
+	if appErr := a.checkScheduledPostsEnabled("GetScheduledPostsForUser"); appErr != nil {
+		return nil, appErr
+	}
+
+	scheduledPosts, err := a.Srv().Store.ScheduledPost().GetForUser(userID, teamID)
+	if err != nil {
+		return nil, model.NewAppError("GetScheduledPostsForUser", "app.scheduled_post.get_for_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return scheduledPosts, nil
+}
+
+// UpdateScheduledPost updates the message, files, props and scheduled time of the post
+// scheduled by the user. A post which couldn't be sent is scheduled again.
+func (a *App) UpdateScheduledPost(userID string, scheduledPost *model.ScheduledPost) (*model.ScheduledPost, *model.AppError) {
+	if appErr := a.checkScheduledPostsEnabled("UpdateScheduledPost"); appErr != nil {
+		return nil, appErr
+	}
+
+	existing, appErr := a.GetScheduledPost(scheduledPost.Id)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if existing.UserId != userID {
+		return nil, model.NewAppError("UpdateScheduledPost", "app.scheduled_post.get.not_found.app_error", nil, "", http.StatusNotFound)
+	}
+
+	existing.Message = scheduledPost.Message
+	existing.FileIds = scheduledPost.FileIds
+	existing.Props = scheduledPost.Props
+	existing.ScheduledAt = scheduledPost.ScheduledAt
+	existing.ProcessedAt = 0
+	existing.ErrorCode = ""
+	if appErr := a.checkScheduledPost("UpdateScheduledPost", existing); appErr != nil {
+		return nil, appErr
+	}
+
+	if err := a.Srv().Store.ScheduledPost().Update(existing); err != nil {
+		var appErr *model.AppError
+		switch {
+		case errors.As(err, &appErr):
+			return nil, appErr
+		default:
+			return nil, model.NewAppError("UpdateScheduledPost", "app.scheduled_post.update.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	a.publishScheduledPostEvent(model.WebsocketEventScheduledPostUpdated, existing)
+
+	return existing, nil
+}
+
+// DeleteScheduledPost deletes the post scheduled by the user.
+func (a *App) DeleteScheduledPost(userID, id string) (*model.ScheduledPost, *model.AppError) {
+	scheduledPost, appErr := a.GetScheduledPost(id)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if scheduledPost.UserId != userID {
+		return nil, model.NewAppError("DeleteScheduledPost", "app.scheduled_post.get.not_found.app_error", nil, "", http.StatusNotFound)
+	}
+
+	if err := a.Srv().Store.ScheduledPost().Delete(id); err != nil {
+		return nil, model.NewAppError("DeleteScheduledPost", "app.scheduled_post.delete.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	a.publishScheduledPostEvent(model.WebsocketEventScheduledPostDeleted, scheduledPost)
+
+	return scheduledPost, nil
+}
+
+// ProcessScheduledPosts sends the scheduled posts which are due. A post is sent only if
+// its user can still post in its channel, otherwise it's kept with the reason it couldn't
+// be sent. A post failing to be processed is logged and doesn't stop the others from
+// being sent.
+func (a *App) ProcessScheduledPosts() *model.AppError {
+	now := model.GetMillis()
+	var afterTime int64
+	afterID := ""
+	for {
+		scheduledPosts, err := a.Srv().Store.ScheduledPost().GetPendingScheduledPosts(now, afterTime, afterID, scheduledPostsBatchSize)
+		if err != nil {
+			return model.NewAppError("ProcessScheduledPosts", "app.scheduled_post.get_pending.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		for _, scheduledPost := range scheduledPosts {
+			if appErr := a.sendScheduledPost(scheduledPost); appErr != nil {
+				mlog.Error("Failed to process scheduled post", mlog.String("scheduled_post_id", scheduledPost.Id), mlog.Err(appErr))
+			}
+		}
+
+		if len(scheduledPosts) < scheduledPostsBatchSize {
+			return nil
+		}
+		afterTime = scheduledPosts[len(scheduledPosts)-1].ScheduledAt
+		afterID = scheduledPosts[len(scheduledPosts)-1].Id
+	}
+}
+
+// sendScheduledPost sends the scheduled post and deletes it, or records why it couldn't
+// be sent. The scheduled post is marked as processed before being sent, so that it's
+// never sent twice, even when it can't be deleted afterwards.
+func (a *App) sendScheduledPost(scheduledPost *model.ScheduledPost) *model.AppError {
+	errorCode := a.checkCanSendScheduledPost(scheduledPost)
+	if appErr := a.setScheduledPostProcessed(scheduledPost, errorCode); appErr != nil {
+		return appErr
+	}
+	if errorCode != "" {
+		return nil
+	}
+
+	if _, appErr := a.CreatePostAsUser(request.EmptyContext(), scheduledPost.ToPost(), "", false); appErr != nil {
+		mlog.Warn("Failed to send scheduled post", mlog.String("scheduled_post_id", scheduledPost.Id), mlog.Err(appErr))
+		return a.setScheduledPostProcessed(scheduledPost, model.ScheduledPostErrorUnknown)
+	}
+
+	if err := a.Srv().Store.ScheduledPost().Delete(scheduledPost.Id); err != nil {
+		return model.NewAppError("ProcessScheduledPosts", "app.scheduled_post.delete.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	a.publishScheduledPostEvent(model.WebsocketEventScheduledPostDeleted, scheduledPost)
+
+	return nil
+}
+
+// setScheduledPostProcessed marks the scheduled post as processed, with the reason it
+// couldn't be sent if it couldn't.
+func (a *App) setScheduledPostProcessed(scheduledPost *model.ScheduledPost, errorCode string) *model.AppError {
+	scheduledPost.ProcessedAt = model.GetMillis()
+	scheduledPost.ErrorCode = errorCode
+	if err := a.Srv().Store.ScheduledPost().Update(scheduledPost); err != nil {
+		return model.NewAppError("ProcessScheduledPosts", "app.scheduled_post.update.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	a.publishScheduledPostEvent(model.WebsocketEventScheduledPostUpdated, scheduledPost)
+
+	return nil
+}
+
+// checkCanSendScheduledPost returns the error code telling why the scheduled post can't
+// be sent, if it can't.
+func (a *App) checkCanSendScheduledPost(scheduledPost *model.ScheduledPost) string {
+	user, err := a.Srv().Store.User().Get(context.Background(), scheduledPost.UserId)
+	if err != nil || user.DeleteAt != 0 {
+		return model.ScheduledPostErrorUserDeleted
+	}
+
+	channel, err := a.Srv().Store.Channel().Get(scheduledPost.ChannelId, true)
+	if err != nil {
+		return model.ScheduledPostErrorChannelNotFound
+	}
+	if channel.DeleteAt != 0 {
+		return model.ScheduledPostErrorChannelArchived
+	}
+
+	if scheduledPost.RootId != "" {
+		if _, appErr := a.GetSinglePost(scheduledPost.RootId); appErr != nil {
+			return model.ScheduledPostErrorThreadDeleted
+		}
+	}
+
+	if !a.HasPermissionToChannel(scheduledPost.UserId, scheduledPost.ChannelId, model.PermissionCreatePost) {
+		return model.ScheduledPostErrorNoChannelPermission
+	}
+
+	return ""
+}
+
+// publishScheduledPostEvent sends the scheduled post to the devices of its user.
+func (a *App) publishScheduledPostEvent(event string, scheduledPost *model.ScheduledPost) {
+	scheduledPostJSON, err := json.Marshal(scheduledPost)
+	if err != nil {
+		mlog.Warn("Failed to encode scheduled post to JSON", mlog.Err(err))
+		return
+	}
+
+	message := model.NewWebSocketEvent(event, "", scheduledPost.ChannelId, scheduledPost.UserId, nil)
+	message.Add("scheduled_post", string(scheduledPostJSON))
+	a.Publish(message)
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func TestSaveScheduledPost(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableScheduledPosts = true })
+
+	scheduledPost, appErr := th.App.SaveScheduledPost(&model.ScheduledPost{
+		Draft: model.Draft{
+			UserId:    th.BasicUser.Id,
+			ChannelId: th.BasicChannel.Id,
+			Message:   "scheduled",
+		},
+		ScheduledAt: model.GetMillis() + 60*1000,
+	})
+	require.Nil(t, appErr)
+	require.NotEmpty(t, scheduledPost.Id)
+
+	t.Run("in the past", func(t *testing.T) {
+		_, appErr := th.App.SaveScheduledPost(&model.ScheduledPost{
+			Draft: model.Draft{
+				UserId:    th.BasicUser.Id,
+				ChannelId: th.BasicChannel.Id,
+				Message:   "scheduled",
+			},
+			ScheduledAt: model.GetMillis() - 1000,
+		})
+		require.NotNil(t, appErr)
+		assert.Equal(t, "model.scheduled_post.is_valid.scheduled_at_past.app_error", appErr.Id)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		scheduledAt := model.GetMillis() + 120*1000
+		updated, appErr := th.App.UpdateScheduledPost(th.BasicUser.Id, &model.ScheduledPost{
+			Id:          scheduledPost.Id,
+			Draft:       model.Draft{Message: "updated"},
+			ScheduledAt: scheduledAt,
+		})
+		require.Nil(t, appErr)
+		assert.Equal(t, "updated", updated.Message)
+		assert.Equal(t, scheduledAt, updated.ScheduledAt)
+		assert.Equal(t, th.BasicChannel.Id, updated.ChannelId)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		_, appErr := th.App.UpdateScheduledPost(th.BasicUser2.Id, scheduledPost)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+		_, appErr = th.App.DeleteScheduledPost(th.BasicUser2.Id, scheduledPost.Id)
+		require.NotNil(t, appErr)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, appErr := th.App.DeleteScheduledPost(th.BasicUser.Id, scheduledPost.Id)
+		require.Nil(t, appErr)
+
+		scheduledPosts, appErr := th.App.GetScheduledPostsForUser(th.BasicUser.Id, th.BasicTeam.Id)
+		require.Nil(t, appErr)
+		assert.Empty(t, scheduledPosts)
+	})
+}
+
+func TestProcessScheduledPosts(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+	th.App.UpdateConfig(func(cfg *model.Config) { *cfg.ServiceSettings.EnableScheduledPosts = true })
+
+	// schedule saves a post scheduled in the future, and makes it due.
+	schedule := func(user *model.User, channel *model.Channel, rootID string) *model.ScheduledPost {
+		scheduledPost, appErr := th.App.SaveScheduledPost(&model.ScheduledPost{
+			Draft: model.Draft{
+				UserId:    user.Id,
+				ChannelId: channel.Id,
+				RootId:    rootID,
+				Message:   "scheduled " + model.NewId(),
+			},
+			ScheduledAt: model.GetMillis() + 60*1000,
+		})
+		require.Nil(t, appErr)
+		scheduledPost.ScheduledAt = model.GetMillis() - 1000
+		require.NoError(t, th.App.Srv().Store.ScheduledPost().Update(scheduledPost))
+		return scheduledPost
+	}
+
+	sent := schedule(th.BasicUser, th.BasicChannel, th.BasicPost.Id)
+
+	archivedChannel := th.CreateChannel(th.BasicTeam)
+	archived := schedule(th.BasicUser, archivedChannel, "")
+	require.Nil(t, th.App.DeleteChannel(th.Context, archivedChannel, th.SystemAdminUser.Id))
+
+	leftChannel := th.CreateChannel(th.BasicTeam)
+	th.AddUserToChannel(th.BasicUser2, leftChannel)
+	noPermission := schedule(th.BasicUser2, leftChannel, "")
+	require.Nil(t, th.App.RemoveUserFromChannel(th.Context, th.BasicUser2.Id, th.BasicUser2.Id, leftChannel))
+
+	rootPost := th.CreatePost(th.BasicChannel)
+	threadDeleted := schedule(th.BasicUser, th.BasicChannel, rootPost.Id)
+	_, appErr := th.App.DeletePost(rootPost.Id, th.BasicUser.Id)
+	require.Nil(t, appErr)
+
+	require.Nil(t, th.App.ProcessScheduledPosts())
+
+	_, err := th.App.Srv().Store.ScheduledPost().Get(sent.Id)
+	require.Error(t, err)
+	posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: th.BasicChannel.Id, PerPage: 10})
+	require.Nil(t, appErr)
+	var messages []string
+	for _, post := range posts.Posts {
+		messages = append(messages, post.Message)
+	}
+	assert.Contains(t, messages, sent.Message)
+	assert.NotContains(t, messages, threadDeleted.Message)
+
+	for scheduledPost, errorCode := range map[*model.ScheduledPost]string{
+		archived:      model.ScheduledPostErrorChannelArchived,
+		noPermission:  model.ScheduledPostErrorNoChannelPermission,
+		threadDeleted: model.ScheduledPostErrorThreadDeleted,
+	} {
+		failed, err := th.App.Srv().Store.ScheduledPost().Get(scheduledPost.Id)
+		require.NoError(t, err)
+		assert.Equal(t, errorCode, failed.ErrorCode)
+		assert.NotZero(t, failed.ProcessedAt)
+	}
+
+	t.Run("processed posts aren't sent again", func(t *testing.T) {
+		require.Nil(t, th.App.ProcessScheduledPosts())
+		failed, err := th.App.Srv().Store.ScheduledPost().Get(archived.Id)
+		require.NoError(t, err)
+		assert.Equal(t, model.ScheduledPostErrorChannelArchived, failed.ErrorCode)
+	})
+
+	t.Run("posts which can't be deleted once sent aren't sent again", func(t *testing.T) {
+		first := schedule(th.BasicUser, th.BasicChannel, "")
+		second := schedule(th.BasicUser, th.BasicChannel, "")
+
+		sqlStore := th.App.Srv().Store
+		th.App.Srv().Store = &failingDeleteStore{Store: sqlStore}
+		require.Nil(t, th.App.ProcessScheduledPosts())
+		th.App.Srv().Store = sqlStore
+		require.Nil(t, th.App.ProcessScheduledPosts())
+
+		posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: th.BasicChannel.Id, PerPage: 20})
+		require.Nil(t, appErr)
+		count := map[string]int{}
+		for _, post := range posts.Posts {
+			count[post.Message]++
+		}
+		assert.Equal(t, 1, count[first.Message])
+		assert.Equal(t, 1, count[second.Message])
+	})
+}
+
+// failingDeleteStore is a store failing to delete the scheduled posts.
+type failingDeleteStore struct {
+	store.Store
+}
+
+func (s *failingDeleteStore) ScheduledPost() store.ScheduledPostStore {
+	return &failingDeleteScheduledPostStore{ScheduledPostStore: s.Store.ScheduledPost()}
+}
+
+type failingDeleteScheduledPostStore struct {
+	store.ScheduledPostStore
+}
+
+func (s *failingDeleteScheduledPostStore) Delete(id string) error {
+	return errors.New("failed to delete")
+}
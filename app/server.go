@@ -55,7 +55,9 @@ import (
 	"github.com/cjdelisle/matterfoss-server/v6/jobs/import_process"
 	"github.com/cjdelisle/matterfoss-server/v6/jobs/migrations"
 	"github.com/cjdelisle/matterfoss-server/v6/jobs/product_notices"
+	"github.com/cjdelisle/matterfoss-server/v6/jobs/reminders"
 	"github.com/cjdelisle/matterfoss-server/v6/jobs/resend_invitation_email"
+	"github.com/cjdelisle/matterfoss-server/v6/jobs/scheduled_posts"
 	"github.com/cjdelisle/matterfoss-server/v6/model"
 	"github.com/cjdelisle/matterfoss-server/v6/plugin/scheduler"
 	"github.com/cjdelisle/matterfoss-server/v6/services/awsmeter"
@@ -275,9 +277,8 @@ func NewServer(options ...Option) (*Server, error) {
 	s.initEnterprise()
 
 	// Step 5: Cache provider.
-	// At the moment we only have this implementation
-	// in the future the cache provider will be built based on the loaded config
-	s.CacheProvider = cache.NewProvider()
+	// Depends on step 1 (config).
+	s.CacheProvider = newCacheProvider(s.Config().CacheSettings)
 	if err2 := s.CacheProvider.Connect(); err2 != nil {
 		return nil, errors.Wrapf(err2, "Unable to connect to cache provider")
 	}
@@ -696,6 +697,19 @@ func (s *Server) SetupMetricsServer() {
 	s.startMetricsServer()
 }
 
+// newCacheProvider builds the cache provider chosen in the config.
+func newCacheProvider(settings model.CacheSettings) cache.Provider {
+	if *settings.CacheType == model.CacheTypeRedis {
+		return cache.NewRedisProvider(cache.RedisOptions{
+			Address:  *settings.RedisAddress,
+			Password: *settings.RedisPassword,
+			DB:       *settings.RedisDB,
+			Prefix:   *settings.RedisPrefix,
+		})
+	}
+	return cache.NewProvider()
+}
+
 func maxInt(a, b int) int {
 	if a > b {
 		return a
@@ -1536,12 +1550,22 @@ func doSecurity(s *Server) {
 	s.DoSecurityUpdateCheck()
 }
 
+// tokenTypesWithoutExpiry are the types of the tokens which outlive MaxTokenExipryTime,
+// and are removed once used instead.
+var tokenTypesWithoutExpiry = []string{
+	// The resets awaiting an approval are removed when approved or denied.
+	TokenTypeMfaResetApproval,
+	// The used refresh tokens are removed once the app is no longer authorized.
+	TokenTypeOAuthUsedRefreshToken,
+}
+
 func doTokenCleanup(s *Server) {
 	expiry := model.GetMillis() - model.MaxTokenExipryTime
 
 	mlog.Debug("Cleaning up token store.")
 
-	s.Store.Token().Cleanup(expiry)
+	s.Store.Token().Cleanup(expiry, tokenTypesWithoutExpiry)
+	cleanupUsedOAuthRefreshTokens(s)
 }
 
 func doCommandWebhookCleanup(s *Server) {
@@ -1992,6 +2016,18 @@ func (s *Server) initJobs() {
 		extract_content.MakeWorker(s.Jobs, New(ServerConnector(s.Channels())), s.Store),
 		nil,
 	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeScheduledPosts,
+		scheduled_posts.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		scheduled_posts.MakeScheduler(s.Jobs),
+	)
+
+	s.Jobs.RegisterJobType(
+		model.JobTypeReminders,
+		reminders.MakeWorker(s.Jobs, New(ServerConnector(s.Channels()))),
+		reminders.MakeScheduler(s.Jobs),
+	)
 }
 
 func (s *Server) TelemetryId() string {
@@ -703,3 +703,21 @@ func TestSentry(t *testing.T) {
 		}
 	})
 }
+
+func TestDoTokenCleanup(t *testing.T) {
+	th := Setup(t)
+	defer th.TearDown()
+
+	expired := model.GetMillis() - model.MaxTokenExipryTime - 1000
+	oldToken := &model.Token{Token: model.NewRandomString(model.TokenSize), CreateAt: expired, Type: TokenTypeMfaReset}
+	require.NoError(t, th.Server.Store.Token().Save(oldToken))
+	approval := &model.Token{Token: model.NewRandomString(model.TokenSize), CreateAt: expired, Type: TokenTypeMfaResetApproval}
+	require.NoError(t, th.Server.Store.Token().Save(approval))
+
+	doTokenCleanup(th.Server)
+
+	_, err := th.Server.Store.Token().GetByToken(oldToken.Token)
+	assert.Error(t, err)
+	_, err = th.Server.Store.Token().GetByToken(approval.Token)
+	assert.NoError(t, err, "the resets awaiting an approval should not expire")
+}
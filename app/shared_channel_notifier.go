@@ -20,6 +20,10 @@ var sharedChannelEventsForSync model.StringArray = []string{
 	model.WebsocketEventPostDeleted,
 	model.WebsocketEventReactionAdded,
 	model.WebsocketEventReactionRemoved,
+	model.WebsocketEventChannelBookmarkCreated,
+	model.WebsocketEventChannelBookmarkUpdated,
+	model.WebsocketEventChannelBookmarkDeleted,
+	model.WebsocketEventChannelBookmarkSorted,
 }
 
 var sharedChannelEventsForInvitation model.StringArray = []string{
@@ -4,10 +4,12 @@
 package app
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
 	"fmt"
 	"image"
+	"io"
 	"mime/multipart"
 	"regexp"
 	"strings"
@@ -15,6 +17,7 @@ import (
 
 	"github.com/cjdelisle/matterfoss-server/v6/app/request"
 	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/services/bulkimport"
 	"github.com/cjdelisle/matterfoss-server/v6/services/slackimport"
 	"github.com/cjdelisle/matterfoss-server/v6/store"
 )
@@ -53,6 +56,40 @@ func (a *App) SlackImport(c *request.Context, fileData multipart.File, fileSize
 	return importer.SlackImport(fileData, fileSize, teamID)
 }
 
+// SlackConvertToBulkImport converts the Slack export into a bulk import data file for
+// the team, written to w, and returns the number of lines written. The Slack users are
+// merged with the existing users sharing their email address.
+func (a *App) SlackConvertToBulkImport(zipReader *zip.Reader, w io.Writer, teamID string) (int, *model.AppError) {
+	opts, appErr := a.bulkConvertOptions(teamID)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	return slackimport.ConvertToBulkImport(zipReader, w, opts)
+}
+
+// bulkConvertOptions returns the options of the conversion of an export to a bulk import
+// data file for the team, merging the users of the export with the existing users
+// sharing their email address.
+func (a *App) bulkConvertOptions(teamID string) (bulkimport.ConvertOptions, *model.AppError) {
+	team, appErr := a.GetTeam(teamID)
+	if appErr != nil {
+		return bulkimport.ConvertOptions{}, appErr
+	}
+
+	return bulkimport.ConvertOptions{
+		TeamName:    team.Name,
+		MaxPostSize: a.MaxPostSize(),
+		ExistingUser: func(email string) *model.User {
+			user, err := a.Srv().Store.User().GetByEmail(email)
+			if err != nil {
+				return nil
+			}
+			return user
+		},
+	}, nil
+}
+
 func (a *App) ProcessSlackText(text string) string {
 	text = expandAnnouncement(text)
 	text = replaceUserIds(a.Srv().Store.User(), text)
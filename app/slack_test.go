@@ -4,8 +4,14 @@
 package app
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/cjdelisle/matterfoss-server/v6/model"
 )
 
@@ -84,3 +90,91 @@ func TestProcessSlackAnnouncement(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestSlackConvertToBulkImport(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for name, content := range map[string]string{
+		"users.json": `[
+			{"id": "U1", "name": "slack.alice", "profile": {"email": "slack.alice@example.com"}},
+			{"id": "U2", "name": "slack.bob", "profile": {"email": "` + th.BasicUser2.Email + `"}}
+		]`,
+		"channels.json": `[{"id": "C1", "name": "slack-general", "members": ["U1", "U2"], "topic": {"value": "Topic"}}]`,
+		"dms.json":      `[{"id": "D1", "members": ["U1", "U2"]}]`,
+		"slack-general/2021-01-01.json": `[
+			{"type": "message", "user": "U1", "text": "Root", "ts": "1609459200.000100", "thread_ts": "1609459200.000100",
+				"reactions": [{"name": "smile", "users": ["U2"], "count": 1}], "pinned_to": ["C1"]},
+			{"type": "message", "user": "U2", "text": "Reply", "ts": "1609459201.000000", "thread_ts": "1609459200.000100"},
+			{"type": "message", "subtype": "file_share", "user": "U1", "text": "File", "ts": "1609459202.000000", "files": [{"id": "F1"}]},
+			{"type": "message", "subtype": "bot_message", "bot_id": "B1", "username": "ci", "text": "Build passed", "ts": "1609459203.000000"}
+		]`,
+		"D1/2021-01-01.json":    `[{"type": "message", "user": "U2", "text": "Direct", "ts": "1609459200.000000"}]`,
+		"__uploads/F1/file.txt": "content",
+	} {
+		w, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	zipReader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	existingUser, err := th.App.Srv().Store.User().Get(context.Background(), th.BasicUser2.Id)
+	require.NoError(t, err)
+
+	data := &bytes.Buffer{}
+	lines, appErr := th.App.SlackConvertToBulkImport(zipReader, data, th.BasicTeam.Id)
+	require.Nil(t, appErr)
+	require.Equal(t, 10, lines)
+
+	var failedLines []int
+	appErr, _ = th.App.BulkImportWithOpts(th.Context, data, zipReader, model.BulkImportOpts{
+		Workers:     1,
+		OnLineError: func(lineNumber int, err *model.AppError) { failedLines = append(failedLines, lineNumber) },
+	})
+	require.Nil(t, appErr)
+	require.Empty(t, failedLines)
+
+	alice, appErr := th.App.GetUserByUsername("slack.alice")
+	require.Nil(t, appErr)
+
+	channel, appErr := th.App.GetChannelByName("slack-general", th.BasicTeam.Id, false)
+	require.Nil(t, appErr)
+	assert.Equal(t, "Topic", channel.Header)
+	_, appErr = th.App.GetChannelMember(context.Background(), channel.Id, th.BasicUser2.Id)
+	require.Nil(t, appErr, "the Slack user should be merged with the user sharing their email")
+	mergedUser, err := th.App.Srv().Store.User().Get(context.Background(), th.BasicUser2.Id)
+	require.NoError(t, err)
+	assert.Equal(t, existingUser.Password, mergedUser.Password, "the merged user should keep their password")
+
+	posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: channel.Id, PerPage: 10})
+	require.Nil(t, appErr)
+	require.Len(t, posts.Order, 4)
+
+	root := posts.Posts[posts.Order[3]]
+	assert.Equal(t, "Root", root.Message)
+	assert.Equal(t, alice.Id, root.UserId)
+	assert.True(t, root.IsPinned)
+	assert.EqualValues(t, 1609459200000, root.CreateAt)
+
+	reply := posts.Posts[posts.Order[2]]
+	assert.Equal(t, "Reply", reply.Message)
+	assert.Equal(t, root.Id, reply.RootId)
+	assert.Equal(t, th.BasicUser2.Id, reply.UserId)
+
+	reactions, appErr := th.App.GetReactionsForPost(root.Id)
+	require.Nil(t, appErr)
+	require.Len(t, reactions, 1)
+	assert.Equal(t, "smile", reactions[0].EmojiName)
+
+	file := posts.Posts[posts.Order[1]]
+	assert.Len(t, file.FileIds, 1)
+
+	bot := posts.Posts[posts.Order[0]]
+	assert.Equal(t, model.PostTypeSlackAttachment, bot.Type)
+	assert.Equal(t, "ci", bot.GetProp("override_username"))
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package slashcommands
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/i18n"
+)
+
+type PollProvider struct {
+}
+
+const (
+	CmdPoll = "poll"
+)
+
+func init() {
+	app.RegisterCommandProvider(&PollProvider{})
+}
+
+func (*PollProvider) GetTrigger() string {
+	return CmdPoll
+}
+
+func (*PollProvider) GetCommand(a *app.App, T i18n.TranslateFunc) *model.Command {
+	return &model.Command{
+		Trigger:          CmdPoll,
+		AutoComplete:     true,
+		AutoCompleteDesc: T("api.command_poll.desc"),
+		AutoCompleteHint: T("api.command_poll.hint"),
+		DisplayName:      T("api.command_poll.name"),
+	}
+}
+
+func (*PollProvider) DoCommand(a *app.App, c *request.Context, args *model.CommandArgs, message string) *model.CommandResponse {
+	question, poll, ok := parsePollCommand(message)
+	if !ok {
+		return &model.CommandResponse{Text: args.T("api.command_poll.usage"), ResponseType: model.CommandResponseTypeEphemeral}
+	}
+
+	if appErr := poll.IsValid(); appErr != nil {
+		return &model.CommandResponse{Text: appErr.SystemMessage(args.T), ResponseType: model.CommandResponseTypeEphemeral}
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeInChannel,
+		Type:         model.PostTypePoll,
+		Text:         question,
+		Props:        model.StringInterface{model.PostPropsPoll: poll},
+	}
+}
+
+// parsePollCommand parses a command of the form `"question" "option 1" "option 2" [--anonymous]
+// [--multi]`. Arguments without spaces need not be quoted.
+func parsePollCommand(message string) (string, *model.Poll, bool) {
+	poll := &model.Poll{}
+	var args []string
+	for _, arg := range splitQuotedArgs(message) {
+		switch strings.ToLower(arg) {
+		case "--anonymous":
+			poll.Anonymous = true
+		case "--multi":
+			poll.MultiChoice = true
+		default:
+			args = append(args, arg)
+		}
+	}
+
+	if len(args) < 1 || strings.TrimSpace(args[0]) == "" {
+		return "", nil, false
+	}
+	poll.Options = args[1:]
+	return args[0], poll, true
+}
+
+// splitQuotedArgs splits the message on spaces, except within double quotes. The typographic
+// quotes inserted by some keyboards are accepted as well.
+func splitQuotedArgs(message string) []string {
+	var args []string
+	var current strings.Builder
+	var closing rune
+	quoted := false
+
+	for _, r := range message {
+		switch {
+		case closing != 0:
+			if r == closing {
+				closing = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"':
+			closing, quoted = r, true
+		case r == '“':
+			closing, quoted = '”', true
+		case unicode.IsSpace(r):
+			if current.Len() > 0 || quoted {
+				args = append(args, current.String())
+				current.Reset()
+				quoted = false
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 || quoted {
+		args = append(args, current.String())
+	}
+
+	return args
+}
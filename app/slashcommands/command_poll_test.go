@@ -0,0 +1,71 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package slashcommands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/i18n"
+)
+
+func TestParsePollCommand(t *testing.T) {
+	for name, tc := range map[string]struct {
+		message  string
+		question string
+		poll     *model.Poll
+		ok       bool
+	}{
+		"quoted":            {`"Lunch?" "Pizza place" Sushi`, "Lunch?", &model.Poll{Options: []string{"Pizza place", "Sushi"}}, true},
+		"typographic":       {`“Where to?” “The beach” “The hills”`, "Where to?", &model.Poll{Options: []string{"The beach", "The hills"}}, true},
+		"flags":             {`--anonymous "Best day?" Monday Friday --multi`, "Best day?", &model.Poll{Options: []string{"Monday", "Friday"}, Anonymous: true, MultiChoice: true}, true},
+		"apostrophe":        {`"What's next?" "Bob's idea" mine`, "What's next?", &model.Poll{Options: []string{"Bob's idea", "mine"}}, true},
+		"empty option kept": {`"Q?" "" b`, "Q?", &model.Poll{Options: []string{"", "b"}}, true},
+		"no options":        {`"Q?"`, "Q?", &model.Poll{Options: []string{}}, true},
+		"empty":             {"", "", nil, false},
+		"empty question":    {`"" a b`, "", nil, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			question, poll, ok := parsePollCommand(tc.message)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.question, question)
+			assert.Equal(t, tc.poll, poll)
+		})
+	}
+}
+
+func TestPollCommand(t *testing.T) {
+	th := setup(t).initBasic()
+	defer th.tearDown()
+
+	cmd := &PollProvider{}
+	args := &model.CommandArgs{
+		T:         i18n.IdentityTfunc(),
+		ChannelId: th.BasicChannel.Id,
+		UserId:    th.BasicUser.Id,
+	}
+
+	resp := cmd.DoCommand(th.App, th.Context, args, "")
+	assert.Equal(t, "api.command_poll.usage", resp.Text)
+
+	resp = cmd.DoCommand(th.App, th.Context, args, `"Lunch?" Pizza`)
+	assert.Equal(t, model.CommandResponseTypeEphemeral, resp.ResponseType)
+	assert.Equal(t, "model.poll.is_valid.options.app_error", resp.Text)
+
+	resp = cmd.DoCommand(th.App, th.Context, args, `"Lunch?" Pizza Sushi --multi`)
+	assert.Equal(t, model.CommandResponseTypeInChannel, resp.ResponseType)
+	assert.Equal(t, model.PostTypePoll, resp.Type)
+	assert.Equal(t, "Lunch?", resp.Text)
+
+	post, appErr := th.App.HandleCommandResponsePost(th.Context, &model.Command{}, args, resp, true)
+	require.Nil(t, appErr)
+	poll := post.GetPoll()
+	require.NotNil(t, poll)
+	assert.Equal(t, []string{"Pizza", "Sushi"}, poll.Options)
+	assert.True(t, poll.MultiChoice)
+	assert.False(t, poll.Anonymous)
+}
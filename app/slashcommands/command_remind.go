@@ -0,0 +1,160 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package slashcommands
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/i18n"
+)
+
+type RemindProvider struct {
+}
+
+const (
+	CmdRemind = "remind"
+)
+
+var reminderDurationRegexp = regexp.MustCompile(`^(\d+)([a-z]*)$`)
+
+var reminderDurationUnits = map[string]time.Duration{
+	"m":       time.Minute,
+	"min":     time.Minute,
+	"mins":    time.Minute,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"h":       time.Hour,
+	"hr":      time.Hour,
+	"hrs":     time.Hour,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"d":       24 * time.Hour,
+	"day":     24 * time.Hour,
+	"days":    24 * time.Hour,
+	"w":       7 * 24 * time.Hour,
+	"week":    7 * 24 * time.Hour,
+	"weeks":   7 * 24 * time.Hour,
+}
+
+func init() {
+	app.RegisterCommandProvider(&RemindProvider{})
+}
+
+func (*RemindProvider) GetTrigger() string {
+	return CmdRemind
+}
+
+func (*RemindProvider) GetCommand(a *app.App, T i18n.TranslateFunc) *model.Command {
+	return &model.Command{
+		Trigger:          CmdRemind,
+		AutoComplete:     true,
+		AutoCompleteDesc: T("api.command_remind.desc"),
+		AutoCompleteHint: T("api.command_remind.hint"),
+		DisplayName:      T("api.command_remind.name"),
+	}
+}
+
+func (*RemindProvider) DoCommand(a *app.App, c *request.Context, args *model.CommandArgs, message string) *model.CommandResponse {
+	if strings.TrimSpace(message) == "list" {
+		return listReminders(a, args)
+	}
+
+	duration, text, ok := parseReminderCommand(message)
+	if !ok {
+		return &model.CommandResponse{Text: args.T("api.command_remind.usage"), ResponseType: model.CommandResponseTypeEphemeral}
+	}
+
+	user, appErr := a.GetUser(args.UserId)
+	if appErr != nil {
+		return &model.CommandResponse{Text: args.T("api.command_remind.app_error"), ResponseType: model.CommandResponseTypeEphemeral}
+	}
+
+	reminder, appErr := a.SaveReminder(&model.Reminder{
+		UserId:   args.UserId,
+		Message:  text,
+		TargetAt: model.GetMillis() + duration.Milliseconds(),
+	})
+	if appErr != nil {
+		return &model.CommandResponse{Text: args.T("api.command_remind.app_error"), ResponseType: model.CommandResponseTypeEphemeral}
+	}
+
+	return &model.CommandResponse{
+		Text:         args.T("api.command_remind.success", map[string]interface{}{"Time": app.FormatReminderTime(user, reminder.TargetAt)}),
+		ResponseType: model.CommandResponseTypeEphemeral,
+	}
+}
+
+func listReminders(a *app.App, args *model.CommandArgs) *model.CommandResponse {
+	user, appErr := a.GetUser(args.UserId)
+	if appErr != nil {
+		return &model.CommandResponse{Text: args.T("api.command_remind.app_error"), ResponseType: model.CommandResponseTypeEphemeral}
+	}
+
+	reminders, appErr := a.GetRemindersForUser(args.UserId)
+	if appErr != nil {
+		return &model.CommandResponse{Text: args.T("api.command_remind.app_error"), ResponseType: model.CommandResponseTypeEphemeral}
+	}
+	if len(reminders) == 0 {
+		return &model.CommandResponse{Text: args.T("api.command_remind.list.empty"), ResponseType: model.CommandResponseTypeEphemeral}
+	}
+
+	lines := []string{args.T("api.command_remind.list.header")}
+	for _, reminder := range reminders {
+		text := reminder.Message
+		if reminder.PostId != "" {
+			text = args.T("api.command_remind.list.post")
+		}
+		lineID := "api.command_remind.list.pending"
+		if reminder.FiredAt != 0 {
+			lineID = "api.command_remind.list.fired"
+		}
+		lines = append(lines, args.T(lineID, map[string]interface{}{"Time": app.FormatReminderTime(user, reminder.TargetAt), "Message": text}))
+	}
+
+	return &model.CommandResponse{Text: strings.Join(lines, "\n"), ResponseType: model.CommandResponseTypeEphemeral}
+}
+
+// parseReminderCommand parses "me in <duration> [to] <message>", where the duration is a
+// number followed by a unit, such as "2h" or "30 minutes".
+func parseReminderCommand(message string) (time.Duration, string, bool) {
+	fields := strings.Fields(message)
+	if len(fields) < 3 || strings.ToLower(fields[0]) != "me" || strings.ToLower(fields[1]) != "in" {
+		return 0, "", false
+	}
+
+	matches := reminderDurationRegexp.FindStringSubmatch(strings.ToLower(fields[2]))
+	if matches == nil {
+		return 0, "", false
+	}
+	amount, err := strconv.Atoi(matches[1])
+	if err != nil || amount <= 0 {
+		return 0, "", false
+	}
+
+	rest := fields[3:]
+	unitName := matches[2]
+	if unitName == "" && len(rest) > 0 {
+		unitName = strings.ToLower(rest[0])
+		rest = rest[1:]
+	}
+	unit, ok := reminderDurationUnits[unitName]
+	if !ok {
+		return 0, "", false
+	}
+
+	if len(rest) > 0 && strings.ToLower(rest[0]) == "to" {
+		rest = rest[1:]
+	}
+	if len(rest) == 0 {
+		return 0, "", false
+	}
+
+	return time.Duration(amount) * unit, strings.Join(rest, " "), true
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package slashcommands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/i18n"
+)
+
+func TestParseReminderCommand(t *testing.T) {
+	for name, tc := range map[string]struct {
+		message  string
+		duration time.Duration
+		text     string
+		ok       bool
+	}{
+		"short unit":      {"me in 2h to call Bob", 2 * time.Hour, "call Bob", true},
+		"word unit":       {"me in 30 minutes check the build", 30 * time.Minute, "check the build", true},
+		"days":            {"Me In 1d to water the plants", 24 * time.Hour, "water the plants", true},
+		"weeks":           {"me in 2 weeks renew", 14 * 24 * time.Hour, "renew", true},
+		"no message":      {"me in 2h to", 0, "", false},
+		"unknown unit":    {"me in 2 fortnights renew", 0, "", false},
+		"no duration":     {"me in to renew", 0, "", false},
+		"zero duration":   {"me in 0m renew", 0, "", false},
+		"missing me":      {"in 2h renew", 0, "", false},
+		"empty":           {"", 0, "", false},
+		"someone else":    {"bob in 2h renew", 0, "", false},
+		"to kept in text": {"me in 5m to talk to Bob", 5 * time.Minute, "talk to Bob", true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			duration, text, ok := parseReminderCommand(tc.message)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.duration, duration)
+			assert.Equal(t, tc.text, text)
+		})
+	}
+}
+
+func TestRemindCommand(t *testing.T) {
+	th := setup(t).initBasic()
+	defer th.tearDown()
+
+	cmd := &RemindProvider{}
+	args := &model.CommandArgs{
+		T:         i18n.IdentityTfunc(),
+		ChannelId: th.BasicChannel.Id,
+		UserId:    th.BasicUser.Id,
+	}
+
+	resp := cmd.DoCommand(th.App, th.Context, args, "list")
+	assert.Equal(t, "api.command_remind.list.empty", resp.Text)
+
+	resp = cmd.DoCommand(th.App, th.Context, args, "me tomorrow")
+	assert.Equal(t, "api.command_remind.usage", resp.Text)
+
+	before := model.GetMillis()
+	resp = cmd.DoCommand(th.App, th.Context, args, "me in 2h to call Bob")
+	assert.Equal(t, "api.command_remind.success", resp.Text)
+
+	reminders, appErr := th.App.GetRemindersForUser(th.BasicUser.Id)
+	require.Nil(t, appErr)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, "call Bob", reminders[0].Message)
+	assert.Empty(t, reminders[0].PostId)
+	assert.GreaterOrEqual(t, reminders[0].TargetAt, before+(2*time.Hour).Milliseconds())
+
+	resp = cmd.DoCommand(th.App, th.Context, args, "list")
+	assert.Equal(t, "api.command_remind.list.header\napi.command_remind.list.pending", resp.Text)
+}
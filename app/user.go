@@ -32,14 +32,16 @@ import (
 )
 
 const (
-	TokenTypePasswordRecovery  = "password_recovery"
-	TokenTypeVerifyEmail       = "verify_email"
-	TokenTypeTeamInvitation    = "team_invitation"
-	TokenTypeGuestInvitation   = "guest_invitation"
-	TokenTypeCWSAccess         = "cws_access_token"
-	PasswordRecoverExpiryTime  = 1000 * 60 * 60 * 24 // 24 hours
-	InvitationExpiryTime       = 1000 * 60 * 60 * 48 // 48 hours
-	ImageProfilePixelDimension = 128
+	TokenTypePasswordRecovery     = "password_recovery"
+	TokenTypeVerifyEmail          = "verify_email"
+	TokenTypeTeamInvitation       = "team_invitation"
+	TokenTypeGuestInvitation      = "guest_invitation"
+	TokenTypeCWSAccess            = "cws_access_token"
+	TokenTypeWebAuthnRegistration = "webauthn_registration"
+	TokenTypeWebAuthnLogin        = "webauthn_login"
+	PasswordRecoverExpiryTime     = 1000 * 60 * 60 * 24 // 24 hours
+	InvitationExpiryTime          = 1000 * 60 * 60 * 48 // 48 hours
+	ImageProfilePixelDimension    = 128
 )
 
 func (a *App) CreateUserWithToken(c *request.Context, user *model.User, token *model.Token) (*model.User, *model.AppError) {
@@ -708,6 +710,10 @@ func (a *App) DeactivateMfa(userID string) *model.AppError {
 		return model.NewAppError("DeactivateMfa", "mfa.deactivate.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
+	if err := a.Srv().Store.MfaRecoveryCode().PermanentDeleteByUser(userID); err != nil {
+		return model.NewAppError("DeactivateMfa", "app.mfa_recovery_code.delete.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
 	// Make sure old MFA status is not cached locally or in cluster nodes.
 	a.InvalidateCacheForUser(userID)
 
@@ -1547,6 +1553,34 @@ func (a *App) PermanentDeleteUser(c *request.Context, user *model.User) *model.A
 		return model.NewAppError("PermanentDeleteUser", "app.post.permanent_delete_by_user.app_error", nil, err.Error(), http.StatusInternalServerError)
 	}
 
+	if err := a.Srv().Store.PostRevision().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.post_revision.permanent_delete_by_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if err := a.Srv().Store.Draft().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.draft.permanent_delete_by_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if err := a.Srv().Store.ScheduledPost().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.scheduled_post.permanent_delete_by_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if err := a.Srv().Store.Reminder().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.reminder.permanent_delete_by_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if err := a.Srv().Store.PollVote().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.poll.permanent_delete_by_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if err := a.Srv().Store.WebAuthnCredential().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.webauthn.permanent_delete_by_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if err := a.Srv().Store.MfaRecoveryCode().PermanentDeleteByUser(user.Id); err != nil {
+		return model.NewAppError("PermanentDeleteUser", "app.mfa_recovery_code.delete.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
 	if err := a.Srv().Store.Bot().PermanentDelete(user.Id); err != nil {
 		var invErr *store.ErrInvalidInput
 		switch {
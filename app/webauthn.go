@@ -0,0 +1,331 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/webauthn"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+// webAuthnCeremony is the state of a registration or a login, kept in the extra of the
+// token the challenge of the ceremony is made of.
+type webAuthnCeremony struct {
+	// The user the credential is registered for, or expected to log in. It is empty for
+	// the logins with a discoverable credential.
+	UserId string `json:"user_id"`
+}
+
+// webAuthnStoreError converts an error of the WebAuthn credential store to an app error.
+func webAuthnStoreError(where, id string, err error) *model.AppError {
+	var appErr *model.AppError
+	var nfErr *store.ErrNotFound
+	var conflictErr *store.ErrConflict
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.As(err, &nfErr):
+		return model.NewAppError(where, "app.webauthn.get.not_found.app_error", nil, nfErr.Error(), http.StatusNotFound)
+	case errors.As(err, &conflictErr):
+		return model.NewAppError(where, "app.webauthn.register.already_registered.app_error", nil, conflictErr.Error(), http.StatusBadRequest)
+	default:
+		return model.NewAppError(where, id, nil, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// webAuthnRelyingParty returns the relying party the credentials are registered with,
+// which is the site of the server.
+func (a *App) webAuthnRelyingParty(where string) (*webauthn.RelyingParty, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableWebAuthn {
+		return nil, model.NewAppError(where, "app.webauthn.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	rp, err := webauthn.NewRelyingParty(a.GetSiteURL(), *a.Config().TeamSettings.SiteName)
+	if err != nil {
+		return nil, model.NewAppError(where, "app.webauthn.site_url.app_error", nil, err.Error(), http.StatusNotImplemented)
+	}
+	return rp, nil
+}
+
+// newWebAuthnChallenge starts a ceremony, returning its challenge. The challenge is a
+// single use token, so that the ceremony can be completed without a session.
+func (a *App) newWebAuthnChallenge(where, tokenType, userID string) ([]byte, *model.AppError) {
+	extra, err := json.Marshal(&webAuthnCeremony{UserId: userID})
+	if err != nil {
+		return nil, model.NewAppError(where, "app.webauthn.save_challenge.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	token := model.NewToken(tokenType, string(extra))
+	if err := a.Srv().Store.Token().Save(token); err != nil {
+		return nil, model.NewAppError(where, "app.webauthn.save_challenge.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return []byte(token.Token), nil
+}
+
+// completeWebAuthnChallenge ends the ceremony with the given challenge, which can't be
+// completed again, and returns its state.
+func (a *App) completeWebAuthnChallenge(where, tokenType string, challenge []byte, rp *webauthn.RelyingParty) (*webAuthnCeremony, *model.AppError) {
+	token, err := a.Srv().Store.Token().GetByToken(string(challenge))
+	if err != nil || token.Type != tokenType {
+		return nil, model.NewAppError(where, "app.webauthn.invalid_challenge.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if err := a.Srv().Store.Token().Delete(token.Token); err != nil {
+		return nil, model.NewAppError(where, "app.webauthn.delete_challenge.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if model.GetMillis()-token.CreateAt > rp.Timeout.Milliseconds() {
+		return nil, model.NewAppError(where, "app.webauthn.invalid_challenge.app_error", nil, "expired", http.StatusBadRequest)
+	}
+
+	var ceremony webAuthnCeremony
+	if err := json.Unmarshal([]byte(token.Extra), &ceremony); err != nil {
+		return nil, model.NewAppError(where, "app.webauthn.invalid_challenge.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+	return &ceremony, nil
+}
+
+func webAuthnDescriptors(credentials []*model.WebAuthnCredential) []webauthn.CredentialDescriptor {
+	descriptors := make([]webauthn.CredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptors = append(descriptors, credential.Descriptor())
+	}
+	return descriptors
+}
+
+// BeginWebAuthnRegistration returns the options with which an authenticator creates a new
+// credential for the user.
+func (a *App) BeginWebAuthnRegistration(userID string) (*webauthn.CreationOptions, *model.AppError) {
+	rp, appErr := a.webAuthnRelyingParty("BeginWebAuthnRegistration")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	user, appErr := a.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if user.IsBot || user.IsSSOUser() && !user.IsLDAPUser() {
+		return nil, model.NewAppError("BeginWebAuthnRegistration", "api.user.activate_mfa.email_and_ldap_only.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	credentials, appErr := a.GetWebAuthnCredentialsForUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if len(credentials) >= model.WebAuthnCredentialsPerUserMax {
+		return nil, model.NewAppError("BeginWebAuthnRegistration", "app.webauthn.register.too_many.app_error", map[string]interface{}{"Max": model.WebAuthnCredentialsPerUserMax}, "", http.StatusBadRequest)
+	}
+
+	challenge, appErr := a.newWebAuthnChallenge("BeginWebAuthnRegistration", TokenTypeWebAuthnRegistration, userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	displayName := user.GetFullName()
+	if displayName == "" {
+		displayName = user.Username
+	}
+	userEntity := webauthn.UserEntity{
+		Id:          webauthn.EncodeBase64([]byte(user.Id)),
+		Name:        user.Username,
+		DisplayName: displayName,
+	}
+
+	return rp.NewCreationOptions(challenge, userEntity, webAuthnDescriptors(credentials)), nil
+}
+
+// FinishWebAuthnRegistration verifies the credential created by an authenticator for the
+// options returned by BeginWebAuthnRegistration, and registers it for the user.
+func (a *App) FinishWebAuthnRegistration(userID string, registration *model.WebAuthnRegistrationRequest) (*model.WebAuthnCredential, *model.AppError) {
+	rp, appErr := a.webAuthnRelyingParty("FinishWebAuthnRegistration")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	challenge, err := registration.Credential.Challenge()
+	if err != nil {
+		return nil, model.NewAppError("FinishWebAuthnRegistration", "app.webauthn.invalid_challenge.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	ceremony, appErr := a.completeWebAuthnChallenge("FinishWebAuthnRegistration", TokenTypeWebAuthnRegistration, challenge, rp)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if ceremony.UserId != userID {
+		return nil, model.NewAppError("FinishWebAuthnRegistration", "app.webauthn.invalid_challenge.app_error", nil, "user_id="+userID, http.StatusBadRequest)
+	}
+
+	verified, err := rp.VerifyRegistration(challenge, registration.Credential, true)
+	if err != nil {
+		return nil, model.NewAppError("FinishWebAuthnRegistration", "app.webauthn.register.verify.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	credential, err := a.Srv().Store.WebAuthnCredential().Save(model.NewWebAuthnCredential(userID, registration.Name, verified))
+	if err != nil {
+		return nil, webAuthnStoreError("FinishWebAuthnRegistration", "app.webauthn.save.app_error", err)
+	}
+
+	return credential, nil
+}
+
+func (a *App) GetWebAuthnCredentialsForUser(userID string) ([]*model.WebAuthnCredential, *model.AppError) {
+	credentials, err := a.Srv().Store.WebAuthnCredential().GetForUser(userID)
+	if err != nil {
+		return nil, model.NewAppError("GetWebAuthnCredentialsForUser", "app.webauthn.get_for_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return credentials, nil
+}
+
+func (a *App) GetWebAuthnCredential(credentialID string) (*model.WebAuthnCredential, *model.AppError) {
+	credential, err := a.Srv().Store.WebAuthnCredential().Get(credentialID)
+	if err != nil {
+		return nil, webAuthnStoreError("GetWebAuthnCredential", "app.webauthn.get.app_error", err)
+	}
+	return credential, nil
+}
+
+func (a *App) PatchWebAuthnCredential(credential *model.WebAuthnCredential, patch *model.WebAuthnCredentialPatch) (*model.WebAuthnCredential, *model.AppError) {
+	credential.Patch(patch)
+
+	updated, err := a.Srv().Store.WebAuthnCredential().Update(credential)
+	if err != nil {
+		return nil, webAuthnStoreError("PatchWebAuthnCredential", "app.webauthn.update.app_error", err)
+	}
+	return updated, nil
+}
+
+func (a *App) DeleteWebAuthnCredential(credentialID string) *model.AppError {
+	if err := a.Srv().Store.WebAuthnCredential().Delete(credentialID); err != nil {
+		return webAuthnStoreError("DeleteWebAuthnCredential", "app.webauthn.delete.app_error", err)
+	}
+	return nil
+}
+
+// HasWebAuthnCredentials tells whether the user registered a credential with which to
+// sign in, which is then the second factor of the user.
+func (a *App) HasWebAuthnCredentials(userID string) (bool, *model.AppError) {
+	if !*a.Config().ServiceSettings.EnableWebAuthn {
+		return false, nil
+	}
+
+	count, err := a.Srv().Store.WebAuthnCredential().CountForUser(userID)
+	if err != nil {
+		return false, model.NewAppError("HasWebAuthnCredentials", "app.webauthn.get_for_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return count > 0, nil
+}
+
+// BeginWebAuthnLogin returns the options with which an authenticator makes an assertion to
+// log in. When the login id of a user is given, only the credentials of the user are
+// allowed, otherwise the user picks a discoverable credential.
+func (a *App) BeginWebAuthnLogin(loginID string) (*webauthn.RequestOptions, *model.AppError) {
+	rp, appErr := a.webAuthnRelyingParty("BeginWebAuthnLogin")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	userID := ""
+	var allowed []webauthn.CredentialDescriptor
+	if loginID != "" {
+		// Whether the user exists is not disclosed, the user is simply asked for any
+		// credential when unknown.
+		if user, appErr := a.GetUserForLogin("", loginID); appErr == nil {
+			credentials, appErr := a.GetWebAuthnCredentialsForUser(user.Id)
+			if appErr != nil {
+				return nil, appErr
+			}
+			if len(credentials) > 0 {
+				userID = user.Id
+				allowed = webAuthnDescriptors(credentials)
+			}
+		}
+	}
+
+	challenge, appErr := a.newWebAuthnChallenge("BeginWebAuthnLogin", TokenTypeWebAuthnLogin, userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return rp.NewRequestOptions(challenge, allowed, webauthn.UserVerificationRequired), nil
+}
+
+// AuthenticateUserForWebAuthnLogin verifies the assertion made by an authenticator for the
+// options returned by BeginWebAuthnLogin, and returns the user owning the credential. As
+// the authenticator verified the user, the assertion satisfies the multi-factor
+// authentication on its own.
+func (a *App) AuthenticateUserForWebAuthnLogin(login *model.WebAuthnLoginRequest) (*model.User, *model.AppError) {
+	rp, appErr := a.webAuthnRelyingParty("AuthenticateUserForWebAuthnLogin")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	challenge, err := login.Credential.Challenge()
+	if err != nil {
+		return nil, model.NewAppError("AuthenticateUserForWebAuthnLogin", "app.webauthn.invalid_challenge.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	ceremony, appErr := a.completeWebAuthnChallenge("AuthenticateUserForWebAuthnLogin", TokenTypeWebAuthnLogin, challenge, rp)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	credentialID, err := login.Credential.CredentialId()
+	if err != nil {
+		return nil, model.NewAppError("AuthenticateUserForWebAuthnLogin", "app.webauthn.login.invalid_credential.app_error", nil, err.Error(), http.StatusUnauthorized)
+	}
+	stored, err := a.Srv().Store.WebAuthnCredential().GetByCredentialId(webauthn.EncodeBase64(credentialID))
+	if err != nil {
+		var nfErr *store.ErrNotFound
+		if errors.As(err, &nfErr) {
+			return nil, model.NewAppError("AuthenticateUserForWebAuthnLogin", "app.webauthn.login.invalid_credential.app_error", nil, nfErr.Error(), http.StatusUnauthorized)
+		}
+		return nil, model.NewAppError("AuthenticateUserForWebAuthnLogin", "app.webauthn.get.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if ceremony.UserId != "" && ceremony.UserId != stored.UserId {
+		return nil, model.NewAppError("AuthenticateUserForWebAuthnLogin", "app.webauthn.login.invalid_credential.app_error", nil, "credential of another user", http.StatusUnauthorized)
+	}
+	if userHandle, err := login.Credential.UserHandle(); err != nil || len(userHandle) > 0 && string(userHandle) != stored.UserId {
+		return nil, model.NewAppError("AuthenticateUserForWebAuthnLogin", "app.webauthn.login.invalid_credential.app_error", nil, "user handle mismatch", http.StatusUnauthorized)
+	}
+
+	user, appErr := a.GetUser(stored.UserId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr := a.CheckUserPreflightAuthenticationCriteria(user, ""); appErr != nil {
+		return nil, appErr
+	}
+
+	credential, err := stored.ToCredential()
+	if err != nil {
+		return nil, model.NewAppError("AuthenticateUserForWebAuthnLogin", "app.webauthn.login.invalid_credential.app_error", nil, err.Error(), http.StatusUnauthorized)
+	}
+
+	signCount, err := rp.VerifyAssertion(challenge, login.Credential, credential, true)
+	if err != nil {
+		if err == webauthn.ErrSignCount {
+			mlog.Warn("The signature counter of a WebAuthn credential went backwards, the authenticator may have been cloned", mlog.String("user_id", user.Id), mlog.String("credential_id", stored.Id))
+		}
+		return nil, model.NewAppError("AuthenticateUserForWebAuthnLogin", "app.webauthn.login.verify.app_error", nil, err.Error(), http.StatusUnauthorized)
+	}
+
+	if err := a.Srv().Store.WebAuthnCredential().UpdateSignCount(stored.Id, int64(signCount), model.GetMillis()); err != nil {
+		return nil, webAuthnStoreError("AuthenticateUserForWebAuthnLogin", "app.webauthn.update.app_error", err)
+	}
+
+	if appErr := a.CheckUserPostflightAuthenticationCriteria(user); appErr != nil {
+		return nil, appErr
+	}
+
+	return user, nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package app
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestZulipConvertToBulkImport(t *testing.T) {
+	th := Setup(t).InitBasic()
+	defer th.TearDown()
+
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for name, content := range map[string]string{
+		"export/realm.json": `{
+			"zerver_userprofile": [
+				{"id": 1, "email": "zulip.alice@example.com", "full_name": "Alice", "is_active": true},
+				{"id": 2, "email": "` + th.BasicUser2.Email + `", "full_name": "Bob", "is_active": true},
+				{"id": 3, "email": "zulip-bot@example.com", "full_name": "Bot", "is_active": true, "is_bot": true, "bot_owner": 1}
+			],
+			"zerver_stream": [{"id": 10, "name": "Zulip General", "description": "Talk"}],
+			"zerver_recipient": [{"id": 100, "type": 2, "type_id": 10}, {"id": 101, "type": 1, "type_id": 2}],
+			"zerver_subscription": [
+				{"user_profile": 1, "recipient": 100, "active": true},
+				{"user_profile": 2, "recipient": 100, "active": true},
+				{"user_profile": 3, "recipient": 100, "active": true}
+			],
+			"zerver_reaction": [{"user_profile": 2, "message": 1000, "emoji_name": "smile"}],
+			"zerver_attachment": [{"id": 1, "path_id": "1/ab/file.txt", "messages": [1002]}]
+		}`,
+		"export/messages-000001.json": `{"zerver_message": [
+			{"id": 1000, "sender": 1, "recipient": 100, "subject": "Plans", "content": "Root", "date_sent": 1609459200},
+			{"id": 1001, "sender": 2, "recipient": 100, "subject": "Plans", "content": "Reply", "date_sent": 1609459201},
+			{"id": 1002, "sender": 3, "recipient": 100, "subject": "Files", "content": "File", "date_sent": 1609459202},
+			{"id": 1003, "sender": 1, "recipient": 101, "subject": "", "content": "Direct", "date_sent": 1609459203}
+		]}`,
+		"export/uploads/1/ab/file.txt": "content",
+	} {
+		w, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	zipReader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	data := &bytes.Buffer{}
+	lines, appErr := th.App.ZulipConvertToBulkImport(zipReader, data, th.BasicTeam.Id)
+	require.Nil(t, appErr)
+	require.Equal(t, 9, lines)
+
+	var failedLines []int
+	appErr, _ = th.App.BulkImportWithOpts(th.Context, data, zipReader, model.BulkImportOpts{
+		Workers:     1,
+		OnLineError: func(lineNumber int, err *model.AppError) { failedLines = append(failedLines, lineNumber) },
+	})
+	require.Nil(t, appErr)
+	require.Empty(t, failedLines)
+
+	alice, appErr := th.App.GetUserByUsername("zulip.alice")
+	require.Nil(t, appErr)
+
+	channel, appErr := th.App.GetChannelByName("zulip-general", th.BasicTeam.Id, false)
+	require.Nil(t, appErr)
+	assert.Equal(t, "Talk", channel.Purpose)
+	_, appErr = th.App.GetChannelMember(context.Background(), channel.Id, th.BasicUser2.Id)
+	require.Nil(t, appErr, "the Zulip user should be merged with the user sharing their email")
+
+	posts, appErr := th.App.GetPostsPage(model.GetPostsOptions{ChannelId: channel.Id, PerPage: 10})
+	require.Nil(t, appErr)
+	require.Len(t, posts.Order, 3)
+
+	root := posts.Posts[posts.Order[2]]
+	assert.Equal(t, "**Plans**\nRoot", root.Message)
+	assert.Equal(t, alice.Id, root.UserId)
+
+	reply := posts.Posts[posts.Order[1]]
+	assert.Equal(t, root.Id, reply.RootId)
+	assert.Equal(t, th.BasicUser2.Id, reply.UserId)
+
+	reactions, appErr := th.App.GetReactionsForPost(root.Id)
+	require.Nil(t, appErr)
+	require.Len(t, reactions, 1)
+
+	file := posts.Posts[posts.Order[0]]
+	assert.Len(t, file.FileIds, 1)
+	bot, appErr := th.App.GetUser(file.UserId)
+	require.Nil(t, appErr)
+	assert.True(t, bot.IsBot)
+}
@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/cjdelisle/matterfoss-server/v6/app"
 	"github.com/cjdelisle/matterfoss-server/v6/audit"
 	"github.com/cjdelisle/matterfoss-server/v6/model"
 
@@ -77,6 +79,9 @@ func init() {
 	BulkExportCmd.Flags().Bool("all-teams", true, "Export all teams from the server.")
 	BulkExportCmd.Flags().Bool("attachments", false, "Also export file attachments.")
 	BulkExportCmd.Flags().Bool("archive", false, "Outputs a single archive file.")
+	BulkExportCmd.Flags().StringSlice("team", nil, "Only export these teams, by name or ID. Direct messages are left out.")
+	BulkExportCmd.Flags().StringSlice("channel", nil, "Only export these channels, in the form team:channel. Direct messages are left out.")
+	BulkExportCmd.Flags().Int64("since", 0, "Only export the posts created or edited since this timestamp, expressed in seconds since the unix epoch.")
 
 	ExportCmd.AddCommand(ScheduleExportCmd)
 	ExportCmd.AddCommand(CsvExportCmd)
@@ -201,10 +206,46 @@ func bulkExportCmdF(command *cobra.Command, args []string) error {
 	if err != nil {
 		return errors.Wrap(err, "all-teams flag error")
 	}
-	if !allTeams {
-		return errors.New("Nothing to export. Please specify the --all-teams flag to export all teams.")
+
+	teamArgs, err := command.Flags().GetStringSlice("team")
+	if err != nil {
+		return errors.Wrap(err, "team flag error")
+	}
+
+	channelArgs, err := command.Flags().GetStringSlice("channel")
+	if err != nil {
+		return errors.Wrap(err, "channel flag error")
 	}
 
+	since, err := command.Flags().GetInt64("since")
+	if err != nil {
+		return errors.Wrap(err, "since flag error")
+	}
+	if since < 0 {
+		return errors.New("since must be a positive timestamp")
+	}
+
+	if !allTeams && len(teamArgs) == 0 && len(channelArgs) == 0 {
+		return errors.New("Nothing to export. Please specify the --all-teams flag to export all teams, or the teams and channels to export.")
+	}
+
+	var opts model.BulkExportOpts
+	for _, teamArg := range teamArgs {
+		team := getTeamFromTeamArg(a, teamArg)
+		if team == nil {
+			return errors.Errorf("Unable to find team '%s'", teamArg)
+		}
+		opts.TeamIds = append(opts.TeamIds, team.Id)
+	}
+	for _, channelArg := range channelArgs {
+		channel := getChannelFromChannelArg(a, channelArg)
+		if channel == nil {
+			return errors.Errorf("Unable to find channel '%s'", channelArg)
+		}
+		opts.ChannelIds = append(opts.ChannelIds, channel.Id)
+	}
+	opts.Since = since * 1000
+
 	attachments, err := command.Flags().GetBool("attachments")
 	if err != nil {
 		return errors.Wrap(err, "attachments flag error")
@@ -226,7 +267,6 @@ func bulkExportCmdF(command *cobra.Command, args []string) error {
 		return err
 	}
 
-	var opts model.BulkExportOpts
 	opts.IncludeAttachments = attachments
 	opts.CreateArchive = archive
 	if err := a.BulkExport(fileWriter, filepath.Dir(outPath), opts); err != nil {
@@ -236,8 +276,33 @@ func bulkExportCmdF(command *cobra.Command, args []string) error {
 
 	auditRec := a.MakeAuditRecord("bulkExport", audit.Success)
 	auditRec.AddMeta("all_teams", allTeams)
+	auditRec.AddMeta("teams", teamArgs)
+	auditRec.AddMeta("channels", channelArgs)
+	auditRec.AddMeta("since", since)
 	auditRec.AddMeta("file", args[0])
 	a.LogAuditRec(auditRec, nil)
 
 	return nil
 }
+
+// getChannelFromChannelArg finds a channel given in the form team:channel, where
+// both the team and the channel can be a name or an ID.
+func getChannelFromChannelArg(a *app.App, channelArg string) *model.Channel {
+	result := strings.SplitN(channelArg, ":", 2)
+	if len(result) != 2 {
+		return nil
+	}
+
+	team := getTeamFromTeamArg(a, result[0])
+	if team == nil {
+		return nil
+	}
+
+	channel, err := a.Srv().Store.Channel().GetByName(team.Id, result[1], false)
+	if err != nil {
+		if channel, err = a.Srv().Store.Channel().Get(result[1], false); err != nil || channel.TeamId != team.Id {
+			return nil
+		}
+	}
+	return channel
+}
@@ -23,6 +23,9 @@ import (
 	_ "github.com/cjdelisle/matterfoss-server/v6/app/slashcommands"
 	// Plugins
 	_ "github.com/cjdelisle/matterfoss-server/v6/model/gitlab"
+	_ "github.com/cjdelisle/matterfoss-server/v6/model/google"
+	_ "github.com/cjdelisle/matterfoss-server/v6/model/office365"
+	_ "github.com/cjdelisle/matterfoss-server/v6/model/openid"
 	// Enterprise Imports
 	_ "github.com/cjdelisle/matterfoss-server/v6/imports"
 )
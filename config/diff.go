@@ -38,6 +38,7 @@ var configSensitivePaths = map[string]bool{
 	"ServiceSettings.GfycatAPISecret":                        true,
 	"ServiceSettings.SplitKey":                               true,
 	"PluginSettings.Plugins":                                 true,
+	"CacheSettings.RedisPassword":                            true,
 }
 
 // Sanitize replaces sensitive config values in the diff with asterisks filled strings.
@@ -93,6 +93,10 @@ func desanitize(actual, target *model.Config) {
 	if *target.ServiceSettings.SplitKey == model.FakeSetting {
 		*target.ServiceSettings.SplitKey = *actual.ServiceSettings.SplitKey
 	}
+
+	if target.CacheSettings.RedisPassword != nil && *target.CacheSettings.RedisPassword == model.FakeSetting {
+		*target.CacheSettings.RedisPassword = *actual.CacheSettings.RedisPassword
+	}
 }
 
 // fixConfig patches invalid or missing data in the configuration.
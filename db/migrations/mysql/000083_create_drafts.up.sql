@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS Drafts (
+    CreateAt bigint(20) DEFAULT NULL,
+    UpdateAt bigint(20) DEFAULT NULL,
+    UserId varchar(26) NOT NULL,
+    ChannelId varchar(26) NOT NULL,
+    RootId varchar(26) NOT NULL DEFAULT '',
+    Message text,
+    Props text,
+    FileIds text,
+    PRIMARY KEY (UserId, ChannelId, RootId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
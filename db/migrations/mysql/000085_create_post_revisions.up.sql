@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS PostRevisions (
+    Id varchar(26) NOT NULL,
+    PostId varchar(26) NOT NULL,
+    ChannelId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    EditorId varchar(26) NOT NULL DEFAULT '',
+    CreateAt bigint(20) NOT NULL,
+    Message text,
+    Props text,
+    FileIds text,
+    PRIMARY KEY (Id),
+    KEY idx_postrevisions_postid_createat (PostId, CreateAt),
+    KEY idx_postrevisions_channelid_createat (ChannelId, CreateAt),
+    KEY idx_postrevisions_createat_id (CreateAt, Id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS Reminders (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    PostId varchar(26) NOT NULL DEFAULT '',
+    Message text,
+    CreateAt bigint(20) NOT NULL,
+    TargetAt bigint(20) NOT NULL,
+    FiredAt bigint(20) NOT NULL DEFAULT 0,
+    PRIMARY KEY (Id),
+    KEY idx_reminders_userid_targetat (UserId, TargetAt),
+    KEY idx_reminders_firedat_targetat_id (FiredAt, TargetAt, Id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
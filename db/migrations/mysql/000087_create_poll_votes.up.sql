@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS PollVotes (
+    PostId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    OptionIndex int NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    PRIMARY KEY (PostId, UserId, OptionIndex),
+    KEY idx_pollvotes_userid (UserId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS ChannelBookmarks (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint(20) NOT NULL,
+    UpdateAt bigint(20) NOT NULL,
+    DeleteAt bigint(20) NOT NULL DEFAULT 0,
+    ChannelId varchar(26) NOT NULL,
+    OwnerId varchar(26) NOT NULL,
+    FileId varchar(26) NOT NULL DEFAULT '',
+    DisplayName varchar(64) NOT NULL,
+    SortOrder bigint(20) NOT NULL DEFAULT 0,
+    LinkUrl text,
+    ImageUrl text,
+    Emoji varchar(64) NOT NULL DEFAULT '',
+    Type varchar(26) NOT NULL,
+    RemoteId varchar(26),
+    PRIMARY KEY (Id),
+    KEY idx_channelbookmarks_channelid_updateat (ChannelId, UpdateAt),
+    KEY idx_channelbookmarks_fileid (FileId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS WebAuthnCredentials (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    Name varchar(64) NOT NULL,
+    CredentialId varchar(1400) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+    PublicKey text NOT NULL,
+    SignCount bigint(20) NOT NULL DEFAULT 0,
+    AAGUID varchar(32) NOT NULL DEFAULT '',
+    Transports varchar(256) NOT NULL DEFAULT '',
+    CreateAt bigint(20) NOT NULL,
+    UpdateAt bigint(20) NOT NULL,
+    LastUsedAt bigint(20) NOT NULL DEFAULT 0,
+    PRIMARY KEY (Id),
+    UNIQUE KEY idx_webauthncredentials_credentialid (CredentialId),
+    KEY idx_webauthncredentials_userid (UserId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
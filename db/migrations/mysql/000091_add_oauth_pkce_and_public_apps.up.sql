@@ -0,0 +1,44 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'OAuthAuthData'
+        AND table_schema = DATABASE()
+        AND column_name = 'CodeChallenge'
+    ) > 0,
+	'SELECT 1',
+    'ALTER TABLE OAuthAuthData ADD COLUMN CodeChallenge varchar(128) NOT NULL DEFAULT "";'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'OAuthAuthData'
+        AND table_schema = DATABASE()
+        AND column_name = 'CodeChallengeMethod'
+    ) > 0,
+	'SELECT 1',
+    'ALTER TABLE OAuthAuthData ADD COLUMN CodeChallengeMethod varchar(32) NOT NULL DEFAULT "";'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
+
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'OAuthApps'
+        AND table_schema = DATABASE()
+        AND column_name = 'IsPublic'
+    ) > 0,
+	'SELECT 1',
+    'ALTER TABLE OAuthApps ADD COLUMN IsPublic boolean NOT NULL DEFAULT false;'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
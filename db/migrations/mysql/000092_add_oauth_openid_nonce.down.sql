@@ -0,0 +1,14 @@
+SET @preparedStatement = (SELECT IF(
+    (
+        SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+        WHERE table_name = 'OAuthAuthData'
+        AND table_schema = DATABASE()
+        AND column_name = 'Nonce'
+    ) > 0,
+    'ALTER TABLE OAuthAuthData DROP COLUMN Nonce;',
+    'SELECT 1'
+));
+
+PREPARE alterIfExists FROM @preparedStatement;
+EXECUTE alterIfExists;
+DEALLOCATE PREPARE alterIfExists;
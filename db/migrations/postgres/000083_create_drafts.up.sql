@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS drafts (
+    createat bigint,
+    updateat bigint,
+    userid VARCHAR(26) NOT NULL,
+    channelid VARCHAR(26) NOT NULL,
+    rootid VARCHAR(26) NOT NULL DEFAULT '',
+    message VARCHAR(65535),
+    props VARCHAR(800000),
+    fileids VARCHAR(300),
+    PRIMARY KEY (userid, channelid, rootid)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS scheduledposts (
+    id VARCHAR(26) PRIMARY KEY,
+    createat bigint,
+    updateat bigint,
+    userid VARCHAR(26) NOT NULL,
+    channelid VARCHAR(26) NOT NULL,
+    rootid VARCHAR(26) NOT NULL DEFAULT '',
+    message VARCHAR(65535),
+    props VARCHAR(800000),
+    fileids VARCHAR(300),
+    scheduledat bigint NOT NULL,
+    processedat bigint NOT NULL DEFAULT 0,
+    errorcode VARCHAR(200) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduledposts_userid_channelid_scheduledat ON scheduledposts (userid, channelid, scheduledat);
+CREATE INDEX IF NOT EXISTS idx_scheduledposts_processedat_scheduledat_id ON scheduledposts (processedat, scheduledat, id);
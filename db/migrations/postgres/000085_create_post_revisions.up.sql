@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS postrevisions (
+    id VARCHAR(26) PRIMARY KEY,
+    postid VARCHAR(26) NOT NULL,
+    channelid VARCHAR(26) NOT NULL,
+    userid VARCHAR(26) NOT NULL,
+    editorid VARCHAR(26) NOT NULL DEFAULT '',
+    createat bigint NOT NULL,
+    message VARCHAR(65535),
+    props VARCHAR(800000),
+    fileids VARCHAR(300)
+);
+
+CREATE INDEX IF NOT EXISTS idx_postrevisions_postid_createat ON postrevisions (postid, createat);
+CREATE INDEX IF NOT EXISTS idx_postrevisions_channelid_createat ON postrevisions (channelid, createat);
+CREATE INDEX IF NOT EXISTS idx_postrevisions_createat_id ON postrevisions (createat, id);
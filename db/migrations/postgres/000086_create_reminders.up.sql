@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS reminders (
+    id VARCHAR(26) PRIMARY KEY,
+    userid VARCHAR(26) NOT NULL,
+    postid VARCHAR(26) NOT NULL DEFAULT '',
+    message VARCHAR(4000),
+    createat bigint NOT NULL,
+    targetat bigint NOT NULL,
+    firedat bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminders_userid_targetat ON reminders (userid, targetat);
+CREATE INDEX IF NOT EXISTS idx_reminders_firedat_targetat_id ON reminders (firedat, targetat, id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS pollvotes (
+    postid VARCHAR(26) NOT NULL,
+    userid VARCHAR(26) NOT NULL,
+    optionindex integer NOT NULL,
+    createat bigint NOT NULL,
+    PRIMARY KEY (postid, userid, optionindex)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pollvotes_userid ON pollvotes (userid);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS channelbookmarks (
+    id VARCHAR(26) PRIMARY KEY,
+    createat bigint NOT NULL,
+    updateat bigint NOT NULL,
+    deleteat bigint NOT NULL DEFAULT 0,
+    channelid VARCHAR(26) NOT NULL,
+    ownerid VARCHAR(26) NOT NULL,
+    fileid VARCHAR(26) NOT NULL DEFAULT '',
+    displayname VARCHAR(64) NOT NULL,
+    sortorder bigint NOT NULL DEFAULT 0,
+    linkurl VARCHAR(1024),
+    imageurl VARCHAR(1024),
+    emoji VARCHAR(64) NOT NULL DEFAULT '',
+    type VARCHAR(26) NOT NULL,
+    remoteid VARCHAR(26)
+);
+
+CREATE INDEX IF NOT EXISTS idx_channelbookmarks_channelid_updateat ON channelbookmarks (channelid, updateat);
+CREATE INDEX IF NOT EXISTS idx_channelbookmarks_fileid ON channelbookmarks (fileid);
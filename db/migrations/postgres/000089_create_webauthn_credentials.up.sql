@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS webauthncredentials (
+    id VARCHAR(26) PRIMARY KEY,
+    userid VARCHAR(26) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    credentialid VARCHAR(1400) NOT NULL,
+    publickey text NOT NULL,
+    signcount bigint NOT NULL DEFAULT 0,
+    aaguid VARCHAR(32) NOT NULL DEFAULT '',
+    transports VARCHAR(256) NOT NULL DEFAULT '',
+    createat bigint NOT NULL,
+    updateat bigint NOT NULL,
+    lastusedat bigint NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webauthncredentials_credentialid ON webauthncredentials (credentialid);
+CREATE INDEX IF NOT EXISTS idx_webauthncredentials_userid ON webauthncredentials (userid);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS mfarecoverycodes (
+    id VARCHAR(26) PRIMARY KEY,
+    userid VARCHAR(26) NOT NULL,
+    codehash VARCHAR(64) NOT NULL,
+    createat bigint NOT NULL,
+    usedat bigint NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mfarecoverycodes_userid_codehash ON mfarecoverycodes (userid, codehash);
@@ -0,0 +1,3 @@
+ALTER TABLE OAuthApps DROP COLUMN IF EXISTS IsPublic;
+ALTER TABLE OAuthAuthData DROP COLUMN IF EXISTS CodeChallengeMethod;
+ALTER TABLE OAuthAuthData DROP COLUMN IF EXISTS CodeChallenge;
@@ -0,0 +1,3 @@
+ALTER TABLE OAuthAuthData ADD COLUMN IF NOT EXISTS CodeChallenge varchar(128) NOT NULL DEFAULT '';
+ALTER TABLE OAuthAuthData ADD COLUMN IF NOT EXISTS CodeChallengeMethod varchar(32) NOT NULL DEFAULT '';
+ALTER TABLE OAuthApps ADD COLUMN IF NOT EXISTS IsPublic boolean NOT NULL DEFAULT false;
@@ -0,0 +1 @@
+ALTER TABLE OAuthAuthData ADD COLUMN IF NOT EXISTS Nonce varchar(256) NOT NULL DEFAULT '';
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS Teams (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    DisplayName varchar(64),
+    Name varchar(64),
+    Description varchar(255),
+    Email varchar(128),
+    Type varchar(255),
+    CompanyName varchar(64),
+    AllowedDomains text,
+    InviteId varchar(32),
+    SchemeId varchar(26),
+    AllowOpenInvite boolean,
+    LastTeamIconUpdate bigint,
+    GroupConstrained boolean,
+    PRIMARY KEY (Id),
+    UNIQUE (Name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_teams_invite_id ON Teams (InviteId);
+CREATE INDEX IF NOT EXISTS idx_teams_update_at ON Teams (UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_teams_create_at ON Teams (CreateAt);
+CREATE INDEX IF NOT EXISTS idx_teams_delete_at ON Teams (DeleteAt);
+CREATE INDEX IF NOT EXISTS idx_teams_scheme_id ON Teams (SchemeId);
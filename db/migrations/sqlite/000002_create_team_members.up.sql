@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS TeamMembers (
+    TeamId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    Roles varchar(64),
+    DeleteAt bigint,
+    SchemeUser boolean,
+    SchemeAdmin boolean,
+    SchemeGuest boolean,
+    PRIMARY KEY (TeamId, UserId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_teammembers_user_id ON TeamMembers (UserId);
+CREATE INDEX IF NOT EXISTS idx_teammembers_delete_at ON TeamMembers (DeleteAt);
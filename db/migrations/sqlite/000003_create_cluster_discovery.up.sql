@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS ClusterDiscovery (
+    Id varchar(26) NOT NULL,
+    Type varchar(64),
+    ClusterName varchar(64),
+    Hostname text,
+    GossipPort integer,
+    Port integer,
+    CreateAt bigint,
+    LastPingAt bigint,
+    PRIMARY KEY (Id)
+);
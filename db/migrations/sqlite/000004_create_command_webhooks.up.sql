@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS CommandWebhooks (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    CommandId varchar(26),
+    UserId varchar(26),
+    ChannelId varchar(26),
+    RootId varchar(26),
+    UseCount integer,
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_command_webhook_create_at ON CommandWebhooks (CreateAt);
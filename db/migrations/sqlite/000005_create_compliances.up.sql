@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS Compliances (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    UserId varchar(26),
+    Status varchar(64),
+    Count integer,
+    `Desc` text,
+    Type varchar(64),
+    StartAt bigint,
+    EndAt bigint,
+    Keywords text,
+    Emails text,
+    PRIMARY KEY (Id)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS Emoji (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    CreatorId varchar(26),
+    Name varchar(64),
+    PRIMARY KEY (Id),
+    UNIQUE (Name, DeleteAt)
+);
+
+CREATE INDEX IF NOT EXISTS idx_emoji_update_at ON Emoji (UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_emoji_create_at ON Emoji (CreateAt);
+CREATE INDEX IF NOT EXISTS idx_emoji_delete_at ON Emoji (DeleteAt);
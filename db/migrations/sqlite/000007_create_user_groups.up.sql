@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS UserGroups (
+    Id varchar(26) NOT NULL,
+    Name varchar(64),
+    DisplayName varchar(128),
+    Description text,
+    Source varchar(64),
+    RemoteId varchar(48),
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    AllowReference boolean,
+    PRIMARY KEY (Id),
+    UNIQUE (Name),
+    UNIQUE (Source, RemoteId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usergroups_remote_id ON UserGroups (RemoteId);
+CREATE INDEX IF NOT EXISTS idx_usergroups_delete_at ON UserGroups (DeleteAt);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS GroupMembers (
+    GroupId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    CreateAt bigint,
+    DeleteAt bigint,
+    PRIMARY KEY (GroupId, UserId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_groupmembers_create_at ON GroupMembers (CreateAt);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS GroupTeams (
+    GroupId varchar(26) NOT NULL,
+    AutoAdd boolean,
+    SchemeAdmin boolean,
+    CreateAt bigint,
+    DeleteAt bigint,
+    UpdateAt bigint,
+    TeamId varchar(26) NOT NULL,
+    PRIMARY KEY (GroupId, TeamId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_groupteams_schemeadmin ON GroupTeams (SchemeAdmin);
+CREATE INDEX IF NOT EXISTS idx_groupteams_teamid ON GroupTeams (TeamId);
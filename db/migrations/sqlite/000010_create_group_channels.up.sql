@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS GroupChannels (
+    GroupId varchar(26) NOT NULL,
+    AutoAdd boolean,
+    SchemeAdmin boolean,
+    CreateAt bigint,
+    DeleteAt bigint,
+    UpdateAt bigint,
+    ChannelId varchar(26) NOT NULL,
+    PRIMARY KEY (GroupId, ChannelId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_groupchannels_schemeadmin ON GroupChannels (SchemeAdmin);
+CREATE INDEX IF NOT EXISTS idx_groupchannels_channelid ON GroupChannels (ChannelId);
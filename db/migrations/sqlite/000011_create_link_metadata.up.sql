@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS LinkMetadata (
+    Hash bigint NOT NULL,
+    URL text,
+    Timestamp bigint,
+    Type varchar(16),
+    Data text,
+    PRIMARY KEY (Hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_link_metadata_url_timestamp ON LinkMetadata (URL, Timestamp);
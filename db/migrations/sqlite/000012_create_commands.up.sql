@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS Commands (
+    Id varchar(26) NOT NULL,
+    Token varchar(26),
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    CreatorId varchar(26),
+    TeamId varchar(26),
+    `Trigger` varchar(128),
+    Method varchar(1),
+    Username varchar(64),
+    IconURL text,
+    AutoComplete boolean,
+    AutoCompleteDesc text,
+    AutoCompleteHint text,
+    DisplayName varchar(64),
+    Description varchar(128),
+    URL text,
+    PluginId varchar(190),
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_command_team_id ON Commands (TeamId);
+CREATE INDEX IF NOT EXISTS idx_command_update_at ON Commands (UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_command_create_at ON Commands (CreateAt);
+CREATE INDEX IF NOT EXISTS idx_command_delete_at ON Commands (DeleteAt);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS IncomingWebhooks (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    UserId varchar(26),
+    ChannelId varchar(26),
+    TeamId varchar(26),
+    DisplayName varchar(64),
+    Description text,
+    Username varchar(255),
+    IconURL text,
+    ChannelLocked boolean,
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incoming_webhook_user_id ON IncomingWebhooks (UserId);
+CREATE INDEX IF NOT EXISTS idx_incoming_webhook_team_id ON IncomingWebhooks (TeamId);
+CREATE INDEX IF NOT EXISTS idx_incoming_webhook_update_at ON IncomingWebhooks (UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_incoming_webhook_create_at ON IncomingWebhooks (CreateAt);
+CREATE INDEX IF NOT EXISTS idx_incoming_webhook_delete_at ON IncomingWebhooks (DeleteAt);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS OutgoingWebhooks (
+    Id varchar(26) NOT NULL,
+    Token varchar(26),
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    CreatorId varchar(26),
+    ChannelId varchar(26),
+    TeamId varchar(26),
+    TriggerWords text,
+    CallbackURLs text,
+    DisplayName varchar(64),
+    ContentType varchar(128),
+    TriggerWhen integer,
+    Username varchar(64),
+    IconURL text,
+    Description text,
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outgoing_webhook_team_id ON OutgoingWebhooks (TeamId);
+CREATE INDEX IF NOT EXISTS idx_outgoing_webhook_update_at ON OutgoingWebhooks (UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_outgoing_webhook_create_at ON OutgoingWebhooks (CreateAt);
+CREATE INDEX IF NOT EXISTS idx_outgoing_webhook_delete_at ON OutgoingWebhooks (DeleteAt);
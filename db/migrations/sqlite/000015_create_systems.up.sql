@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS Systems (
+    Name varchar(64) NOT NULL,
+    Value text,
+    PRIMARY KEY (Name)
+);
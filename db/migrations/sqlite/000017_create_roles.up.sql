@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS Roles (
+    Id varchar(26) NOT NULL,
+    Name varchar(64),
+    DisplayName varchar(128),
+    Description text,
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    Permissions text,
+    SchemeManaged boolean,
+    BuiltIn boolean,
+    PRIMARY KEY (Id),
+    UNIQUE (Name)
+);
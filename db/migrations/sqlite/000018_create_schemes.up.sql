@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS Schemes (
+    Id varchar(26) NOT NULL,
+    Name varchar(64),
+    DisplayName varchar(128),
+    Description text,
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    Scope varchar(32),
+    DefaultTeamAdminRole varchar(64),
+    DefaultTeamUserRole varchar(64),
+    DefaultChannelAdminRole varchar(64),
+    DefaultChannelUserRole varchar(64),
+    DefaultTeamGuestRole varchar(64),
+    DefaultChannelGuestRole varchar(64),
+    PRIMARY KEY (Id),
+    UNIQUE (Name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_schemes_channel_guest_role ON Schemes (DefaultChannelGuestRole);
+CREATE INDEX IF NOT EXISTS idx_schemes_channel_user_role ON Schemes (DefaultChannelUserRole);
+CREATE INDEX IF NOT EXISTS idx_schemes_channel_admin_role ON Schemes (DefaultChannelAdminRole);
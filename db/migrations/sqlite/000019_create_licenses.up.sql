@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS Licenses (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    Bytes text,
+    PRIMARY KEY (Id)
+);
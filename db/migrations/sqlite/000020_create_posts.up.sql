@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS Posts (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    UserId varchar(26),
+    ChannelId varchar(26),
+    RootId varchar(26),
+    OriginalId varchar(26),
+    Message text,
+    Type varchar(26),
+    Props text,
+    Hashtags text,
+    Filenames text,
+    FileIds text,
+    HasReactions boolean,
+    EditAt bigint,
+    IsPinned boolean,
+    RemoteId varchar(26),
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_posts_update_at ON Posts (UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_posts_create_at ON Posts (CreateAt);
+CREATE INDEX IF NOT EXISTS idx_posts_delete_at ON Posts (DeleteAt);
+CREATE INDEX IF NOT EXISTS idx_posts_user_id ON Posts (UserId);
+CREATE INDEX IF NOT EXISTS idx_posts_is_pinned ON Posts (IsPinned);
+CREATE INDEX IF NOT EXISTS idx_posts_channel_id_update_at ON Posts (ChannelId, UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_posts_channel_id_delete_at_create_at ON Posts (ChannelId, DeleteAt, CreateAt);
+CREATE INDEX IF NOT EXISTS idx_posts_root_id_delete_at ON Posts (RootId, DeleteAt);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS ProductNoticeViewState (
+    UserId varchar(26) NOT NULL,
+    NoticeId varchar(26) NOT NULL,
+    Viewed integer,
+    Timestamp bigint,
+    PRIMARY KEY (UserId, NoticeId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notice_views_timestamp ON ProductNoticeViewState (Timestamp);
+CREATE INDEX IF NOT EXISTS idx_notice_views_notice_id ON ProductNoticeViewState (NoticeId);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS Sessions (
+    Id varchar(26) NOT NULL,
+    Token varchar(26),
+    CreateAt bigint,
+    ExpiresAt bigint,
+    LastActivityAt bigint,
+    UserId varchar(26),
+    DeviceId text,
+    Roles varchar(64),
+    IsOAuth boolean,
+    Props text,
+    ExpiredNotify boolean,
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON Sessions (UserId);
+CREATE INDEX IF NOT EXISTS idx_sessions_token ON Sessions (Token);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON Sessions (ExpiresAt);
+CREATE INDEX IF NOT EXISTS idx_sessions_create_at ON Sessions (CreateAt);
+CREATE INDEX IF NOT EXISTS idx_sessions_last_activity_at ON Sessions (LastActivityAt);
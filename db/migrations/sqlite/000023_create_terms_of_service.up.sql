@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS TermsOfService (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    UserId varchar(26),
+    Text text,
+    PRIMARY KEY (Id)
+);
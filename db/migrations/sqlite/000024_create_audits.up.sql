@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS Audits (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    UserId varchar(26),
+    Action text,
+    ExtraInfo text,
+    IpAddress varchar(64),
+    SessionId varchar(26),
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_audits_user_id ON Audits (UserId);
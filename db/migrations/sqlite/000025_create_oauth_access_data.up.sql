@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS OAuthAccessData (
+    Token varchar(26) NOT NULL,
+    RefreshToken varchar(26),
+    RedirectUri text,
+    ClientId varchar(26),
+    UserId varchar(26),
+    ExpiresAt bigint,
+    Scope varchar(128),
+    PRIMARY KEY (Token),
+    UNIQUE (ClientId, UserId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauthaccessdata_user_id ON OAuthAccessData (UserId);
+CREATE INDEX IF NOT EXISTS idx_oauthaccessdata_refresh_token ON OAuthAccessData (RefreshToken);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS Preferences (
+    UserId varchar(26) NOT NULL,
+    Category varchar(32) NOT NULL,
+    Name varchar(32) NOT NULL,
+    Value text,
+    PRIMARY KEY (UserId, Category, Name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_preferences_category ON Preferences (Category);
+CREATE INDEX IF NOT EXISTS idx_preferences_name ON Preferences (Name);
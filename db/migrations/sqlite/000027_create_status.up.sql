@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS Status (
+    UserId varchar(26) NOT NULL,
+    Status varchar(32),
+    Manual boolean,
+    LastActivityAt bigint,
+    DNDEndTime bigint,
+    PrevStatus varchar(32),
+    PRIMARY KEY (UserId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_status_dndendtime ON Status (Status, DNDEndTime);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS Tokens (
+    Token varchar(64) NOT NULL,
+    CreateAt bigint,
+    Type varchar(64),
+    Extra text,
+    PRIMARY KEY (Token)
+);
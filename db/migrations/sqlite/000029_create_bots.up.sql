@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS Bots (
+    UserId varchar(26) NOT NULL,
+    Description text,
+    OwnerId varchar(190),
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    LastIconUpdate bigint,
+    PRIMARY KEY (UserId)
+);
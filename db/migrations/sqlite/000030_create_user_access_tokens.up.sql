@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS UserAccessTokens (
+    Id varchar(26) NOT NULL,
+    Token varchar(26),
+    UserId varchar(26),
+    Description text,
+    IsActive boolean,
+    PRIMARY KEY (Id),
+    UNIQUE (Token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_access_tokens_user_id ON UserAccessTokens (UserId);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS SharedChannels (
+    ChannelId varchar(26) NOT NULL,
+    TeamId varchar(26),
+    Home boolean,
+    ReadOnly boolean,
+    ShareName varchar(64),
+    ShareDisplayName varchar(64),
+    SharePurpose varchar(250),
+    ShareHeader text,
+    CreatorId varchar(26),
+    CreateAt bigint,
+    UpdateAt bigint,
+    RemoteId varchar(26),
+    PRIMARY KEY (ChannelId),
+    UNIQUE (ShareName, TeamId)
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS SidebarChannels (
+    ChannelId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    CategoryId varchar(128) NOT NULL,
+    SortOrder bigint,
+    PRIMARY KEY (ChannelId, UserId, CategoryId)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS OAuthAuthData (
+    ClientId varchar(26),
+    UserId varchar(26),
+    Code varchar(128) NOT NULL,
+    ExpiresIn integer,
+    CreateAt bigint,
+    RedirectUri text,
+    State text,
+    Scope varchar(128),
+    PRIMARY KEY (Code)
+);
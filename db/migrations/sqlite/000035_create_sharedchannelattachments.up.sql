@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS SharedChannelAttachments (
+    Id varchar(26) NOT NULL,
+    FileId varchar(26),
+    RemoteId varchar(26),
+    CreateAt bigint,
+    LastSyncAt bigint,
+    PRIMARY KEY (Id),
+    UNIQUE (FileId, RemoteId)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS SharedChannelUsers (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26),
+    RemoteId varchar(26),
+    CreateAt bigint,
+    LastSyncAt bigint,
+    ChannelId varchar(26),
+    PRIMARY KEY (Id),
+    UNIQUE (UserId, ChannelId, RemoteId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sharedchannelusers_remote_id ON SharedChannelUsers (RemoteId);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS SharedChannelRemotes (
+    Id varchar(26) NOT NULL,
+    ChannelId varchar(26) NOT NULL,
+    CreatorId varchar(26),
+    CreateAt bigint,
+    UpdateAt bigint,
+    IsInviteAccepted boolean,
+    IsInviteConfirmed boolean,
+    RemoteId varchar(26),
+    LastPostUpdateAt bigint,
+    LastPostId varchar(26),
+    PRIMARY KEY (Id, ChannelId),
+    UNIQUE (ChannelId, RemoteId)
+);
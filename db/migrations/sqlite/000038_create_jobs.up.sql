@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS Jobs (
+    Id varchar(26) NOT NULL,
+    Type varchar(32),
+    Priority bigint,
+    CreateAt bigint,
+    StartAt bigint,
+    LastActivityAt bigint,
+    Status varchar(32),
+    Progress bigint,
+    Data text,
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_type ON Jobs (Type);
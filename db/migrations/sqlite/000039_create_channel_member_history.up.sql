@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS ChannelMemberHistory (
+    ChannelId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    JoinTime bigint NOT NULL,
+    LeaveTime bigint,
+    PRIMARY KEY (ChannelId, UserId, JoinTime)
+);
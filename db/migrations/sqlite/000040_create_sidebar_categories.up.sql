@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS SidebarCategories (
+    Id varchar(128) NOT NULL,
+    UserId varchar(26),
+    TeamId varchar(26),
+    SortOrder bigint,
+    Sorting varchar(64),
+    Type varchar(64),
+    DisplayName varchar(64),
+    Muted boolean,
+    Collapsed boolean,
+    PRIMARY KEY (Id)
+);
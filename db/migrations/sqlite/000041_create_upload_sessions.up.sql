@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS UploadSessions (
+    Id varchar(26) NOT NULL,
+    Type varchar(32),
+    CreateAt bigint,
+    UserId varchar(26),
+    ChannelId varchar(26),
+    Filename text,
+    Path text,
+    FileSize bigint,
+    FileOffset bigint,
+    RemoteId varchar(26),
+    ReqFileId varchar(26),
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_uploadsessions_user_id ON UploadSessions (UserId);
+CREATE INDEX IF NOT EXISTS idx_uploadsessions_create_at ON UploadSessions (CreateAt);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS Threads (
+    PostId varchar(26) NOT NULL,
+    ReplyCount bigint,
+    LastReplyAt bigint,
+    Participants text,
+    ChannelId varchar(26),
+    PRIMARY KEY (PostId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_threads_channel_id_last_reply_at ON Threads (ChannelId, LastReplyAt);
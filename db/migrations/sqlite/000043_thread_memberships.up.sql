@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS ThreadMemberships (
+    PostId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    Following boolean,
+    LastViewed bigint,
+    LastUpdated bigint,
+    UnreadMentions bigint,
+    PRIMARY KEY (PostId, UserId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_thread_memberships_last_update_at ON ThreadMemberships (LastUpdated);
+CREATE INDEX IF NOT EXISTS idx_thread_memberships_last_view_at ON ThreadMemberships (LastViewed);
+CREATE INDEX IF NOT EXISTS idx_thread_memberships_user_id ON ThreadMemberships (UserId);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS UserTermsOfService (
+    UserId varchar(26) NOT NULL,
+    TermsOfServiceId varchar(26),
+    CreateAt bigint,
+    PRIMARY KEY (UserId)
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS PluginKeyValueStore (
+    PluginId varchar(190) NOT NULL,
+    PKey varchar(50) NOT NULL,
+    PValue blob,
+    ExpireAt bigint,
+    PRIMARY KEY (PluginId, PKey)
+);
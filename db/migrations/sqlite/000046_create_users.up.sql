@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS Users (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    Username varchar(64),
+    Password varchar(128),
+    AuthData varchar(128),
+    AuthService varchar(32),
+    Email varchar(128),
+    EmailVerified boolean,
+    Nickname varchar(64),
+    FirstName varchar(64),
+    LastName varchar(64),
+    Roles text,
+    AllowMarketing boolean,
+    Props text,
+    NotifyProps text,
+    LastPasswordUpdate bigint,
+    LastPictureUpdate bigint,
+    FailedAttempts integer,
+    Locale varchar(5),
+    MfaActive boolean,
+    MfaSecret varchar(128),
+    Position varchar(128),
+    Timezone text,
+    RemoteId varchar(26),
+    PRIMARY KEY (Id),
+    UNIQUE (Username),
+    UNIQUE (AuthData),
+    UNIQUE (Email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_update_at ON Users (UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_users_create_at ON Users (CreateAt);
+CREATE INDEX IF NOT EXISTS idx_users_delete_at ON Users (DeleteAt);
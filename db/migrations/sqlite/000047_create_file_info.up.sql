@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS FileInfo (
+    Id varchar(26) NOT NULL,
+    CreatorId varchar(26),
+    PostId varchar(26),
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    Path text,
+    ThumbnailPath text,
+    PreviewPath text,
+    Name text,
+    Extension varchar(64),
+    Size bigint,
+    MimeType text,
+    Width integer,
+    Height integer,
+    HasPreviewImage boolean,
+    MiniPreview blob,
+    Content text,
+    RemoteId varchar(26),
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fileinfo_update_at ON FileInfo (UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_fileinfo_create_at ON FileInfo (CreateAt);
+CREATE INDEX IF NOT EXISTS idx_fileinfo_delete_at ON FileInfo (DeleteAt);
+CREATE INDEX IF NOT EXISTS idx_fileinfo_postid_at ON FileInfo (PostId);
+CREATE INDEX IF NOT EXISTS idx_fileinfo_extension_at ON FileInfo (Extension);
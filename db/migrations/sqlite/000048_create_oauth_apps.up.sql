@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS OAuthApps (
+    Id varchar(26) NOT NULL,
+    CreatorId varchar(26),
+    CreateAt bigint,
+    UpdateAt bigint,
+    ClientSecret varchar(128),
+    Name varchar(64),
+    Description text,
+    CallbackUrls text,
+    Homepage text,
+    IsTrusted boolean,
+    IconURL text,
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauthapps_creator_id ON OAuthApps (CreatorId);
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS Channels (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    UpdateAt bigint,
+    DeleteAt bigint,
+    TeamId varchar(26),
+    Type varchar(1),
+    DisplayName varchar(64),
+    Name varchar(64),
+    Header text,
+    Purpose varchar(250),
+    LastPostAt bigint,
+    TotalMsgCount bigint,
+    ExtraUpdateAt bigint,
+    CreatorId varchar(26),
+    SchemeId varchar(26),
+    GroupConstrained boolean,
+    Shared boolean,
+    TotalMsgCountRoot bigint,
+    PRIMARY KEY (Id),
+    UNIQUE (Name, TeamId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_channels_update_at ON Channels (UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_channels_create_at ON Channels (CreateAt);
+CREATE INDEX IF NOT EXISTS idx_channels_delete_at ON Channels (DeleteAt);
+CREATE INDEX IF NOT EXISTS idx_channels_scheme_id ON Channels (SchemeId);
+CREATE INDEX IF NOT EXISTS idx_channels_team_id_display_name ON Channels (TeamId, DisplayName);
+CREATE INDEX IF NOT EXISTS idx_channels_team_id_type ON Channels (TeamId, Type);
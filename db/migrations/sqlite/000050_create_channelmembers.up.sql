@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS ChannelMembers (
+    ChannelId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    Roles varchar(64),
+    LastViewedAt bigint,
+    MsgCount bigint,
+    MentionCount bigint,
+    NotifyProps text,
+    LastUpdateAt bigint,
+    SchemeUser boolean,
+    SchemeAdmin boolean,
+    SchemeGuest boolean,
+    MentionCountRoot bigint,
+    MsgCountRoot bigint,
+    PRIMARY KEY (ChannelId, UserId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_channelmembers_user_id_channel_id_last_viewed_at ON ChannelMembers (UserId, ChannelId, LastViewedAt);
+CREATE INDEX IF NOT EXISTS idx_channelmembers_channel_id_scheme_guest_user_id ON ChannelMembers (ChannelId, SchemeGuest, UserId);
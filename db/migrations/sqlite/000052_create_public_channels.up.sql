@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS PublicChannels (
+    Id varchar(26) NOT NULL,
+    DeleteAt bigint,
+    TeamId varchar(26),
+    DisplayName varchar(64),
+    Name varchar(64),
+    Header text,
+    Purpose varchar(250),
+    PRIMARY KEY (Id),
+    UNIQUE (Name, TeamId)
+);
+
+CREATE INDEX IF NOT EXISTS idx_publicchannels_team_id ON PublicChannels (TeamId);
+CREATE INDEX IF NOT EXISTS idx_publicchannels_delete_at ON PublicChannels (DeleteAt);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS RetentionPoliciesChannels;
+DROP TABLE IF EXISTS RetentionPoliciesTeams;
+DROP TABLE IF EXISTS RetentionPolicies;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS RetentionPolicies (
+    Id varchar(26) NOT NULL,
+    DisplayName varchar(64),
+    PostDuration bigint,
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS IDX_RetentionPolicies_DisplayName ON RetentionPolicies (DisplayName);
+
+CREATE TABLE IF NOT EXISTS RetentionPoliciesTeams (
+    PolicyId varchar(26),
+    TeamId varchar(26) NOT NULL,
+    PRIMARY KEY (TeamId),
+    FOREIGN KEY (PolicyId) REFERENCES RetentionPolicies (Id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS IDX_RetentionPoliciesTeams_PolicyId ON RetentionPoliciesTeams (PolicyId);
+
+CREATE TABLE IF NOT EXISTS RetentionPoliciesChannels (
+    PolicyId varchar(26),
+    ChannelId varchar(26) NOT NULL,
+    PRIMARY KEY (ChannelId),
+    FOREIGN KEY (PolicyId) REFERENCES RetentionPolicies (Id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS IDX_RetentionPoliciesChannels_PolicyId ON RetentionPoliciesChannels (PolicyId);
@@ -0,0 +1 @@
+-- The SQLite schema is created at its v6.0 state, there is nothing to upgrade.
@@ -0,0 +1 @@
+-- SQLite does not enforce the length of varchar columns, Roles needs no change.
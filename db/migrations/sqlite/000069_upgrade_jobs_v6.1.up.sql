@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_jobs_status_type ON Jobs (Status, Type);
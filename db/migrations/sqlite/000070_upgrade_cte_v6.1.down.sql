@@ -0,0 +1 @@
+ALTER TABLE Channels DROP COLUMN LastRootPostAt;
@@ -0,0 +1,8 @@
+ALTER TABLE Channels ADD COLUMN LastRootPostAt bigint DEFAULT 0;
+
+UPDATE Channels SET LastRootPostAt = (
+    SELECT COALESCE(MAX(Posts.CreateAt), 0)
+    FROM Posts
+    WHERE Posts.ChannelId = Channels.Id
+    AND Posts.RootId = ''
+);
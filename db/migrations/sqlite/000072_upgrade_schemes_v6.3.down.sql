@@ -0,0 +1,4 @@
+ALTER TABLE Schemes DROP COLUMN DefaultRunMemberRole;
+ALTER TABLE Schemes DROP COLUMN DefaultRunAdminRole;
+ALTER TABLE Schemes DROP COLUMN DefaultPlaybookMemberRole;
+ALTER TABLE Schemes DROP COLUMN DefaultPlaybookAdminRole;
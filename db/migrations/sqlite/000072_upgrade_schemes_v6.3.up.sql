@@ -0,0 +1,4 @@
+ALTER TABLE Schemes ADD COLUMN DefaultPlaybookAdminRole varchar(64) DEFAULT '';
+ALTER TABLE Schemes ADD COLUMN DefaultPlaybookMemberRole varchar(64) DEFAULT '';
+ALTER TABLE Schemes ADD COLUMN DefaultRunAdminRole varchar(64) DEFAULT '';
+ALTER TABLE Schemes ADD COLUMN DefaultRunMemberRole varchar(64) DEFAULT '';
@@ -0,0 +1 @@
+-- The SQLite schema never had the AcceptedTermsOfServiceId column.
@@ -0,0 +1 @@
+-- The SQLite schema always indexed UploadSessions by UserId only.
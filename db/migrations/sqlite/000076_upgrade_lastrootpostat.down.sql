@@ -0,0 +1 @@
+-- The default of LastRootPostAt is set when the column is created.
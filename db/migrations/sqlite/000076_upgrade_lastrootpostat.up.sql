@@ -0,0 +1,7 @@
+UPDATE Channels SET LastRootPostAt = (
+    SELECT COALESCE(MAX(Posts.CreateAt), 0)
+    FROM Posts
+    WHERE Posts.ChannelId = Channels.Id
+    AND Posts.RootId = ''
+)
+WHERE LastRootPostAt IS NULL;
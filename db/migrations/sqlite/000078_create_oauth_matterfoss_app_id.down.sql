@@ -0,0 +1 @@
+ALTER TABLE OAuthApps DROP COLUMN MatterfossAppID;
@@ -0,0 +1 @@
+ALTER TABLE OAuthApps ADD COLUMN MatterfossAppID varchar(32) NOT NULL DEFAULT '';
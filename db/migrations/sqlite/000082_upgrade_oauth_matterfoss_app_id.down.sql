@@ -0,0 +1 @@
+-- SQLite cannot alter a column, MatterfossAppID stays NOT NULL DEFAULT ''.
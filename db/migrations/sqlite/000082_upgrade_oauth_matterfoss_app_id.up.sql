@@ -0,0 +1,2 @@
+-- SQLite cannot alter a column, MatterfossAppID is created as NOT NULL DEFAULT ''.
+UPDATE OAuthApps SET MatterfossAppID = '' WHERE MatterfossAppID IS NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS Drafts (
+    CreateAt bigint,
+    UpdateAt bigint,
+    UserId varchar(26) NOT NULL,
+    ChannelId varchar(26) NOT NULL,
+    RootId varchar(26) NOT NULL DEFAULT '',
+    Message text,
+    Props text,
+    FileIds text,
+    PRIMARY KEY (UserId, ChannelId, RootId)
+);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS ScheduledPosts (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint,
+    UpdateAt bigint,
+    UserId varchar(26) NOT NULL,
+    ChannelId varchar(26) NOT NULL,
+    RootId varchar(26) NOT NULL DEFAULT '',
+    Message text,
+    Props text,
+    FileIds text,
+    ScheduledAt bigint NOT NULL,
+    ProcessedAt bigint NOT NULL DEFAULT 0,
+    ErrorCode varchar(200) NOT NULL DEFAULT '',
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduledposts_userid_channelid_scheduledat ON ScheduledPosts (UserId, ChannelId, ScheduledAt);
+CREATE INDEX IF NOT EXISTS idx_scheduledposts_processedat_scheduledat_id ON ScheduledPosts (ProcessedAt, ScheduledAt, Id);
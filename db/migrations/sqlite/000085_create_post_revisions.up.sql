@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS PostRevisions (
+    Id varchar(26) NOT NULL,
+    PostId varchar(26) NOT NULL,
+    ChannelId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    EditorId varchar(26) NOT NULL DEFAULT '',
+    CreateAt bigint NOT NULL,
+    Message text,
+    Props text,
+    FileIds text,
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_postrevisions_postid_createat ON PostRevisions (PostId, CreateAt);
+CREATE INDEX IF NOT EXISTS idx_postrevisions_channelid_createat ON PostRevisions (ChannelId, CreateAt);
+CREATE INDEX IF NOT EXISTS idx_postrevisions_createat_id ON PostRevisions (CreateAt, Id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS Reminders (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    PostId varchar(26) NOT NULL DEFAULT '',
+    Message text,
+    CreateAt bigint NOT NULL,
+    TargetAt bigint NOT NULL,
+    FiredAt bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminders_userid_targetat ON Reminders (UserId, TargetAt);
+CREATE INDEX IF NOT EXISTS idx_reminders_firedat_targetat_id ON Reminders (FiredAt, TargetAt, Id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS PollVotes (
+    PostId varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    OptionIndex integer NOT NULL,
+    CreateAt bigint NOT NULL,
+    PRIMARY KEY (PostId, UserId, OptionIndex)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pollvotes_userid ON PollVotes (UserId);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS ChannelBookmarks (
+    Id varchar(26) NOT NULL,
+    CreateAt bigint NOT NULL,
+    UpdateAt bigint NOT NULL,
+    DeleteAt bigint NOT NULL DEFAULT 0,
+    ChannelId varchar(26) NOT NULL,
+    OwnerId varchar(26) NOT NULL,
+    FileId varchar(26) NOT NULL DEFAULT '',
+    DisplayName varchar(64) NOT NULL,
+    SortOrder bigint NOT NULL DEFAULT 0,
+    LinkUrl text,
+    ImageUrl text,
+    Emoji varchar(64) NOT NULL DEFAULT '',
+    Type varchar(26) NOT NULL,
+    RemoteId varchar(26),
+    PRIMARY KEY (Id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_channelbookmarks_channelid_updateat ON ChannelBookmarks (ChannelId, UpdateAt);
+CREATE INDEX IF NOT EXISTS idx_channelbookmarks_fileid ON ChannelBookmarks (FileId);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS WebAuthnCredentials (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    Name varchar(64) NOT NULL,
+    CredentialId varchar(1400) NOT NULL,
+    PublicKey text NOT NULL,
+    SignCount bigint NOT NULL DEFAULT 0,
+    AAGUID varchar(32) NOT NULL DEFAULT '',
+    Transports varchar(256) NOT NULL DEFAULT '',
+    CreateAt bigint NOT NULL,
+    UpdateAt bigint NOT NULL,
+    LastUsedAt bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (Id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webauthncredentials_credentialid ON WebAuthnCredentials (CredentialId);
+CREATE INDEX IF NOT EXISTS idx_webauthncredentials_userid ON WebAuthnCredentials (UserId);
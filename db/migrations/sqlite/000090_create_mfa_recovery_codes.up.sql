@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS MfaRecoveryCodes (
+    Id varchar(26) NOT NULL,
+    UserId varchar(26) NOT NULL,
+    CodeHash varchar(64) NOT NULL,
+    CreateAt bigint NOT NULL,
+    UsedAt bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (Id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mfarecoverycodes_userid_codehash ON MfaRecoveryCodes (UserId, CodeHash);
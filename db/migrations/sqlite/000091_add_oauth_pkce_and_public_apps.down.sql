@@ -0,0 +1,3 @@
+ALTER TABLE OAuthApps DROP COLUMN IsPublic;
+ALTER TABLE OAuthAuthData DROP COLUMN CodeChallengeMethod;
+ALTER TABLE OAuthAuthData DROP COLUMN CodeChallenge;
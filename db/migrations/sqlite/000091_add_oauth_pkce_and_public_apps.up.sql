@@ -0,0 +1,3 @@
+ALTER TABLE OAuthAuthData ADD COLUMN CodeChallenge varchar(128) NOT NULL DEFAULT '';
+ALTER TABLE OAuthAuthData ADD COLUMN CodeChallengeMethod varchar(32) NOT NULL DEFAULT '';
+ALTER TABLE OAuthApps ADD COLUMN IsPublic boolean NOT NULL DEFAULT false;
@@ -0,0 +1 @@
+ALTER TABLE OAuthAuthData DROP COLUMN Nonce;
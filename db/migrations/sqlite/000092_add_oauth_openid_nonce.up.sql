@@ -0,0 +1 @@
+ALTER TABLE OAuthAuthData ADD COLUMN Nonce varchar(256) NOT NULL DEFAULT '';
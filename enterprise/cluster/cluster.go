@@ -0,0 +1,610 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+const (
+	// RequestTimeout is how long a node waits for the other nodes to answer a gossip request.
+	RequestTimeout = 15 * time.Second
+	// LeaveTimeout is how long a node waits to broadcast its departure on shutdown.
+	LeaveTimeout = 5 * time.Second
+	// DiscoveryInterval is how often the discovery table is checked for new nodes to join.
+	DiscoveryInterval = 10 * time.Second
+	// DiscoveryPingInterval is how often a node refreshes its own row in the discovery table.
+	DiscoveryPingInterval = 60 * time.Second
+	// DispatchQueueSize is the number of received messages that can wait for a handler.
+	DispatchQueueSize = 10000
+	// MaxBestEffortSize is the largest message sent over UDP; bigger messages go over TCP.
+	MaxBestEffortSize = 1200
+
+	DefaultClusterName = "matterfoss"
+)
+
+// ServerIface is the subset of the app server that the cluster needs. It allows the
+// cluster to be tested without a running server.
+type ServerIface interface {
+	Config() *model.Config
+	ReloadConfig() error
+	GetStore() store.Store
+	GetLogger() mlog.LoggerIFace
+	GetMetrics() einterfaces.MetricsInterface
+	GetLogsSkipSend(page, perPage int) ([]string, *model.AppError)
+	GetPluginStatuses() (model.PluginStatuses, *model.AppError)
+	TotalWebsocketConnections() int
+	InvokeClusterLeaderChangedListeners()
+}
+
+// serverAdapter exposes the few server capabilities that live on the Channels product.
+type serverAdapter struct {
+	*app.Server
+}
+
+func (s serverAdapter) GetPluginStatuses() (model.PluginStatuses, *model.AppError) {
+	return s.Channels().GetPluginStatuses()
+}
+
+func init() {
+	app.RegisterClusterInterface(func(s *app.Server) einterfaces.ClusterInterface {
+		return NewMatterfossCluster(serverAdapter{s})
+	})
+}
+
+// MatterfossCluster implements einterfaces.ClusterInterface on top of a memberlist gossip
+// ring. Nodes find each other through the ClusterDiscovery table, exchange
+// model.ClusterMessage values directly and elect the oldest live node as leader.
+type MatterfossCluster struct {
+	server  ServerIface
+	id      string
+	startAt int64
+
+	handlersMut sync.RWMutex
+	handlers    map[model.ClusterEvent]einterfaces.ClusterMessageHandler
+
+	requestsMut sync.Mutex
+	requests    map[string]chan *envelope
+
+	// lifecycleMut serializes starting and stopping inter-node communication.
+	lifecycleMut sync.Mutex
+	wg           sync.WaitGroup
+
+	// everything below guarded by `mut`
+	mut      sync.RWMutex
+	list     *memberlist.Memberlist
+	members  map[string]*nodeMeta
+	leaderId string
+	dispatch chan *envelope
+	done     chan struct{}
+}
+
+// NewMatterfossCluster creates a cluster node. Nothing is started until
+// StartInterNodeCommunication is called.
+func NewMatterfossCluster(server ServerIface) *MatterfossCluster {
+	id := model.NewId()
+	return &MatterfossCluster{
+		server:   server,
+		id:       id,
+		startAt:  model.GetMillis(),
+		handlers: make(map[model.ClusterEvent]einterfaces.ClusterMessageHandler),
+		requests: make(map[string]chan *envelope),
+		members:  make(map[string]*nodeMeta),
+		leaderId: id,
+	}
+}
+
+func (c *MatterfossCluster) clusterName() string {
+	if name := *c.server.Config().ClusterSettings.ClusterName; name != "" {
+		return name
+	}
+	return DefaultClusterName
+}
+
+func (c *MatterfossCluster) StartInterNodeCommunication() {
+	c.lifecycleMut.Lock()
+	defer c.lifecycleMut.Unlock()
+
+	if c.started() {
+		return
+	}
+
+	logger := c.server.GetLogger()
+	settings := c.server.Config().ClusterSettings
+	if !*settings.Enable {
+		logger.Debug("Cluster is disabled, skipping inter-node communication")
+		return
+	}
+
+	conf, err := c.memberlistConfig(&settings)
+	if err != nil {
+		logger.Error("Cluster failed to configure inter-node communication", mlog.Err(err))
+		return
+	}
+
+	// memberlist calls back into the delegates while it is being created, so the
+	// lock must not be held here.
+	list, err := memberlist.Create(conf)
+	if err != nil {
+		logger.Error("Cluster failed to start inter-node communication", mlog.Int("gossip_port", *settings.GossipPort), mlog.Err(err))
+		return
+	}
+
+	discovery := &model.ClusterDiscovery{
+		Type:        model.CDSTypeApp,
+		ClusterName: c.clusterName(),
+		GossipPort:  int32(list.LocalNode().Port),
+		Port:        int32(*settings.StreamingPort),
+		Hostname:    list.LocalNode().Addr.String(),
+	}
+	dispatch := make(chan *envelope, DispatchQueueSize)
+	done := make(chan struct{})
+
+	c.mut.Lock()
+	c.list = list
+	c.dispatch = dispatch
+	c.done = done
+	c.mut.Unlock()
+
+	c.wg.Add(2)
+	go c.dispatchLoop(dispatch, done)
+	go c.discoveryLoop(discovery, done)
+
+	logger.Info("Cluster inter-node communication started",
+		mlog.String("cluster_id", c.id),
+		mlog.String("cluster_name", discovery.ClusterName),
+		mlog.String("address", list.LocalNode().Address()),
+	)
+}
+
+func (c *MatterfossCluster) started() bool {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	return c.list != nil
+}
+
+func (c *MatterfossCluster) memberlistConfig(settings *model.ClusterSettings) (*memberlist.Config, error) {
+	conf := memberlist.DefaultLANConfig()
+	conf.Name = c.id
+	conf.BindPort = *settings.GossipPort
+	conf.AdvertisePort = *settings.GossipPort
+	conf.Delegate = &delegate{cluster: c}
+	conf.Events = &eventDelegate{cluster: c}
+	conf.EnableCompression = *settings.EnableGossipCompression
+	conf.LogOutput = &logWriter{logger: c.server.GetLogger()}
+
+	if *settings.BindAddress != "" {
+		conf.BindAddr = *settings.BindAddress
+	}
+
+	switch {
+	case *settings.AdvertiseAddress != "":
+		conf.AdvertiseAddr = *settings.AdvertiseAddress
+	case *settings.UseIPAddress:
+		conf.AdvertiseAddr = model.GetServerIPAddress(*settings.NetworkInterface)
+	case *settings.OverrideHostname != "":
+		addr, err := resolveHost(*settings.OverrideHostname)
+		if err != nil {
+			return nil, err
+		}
+		conf.AdvertiseAddr = addr
+	}
+
+	if *settings.EnableExperimentalGossipEncryption {
+		key, err := c.encryptionKey()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the gossip encryption key")
+		}
+		conf.SecretKey = key
+	}
+
+	return conf, nil
+}
+
+func resolveHost(hostname string) (string, error) {
+	addrs, err := net.LookupHost(hostname)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to resolve hostname %s", hostname)
+	}
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("hostname %s has no address", hostname)
+	}
+	return addrs[0], nil
+}
+
+func (c *MatterfossCluster) StopInterNodeCommunication() {
+	c.lifecycleMut.Lock()
+	defer c.lifecycleMut.Unlock()
+
+	c.mut.Lock()
+	list := c.list
+	done := c.done
+	c.list = nil
+	c.mut.Unlock()
+
+	if list == nil {
+		return
+	}
+
+	c.mut.Lock()
+	c.members = make(map[string]*nodeMeta)
+	c.leaderId = c.id
+	c.mut.Unlock()
+
+	if err := list.Leave(LeaveTimeout); err != nil {
+		c.server.GetLogger().Warn("Cluster failed to leave gracefully", mlog.Err(err))
+	}
+	if err := list.Shutdown(); err != nil {
+		c.server.GetLogger().Warn("Cluster failed to shutdown", mlog.Err(err))
+	}
+
+	close(done)
+	c.wg.Wait()
+
+	c.server.GetLogger().Info("Cluster inter-node communication stopped", mlog.String("cluster_id", c.id))
+}
+
+func (c *MatterfossCluster) RegisterClusterMessageHandler(event model.ClusterEvent, crm einterfaces.ClusterMessageHandler) {
+	c.handlersMut.Lock()
+	defer c.handlersMut.Unlock()
+	c.handlers[event] = crm
+}
+
+func (c *MatterfossCluster) GetClusterId() string {
+	return c.id
+}
+
+func (c *MatterfossCluster) IsLeader() bool {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	if c.list == nil {
+		return true
+	}
+	return c.leaderId == c.id
+}
+
+func (c *MatterfossCluster) HealthScore() int {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	if c.list == nil {
+		return 0
+	}
+	return c.list.GetHealthScore()
+}
+
+func (c *MatterfossCluster) GetMyClusterInfo() *model.ClusterInfo {
+	info := &model.ClusterInfo{
+		Id:         c.id,
+		Version:    model.CurrentVersion,
+		ConfigHash: configHash(c.server.Config()),
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		info.Hostname = hostname
+	}
+
+	c.mut.RLock()
+	if c.list != nil {
+		info.IPAddress = c.list.LocalNode().Address()
+	}
+	c.mut.RUnlock()
+
+	if *c.server.Config().ClusterSettings.OverrideHostname != "" {
+		info.Hostname = *c.server.Config().ClusterSettings.OverrideHostname
+	}
+
+	return info
+}
+
+func (c *MatterfossCluster) GetClusterInfos() []*model.ClusterInfo {
+	infos := []*model.ClusterInfo{c.GetMyClusterInfo()}
+
+	for _, node := range c.otherNodes() {
+		meta, err := decodeNodeMeta(node.Meta)
+		if err != nil {
+			c.server.GetLogger().Warn("Cluster failed to decode node metadata", mlog.String("node", node.Name), mlog.Err(err))
+			continue
+		}
+		info := meta.Info
+		info.IPAddress = node.Address()
+		infos = append(infos, &info)
+	}
+
+	return infos
+}
+
+func (c *MatterfossCluster) SendClusterMessage(msg *model.ClusterMessage) {
+	nodes := c.otherNodes()
+	if len(nodes) == 0 {
+		return
+	}
+
+	if metrics := c.server.GetMetrics(); metrics != nil {
+		metrics.IncrementClusterEventType(msg.Event)
+	}
+
+	buf, err := json.Marshal(&envelope{From: c.id, Message: msg})
+	if err != nil {
+		c.server.GetLogger().Error("Cluster failed to encode message", mlog.String("event", string(msg.Event)), mlog.Err(err))
+		return
+	}
+
+	send := func() {
+		var wg sync.WaitGroup
+		for _, node := range nodes {
+			wg.Add(1)
+			go func(node *memberlist.Node) {
+				defer wg.Done()
+				if err := c.send(node, buf, msg.SendType); err != nil {
+					c.server.GetLogger().Warn("Cluster failed to send message",
+						mlog.String("event", string(msg.Event)),
+						mlog.String("node", node.Name),
+						mlog.Err(err),
+					)
+				}
+			}(node)
+		}
+		wg.Wait()
+	}
+
+	if msg.WaitForAllToSend {
+		send()
+	} else {
+		go send()
+	}
+}
+
+func (c *MatterfossCluster) SendClusterMessageToNode(nodeID string, msg *model.ClusterMessage) error {
+	node := c.findNode(nodeID)
+	if node == nil {
+		return fmt.Errorf("cluster node %s is not a member of the cluster", nodeID)
+	}
+
+	if metrics := c.server.GetMetrics(); metrics != nil {
+		metrics.IncrementClusterEventType(msg.Event)
+	}
+
+	buf, err := json.Marshal(&envelope{From: c.id, Message: msg})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode cluster message")
+	}
+
+	return c.send(node, buf, msg.SendType)
+}
+
+// send delivers an encoded message to a single node. Best effort messages go over UDP
+// unless they are too large to fit in a single packet.
+func (c *MatterfossCluster) send(node *memberlist.Node, buf []byte, sendType string) error {
+	c.mut.RLock()
+	list := c.list
+	c.mut.RUnlock()
+
+	if list == nil {
+		return errors.New("inter-node communication is not started")
+	}
+
+	if sendType == model.ClusterSendBestEffort && len(buf) <= MaxBestEffortSize {
+		return list.SendBestEffort(node, buf)
+	}
+	return list.SendReliable(node, buf)
+}
+
+// NotifyMsg is called by memberlist for every message received from another node.
+func (c *MatterfossCluster) NotifyMsg(buf []byte) {
+	if len(buf) == 0 {
+		return
+	}
+
+	var env envelope
+	if err := json.Unmarshal(buf, &env); err != nil {
+		c.server.GetLogger().Warn("Cluster received an invalid message", mlog.Err(err))
+		return
+	}
+	if env.Message == nil || env.From == c.id {
+		return
+	}
+
+	if env.RequestId != "" && isGossipResponse(env.Message.Event) {
+		c.deliverResponse(&env)
+		return
+	}
+
+	c.mut.RLock()
+	dispatch := c.dispatch
+	done := c.done
+	c.mut.RUnlock()
+
+	if dispatch == nil {
+		return
+	}
+
+	select {
+	case dispatch <- &env:
+	case <-done:
+	default:
+		c.server.GetLogger().Error("Cluster message queue is full, dropping message", mlog.String("event", string(env.Message.Event)))
+	}
+}
+
+func (c *MatterfossCluster) dispatchLoop(dispatch <-chan *envelope, done <-chan struct{}) {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case env := <-dispatch:
+			c.handle(env)
+		case <-done:
+			return
+		}
+	}
+}
+
+func (c *MatterfossCluster) handle(env *envelope) {
+	if isGossipRequest(env.Message.Event) {
+		c.handleGossipRequest(env)
+		return
+	}
+
+	c.handlersMut.RLock()
+	handler, ok := c.handlers[env.Message.Event]
+	c.handlersMut.RUnlock()
+
+	if !ok {
+		c.server.GetLogger().Debug("Cluster has no handler for event", mlog.String("event", string(env.Message.Event)))
+		return
+	}
+
+	handler(env.Message)
+}
+
+// otherNodes returns every live member except this one.
+func (c *MatterfossCluster) otherNodes() []*memberlist.Node {
+	c.mut.RLock()
+	list := c.list
+	c.mut.RUnlock()
+
+	if list == nil {
+		return nil
+	}
+
+	members := list.Members()
+	nodes := make([]*memberlist.Node, 0, len(members))
+	for _, node := range members {
+		if node.Name != c.id {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func (c *MatterfossCluster) findNode(nodeID string) *memberlist.Node {
+	for _, node := range c.otherNodes() {
+		if node.Name == nodeID {
+			return node
+		}
+	}
+	return nil
+}
+
+// updateMember records the metadata of a node that joined or was updated. It is
+// called from the memberlist event delegate, which holds the memberlist locks, so it
+// must never call back into memberlist.
+func (c *MatterfossCluster) updateMember(name string, meta *nodeMeta) {
+	c.mut.Lock()
+	c.members[name] = meta
+	c.mut.Unlock()
+
+	c.electLeader()
+}
+
+func (c *MatterfossCluster) removeMember(name string) {
+	c.mut.Lock()
+	delete(c.members, name)
+	c.mut.Unlock()
+
+	c.electLeader()
+}
+
+// electLeader picks the live node that started first, breaking ties by id, and
+// notifies the server whenever leadership moves to or away from this node.
+func (c *MatterfossCluster) electLeader() {
+	c.mut.Lock()
+	leaderId := c.id
+	leaderStartAt := c.startAt
+	for name, meta := range c.members {
+		if meta.StartAt < leaderStartAt || (meta.StartAt == leaderStartAt && name < leaderId) {
+			leaderId = name
+			leaderStartAt = meta.StartAt
+		}
+	}
+
+	wasLeader := c.leaderId == c.id
+	c.leaderId = leaderId
+	isLeader := leaderId == c.id
+	c.mut.Unlock()
+
+	if wasLeader != isLeader {
+		c.server.GetLogger().Info("Cluster leader changed", mlog.String("leader_id", leaderId), mlog.Bool("is_leader", isLeader))
+		go c.server.InvokeClusterLeaderChangedListeners()
+	}
+}
+
+func (c *MatterfossCluster) ConfigChanged(previousConfig *model.Config, newConfig *model.Config, sendToOtherServer bool) *model.AppError {
+	if previousConfig != nil && newConfig != nil && !clusterSettingsEqual(&previousConfig.ClusterSettings, &newConfig.ClusterSettings) {
+		c.server.GetLogger().Warn("Cluster configuration has changed. The cluster may become unstable and a restart is required.", mlog.String("cluster_id", c.id))
+	}
+
+	c.mut.RLock()
+	list := c.list
+	c.mut.RUnlock()
+
+	if list == nil {
+		return nil
+	}
+
+	if err := list.UpdateNode(LeaveTimeout); err != nil {
+		c.server.GetLogger().Warn("Cluster failed to update node metadata", mlog.Err(err))
+	}
+
+	if !sendToOtherServer {
+		return nil
+	}
+
+	msg := &model.ClusterMessage{
+		Event:    model.ClusterGossipEventRequestSaveConfig,
+		SendType: model.ClusterSendReliable,
+	}
+	responses, appErr := c.request(msg)
+	if appErr != nil {
+		return appErr
+	}
+
+	for _, response := range responses {
+		if errMsg := response.Props["error"]; errMsg != "" {
+			return model.NewAppError("ConfigChanged", "ent.cluster.reload_config.app_error", nil, errMsg, http.StatusInternalServerError)
+		}
+	}
+
+	return nil
+}
+
+func clusterSettingsEqual(a, b *model.ClusterSettings) bool {
+	aBuf, _ := json.Marshal(a)
+	bBuf, _ := json.Marshal(b)
+	return string(aBuf) == string(bBuf)
+}
+
+func configHash(cfg *model.Config) string {
+	buf, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", md5.Sum(buf))
+}
+
+func pageProps(page, perPage int) map[string]string {
+	return map[string]string{
+		"page":     strconv.Itoa(page),
+		"per_page": strconv.Itoa(perPage),
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+	"github.com/cjdelisle/matterfoss-server/v6/store/storetest/mocks"
+)
+
+// discoveryTable is an in-memory ClusterDiscoveryStore shared by the test nodes.
+type discoveryTable struct {
+	mut  sync.Mutex
+	rows map[string]*model.ClusterDiscovery
+}
+
+func (d *discoveryTable) Save(discovery *model.ClusterDiscovery) error {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	discovery.PreSave()
+	copy := *discovery
+	d.rows[discovery.Id] = &copy
+	return nil
+}
+
+func (d *discoveryTable) Delete(discovery *model.ClusterDiscovery) (bool, error) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	_, ok := d.rows[discovery.Id]
+	delete(d.rows, discovery.Id)
+	return ok, nil
+}
+
+func (d *discoveryTable) Exists(discovery *model.ClusterDiscovery) (bool, error) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	_, ok := d.rows[discovery.Id]
+	return ok, nil
+}
+
+func (d *discoveryTable) GetAll(discoveryType, clusterName string) ([]*model.ClusterDiscovery, error) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	var all []*model.ClusterDiscovery
+	for _, row := range d.rows {
+		if row.Type == discoveryType && row.ClusterName == clusterName {
+			all = append(all, row)
+		}
+	}
+	return all, nil
+}
+
+func (d *discoveryTable) SetLastPingAt(discovery *model.ClusterDiscovery) error { return nil }
+func (d *discoveryTable) Cleanup() error                                        { return nil }
+
+type mockServer struct {
+	config       *model.Config
+	store        *mocks.Store
+	logger       *mlog.Logger
+	reloads      int32
+	leaderChange int32
+}
+
+func newMockServer(t *testing.T, table *discoveryTable) *mockServer {
+	config := &model.Config{}
+	config.SetDefaults()
+	config.ClusterSettings.Enable = model.NewBool(true)
+	config.ClusterSettings.ClusterName = model.NewString("test")
+	config.ClusterSettings.BindAddress = model.NewString("127.0.0.1")
+	config.ClusterSettings.AdvertiseAddress = model.NewString("127.0.0.1")
+	config.ClusterSettings.GossipPort = model.NewInt(0)
+
+	mockStore := &mocks.Store{}
+	mockStore.On("ClusterDiscovery").Return(table)
+	mockStore.On("TotalReadDbConnections").Return(3)
+	mockStore.On("TotalMasterDbConnections").Return(5)
+
+	ms := &mockServer{
+		config: config,
+		store:  mockStore,
+		logger: mlog.CreateConsoleTestLogger(true, mlog.LvlError),
+	}
+	t.Cleanup(func() { ms.logger.Shutdown() })
+	return ms
+}
+
+func (ms *mockServer) Config() *model.Config                    { return ms.config }
+func (ms *mockServer) GetStore() store.Store                    { return ms.store }
+func (ms *mockServer) GetLogger() mlog.LoggerIFace              { return ms.logger }
+func (ms *mockServer) GetMetrics() einterfaces.MetricsInterface { return nil }
+func (ms *mockServer) TotalWebsocketConnections() int           { return 7 }
+func (ms *mockServer) ReloadConfig() error {
+	atomic.AddInt32(&ms.reloads, 1)
+	return nil
+}
+func (ms *mockServer) GetLogsSkipSend(page, perPage int) ([]string, *model.AppError) {
+	return []string{"log line"}, nil
+}
+func (ms *mockServer) GetPluginStatuses() (model.PluginStatuses, *model.AppError) {
+	return model.PluginStatuses{{PluginId: "plugin"}}, nil
+}
+func (ms *mockServer) InvokeClusterLeaderChangedListeners() {
+	atomic.AddInt32(&ms.leaderChange, 1)
+}
+
+func startNodes(t *testing.T, count int) ([]*MatterfossCluster, []*mockServer) {
+	table := &discoveryTable{rows: make(map[string]*model.ClusterDiscovery)}
+
+	var nodes []*MatterfossCluster
+	var servers []*mockServer
+	for i := 0; i < count; i++ {
+		server := newMockServer(t, table)
+		node := NewMatterfossCluster(server)
+		// Make the start order, and so the leader, deterministic.
+		node.startAt = int64(i + 1)
+		node.StartInterNodeCommunication()
+		require.True(t, node.started())
+		t.Cleanup(node.StopInterNodeCommunication)
+
+		nodes = append(nodes, node)
+		servers = append(servers, server)
+	}
+
+	// The first nodes may have looked at the discovery table before the others registered.
+	for _, node := range nodes {
+		node.joinDiscovered(&model.ClusterDiscovery{Type: model.CDSTypeApp, ClusterName: "test"})
+	}
+
+	for _, node := range nodes {
+		require.Eventually(t, func() bool {
+			return len(node.GetClusterInfos()) == count
+		}, 10*time.Second, 50*time.Millisecond)
+	}
+
+	return nodes, servers
+}
+
+func TestClusterMembership(t *testing.T) {
+	nodes, _ := startNodes(t, 3)
+
+	infos := nodes[1].GetClusterInfos()
+	ids := make([]string, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.Id)
+		assert.Equal(t, model.CurrentVersion, info.Version)
+		assert.NotEmpty(t, info.ConfigHash)
+		assert.NotEmpty(t, info.IPAddress)
+	}
+	assert.ElementsMatch(t, []string{nodes[0].GetClusterId(), nodes[1].GetClusterId(), nodes[2].GetClusterId()}, ids)
+
+	assert.Equal(t, 0, nodes[0].HealthScore())
+}
+
+func TestClusterLeaderElection(t *testing.T) {
+	nodes, servers := startNodes(t, 3)
+
+	require.Eventually(t, func() bool {
+		return nodes[0].IsLeader() && !nodes[1].IsLeader() && !nodes[2].IsLeader()
+	}, 10*time.Second, 50*time.Millisecond)
+
+	nodes[0].StopInterNodeCommunication()
+
+	require.Eventually(t, func() bool {
+		return nodes[1].IsLeader() && !nodes[2].IsLeader()
+	}, 10*time.Second, 50*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&servers[1].leaderChange) > 0
+	}, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestClusterSendMessage(t *testing.T) {
+	nodes, _ := startNodes(t, 3)
+
+	received := make(chan *model.ClusterMessage, 10)
+	for _, node := range nodes[1:] {
+		node.RegisterClusterMessageHandler(model.ClusterEventInvalidateCacheForUser, func(msg *model.ClusterMessage) {
+			received <- msg
+		})
+	}
+
+	t.Run("broadcast", func(t *testing.T) {
+		nodes[0].SendClusterMessage(&model.ClusterMessage{
+			Event:            model.ClusterEventInvalidateCacheForUser,
+			SendType:         model.ClusterSendReliable,
+			WaitForAllToSend: true,
+			Data:             []byte("user_id"),
+		})
+
+		for i := 0; i < 2; i++ {
+			select {
+			case msg := <-received:
+				assert.Equal(t, []byte("user_id"), msg.Data)
+			case <-time.After(10 * time.Second):
+				require.Fail(t, "message not received")
+			}
+		}
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		nodes[0].SendClusterMessage(&model.ClusterMessage{
+			Event:    model.ClusterEventInvalidateCacheForUser,
+			SendType: model.ClusterSendBestEffort,
+			Props:    map[string]string{"key": "value"},
+		})
+
+		for i := 0; i < 2; i++ {
+			select {
+			case msg := <-received:
+				assert.Equal(t, "value", msg.Props["key"])
+			case <-time.After(10 * time.Second):
+				require.Fail(t, "message not received")
+			}
+		}
+	})
+
+	t.Run("to node", func(t *testing.T) {
+		err := nodes[0].SendClusterMessageToNode(nodes[2].GetClusterId(), &model.ClusterMessage{
+			Event:    model.ClusterEventInvalidateCacheForUser,
+			SendType: model.ClusterSendReliable,
+			Data:     []byte("only node 2"),
+		})
+		require.NoError(t, err)
+
+		select {
+		case msg := <-received:
+			assert.Equal(t, []byte("only node 2"), msg.Data)
+		case <-time.After(10 * time.Second):
+			require.Fail(t, "message not received")
+		}
+
+		select {
+		case <-received:
+			require.Fail(t, "message received twice")
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+
+	t.Run("to unknown node", func(t *testing.T) {
+		err := nodes[0].SendClusterMessageToNode(model.NewId(), &model.ClusterMessage{
+			Event: model.ClusterEventInvalidateCacheForUser,
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestClusterRequests(t *testing.T) {
+	nodes, servers := startNodes(t, 3)
+
+	t.Run("stats", func(t *testing.T) {
+		stats, appErr := nodes[0].GetClusterStats()
+		require.Nil(t, appErr)
+		require.Len(t, stats, 2)
+		for _, stat := range stats {
+			assert.NotEqual(t, nodes[0].GetClusterId(), stat.Id)
+			assert.Equal(t, 7, stat.TotalWebsocketConnections)
+			assert.Equal(t, 3, stat.TotalReadDbConnections)
+			assert.Equal(t, 5, stat.TotalMasterDbConnections)
+		}
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		lines, appErr := nodes[0].GetLogs(0, 10)
+		require.Nil(t, appErr)
+		count := 0
+		for _, line := range lines {
+			if line == "log line" {
+				count++
+			}
+		}
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("plugin statuses", func(t *testing.T) {
+		statuses, appErr := nodes[0].GetPluginStatuses()
+		require.Nil(t, appErr)
+		assert.Len(t, statuses, 2)
+	})
+
+	t.Run("config changed", func(t *testing.T) {
+		appErr := nodes[0].ConfigChanged(servers[0].config, servers[0].config, true)
+		require.Nil(t, appErr)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&servers[0].reloads))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&servers[1].reloads))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&servers[2].reloads))
+
+		appErr = nodes[0].ConfigChanged(servers[0].config, servers[0].config, false)
+		require.Nil(t, appErr)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&servers[1].reloads))
+	})
+}
+
+func TestClusterDisabled(t *testing.T) {
+	table := &discoveryTable{rows: make(map[string]*model.ClusterDiscovery)}
+	server := newMockServer(t, table)
+	server.config.ClusterSettings.Enable = model.NewBool(false)
+
+	node := NewMatterfossCluster(server)
+	node.StartInterNodeCommunication()
+	defer node.StopInterNodeCommunication()
+
+	assert.False(t, node.started())
+	assert.True(t, node.IsLeader())
+	assert.Len(t, node.GetClusterInfos(), 1)
+
+	stats, appErr := node.GetClusterStats()
+	require.Nil(t, appErr)
+	assert.Empty(t, stats)
+}
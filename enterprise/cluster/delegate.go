@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/memberlist"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// envelope is the wire format of every message exchanged between nodes.
+type envelope struct {
+	From      string                `json:"from"`
+	RequestId string                `json:"request_id,omitempty"`
+	Message   *model.ClusterMessage `json:"message"`
+}
+
+// nodeMeta is gossiped by memberlist along with the node's address.
+type nodeMeta struct {
+	Info    model.ClusterInfo `json:"info"`
+	StartAt int64             `json:"start_at"`
+}
+
+func decodeNodeMeta(buf []byte) (*nodeMeta, error) {
+	var meta nodeMeta
+	if err := json.Unmarshal(buf, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// delegate hooks the cluster into the memberlist gossip protocol.
+type delegate struct {
+	cluster *MatterfossCluster
+}
+
+func (d *delegate) NodeMeta(limit int) []byte {
+	meta := nodeMeta{
+		Info:    *d.cluster.GetMyClusterInfo(),
+		StartAt: d.cluster.startAt,
+	}
+	meta.Info.IPAddress = ""
+
+	buf, err := json.Marshal(meta)
+	if err != nil {
+		d.cluster.server.GetLogger().Error("Cluster failed to encode node metadata", mlog.Err(err))
+		return nil
+	}
+
+	if len(buf) > limit {
+		// The hostname is the only unbounded field, drop it rather than the whole metadata.
+		meta.Info.Hostname = ""
+		buf, _ = json.Marshal(meta)
+	}
+
+	return buf
+}
+
+func (d *delegate) NotifyMsg(buf []byte) {
+	// memberlist reuses the buffer once this returns.
+	d.cluster.NotifyMsg(append([]byte(nil), buf...))
+}
+
+func (d *delegate) GetBroadcasts(overhead, limit int) [][]byte {
+	return nil
+}
+
+func (d *delegate) LocalState(join bool) []byte {
+	return nil
+}
+
+func (d *delegate) MergeRemoteState(buf []byte, join bool) {
+}
+
+// eventDelegate keeps track of the members and re-runs the leader election whenever
+// the membership changes.
+type eventDelegate struct {
+	cluster *MatterfossCluster
+}
+
+func (e *eventDelegate) NotifyJoin(node *memberlist.Node) {
+	e.cluster.server.GetLogger().Info("Cluster node joined", mlog.String("node", node.Name), mlog.String("address", node.Address()))
+	e.NotifyUpdate(node)
+}
+
+func (e *eventDelegate) NotifyLeave(node *memberlist.Node) {
+	e.cluster.server.GetLogger().Info("Cluster node left", mlog.String("node", node.Name), mlog.String("address", node.Address()))
+	e.cluster.removeMember(node.Name)
+}
+
+func (e *eventDelegate) NotifyUpdate(node *memberlist.Node) {
+	meta, err := decodeNodeMeta(node.Meta)
+	if err != nil {
+		e.cluster.server.GetLogger().Warn("Cluster failed to decode node metadata", mlog.String("node", node.Name), mlog.Err(err))
+		return
+	}
+	e.cluster.updateMember(node.Name, meta)
+}
+
+// logWriter forwards the memberlist log output to the server logger.
+type logWriter struct {
+	logger mlog.LoggerIFace
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimSpace(p))
+
+	switch {
+	case strings.Contains(msg, "[ERR]"):
+		w.logger.Error(msg)
+	case strings.Contains(msg, "[WARN]"):
+		w.logger.Warn(msg)
+	case strings.Contains(msg, "[INFO]"):
+		w.logger.Info(msg)
+	default:
+		w.logger.Debug(msg)
+	}
+
+	return len(p), nil
+}
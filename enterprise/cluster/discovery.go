@@ -0,0 +1,119 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// discoveryLoop registers this node in the ClusterDiscovery table, keeps the row
+// alive and joins every other node of the same cluster found there.
+func (c *MatterfossCluster) discoveryLoop(discovery *model.ClusterDiscovery, done <-chan struct{}) {
+	defer c.wg.Done()
+
+	logger := c.server.GetLogger()
+	discoveryStore := c.server.GetStore().ClusterDiscovery()
+
+	if err := discoveryStore.Cleanup(); err != nil {
+		logger.Warn("Cluster failed to cleanup the outdated cluster discovery information", mlog.Err(err))
+	}
+	if err := discoveryStore.Save(discovery); err != nil {
+		logger.Error("Cluster failed to save cluster discovery information", mlog.Err(err))
+	}
+
+	defer func() {
+		if _, err := discoveryStore.Delete(discovery); err != nil {
+			logger.Warn("Cluster failed to cleanup cluster discovery information", mlog.String("discovery_id", discovery.Id), mlog.Err(err))
+		}
+	}()
+
+	c.joinDiscovered(discovery)
+
+	joinTicker := time.NewTicker(DiscoveryInterval)
+	defer joinTicker.Stop()
+	pingTicker := time.NewTicker(DiscoveryPingInterval)
+	defer pingTicker.Stop()
+
+	for {
+		select {
+		case <-joinTicker.C:
+			c.joinDiscovered(discovery)
+		case <-pingTicker.C:
+			if err := discoveryStore.SetLastPingAt(discovery); err != nil {
+				logger.Error("Cluster failed to write discovery ping", mlog.String("discovery_id", discovery.Id), mlog.Err(err))
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// joinDiscovered joins the nodes from the discovery table that are not yet members.
+func (c *MatterfossCluster) joinDiscovered(self *model.ClusterDiscovery) {
+	discovered, err := c.server.GetStore().ClusterDiscovery().GetAll(self.Type, self.ClusterName)
+	if err != nil {
+		c.server.GetLogger().Warn("Cluster failed to get cluster discovery information", mlog.Err(err))
+		return
+	}
+
+	known := make(map[string]bool)
+	for _, node := range c.otherNodes() {
+		known[node.Address()] = true
+	}
+
+	var addresses []string
+	for _, d := range discovered {
+		if d.Id == self.Id {
+			continue
+		}
+		address := net.JoinHostPort(d.Hostname, strconv.Itoa(int(d.GossipPort)))
+		if !known[address] {
+			addresses = append(addresses, address)
+		}
+	}
+
+	if len(addresses) == 0 {
+		return
+	}
+
+	c.mut.RLock()
+	list := c.list
+	c.mut.RUnlock()
+	if list == nil {
+		return
+	}
+
+	joined, err := list.Join(addresses)
+	if err != nil {
+		c.server.GetLogger().Debug("Cluster failed to join some nodes", mlog.Int("joined", joined), mlog.Err(err))
+	}
+}
+
+// encryptionKey returns the gossip encryption key shared by every node through the
+// Systems table, generating it if this is the first node to need one.
+func (c *MatterfossCluster) encryptionKey() ([]byte, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, errors.Wrap(err, "failed to generate key")
+	}
+
+	system, err := c.server.GetStore().System().InsertIfExists(&model.System{
+		Name:  model.SystemClusterEncryptionKey,
+		Value: base64.StdEncoding.EncodeToString(buf),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(system.Value)
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+var gossipResponses = map[model.ClusterEvent]model.ClusterEvent{
+	model.ClusterGossipEventRequestGetLogs:           model.ClusterGossipEventResponseGetLogs,
+	model.ClusterGossipEventRequestGetClusterStats:   model.ClusterGossipEventResponseGetClusterStats,
+	model.ClusterGossipEventRequestGetPluginStatuses: model.ClusterGossipEventResponseGetPluginStatuses,
+	model.ClusterGossipEventRequestSaveConfig:        model.ClusterGossipEventResponseSaveConfig,
+}
+
+func isGossipRequest(event model.ClusterEvent) bool {
+	_, ok := gossipResponses[event]
+	return ok
+}
+
+func isGossipResponse(event model.ClusterEvent) bool {
+	for _, response := range gossipResponses {
+		if response == event {
+			return true
+		}
+	}
+	return false
+}
+
+// request sends msg to every other node and waits for each of them to answer.
+func (c *MatterfossCluster) request(msg *model.ClusterMessage) ([]*model.ClusterMessage, *model.AppError) {
+	nodes := c.otherNodes()
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	if metrics := c.server.GetMetrics(); metrics != nil {
+		metrics.IncrementClusterRequest()
+		start := time.Now()
+		defer func() {
+			metrics.ObserveClusterRequestDuration(time.Since(start).Seconds())
+		}()
+	}
+
+	env := &envelope{
+		From:      c.id,
+		RequestId: model.NewId(),
+		Message:   msg,
+	}
+	buf, err := json.Marshal(env)
+	if err != nil {
+		return nil, model.NewAppError("request", "ent.cluster.json_encode.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	responses := make(chan *envelope, len(nodes))
+	c.requestsMut.Lock()
+	c.requests[env.RequestId] = responses
+	c.requestsMut.Unlock()
+
+	defer func() {
+		c.requestsMut.Lock()
+		delete(c.requests, env.RequestId)
+		c.requestsMut.Unlock()
+	}()
+
+	expected := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if err := c.send(node, buf, model.ClusterSendReliable); err != nil {
+			c.server.GetLogger().Warn("Cluster failed to send request",
+				mlog.String("event", string(msg.Event)),
+				mlog.String("node", node.Name),
+				mlog.Err(err),
+			)
+			continue
+		}
+		expected[node.Name] = true
+	}
+
+	results := make([]*model.ClusterMessage, 0, len(expected))
+	timeout := time.NewTimer(RequestTimeout)
+	defer timeout.Stop()
+
+	for len(expected) > 0 {
+		select {
+		case response := <-responses:
+			if !expected[response.From] {
+				continue
+			}
+			delete(expected, response.From)
+			results = append(results, response.Message)
+		case <-timeout.C:
+			return nil, model.NewAppError("request", "ent.cluster.timeout.error", nil, "event="+string(msg.Event), http.StatusInternalServerError)
+		}
+	}
+
+	return results, nil
+}
+
+func (c *MatterfossCluster) deliverResponse(env *envelope) {
+	c.requestsMut.Lock()
+	responses, ok := c.requests[env.RequestId]
+	c.requestsMut.Unlock()
+
+	if !ok {
+		c.server.GetLogger().Debug("Cluster received a response to an unknown request", mlog.String("request_id", env.RequestId))
+		return
+	}
+
+	select {
+	case responses <- env:
+	default:
+	}
+}
+
+func (c *MatterfossCluster) handleGossipRequest(env *envelope) {
+	response := &model.ClusterMessage{
+		Event:    gossipResponses[env.Message.Event],
+		SendType: model.ClusterSendReliable,
+		Props:    map[string]string{},
+	}
+
+	var data interface{}
+	var appErr *model.AppError
+
+	switch env.Message.Event {
+	case model.ClusterGossipEventRequestGetLogs:
+		page, _ := strconv.Atoi(env.Message.Props["page"])
+		perPage, _ := strconv.Atoi(env.Message.Props["per_page"])
+		data, appErr = c.localLogs(page, perPage)
+	case model.ClusterGossipEventRequestGetClusterStats:
+		data = c.localStats()
+	case model.ClusterGossipEventRequestGetPluginStatuses:
+		data, appErr = c.server.GetPluginStatuses()
+	case model.ClusterGossipEventRequestSaveConfig:
+		if err := c.server.ReloadConfig(); err != nil {
+			response.Props["error"] = err.Error()
+		}
+	}
+
+	if appErr != nil {
+		response.Props["error"] = appErr.Error()
+	} else if data != nil {
+		buf, err := json.Marshal(data)
+		if err != nil {
+			response.Props["error"] = err.Error()
+		}
+		response.Data = buf
+	}
+
+	c.reply(env, response)
+}
+
+func (c *MatterfossCluster) reply(request *envelope, msg *model.ClusterMessage) {
+	var node *memberlist.Node
+	if node = c.findNode(request.From); node == nil {
+		c.server.GetLogger().Warn("Cluster cannot answer a request from an unknown node", mlog.String("node", request.From))
+		return
+	}
+
+	buf, err := json.Marshal(&envelope{From: c.id, RequestId: request.RequestId, Message: msg})
+	if err != nil {
+		c.server.GetLogger().Error("Cluster failed to encode response", mlog.String("event", string(msg.Event)), mlog.Err(err))
+		return
+	}
+
+	if err := c.send(node, buf, model.ClusterSendReliable); err != nil {
+		c.server.GetLogger().Warn("Cluster failed to send response", mlog.String("node", node.Name), mlog.Err(err))
+	}
+}
+
+func (c *MatterfossCluster) localLogs(page, perPage int) ([]string, *model.AppError) {
+	separator := strings.Repeat("-", 107)
+	info := c.GetMyClusterInfo()
+	lines := []string{separator, separator, info.Hostname, separator, separator}
+
+	logs, appErr := c.server.GetLogsSkipSend(page, perPage)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return append(lines, logs...), nil
+}
+
+func (c *MatterfossCluster) localStats() *model.ClusterStats {
+	return &model.ClusterStats{
+		Id:                        c.id,
+		TotalWebsocketConnections: c.server.TotalWebsocketConnections(),
+		TotalReadDbConnections:    c.server.GetStore().TotalReadDbConnections(),
+		TotalMasterDbConnections:  c.server.GetStore().TotalMasterDbConnections(),
+	}
+}
+
+func responseError(where string, msg *model.ClusterMessage) *model.AppError {
+	if errMsg := msg.Props["error"]; errMsg != "" {
+		return model.NewAppError(where, "ent.cluster.request.app_error", nil, errMsg, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (c *MatterfossCluster) GetClusterStats() ([]*model.ClusterStats, *model.AppError) {
+	responses, appErr := c.request(&model.ClusterMessage{Event: model.ClusterGossipEventRequestGetClusterStats})
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	stats := make([]*model.ClusterStats, 0, len(responses))
+	for _, response := range responses {
+		if appErr := responseError("GetClusterStats", response); appErr != nil {
+			return nil, appErr
+		}
+		var stat model.ClusterStats
+		if err := json.Unmarshal(response.Data, &stat); err != nil {
+			return nil, model.NewAppError("GetClusterStats", "ent.cluster.json_decode.error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		stats = append(stats, &stat)
+	}
+
+	return stats, nil
+}
+
+func (c *MatterfossCluster) GetLogs(page, perPage int) ([]string, *model.AppError) {
+	responses, appErr := c.request(&model.ClusterMessage{
+		Event: model.ClusterGossipEventRequestGetLogs,
+		Props: pageProps(page, perPage),
+	})
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var lines []string
+	for _, response := range responses {
+		if appErr := responseError("GetLogs", response); appErr != nil {
+			return nil, appErr
+		}
+		var nodeLines []string
+		if err := json.Unmarshal(response.Data, &nodeLines); err != nil {
+			return nil, model.NewAppError("GetLogs", "ent.cluster.json_decode.error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		lines = append(lines, nodeLines...)
+	}
+
+	return lines, nil
+}
+
+func (c *MatterfossCluster) GetPluginStatuses() (model.PluginStatuses, *model.AppError) {
+	responses, appErr := c.request(&model.ClusterMessage{Event: model.ClusterGossipEventRequestGetPluginStatuses})
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	var statuses model.PluginStatuses
+	for _, response := range responses {
+		if appErr := responseError("GetPluginStatuses", response); appErr != nil {
+			return nil, appErr
+		}
+		var nodeStatuses model.PluginStatuses
+		if err := json.Unmarshal(response.Data, &nodeStatuses); err != nil {
+			return nil, model.NewAppError("GetPluginStatuses", "ent.cluster.json_decode.error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		statuses = append(statuses, nodeStatuses...)
+	}
+
+	return statuses, nil
+}
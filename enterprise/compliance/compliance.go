@@ -0,0 +1,227 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package compliance
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/filestore"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+const (
+	// reportDirectory is where reports are written, relative to ComplianceSettings.Directory.
+	reportDirectory = "compliance/"
+	// postsFileName is the name of the CSV file inside a report's zip file.
+	postsFileName = "posts.csv"
+
+	dailyTaskName = "Compliance Daily Report"
+)
+
+// AppIface is the subset of the app layer used by the compliance implementation.
+type AppIface interface {
+	Config() *model.Config
+	License() *model.License
+}
+
+func init() {
+	app.RegisterComplianceInterface(func(a *app.App) einterfaces.ComplianceInterface {
+		return New(a, a.Srv().Store)
+	})
+}
+
+// Compliance implements einterfaces.ComplianceInterface. Reports are zipped CSV files
+// of the matching posts, written to ComplianceSettings.Directory where
+// App.GetComplianceFile reads them back.
+type Compliance struct {
+	app   AppIface
+	store store.Store
+
+	mut       sync.Mutex
+	dailyTask *model.ScheduledTask
+}
+
+func New(app AppIface, store store.Store) *Compliance {
+	return &Compliance{app: app, store: store}
+}
+
+func (c *Compliance) checkEnabled(where string) *model.AppError {
+	license := c.app.License()
+	if !*c.app.Config().ComplianceSettings.Enable || license == nil || !*license.Features.Compliance {
+		return model.NewAppError(where, "ent.compliance.licence_disable.app_error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+// StartComplianceDailyJob schedules a report of the previous day's posts to run every
+// night at midnight, as long as ComplianceSettings.EnableDaily is set.
+func (c *Compliance) StartComplianceDailyJob() {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if c.dailyTask != nil {
+		return
+	}
+	c.scheduleDailyReport(time.Now())
+}
+
+// scheduleDailyReport must be called with mut held.
+func (c *Compliance) scheduleDailyReport(now time.Time) {
+	c.dailyTask = model.CreateTask(dailyTaskName, func() {
+		reportTime := time.Now()
+		c.runDailyReport(reportTime)
+
+		c.mut.Lock()
+		defer c.mut.Unlock()
+		c.scheduleDailyReport(reportTime)
+	}, nextMidnight(now).Sub(now))
+}
+
+func nextMidnight(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+}
+
+// runDailyReport saves and runs a report of the day ending at the midnight before now.
+func (c *Compliance) runDailyReport(now time.Time) {
+	if !*c.app.Config().ComplianceSettings.EnableDaily || c.checkEnabled("runDailyReport") != nil {
+		return
+	}
+
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := end.AddDate(0, 0, -1)
+	job := &model.Compliance{
+		Desc:    start.Format("2006-01-02"),
+		Type:    model.ComplianceTypeDaily,
+		StartAt: model.GetMillisForTime(start),
+		EndAt:   model.GetMillisForTime(end),
+	}
+
+	job, err := c.store.Compliance().Save(job)
+	if err != nil {
+		mlog.Error("Failed to save the daily compliance report", mlog.Err(err))
+		return
+	}
+	if appErr := c.RunComplianceJob(job); appErr != nil {
+		mlog.Error("Failed to run the daily compliance report", mlog.String("job_name", job.JobName()), mlog.Err(appErr))
+	}
+}
+
+// RunComplianceJob writes the report for job, moving its status from created to
+// running and then to finished or failed.
+func (c *Compliance) RunComplianceJob(job *model.Compliance) *model.AppError {
+	if appErr := c.checkEnabled("RunComplianceJob"); appErr != nil {
+		return appErr
+	}
+	path := reportDirectory + job.JobName() + ".zip"
+
+	job.Status = model.ComplianceStatusRunning
+	if _, err := c.store.Compliance().Update(job); err != nil {
+		return model.NewAppError("RunComplianceJob", "app.compliance.save.saving.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	count, appErr := c.writeReport(job, path)
+	if appErr != nil {
+		job.Status = model.ComplianceStatusFailed
+		if _, err := c.store.Compliance().Update(job); err != nil {
+			mlog.Warn("Failed to mark the compliance report as failed", mlog.String("job_name", job.JobName()), mlog.Err(err))
+		}
+		return model.NewAppError("RunComplianceJob", "ent.compliance.run_failed.error", map[string]interface{}{"JobName": job.JobName(), "FilePath": path}, appErr.Error(), http.StatusInternalServerError)
+	}
+
+	job.Status = model.ComplianceStatusFinished
+	job.Count = count
+	if _, err := c.store.Compliance().Update(job); err != nil {
+		return model.NewAppError("RunComplianceJob", "app.compliance.save.saving.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	mlog.Info("Compliance report finished", mlog.String("job_name", job.JobName()), mlog.Int("count", count))
+	return nil
+}
+
+// writeReport builds the zip file in a temporary file and then moves it to path in
+// the report file store. It returns the number of posts exported.
+func (c *Compliance) writeReport(job *model.Compliance, path string) (int, *model.AppError) {
+	tmp, err := ioutil.TempFile("", "compliance")
+	if err != nil {
+		return 0, model.NewAppError("writeReport", "ent.compliance.csv.file.creation.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zipFile := zip.NewWriter(tmp)
+	postsFile, err := zipFile.Create(postsFileName)
+	if err != nil {
+		return 0, model.NewAppError("writeReport", "ent.compliance.csv.zip.creation.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	count, appErr := c.writePosts(job, postsFile)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	if err = zipFile.Close(); err != nil {
+		return 0, model.NewAppError("writeReport", "ent.compliance.csv.zip.creation.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, model.NewAppError("writeReport", "ent.compliance.csv.write_file.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	backend, err := filestore.NewFileBackend(filestore.FileBackendSettings{
+		DriverName: model.ImageDriverLocal,
+		Directory:  *c.app.Config().ComplianceSettings.Directory,
+	})
+	if err != nil {
+		return 0, model.NewAppError("writeReport", "ent.compliance.csv.write_file.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+	if _, err = backend.WriteFile(tmp, path); err != nil {
+		return 0, model.NewAppError("writeReport", "ent.compliance.csv.write_file.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return count, nil
+}
+
+// writePosts writes the posts matched by job to w as CSV, in batches of
+// ComplianceSettings.BatchSize.
+func (c *Compliance) writePosts(job *model.Compliance, w io.Writer) (int, *model.AppError) {
+	batchSize := *c.app.Config().ComplianceSettings.BatchSize
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(model.CompliancePostHeader()); err != nil {
+		return 0, model.NewAppError("writePosts", "ent.compliance.csv.header.export.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	count := 0
+	var cursor model.ComplianceExportCursor
+	for !cursor.ChannelsQueryCompleted || !cursor.DirectMessagesQueryCompleted {
+		var posts []*model.CompliancePost
+		var err error
+		posts, cursor, err = c.store.Compliance().ComplianceExport(job, cursor, batchSize)
+		if err != nil {
+			return 0, model.NewAppError("writePosts", "ent.compliance.csv.post.export.appError", nil, err.Error(), http.StatusInternalServerError)
+		}
+
+		for _, post := range posts {
+			if err := writer.Write(post.Row()); err != nil {
+				return 0, model.NewAppError("writePosts", "ent.compliance.csv.post.export.appError", nil, err.Error(), http.StatusInternalServerError)
+			}
+		}
+		count += len(posts)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return 0, model.NewAppError("writePosts", "ent.compliance.csv.write_file.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return count, nil
+}
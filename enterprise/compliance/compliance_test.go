@@ -0,0 +1,187 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package compliance
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store/storetest/mocks"
+)
+
+type mockApp struct {
+	config  *model.Config
+	license *model.License
+}
+
+func (ma *mockApp) Config() *model.Config   { return ma.config }
+func (ma *mockApp) License() *model.License { return ma.license }
+
+type testHelper struct {
+	compliance      *Compliance
+	app             *mockApp
+	complianceStore *mocks.ComplianceStore
+	statuses        []string
+}
+
+func setup(t *testing.T) *testHelper {
+	config := &model.Config{}
+	config.SetDefaults()
+	config.ComplianceSettings.Enable = model.NewBool(true)
+	config.ComplianceSettings.Directory = model.NewString(t.TempDir())
+	config.ComplianceSettings.BatchSize = model.NewInt(2)
+
+	th := &testHelper{
+		app:             &mockApp{config: config, license: model.NewTestLicense("compliance")},
+		complianceStore: &mocks.ComplianceStore{},
+	}
+	mockStore := &mocks.Store{}
+	mockStore.On("Compliance").Return(th.complianceStore)
+	th.compliance = New(th.app, mockStore)
+
+	th.complianceStore.On("Update", mock.AnythingOfType("*model.Compliance")).Return(func(job *model.Compliance) *model.Compliance {
+		th.statuses = append(th.statuses, job.Status)
+		return job
+	}, nil)
+
+	return th
+}
+
+func newJob() *model.Compliance {
+	job := &model.Compliance{
+		Desc:     "legal hold",
+		Type:     model.ComplianceTypeAdhoc,
+		StartAt:  1,
+		EndAt:    model.GetMillis(),
+		Keywords: "contract",
+		Emails:   "alice@example.com",
+	}
+	job.PreSave()
+	return job
+}
+
+func newPost(message string) *model.CompliancePost {
+	return &model.CompliancePost{
+		TeamName:     "team",
+		ChannelName:  "town-square",
+		UserUsername: "alice",
+		UserEmail:    "alice@example.com",
+		PostId:       model.NewId(),
+		PostCreateAt: model.GetMillis(),
+		PostMessage:  message,
+	}
+}
+
+func readReport(t *testing.T, th *testHelper, job *model.Compliance) [][]string {
+	path := filepath.Join(*th.app.config.ComplianceSettings.Directory, reportDirectory, job.JobName()+".zip")
+	reader, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	require.Len(t, reader.File, 1)
+	assert.Equal(t, postsFileName, reader.File[0].Name)
+	file, err := reader.File[0].Open()
+	require.NoError(t, err)
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestRunComplianceJob(t *testing.T) {
+	t.Run("writes the matching posts", func(t *testing.T) {
+		th := setup(t)
+		job := newJob()
+
+		first, second, third := newPost("the contract"), newPost("=contract"), newPost("contract signed")
+		afterFirst := model.ComplianceExportCursor{LastChannelsQueryPostID: second.PostId}
+		done := model.ComplianceExportCursor{ChannelsQueryCompleted: true, DirectMessagesQueryCompleted: true}
+		th.complianceStore.On("ComplianceExport", job, model.ComplianceExportCursor{}, 2).Return([]*model.CompliancePost{first, second}, afterFirst, nil).Once()
+		th.complianceStore.On("ComplianceExport", job, afterFirst, 2).Return([]*model.CompliancePost{third}, done, nil).Once()
+
+		appErr := th.compliance.RunComplianceJob(job)
+		require.Nil(t, appErr)
+
+		assert.Equal(t, []string{model.ComplianceStatusRunning, model.ComplianceStatusFinished}, th.statuses)
+		assert.Equal(t, 3, job.Count)
+
+		records := readReport(t, th, job)
+		require.Len(t, records, 4)
+		assert.Equal(t, model.CompliancePostHeader(), records[0])
+		assert.Equal(t, first.Row(), records[1])
+		assert.Equal(t, "'=contract", records[2][15])
+		assert.Equal(t, third.PostId, records[3][9])
+		th.complianceStore.AssertExpectations(t)
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		th := setup(t)
+		job := newJob()
+		th.complianceStore.On("ComplianceExport", job, mock.Anything, 2).Return(nil, model.ComplianceExportCursor{}, errors.New("connection lost"))
+
+		appErr := th.compliance.RunComplianceJob(job)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.compliance.run_failed.error", appErr.Id)
+		assert.Equal(t, []string{model.ComplianceStatusRunning, model.ComplianceStatusFailed}, th.statuses)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		th := setup(t)
+		th.app.config.ComplianceSettings.Enable = model.NewBool(false)
+
+		appErr := th.compliance.RunComplianceJob(newJob())
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.compliance.licence_disable.app_error", appErr.Id)
+		assert.Empty(t, th.statuses)
+	})
+}
+
+func TestRunDailyReport(t *testing.T) {
+	now := time.Date(2021, time.March, 14, 0, 0, 5, 0, time.Local)
+	done := model.ComplianceExportCursor{ChannelsQueryCompleted: true, DirectMessagesQueryCompleted: true}
+
+	t.Run("daily reports disabled", func(t *testing.T) {
+		th := setup(t)
+		th.compliance.runDailyReport(now)
+		th.complianceStore.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("reports the previous day", func(t *testing.T) {
+		th := setup(t)
+		th.app.config.ComplianceSettings.EnableDaily = model.NewBool(true)
+
+		var saved *model.Compliance
+		th.complianceStore.On("Save", mock.AnythingOfType("*model.Compliance")).Return(func(job *model.Compliance) *model.Compliance {
+			job.PreSave()
+			saved = job
+			return job
+		}, nil).Once()
+		th.complianceStore.On("ComplianceExport", mock.Anything, mock.Anything, 2).Return([]*model.CompliancePost{}, done, nil).Once()
+
+		th.compliance.runDailyReport(now)
+
+		require.NotNil(t, saved)
+		assert.Equal(t, model.ComplianceTypeDaily, saved.Type)
+		assert.Equal(t, "2021-03-13", saved.Desc)
+		assert.Equal(t, model.GetMillisForTime(time.Date(2021, time.March, 13, 0, 0, 0, 0, time.Local)), saved.StartAt)
+		assert.Equal(t, model.GetMillisForTime(time.Date(2021, time.March, 14, 0, 0, 0, 0, time.Local)), saved.EndAt)
+		assert.Equal(t, model.ComplianceStatusFinished, saved.Status)
+		assert.Len(t, readReport(t, th, saved), 1)
+	})
+}
+
+func TestNextMidnight(t *testing.T) {
+	assert.Equal(t, time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), nextMidnight(time.Date(2020, time.December, 31, 23, 59, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2020, time.December, 31, 0, 0, 0, 0, time.UTC), nextMidnight(time.Date(2020, time.December, 30, 0, 0, 0, 0, time.UTC)))
+}
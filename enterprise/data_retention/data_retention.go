@@ -0,0 +1,289 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package data_retention
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	ejobs "github.com/cjdelisle/matterfoss-server/v6/einterfaces/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/plugin"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+// AppIface is the subset of the app layer used by the data retention implementation.
+type AppIface interface {
+	Config() *model.Config
+	License() *model.License
+	RemoveFile(path string) *model.AppError
+	GetPluginsEnvironment() *plugin.Environment
+}
+
+func init() {
+	app.RegisterDataRetentionInterface(func(a *app.App) einterfaces.DataRetentionInterface {
+		return New(a, a.Srv().Store)
+	})
+	app.RegisterJobsDataRetentionJobInterface(func(s *app.Server) ejobs.DataRetentionJobInterface {
+		return &DataRetentionJob{
+			dataRetention: New(app.New(app.ServerConnector(s.Channels())), s.Store),
+			jobServer:     s.Jobs,
+		}
+	})
+}
+
+// DataRetention implements einterfaces.DataRetentionInterface. The global policy is
+// read from DataRetentionSettings while the granular, per-team and per-channel,
+// policies live in the store.
+type DataRetention struct {
+	app   AppIface
+	store store.Store
+}
+
+func New(app AppIface, store store.Store) *DataRetention {
+	return &DataRetention{app: app, store: store}
+}
+
+func (dr *DataRetention) checkLicense(where string) *model.AppError {
+	if license := dr.app.License(); license == nil || !*license.Features.DataRetention {
+		return model.NewAppError(where, "ent.data_retention.generic.license.error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+func storeError(where string, err error) *model.AppError {
+	var nfErr *store.ErrNotFound
+	var invErr *store.ErrInvalidInput
+	switch {
+	case errors.As(err, &nfErr), errors.Is(err, sql.ErrNoRows):
+		return model.NewAppError(where, "ent.data_retention.policies.not_found", nil, err.Error(), http.StatusNotFound)
+	case errors.As(err, &invErr):
+		return model.NewAppError(where, "ent.data_retention.policies.invalid_policy", nil, err.Error(), http.StatusBadRequest)
+	default:
+		return model.NewAppError(where, "ent.data_retention.policies.internal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// validPostDuration reports whether duration is a number of days, or -1 to keep
+// posts forever.
+func validPostDuration(duration int64) bool {
+	return duration > 0 || duration == -1
+}
+
+func validatePolicy(where string, policy *model.RetentionPolicyWithTeamAndChannelIDs) *model.AppError {
+	if policy.DisplayName == "" || policy.PostDuration == nil || !validPostDuration(*policy.PostDuration) {
+		return model.NewAppError(where, "ent.data_retention.policies.invalid_policy", nil, "", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// retentionCutoff is the creation time before which data kept for days is expired.
+func retentionCutoff(now time.Time, days int) int64 {
+	return model.GetMillisForTime(now.AddDate(0, 0, -days))
+}
+
+func (dr *DataRetention) GetGlobalPolicy() (*model.GlobalRetentionPolicy, *model.AppError) {
+	if appErr := dr.checkLicense("GetGlobalPolicy"); appErr != nil {
+		return nil, appErr
+	}
+	settings := dr.app.Config().DataRetentionSettings
+	now := time.Now()
+
+	policy := &model.GlobalRetentionPolicy{
+		MessageDeletionEnabled: *settings.EnableMessageDeletion,
+		FileDeletionEnabled:    *settings.EnableFileDeletion,
+		BoardsDeletionEnabled:  *settings.EnableBoardsDeletion,
+	}
+	if policy.MessageDeletionEnabled {
+		policy.MessageRetentionCutoff = retentionCutoff(now, *settings.MessageRetentionDays)
+	}
+	if policy.FileDeletionEnabled {
+		policy.FileRetentionCutoff = retentionCutoff(now, *settings.FileRetentionDays)
+	}
+	if policy.BoardsDeletionEnabled {
+		policy.BoardsRetentionCutoff = retentionCutoff(now, *settings.BoardsRetentionDays)
+	}
+	return policy, nil
+}
+
+func (dr *DataRetention) GetPolicies(offset, limit int) (*model.RetentionPolicyWithTeamAndChannelCountsList, *model.AppError) {
+	if appErr := dr.checkLicense("GetPolicies"); appErr != nil {
+		return nil, appErr
+	}
+	policies, err := dr.store.RetentionPolicy().GetAll(offset, limit)
+	if err != nil {
+		return nil, storeError("GetPolicies", err)
+	}
+	count, err := dr.store.RetentionPolicy().GetCount()
+	if err != nil {
+		return nil, storeError("GetPolicies", err)
+	}
+	return &model.RetentionPolicyWithTeamAndChannelCountsList{Policies: policies, TotalCount: count}, nil
+}
+
+func (dr *DataRetention) GetPoliciesCount() (int64, *model.AppError) {
+	if appErr := dr.checkLicense("GetPoliciesCount"); appErr != nil {
+		return 0, appErr
+	}
+	count, err := dr.store.RetentionPolicy().GetCount()
+	if err != nil {
+		return 0, storeError("GetPoliciesCount", err)
+	}
+	return count, nil
+}
+
+func (dr *DataRetention) GetPolicy(policyID string) (*model.RetentionPolicyWithTeamAndChannelCounts, *model.AppError) {
+	if appErr := dr.checkLicense("GetPolicy"); appErr != nil {
+		return nil, appErr
+	}
+	policy, err := dr.store.RetentionPolicy().Get(policyID)
+	if err != nil {
+		return nil, storeError("GetPolicy", err)
+	}
+	return policy, nil
+}
+
+func (dr *DataRetention) CreatePolicy(policy *model.RetentionPolicyWithTeamAndChannelIDs) (*model.RetentionPolicyWithTeamAndChannelCounts, *model.AppError) {
+	if appErr := dr.checkLicense("CreatePolicy"); appErr != nil {
+		return nil, appErr
+	}
+	if appErr := validatePolicy("CreatePolicy", policy); appErr != nil {
+		return nil, appErr
+	}
+	saved, err := dr.store.RetentionPolicy().Save(policy)
+	if err != nil {
+		return nil, storeError("CreatePolicy", err)
+	}
+	return saved, nil
+}
+
+// PatchPolicy updates the fields set in patch. An empty DisplayName and a nil
+// PostDuration leave the existing values alone.
+func (dr *DataRetention) PatchPolicy(patch *model.RetentionPolicyWithTeamAndChannelIDs) (*model.RetentionPolicyWithTeamAndChannelCounts, *model.AppError) {
+	if appErr := dr.checkLicense("PatchPolicy"); appErr != nil {
+		return nil, appErr
+	}
+	if patch.PostDuration != nil && !validPostDuration(*patch.PostDuration) {
+		return nil, model.NewAppError("PatchPolicy", "ent.data_retention.policies.invalid_policy", nil, "", http.StatusBadRequest)
+	}
+	policy, err := dr.store.RetentionPolicy().Patch(patch)
+	if err != nil {
+		return nil, storeError("PatchPolicy", err)
+	}
+	return policy, nil
+}
+
+func (dr *DataRetention) DeletePolicy(policyID string) *model.AppError {
+	if appErr := dr.checkLicense("DeletePolicy"); appErr != nil {
+		return appErr
+	}
+	if err := dr.store.RetentionPolicy().Delete(policyID); err != nil {
+		return storeError("DeletePolicy", err)
+	}
+	return nil
+}
+
+func (dr *DataRetention) GetTeamsForPolicy(policyID string, offset, limit int) (*model.TeamsWithCount, *model.AppError) {
+	if appErr := dr.checkLicense("GetTeamsForPolicy"); appErr != nil {
+		return nil, appErr
+	}
+	teams, err := dr.store.RetentionPolicy().GetTeams(policyID, offset, limit)
+	if err != nil {
+		return nil, storeError("GetTeamsForPolicy", err)
+	}
+	count, err := dr.store.RetentionPolicy().GetTeamsCount(policyID)
+	if err != nil {
+		return nil, storeError("GetTeamsForPolicy", err)
+	}
+	return &model.TeamsWithCount{Teams: teams, TotalCount: count}, nil
+}
+
+func (dr *DataRetention) AddTeamsToPolicy(policyID string, teamIDs []string) *model.AppError {
+	if appErr := dr.checkLicense("AddTeamsToPolicy"); appErr != nil {
+		return appErr
+	}
+	if err := dr.store.RetentionPolicy().AddTeams(policyID, teamIDs); err != nil {
+		return storeError("AddTeamsToPolicy", err)
+	}
+	return nil
+}
+
+func (dr *DataRetention) RemoveTeamsFromPolicy(policyID string, teamIDs []string) *model.AppError {
+	if appErr := dr.checkLicense("RemoveTeamsFromPolicy"); appErr != nil {
+		return appErr
+	}
+	if err := dr.store.RetentionPolicy().RemoveTeams(policyID, teamIDs); err != nil {
+		return storeError("RemoveTeamsFromPolicy", err)
+	}
+	return nil
+}
+
+func (dr *DataRetention) GetChannelsForPolicy(policyID string, offset, limit int) (*model.ChannelsWithCount, *model.AppError) {
+	if appErr := dr.checkLicense("GetChannelsForPolicy"); appErr != nil {
+		return nil, appErr
+	}
+	channels, err := dr.store.RetentionPolicy().GetChannels(policyID, offset, limit)
+	if err != nil {
+		return nil, storeError("GetChannelsForPolicy", err)
+	}
+	count, err := dr.store.RetentionPolicy().GetChannelsCount(policyID)
+	if err != nil {
+		return nil, storeError("GetChannelsForPolicy", err)
+	}
+	return &model.ChannelsWithCount{Channels: channels, TotalCount: count}, nil
+}
+
+func (dr *DataRetention) AddChannelsToPolicy(policyID string, channelIDs []string) *model.AppError {
+	if appErr := dr.checkLicense("AddChannelsToPolicy"); appErr != nil {
+		return appErr
+	}
+	if err := dr.store.RetentionPolicy().AddChannels(policyID, channelIDs); err != nil {
+		return storeError("AddChannelsToPolicy", err)
+	}
+	return nil
+}
+
+func (dr *DataRetention) RemoveChannelsFromPolicy(policyID string, channelIDs []string) *model.AppError {
+	if appErr := dr.checkLicense("RemoveChannelsFromPolicy"); appErr != nil {
+		return appErr
+	}
+	if err := dr.store.RetentionPolicy().RemoveChannels(policyID, channelIDs); err != nil {
+		return storeError("RemoveChannelsFromPolicy", err)
+	}
+	return nil
+}
+
+func (dr *DataRetention) GetTeamPoliciesForUser(userID string, offset, limit int) (*model.RetentionPolicyForTeamList, *model.AppError) {
+	if appErr := dr.checkLicense("GetTeamPoliciesForUser"); appErr != nil {
+		return nil, appErr
+	}
+	policies, err := dr.store.RetentionPolicy().GetTeamPoliciesForUser(userID, offset, limit)
+	if err != nil {
+		return nil, storeError("GetTeamPoliciesForUser", err)
+	}
+	count, err := dr.store.RetentionPolicy().GetTeamPoliciesCountForUser(userID)
+	if err != nil {
+		return nil, storeError("GetTeamPoliciesForUser", err)
+	}
+	return &model.RetentionPolicyForTeamList{Policies: policies, TotalCount: count}, nil
+}
+
+func (dr *DataRetention) GetChannelPoliciesForUser(userID string, offset, limit int) (*model.RetentionPolicyForChannelList, *model.AppError) {
+	if appErr := dr.checkLicense("GetChannelPoliciesForUser"); appErr != nil {
+		return nil, appErr
+	}
+	policies, err := dr.store.RetentionPolicy().GetChannelPoliciesForUser(userID, offset, limit)
+	if err != nil {
+		return nil, storeError("GetChannelPoliciesForUser", err)
+	}
+	count, err := dr.store.RetentionPolicy().GetChannelPoliciesCountForUser(userID)
+	if err != nil {
+		return nil, storeError("GetChannelPoliciesForUser", err)
+	}
+	return &model.RetentionPolicyForChannelList{Policies: policies, TotalCount: count}, nil
+}
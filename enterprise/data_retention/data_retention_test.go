@@ -0,0 +1,192 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package data_retention
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/plugin"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+	"github.com/cjdelisle/matterfoss-server/v6/store/storetest/mocks"
+)
+
+// mockApp records the files removed from the file store.
+type mockApp struct {
+	config       *model.Config
+	license      *model.License
+	removedFiles []string
+}
+
+func newMockApp() *mockApp {
+	config := &model.Config{}
+	config.SetDefaults()
+	return &mockApp{config: config, license: model.NewTestLicense("data_retention")}
+}
+
+func (ma *mockApp) Config() *model.Config                      { return ma.config }
+func (ma *mockApp) License() *model.License                    { return ma.license }
+func (ma *mockApp) GetPluginsEnvironment() *plugin.Environment { return nil }
+
+func (ma *mockApp) RemoveFile(path string) *model.AppError {
+	ma.removedFiles = append(ma.removedFiles, path)
+	return nil
+}
+
+func newTestDataRetention() (*DataRetention, *mockApp, *mocks.Store, *mocks.RetentionPolicyStore) {
+	ma := newMockApp()
+	policyStore := &mocks.RetentionPolicyStore{}
+	mockStore := &mocks.Store{}
+	mockStore.On("RetentionPolicy").Return(policyStore)
+	return New(ma, mockStore), ma, mockStore, policyStore
+}
+
+func TestLicense(t *testing.T) {
+	dr, ma, _, _ := newTestDataRetention()
+	ma.license.Features.DataRetention = model.NewBool(false)
+
+	_, appErr := dr.GetGlobalPolicy()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.data_retention.generic.license.error", appErr.Id)
+	assert.Equal(t, http.StatusNotImplemented, appErr.StatusCode)
+
+	ma.license = nil
+	appErr = dr.DeletePolicy(model.NewId())
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.data_retention.generic.license.error", appErr.Id)
+}
+
+func TestGetGlobalPolicy(t *testing.T) {
+	dr, ma, _, _ := newTestDataRetention()
+
+	t.Run("disabled", func(t *testing.T) {
+		policy, appErr := dr.GetGlobalPolicy()
+		require.Nil(t, appErr)
+		assert.Equal(t, &model.GlobalRetentionPolicy{}, policy)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		ma.config.DataRetentionSettings.EnableMessageDeletion = model.NewBool(true)
+		ma.config.DataRetentionSettings.MessageRetentionDays = model.NewInt(10)
+		ma.config.DataRetentionSettings.EnableFileDeletion = model.NewBool(true)
+		ma.config.DataRetentionSettings.FileRetentionDays = model.NewInt(1)
+
+		before := model.GetMillisForTime(time.Now().AddDate(0, 0, -10))
+		policy, appErr := dr.GetGlobalPolicy()
+		require.Nil(t, appErr)
+		after := model.GetMillisForTime(time.Now().AddDate(0, 0, -10))
+
+		assert.True(t, policy.MessageDeletionEnabled)
+		assert.True(t, policy.FileDeletionEnabled)
+		assert.False(t, policy.BoardsDeletionEnabled)
+		assert.True(t, policy.MessageRetentionCutoff >= before && policy.MessageRetentionCutoff <= after)
+		assert.Greater(t, policy.FileRetentionCutoff, policy.MessageRetentionCutoff)
+		assert.Zero(t, policy.BoardsRetentionCutoff)
+	})
+}
+
+func TestCreatePolicy(t *testing.T) {
+	dr, _, _, policyStore := newTestDataRetention()
+
+	for name, policy := range map[string]model.RetentionPolicy{
+		"no display name":    {PostDuration: model.NewInt64(10)},
+		"no post duration":   {DisplayName: "policy"},
+		"zero post duration": {DisplayName: "policy", PostDuration: model.NewInt64(0)},
+		"negative duration":  {DisplayName: "policy", PostDuration: model.NewInt64(-2)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, appErr := dr.CreatePolicy(&model.RetentionPolicyWithTeamAndChannelIDs{RetentionPolicy: policy})
+			require.NotNil(t, appErr)
+			assert.Equal(t, "ent.data_retention.policies.invalid_policy", appErr.Id)
+			assert.Equal(t, http.StatusBadRequest, appErr.StatusCode)
+		})
+	}
+
+	t.Run("keep forever", func(t *testing.T) {
+		policy := &model.RetentionPolicyWithTeamAndChannelIDs{
+			RetentionPolicy: model.RetentionPolicy{DisplayName: "forever", PostDuration: model.NewInt64(-1)},
+			TeamIDs:         []string{model.NewId()},
+		}
+		saved := &model.RetentionPolicyWithTeamAndChannelCounts{RetentionPolicy: policy.RetentionPolicy, TeamCount: 1}
+		policyStore.On("Save", policy).Return(saved, nil).Once()
+
+		result, appErr := dr.CreatePolicy(policy)
+		require.Nil(t, appErr)
+		assert.Equal(t, saved, result)
+	})
+
+	t.Run("unknown team", func(t *testing.T) {
+		policy := &model.RetentionPolicyWithTeamAndChannelIDs{
+			RetentionPolicy: model.RetentionPolicy{DisplayName: "policy", PostDuration: model.NewInt64(30)},
+			TeamIDs:         []string{model.NewId()},
+		}
+		policyStore.On("Save", policy).Return(nil, store.NewErrNotFound("Team", policy.TeamIDs[0])).Once()
+
+		_, appErr := dr.CreatePolicy(policy)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.data_retention.policies.not_found", appErr.Id)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+}
+
+func TestPatchPolicy(t *testing.T) {
+	dr, _, _, policyStore := newTestDataRetention()
+
+	_, appErr := dr.PatchPolicy(&model.RetentionPolicyWithTeamAndChannelIDs{
+		RetentionPolicy: model.RetentionPolicy{ID: model.NewId(), PostDuration: model.NewInt64(0)},
+	})
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.data_retention.policies.invalid_policy", appErr.Id)
+
+	patch := &model.RetentionPolicyWithTeamAndChannelIDs{
+		RetentionPolicy: model.RetentionPolicy{ID: model.NewId()},
+		ChannelIDs:      []string{model.NewId()},
+	}
+	patched := &model.RetentionPolicyWithTeamAndChannelCounts{ChannelCount: 1}
+	policyStore.On("Patch", patch).Return(patched, nil).Once()
+
+	result, appErr := dr.PatchPolicy(patch)
+	require.Nil(t, appErr)
+	assert.Equal(t, patched, result)
+}
+
+func TestStoreErrors(t *testing.T) {
+	dr, _, _, policyStore := newTestDataRetention()
+
+	policyStore.On("Get", "missing").Return(nil, sql.ErrNoRows)
+	_, appErr := dr.GetPolicy("missing")
+	require.NotNil(t, appErr)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+	policyStore.On("Delete", "missing").Return(store.NewErrNotFound("RetentionPolicy", "missing"))
+	appErr = dr.DeletePolicy("missing")
+	require.NotNil(t, appErr)
+	assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+
+	policyStore.On("GetCount").Return(int64(0), errors.New("connection lost"))
+	_, appErr = dr.GetPoliciesCount()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.data_retention.policies.internal_error", appErr.Id)
+	assert.Equal(t, http.StatusInternalServerError, appErr.StatusCode)
+}
+
+func TestGetTeamsForPolicy(t *testing.T) {
+	dr, _, _, policyStore := newTestDataRetention()
+	policyID := model.NewId()
+	teams := []*model.Team{{Id: model.NewId()}, {Id: model.NewId()}}
+
+	policyStore.On("GetTeams", policyID, 0, 2).Return(teams, nil)
+	policyStore.On("GetTeamsCount", policyID).Return(int64(5), nil)
+
+	result, appErr := dr.GetTeamsForPolicy(policyID, 0, 2)
+	require.Nil(t, appErr)
+	assert.Equal(t, &model.TeamsWithCount{Teams: teams, TotalCount: 5}, result)
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package data_retention
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cjdelisle/matterfoss-server/v6/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/plugin"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const jobName = "DataRetention"
+
+// DataRetentionJob implements ejobs.DataRetentionJobInterface.
+type DataRetentionJob struct {
+	dataRetention *DataRetention
+	jobServer     *jobs.JobServer
+}
+
+// isEnabled does not look at the global policy settings: granular policies are
+// enforced even when global message and file deletion are turned off.
+func (j *DataRetentionJob) isEnabled(cfg *model.Config) bool {
+	license := j.dataRetention.app.License()
+	return license != nil && *license.Features.DataRetention
+}
+
+func (j *DataRetentionJob) MakeWorker() model.Worker {
+	execute := func(job *model.Job) error {
+		progress := func(result *RunResult, percent int64) {
+			if job.Data == nil {
+				job.Data = make(map[string]string)
+			}
+			for key, value := range result.data() {
+				job.Data[key] = value
+			}
+			job.Progress = percent
+			if appErr := j.jobServer.UpdateInProgressJobData(job); appErr != nil {
+				mlog.Warn("Worker: Failed to save the data retention progress", mlog.String("worker", model.JobTypeDataRetention), mlog.String("job_id", job.Id), mlog.Err(appErr))
+			}
+		}
+
+		if _, appErr := j.dataRetention.Run(time.Now(), progress); appErr != nil {
+			mlog.Error("Worker: Failed to run data retention", mlog.String("worker", model.JobTypeDataRetention), mlog.String("job_id", job.Id), mlog.Err(appErr))
+			return appErr
+		}
+		return nil
+	}
+	return jobs.NewSimpleWorker(jobName, j.jobServer, execute, j.isEnabled)
+}
+
+func (j *DataRetentionJob) MakeScheduler() model.Scheduler {
+	startTime := func(cfg *model.Config) *time.Time {
+		scheduled, err := time.Parse("15:04", *cfg.DataRetentionSettings.DeletionJobStartTime)
+		if err != nil {
+			mlog.Error("Cannot schedule the data retention job", mlog.String("deletion_job_start_time", *cfg.DataRetentionSettings.DeletionJobStartTime), mlog.Err(err))
+			return nil
+		}
+		return &scheduled
+	}
+	return jobs.NewDailyScheduler(j.jobServer, model.JobTypeDataRetention, startTime, j.isEnabled)
+}
+
+// RunResult counts what a run of the data retention job deleted.
+type RunResult struct {
+	Posts                int64
+	PostRevisions        int64
+	ChannelMemberHistory int64
+	Threads              int64
+	ThreadMemberships    int64
+	OrphanedRows         int64
+	Files                int64
+	Plugins              int64
+}
+
+func (r *RunResult) data() map[string]string {
+	return map[string]string{
+		"posts_deleted":                  strconv.FormatInt(r.Posts, 10),
+		"post_revisions_deleted":         strconv.FormatInt(r.PostRevisions, 10),
+		"channel_member_history_deleted": strconv.FormatInt(r.ChannelMemberHistory, 10),
+		"threads_deleted":                strconv.FormatInt(r.Threads, 10),
+		"thread_memberships_deleted":     strconv.FormatInt(r.ThreadMemberships, 10),
+		"orphaned_rows_deleted":          strconv.FormatInt(r.OrphanedRows, 10),
+		"files_deleted":                  strconv.FormatInt(r.Files, 10),
+		"plugin_rows_deleted":            strconv.FormatInt(r.Plugins, 10),
+	}
+}
+
+// Run applies the global and granular policies as of now, deleting in batches of
+// DataRetentionSettings.BatchSize. progress, if not nil, is called after each step
+// with the counts so far and the percentage of steps completed.
+func (dr *DataRetention) Run(now time.Time, progress func(result *RunResult, percent int64)) (*RunResult, *model.AppError) {
+	if appErr := dr.checkLicense("Run"); appErr != nil {
+		return nil, appErr
+	}
+	settings := dr.app.Config().DataRetentionSettings
+	batchSize := *settings.BatchSize
+	nowMillis := model.GetMillisForTime(now)
+
+	// A zero end time disables the global policy in the store.
+	var messageCutoff, fileCutoff int64
+	if *settings.EnableMessageDeletion {
+		messageCutoff = retentionCutoff(now, *settings.MessageRetentionDays)
+	}
+	if *settings.EnableFileDeletion {
+		fileCutoff = retentionCutoff(now, *settings.FileRetentionDays)
+	}
+
+	result := &RunResult{}
+	steps := []struct {
+		name string
+		run  func() (int64, error)
+		add  *int64
+	}{
+		{"posts", func() (int64, error) {
+			return deleteForPolicies(func(cursor model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error) {
+				return dr.store.Post().PermanentDeleteBatchForRetentionPolicies(nowMillis, messageCutoff, int64(batchSize), cursor)
+			})
+		}, &result.Posts},
+		{"post_revisions", func() (int64, error) {
+			return deleteForPolicies(func(cursor model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error) {
+				return dr.store.PostRevision().PermanentDeleteBatchForRetentionPolicies(nowMillis, messageCutoff, int64(batchSize), cursor)
+			})
+		}, &result.PostRevisions},
+		{"channel_member_history", func() (int64, error) {
+			return deleteForPolicies(func(cursor model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error) {
+				return dr.store.ChannelMemberHistory().PermanentDeleteBatchForRetentionPolicies(nowMillis, messageCutoff, int64(batchSize), cursor)
+			})
+		}, &result.ChannelMemberHistory},
+		{"threads", func() (int64, error) {
+			return deleteForPolicies(func(cursor model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error) {
+				return dr.store.Thread().PermanentDeleteBatchForRetentionPolicies(nowMillis, messageCutoff, int64(batchSize), cursor)
+			})
+		}, &result.Threads},
+		{"thread_memberships", func() (int64, error) {
+			return deleteForPolicies(func(cursor model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error) {
+				return dr.store.Thread().PermanentDeleteBatchThreadMembershipsForRetentionPolicies(nowMillis, messageCutoff, int64(batchSize), cursor)
+			})
+		}, &result.ThreadMemberships},
+		{"orphaned_rows", func() (int64, error) {
+			return dr.deleteOrphanedRows(batchSize)
+		}, &result.OrphanedRows},
+		{"files", func() (int64, error) {
+			return dr.deleteFiles(fileCutoff, batchSize)
+		}, &result.Files},
+		{"plugins", func() (int64, error) {
+			return dr.runPlugins(nowMillis, int64(batchSize)), nil
+		}, &result.Plugins},
+	}
+
+	for i, step := range steps {
+		deleted, err := step.run()
+		*step.add += deleted
+		if err != nil {
+			return result, model.NewAppError("Run", "ent.data_retention.run_failed.error", nil, step.name+": "+err.Error(), http.StatusInternalServerError)
+		}
+		if progress != nil {
+			progress(result, int64((i+1)*100/len(steps)))
+		}
+	}
+
+	return result, nil
+}
+
+// deleteForPolicies calls batch until the channel, team and global policies have all
+// been applied.
+func deleteForPolicies(batch func(cursor model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error)) (int64, error) {
+	var total int64
+	var cursor model.RetentionPolicyCursor
+	for !cursor.ChannelPoliciesDone || !cursor.TeamPoliciesDone || !cursor.GlobalPoliciesDone {
+		deleted, next, err := batch(cursor)
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+		cursor = next
+	}
+	return total, nil
+}
+
+// deleteOrphanedRows removes the rows which refer to posts or channels that are gone.
+// Posts come first so that the rows they leave behind are cleaned up in the same run.
+func (dr *DataRetention) deleteOrphanedRows(batchSize int) (int64, error) {
+	batches := []func(limit int) (int64, error){
+		dr.store.Post().DeleteOrphanedRows,
+		dr.store.PostRevision().DeleteOrphanedRows,
+		dr.store.PollVote().DeleteOrphanedRows,
+		dr.store.ChannelMemberHistory().DeleteOrphanedRows,
+		dr.store.Thread().DeleteOrphanedRows,
+		dr.store.Reaction().DeleteOrphanedRows,
+		dr.store.Preference().DeleteOrphanedRows,
+		dr.store.RetentionPolicy().DeleteOrphanedRows,
+	}
+
+	var total int64
+	for _, batch := range batches {
+		for {
+			deleted, err := batch(batchSize)
+			if err != nil {
+				return total, err
+			}
+			total += deleted
+			if deleted < int64(batchSize) {
+				break
+			}
+		}
+	}
+	return total, nil
+}
+
+// deleteFiles removes the files older than cutoff, when the global policy deletes
+// files, then those whose post has been deleted by a policy.
+func (dr *DataRetention) deleteFiles(cutoff int64, batchSize int) (int64, error) {
+	var total int64
+
+	for cutoff > 0 {
+		infos, err := dr.store.FileInfo().GetExpiredBatch(cutoff, batchSize)
+		if err != nil {
+			return total, err
+		}
+		for _, info := range infos {
+			if err := dr.deleteFile(info); err != nil {
+				return total, err
+			}
+		}
+		total += int64(len(infos))
+		if len(infos) < batchSize {
+			break
+		}
+	}
+
+	for {
+		infos, err := dr.store.FileInfo().GetOrphanedBatch(batchSize)
+		if err != nil {
+			return total, err
+		}
+		for _, info := range infos {
+			if err := dr.deleteFile(info); err != nil {
+				return total, err
+			}
+		}
+		total += int64(len(infos))
+		if len(infos) < batchSize {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+// deleteFile removes a file, with its thumbnail and preview, from the file store and
+// then its FileInfo. A file which cannot be removed from the file store is left
+// behind rather than blocking the job.
+func (dr *DataRetention) deleteFile(info *model.FileInfo) error {
+	for _, path := range []string{info.Path, info.ThumbnailPath, info.PreviewPath} {
+		if path == "" {
+			continue
+		}
+		if appErr := dr.app.RemoveFile(path); appErr != nil {
+			mlog.Warn("Failed to remove a file for data retention", mlog.String("file_id", info.Id), mlog.String("path", path), mlog.Err(appErr))
+		}
+	}
+	return dr.store.FileInfo().PermanentDelete(info.Id)
+}
+
+// runPlugins lets plugins apply the global policy to their own data.
+func (dr *DataRetention) runPlugins(nowMillis, batchSize int64) int64 {
+	pluginsEnvironment := dr.app.GetPluginsEnvironment()
+	if pluginsEnvironment == nil {
+		return 0
+	}
+
+	var total int64
+	pluginsEnvironment.RunMultiPluginHook(func(hooks plugin.Hooks) bool {
+		deleted, err := hooks.RunDataRetention(nowMillis, batchSize)
+		if err != nil {
+			mlog.Error("Plugin failed to run data retention", mlog.Err(err))
+		}
+		total += deleted
+		return true
+	}, plugin.RunDataRetentionID)
+	return total
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package data_retention
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store/storetest/mocks"
+)
+
+type runStores struct {
+	post                 *mocks.PostStore
+	postRevision         *mocks.PostRevisionStore
+	pollVote             *mocks.PollVoteStore
+	channelMemberHistory *mocks.ChannelMemberHistoryStore
+	thread               *mocks.ThreadStore
+	reaction             *mocks.ReactionStore
+	preference           *mocks.PreferenceStore
+	fileInfo             *mocks.FileInfoStore
+}
+
+// newRunStores mocks a store in which nothing is left to delete; tests override the
+// parts they are interested in.
+func newRunStores(mockStore *mocks.Store, policyStore *mocks.RetentionPolicyStore) *runStores {
+	s := &runStores{
+		post:                 &mocks.PostStore{},
+		postRevision:         &mocks.PostRevisionStore{},
+		pollVote:             &mocks.PollVoteStore{},
+		channelMemberHistory: &mocks.ChannelMemberHistoryStore{},
+		thread:               &mocks.ThreadStore{},
+		reaction:             &mocks.ReactionStore{},
+		preference:           &mocks.PreferenceStore{},
+		fileInfo:             &mocks.FileInfoStore{},
+	}
+	mockStore.On("Post").Return(s.post)
+	mockStore.On("PostRevision").Return(s.postRevision)
+	mockStore.On("PollVote").Return(s.pollVote)
+	mockStore.On("ChannelMemberHistory").Return(s.channelMemberHistory)
+	mockStore.On("Thread").Return(s.thread)
+	mockStore.On("Reaction").Return(s.reaction)
+	mockStore.On("Preference").Return(s.preference)
+	mockStore.On("FileInfo").Return(s.fileInfo)
+
+	done := model.RetentionPolicyCursor{ChannelPoliciesDone: true, TeamPoliciesDone: true, GlobalPoliciesDone: true}
+	s.postRevision.On("PermanentDeleteBatchForRetentionPolicies", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(0), done, nil)
+	s.channelMemberHistory.On("PermanentDeleteBatchForRetentionPolicies", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(0), done, nil)
+	s.thread.On("PermanentDeleteBatchForRetentionPolicies", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(0), done, nil)
+	s.thread.On("PermanentDeleteBatchThreadMembershipsForRetentionPolicies", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(0), done, nil)
+
+	s.post.On("DeleteOrphanedRows", mock.Anything).Return(int64(0), nil)
+	s.postRevision.On("DeleteOrphanedRows", mock.Anything).Return(int64(0), nil)
+	s.pollVote.On("DeleteOrphanedRows", mock.Anything).Return(int64(0), nil)
+	s.channelMemberHistory.On("DeleteOrphanedRows", mock.Anything).Return(int64(0), nil)
+	s.thread.On("DeleteOrphanedRows", mock.Anything).Return(int64(0), nil)
+	s.reaction.On("DeleteOrphanedRows", mock.Anything).Return(int64(0), nil)
+	s.preference.On("DeleteOrphanedRows", mock.Anything).Return(int64(0), nil)
+	policyStore.On("DeleteOrphanedRows", mock.Anything).Return(int64(0), nil)
+
+	return s
+}
+
+func TestRun(t *testing.T) {
+	dr, ma, mockStore, policyStore := newTestDataRetention()
+	stores := newRunStores(mockStore, policyStore)
+
+	settings := &ma.config.DataRetentionSettings
+	settings.EnableMessageDeletion = model.NewBool(true)
+	settings.MessageRetentionDays = model.NewInt(30)
+	settings.EnableFileDeletion = model.NewBool(true)
+	settings.FileRetentionDays = model.NewInt(7)
+	settings.BatchSize = model.NewInt(2)
+
+	now := time.Now()
+	nowMillis := model.GetMillisForTime(now)
+	messageCutoff := model.GetMillisForTime(now.AddDate(0, 0, -30))
+	fileCutoff := model.GetMillisForTime(now.AddDate(0, 0, -7))
+
+	// Posts are deleted in batches until the store reports every policy as done.
+	first := model.RetentionPolicyCursor{}
+	second := model.RetentionPolicyCursor{ChannelPoliciesDone: true}
+	done := model.RetentionPolicyCursor{ChannelPoliciesDone: true, TeamPoliciesDone: true, GlobalPoliciesDone: true}
+	stores.post.On("PermanentDeleteBatchForRetentionPolicies", nowMillis, messageCutoff, int64(2), first).Return(int64(2), second, nil).Once()
+	stores.post.On("PermanentDeleteBatchForRetentionPolicies", nowMillis, messageCutoff, int64(2), second).Return(int64(1), done, nil).Once()
+
+	// Revisions follow the same policies as the posts.
+	stores.postRevision.ExpectedCalls = nil
+	stores.postRevision.On("PermanentDeleteBatchForRetentionPolicies", nowMillis, messageCutoff, int64(2), first).Return(int64(1), done, nil).Once()
+	stores.postRevision.On("DeleteOrphanedRows", 2).Return(int64(0), nil).Once()
+
+	// A full batch of orphaned reactions is followed by another attempt.
+	stores.reaction.ExpectedCalls = nil
+	stores.reaction.On("DeleteOrphanedRows", 2).Return(int64(2), nil).Once()
+	stores.reaction.On("DeleteOrphanedRows", 2).Return(int64(0), nil).Once()
+
+	expired := &model.FileInfo{Id: model.NewId(), CreateAt: fileCutoff - 1, Path: "expired", ThumbnailPath: "expired_thumb", PreviewPath: "expired_preview"}
+	orphaned := &model.FileInfo{Id: model.NewId(), CreateAt: nowMillis, Path: "orphaned"}
+	stores.fileInfo.On("GetExpiredBatch", fileCutoff, 2).Return([]*model.FileInfo{expired}, nil).Once()
+	stores.fileInfo.On("GetOrphanedBatch", 2).Return([]*model.FileInfo{orphaned}, nil).Once()
+	stores.fileInfo.On("PermanentDelete", expired.Id).Return(nil).Once()
+	stores.fileInfo.On("PermanentDelete", orphaned.Id).Return(nil).Once()
+
+	var percents []int64
+	result, appErr := dr.Run(now, func(result *RunResult, percent int64) {
+		percents = append(percents, percent)
+	})
+	require.Nil(t, appErr)
+
+	assert.Equal(t, &RunResult{Posts: 3, PostRevisions: 1, OrphanedRows: 2, Files: 2}, result)
+	assert.Equal(t, []string{"expired", "expired_thumb", "expired_preview", "orphaned"}, ma.removedFiles)
+	assert.Len(t, percents, 8)
+	assert.Equal(t, int64(100), percents[len(percents)-1])
+	assert.Equal(t, "3", result.data()["posts_deleted"])
+	assert.Equal(t, "1", result.data()["post_revisions_deleted"])
+
+	stores.post.AssertExpectations(t)
+	stores.postRevision.AssertExpectations(t)
+	stores.reaction.AssertExpectations(t)
+	stores.fileInfo.AssertExpectations(t)
+}
+
+func TestRunGranularOnly(t *testing.T) {
+	dr, _, mockStore, policyStore := newTestDataRetention()
+	stores := newRunStores(mockStore, policyStore)
+
+	// With the global policy disabled, the store is given a zero end time and files
+	// are only looked at if orphaned.
+	done := model.RetentionPolicyCursor{ChannelPoliciesDone: true, TeamPoliciesDone: true, GlobalPoliciesDone: true}
+	stores.post.On("PermanentDeleteBatchForRetentionPolicies", mock.Anything, int64(0), mock.Anything, model.RetentionPolicyCursor{}).Return(int64(0), done, nil).Once()
+	stores.fileInfo.On("GetOrphanedBatch", mock.Anything).Return([]*model.FileInfo{}, nil).Once()
+
+	result, appErr := dr.Run(time.Now(), nil)
+	require.Nil(t, appErr)
+	assert.Equal(t, &RunResult{}, result)
+
+	stores.post.AssertExpectations(t)
+	stores.fileInfo.AssertNotCalled(t, "GetWithOptions", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunError(t *testing.T) {
+	dr, _, mockStore, policyStore := newTestDataRetention()
+	stores := newRunStores(mockStore, policyStore)
+
+	stores.post.On("PermanentDeleteBatchForRetentionPolicies", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(0), model.RetentionPolicyCursor{}, errors.New("connection lost"))
+
+	_, appErr := dr.Run(time.Now(), func(*RunResult, int64) {
+		require.Fail(t, "progress reported for a failed step")
+	})
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.data_retention.run_failed.error", appErr.Id)
+}
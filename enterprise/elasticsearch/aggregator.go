@@ -0,0 +1,165 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"gopkg.in/olivere/elastic.v6"
+
+	"github.com/cjdelisle/matterfoss-server/v6/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// reindexPollInterval is the delay between two checks of a reindex task.
+var reindexPollInterval = 10 * time.Second
+
+// AggregatorJob implements ejobs.ElasticsearchAggregatorInterface. Every day it
+// merges the daily post indexes older than AggregatePostsAfterDays into monthly
+// indexes, keeping the number of indexes, and of shards, in the cluster bounded.
+type AggregatorJob struct {
+	engine    *ElasticsearchInterfaceImpl
+	jobServer *jobs.JobServer
+}
+
+func (j *AggregatorJob) isEnabled(cfg *model.Config) bool {
+	license := j.engine.server.License()
+	return license != nil && *license.Features.Elasticsearch && *cfg.ElasticsearchSettings.EnableIndexing
+}
+
+func (j *AggregatorJob) MakeWorker() model.Worker {
+	if j.engine == nil {
+		return nil
+	}
+	execute := func(job *model.Job) error {
+		if appErr := j.engine.AggregatePostIndexes(time.Now()); appErr != nil {
+			mlog.Error("Worker: Failed to aggregate the post indexes", mlog.String("worker", model.JobTypeElasticsearchPostAggregation), mlog.String("job_id", job.Id), mlog.Err(appErr))
+			return appErr
+		}
+		return nil
+	}
+	return jobs.NewSimpleWorker("ElasticsearchAggregator", j.jobServer, execute, j.isEnabled)
+}
+
+func (j *AggregatorJob) MakeScheduler() model.Scheduler {
+	if j.engine == nil {
+		return nil
+	}
+	startTime := func(cfg *model.Config) *time.Time {
+		scheduled, err := time.Parse("15:04", *cfg.ElasticsearchSettings.PostsAggregatorJobStartTime)
+		if err != nil {
+			mlog.Error("Cannot schedule the post aggregation job", mlog.String("posts_aggregator_job_start_time", *cfg.ElasticsearchSettings.PostsAggregatorJobStartTime), mlog.Err(err))
+			return nil
+		}
+		return &scheduled
+	}
+	return jobs.NewDailyScheduler(j.jobServer, model.JobTypeElasticsearchPostAggregation, startTime, j.isEnabled)
+}
+
+// AggregatePostIndexes merges the daily post indexes which are entirely older
+// than AggregatePostsAfterDays days into the index of their month, then deletes
+// them. Posts updated since their day crossed the cutoff have already been
+// written to the monthly index, and the copy there is kept.
+func (es *ElasticsearchInterfaceImpl) AggregatePostIndexes(now time.Time) *model.AppError {
+	// The reindexing can take long, so the mutex is not held while waiting for it:
+	// stopping the engine meanwhile makes the next request fail.
+	es.mutex.RLock()
+	if appErr := es.checkStarted("Elasticsearch.AggregatePostIndexes"); appErr != nil {
+		es.mutex.RUnlock()
+		return appErr
+	}
+	client := es.client
+	names, err := es.indexes(PostIndex + "_*")
+	es.mutex.RUnlock()
+	if err != nil {
+		return model.NewAppError("Elasticsearch.AggregatePostIndexes", "ent.elasticsearch.aggregator_worker.get_indexes.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	settings := es.settings()
+	cutoff := aggregationCutoff(now, *settings.AggregatePostsAfterDays)
+	dailyIndexes := map[string][]string{}
+	for _, name := range names {
+		postIndex, ok := parsePostIndexName(*settings.IndexPrefix, name)
+		if !ok || !postIndex.Daily || postIndex.End.After(cutoff) {
+			continue
+		}
+		monthlyIndex := es.indexName(PostIndex + "_" + postIndex.Start.Format(monthlyIndexLayout))
+		dailyIndexes[monthlyIndex] = append(dailyIndexes[monthlyIndex], name)
+	}
+
+	monthlyIndexes := make([]string, 0, len(dailyIndexes))
+	for monthlyIndex := range dailyIndexes {
+		monthlyIndexes = append(monthlyIndexes, monthlyIndex)
+	}
+	sort.Strings(monthlyIndexes)
+
+	for _, monthlyIndex := range monthlyIndexes {
+		sources := dailyIndexes[monthlyIndex]
+		sort.Strings(sources)
+		if err := reindex(client, sources, monthlyIndex); err != nil {
+			return model.NewAppError("Elasticsearch.AggregatePostIndexes", "ent.elasticsearch.aggregator_worker.index_job_failed.error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		if _, err := client.DeleteIndex(sources...).Do(context.Background()); err != nil {
+			return model.NewAppError("Elasticsearch.AggregatePostIndexes", "ent.elasticsearch.aggregator_worker.delete_indexes.error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		mlog.Info("Aggregated the daily post indexes", mlog.String("index", monthlyIndex), mlog.Int("daily_indexes", len(sources)))
+	}
+
+	return nil
+}
+
+// reindexTask is the status of a task, as returned by the tasks API.
+type reindexTask struct {
+	Completed bool                  `json:"completed"`
+	Error     *elastic.ErrorDetails `json:"error"`
+	Response  struct {
+		Failures []json.RawMessage `json:"failures"`
+	} `json:"response"`
+}
+
+// reindex copies the documents of sources missing from destination, as a
+// background task of the server which is polled until it completes.
+func reindex(client *elastic.Client, sources []string, destination string) error {
+	started, err := client.Reindex().
+		Source(elastic.NewReindexSource().Index(sources...)).
+		Destination(elastic.NewReindexDestination().Index(destination).OpType("create")).
+		ProceedOnVersionConflict().
+		DoAsync(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for {
+		response, err := client.PerformRequest(context.Background(), elastic.PerformRequestOptions{
+			Method: "GET",
+			Path:   "/_tasks/" + started.TaskId,
+		})
+		if err != nil {
+			return err
+		}
+		var task reindexTask
+		if err := json.Unmarshal(response.Body, &task); err != nil {
+			return err
+		}
+
+		if task.Completed {
+			if task.Error != nil {
+				return errors.New(task.Error.Reason)
+			}
+			if len(task.Response.Failures) > 0 {
+				return fmt.Errorf("%d documents failed, first failure: %s", len(task.Response.Failures), task.Response.Failures[0])
+			}
+			return nil
+		}
+
+		time.Sleep(reindexPollInterval)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package elasticsearch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestPostIndexName(t *testing.T) {
+	now := time.Date(2021, 3, 15, 10, 30, 0, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		created  time.Time
+		expected string
+	}{
+		"today":            {time.Date(2021, 3, 15, 9, 0, 0, 0, time.UTC), "prefix_posts_2021_03_15"},
+		"last daily index": {time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), "prefix_posts_2021_03_01"},
+		"aggregated":       {time.Date(2021, 2, 28, 23, 59, 0, 0, time.UTC), "prefix_posts_2021_02"},
+		"last year":        {time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC), "prefix_posts_2020_12"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, postIndexName("prefix_", model.GetMillisForTime(tc.created), 14, now))
+		})
+	}
+}
+
+func TestParsePostIndexName(t *testing.T) {
+	index, ok := parsePostIndexName("prefix_", "prefix_posts_2021_02_28")
+	require.True(t, ok)
+	assert.True(t, index.Daily)
+	assert.Equal(t, time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC), index.Start)
+	assert.Equal(t, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), index.End)
+
+	index, ok = parsePostIndexName("prefix_", "prefix_posts_2021_12")
+	require.True(t, ok)
+	assert.False(t, index.Daily)
+	assert.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), index.End)
+
+	for _, name := range []string{"posts_2021_02", "prefix_posts_latest", "prefix_users"} {
+		_, ok = parsePostIndexName("prefix_", name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestAggregatePostIndexes(t *testing.T) {
+	reindexPollInterval = time.Millisecond
+
+	th := setup(t)
+	th.server.config.ElasticsearchSettings.AggregatePostsAfterDays = model.NewInt(2)
+	th.start(t)
+
+	th.stub.addDocument("test_posts_2021_02", "old", ESPost{Message: "kept"})
+	th.stub.addDocument("test_posts_2021_02_27", "a", ESPost{})
+	th.stub.addDocument("test_posts_2021_02_28", "b", ESPost{})
+	th.stub.addDocument("test_posts_2021_02_28", "old", ESPost{Message: "stale"})
+	th.stub.addDocument("test_posts_2021_03_01", "c", ESPost{})
+	th.stub.addDocument("test_posts_2021_03_02", "d", ESPost{})
+	th.stub.addDocument("test_posts_2021_03_03", "e", ESPost{})
+
+	now := time.Date(2021, 3, 4, 12, 0, 0, 0, time.UTC)
+	require.Nil(t, th.engine.AggregatePostIndexes(now))
+
+	assert.Equal(t, []string{"test_posts_2021_02", "test_posts_2021_03", "test_posts_2021_03_02", "test_posts_2021_03_03"}, th.stub.indexNames())
+	assert.Equal(t, []string{"a", "b", "old"}, th.stub.documentIds("test_posts_2021_02"))
+	assert.Equal(t, []string{"c"}, th.stub.documentIds("test_posts_2021_03"))
+
+	// The copy already in the monthly index is the latest one.
+	var doc ESPost
+	th.stub.mut.Lock()
+	require.NoError(t, json.Unmarshal(th.stub.indexes["test_posts_2021_02"]["old"], &doc))
+	th.stub.mut.Unlock()
+	assert.Equal(t, "kept", doc.Message)
+
+	// Running again has nothing left to aggregate.
+	require.Nil(t, th.engine.AggregatePostIndexes(now))
+	assert.Len(t, th.stub.indexNames(), 4)
+}
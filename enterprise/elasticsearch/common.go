@@ -0,0 +1,326 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/olivere/elastic.v6"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/services/searchengine"
+)
+
+const (
+	dailyIndexLayout   = "2006_01_02"
+	monthlyIndexLayout = "2006_01"
+)
+
+type ESChannel struct {
+	Id            string            `json:"id"`
+	Type          model.ChannelType `json:"type"`
+	DeleteAt      int64             `json:"delete_at"`
+	UserIDs       []string          `json:"user_ids"`
+	TeamId        string            `json:"team_id"`
+	TeamMemberIDs []string          `json:"team_member_ids"`
+	NameSuggest   []string          `json:"name_suggestions"`
+}
+
+type ESUser struct {
+	Id                         string   `json:"id"`
+	SuggestionsWithFullname    []string `json:"suggestions_with_fullname"`
+	SuggestionsWithoutFullname []string `json:"suggestions_without_fullname"`
+	DeleteAt                   int64    `json:"delete_at"`
+	TeamsIds                   []string `json:"team_ids"`
+	ChannelsIds                []string `json:"channel_ids"`
+}
+
+type ESPost struct {
+	Id        string   `json:"id"`
+	TeamId    string   `json:"team_id"`
+	ChannelId string   `json:"channel_id"`
+	UserId    string   `json:"user_id"`
+	CreateAt  int64    `json:"create_at"`
+	Message   string   `json:"message"`
+	Type      string   `json:"type"`
+	Hashtags  []string `json:"hashtags"`
+}
+
+type ESFile struct {
+	Id        string `json:"id"`
+	CreatorId string `json:"creator_id"`
+	PostId    string `json:"post_id"`
+	ChannelId string `json:"channel_id"`
+	CreateAt  int64  `json:"create_at"`
+	Name      string `json:"name"`
+	Content   string `json:"content"`
+	Extension string `json:"extension"`
+}
+
+func ESChannelFromChannel(channel *model.Channel, userIDs, teamMemberIDs []string) *ESChannel {
+	displayNameInputs := searchengine.GetSuggestionInputsSplitBy(channel.DisplayName, " ")
+	nameInputs := searchengine.GetSuggestionInputsSplitByMultiple(channel.Name, []string{"-", "_"})
+
+	return &ESChannel{
+		Id:            channel.Id,
+		Type:          channel.Type,
+		DeleteAt:      channel.DeleteAt,
+		TeamId:        channel.TeamId,
+		NameSuggest:   append(displayNameInputs, nameInputs...),
+		UserIDs:       userIDs,
+		TeamMemberIDs: teamMemberIDs,
+	}
+}
+
+func ESUserFromUserAndTeams(user *model.User, teamsIds, channelsIds []string) *ESUser {
+	usernameSuggestions := searchengine.GetSuggestionInputsSplitByMultiple(user.Username, []string{".", "-", "_"})
+
+	fullnameStrings := []string{}
+	if user.FirstName != "" {
+		fullnameStrings = append(fullnameStrings, user.FirstName)
+	}
+	if user.LastName != "" {
+		fullnameStrings = append(fullnameStrings, user.LastName)
+	}
+
+	fullnameSuggestions := []string{}
+	if len(fullnameStrings) > 0 {
+		fullnameSuggestions = searchengine.GetSuggestionInputsSplitBy(strings.Join(fullnameStrings, " "), " ")
+	}
+
+	nicknameSuggestions := []string{}
+	if user.Nickname != "" {
+		nicknameSuggestions = searchengine.GetSuggestionInputsSplitBy(user.Nickname, " ")
+	}
+
+	usernameAndNicknameSuggestions := append(usernameSuggestions, nicknameSuggestions...)
+
+	return &ESUser{
+		Id:                         user.Id,
+		SuggestionsWithFullname:    append(append([]string{}, usernameAndNicknameSuggestions...), fullnameSuggestions...),
+		SuggestionsWithoutFullname: usernameAndNicknameSuggestions,
+		DeleteAt:                   user.DeleteAt,
+		TeamsIds:                   teamsIds,
+		ChannelsIds:                channelsIds,
+	}
+}
+
+func ESUserFromUserForIndexing(userForIndexing *model.UserForIndexing) *ESUser {
+	user := &model.User{
+		Id:        userForIndexing.Id,
+		Username:  userForIndexing.Username,
+		Nickname:  userForIndexing.Nickname,
+		FirstName: userForIndexing.FirstName,
+		LastName:  userForIndexing.LastName,
+		CreateAt:  userForIndexing.CreateAt,
+		DeleteAt:  userForIndexing.DeleteAt,
+	}
+
+	return ESUserFromUserAndTeams(user, userForIndexing.TeamsIds, userForIndexing.ChannelsIds)
+}
+
+func ESPostFromPost(post *model.Post, teamId string) *ESPost {
+	p := &model.PostForIndexing{
+		TeamId: teamId,
+	}
+	post.ShallowCopy(&p.Post)
+	return ESPostFromPostForIndexing(p)
+}
+
+func ESPostFromPostForIndexing(post *model.PostForIndexing) *ESPost {
+	return &ESPost{
+		Id:        post.Id,
+		TeamId:    post.TeamId,
+		ChannelId: post.ChannelId,
+		UserId:    post.UserId,
+		CreateAt:  post.CreateAt,
+		Message:   post.Message,
+		Type:      post.Type,
+		Hashtags:  strings.Fields(post.Hashtags),
+	}
+}
+
+func splitFilenameWords(name string) string {
+	result := name
+	result = strings.ReplaceAll(result, "-", " ")
+	result = strings.ReplaceAll(result, ".", " ")
+	return result
+}
+
+func ESFileFromFileInfo(fileInfo *model.FileInfo, channelId string) *ESFile {
+	return &ESFile{
+		Id:        fileInfo.Id,
+		CreatorId: fileInfo.CreatorId,
+		PostId:    fileInfo.PostId,
+		ChannelId: channelId,
+		CreateAt:  fileInfo.CreateAt,
+		Content:   fileInfo.Content,
+		Extension: fileInfo.Extension,
+		Name:      fileInfo.Name + " " + splitFilenameWords(fileInfo.Name),
+	}
+}
+
+func ESFileFromFileForIndexing(file *model.FileForIndexing) *ESFile {
+	return &ESFile{
+		Id:        file.Id,
+		CreatorId: file.CreatorId,
+		PostId:    file.PostId,
+		ChannelId: file.ChannelId,
+		CreateAt:  file.CreateAt,
+		Content:   file.Content,
+		Extension: file.Extension,
+		Name:      file.Name + " " + splitFilenameWords(file.Name),
+	}
+}
+
+// aggregationCutoff returns the start of the first day whose posts are kept in
+// a daily index.
+func aggregationCutoff(now time.Time, aggregateAfterDays int) time.Time {
+	today := now.UTC().Truncate(24 * time.Hour)
+	return today.AddDate(0, 0, -aggregateAfterDays)
+}
+
+// postIndexName returns the name of the index holding a post created at
+// createAt: daily indexes are kept for the last aggregateAfterDays days, older
+// posts go to monthly indexes.
+func postIndexName(prefix string, createAt int64, aggregateAfterDays int, now time.Time) string {
+	created := model.GetTimeForMillis(createAt).UTC()
+	if created.Before(aggregationCutoff(now, aggregateAfterDays)) {
+		return prefix + PostIndex + "_" + created.Format(monthlyIndexLayout)
+	}
+	return prefix + PostIndex + "_" + created.Format(dailyIndexLayout)
+}
+
+func (es *ElasticsearchInterfaceImpl) postIndexName(createAt int64) string {
+	settings := es.settings()
+	return postIndexName(*settings.IndexPrefix, createAt, *settings.AggregatePostsAfterDays, time.Now())
+}
+
+// postIndex describes the range of creation times covered by a post index.
+type postIndex struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+	Daily bool
+}
+
+func parsePostIndexName(prefix, name string) (*postIndex, bool) {
+	suffix := strings.TrimPrefix(name, prefix+PostIndex+"_")
+	if suffix == name {
+		return nil, false
+	}
+	if start, err := time.Parse(dailyIndexLayout, suffix); err == nil {
+		return &postIndex{Name: name, Start: start, End: start.AddDate(0, 0, 1), Daily: true}, true
+	}
+	if start, err := time.Parse(monthlyIndexLayout, suffix); err == nil {
+		return &postIndex{Name: name, Start: start, End: start.AddDate(0, 1, 0)}, true
+	}
+	return nil, false
+}
+
+var (
+	keywordField = map[string]interface{}{"type": "keyword"}
+	textField    = map[string]interface{}{"type": "text", "analyzer": "standard"}
+	dateField    = map[string]interface{}{"type": "long"}
+)
+
+func indexTemplate(pattern string, shards, replicas int, properties map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"index_patterns": []string{pattern},
+		"settings": map[string]interface{}{
+			"number_of_shards":   shards,
+			"number_of_replicas": replicas,
+		},
+		"mappings": map[string]interface{}{
+			"properties": properties,
+		},
+	}
+}
+
+// createTemplates installs the index templates, so that the indexes get their
+// mappings whenever they are created, including the post indexes created every day.
+func (es *ElasticsearchInterfaceImpl) createTemplates(client *elastic.Client) *model.AppError {
+	settings := es.settings()
+	templates := []struct {
+		name       string
+		errorId    string
+		pattern    string
+		shards     int
+		replicas   int
+		properties map[string]interface{}
+	}{
+		{PostIndex, "ent.elasticsearch.create_template_posts_if_not_exists.template_create_failed", PostIndex + "_*", *settings.PostIndexShards, *settings.PostIndexReplicas, map[string]interface{}{
+			"id":         keywordField,
+			"team_id":    keywordField,
+			"channel_id": keywordField,
+			"user_id":    keywordField,
+			"create_at":  dateField,
+			"message":    textField,
+			"type":       keywordField,
+			"hashtags":   map[string]interface{}{"type": "text", "analyzer": "whitespace"},
+		}},
+		{ChannelIndex, "ent.elasticsearch.create_template_channels_if_not_exists.template_create_failed", ChannelIndex, *settings.ChannelIndexShards, *settings.ChannelIndexReplicas, map[string]interface{}{
+			"id":               keywordField,
+			"type":             keywordField,
+			"delete_at":        dateField,
+			"user_ids":         keywordField,
+			"team_id":          keywordField,
+			"team_member_ids":  keywordField,
+			"name_suggestions": keywordField,
+		}},
+		{UserIndex, "ent.elasticsearch.create_template_users_if_not_exists.template_create_failed", UserIndex, *settings.UserIndexShards, *settings.UserIndexReplicas, map[string]interface{}{
+			"id":                           keywordField,
+			"suggestions_with_fullname":    keywordField,
+			"suggestions_without_fullname": keywordField,
+			"delete_at":                    dateField,
+			"team_ids":                     keywordField,
+			"channel_ids":                  keywordField,
+		}},
+		{FileIndex, "ent.elasticsearch.create_template_file_info_if_not_exists.template_create_failed", FileIndex, *settings.PostIndexShards, *settings.PostIndexReplicas, map[string]interface{}{
+			"id":         keywordField,
+			"creator_id": keywordField,
+			"post_id":    keywordField,
+			"channel_id": keywordField,
+			"create_at":  dateField,
+			"name":       textField,
+			"content":    textField,
+			"extension":  keywordField,
+		}},
+	}
+
+	for _, template := range templates {
+		body := indexTemplate(es.indexName(template.pattern), template.shards, template.replicas, template.properties)
+		if _, err := client.IndexPutTemplate(es.indexName(template.name)).BodyJson(body).Do(context.Background()); err != nil {
+			return model.NewAppError("Elasticsearch.Start", template.errorId, nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+	return nil
+}
+
+// bulkErrors returns an error describing the failed items of a bulk request, if
+// any. Deleting a document which is not indexed is not a failure.
+func bulkErrors(response *elastic.BulkResponse) error {
+	if response == nil || !response.Errors {
+		return nil
+	}
+	failures := []string{}
+	for _, item := range response.Failed() {
+		if item.Status == http.StatusNotFound {
+			continue
+		}
+		reason := ""
+		if item.Error != nil {
+			reason = item.Error.Reason
+		}
+		failures = append(failures, fmt.Sprintf("%s/%s: %s", item.Index, item.Id, reason))
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d documents failed: %s", len(failures), strings.Join(failures, "; "))
+}
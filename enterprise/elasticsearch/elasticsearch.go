@@ -0,0 +1,429 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package elasticsearch
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/olivere/elastic.v6"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	ejobs "github.com/cjdelisle/matterfoss-server/v6/einterfaces/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/services/searchengine"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const (
+	EngineName = "elasticsearch"
+
+	// Posts are written to one index per day, named after PostIndex and the day,
+	// until the aggregation job merges them into one index per month.
+	PostIndex    = "posts"
+	ChannelIndex = "channels"
+	UserIndex    = "users"
+	FileIndex    = "files"
+
+	// docType is the type the single document APIs are called with. Bulk
+	// requests go without one, as OpenSearch 2 rejects types there.
+	docType = "_doc"
+
+	liveIndexingFlushInterval = 5 * time.Second
+)
+
+// ServerIface is the subset of the server used by the Elasticsearch implementation.
+type ServerIface interface {
+	Config() *model.Config
+	License() *model.License
+}
+
+func init() {
+	app.RegisterElasticsearchInterface(func(s *app.Server) searchengine.SearchEngineInterface {
+		return New(s)
+	})
+	app.RegisterJobsElasticsearchIndexerInterface(func(s *app.Server) ejobs.IndexerJobInterface {
+		return &IndexerJob{engine: serverEngine(s), jobServer: s.Jobs}
+	})
+	app.RegisterJobsElasticsearchAggregatorInterface(func(s *app.Server) ejobs.ElasticsearchAggregatorInterface {
+		return &AggregatorJob{engine: serverEngine(s), jobServer: s.Jobs}
+	})
+}
+
+// serverEngine returns the engine registered on the server, which the jobs share
+// so that they are stopped and restarted along with it.
+func serverEngine(s *app.Server) *ElasticsearchInterfaceImpl {
+	if s.SearchEngine == nil {
+		return nil
+	}
+	engine, _ := s.SearchEngine.ElasticsearchEngine.(*ElasticsearchInterfaceImpl)
+	return engine
+}
+
+// ElasticsearchInterfaceImpl implements searchengine.SearchEngineInterface on top
+// of the Elasticsearch REST API, as also served by OpenSearch.
+type ElasticsearchInterfaceImpl struct {
+	server ServerIface
+
+	mutex         sync.RWMutex
+	ready         int32
+	client        *elastic.Client
+	bulkProcessor *elastic.BulkProcessor
+	version       int
+	fullVersion   string
+	plugins       []string
+}
+
+func New(server ServerIface) *ElasticsearchInterfaceImpl {
+	return &ElasticsearchInterfaceImpl{server: server}
+}
+
+// logger adapts mlog to the logger interface of the client.
+type logger struct {
+	level mlog.Level
+}
+
+func (l *logger) Printf(format string, v ...interface{}) {
+	mlog.Log(l.level, "Elasticsearch client", mlog.String("message", strings.TrimSpace(fmt.Sprintf(format, v...))))
+}
+
+func newClient(settings *model.ElasticsearchSettings) (*elastic.Client, error) {
+	httpClient := &http.Client{
+		Timeout: time.Duration(*settings.RequestTimeoutSeconds) * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: *settings.SkipTLSVerification},
+		},
+	}
+
+	options := []elastic.ClientOptionFunc{
+		elastic.SetURL(*settings.ConnectionURL),
+		elastic.SetSniff(*settings.Sniff),
+		elastic.SetHttpClient(httpClient),
+	}
+	if *settings.Username != "" {
+		options = append(options, elastic.SetBasicAuth(*settings.Username, *settings.Password))
+	}
+	switch *settings.Trace {
+	case "all":
+		options = append(options, elastic.SetTraceLog(&logger{mlog.LvlTrace}), elastic.SetErrorLog(&logger{mlog.LvlError}))
+	case "error":
+		options = append(options, elastic.SetErrorLog(&logger{mlog.LvlError}))
+	}
+
+	return elastic.NewClient(options...)
+}
+
+// serverVersion returns the full version of the server and its major version.
+func serverVersion(client *elastic.Client, url string) (string, int, *model.AppError) {
+	fullVersion, err := client.ElasticsearchVersion(url)
+	if err != nil {
+		return "", 0, model.NewAppError("Elasticsearch.Start", "ent.elasticsearch.start.get_server_version.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	version, err := strconv.Atoi(strings.SplitN(fullVersion, ".", 2)[0])
+	if err != nil {
+		return "", 0, model.NewAppError("Elasticsearch.Start", "ent.elasticsearch.start.parse_server_version.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return fullVersion, version, nil
+}
+
+func (es *ElasticsearchInterfaceImpl) isLicensed() bool {
+	license := es.server.License()
+	return license != nil && *license.Features.Elasticsearch
+}
+
+func (es *ElasticsearchInterfaceImpl) settings() *model.ElasticsearchSettings {
+	return &es.server.Config().ElasticsearchSettings
+}
+
+// indexName prefixes name with ElasticsearchSettings.IndexPrefix.
+func (es *ElasticsearchInterfaceImpl) indexName(name string) string {
+	return *es.settings().IndexPrefix + name
+}
+
+func (es *ElasticsearchInterfaceImpl) Start() *model.AppError {
+	settings := es.settings()
+	if !es.isLicensed() || !*settings.EnableIndexing {
+		return nil
+	}
+
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	if atomic.LoadInt32(&es.ready) != 0 {
+		return model.NewAppError("Elasticsearch.Start", "ent.elasticsearch.start.already_started.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	mlog.Info("Starting Elasticsearch")
+
+	client, err := newClient(settings)
+	if err != nil {
+		return model.NewAppError("Elasticsearch.Start", "ent.elasticsearch.create_client.connect_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	fullVersion, version, appErr := serverVersion(client, *settings.ConnectionURL)
+	if appErr != nil {
+		client.Stop()
+		return appErr
+	}
+
+	var plugins []string
+	if info, err := client.NodesInfo().Metric("plugins").Do(context.Background()); err != nil {
+		mlog.Warn("Failed to get the Elasticsearch plugins", mlog.Err(err))
+	} else {
+		for _, node := range info.Nodes {
+			for _, plugin := range node.Plugins {
+				plugins = append(plugins, plugin.Name)
+			}
+		}
+	}
+
+	if appErr := es.createTemplates(client); appErr != nil {
+		client.Stop()
+		return appErr
+	}
+
+	var bulkProcessor *elastic.BulkProcessor
+	if *settings.LiveIndexingBatchSize > 1 {
+		bulkProcessor, err = client.BulkProcessor().
+			Name("MatterfossLiveIndexing").
+			BulkActions(*settings.LiveIndexingBatchSize).
+			FlushInterval(liveIndexingFlushInterval).
+			After(logBulkFailures).
+			Do(context.Background())
+		if err != nil {
+			client.Stop()
+			return model.NewAppError("Elasticsearch.Start", "ent.elasticsearch.start.create_bulk_processor_failed.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	es.client = client
+	es.bulkProcessor = bulkProcessor
+	es.fullVersion = fullVersion
+	es.version = version
+	es.plugins = plugins
+	atomic.StoreInt32(&es.ready, 1)
+	return nil
+}
+
+// logBulkFailures reports the documents the live indexing bulk processor failed
+// to write, since nobody is waiting on their result.
+func logBulkFailures(_ int64, _ []elastic.BulkableRequest, response *elastic.BulkResponse, err error) {
+	if err != nil {
+		mlog.Warn("Elasticsearch live indexing batch failed", mlog.Err(err))
+		return
+	}
+	if err := bulkErrors(response); err != nil {
+		mlog.Warn("Elasticsearch live indexing batch failed", mlog.Err(err))
+	}
+}
+
+func (es *ElasticsearchInterfaceImpl) Stop() *model.AppError {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	if atomic.LoadInt32(&es.ready) == 0 {
+		return model.NewAppError("Elasticsearch.Stop", "ent.elasticsearch.stop.already_stopped.app_error", nil, "", http.StatusInternalServerError)
+	}
+
+	mlog.Info("Stopping Elasticsearch")
+
+	if es.bulkProcessor != nil {
+		if err := es.bulkProcessor.Close(); err != nil {
+			mlog.Warn("Failed to flush the Elasticsearch live indexing batch", mlog.Err(err))
+		}
+		es.bulkProcessor = nil
+	}
+	es.client.Stop()
+	es.client = nil
+	atomic.StoreInt32(&es.ready, 0)
+	return nil
+}
+
+// IsActive reports whether the engine should be running. The server starts an
+// active engine, and operations on an engine which has not started yet fail,
+// letting the search layer fall back to the other engines.
+func (es *ElasticsearchInterfaceImpl) IsActive() bool {
+	return es.isLicensed() && *es.settings().EnableIndexing
+}
+
+func (es *ElasticsearchInterfaceImpl) isStarted() bool {
+	return atomic.LoadInt32(&es.ready) == 1
+}
+
+// checkStarted must be called with the mutex held.
+func (es *ElasticsearchInterfaceImpl) checkStarted(where string) *model.AppError {
+	if !es.isStarted() {
+		return model.NewAppError(where, "ent.elasticsearch.not_started.error", nil, "", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) IsIndexingEnabled() bool {
+	return *es.settings().EnableIndexing
+}
+
+func (es *ElasticsearchInterfaceImpl) IsSearchEnabled() bool {
+	return *es.settings().EnableSearching
+}
+
+func (es *ElasticsearchInterfaceImpl) IsAutocompletionEnabled() bool {
+	return *es.settings().EnableAutocomplete
+}
+
+func (es *ElasticsearchInterfaceImpl) IsIndexingSync() bool {
+	return false
+}
+
+func (es *ElasticsearchInterfaceImpl) GetVersion() int {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+	return es.version
+}
+
+func (es *ElasticsearchInterfaceImpl) GetFullVersion() string {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+	return es.fullVersion
+}
+
+func (es *ElasticsearchInterfaceImpl) GetPlugins() []string {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+	return append([]string{}, es.plugins...)
+}
+
+func (es *ElasticsearchInterfaceImpl) GetName() string {
+	return EngineName
+}
+
+// UpdateConfig has nothing to do: the settings are read on every call and the
+// server restarts the engine when the connection settings change.
+func (es *ElasticsearchInterfaceImpl) UpdateConfig(cfg *model.Config) {}
+
+func (es *ElasticsearchInterfaceImpl) TestConfig(cfg *model.Config) *model.AppError {
+	if !es.isLicensed() {
+		return model.NewAppError("Elasticsearch.TestConfig", "ent.elasticsearch.test_config.license.error", nil, "", http.StatusNotImplemented)
+	}
+	settings := &cfg.ElasticsearchSettings
+	if !*settings.EnableIndexing {
+		return model.NewAppError("Elasticsearch.TestConfig", "ent.elasticsearch.test_config.indexing_disabled.error", nil, "", http.StatusNotImplemented)
+	}
+
+	client, err := newClient(settings)
+	if err != nil {
+		return model.NewAppError("Elasticsearch.TestConfig", "ent.elasticsearch.test_config.connect_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+	defer client.Stop()
+
+	if _, err := client.ElasticsearchVersion(*settings.ConnectionURL); err != nil {
+		return model.NewAppError("Elasticsearch.TestConfig", "ent.elasticsearch.test_config.connect_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// indexes returns the names of the existing indexes matching pattern, which is
+// relative to the index prefix.
+func (es *ElasticsearchInterfaceImpl) indexes(pattern string) ([]string, error) {
+	rows, err := es.client.CatIndices().Index(es.indexName(pattern)).Columns("index").Do(context.Background())
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.Index)
+	}
+	return names, nil
+}
+
+func (es *ElasticsearchInterfaceImpl) PurgeIndexes() *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.PurgeIndexes"); appErr != nil {
+		return appErr
+	}
+
+	mlog.Info("Purging the Elasticsearch indexes")
+
+	// Index deletion by wildcard is commonly disabled, so the indexes are listed first.
+	names := []string{}
+	for _, pattern := range []string{PostIndex + "_*", ChannelIndex, UserIndex, FileIndex} {
+		matching, err := es.indexes(pattern)
+		if err != nil {
+			return model.NewAppError("Elasticsearch.PurgeIndexes", "ent.elasticsearch.purge_indexes.delete_failed", nil, err.Error(), http.StatusInternalServerError)
+		}
+		names = append(names, matching...)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	if _, err := es.client.DeleteIndex(names...).Do(context.Background()); err != nil {
+		return model.NewAppError("Elasticsearch.PurgeIndexes", "ent.elasticsearch.purge_indexes.delete_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) RefreshIndexes() *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.RefreshIndexes"); appErr != nil {
+		return appErr
+	}
+
+	if es.bulkProcessor != nil {
+		if err := es.bulkProcessor.Flush(); err != nil {
+			return model.NewAppError("Elasticsearch.RefreshIndexes", "ent.elasticsearch.refresh_indexes.refresh_failed", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+	if _, err := es.client.Refresh(es.indexName("*")).Do(context.Background()); err != nil {
+		return model.NewAppError("Elasticsearch.RefreshIndexes", "ent.elasticsearch.refresh_indexes.refresh_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// DataRetentionDeleteIndexes deletes the post indexes holding only posts created
+// before cutoff.
+func (es *ElasticsearchInterfaceImpl) DataRetentionDeleteIndexes(cutoff time.Time) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.DataRetentionDeleteIndexes"); appErr != nil {
+		return appErr
+	}
+
+	names, err := es.indexes(PostIndex + "_*")
+	if err != nil {
+		return model.NewAppError("Elasticsearch.DataRetentionDeleteIndexes", "ent.elasticsearch.data_retention_delete_indexes.get_indexes.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	expired := []string{}
+	for _, name := range names {
+		postIndex, ok := parsePostIndexName(*es.settings().IndexPrefix, name)
+		if ok && !postIndex.End.After(cutoff) {
+			expired = append(expired, name)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	if _, err := es.client.DeleteIndex(expired...).Do(context.Background()); err != nil {
+		return model.NewAppError("Elasticsearch.DataRetentionDeleteIndexes", "ent.elasticsearch.data_retention_delete_indexes.delete_index.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	mlog.Info("Deleted the expired Elasticsearch post indexes", mlog.Int("count", len(expired)))
+	return nil
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package elasticsearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+type mockServer struct {
+	config  *model.Config
+	license *model.License
+}
+
+func (ms *mockServer) Config() *model.Config   { return ms.config }
+func (ms *mockServer) License() *model.License { return ms.license }
+
+type testHelper struct {
+	engine *ElasticsearchInterfaceImpl
+	server *mockServer
+	stub   *stubServer
+}
+
+func setup(t *testing.T) *testHelper {
+	stub := newStubServer(t)
+
+	config := &model.Config{}
+	config.SetDefaults()
+	config.ElasticsearchSettings.ConnectionURL = model.NewString(stub.URL)
+	config.ElasticsearchSettings.EnableIndexing = model.NewBool(true)
+	config.ElasticsearchSettings.EnableSearching = model.NewBool(true)
+	config.ElasticsearchSettings.Sniff = model.NewBool(false)
+	config.ElasticsearchSettings.IndexPrefix = model.NewString("test_")
+	config.ElasticsearchSettings.LiveIndexingBatchSize = model.NewInt(1)
+
+	th := &testHelper{
+		server: &mockServer{config: config, license: model.NewTestLicense("elasticsearch")},
+		stub:   stub,
+	}
+	th.engine = New(th.server)
+	return th
+}
+
+func (th *testHelper) start(t *testing.T) {
+	require.Nil(t, th.engine.Start())
+	t.Cleanup(func() {
+		if th.engine.isStarted() {
+			th.engine.Stop()
+		}
+	})
+}
+
+// query returns the query of the last search on the indexes matching pattern.
+func (th *testHelper) query(t *testing.T, pattern string) string {
+	request := th.stub.lastRequest(http.MethodPost, th.engine.indexName(pattern)+"/_search")
+	require.NotNil(t, request)
+
+	var body struct {
+		Query json.RawMessage `json:"query"`
+	}
+	require.NoError(t, json.Unmarshal(request.Body, &body))
+	return string(body.Query)
+}
+
+func TestStartStop(t *testing.T) {
+	t.Run("not licensed", func(t *testing.T) {
+		th := setup(t)
+		th.server.license = nil
+
+		require.Nil(t, th.engine.Start())
+		assert.False(t, th.engine.IsActive())
+		assert.False(t, th.engine.isStarted())
+	})
+
+	t.Run("indexing disabled", func(t *testing.T) {
+		th := setup(t)
+		th.server.config.ElasticsearchSettings.EnableIndexing = model.NewBool(false)
+
+		require.Nil(t, th.engine.Start())
+		assert.False(t, th.engine.isStarted())
+	})
+
+	t.Run("start and stop", func(t *testing.T) {
+		th := setup(t)
+		th.start(t)
+
+		assert.True(t, th.engine.IsActive())
+		assert.Equal(t, 2, th.engine.GetVersion())
+		assert.Equal(t, "2.11.0", th.engine.GetFullVersion())
+		assert.Equal(t, []string{"analysis-icu"}, th.engine.GetPlugins())
+
+		th.stub.mut.Lock()
+		assert.Len(t, th.stub.templates, 4)
+		var template struct {
+			IndexPatterns []string `json:"index_patterns"`
+		}
+		require.NoError(t, json.Unmarshal(th.stub.templates["test_posts"], &template))
+		th.stub.mut.Unlock()
+		assert.Equal(t, []string{"test_posts_*"}, template.IndexPatterns)
+
+		appErr := th.engine.Start()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.elasticsearch.start.already_started.app_error", appErr.Id)
+
+		require.Nil(t, th.engine.Stop())
+		appErr = th.engine.Stop()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.elasticsearch.stop.already_stopped.app_error", appErr.Id)
+	})
+
+	t.Run("unparsable version", func(t *testing.T) {
+		th := setup(t)
+		th.stub.version = "unknown"
+
+		appErr := th.engine.Start()
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.elasticsearch.start.parse_server_version.app_error", appErr.Id)
+		assert.False(t, th.engine.isStarted())
+	})
+
+	t.Run("operations before start", func(t *testing.T) {
+		th := setup(t)
+
+		appErr := th.engine.IndexPost(&model.Post{Id: model.NewId()}, "")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.elasticsearch.not_started.error", appErr.Id)
+	})
+}
+
+func TestTestConfig(t *testing.T) {
+	th := setup(t)
+	require.Nil(t, th.engine.TestConfig(th.server.config))
+
+	cfg := th.server.config.Clone()
+	cfg.ElasticsearchSettings.ConnectionURL = model.NewString("http://127.0.0.1:1")
+	appErr := th.engine.TestConfig(cfg)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.elasticsearch.test_config.connect_failed", appErr.Id)
+
+	th.server.license = nil
+	appErr = th.engine.TestConfig(cfg)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.elasticsearch.test_config.license.error", appErr.Id)
+}
+
+func TestPosts(t *testing.T) {
+	th := setup(t)
+	th.start(t)
+
+	channel := &model.Channel{Id: model.NewId()}
+	post := &model.Post{
+		Id:        model.NewId(),
+		ChannelId: channel.Id,
+		UserId:    model.NewId(),
+		CreateAt:  model.GetMillis(),
+		Message:   "the quick brown fox #animals",
+		Hashtags:  "#animals",
+	}
+	index := th.engine.postIndexName(post.CreateAt)
+
+	require.Nil(t, th.engine.IndexPost(post, "team"))
+	assert.Equal(t, []string{post.Id}, th.stub.documentIds(index))
+
+	var doc ESPost
+	th.stub.mut.Lock()
+	require.NoError(t, json.Unmarshal(th.stub.indexes[index][post.Id], &doc))
+	th.stub.mut.Unlock()
+	assert.Equal(t, "team", doc.TeamId)
+	assert.Equal(t, []string{"#animals"}, doc.Hashtags)
+
+	t.Run("search", func(t *testing.T) {
+		th.stub.mut.Lock()
+		th.stub.highlight[post.Id] = []string{"the <em>quick</em> <em>brown</em> fox"}
+		th.stub.mut.Unlock()
+
+		params := []*model.SearchParams{{
+			Terms:         `quick "brown fox" anim*`,
+			ExcludedTerms: "slow",
+			FromUsers:     []string{post.UserId},
+			OnDate:        time.Now().Format("2006-01-02"),
+		}}
+		postIds, matches, appErr := th.engine.SearchPosts(model.ChannelList{channel}, params, 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{post.Id}, postIds)
+		assert.Equal(t, []string{"quick", "brown"}, matches[post.Id])
+
+		query := th.query(t, PostIndex+"_*")
+		assert.Contains(t, query, `{"terms":{"channel_id":["`+channel.Id+`"]}}`)
+		assert.Contains(t, query, `{"terms":{"user_id":["`+post.UserId+`"]}}`)
+		assert.Contains(t, query, `{"match_phrase":{"message":{"query":"brown fox"}}}`)
+		assert.Contains(t, query, `{"prefix":{"message":"anim"}}`)
+		assert.Contains(t, query, `{"match":{"message":{"operator":"and","query":"quick"}}}`)
+		assert.Contains(t, query, `"must_not":{"match":{"message":{"operator":"or","query":"slow"}}}`)
+		assert.Contains(t, query, `"range":{"create_at"`)
+	})
+
+	t.Run("search hashtags", func(t *testing.T) {
+		params := []*model.SearchParams{{Terms: "#animals", IsHashtag: true}}
+		_, _, appErr := th.engine.SearchPosts(model.ChannelList{channel}, params, 0, 20)
+		require.Nil(t, appErr)
+		assert.Contains(t, th.query(t, PostIndex+"_*"), `{"match":{"hashtags":{"operator":"and","query":"#animals"}}}`)
+	})
+
+	t.Run("post in two indexes while aggregated", func(t *testing.T) {
+		th.stub.addDocument(th.engine.indexName(PostIndex+"_2000_01"), post.Id, doc)
+
+		postIds, _, appErr := th.engine.SearchPosts(model.ChannelList{channel}, []*model.SearchParams{{Terms: "fox"}}, 0, 20)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{post.Id}, postIds)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.Nil(t, th.engine.DeletePost(post))
+		assert.Empty(t, th.stub.documentIds(index))
+
+		// Deleting a post which is not indexed is not an error.
+		require.Nil(t, th.engine.DeletePost(post))
+	})
+
+	t.Run("delete channel posts", func(t *testing.T) {
+		require.Nil(t, th.engine.DeleteChannelPosts(channel.Id))
+		request := th.stub.lastRequest(http.MethodPost, "/_delete_by_query")
+		require.NotNil(t, request)
+		assert.Contains(t, request.Path, "test_posts_*")
+		assert.Contains(t, string(request.Body), `{"term":{"channel_id":"`+channel.Id+`"}}`)
+	})
+}
+
+func TestLiveIndexingBatch(t *testing.T) {
+	th := setup(t)
+	th.server.config.ElasticsearchSettings.LiveIndexingBatchSize = model.NewInt(10)
+	th.start(t)
+
+	user := &model.User{Id: model.NewId(), Username: "john.smith"}
+	require.Nil(t, th.engine.IndexUser(user, []string{"team"}, []string{"channel"}))
+	assert.Empty(t, th.stub.documentIds(th.engine.indexName(UserIndex)))
+
+	// Refreshing flushes the pending documents.
+	require.Nil(t, th.engine.RefreshIndexes())
+	assert.Equal(t, []string{user.Id}, th.stub.documentIds(th.engine.indexName(UserIndex)))
+
+	require.Nil(t, th.engine.DeleteUser(user))
+	require.Nil(t, th.engine.Stop())
+	assert.Empty(t, th.stub.documentIds(th.engine.indexName(UserIndex)))
+}
+
+func TestChannels(t *testing.T) {
+	th := setup(t)
+	th.start(t)
+
+	channel := &model.Channel{Id: model.NewId(), TeamId: "team", Type: model.ChannelTypeOpen, Name: "off-topic", DisplayName: "Off Topic"}
+	require.Nil(t, th.engine.IndexChannel(channel, nil, []string{"user"}))
+
+	channelIds, appErr := th.engine.SearchChannels("team", "user", "Off")
+	require.Nil(t, appErr)
+	assert.Equal(t, []string{channel.Id}, channelIds)
+
+	query := th.query(t, ChannelIndex)
+	assert.Contains(t, query, `{"term":{"team_id":"team"}}`)
+	assert.Contains(t, query, `{"prefix":{"name_suggestions":"off"}}`)
+	assert.Contains(t, query, `{"term":{"user_ids":"user"}}`)
+
+	require.Nil(t, th.engine.DeleteChannel(channel))
+	assert.Empty(t, th.stub.documentIds(th.engine.indexName(ChannelIndex)))
+}
+
+func TestUsers(t *testing.T) {
+	th := setup(t)
+	th.start(t)
+
+	user := &model.User{Id: model.NewId(), Username: "john.smith", FirstName: "John", LastName: "Smith"}
+	require.Nil(t, th.engine.IndexUser(user, []string{"team"}, []string{"channel"}))
+
+	t.Run("in team", func(t *testing.T) {
+		options := &model.UserSearchOptions{Limit: 10}
+		userIds, appErr := th.engine.SearchUsersInTeam("team", nil, "Smi", options)
+		require.Nil(t, appErr)
+		assert.Equal(t, []string{user.Id}, userIds)
+
+		query := th.query(t, UserIndex)
+		assert.Contains(t, query, `{"prefix":{"suggestions_without_fullname":"smi"}}`)
+		assert.Contains(t, query, `{"term":{"team_ids":"team"}}`)
+		assert.Contains(t, query, `{"term":{"delete_at":0}}`)
+	})
+
+	t.Run("full names allowed", func(t *testing.T) {
+		options := &model.UserSearchOptions{Limit: 10, AllowFullNames: true, AllowInactive: true}
+		_, appErr := th.engine.SearchUsersInTeam("", nil, "smi", options)
+		require.Nil(t, appErr)
+
+		query := th.query(t, UserIndex)
+		assert.Contains(t, query, `{"prefix":{"suggestions_with_fullname":"smi"}}`)
+		assert.NotContains(t, query, "team_ids")
+		assert.NotContains(t, query, "delete_at")
+	})
+
+	t.Run("no restricted channels", func(t *testing.T) {
+		userIds, appErr := th.engine.SearchUsersInTeam("team", []string{}, "", &model.UserSearchOptions{Limit: 10})
+		require.Nil(t, appErr)
+		assert.Empty(t, userIds)
+	})
+
+	t.Run("in channel", func(t *testing.T) {
+		_, _, appErr := th.engine.SearchUsersInChannel("team", "channel", nil, "john", &model.UserSearchOptions{Limit: 10})
+		require.Nil(t, appErr)
+
+		query := th.query(t, UserIndex)
+		assert.Contains(t, query, `"must_not":{"term":{"channel_ids":"channel"}}`)
+	})
+}
+
+func TestFiles(t *testing.T) {
+	th := setup(t)
+	th.start(t)
+
+	channel := &model.Channel{Id: model.NewId()}
+	file := &model.FileInfo{Id: model.NewId(), CreatorId: model.NewId(), Name: "annual-report.pdf", Extension: "pdf", CreateAt: model.GetMillis()}
+	require.Nil(t, th.engine.IndexFile(file, channel.Id))
+
+	var doc ESFile
+	th.stub.mut.Lock()
+	require.NoError(t, json.Unmarshal(th.stub.indexes[th.engine.indexName(FileIndex)][file.Id], &doc))
+	th.stub.mut.Unlock()
+	assert.Equal(t, "annual-report.pdf annual report pdf", doc.Name)
+
+	params := []*model.SearchParams{{Terms: "report", Extensions: []string{"pdf"}, ExcludedExtensions: []string{"txt"}}}
+	fileIds, appErr := th.engine.SearchFiles(model.ChannelList{channel}, params, 0, 20)
+	require.Nil(t, appErr)
+	assert.Equal(t, []string{file.Id}, fileIds)
+
+	query := th.query(t, FileIndex)
+	assert.Contains(t, query, `{"terms":{"extension":["pdf"]}}`)
+	assert.Contains(t, query, `{"terms":{"extension":["txt"]}}`)
+	assert.Contains(t, query, `{"match":{"content":{"operator":"and","query":"report"}}}`)
+	assert.Contains(t, query, `{"match":{"name":{"operator":"and","query":"report"}}}`)
+
+	require.Nil(t, th.engine.DeleteFile(file.Id))
+	assert.Empty(t, th.stub.documentIds(th.engine.indexName(FileIndex)))
+}
+
+func TestPurgeIndexes(t *testing.T) {
+	th := setup(t)
+	th.start(t)
+
+	th.stub.addDocument("test_posts_2020_01", "post", ESPost{})
+	th.stub.addDocument("test_users", "user", ESUser{})
+	th.stub.addDocument("other_users", "user", ESUser{})
+
+	require.Nil(t, th.engine.PurgeIndexes())
+	assert.Equal(t, []string{"other_users"}, th.stub.indexNames())
+
+	// There is nothing left to purge.
+	require.Nil(t, th.engine.PurgeIndexes())
+}
+
+func TestDataRetentionDeleteIndexes(t *testing.T) {
+	th := setup(t)
+	th.start(t)
+
+	for _, name := range []string{"test_posts_2020_01", "test_posts_2020_02", "test_posts_2020_02_28", "test_posts_2020_03_01"} {
+		th.stub.addDocument(name, model.NewId(), ESPost{})
+	}
+
+	require.Nil(t, th.engine.DataRetentionDeleteIndexes(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []string{"test_posts_2020_03_01"}, th.stub.indexNames())
+}
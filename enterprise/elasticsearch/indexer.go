@@ -0,0 +1,491 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/olivere/elastic.v6"
+
+	"github.com/cjdelisle/matterfoss-server/v6/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const (
+	BatchSize             = 1000
+	TimeBetweenBatches    = 100
+	EstimatedPostCount    = 10000000
+	EstimatedFilesCount   = 100000
+	EstimatedChannelCount = 100000
+	EstimatedUserCount    = 10000
+)
+
+// batchRetries and batchRetryDelay bound the attempts at fetching a batch
+// from the database.
+var (
+	batchRetries    = 10
+	batchRetryDelay = 15 * time.Second
+)
+
+// IndexerJob implements ejobs.IndexerJobInterface.
+type IndexerJob struct {
+	engine    *ElasticsearchInterfaceImpl
+	jobServer *jobs.JobServer
+}
+
+func (j *IndexerJob) MakeWorker() model.Worker {
+	if j.engine == nil {
+		return nil
+	}
+	return &IndexerWorker{
+		name:      "ElasticsearchIndexer",
+		stop:      make(chan struct{}),
+		stopped:   make(chan bool, 1),
+		jobs:      make(chan model.Job),
+		jobServer: j.jobServer,
+		engine:    j.engine,
+	}
+}
+
+type IndexerWorker struct {
+	name      string
+	stop      chan struct{}
+	stopped   chan bool
+	jobs      chan model.Job
+	jobServer *jobs.JobServer
+	engine    *ElasticsearchInterfaceImpl
+	closed    int32
+}
+
+type IndexingProgress struct {
+	Now                time.Time
+	StartAtTime        int64
+	EndAtTime          int64
+	LastEntityTime     int64
+	TotalPostsCount    int64
+	DonePostsCount     int64
+	DonePosts          bool
+	TotalFilesCount    int64
+	DoneFilesCount     int64
+	DoneFiles          bool
+	TotalChannelsCount int64
+	DoneChannelsCount  int64
+	DoneChannels       bool
+	TotalUsersCount    int64
+	DoneUsersCount     int64
+	DoneUsers          bool
+}
+
+func (ip *IndexingProgress) CurrentProgress() int64 {
+	total := ip.TotalPostsCount + ip.TotalChannelsCount + ip.TotalUsersCount + ip.TotalFilesCount
+	if total == 0 {
+		return 0
+	}
+	return (ip.DonePostsCount + ip.DoneChannelsCount + ip.DoneUsersCount + ip.DoneFilesCount) * 100 / total
+}
+
+func (ip *IndexingProgress) IsDone() bool {
+	return ip.DonePosts && ip.DoneChannels && ip.DoneUsers && ip.DoneFiles
+}
+
+func (worker *IndexerWorker) JobChannel() chan<- model.Job {
+	return worker.jobs
+}
+
+func (worker *IndexerWorker) IsEnabled(cfg *model.Config) bool {
+	license := worker.engine.server.License()
+	return license != nil && *license.Features.Elasticsearch && *cfg.ElasticsearchSettings.EnableIndexing
+}
+
+func (worker *IndexerWorker) Run() {
+	// Set to open if closed before. We are not bothered about multiple opens.
+	if atomic.CompareAndSwapInt32(&worker.closed, 1, 0) {
+		worker.stop = make(chan struct{})
+	}
+	mlog.Debug("Worker Started", mlog.String("workername", worker.name))
+
+	defer func() {
+		mlog.Debug("Worker: Finished", mlog.String("workername", worker.name))
+		worker.stopped <- true
+	}()
+
+	for {
+		select {
+		case <-worker.stop:
+			mlog.Debug("Worker: Received stop signal", mlog.String("workername", worker.name))
+			return
+		case job := <-worker.jobs:
+			mlog.Debug("Worker: Received a new candidate job.", mlog.String("workername", worker.name))
+			worker.DoJob(&job)
+		}
+	}
+}
+
+func (worker *IndexerWorker) Stop() {
+	// Set to close, and if already closed before, then return.
+	if !atomic.CompareAndSwapInt32(&worker.closed, 0, 1) {
+		return
+	}
+	mlog.Debug("Worker Stopping", mlog.String("workername", worker.name))
+	close(worker.stop)
+	<-worker.stopped
+}
+
+func (worker *IndexerWorker) setJobError(job *model.Job, appError *model.AppError) {
+	mlog.Error("Worker: Failed to run indexing job", mlog.String("workername", worker.name), mlog.String("job_id", job.Id), mlog.Err(appError))
+	if err := worker.jobServer.SetJobError(job, appError); err != nil {
+		mlog.Error("Worker: Failed to set job error", mlog.String("workername", worker.name), mlog.String("job_id", job.Id), mlog.Err(err), mlog.NamedErr("set_error", appError))
+	}
+}
+
+// initProgress reads the time range of the job and counts the entities to index.
+func (worker *IndexerWorker) initProgress(job *model.Job) (IndexingProgress, *model.AppError) {
+	progress := IndexingProgress{
+		Now:       time.Now(),
+		EndAtTime: model.GetMillis(),
+	}
+
+	// Extract the start and end times, if they are set.
+	if startString, ok := job.Data["start_time"]; ok {
+		startInt, err := strconv.ParseInt(startString, 10, 64)
+		if err != nil {
+			return progress, model.NewAppError("IndexerWorker", "ent.elasticsearch.indexer.do_job.parse_start_time.error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		progress.StartAtTime = startInt
+	} else {
+		// Set start time to oldest entity in the database.
+		// A user or a channel may be created before any post.
+		oldestEntityCreationTime, err := worker.jobServer.Store.Post().GetOldestEntityCreationTime()
+		if err != nil {
+			return progress, model.NewAppError("IndexerWorker", "ent.elasticsearch.indexer.do_job.get_oldest_entity.error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		progress.StartAtTime = oldestEntityCreationTime
+	}
+	progress.LastEntityTime = progress.StartAtTime
+
+	if endString, ok := job.Data["end_time"]; ok {
+		endInt, err := strconv.ParseInt(endString, 10, 64)
+		if err != nil {
+			return progress, model.NewAppError("IndexerWorker", "ent.elasticsearch.indexer.do_job.parse_end_time.error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		progress.EndAtTime = endInt
+	}
+
+	// Counting may fail or timeout when the tables are large. If this happens, log a warning, but carry
+	// on with the indexing job anyway. The only issue is that the progress % reporting will be inaccurate.
+	if count, err := worker.jobServer.Store.Post().AnalyticsPostCount("", false, false); err != nil {
+		mlog.Warn("Worker: Failed to fetch total post count for job. An estimated value will be used for progress reporting.", mlog.String("workername", worker.name), mlog.String("job_id", job.Id), mlog.Err(err))
+		progress.TotalPostsCount = EstimatedPostCount
+	} else {
+		progress.TotalPostsCount = count
+	}
+
+	if count, err := worker.jobServer.Store.Channel().AnalyticsTypeCount("", model.ChannelTypeOpen); err != nil {
+		mlog.Warn("Worker: Failed to fetch total channel count for job. An estimated value will be used for progress reporting.", mlog.String("workername", worker.name), mlog.String("job_id", job.Id), mlog.Err(err))
+		progress.TotalChannelsCount = EstimatedChannelCount
+	} else {
+		progress.TotalChannelsCount = count
+	}
+
+	if count, err := worker.jobServer.Store.User().Count(model.UserCountOptions{}); err != nil {
+		mlog.Warn("Worker: Failed to fetch total user count for job. An estimated value will be used for progress reporting.", mlog.String("workername", worker.name), mlog.String("job_id", job.Id), mlog.Err(err))
+		progress.TotalUsersCount = EstimatedUserCount
+	} else {
+		progress.TotalUsersCount = count
+	}
+
+	if count, err := worker.jobServer.Store.FileInfo().CountAll(); err != nil {
+		mlog.Warn("Worker: Failed to fetch total file info count for job. An estimated value will be used for progress reporting.", mlog.String("workername", worker.name), mlog.String("job_id", job.Id), mlog.Err(err))
+		progress.TotalFilesCount = EstimatedFilesCount
+	} else {
+		progress.TotalFilesCount = count
+	}
+
+	return progress, nil
+}
+
+func (worker *IndexerWorker) DoJob(job *model.Job) {
+	claimed, err := worker.jobServer.ClaimJob(job)
+	if err != nil {
+		mlog.Warn("Worker: Error occurred while trying to claim job", mlog.String("workername", worker.name), mlog.String("job_id", job.Id), mlog.Err(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	mlog.Info("Worker: Indexing job claimed by worker", mlog.String("workername", worker.name), mlog.String("job_id", job.Id))
+
+	if !worker.engine.isStarted() {
+		worker.setJobError(job, model.NewAppError("IndexerWorker", "ent.elasticsearch.not_started.error", nil, "", http.StatusInternalServerError))
+		return
+	}
+
+	progress, appErr := worker.initProgress(job)
+	if appErr != nil {
+		worker.setJobError(job, appErr)
+		return
+	}
+
+	cancelCtx, cancelCancelWatcher := context.WithCancel(context.Background())
+	cancelWatcherChan := make(chan struct{}, 1)
+	go worker.jobServer.CancellationWatcher(cancelCtx, job.Id, cancelWatcherChan)
+
+	defer cancelCancelWatcher()
+
+	for {
+		select {
+		case <-cancelWatcherChan:
+			mlog.Info("Worker: Indexing job has been canceled via CancellationWatcher", mlog.String("workername", worker.name), mlog.String("job_id", job.Id))
+			if err := worker.jobServer.SetJobCanceled(job); err != nil {
+				mlog.Error("Worker: Failed to mark job as cancelled", mlog.String("workername", worker.name), mlog.String("job_id", job.Id), mlog.Err(err))
+			}
+			return
+
+		case <-worker.stop:
+			mlog.Info("Worker: Indexing has been canceled via Worker Stop", mlog.String("workername", worker.name), mlog.String("job_id", job.Id))
+			if err := worker.jobServer.SetJobCanceled(job); err != nil {
+				mlog.Error("Worker: Failed to mark job as canceled", mlog.String("workername", worker.name), mlog.String("job_id", job.Id), mlog.Err(err))
+			}
+			return
+
+		case <-time.After(TimeBetweenBatches * time.Millisecond):
+			var err *model.AppError
+			if progress, err = worker.IndexBatch(progress); err != nil {
+				worker.setJobError(job, err)
+				return
+			}
+
+			if err := worker.jobServer.SetJobProgress(job, progress.CurrentProgress()); err != nil {
+				worker.setJobError(job, err)
+				return
+			}
+
+			if progress.IsDone() {
+				if err := worker.jobServer.SetJobSuccess(job); err != nil {
+					worker.setJobError(job, err)
+					return
+				}
+				mlog.Info("Worker: Indexing job finished successfully", mlog.String("workername", worker.name), mlog.String("job_id", job.Id))
+				return
+			}
+		}
+	}
+}
+
+func (worker *IndexerWorker) IndexBatch(progress IndexingProgress) (IndexingProgress, *model.AppError) {
+	if !progress.DonePosts {
+		err := worker.indexEntities(&progress, "posts", &progress.DonePosts, &progress.DonePostsCount, worker.indexPostsBatch)
+		return progress, err
+	}
+	if !progress.DoneChannels {
+		err := worker.indexEntities(&progress, "channels", &progress.DoneChannels, &progress.DoneChannelsCount, worker.indexChannelsBatch)
+		return progress, err
+	}
+	if !progress.DoneUsers {
+		err := worker.indexEntities(&progress, "users", &progress.DoneUsers, &progress.DoneUsersCount, worker.indexUsersBatch)
+		return progress, err
+	}
+	if !progress.DoneFiles {
+		err := worker.indexEntities(&progress, "files", &progress.DoneFiles, &progress.DoneFilesCount, worker.indexFilesBatch)
+		return progress, err
+	}
+	return progress, model.NewAppError("IndexerWorker", "ent.elasticsearch.indexer.index_batch.nothing_left_to_index.error", nil, "", http.StatusInternalServerError)
+}
+
+// indexEntities indexes the next batch of one kind of entity, created from
+// progress.LastEntityTime and within the bulk indexing time window. indexBatch
+// returns the size of the batch and the creation time of its last entity.
+func (worker *IndexerWorker) indexEntities(progress *IndexingProgress, entities string, done *bool, doneCount *int64, indexBatch func(startTime, endTime int64) (int, int64, *model.AppError)) *model.AppError {
+	endTime := progress.LastEntityTime + int64(*worker.jobServer.Config().ElasticsearchSettings.BulkIndexingTimeWindowSeconds*1000)
+
+	count, newLastEntityTime, err := indexBatch(progress.LastEntityTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	// Due to the "endTime" parameter in the store query, we might get an incomplete batch before the end. In this
+	// case, set the "newLastEntityTime" to the endTime so we don't get stuck running the same query in a loop.
+	if count < BatchSize {
+		newLastEntityTime = endTime
+	}
+
+	// When to Stop: we index either until we pass a batch of entities where the last
+	// entity is created at or after the specified end time when setting up the batch
+	// index, or until two consecutive full batches have the same end time of their final
+	// entities. This second case is safe as long as the assumption that the database
+	// cannot contain more entities with the same CreateAt time than the batch size holds.
+	if progress.EndAtTime <= newLastEntityTime {
+		*done = true
+		progress.LastEntityTime = progress.StartAtTime
+	} else if progress.LastEntityTime == newLastEntityTime && count == BatchSize {
+		mlog.Warn("More entities with the same CreateAt time were detected than the permitted batch size. Aborting indexing job.", mlog.String("entities", entities), mlog.Int64("CreateAt", newLastEntityTime), mlog.Int("Batch Size", BatchSize))
+		*done = true
+		progress.LastEntityTime = progress.StartAtTime
+	} else {
+		progress.LastEntityTime = newLastEntityTime
+	}
+
+	*doneCount += int64(count)
+
+	return nil
+}
+
+// fetchBatch calls fetch until it succeeds, waiting between the attempts.
+func fetchBatch(entities string, fetch func() error) error {
+	var err error
+	for tries := 0; tries < batchRetries; tries++ {
+		if err = fetch(); err == nil {
+			return nil
+		}
+		mlog.Warn("Failed to get batch for indexing. Retrying.", mlog.String("entities", entities), mlog.Err(err))
+
+		// Wait a bit before trying again.
+		time.Sleep(batchRetryDelay)
+	}
+	return err
+}
+
+// bulkIndex sends the requests of a batch, if any.
+func (worker *IndexerWorker) bulkIndex(requests []elastic.BulkableRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	worker.engine.mutex.RLock()
+	defer worker.engine.mutex.RUnlock()
+
+	if !worker.engine.isStarted() {
+		return model.NewAppError("IndexerWorker", "ent.elasticsearch.not_started.error", nil, "", http.StatusInternalServerError)
+	}
+	response, err := worker.engine.client.Bulk().Add(requests...).Do(context.Background())
+	if err != nil {
+		return err
+	}
+	return bulkErrors(response)
+}
+
+func (worker *IndexerWorker) indexPostsBatch(startTime, endTime int64) (int, int64, *model.AppError) {
+	var posts []*model.PostForIndexing
+	if err := fetchBatch("posts", func() (err error) {
+		posts, err = worker.jobServer.Store.Post().GetPostsBatchForIndexing(startTime, endTime, BatchSize)
+		return
+	}); err != nil {
+		return 0, 0, model.NewAppError("IndexerWorker", "ent.elasticsearch.post.get_posts_batch_for_indexing.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	lastCreateAt := int64(0)
+	requests := make([]elastic.BulkableRequest, 0, len(posts))
+	for _, post := range posts {
+		index := worker.engine.postIndexName(post.CreateAt)
+		if post.DeleteAt == 0 {
+			requests = append(requests, elastic.NewBulkIndexRequest().Index(index).Id(post.Id).Doc(ESPostFromPostForIndexing(post)))
+		} else {
+			requests = append(requests, elastic.NewBulkDeleteRequest().Index(index).Id(post.Id))
+		}
+		lastCreateAt = post.CreateAt
+	}
+
+	if err := worker.bulkIndex(requests); err != nil {
+		return 0, 0, model.NewAppError("IndexerWorker", "ent.elasticsearch.index_post.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return len(posts), lastCreateAt, nil
+}
+
+func (worker *IndexerWorker) indexChannelsBatch(startTime, endTime int64) (int, int64, *model.AppError) {
+	var channels []*model.Channel
+	if err := fetchBatch("channels", func() (err error) {
+		channels, err = worker.jobServer.Store.Channel().GetChannelsBatchForIndexing(startTime, endTime, BatchSize)
+		return
+	}); err != nil {
+		return 0, 0, model.NewAppError("IndexerWorker", "ent.elasticsearch.index_channels_batch.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	lastCreateAt := int64(0)
+	index := worker.engine.indexName(ChannelIndex)
+	requests := make([]elastic.BulkableRequest, 0, len(channels))
+	for _, channel := range channels {
+		if channel.DeleteAt == 0 {
+			var userIDs []string
+			var err error
+			if channel.Type == model.ChannelTypePrivate {
+				userIDs, err = worker.jobServer.Store.Channel().GetAllChannelMembersById(channel.Id)
+				if err != nil {
+					return 0, 0, model.NewAppError("IndexerWorker", "ent.elasticsearch.getAllChannelMembers.error", nil, err.Error(), http.StatusInternalServerError)
+				}
+			}
+
+			teamMemberIDs, err := worker.jobServer.Store.Channel().GetTeamMembersForChannel(channel.Id)
+			if err != nil {
+				return 0, 0, model.NewAppError("IndexerWorker", "ent.elasticsearch.getAllTeamMembers.error", nil, err.Error(), http.StatusInternalServerError)
+			}
+
+			requests = append(requests, elastic.NewBulkIndexRequest().Index(index).Id(channel.Id).Doc(ESChannelFromChannel(channel, userIDs, teamMemberIDs)))
+		} else {
+			requests = append(requests, elastic.NewBulkDeleteRequest().Index(index).Id(channel.Id))
+		}
+		lastCreateAt = channel.CreateAt
+	}
+
+	if err := worker.bulkIndex(requests); err != nil {
+		return 0, 0, model.NewAppError("IndexerWorker", "ent.elasticsearch.index_channel.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return len(channels), lastCreateAt, nil
+}
+
+func (worker *IndexerWorker) indexUsersBatch(startTime, endTime int64) (int, int64, *model.AppError) {
+	var users []*model.UserForIndexing
+	if err := fetchBatch("users", func() (err error) {
+		users, err = worker.jobServer.Store.User().GetUsersBatchForIndexing(startTime, endTime, BatchSize)
+		return
+	}); err != nil {
+		return 0, 0, model.NewAppError("IndexerWorker", "app.user.get_users_batch_for_indexing.get_users.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	// Deactivated users stay indexed, for the searches allowing inactive users.
+	lastCreateAt := int64(0)
+	index := worker.engine.indexName(UserIndex)
+	requests := make([]elastic.BulkableRequest, 0, len(users))
+	for _, user := range users {
+		requests = append(requests, elastic.NewBulkIndexRequest().Index(index).Id(user.Id).Doc(ESUserFromUserForIndexing(user)))
+		lastCreateAt = user.CreateAt
+	}
+
+	if err := worker.bulkIndex(requests); err != nil {
+		return 0, 0, model.NewAppError("IndexerWorker", "ent.elasticsearch.index_user.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return len(users), lastCreateAt, nil
+}
+
+func (worker *IndexerWorker) indexFilesBatch(startTime, endTime int64) (int, int64, *model.AppError) {
+	var files []*model.FileForIndexing
+	if err := fetchBatch("files", func() (err error) {
+		files, err = worker.jobServer.Store.FileInfo().GetFilesBatchForIndexing(startTime, endTime, BatchSize)
+		return
+	}); err != nil {
+		return 0, 0, model.NewAppError("IndexerWorker", "ent.elasticsearch.post.get_files_batch_for_indexing.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	lastCreateAt := int64(0)
+	index := worker.engine.indexName(FileIndex)
+	requests := make([]elastic.BulkableRequest, 0, len(files))
+	for _, file := range files {
+		if file.DeleteAt == 0 {
+			requests = append(requests, elastic.NewBulkIndexRequest().Index(index).Id(file.Id).Doc(ESFileFromFileForIndexing(file)))
+		} else {
+			requests = append(requests, elastic.NewBulkDeleteRequest().Index(index).Id(file.Id))
+		}
+		lastCreateAt = file.CreateAt
+	}
+
+	if err := worker.bulkIndex(requests); err != nil {
+		return 0, 0, model.NewAppError("IndexerWorker", "ent.elasticsearch.index_file.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return len(files), lastCreateAt, nil
+}
@@ -0,0 +1,709 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"gopkg.in/olivere/elastic.v6"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+var (
+	phraseRegex    = regexp.MustCompile(`"[^"]*"`)
+	highlightRegex = regexp.MustCompile(`<em>(.*?)</em>`)
+)
+
+// indexDocument writes a document, through the live indexing bulk processor
+// when it is enabled. Must be called with the mutex held.
+func (es *ElasticsearchInterfaceImpl) indexDocument(index, id string, doc interface{}) error {
+	if es.bulkProcessor != nil {
+		es.bulkProcessor.Add(elastic.NewBulkIndexRequest().Index(index).Id(id).Doc(doc))
+		return nil
+	}
+	_, err := es.client.Index().Index(index).Type(docType).Id(id).BodyJson(doc).Do(context.Background())
+	return err
+}
+
+// deleteDocument removes a document, through the live indexing bulk processor
+// when it is enabled, so that it is ordered with the writes. Must be called with
+// the mutex held.
+func (es *ElasticsearchInterfaceImpl) deleteDocument(index, id string) error {
+	if es.bulkProcessor != nil {
+		es.bulkProcessor.Add(elastic.NewBulkDeleteRequest().Index(index).Id(id))
+		return nil
+	}
+	_, err := es.client.Delete().Index(index).Type(docType).Id(id).Do(context.Background())
+	if elastic.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// deleteByQuery removes the documents matching query from the indexes matching
+// pattern. Pending live writes are flushed first so that none of them survives
+// the deletion. Must be called with the mutex held.
+func (es *ElasticsearchInterfaceImpl) deleteByQuery(pattern string, query elastic.Query, size int) (int64, error) {
+	if es.bulkProcessor != nil {
+		if err := es.bulkProcessor.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	service := es.client.DeleteByQuery(es.indexName(pattern)).
+		Query(query).
+		ProceedOnVersionConflict().
+		IgnoreUnavailable(true).
+		AllowNoIndices(true)
+	if size > 0 {
+		service = service.Size(size)
+	}
+	response, err := service.Do(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return response.Deleted, nil
+}
+
+// searchIds runs a search and returns the ids of the hits, in order and without
+// duplicates, along with the hits themselves. The total is requested as a
+// number, as the client expects, rather than the object of Elasticsearch 7 and
+// OpenSearch.
+func (es *ElasticsearchInterfaceImpl) searchIds(search *elastic.SearchService) ([]string, []*elastic.SearchHit, error) {
+	result, err := search.IgnoreUnavailable(true).AllowNoIndices(true).RestTotalHitsAsInt(true).Do(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := []string{}
+	hits := []*elastic.SearchHit{}
+	seen := map[string]bool{}
+	if result.Hits == nil {
+		return ids, hits, nil
+	}
+	for _, hit := range result.Hits.Hits {
+		// A post is briefly in two indexes while its daily index is aggregated.
+		if seen[hit.Id] {
+			continue
+		}
+		seen[hit.Id] = true
+		ids = append(ids, hit.Id)
+		hits = append(hits, hit)
+	}
+	return ids, hits, nil
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+func channelIds(channels model.ChannelList) []interface{} {
+	ids := make([]interface{}, len(channels))
+	for i, channel := range channels {
+		ids[i] = channel.Id
+	}
+	return ids
+}
+
+// dateFilters adds the date filters of params on field, as the database search does.
+func dateFilters(params *model.SearchParams, field string, filters, notFilters []elastic.Query) ([]elastic.Query, []elastic.Query) {
+	if params.OnDate != "" {
+		before, after := params.GetOnDateMillis()
+		return append(filters, elastic.NewRangeQuery(field).Gte(before).Lte(after)), notFilters
+	}
+
+	if params.AfterDate != "" || params.BeforeDate != "" {
+		dateQ := elastic.NewRangeQuery(field)
+		if params.AfterDate != "" {
+			dateQ = dateQ.Gte(params.GetAfterDateMillis())
+		}
+		if params.BeforeDate != "" {
+			dateQ = dateQ.Lte(params.GetBeforeDateMillis())
+		}
+		filters = append(filters, dateQ)
+	}
+
+	if params.ExcludedAfterDate != "" {
+		notFilters = append(notFilters, elastic.NewRangeQuery(field).Gte(params.GetExcludedAfterDateMillis()))
+	}
+
+	if params.ExcludedBeforeDate != "" {
+		notFilters = append(notFilters, elastic.NewRangeQuery(field).Lte(params.GetExcludedBeforeDateMillis()))
+	}
+
+	if params.ExcludedDate != "" {
+		before, after := params.GetExcludedDateMillis()
+		notFilters = append(notFilters, elastic.NewRangeQuery(field).Gte(before).Lte(after))
+	}
+
+	return filters, notFilters
+}
+
+// termsQueries turns search terms into queries on fields: quoted phrases are
+// matched as phrases, words ending with a star as prefixes and the other words
+// together, with operator.
+func termsQueries(terms string, operator string, fields ...string) []elastic.Query {
+	queries := []elastic.Query{}
+	field := func(build func(field string) elastic.Query) elastic.Query {
+		if len(fields) == 1 {
+			return build(fields[0])
+		}
+		disjunction := elastic.NewBoolQuery().MinimumNumberShouldMatch(1)
+		for _, f := range fields {
+			disjunction = disjunction.Should(build(f))
+		}
+		return disjunction
+	}
+
+	for _, phrase := range phraseRegex.FindAllString(terms, -1) {
+		phrase = strings.Trim(phrase, `"`)
+		if strings.TrimSpace(phrase) == "" {
+			continue
+		}
+		queries = append(queries, field(func(f string) elastic.Query {
+			return elastic.NewMatchPhraseQuery(f, phrase)
+		}))
+	}
+
+	words := []string{}
+	for _, word := range strings.Fields(phraseRegex.ReplaceAllString(terms, " ")) {
+		if strings.HasSuffix(word, "*") {
+			prefix := strings.ToLower(strings.TrimSuffix(word, "*"))
+			if prefix == "" {
+				continue
+			}
+			queries = append(queries, field(func(f string) elastic.Query {
+				return elastic.NewPrefixQuery(f, prefix)
+			}))
+		} else {
+			words = append(words, word)
+		}
+	}
+
+	if len(words) > 0 {
+		text := strings.Join(words, " ")
+		queries = append(queries, field(func(f string) elastic.Query {
+			return elastic.NewMatchQuery(f, text).Operator(operator)
+		}))
+	}
+
+	return queries
+}
+
+// searchQuery combines the parts of a post or file search into its query.
+func searchQuery(orTerms bool, termQueries, notTermQueries, filters, notFilters []elastic.Query) *elastic.BoolQuery {
+	query := elastic.NewBoolQuery().Filter(filters...)
+	if len(notFilters) > 0 {
+		query = query.MustNot(notFilters...)
+	}
+	if len(notTermQueries) > 0 {
+		query = query.MustNot(notTermQueries...)
+	}
+	if len(termQueries) > 0 {
+		if orTerms {
+			query = query.Must(elastic.NewBoolQuery().Should(termQueries...).MinimumNumberShouldMatch(1))
+		} else {
+			query = query.Must(termQueries...)
+		}
+	}
+	return query
+}
+
+func termOperator(searchParams []*model.SearchParams) string {
+	if searchParams[0].OrTerms {
+		return "or"
+	}
+	return "and"
+}
+
+func (es *ElasticsearchInterfaceImpl) IndexPost(post *model.Post, teamId string) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.IndexPost"); appErr != nil {
+		return appErr
+	}
+
+	esPost := ESPostFromPost(post, teamId)
+	if err := es.indexDocument(es.postIndexName(post.CreateAt), esPost.Id, esPost); err != nil {
+		return model.NewAppError("Elasticsearch.IndexPost", "ent.elasticsearch.index_post.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) SearchPosts(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, model.PostSearchMatches, *model.AppError) {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.SearchPosts"); appErr != nil {
+		return nil, nil, appErr
+	}
+
+	filters := []elastic.Query{
+		elastic.NewTermsQuery("channel_id", channelIds(channels)...),
+		// Only user posts are searchable, not system messages.
+		elastic.NewTermQuery("type", ""),
+	}
+	var notFilters, termQueries, notTermQueries []elastic.Query
+	operator := termOperator(searchParams)
+
+	for i, params := range searchParams {
+		// Date, channels and FromUsers filters come in all
+		// searchParams iteration, and as they are global to the
+		// query, we only need to process them once
+		if i == 0 {
+			if len(params.InChannels) > 0 {
+				filters = append(filters, elastic.NewTermsQuery("channel_id", stringsToInterfaces(params.InChannels)...))
+			}
+			if len(params.ExcludedChannels) > 0 {
+				notFilters = append(notFilters, elastic.NewTermsQuery("channel_id", stringsToInterfaces(params.ExcludedChannels)...))
+			}
+			if len(params.FromUsers) > 0 {
+				filters = append(filters, elastic.NewTermsQuery("user_id", stringsToInterfaces(params.FromUsers)...))
+			}
+			if len(params.ExcludedUsers) > 0 {
+				notFilters = append(notFilters, elastic.NewTermsQuery("user_id", stringsToInterfaces(params.ExcludedUsers)...))
+			}
+			filters, notFilters = dateFilters(params, "create_at", filters, notFilters)
+		}
+
+		if params.IsHashtag {
+			if params.Terms != "" {
+				termQueries = append(termQueries, elastic.NewMatchQuery("hashtags", params.Terms).Operator(operator))
+			} else if params.ExcludedTerms != "" {
+				notTermQueries = append(notTermQueries, elastic.NewMatchQuery("hashtags", params.ExcludedTerms).Operator(operator))
+			}
+		} else {
+			if params.Terms != "" {
+				termQueries = append(termQueries, termsQueries(params.Terms, operator, "message")...)
+			}
+			if params.ExcludedTerms != "" {
+				notTermQueries = append(notTermQueries, termsQueries(params.ExcludedTerms, "or", "message")...)
+			}
+		}
+	}
+
+	search := es.client.Search(es.indexName(PostIndex+"_*")).
+		Query(searchQuery(searchParams[0].OrTerms, termQueries, notTermQueries, filters, notFilters)).
+		Sort("create_at", false).
+		From(page * perPage).
+		Size(perPage).
+		Highlight(elastic.NewHighlight().Field("message").Field("hashtags").PreTags("<em>").PostTags("</em>").NumOfFragments(0))
+
+	postIds, hits, err := es.searchIds(search)
+	if err != nil {
+		return nil, nil, model.NewAppError("Elasticsearch.SearchPosts", "ent.elasticsearch.search_posts.search_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	matches := model.PostSearchMatches{}
+	for _, hit := range hits {
+		matches[hit.Id] = highlightedTerms(hit.Highlight)
+	}
+
+	return postIds, matches, nil
+}
+
+// highlightedTerms returns the distinct terms the search highlighted in a hit.
+func highlightedTerms(highlight elastic.SearchHitHighlight) []string {
+	terms := []string{}
+	seen := map[string]bool{}
+	for _, fragments := range highlight {
+		for _, fragment := range fragments {
+			for _, match := range highlightRegex.FindAllStringSubmatch(fragment, -1) {
+				if !seen[match[1]] {
+					seen[match[1]] = true
+					terms = append(terms, match[1])
+				}
+			}
+		}
+	}
+	return terms
+}
+
+func (es *ElasticsearchInterfaceImpl) DeletePost(post *model.Post) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.DeletePost"); appErr != nil {
+		return appErr
+	}
+
+	if err := es.deleteDocument(es.postIndexName(post.CreateAt), post.Id); err != nil {
+		return model.NewAppError("Elasticsearch.DeletePost", "ent.elasticsearch.delete_post.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) DeleteChannelPosts(channelID string) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.DeleteChannelPosts"); appErr != nil {
+		return appErr
+	}
+
+	deleted, err := es.deleteByQuery(PostIndex+"_*", elastic.NewTermQuery("channel_id", channelID), 0)
+	if err != nil {
+		return model.NewAppError("Elasticsearch.DeleteChannelPosts", "ent.elasticsearch.delete_channel_posts.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	mlog.Info("Posts for channel deleted", mlog.String("channel_id", channelID), mlog.Int64("deleted", deleted))
+
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) DeleteUserPosts(userID string) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.DeleteUserPosts"); appErr != nil {
+		return appErr
+	}
+
+	deleted, err := es.deleteByQuery(PostIndex+"_*", elastic.NewTermQuery("user_id", userID), 0)
+	if err != nil {
+		return model.NewAppError("Elasticsearch.DeleteUserPosts", "ent.elasticsearch.delete_user_posts.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	mlog.Info("Posts for user deleted", mlog.String("user_id", userID), mlog.Int64("deleted", deleted))
+
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) IndexChannel(channel *model.Channel, userIDs, teamMemberIDs []string) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.IndexChannel"); appErr != nil {
+		return appErr
+	}
+
+	esChannel := ESChannelFromChannel(channel, userIDs, teamMemberIDs)
+	if err := es.indexDocument(es.indexName(ChannelIndex), esChannel.Id, esChannel); err != nil {
+		return model.NewAppError("Elasticsearch.IndexChannel", "ent.elasticsearch.index_channel.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) SearchChannels(teamId, userID, term string) ([]string, *model.AppError) {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.SearchChannels"); appErr != nil {
+		return nil, appErr
+	}
+
+	// Public channels of the team, or of the teams of the user when no team is
+	// given, and the private ones the user is a member of.
+	query := elastic.NewBoolQuery()
+	if teamId != "" {
+		query = query.Filter(elastic.NewTermQuery("team_id", teamId))
+	} else {
+		query = query.Filter(elastic.NewTermQuery("team_member_ids", userID))
+	}
+
+	privateQ := elastic.NewTermQuery("type", string(model.ChannelTypePrivate))
+	query = query.Filter(elastic.NewBoolQuery().
+		Should(
+			elastic.NewBoolQuery().MustNot(privateQ),
+			elastic.NewBoolQuery().Filter(privateQ, elastic.NewTermQuery("user_ids", userID)),
+		).
+		MinimumNumberShouldMatch(1))
+
+	if term != "" {
+		query = query.Filter(elastic.NewPrefixQuery("name_suggestions", strings.ToLower(term)))
+	}
+
+	search := es.client.Search(es.indexName(ChannelIndex)).Query(query).Size(model.ChannelSearchDefaultLimit)
+	channelIds, _, err := es.searchIds(search)
+	if err != nil {
+		return nil, model.NewAppError("Elasticsearch.SearchChannels", "ent.elasticsearch.search_channels.search_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return channelIds, nil
+}
+
+func (es *ElasticsearchInterfaceImpl) DeleteChannel(channel *model.Channel) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.DeleteChannel"); appErr != nil {
+		return appErr
+	}
+
+	if err := es.deleteDocument(es.indexName(ChannelIndex), channel.Id); err != nil {
+		return model.NewAppError("Elasticsearch.DeleteChannel", "ent.elasticsearch.delete_channel.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) IndexUser(user *model.User, teamsIds, channelsIds []string) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.IndexUser"); appErr != nil {
+		return appErr
+	}
+
+	esUser := ESUserFromUserAndTeams(user, teamsIds, channelsIds)
+	if err := es.indexDocument(es.indexName(UserIndex), esUser.Id, esUser); err != nil {
+		return model.NewAppError("Elasticsearch.IndexUser", "ent.elasticsearch.index_user.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func userTermQuery(term string, options *model.UserSearchOptions) elastic.Query {
+	if options.AllowFullNames {
+		return elastic.NewPrefixQuery("suggestions_with_fullname", strings.ToLower(term))
+	}
+	return elastic.NewPrefixQuery("suggestions_without_fullname", strings.ToLower(term))
+}
+
+func (es *ElasticsearchInterfaceImpl) SearchUsersInChannel(teamId, channelId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, []string, *model.AppError) {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.SearchUsersInChannel"); appErr != nil {
+		return nil, nil, appErr
+	}
+
+	if restrictedToChannels != nil && len(restrictedToChannels) == 0 {
+		return []string{}, []string{}, nil
+	}
+
+	// users in channel
+	uchanQ := elastic.NewBoolQuery().Filter(elastic.NewTermQuery("channel_ids", channelId))
+	if term != "" {
+		uchanQ = uchanQ.Filter(userTermQuery(term, options))
+	}
+	if !options.AllowInactive {
+		uchanQ = uchanQ.Filter(elastic.NewTermQuery("delete_at", 0))
+	}
+
+	uchanIds, _, err := es.searchIds(es.client.Search(es.indexName(UserIndex)).Query(uchanQ).Size(options.Limit))
+	if err != nil {
+		return nil, nil, model.NewAppError("Elasticsearch.SearchUsersInChannel", "ent.elasticsearch.search_users.search_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	// users not in channel
+	nuchanQ := elastic.NewBoolQuery().
+		Filter(elastic.NewTermQuery("team_ids", teamId)).
+		MustNot(elastic.NewTermQuery("channel_ids", channelId))
+	if term != "" {
+		nuchanQ = nuchanQ.Filter(userTermQuery(term, options))
+	}
+	if !options.AllowInactive {
+		nuchanQ = nuchanQ.Filter(elastic.NewTermQuery("delete_at", 0))
+	}
+	if len(restrictedToChannels) > 0 {
+		nuchanQ = nuchanQ.Filter(elastic.NewTermsQuery("channel_ids", stringsToInterfaces(restrictedToChannels)...))
+	}
+
+	nuchanIds, _, err := es.searchIds(es.client.Search(es.indexName(UserIndex)).Query(nuchanQ).Size(options.Limit))
+	if err != nil {
+		return nil, nil, model.NewAppError("Elasticsearch.SearchUsersInChannel", "ent.elasticsearch.search_users.search_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return uchanIds, nuchanIds, nil
+}
+
+func (es *ElasticsearchInterfaceImpl) SearchUsersInTeam(teamId string, restrictedToChannels []string, term string, options *model.UserSearchOptions) ([]string, *model.AppError) {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.SearchUsersInTeam"); appErr != nil {
+		return nil, appErr
+	}
+
+	if restrictedToChannels != nil && len(restrictedToChannels) == 0 {
+		return []string{}, nil
+	}
+
+	query := elastic.NewBoolQuery()
+	if term != "" {
+		query = query.Filter(userTermQuery(term, options))
+	}
+	if len(restrictedToChannels) > 0 {
+		// restricted channels are already filtered by team, so we
+		// can search only those matches
+		query = query.Filter(elastic.NewTermsQuery("channel_ids", stringsToInterfaces(restrictedToChannels)...))
+	} else if teamId != "" {
+		// this means that we only need to restrict by team
+		query = query.Filter(elastic.NewTermQuery("team_ids", teamId))
+	}
+	if !options.AllowInactive {
+		query = query.Filter(elastic.NewTermQuery("delete_at", 0))
+	}
+
+	usersIds, _, err := es.searchIds(es.client.Search(es.indexName(UserIndex)).Query(query).Size(options.Limit))
+	if err != nil {
+		return nil, model.NewAppError("Elasticsearch.SearchUsersInTeam", "ent.elasticsearch.search_users.search_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return usersIds, nil
+}
+
+func (es *ElasticsearchInterfaceImpl) DeleteUser(user *model.User) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.DeleteUser"); appErr != nil {
+		return appErr
+	}
+
+	if err := es.deleteDocument(es.indexName(UserIndex), user.Id); err != nil {
+		return model.NewAppError("Elasticsearch.DeleteUser", "ent.elasticsearch.delete_user.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) IndexFile(file *model.FileInfo, channelId string) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.IndexFile"); appErr != nil {
+		return appErr
+	}
+
+	esFile := ESFileFromFileInfo(file, channelId)
+	if err := es.indexDocument(es.indexName(FileIndex), esFile.Id, esFile); err != nil {
+		return model.NewAppError("Elasticsearch.IndexFile", "ent.elasticsearch.index_file.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) SearchFiles(channels model.ChannelList, searchParams []*model.SearchParams, page, perPage int) ([]string, *model.AppError) {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.SearchFiles"); appErr != nil {
+		return nil, appErr
+	}
+
+	filters := []elastic.Query{elastic.NewTermsQuery("channel_id", channelIds(channels)...)}
+	var notFilters, termQueries, notTermQueries []elastic.Query
+	operator := termOperator(searchParams)
+
+	for i, params := range searchParams {
+		// Date, channels and FromUsers filters come in all
+		// searchParams iteration, and as they are global to the
+		// query, we only need to process them once
+		if i == 0 {
+			if len(params.InChannels) > 0 {
+				filters = append(filters, elastic.NewTermsQuery("channel_id", stringsToInterfaces(params.InChannels)...))
+			}
+			if len(params.ExcludedChannels) > 0 {
+				notFilters = append(notFilters, elastic.NewTermsQuery("channel_id", stringsToInterfaces(params.ExcludedChannels)...))
+			}
+			if len(params.FromUsers) > 0 {
+				filters = append(filters, elastic.NewTermsQuery("creator_id", stringsToInterfaces(params.FromUsers)...))
+			}
+			if len(params.ExcludedUsers) > 0 {
+				notFilters = append(notFilters, elastic.NewTermsQuery("creator_id", stringsToInterfaces(params.ExcludedUsers)...))
+			}
+			if len(params.Extensions) > 0 {
+				filters = append(filters, elastic.NewTermsQuery("extension", stringsToInterfaces(params.Extensions)...))
+			}
+			if len(params.ExcludedExtensions) > 0 {
+				notFilters = append(notFilters, elastic.NewTermsQuery("extension", stringsToInterfaces(params.ExcludedExtensions)...))
+			}
+			filters, notFilters = dateFilters(params, "create_at", filters, notFilters)
+		}
+
+		if params.Terms != "" {
+			termQueries = append(termQueries, termsQueries(params.Terms, operator, "name", "content")...)
+		}
+		if params.ExcludedTerms != "" {
+			notTermQueries = append(notTermQueries, termsQueries(params.ExcludedTerms, "or", "name", "content")...)
+		}
+	}
+
+	search := es.client.Search(es.indexName(FileIndex)).
+		Query(searchQuery(searchParams[0].OrTerms, termQueries, notTermQueries, filters, notFilters)).
+		Sort("create_at", false).
+		From(page * perPage).
+		Size(perPage)
+
+	fileIds, _, err := es.searchIds(search)
+	if err != nil {
+		return nil, model.NewAppError("Elasticsearch.SearchFiles", "ent.elasticsearch.search_files.search_failed", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return fileIds, nil
+}
+
+func (es *ElasticsearchInterfaceImpl) DeleteFile(fileID string) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.DeleteFile"); appErr != nil {
+		return appErr
+	}
+
+	if err := es.deleteDocument(es.indexName(FileIndex), fileID); err != nil {
+		return model.NewAppError("Elasticsearch.DeleteFile", "ent.elasticsearch.delete_file.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) DeleteUserFiles(userID string) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.DeleteUserFiles"); appErr != nil {
+		return appErr
+	}
+
+	deleted, err := es.deleteByQuery(FileIndex, elastic.NewTermQuery("creator_id", userID), 0)
+	if err != nil {
+		return model.NewAppError("Elasticsearch.DeleteUserFiles", "ent.elasticsearch.delete_user_files.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	mlog.Info("Files for user deleted", mlog.String("user_id", userID), mlog.Int64("deleted", deleted))
+
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) DeletePostFiles(postID string) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.DeletePostFiles"); appErr != nil {
+		return appErr
+	}
+
+	deleted, err := es.deleteByQuery(FileIndex, elastic.NewTermQuery("post_id", postID), 0)
+	if err != nil {
+		return model.NewAppError("Elasticsearch.DeletePostFiles", "ent.elasticsearch.delete_post_files.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	mlog.Info("Files for post deleted", mlog.String("post_id", postID), mlog.Int64("deleted", deleted))
+
+	return nil
+}
+
+func (es *ElasticsearchInterfaceImpl) DeleteFilesBatch(endTime, limit int64) *model.AppError {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	if appErr := es.checkStarted("Elasticsearch.DeleteFilesBatch"); appErr != nil {
+		return appErr
+	}
+
+	deleted, err := es.deleteByQuery(FileIndex, elastic.NewRangeQuery("create_at").Lte(endTime), int(limit))
+	if err != nil {
+		return model.NewAppError("Elasticsearch.DeleteFilesBatch", "ent.elasticsearch.delete_file.error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	mlog.Info("Files in batch deleted", mlog.Int64("endTime", endTime), mlog.Int64("limit", limit), mlog.Int64("deleted", deleted))
+
+	return nil
+}
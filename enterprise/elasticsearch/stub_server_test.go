@@ -0,0 +1,298 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stubServer is a minimal in-process server for the subset of the REST API of
+// Elasticsearch and OpenSearch used by the implementation. It stores documents
+// and templates, and records the requests so that the tests can check the
+// queries sent, but does not evaluate the queries: a search returns every
+// document of the indexes searched, newest first.
+type stubServer struct {
+	*httptest.Server
+
+	mut       sync.Mutex
+	version   string
+	templates map[string]json.RawMessage
+	indexes   map[string]map[string]json.RawMessage
+	requests  []stubRequest
+	highlight map[string][]string
+}
+
+type stubRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+func newStubServer(t *testing.T) *stubServer {
+	s := &stubServer{
+		version:   "2.11.0",
+		templates: make(map[string]json.RawMessage),
+		indexes:   make(map[string]map[string]json.RawMessage),
+		highlight: make(map[string][]string),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// addDocument stores a document directly, as if it had been indexed earlier.
+func (s *stubServer) addDocument(index, id string, doc interface{}) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	data, _ := json.Marshal(doc)
+	s.document(index, id, data)
+}
+
+// document stores a document. Must be called with the mutex held.
+func (s *stubServer) document(index, id string, data json.RawMessage) {
+	if s.indexes[index] == nil {
+		s.indexes[index] = make(map[string]json.RawMessage)
+	}
+	s.indexes[index][id] = data
+}
+
+func (s *stubServer) indexNames() []string {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	names := []string{}
+	for name := range s.indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *stubServer) documentIds(index string) []string {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	ids := []string{}
+	for id := range s.indexes[index] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// lastRequest returns the last request with method on a path ending with suffix.
+func (s *stubServer) lastRequest(method, suffix string) *stubRequest {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if s.requests[i].Method == method && strings.HasSuffix(s.requests[i].Path, suffix) {
+			return &s.requests[i]
+		}
+	}
+	return nil
+}
+
+func (s *stubServer) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.requests = append(s.requests, stubRequest{Method: r.Method, Path: r.URL.Path, Body: body})
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.URL.Path == "/":
+		s.write(w, http.StatusOK, map[string]interface{}{
+			"version": map[string]interface{}{"distribution": "opensearch", "number": s.version},
+		})
+	case parts[0] == "_nodes":
+		s.write(w, http.StatusOK, map[string]interface{}{
+			"nodes": map[string]interface{}{
+				"node1": map[string]interface{}{"plugins": []map[string]string{{"name": "analysis-icu"}}},
+			},
+		})
+	case parts[0] == "_template" && r.Method == http.MethodPut:
+		s.templates[parts[1]] = body
+		s.write(w, http.StatusOK, map[string]interface{}{"acknowledged": true})
+	case parts[0] == "_cat":
+		rows := []map[string]string{}
+		for _, name := range s.matching(parts[2]) {
+			rows = append(rows, map[string]string{"index": name})
+		}
+		s.write(w, http.StatusOK, rows)
+	case parts[0] == "_bulk":
+		s.bulk(w, body)
+	case parts[0] == "_reindex":
+		s.reindex(w, body)
+	case parts[0] == "_tasks":
+		s.write(w, http.StatusOK, map[string]interface{}{"completed": true, "response": map[string]interface{}{"failures": []interface{}{}}})
+	case len(parts) == 3 && parts[1] == "_doc":
+		s.singleDocument(w, r.Method, parts[0], parts[2], body)
+	case len(parts) == 2 && parts[1] == "_search":
+		s.search(w, parts[0])
+	case len(parts) == 2 && parts[1] == "_delete_by_query":
+		s.write(w, http.StatusOK, map[string]interface{}{"deleted": 0})
+	case len(parts) == 2 && parts[1] == "_refresh":
+		s.write(w, http.StatusOK, map[string]interface{}{})
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		for _, name := range strings.Split(parts[0], ",") {
+			delete(s.indexes, name)
+		}
+		s.write(w, http.StatusOK, map[string]interface{}{"acknowledged": true})
+	default:
+		s.write(w, http.StatusOK, map[string]interface{}{})
+	}
+}
+
+func (s *stubServer) write(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// matching returns the names of the indexes matching the comma separated patterns.
+func (s *stubServer) matching(patterns string) []string {
+	names := []string{}
+	for name := range s.indexes {
+		for _, pattern := range strings.Split(patterns, ",") {
+			if ok, _ := path.Match(pattern, name); ok {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *stubServer) singleDocument(w http.ResponseWriter, method, index, id string, body []byte) {
+	switch method {
+	case http.MethodPut, http.MethodPost:
+		s.document(index, id, body)
+		s.write(w, http.StatusCreated, map[string]interface{}{"_index": index, "_id": id, "result": "created"})
+	case http.MethodDelete:
+		if _, ok := s.indexes[index][id]; !ok {
+			s.write(w, http.StatusNotFound, map[string]interface{}{"_index": index, "_id": id, "result": "not_found"})
+			return
+		}
+		delete(s.indexes[index], id)
+		s.write(w, http.StatusOK, map[string]interface{}{"_index": index, "_id": id, "result": "deleted"})
+	}
+}
+
+func (s *stubServer) bulk(w http.ResponseWriter, body []byte) {
+	type action struct {
+		Index string `json:"_index"`
+		Id    string `json:"_id"`
+	}
+
+	items := []map[string]interface{}{}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var line map[string]action
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if a, ok := line["index"]; ok && scanner.Scan() {
+			s.document(a.Index, a.Id, append(json.RawMessage{}, scanner.Bytes()...))
+			items = append(items, map[string]interface{}{"index": map[string]interface{}{"_index": a.Index, "_id": a.Id, "status": http.StatusCreated}})
+		} else if a, ok := line["delete"]; ok {
+			status := http.StatusOK
+			if _, found := s.indexes[a.Index][a.Id]; !found {
+				status = http.StatusNotFound
+			}
+			delete(s.indexes[a.Index], a.Id)
+			items = append(items, map[string]interface{}{"delete": map[string]interface{}{"_index": a.Index, "_id": a.Id, "status": status}})
+		}
+	}
+
+	errors := false
+	for _, item := range items {
+		for _, result := range item {
+			if result.(map[string]interface{})["status"] == http.StatusNotFound {
+				errors = true
+			}
+		}
+	}
+	s.write(w, http.StatusOK, map[string]interface{}{"took": 1, "errors": errors, "items": items})
+}
+
+func (s *stubServer) search(w http.ResponseWriter, patterns string) {
+	type hit struct {
+		Index     string              `json:"_index"`
+		Id        string              `json:"_id"`
+		Source    json.RawMessage     `json:"_source"`
+		Highlight map[string][]string `json:"highlight,omitempty"`
+		createAt  int64
+	}
+
+	hits := []hit{}
+	for _, name := range s.matching(patterns) {
+		for id, doc := range s.indexes[name] {
+			var fields struct {
+				CreateAt int64 `json:"create_at"`
+			}
+			json.Unmarshal(doc, &fields)
+			h := hit{Index: name, Id: id, Source: doc, createAt: fields.CreateAt}
+			if terms, ok := s.highlight[id]; ok {
+				h.Highlight = map[string][]string{"message": terms}
+			}
+			hits = append(hits, h)
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].createAt != hits[j].createAt {
+			return hits[i].createAt > hits[j].createAt
+		}
+		return hits[i].Id < hits[j].Id
+	})
+
+	s.write(w, http.StatusOK, map[string]interface{}{
+		"took": 1,
+		"hits": map[string]interface{}{"total": len(hits), "hits": hits},
+	})
+}
+
+func (s *stubServer) reindex(w http.ResponseWriter, body []byte) {
+	var request struct {
+		Source struct {
+			Index json.RawMessage `json:"index"`
+		} `json:"source"`
+		Dest struct {
+			Index string `json:"index"`
+		} `json:"dest"`
+	}
+	json.Unmarshal(body, &request)
+
+	var sources []string
+	if err := json.Unmarshal(request.Source.Index, &sources); err != nil {
+		var source string
+		json.Unmarshal(request.Source.Index, &source)
+		sources = strings.Split(source, ",")
+	}
+
+	// The documents already in the destination are kept, as with the create op type.
+	for _, source := range sources {
+		for id, doc := range s.indexes[source] {
+			if _, ok := s.indexes[request.Dest.Index][id]; !ok {
+				s.document(request.Dest.Index, id, doc)
+			}
+		}
+	}
+	s.write(w, http.StatusOK, map[string]interface{}{"task": "stub:1"})
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ldap
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/ldap"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// DefaultGroupFilter matches the group object classes of Active Directory and OpenLDAP.
+const DefaultGroupFilter = "(|(objectClass=group)(objectClass=groupOfNames)(objectClass=groupOfUniqueNames))"
+
+// groupMemberAttributes hold the DNs of the members of a group.
+var groupMemberAttributes = []string{"member", "uniqueMember"}
+
+// groupMemberUidAttribute holds the login ids of the members of a posixGroup.
+const groupMemberUidAttribute = "memberUid"
+
+// connect opens a connection to the configured server and binds it with the
+// configured service account.
+func (l *MatterfossLdap) connect() (*ldap.Conn, *model.AppError) {
+	conn, appErr := l.dial()
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	settings := l.app.Config().LdapSettings
+	if *settings.BindUsername == "" && *settings.BindPassword == "" {
+		err := conn.UnauthenticatedBind("")
+		if err != nil {
+			conn.Close()
+			return nil, model.NewAppError("connect", "ent.ldap.do_login.bind_admin_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		return conn, nil
+	}
+
+	if err := conn.Bind(*settings.BindUsername, *settings.BindPassword); err != nil {
+		conn.Close()
+		return nil, model.NewAppError("connect", "ent.ldap.do_login.bind_admin_user.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return conn, nil
+}
+
+// dial opens an unbound connection honouring the configured connection security.
+func (l *MatterfossLdap) dial() (*ldap.Conn, *model.AppError) {
+	settings := l.app.Config().LdapSettings
+	address := net.JoinHostPort(*settings.LdapServer, strconv.Itoa(*settings.LdapPort))
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: *settings.SkipCertificateVerification,
+		ServerName:         *settings.LdapServer,
+	}
+
+	if *settings.PublicCertificateFile != "" && *settings.PrivateKeyFile != "" {
+		cert, appErr := l.clientCertificate()
+		if appErr != nil {
+			return nil, appErr
+		}
+		tlsConfig.Certificates = []tls.Certificate{*cert}
+	}
+
+	var conn *ldap.Conn
+	var err error
+	switch *settings.ConnectionSecurity {
+	case model.ConnSecurityTLS:
+		conn, err = ldap.DialTLS("tcp", address, tlsConfig)
+	default:
+		conn, err = ldap.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, model.NewAppError("dial", "ent.ldap.do_login.unable_to_connect.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	conn.Start()
+
+	if *settings.ConnectionSecurity == model.ConnSecurityStarttls {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, model.NewAppError("dial", "ent.ldap.do_login.unable_to_connect.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	conn.SetTimeout(time.Duration(*settings.QueryTimeout) * time.Second)
+	conn.Debug.Enable(*settings.Trace)
+
+	return conn, nil
+}
+
+func (l *MatterfossLdap) clientCertificate() (*tls.Certificate, *model.AppError) {
+	settings := l.app.Config().LdapSettings
+
+	certPEM, err := l.app.GetConfigFile(*settings.PublicCertificateFile)
+	if err != nil {
+		return nil, model.NewAppError("clientCertificate", "ent.ldap.do_login.certificate.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	keyPEM, err := l.app.GetConfigFile(*settings.PrivateKeyFile)
+	if err != nil {
+		return nil, model.NewAppError("clientCertificate", "ent.ldap.do_login.key.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, model.NewAppError("clientCertificate", "ent.ldap.do_login.x509.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return &cert, nil
+}
+
+// search runs a paged subtree search under the base DN.
+func (l *MatterfossLdap) search(conn *ldap.Conn, filter string, attributes []string) ([]*ldap.Entry, *model.AppError) {
+	settings := l.app.Config().LdapSettings
+
+	request := ldap.NewSearchRequest(
+		*settings.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.DerefAlways,
+		0,
+		*settings.QueryTimeout,
+		false,
+		filter,
+		attributes,
+		nil,
+	)
+
+	result, err := conn.SearchWithPaging(request, uint32(*settings.MaxPageSize))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+			return nil, model.NewAppError("search", "ent.ldap.syncronize.search_failure_size_exceeded.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		return nil, model.NewAppError("search", "ent.ldap.syncronize.search_failure.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return result.Entries, nil
+}
+
+// userAttributes returns the attributes to fetch for every user entry.
+func userAttributes(settings *model.LdapSettings) []string {
+	attributes := []string{}
+	for _, attribute := range []string{
+		*settings.IdAttribute,
+		*settings.UsernameAttribute,
+		*settings.EmailAttribute,
+		*settings.FirstNameAttribute,
+		*settings.LastNameAttribute,
+		*settings.NicknameAttribute,
+		*settings.PositionAttribute,
+		*settings.LoginIdAttribute,
+	} {
+		if attribute != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+	return attributes
+}
+
+// userFilter restricts filter to the entries matched by the configured user filter.
+func userFilter(settings *model.LdapSettings, filter string) string {
+	if *settings.UserFilter == "" {
+		return filter
+	}
+	return "(&" + filter + wrapFilter(*settings.UserFilter) + ")"
+}
+
+// groupFilter restricts filter to the entries matched by the configured group filter.
+func groupFilter(settings *model.LdapSettings, filter string) string {
+	base := DefaultGroupFilter
+	if *settings.GroupFilter != "" {
+		base = wrapFilter(*settings.GroupFilter)
+	}
+	if filter == "" {
+		return base
+	}
+	return "(&" + base + filter + ")"
+}
+
+// wrapFilter adds the outer parentheses that administrators often leave out.
+func wrapFilter(filter string) string {
+	filter = strings.TrimSpace(filter)
+	if !strings.HasPrefix(filter, "(") {
+		return "(" + filter + ")"
+	}
+	return filter
+}
+
+func equalityFilter(attribute, value string) string {
+	return fmt.Sprintf("(%s=%s)", attribute, ldap.EscapeFilter(value))
+}
+
+// allUsersFilter matches every entry that has an id, so organizational units and
+// groups are left out when no user filter is configured.
+func allUsersFilter(settings *model.LdapSettings) string {
+	return userFilter(settings, "("+*settings.IdAttribute+"=*)")
+}
+
+// idFilter matches the user whose id attribute is authData, encoding objectGUIDs
+// back to their binary form.
+func idFilter(settings *model.LdapSettings, authData string) string {
+	if strings.EqualFold(*settings.IdAttribute, objectGUIDAttribute) {
+		if raw, ok := guidFromString(authData); ok {
+			var escaped strings.Builder
+			for _, b := range raw {
+				fmt.Fprintf(&escaped, "\\%02x", b)
+			}
+			return fmt.Sprintf("(%s=%s)", *settings.IdAttribute, escaped.String())
+		}
+	}
+	return equalityFilter(*settings.IdAttribute, authData)
+}
+
+// findUser returns the only user entry matched by filter.
+func (l *MatterfossLdap) findUser(conn *ldap.Conn, filter string) (*ldap.Entry, *model.AppError) {
+	settings := l.app.Config().LdapSettings
+
+	entries, appErr := l.search(conn, userFilter(&settings, filter), userAttributes(&settings))
+	if appErr != nil {
+		appErr.Id = "ent.ldap.do_login.search_ldap_server.app_error"
+		return nil, appErr
+	}
+
+	switch len(entries) {
+	case 0:
+		return nil, model.NewAppError("findUser", "ent.ldap.do_login.user_not_registered.app_error", nil, "", http.StatusBadRequest)
+	case 1:
+		return entries[0], nil
+	default:
+		return nil, model.NewAppError("findUser", "ent.ldap.do_login.matched_to_many_users.app_error", nil, "", http.StatusBadRequest)
+	}
+}
+
+// matchesFilter reports whether the entry with the given DN is matched by filter.
+func (l *MatterfossLdap) matchesFilter(conn *ldap.Conn, dn, filter string) (bool, error) {
+	request := ldap.NewSearchRequest(
+		dn,
+		ldap.ScopeBaseObject,
+		ldap.NeverDerefAliases,
+		0,
+		*l.app.Config().LdapSettings.QueryTimeout,
+		false,
+		wrapFilter(filter),
+		[]string{"dn"},
+		nil,
+	)
+
+	result, err := conn.Search(request)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return len(result.Entries) > 0, nil
+}
+
+// checkPassword binds a fresh connection as dn to verify password.
+func (l *MatterfossLdap) checkPassword(dn, password string) *model.AppError {
+	// An empty password would be an unauthenticated bind, which most servers accept.
+	if password == "" {
+		return model.NewAppError("checkPassword", "ent.ldap.do_login.invalid_password.app_error", nil, "", http.StatusUnauthorized)
+	}
+
+	conn, appErr := l.dial()
+	if appErr != nil {
+		return appErr
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(dn, password); err != nil {
+		return model.NewAppError("checkPassword", "ent.ldap.do_login.invalid_password.app_error", nil, err.Error(), http.StatusUnauthorized)
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ldap
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/ldap"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func groupAttributes(settings *model.LdapSettings) []string {
+	return append([]string{
+		*settings.GroupIdAttribute,
+		*settings.GroupDisplayNameAttribute,
+		groupMemberUidAttribute,
+	}, groupMemberAttributes...)
+}
+
+func entryToGroup(settings *model.LdapSettings, entry *ldap.Entry) *model.Group {
+	remoteID := attributeValue(entry, *settings.GroupIdAttribute)
+	displayName := attributeValue(entry, *settings.GroupDisplayNameAttribute)
+	if displayName == "" {
+		displayName = remoteID
+	}
+
+	return &model.Group{
+		DisplayName: displayName,
+		RemoteId:    model.NewString(remoteID),
+		Source:      model.GroupSourceLdap,
+	}
+}
+
+// memberFilter matches the groups that list the user entry as a member.
+func memberFilter(settings *model.LdapSettings, entry *ldap.Entry) string {
+	var filter strings.Builder
+	filter.WriteString("(|")
+	for _, attribute := range groupMemberAttributes {
+		filter.WriteString(equalityFilter(attribute, entry.DN))
+	}
+	if uid := attributeValue(entry, *settings.UsernameAttribute); uid != "" {
+		filter.WriteString(equalityFilter(groupMemberUidAttribute, uid))
+	}
+	filter.WriteString(")")
+	return filter.String()
+}
+
+// groupEntries returns the group entries matched by filter under the group filter.
+func (l *MatterfossLdap) groupEntries(conn *ldap.Conn, filter string) ([]*ldap.Entry, *model.AppError) {
+	settings := l.app.Config().LdapSettings
+
+	entries, appErr := l.search(conn, groupFilter(&settings, filter), groupAttributes(&settings))
+	if appErr != nil {
+		return nil, model.NewAppError("groupEntries", "ent.ldap_groups.groups_search_error", nil, appErr.Error(), http.StatusInternalServerError)
+	}
+
+	valid := entries[:0]
+	for _, entry := range entries {
+		if attributeValue(entry, *settings.GroupIdAttribute) != "" {
+			valid = append(valid, entry)
+		}
+	}
+
+	return valid, nil
+}
+
+// linkedGroups returns the live AD/LDAP groups of Matterfoss keyed by their remote id.
+func (l *MatterfossLdap) linkedGroups() (map[string]*model.Group, *model.AppError) {
+	groups, appErr := l.app.GetGroupsBySource(model.GroupSourceLdap)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	linked := make(map[string]*model.Group, len(groups))
+	for _, group := range groups {
+		if group.DeleteAt == 0 && group.RemoteId != nil {
+			linked[*group.RemoteId] = group
+		}
+	}
+
+	return linked, nil
+}
+
+func (l *MatterfossLdap) hasSyncables(groupID string) (bool, *model.AppError) {
+	for _, syncableType := range []model.GroupSyncableType{model.GroupSyncableTypeTeam, model.GroupSyncableTypeChannel} {
+		syncables, appErr := l.app.GetGroupSyncables(groupID, syncableType)
+		if appErr != nil {
+			return false, appErr
+		}
+		if len(syncables) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (l *MatterfossLdap) GetGroup(groupUID string) (*model.Group, *model.AppError) {
+	if appErr := l.checkEnabled("GetGroup"); appErr != nil {
+		return nil, appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return nil, appErr
+	}
+	defer conn.Close()
+
+	entries, appErr := l.groupEntries(conn, equalityFilter(*settings.GroupIdAttribute, groupUID))
+	if appErr != nil {
+		appErr.Id = "ent.ldap_groups.group_search_error"
+		return nil, appErr
+	}
+	if len(entries) == 0 {
+		return nil, model.NewAppError("GetGroup", "ent.ldap_groups.no_rows", nil, "remote_id="+groupUID, http.StatusNotFound)
+	}
+
+	return entryToGroup(&settings, entries[0]), nil
+}
+
+// GetAllGroupsPage lists the groups of the directory. Groups already linked to a
+// Matterfoss group carry its id so that the caller can tell them apart.
+func (l *MatterfossLdap) GetAllGroupsPage(page int, perPage int, opts model.LdapGroupSearchOpts) ([]*model.Group, int, *model.AppError) {
+	if appErr := l.checkEnabled("GetAllGroupsPage"); appErr != nil {
+		return nil, 0, appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return nil, 0, appErr
+	}
+	defer conn.Close()
+
+	filter := ""
+	if opts.Q != "" {
+		filter = fmt.Sprintf("(%s=*%s*)", *settings.GroupDisplayNameAttribute, ldap.EscapeFilter(opts.Q))
+	}
+
+	entries, appErr := l.groupEntries(conn, filter)
+	if appErr != nil {
+		return nil, 0, appErr
+	}
+
+	linked, appErr := l.linkedGroups()
+	if appErr != nil {
+		return nil, 0, appErr
+	}
+
+	groups := make([]*model.Group, 0, len(entries))
+	for _, entry := range entries {
+		group := entryToGroup(&settings, entry)
+		if existing, ok := linked[*group.RemoteId]; ok {
+			group.Id = existing.Id
+			if group.HasSyncables, appErr = l.hasSyncables(existing.Id); appErr != nil {
+				return nil, 0, appErr
+			}
+		}
+
+		if opts.IsLinked != nil && *opts.IsLinked != (group.Id != "") {
+			continue
+		}
+		if opts.IsConfigured != nil && *opts.IsConfigured != group.HasSyncables {
+			continue
+		}
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].DisplayName) < strings.ToLower(groups[j].DisplayName)
+	})
+
+	total := len(groups)
+	start := page * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	return groups[start:end], total, nil
+}
+
+// syncUserGroups adds user to the linked groups that list them in the directory and
+// then to the teams and channels synchronized with those groups.
+func (l *MatterfossLdap) syncUserGroups(c *request.Context, conn *ldap.Conn, entry *ldap.Entry, user *model.User) *model.AppError {
+	if license := l.app.License(); license == nil || !*license.Features.LDAPGroups {
+		return nil
+	}
+	settings := l.app.Config().LdapSettings
+
+	entries, appErr := l.groupEntries(conn, memberFilter(&settings, entry))
+	if appErr != nil {
+		appErr.Id = "ent.ldap_groups.reachable_groups_error"
+		return appErr
+	}
+
+	linked, appErr := l.linkedGroups()
+	if appErr != nil {
+		return appErr
+	}
+
+	since := model.GetMillis()
+	added := false
+	for _, groupEntry := range entries {
+		group, ok := linked[attributeValue(groupEntry, *settings.GroupIdAttribute)]
+		if !ok {
+			continue
+		}
+		if _, appErr := l.app.UpsertGroupMembers(group.Id, []string{user.Id}); appErr != nil {
+			return appErr
+		}
+		added = true
+	}
+
+	if !added {
+		return nil
+	}
+
+	if err := l.app.CreateDefaultMemberships(c, since, false); err != nil {
+		return model.NewAppError("syncUserGroups", "ent.ldap.syncronize.populate_syncables", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
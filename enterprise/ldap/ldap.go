@@ -0,0 +1,665 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ldap
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/ldap"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	ejobs "github.com/cjdelisle/matterfoss-server/v6/einterfaces/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const (
+	// SynchronizeJobTimeout bounds how long StartSynchronizeJob waits for the job to finish.
+	SynchronizeJobTimeout = 30 * time.Minute
+	// synchronizeJobPollInterval is how often a waiting StartSynchronizeJob checks the job.
+	synchronizeJobPollInterval = time.Second
+
+	// pictureHashProp stores the hash of the last profile picture read from the directory.
+	pictureHashProp = "ldap_picture_hash"
+)
+
+// AppIface is the subset of the app layer used by the LDAP implementation. It allows
+// the implementation to be tested without a running server.
+type AppIface interface {
+	Config() *model.Config
+	UpdateConfig(f func(*model.Config))
+	GetConfigFile(name string) ([]byte, error)
+	License() *model.License
+	Log() *mlog.Logger
+	CreateJob(job *model.Job) (*model.Job, *model.AppError)
+	GetJob(id string) (*model.Job, *model.AppError)
+	GetUser(userID string) (*model.User, *model.AppError)
+	GetUserByAuth(authData *string, authService string) (*model.User, *model.AppError)
+	GetUserByEmail(email string) (*model.User, *model.AppError)
+	GetUsersUsingAuthService(authService string) ([]*model.User, *model.AppError)
+	CreateUser(c *request.Context, user *model.User) (*model.User, *model.AppError)
+	CreateGuest(c *request.Context, user *model.User) (*model.User, *model.AppError)
+	UpdateUser(user *model.User, sendNotifications bool) (*model.User, *model.AppError)
+	UpdateActive(c *request.Context, user *model.User, active bool) (*model.User, *model.AppError)
+	UpdateUserAuth(userID string, userAuth *model.UserAuth) (*model.UserAuth, *model.AppError)
+	UpdateUserRolesWithUser(user *model.User, newRoles string, sendWebSocketEvent bool) (*model.User, *model.AppError)
+	PromoteGuestToUser(c *request.Context, user *model.User, requestorId string) *model.AppError
+	DemoteUserToGuest(user *model.User) *model.AppError
+	SetProfileImageFromFile(userID string, file io.Reader) *model.AppError
+	GetGroupsBySource(groupSource model.GroupSource) ([]*model.Group, *model.AppError)
+	GetGroupSyncables(groupID string, syncableType model.GroupSyncableType) ([]*model.GroupSyncable, *model.AppError)
+	UpdateGroup(group *model.Group) (*model.Group, *model.AppError)
+	GetGroupMemberUsers(groupID string) ([]*model.User, *model.AppError)
+	UpsertGroupMembers(groupID string, userIDs []string) ([]*model.GroupMember, *model.AppError)
+	DeleteGroupMembers(groupID string, userIDs []string) ([]*model.GroupMember, *model.AppError)
+	CreateDefaultMemberships(c *request.Context, since int64, includeRemovedMembers bool) error
+	DeleteGroupConstrainedMemberships(c *request.Context) error
+}
+
+// appAdapter exposes the few app capabilities that only exist on the store.
+type appAdapter struct {
+	*app.App
+}
+
+func (a appAdapter) GetUsersUsingAuthService(authService string) ([]*model.User, *model.AppError) {
+	users, err := a.Srv().Store.User().GetAllUsingAuthService(authService)
+	if err != nil {
+		return nil, model.NewAppError("GetUsersUsingAuthService", "ent.ldap.syncronize.get_all.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return users, nil
+}
+
+func init() {
+	app.RegisterLdapInterface(func(a *app.App) einterfaces.LdapInterface {
+		return NewMatterfossLdap(appAdapter{a})
+	})
+	app.RegisterJobsLdapSyncInterface(func(a *app.App) ejobs.LdapSyncInterface {
+		return &LdapSyncJob{ldap: NewMatterfossLdap(appAdapter{a}), jobServer: a.Srv().Jobs}
+	})
+}
+
+// MatterfossLdap implements einterfaces.LdapInterface against any LDAPv3 directory,
+// including Active Directory.
+type MatterfossLdap struct {
+	app AppIface
+}
+
+func NewMatterfossLdap(app AppIface) *MatterfossLdap {
+	return &MatterfossLdap{app: app}
+}
+
+func (l *MatterfossLdap) checkEnabled(where string) *model.AppError {
+	if license := l.app.License(); license == nil || !*license.Features.LDAP {
+		return model.NewAppError(where, "ent.ldap.do_login.licence_disable.app_error", nil, "", http.StatusNotImplemented)
+	}
+	settings := l.app.Config().LdapSettings
+	if !*settings.Enable && !*settings.EnableSync {
+		return model.NewAppError(where, "ent.ldap.disabled.app_error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+// loginAttribute is the attribute users sign in with.
+func loginAttribute(settings *model.LdapSettings) string {
+	if *settings.LoginIdAttribute != "" {
+		return *settings.LoginIdAttribute
+	}
+	return *settings.UsernameAttribute
+}
+
+// userRoles reports whether the entry with the given DN is matched by the admin and
+// guest filters.
+func (l *MatterfossLdap) userRoles(conn *ldap.Conn, dn string) (isAdmin bool, isGuest bool, appErr *model.AppError) {
+	cfg := l.app.Config()
+	settings := cfg.LdapSettings
+
+	if *settings.EnableAdminFilter && *settings.AdminFilter != "" {
+		matched, err := l.matchesFilter(conn, dn, *settings.AdminFilter)
+		if err != nil {
+			return false, false, model.NewAppError("userRoles", "ent.ldap.validate_admin_filter.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		isAdmin = matched
+	}
+
+	if *cfg.GuestAccountsSettings.Enable && *settings.GuestFilter != "" {
+		matched, err := l.matchesFilter(conn, dn, *settings.GuestFilter)
+		if err != nil {
+			return false, false, model.NewAppError("userRoles", "ent.ldap.validate_guest_filter.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		isGuest = matched
+	}
+
+	return isAdmin, isGuest, nil
+}
+
+// applyRoles brings the system roles of user in line with the admin and guest filters.
+func (l *MatterfossLdap) applyRoles(c *request.Context, user *model.User, isAdmin, isGuest bool) (*model.User, *model.AppError) {
+	cfg := l.app.Config()
+	settings := cfg.LdapSettings
+
+	if *cfg.GuestAccountsSettings.Enable && *settings.GuestFilter != "" && isGuest != user.IsGuest() {
+		var appErr *model.AppError
+		if isGuest {
+			appErr = l.app.DemoteUserToGuest(user)
+		} else {
+			appErr = l.app.PromoteGuestToUser(c, user, "")
+		}
+		if appErr != nil {
+			return nil, appErr
+		}
+		if user, appErr = l.app.GetUser(user.Id); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	if !*settings.EnableAdminFilter || *settings.AdminFilter == "" || user.IsGuest() || isAdmin == user.IsSystemAdmin() {
+		return user, nil
+	}
+
+	var roles []string
+	for _, role := range user.GetRoles() {
+		if role != model.SystemAdminRoleId {
+			roles = append(roles, role)
+		}
+	}
+	if isAdmin {
+		roles = append(roles, model.SystemAdminRoleId)
+	}
+
+	return l.app.UpdateUserRolesWithUser(user, strings.Join(roles, " "), true)
+}
+
+func (l *MatterfossLdap) DoLogin(c *request.Context, id string, password string) (*model.User, *model.AppError) {
+	if appErr := l.checkEnabled("DoLogin"); appErr != nil {
+		return nil, appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return nil, appErr
+	}
+	defer conn.Close()
+
+	entry, appErr := l.findUser(conn, equalityFilter(loginAttribute(&settings), id))
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr = l.checkPassword(entry.DN, password); appErr != nil {
+		return nil, appErr
+	}
+
+	isAdmin, isGuest, appErr := l.userRoles(conn, entry.DN)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	ldapUser := entryToUser(&settings, entry)
+	if *ldapUser.AuthData == "" {
+		return nil, model.NewAppError("DoLogin", "ent.ldap.do_login.user_not_registered.app_error", nil, "missing id attribute", http.StatusBadRequest)
+	}
+
+	user, appErr := l.app.GetUserByAuth(ldapUser.AuthData, model.UserAuthServiceLdap)
+	if appErr != nil {
+		if appErr.Id != app.MissingAuthAccountError {
+			return nil, appErr
+		}
+		return l.createUser(c, conn, entry, ldapUser, isAdmin, isGuest)
+	}
+
+	if updateUserAttributes(&settings, user, ldapUser) {
+		if user, appErr = l.app.UpdateUser(user, false); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return l.applyRoles(c, user, isAdmin, isGuest)
+}
+
+// createUser saves the user signing in for the first time and adds them to their
+// synchronized groups.
+func (l *MatterfossLdap) createUser(c *request.Context, conn *ldap.Conn, entry *ldap.Entry, ldapUser *model.User, isAdmin, isGuest bool) (*model.User, *model.AppError) {
+	if existing, appErr := l.app.GetUserByEmail(ldapUser.Email); appErr == nil {
+		if existing.AuthService != model.UserAuthServiceLdap {
+			return nil, model.NewAppError("DoLogin", "ent.ldap.save_user.email_exists.ldap_app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	var user *model.User
+	var appErr *model.AppError
+	if isGuest {
+		user, appErr = l.app.CreateGuest(c, ldapUser)
+	} else {
+		user, appErr = l.app.CreateUser(c, ldapUser)
+	}
+	if appErr != nil {
+		if appErr.Id == "app.user.save.username_exists.app_error" {
+			return nil, model.NewAppError("DoLogin", "ent.ldap.save_user.username_exists.ldap_app_error", nil, "", http.StatusBadRequest)
+		}
+		return nil, model.NewAppError("DoLogin", "ent.ldap.create_fail", nil, appErr.Error(), http.StatusInternalServerError)
+	}
+
+	if appErr = l.syncUserGroups(c, conn, entry, user); appErr != nil {
+		l.app.Log().Warn("Failed to add the new AD/LDAP user to their groups", mlog.String("user_id", user.Id), mlog.Err(appErr))
+	}
+
+	return l.applyRoles(c, user, isAdmin, isGuest)
+}
+
+func (l *MatterfossLdap) GetUser(id string) (*model.User, *model.AppError) {
+	if appErr := l.checkEnabled("GetUser"); appErr != nil {
+		return nil, appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return nil, appErr
+	}
+	defer conn.Close()
+
+	entry, appErr := l.findUser(conn, equalityFilter(loginAttribute(&settings), id))
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return entryToUser(&settings, entry), nil
+}
+
+func (l *MatterfossLdap) GetUserAttributes(id string, attributes []string) (map[string]string, *model.AppError) {
+	if appErr := l.checkEnabled("GetUserAttributes"); appErr != nil {
+		return nil, appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return nil, appErr
+	}
+	defer conn.Close()
+
+	entries, appErr := l.search(conn, userFilter(&settings, idFilter(&settings, id)), attributes)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if len(entries) != 1 {
+		return nil, model.NewAppError("GetUserAttributes", "ent.ldap.do_login.user_not_registered.app_error", nil, "", http.StatusNotFound)
+	}
+
+	values := make(map[string]string, len(attributes))
+	for _, attribute := range attributes {
+		values[attribute] = attributeValue(entries[0], attribute)
+	}
+
+	return values, nil
+}
+
+func (l *MatterfossLdap) CheckPassword(id string, password string) *model.AppError {
+	if appErr := l.checkEnabled("CheckPassword"); appErr != nil {
+		return appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return appErr
+	}
+	defer conn.Close()
+
+	entry, appErr := l.findUser(conn, equalityFilter(loginAttribute(&settings), id))
+	if appErr != nil {
+		return appErr
+	}
+
+	return l.checkPassword(entry.DN, password)
+}
+
+func (l *MatterfossLdap) CheckPasswordAuthData(authData string, password string) *model.AppError {
+	if appErr := l.checkEnabled("CheckPasswordAuthData"); appErr != nil {
+		return appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return appErr
+	}
+	defer conn.Close()
+
+	entry, appErr := l.findUser(conn, idFilter(&settings, authData))
+	if appErr != nil {
+		return appErr
+	}
+
+	return l.checkPassword(entry.DN, password)
+}
+
+// CheckProviderAttributes returns the name of the first field of patch that is
+// controlled by the directory, or an empty string if the patch may be applied.
+func (l *MatterfossLdap) CheckProviderAttributes(LS *model.LdapSettings, ouser *model.User, patch *model.UserPatch) string {
+	tryingToChange := func(attribute string, current *string, value *string) bool {
+		return attribute != "" && value != nil && *value != *current
+	}
+
+	switch {
+	case tryingToChange(*LS.FirstNameAttribute, &ouser.FirstName, patch.FirstName):
+		return "first name"
+	case tryingToChange(*LS.LastNameAttribute, &ouser.LastName, patch.LastName):
+		return "last name"
+	case tryingToChange(*LS.NicknameAttribute, &ouser.Nickname, patch.Nickname):
+		return "nickname"
+	case tryingToChange(*LS.PositionAttribute, &ouser.Position, patch.Position):
+		return "position"
+	case tryingToChange(*LS.EmailAttribute, &ouser.Email, patch.Email):
+		return "email"
+	case tryingToChange(*LS.UsernameAttribute, &ouser.Username, patch.Username):
+		return "username"
+	}
+
+	return ""
+}
+
+func (l *MatterfossLdap) SwitchToLdap(userID, ldapID, ldapPassword string) *model.AppError {
+	if appErr := l.checkEnabled("SwitchToLdap"); appErr != nil {
+		return appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return appErr
+	}
+	defer conn.Close()
+
+	entry, appErr := l.findUser(conn, equalityFilter(loginAttribute(&settings), ldapID))
+	if appErr != nil {
+		return appErr
+	}
+
+	if appErr = l.checkPassword(entry.DN, ldapPassword); appErr != nil {
+		return appErr
+	}
+
+	ldapUser := entryToUser(&settings, entry)
+	if existing, appErr := l.app.GetUserByAuth(ldapUser.AuthData, model.UserAuthServiceLdap); appErr == nil && existing.Id != userID {
+		return model.NewAppError("SwitchToLdap", "ent.ldap.do_login.matched_to_many_users.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	_, appErr = l.app.UpdateUserAuth(userID, &model.UserAuth{
+		AuthService: model.UserAuthServiceLdap,
+		AuthData:    ldapUser.AuthData,
+	})
+	return appErr
+}
+
+func (l *MatterfossLdap) StartSynchronizeJob(waitForJobToFinish bool, includeRemovedMembers bool) (*model.Job, *model.AppError) {
+	job, appErr := l.app.CreateJob(&model.Job{
+		Type: model.JobTypeLdapSync,
+		Data: map[string]string{
+			"include_removed_members": strconv.FormatBool(includeRemovedMembers),
+		},
+	})
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if !waitForJobToFinish {
+		return job, nil
+	}
+
+	timeout := time.NewTimer(SynchronizeJobTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(synchronizeJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			job, appErr = l.app.GetJob(job.Id)
+			if appErr != nil {
+				return nil, appErr
+			}
+			switch job.Status {
+			case model.JobStatusSuccess, model.JobStatusError, model.JobStatusCanceled:
+				return job, nil
+			}
+		case <-timeout.C:
+			return job, model.NewAppError("StartSynchronizeJob", "ent.jobs.start_synchronize_job.timeout", nil, "", http.StatusInternalServerError)
+		}
+	}
+}
+
+func (l *MatterfossLdap) RunTest() *model.AppError {
+	settings := l.app.Config().LdapSettings
+
+	for id, filter := range map[string]string{
+		"ent.ldap.validate_filter.app_error":       *settings.UserFilter,
+		"ent.ldap.validate_guest_filter.app_error": *settings.GuestFilter,
+		"ent.ldap.validate_admin_filter.app_error": *settings.AdminFilter,
+	} {
+		if filter == "" {
+			continue
+		}
+		if _, err := ldap.CompileFilter(wrapFilter(filter)); err != nil {
+			return model.NewAppError("RunTest", id, map[string]interface{}{"Filter": filter}, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return appErr
+	}
+	defer conn.Close()
+
+	entries, appErr := l.search(conn, allUsersFilter(&settings), []string{*settings.IdAttribute})
+	if appErr != nil {
+		return appErr
+	}
+	if len(entries) == 0 {
+		return model.NewAppError("RunTest", "ent.ldap.no.users.checkcertificate", nil, "", http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// allEntries returns the entries of every user the directory allows to sign in.
+func (l *MatterfossLdap) allEntries(conn *ldap.Conn) ([]*ldap.Entry, *model.AppError) {
+	settings := l.app.Config().LdapSettings
+
+	entries, appErr := l.search(conn, allUsersFilter(&settings), userAttributes(&settings))
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	valid := entries[:0]
+	for _, entry := range entries {
+		if attributeValue(entry, *settings.IdAttribute) != "" {
+			valid = append(valid, entry)
+		}
+	}
+
+	return valid, nil
+}
+
+func (l *MatterfossLdap) GetAllLdapUsers() ([]*model.User, *model.AppError) {
+	if appErr := l.checkEnabled("GetAllLdapUsers"); appErr != nil {
+		return nil, appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return nil, appErr
+	}
+	defer conn.Close()
+
+	entries, appErr := l.allEntries(conn)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	users := make([]*model.User, 0, len(entries))
+	for _, entry := range entries {
+		users = append(users, entryToUser(&settings, entry))
+	}
+
+	return users, nil
+}
+
+// MigrateIDAttribute rewrites the AuthData of every AD/LDAP user from the current
+// id attribute to toAttribute and then switches the configuration over.
+func (l *MatterfossLdap) MigrateIDAttribute(toAttribute string) error {
+	if appErr := l.checkEnabled("MigrateIDAttribute"); appErr != nil {
+		return appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return appErr
+	}
+	defer conn.Close()
+
+	users, appErr := l.app.GetUsersUsingAuthService(model.UserAuthServiceLdap)
+	if appErr != nil {
+		return appErr
+	}
+
+	for _, user := range users {
+		if user.AuthData == nil || *user.AuthData == "" {
+			continue
+		}
+
+		entries, appErr := l.search(conn, idFilter(&settings, *user.AuthData), []string{toAttribute})
+		if appErr != nil {
+			return appErr
+		}
+		if len(entries) != 1 {
+			l.app.Log().Warn("Skipping the id migration of an AD/LDAP user not found in the directory", mlog.String("user_id", user.Id))
+			continue
+		}
+
+		authData := attributeValue(entries[0], toAttribute)
+		if authData == "" {
+			l.app.Log().Warn("Skipping the id migration of an AD/LDAP user without the new id attribute", mlog.String("user_id", user.Id))
+			continue
+		}
+
+		if _, appErr := l.app.UpdateUserAuth(user.Id, &model.UserAuth{
+			AuthService: model.UserAuthServiceLdap,
+			AuthData:    &authData,
+		}); appErr != nil {
+			return appErr
+		}
+	}
+
+	l.app.UpdateConfig(func(cfg *model.Config) {
+		*cfg.LdapSettings.IdAttribute = toAttribute
+	})
+
+	return nil
+}
+
+// FirstLoginSync brings a SAML user who signs in for the first time in line with the
+// directory, when SAML is configured to synchronize with AD/LDAP.
+func (l *MatterfossLdap) FirstLoginSync(c *request.Context, user *model.User, userAuthService, userAuthData, email string) *model.AppError {
+	if appErr := l.checkEnabled("FirstLoginSync"); appErr != nil {
+		return appErr
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return appErr
+	}
+	defer conn.Close()
+
+	authData := userAuthData
+	if userAuthService == model.UserAuthServiceSaml {
+		authData = l.GetADLdapIdFromSAMLId(userAuthData)
+	}
+
+	entry, appErr := l.findUser(conn, idFilter(&settings, authData))
+	if appErr != nil && email != "" {
+		entry, appErr = l.findUser(conn, equalityFilter(*settings.EmailAttribute, email))
+	}
+	if appErr != nil {
+		return appErr
+	}
+
+	if updateUserAttributes(&settings, user, entryToUser(&settings, entry)) {
+		if _, appErr = l.app.UpdateUser(user, false); appErr != nil {
+			return appErr
+		}
+	}
+
+	return l.syncUserGroups(c, conn, entry, user)
+}
+
+func (l *MatterfossLdap) UpdateProfilePictureIfNecessary(user model.User, session model.Session) {
+	settings := l.app.Config().LdapSettings
+	if *settings.PictureAttribute == "" || user.AuthData == nil || l.checkEnabled("UpdateProfilePictureIfNecessary") != nil {
+		return
+	}
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		l.app.Log().Warn("Failed to connect to AD/LDAP to update a profile picture", mlog.Err(appErr))
+		return
+	}
+	defer conn.Close()
+
+	entries, appErr := l.search(conn, userFilter(&settings, idFilter(&settings, *user.AuthData)), []string{*settings.PictureAttribute})
+	if appErr != nil || len(entries) != 1 {
+		return
+	}
+
+	picture := entries[0].GetRawAttributeValue(*settings.PictureAttribute)
+	if len(picture) == 0 {
+		return
+	}
+
+	sum := sha256.Sum256(picture)
+	hash := hex.EncodeToString(sum[:])
+	if user.Props[pictureHashProp] == hash {
+		return
+	}
+
+	user.SetProp(pictureHashProp, hash)
+	if _, appErr := l.app.UpdateUser(&user, false); appErr != nil {
+		l.app.Log().Warn("Failed to save the AD/LDAP profile picture hash", mlog.String("user_id", user.Id), mlog.Err(appErr))
+		return
+	}
+
+	if appErr := l.app.SetProfileImageFromFile(user.Id, bytes.NewReader(picture)); appErr != nil {
+		l.app.Log().Warn("Failed to update the profile picture from AD/LDAP", mlog.String("user_id", user.Id), mlog.Err(appErr))
+	}
+}
+
+func (l *MatterfossLdap) GetVendorNameAndVendorVersion() (string, string) {
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return "", ""
+	}
+	defer conn.Close()
+
+	request := ldap.NewSearchRequest("", ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)", []string{"vendorName", "vendorVersion"}, nil)
+	result, err := conn.Search(request)
+	if err != nil || len(result.Entries) == 0 {
+		return "", ""
+	}
+
+	return result.Entries[0].GetAttributeValue("vendorName"), result.Entries[0].GetAttributeValue("vendorVersion")
+}
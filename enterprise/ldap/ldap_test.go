@@ -0,0 +1,391 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ldap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestDoLogin(t *testing.T) {
+	server, ma, l := newTestDirectory(t)
+	c := request.EmptyContext()
+
+	t.Run("first login creates the user", func(t *testing.T) {
+		user, appErr := l.DoLogin(c, "alice", "alice-password")
+		require.Nil(t, appErr)
+		assert.NotEmpty(t, user.Id)
+		assert.Equal(t, model.UserAuthServiceLdap, user.AuthService)
+		assert.Equal(t, "alice", *user.AuthData)
+		assert.Equal(t, "alice", user.Username)
+		assert.Equal(t, "alice@example.com", user.Email)
+		assert.Equal(t, "Alice", user.FirstName)
+		assert.Equal(t, "Anderson", user.LastName)
+		assert.Equal(t, "Engineer", user.Position)
+		assert.Len(t, ma.users, 1)
+	})
+
+	t.Run("later logins update the attributes", func(t *testing.T) {
+		server.set(personDN("alice"), "sn", "Archer")
+
+		user, appErr := l.DoLogin(c, "alice", "alice-password")
+		require.Nil(t, appErr)
+		assert.Equal(t, "Archer", user.LastName)
+		assert.Len(t, ma.users, 1)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, appErr := l.DoLogin(c, "alice", "bob-password")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.ldap.do_login.invalid_password.app_error", appErr.Id)
+	})
+
+	t.Run("empty password", func(t *testing.T) {
+		_, appErr := l.DoLogin(c, "alice", "")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.ldap.do_login.invalid_password.app_error", appErr.Id)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, appErr := l.DoLogin(c, "mallory", "password")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.ldap.do_login.user_not_registered.app_error", appErr.Id)
+	})
+
+	t.Run("user filtered out", func(t *testing.T) {
+		ma.config.LdapSettings.UserFilter = model.NewString("(title=Manager)")
+		defer func() { ma.config.LdapSettings.UserFilter = model.NewString("(objectClass=person)") }()
+
+		_, appErr := l.DoLogin(c, "bob", "bob-password")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.ldap.do_login.user_not_registered.app_error", appErr.Id)
+	})
+
+	t.Run("admin filter", func(t *testing.T) {
+		ma.config.LdapSettings.EnableAdminFilter = model.NewBool(true)
+		ma.config.LdapSettings.AdminFilter = model.NewString("(uid=bob)")
+		defer func() { ma.config.LdapSettings.EnableAdminFilter = model.NewBool(false) }()
+
+		user, appErr := l.DoLogin(c, "bob", "bob-password")
+		require.Nil(t, appErr)
+		assert.True(t, user.IsSystemAdmin())
+
+		user, appErr = l.DoLogin(c, "alice", "alice-password")
+		require.Nil(t, appErr)
+		assert.False(t, user.IsSystemAdmin())
+	})
+
+	t.Run("guest filter", func(t *testing.T) {
+		addPerson(server, "carol", "Carol", "Clark")
+		ma.config.GuestAccountsSettings.Enable = model.NewBool(true)
+		ma.config.LdapSettings.GuestFilter = model.NewString("(uid=carol)")
+
+		user, appErr := l.DoLogin(c, "carol", "carol-password")
+		require.Nil(t, appErr)
+		assert.True(t, user.IsGuest())
+	})
+
+	t.Run("email already used by another account", func(t *testing.T) {
+		addPerson(server, "dave", "Dave", "Davis")
+		ma.save(&model.User{Id: model.NewId(), Username: "dave-email", Email: "dave@example.com"})
+
+		_, appErr := l.DoLogin(c, "dave", "dave-password")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.ldap.save_user.email_exists.ldap_app_error", appErr.Id)
+	})
+
+	t.Run("license", func(t *testing.T) {
+		license := ma.license
+		ma.license = nil
+		defer func() { ma.license = license }()
+
+		_, appErr := l.DoLogin(c, "alice", "alice-password")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.ldap.do_login.licence_disable.app_error", appErr.Id)
+	})
+}
+
+func TestDoLoginAddsGroupMemberships(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+	developers := ma.addGroup("developers", true)
+	designers := ma.addGroup("designers", true)
+
+	alice, appErr := l.DoLogin(request.EmptyContext(), "alice", "alice-password")
+	require.Nil(t, appErr)
+	bob, appErr := l.DoLogin(request.EmptyContext(), "bob", "bob-password")
+	require.Nil(t, appErr)
+
+	assert.Equal(t, []string{alice.Id}, ma.members(developers.Id))
+	assert.Equal(t, []string{bob.Id}, ma.members(designers.Id))
+	assert.Len(t, ma.defaultMembershipsSince, 2)
+}
+
+func TestCheckPassword(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+
+	require.Nil(t, l.CheckPassword("alice", "alice-password"))
+	require.NotNil(t, l.CheckPassword("alice", "wrong"))
+	require.Nil(t, l.CheckPasswordAuthData("bob", "bob-password"))
+	require.NotNil(t, l.CheckPasswordAuthData("bob", ""))
+
+	t.Run("bad bind credentials", func(t *testing.T) {
+		ma.config.LdapSettings.BindPassword = model.NewString("wrong")
+		appErr := l.CheckPassword("alice", "alice-password")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.ldap.do_login.bind_admin_user.app_error", appErr.Id)
+	})
+}
+
+func TestGetUser(t *testing.T) {
+	_, _, l := newTestDirectory(t)
+
+	user, appErr := l.GetUser("bob")
+	require.Nil(t, appErr)
+	assert.Empty(t, user.Id)
+	assert.Equal(t, "bob@example.com", user.Email)
+
+	attributes, appErr := l.GetUserAttributes("bob", []string{"givenName", "title", "missing"})
+	require.Nil(t, appErr)
+	assert.Equal(t, map[string]string{"givenName": "Bob", "title": "Engineer", "missing": ""}, attributes)
+}
+
+func TestGetAllLdapUsers(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+
+	users, appErr := l.GetAllLdapUsers()
+	require.Nil(t, appErr)
+	require.Len(t, users, 2)
+	assert.Equal(t, "alice", users[0].Username)
+	assert.Equal(t, "bob", users[1].Username)
+
+	ma.config.LdapSettings.UserFilter = model.NewString("uid=bob")
+	users, appErr = l.GetAllLdapUsers()
+	require.Nil(t, appErr)
+	require.Len(t, users, 1)
+}
+
+func TestRunTest(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+
+	require.Nil(t, l.RunTest())
+
+	ma.config.LdapSettings.UserFilter = model.NewString("(uid=nobody)")
+	appErr := l.RunTest()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.ldap.no.users.checkcertificate", appErr.Id)
+
+	ma.config.LdapSettings.UserFilter = model.NewString("(uid=")
+	appErr = l.RunTest()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.ldap.validate_filter.app_error", appErr.Id)
+
+	ma.config.LdapSettings.UserFilter = model.NewString("")
+	ma.config.LdapSettings.LdapPort = model.NewInt(1)
+	appErr = l.RunTest()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.ldap.do_login.unable_to_connect.app_error", appErr.Id)
+}
+
+func TestGroups(t *testing.T) {
+	server, ma, l := newTestDirectory(t)
+	server.add("cn=testers,ou=groups,dc=example,dc=com", map[string][]string{
+		"objectClass": {"groupOfUniqueNames"},
+		"cn":          {"testers"},
+	})
+	developers := ma.addGroup("developers", true)
+	designers := ma.addGroup("designers", false)
+
+	t.Run("get group", func(t *testing.T) {
+		group, appErr := l.GetGroup("developers")
+		require.Nil(t, appErr)
+		assert.Equal(t, "developers", group.DisplayName)
+		assert.Equal(t, "developers", *group.RemoteId)
+		assert.Equal(t, model.GroupSourceLdap, group.Source)
+
+		_, appErr = l.GetGroup("nobody")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.ldap_groups.no_rows", appErr.Id)
+	})
+
+	t.Run("all groups", func(t *testing.T) {
+		groups, total, appErr := l.GetAllGroupsPage(0, 10, model.LdapGroupSearchOpts{})
+		require.Nil(t, appErr)
+		assert.Equal(t, 3, total)
+		require.Len(t, groups, 3)
+		assert.Equal(t, "designers", groups[0].DisplayName)
+		assert.Equal(t, designers.Id, groups[0].Id)
+		assert.False(t, groups[0].HasSyncables)
+		assert.Equal(t, developers.Id, groups[1].Id)
+		assert.True(t, groups[1].HasSyncables)
+		assert.Empty(t, groups[2].Id)
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		groups, total, appErr := l.GetAllGroupsPage(1, 2, model.LdapGroupSearchOpts{})
+		require.Nil(t, appErr)
+		assert.Equal(t, 3, total)
+		require.Len(t, groups, 1)
+		assert.Equal(t, "testers", groups[0].DisplayName)
+
+		groups, _, appErr = l.GetAllGroupsPage(5, 2, model.LdapGroupSearchOpts{})
+		require.Nil(t, appErr)
+		assert.Empty(t, groups)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		groups, total, appErr := l.GetAllGroupsPage(0, 10, model.LdapGroupSearchOpts{Q: "SIGN"})
+		require.Nil(t, appErr)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, "designers", groups[0].DisplayName)
+	})
+
+	t.Run("linked and configured", func(t *testing.T) {
+		groups, _, appErr := l.GetAllGroupsPage(0, 10, model.LdapGroupSearchOpts{IsLinked: model.NewBool(false)})
+		require.Nil(t, appErr)
+		require.Len(t, groups, 1)
+		assert.Equal(t, "testers", groups[0].DisplayName)
+
+		groups, _, appErr = l.GetAllGroupsPage(0, 10, model.LdapGroupSearchOpts{IsConfigured: model.NewBool(true)})
+		require.Nil(t, appErr)
+		require.Len(t, groups, 1)
+		assert.Equal(t, developers.Id, groups[0].Id)
+	})
+}
+
+func TestFirstLoginSync(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+	developers := ma.addGroup("developers", true)
+	ma.config.SamlSettings.EnableSyncWithLdap = model.NewBool(true)
+
+	user := ma.save(&model.User{
+		Id:          model.NewId(),
+		Username:    "alice",
+		Email:       "alice@example.com",
+		AuthService: model.UserAuthServiceSaml,
+		AuthData:    model.NewString("alice"),
+	})
+
+	appErr := l.FirstLoginSync(request.EmptyContext(), user, model.UserAuthServiceSaml, "alice", user.Email)
+	require.Nil(t, appErr)
+
+	assert.Equal(t, []string{user.Id}, ma.members(developers.Id))
+	assert.Equal(t, "Anderson", ma.users[user.Id].LastName)
+	require.Len(t, ma.defaultMembershipsSince, 1)
+}
+
+func TestSwitchToLdap(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+	user := ma.save(&model.User{Id: model.NewId(), Username: "bobby", Email: "bob@example.com", Password: "hash"})
+
+	appErr := l.SwitchToLdap(user.Id, "bob", "wrong")
+	require.NotNil(t, appErr)
+
+	appErr = l.SwitchToLdap(user.Id, "bob", "bob-password")
+	require.Nil(t, appErr)
+	assert.Equal(t, model.UserAuthServiceLdap, ma.users[user.Id].AuthService)
+	assert.Equal(t, "bob", *ma.users[user.Id].AuthData)
+}
+
+func TestMigrateIDAttribute(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+	alice, appErr := l.DoLogin(request.EmptyContext(), "alice", "alice-password")
+	require.Nil(t, appErr)
+
+	require.NoError(t, l.MigrateIDAttribute("mail"))
+	assert.Equal(t, "Alice@Example.com", *ma.users[alice.Id].AuthData)
+	assert.Equal(t, "mail", *ma.config.LdapSettings.IdAttribute)
+
+	_, appErr = l.DoLogin(request.EmptyContext(), "alice", "alice-password")
+	require.Nil(t, appErr)
+	assert.Len(t, ma.users, 1)
+}
+
+func TestUpdateProfilePictureIfNecessary(t *testing.T) {
+	server, ma, l := newTestDirectory(t)
+	ma.config.LdapSettings.PictureAttribute = model.NewString("jpegPhoto")
+	server.set(personDN("alice"), "jpegPhoto", "picture")
+
+	alice, appErr := l.DoLogin(request.EmptyContext(), "alice", "alice-password")
+	require.Nil(t, appErr)
+
+	l.UpdateProfilePictureIfNecessary(*alice, model.Session{})
+	assert.Equal(t, []byte("picture"), ma.pictures[alice.Id])
+
+	delete(ma.pictures, alice.Id)
+	l.UpdateProfilePictureIfNecessary(*ma.users[alice.Id], model.Session{})
+	assert.Empty(t, ma.pictures)
+}
+
+func TestCheckProviderAttributes(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+	settings := &ma.config.LdapSettings
+	user := &model.User{FirstName: "Alice", Nickname: "al"}
+
+	assert.Equal(t, "", l.CheckProviderAttributes(settings, user, &model.UserPatch{FirstName: model.NewString("Alice")}))
+	assert.Equal(t, "first name", l.CheckProviderAttributes(settings, user, &model.UserPatch{FirstName: model.NewString("Eve")}))
+	// The nickname attribute is not mapped, so users may change it.
+	assert.Equal(t, "", l.CheckProviderAttributes(settings, user, &model.UserPatch{Nickname: model.NewString("ally")}))
+}
+
+func TestStartSynchronizeJob(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+
+	job, appErr := l.StartSynchronizeJob(false, true)
+	require.Nil(t, appErr)
+	assert.Equal(t, model.JobTypeLdapSync, job.Type)
+	assert.Equal(t, "true", job.Data["include_removed_members"])
+
+	go func() {
+		// Finish the job once the waiting call has created it.
+		for {
+			ma.mut.Lock()
+			created := len(ma.jobs) == 2
+			if created {
+				for _, job := range ma.jobs {
+					job.Status = model.JobStatusSuccess
+				}
+			}
+			ma.mut.Unlock()
+			if created {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	job, appErr = l.StartSynchronizeJob(true, false)
+	require.Nil(t, appErr)
+	assert.Equal(t, model.JobStatusSuccess, job.Status)
+	assert.Equal(t, "false", job.Data["include_removed_members"])
+}
+
+func TestObjectGUID(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+	ma.config.LdapSettings.IdAttribute = model.NewString("objectGUID")
+
+	raw := []byte{0x78, 0x56, 0x34, 0x12, 0x34, 0x12, 0x78, 0x56, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0}
+	guid := guidToString(raw)
+	assert.Equal(t, "12345678-1234-5678-1234-56789abcdef0", guid)
+
+	samlID := "eFY0EjQSeFYSNFZ4mrze8A=="
+	assert.Equal(t, guid, l.GetADLdapIdFromSAMLId(samlID))
+	assert.Equal(t, samlID, l.GetSAMLIdFromADLdapId(guid))
+	assert.Equal(t, `(objectGUID=\78\56\34\12\34\12\78\56\12\34\56\78\9a\bc\de\f0)`, idFilter(&ma.config.LdapSettings, guid))
+
+	ma.config.LdapSettings.IdAttribute = model.NewString("uid")
+	assert.Equal(t, samlID, l.GetADLdapIdFromSAMLId(samlID))
+}
+
+func TestGetVendorNameAndVendorVersion(t *testing.T) {
+	_, _, l := newTestDirectory(t)
+
+	name, version := l.GetVendorNameAndVendorVersion()
+	assert.Equal(t, "Stub", name)
+	assert.Equal(t, "1.0", version)
+}
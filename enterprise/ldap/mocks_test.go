@@ -0,0 +1,384 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ldap
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// mockApp is an in-memory AppIface holding just enough users, groups and jobs for
+// the tests.
+type mockApp struct {
+	mut          sync.Mutex
+	config       *model.Config
+	license      *model.License
+	logger       *mlog.Logger
+	users        map[string]*model.User
+	groups       map[string]*model.Group
+	groupMembers map[string]map[string]bool
+	syncables    map[string]bool
+	jobs         map[string]*model.Job
+	pictures     map[string][]byte
+
+	defaultMembershipsSince []int64
+	constrainedDeletes      int
+}
+
+func newMockApp(t *testing.T, port int) *mockApp {
+	config := &model.Config{}
+	config.SetDefaults()
+	config.LdapSettings.Enable = model.NewBool(true)
+	config.LdapSettings.EnableSync = model.NewBool(true)
+	config.LdapSettings.LdapServer = model.NewString("127.0.0.1")
+	config.LdapSettings.LdapPort = model.NewInt(port)
+	config.LdapSettings.BaseDN = model.NewString("dc=example,dc=com")
+	config.LdapSettings.BindUsername = model.NewString("cn=admin,dc=example,dc=com")
+	config.LdapSettings.BindPassword = model.NewString("admin")
+	config.LdapSettings.UserFilter = model.NewString("(objectClass=person)")
+	config.LdapSettings.IdAttribute = model.NewString("uid")
+	config.LdapSettings.LoginIdAttribute = model.NewString("uid")
+	config.LdapSettings.UsernameAttribute = model.NewString("uid")
+	config.LdapSettings.EmailAttribute = model.NewString("mail")
+	config.LdapSettings.FirstNameAttribute = model.NewString("givenName")
+	config.LdapSettings.LastNameAttribute = model.NewString("sn")
+	config.LdapSettings.NicknameAttribute = model.NewString("")
+	config.LdapSettings.PositionAttribute = model.NewString("title")
+	config.LdapSettings.GroupIdAttribute = model.NewString("cn")
+	config.LdapSettings.GroupDisplayNameAttribute = model.NewString("cn")
+	config.LdapSettings.QueryTimeout = model.NewInt(5)
+
+	license := model.NewTestLicense("ldap", "ldap_groups")
+
+	ma := &mockApp{
+		config:       config,
+		license:      license,
+		logger:       mlog.CreateConsoleTestLogger(true, mlog.LvlError),
+		users:        make(map[string]*model.User),
+		groups:       make(map[string]*model.Group),
+		groupMembers: make(map[string]map[string]bool),
+		syncables:    make(map[string]bool),
+		jobs:         make(map[string]*model.Job),
+		pictures:     make(map[string][]byte),
+	}
+	t.Cleanup(func() { ma.logger.Shutdown() })
+	return ma
+}
+
+func (ma *mockApp) Config() *model.Config { return ma.config }
+func (ma *mockApp) UpdateConfig(f func(*model.Config)) {
+	f(ma.config)
+}
+func (ma *mockApp) GetConfigFile(name string) ([]byte, error) {
+	return nil, errors.New("not found")
+}
+func (ma *mockApp) License() *model.License { return ma.license }
+func (ma *mockApp) Log() *mlog.Logger       { return ma.logger }
+
+func (ma *mockApp) CreateJob(job *model.Job) (*model.Job, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	job.Id = model.NewId()
+	job.Status = model.JobStatusPending
+	ma.jobs[job.Id] = job
+	return job, nil
+}
+
+func (ma *mockApp) GetJob(id string) (*model.Job, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	job, ok := ma.jobs[id]
+	if !ok {
+		return nil, model.NewAppError("GetJob", "app.job.get.app_error", nil, "", http.StatusNotFound)
+	}
+	copy := *job
+	return &copy, nil
+}
+
+func (ma *mockApp) save(user *model.User) *model.User {
+	copy := *user
+	ma.users[user.Id] = &copy
+	result := copy
+	return &result
+}
+
+func (ma *mockApp) GetUser(userID string) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	user, ok := ma.users[userID]
+	if !ok {
+		return nil, model.NewAppError("GetUser", app.MissingAccountError, nil, "", http.StatusNotFound)
+	}
+	copy := *user
+	return &copy, nil
+}
+
+func (ma *mockApp) GetUserByAuth(authData *string, authService string) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	for _, user := range ma.users {
+		if user.AuthService == authService && user.AuthData != nil && *user.AuthData == *authData {
+			copy := *user
+			return &copy, nil
+		}
+	}
+	return nil, model.NewAppError("GetUserByAuth", app.MissingAuthAccountError, nil, "", http.StatusInternalServerError)
+}
+
+func (ma *mockApp) GetUserByEmail(email string) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	for _, user := range ma.users {
+		if user.Email == email {
+			copy := *user
+			return &copy, nil
+		}
+	}
+	return nil, model.NewAppError("GetUserByEmail", app.MissingAccountError, nil, "", http.StatusNotFound)
+}
+
+func (ma *mockApp) GetUsersUsingAuthService(authService string) ([]*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	var users []*model.User
+	for _, user := range ma.users {
+		if user.AuthService == authService {
+			copy := *user
+			users = append(users, &copy)
+		}
+	}
+	return users, nil
+}
+
+func (ma *mockApp) createUser(user *model.User, roles string) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	for _, existing := range ma.users {
+		if existing.Username == user.Username {
+			return nil, model.NewAppError("createUserOrGuest", "app.user.save.username_exists.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+	user.Id = model.NewId()
+	user.Roles = roles
+	user.CreateAt = model.GetMillis()
+	return ma.save(user), nil
+}
+
+func (ma *mockApp) CreateUser(c *request.Context, user *model.User) (*model.User, *model.AppError) {
+	return ma.createUser(user, model.SystemUserRoleId)
+}
+
+func (ma *mockApp) CreateGuest(c *request.Context, user *model.User) (*model.User, *model.AppError) {
+	return ma.createUser(user, model.SystemGuestRoleId)
+}
+
+func (ma *mockApp) UpdateUser(user *model.User, sendNotifications bool) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	return ma.save(user), nil
+}
+
+func (ma *mockApp) UpdateActive(c *request.Context, user *model.User, active bool) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	if active {
+		user.DeleteAt = 0
+	} else {
+		user.DeleteAt = model.GetMillis()
+	}
+	return ma.save(user), nil
+}
+
+func (ma *mockApp) UpdateUserAuth(userID string, userAuth *model.UserAuth) (*model.UserAuth, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	user := ma.users[userID]
+	user.AuthService = userAuth.AuthService
+	user.AuthData = userAuth.AuthData
+	user.Password = ""
+	return userAuth, nil
+}
+
+func (ma *mockApp) UpdateUserRolesWithUser(user *model.User, newRoles string, sendWebSocketEvent bool) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	user.Roles = newRoles
+	return ma.save(user), nil
+}
+
+func (ma *mockApp) PromoteGuestToUser(c *request.Context, user *model.User, requestorId string) *model.AppError {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	ma.users[user.Id].Roles = model.SystemUserRoleId
+	return nil
+}
+
+func (ma *mockApp) DemoteUserToGuest(user *model.User) *model.AppError {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	ma.users[user.Id].Roles = model.SystemGuestRoleId
+	return nil
+}
+
+func (ma *mockApp) SetProfileImageFromFile(userID string, file io.Reader) *model.AppError {
+	data, _ := ioutil.ReadAll(file)
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	ma.pictures[userID] = data
+	return nil
+}
+
+func (ma *mockApp) addGroup(remoteID string, hasSyncables bool) *model.Group {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	group := &model.Group{
+		Id:          model.NewId(),
+		DisplayName: remoteID,
+		RemoteId:    model.NewString(remoteID),
+		Source:      model.GroupSourceLdap,
+	}
+	ma.groups[group.Id] = group
+	ma.groupMembers[group.Id] = make(map[string]bool)
+	ma.syncables[group.Id] = hasSyncables
+	return group
+}
+
+func (ma *mockApp) members(groupID string) []string {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	var ids []string
+	for id := range ma.groupMembers[groupID] {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (ma *mockApp) GetGroupsBySource(groupSource model.GroupSource) ([]*model.Group, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	var groups []*model.Group
+	for _, group := range ma.groups {
+		if group.Source == groupSource {
+			copy := *group
+			groups = append(groups, &copy)
+		}
+	}
+	return groups, nil
+}
+
+func (ma *mockApp) GetGroupSyncables(groupID string, syncableType model.GroupSyncableType) ([]*model.GroupSyncable, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	if ma.syncables[groupID] && syncableType == model.GroupSyncableTypeTeam {
+		return []*model.GroupSyncable{{GroupId: groupID, SyncableId: model.NewId(), Type: syncableType}}, nil
+	}
+	return nil, nil
+}
+
+func (ma *mockApp) UpdateGroup(group *model.Group) (*model.Group, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	copy := *group
+	ma.groups[group.Id] = &copy
+	return group, nil
+}
+
+func (ma *mockApp) GetGroupMemberUsers(groupID string) ([]*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	var users []*model.User
+	for id := range ma.groupMembers[groupID] {
+		users = append(users, ma.users[id])
+	}
+	return users, nil
+}
+
+func (ma *mockApp) UpsertGroupMembers(groupID string, userIDs []string) ([]*model.GroupMember, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	var members []*model.GroupMember
+	for _, id := range userIDs {
+		ma.groupMembers[groupID][id] = true
+		members = append(members, &model.GroupMember{GroupId: groupID, UserId: id})
+	}
+	return members, nil
+}
+
+func (ma *mockApp) DeleteGroupMembers(groupID string, userIDs []string) ([]*model.GroupMember, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	var members []*model.GroupMember
+	for _, id := range userIDs {
+		delete(ma.groupMembers[groupID], id)
+		members = append(members, &model.GroupMember{GroupId: groupID, UserId: id})
+	}
+	return members, nil
+}
+
+func (ma *mockApp) CreateDefaultMemberships(c *request.Context, since int64, includeRemovedMembers bool) error {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	ma.defaultMembershipsSince = append(ma.defaultMembershipsSince, since)
+	return nil
+}
+
+func (ma *mockApp) DeleteGroupConstrainedMemberships(c *request.Context) error {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	ma.constrainedDeletes++
+	return nil
+}
+
+// newTestDirectory starts a stub server holding an admin, two people and a group.
+func newTestDirectory(t *testing.T) (*stubServer, *mockApp, *MatterfossLdap) {
+	server := newStubServer(t)
+	server.add("cn=admin,dc=example,dc=com", map[string][]string{
+		"objectClass":  {"organizationalRole"},
+		"userPassword": {"admin"},
+	})
+	server.add("ou=people,dc=example,dc=com", map[string][]string{
+		"objectClass": {"organizationalUnit"},
+	})
+	addPerson(server, "alice", "Alice", "Anderson")
+	addPerson(server, "bob", "Bob", "Brown")
+	server.add("cn=developers,ou=groups,dc=example,dc=com", map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"developers"},
+		"member":      {"uid=alice,ou=people,dc=example,dc=com"},
+	})
+	server.add("cn=designers,ou=groups,dc=example,dc=com", map[string][]string{
+		"objectClass": {"posixGroup", "groupOfNames"},
+		"cn":          {"designers"},
+		"memberUid":   {"bob"},
+	})
+
+	ma := newMockApp(t, server.port)
+	return server, ma, NewMatterfossLdap(ma)
+}
+
+func personDN(uid string) string {
+	return "uid=" + uid + ",ou=people,dc=example,dc=com"
+}
+
+func addPerson(server *stubServer, uid, firstName, lastName string) {
+	server.add(personDN(uid), map[string][]string{
+		"objectClass":  {"person", "inetOrgPerson"},
+		"uid":          {uid},
+		"mail":         {strings.Title(uid) + "@Example.com"},
+		"givenName":    {firstName},
+		"sn":           {lastName},
+		"title":        {"Engineer"},
+		"userPassword": {uid + "-password"},
+	})
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ldap
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/mattermost/ldap"
+	"github.com/stretchr/testify/require"
+)
+
+// stubServer is a minimal in-process LDAPv3 server. It understands simple binds,
+// unbinds and searches with the filters used by the implementation, which is enough
+// to exercise it end to end without a real directory.
+type stubServer struct {
+	listener net.Listener
+	port     int
+
+	mut     sync.Mutex
+	entries map[string]map[string][]string
+	order   []string
+}
+
+func newStubServer(t *testing.T) *stubServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &stubServer{
+		listener: listener,
+		port:     listener.Addr().(*net.TCPAddr).Port,
+		entries:  make(map[string]map[string][]string),
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go s.accept()
+	return s
+}
+
+// add creates or replaces the entry dn. The userPassword attribute is the password
+// the entry binds with.
+func (s *stubServer) add(dn string, attributes map[string][]string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	key := strings.ToLower(dn)
+	if _, ok := s.entries[key]; !ok {
+		s.order = append(s.order, dn)
+	}
+	s.entries[key] = attributes
+}
+
+func (s *stubServer) remove(dn string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	delete(s.entries, strings.ToLower(dn))
+	for i, existing := range s.order {
+		if strings.EqualFold(existing, dn) {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *stubServer) set(dn, attribute string, values ...string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.entries[strings.ToLower(dn)][attribute] = values
+}
+
+func (s *stubServer) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.serve(conn)
+	}
+}
+
+func (s *stubServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		messageID := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			code := s.bind(op.Children[1].Value.(string), op.Children[2].Data.String())
+			s.write(conn, messageID, result(ldap.ApplicationBindResponse, code))
+		case ldap.ApplicationSearchRequest:
+			for _, entry := range s.search(op) {
+				s.write(conn, messageID, entry)
+			}
+			s.write(conn, messageID, result(ldap.ApplicationSearchResultDone, s.searchCode(op)))
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			s.write(conn, messageID, result(ber.Tag(op.Tag+1), ldap.LDAPResultUnwillingToPerform))
+		}
+	}
+}
+
+func (s *stubServer) write(conn net.Conn, messageID int64, op *ber.Packet) {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	envelope.AppendChild(op)
+	conn.Write(envelope.Bytes())
+}
+
+func result(tag ber.Tag, code uint16) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, uint64(code), "Result Code"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return packet
+}
+
+func (s *stubServer) bind(dn, password string) uint16 {
+	if dn == "" && password == "" {
+		return ldap.LDAPResultSuccess
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	entry, ok := s.entries[strings.ToLower(dn)]
+	if !ok || password == "" || len(entry["userPassword"]) == 0 || entry["userPassword"][0] != password {
+		return ldap.LDAPResultInvalidCredentials
+	}
+	return ldap.LDAPResultSuccess
+}
+
+func (s *stubServer) searchCode(op *ber.Packet) uint16 {
+	base := op.Children[0].Value.(string)
+	scope := op.Children[1].Value.(int64)
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if scope == ldap.ScopeBaseObject && base != "" {
+		if _, ok := s.entries[strings.ToLower(base)]; !ok {
+			return ldap.LDAPResultNoSuchObject
+		}
+	}
+	return ldap.LDAPResultSuccess
+}
+
+func (s *stubServer) search(op *ber.Packet) []*ber.Packet {
+	base := strings.ToLower(op.Children[0].Value.(string))
+	scope := op.Children[1].Value.(int64)
+	filter := op.Children[6]
+
+	var requested []string
+	for _, attribute := range op.Children[7].Children {
+		requested = append(requested, attribute.Value.(string))
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if base == "" && scope == ldap.ScopeBaseObject {
+		rootDSE := map[string][]string{"vendorName": {"Stub"}, "vendorVersion": {"1.0"}}
+		return []*ber.Packet{searchEntry("", rootDSE, requested)}
+	}
+
+	var found []*ber.Packet
+	for _, dn := range s.order {
+		key := strings.ToLower(dn)
+		switch {
+		case scope == ldap.ScopeBaseObject && key != base:
+			continue
+		case key != base && !strings.HasSuffix(key, ","+base):
+			continue
+		}
+
+		attributes := s.entries[key]
+		if matchFilter(attributes, filter) {
+			found = append(found, searchEntry(dn, attributes, requested))
+		}
+	}
+
+	return found
+}
+
+func searchEntry(dn string, attributes map[string][]string, requested []string) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "DN"))
+
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, values := range attributes {
+		if name == "userPassword" || !isRequested(name, requested) {
+			continue
+		}
+
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		attribute.AppendChild(set)
+		list.AppendChild(attribute)
+	}
+	packet.AppendChild(list)
+
+	return packet
+}
+
+func isRequested(name string, requested []string) bool {
+	if len(requested) == 0 {
+		return true
+	}
+	for _, attribute := range requested {
+		if strings.EqualFold(attribute, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func attributeValues(attributes map[string][]string, name string) []string {
+	for attribute, values := range attributes {
+		if strings.EqualFold(attribute, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func matchFilter(attributes map[string][]string, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matchFilter(attributes, child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if matchFilter(attributes, child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !matchFilter(attributes, filter.Children[0])
+	case ldap.FilterEqualityMatch:
+		name := filter.Children[0].Value.(string)
+		value := filter.Children[1].Data.String()
+		for _, candidate := range attributeValues(attributes, name) {
+			if strings.EqualFold(candidate, value) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterSubstrings:
+		name := filter.Children[0].Value.(string)
+		for _, candidate := range attributeValues(attributes, name) {
+			if matchSubstrings(strings.ToLower(candidate), filter.Children[1].Children) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterPresent:
+		name := filter.Data.String()
+		return strings.EqualFold(name, "objectClass") || len(attributeValues(attributes, name)) > 0
+	}
+	return false
+}
+
+func matchSubstrings(value string, parts []*ber.Packet) bool {
+	for _, part := range parts {
+		sub := strings.ToLower(part.Data.String())
+		switch part.Tag {
+		case ldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(value, sub) {
+				return false
+			}
+			value = value[len(sub):]
+		case ldap.FilterSubstringsAny:
+			index := strings.Index(value, sub)
+			if index < 0 {
+				return false
+			}
+			value = value[index+len(sub):]
+		case ldap.FilterSubstringsFinal:
+			if !strings.HasSuffix(value, sub) {
+				return false
+			}
+		}
+	}
+	return true
+}
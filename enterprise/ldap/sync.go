@@ -0,0 +1,345 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ldap
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/ldap"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const syncJobName = "LdapSync"
+
+// LdapSyncJob implements ejobs.LdapSyncInterface.
+type LdapSyncJob struct {
+	ldap      *MatterfossLdap
+	jobServer *jobs.JobServer
+}
+
+func (j *LdapSyncJob) isEnabled(cfg *model.Config) bool {
+	license := j.ldap.app.License()
+	return license != nil && *license.Features.LDAP && *cfg.LdapSettings.EnableSync
+}
+
+func (j *LdapSyncJob) MakeWorker() model.Worker {
+	execute := func(job *model.Job) error {
+		includeRemovedMembers, _ := strconv.ParseBool(job.Data["include_removed_members"])
+
+		since := int64(0)
+		if last, appErr := j.jobServer.GetLastSuccessfulJobByType(model.JobTypeLdapSync); appErr == nil && last != nil {
+			since = last.StartAt
+		}
+
+		result, appErr := j.ldap.Synchronize(request.EmptyContext(), since, includeRemovedMembers)
+		if appErr != nil {
+			mlog.Error("Worker: Failed to synchronize with AD/LDAP", mlog.String("worker", model.JobTypeLdapSync), mlog.String("job_id", job.Id), mlog.Err(appErr))
+			return appErr
+		}
+
+		if job.Data == nil {
+			job.Data = make(map[string]string)
+		}
+		for key, value := range result.data() {
+			job.Data[key] = value
+		}
+		if appErr := j.jobServer.UpdateInProgressJobData(job); appErr != nil {
+			mlog.Warn("Worker: Failed to save the AD/LDAP synchronization results", mlog.String("worker", model.JobTypeLdapSync), mlog.String("job_id", job.Id), mlog.Err(appErr))
+		}
+
+		return nil
+	}
+	return jobs.NewSimpleWorker(syncJobName, j.jobServer, execute, j.isEnabled)
+}
+
+// SyncScheduler runs the synchronization every LdapSettings.SyncIntervalMinutes.
+type SyncScheduler struct {
+	*jobs.PeriodicScheduler
+}
+
+func (scheduler *SyncScheduler) NextScheduleTime(cfg *model.Config, now time.Time, pendingJobs bool, lastSuccessfulJob *model.Job) *time.Time {
+	nextTime := time.Now().Add(time.Duration(*cfg.LdapSettings.SyncIntervalMinutes) * time.Minute)
+	return &nextTime
+}
+
+func (j *LdapSyncJob) MakeScheduler() model.Scheduler {
+	return &SyncScheduler{PeriodicScheduler: jobs.NewPeriodicScheduler(j.jobServer, model.JobTypeLdapSync, 0, j.isEnabled)}
+}
+
+// SyncResult counts what a synchronization changed.
+type SyncResult struct {
+	LdapUsers   int
+	Updated     int
+	Deactivated int
+	Reactivated int
+	Groups      int
+}
+
+func (r *SyncResult) data() map[string]string {
+	return map[string]string{
+		"ldap_users_count":  strconv.Itoa(r.LdapUsers),
+		"update_count":      strconv.Itoa(r.Updated),
+		"deactivated_count": strconv.Itoa(r.Deactivated),
+		"reactivated_count": strconv.Itoa(r.Reactivated),
+		"group_count":       strconv.Itoa(r.Groups),
+	}
+}
+
+// Synchronize brings the AD/LDAP users and linked groups of Matterfoss in line with
+// the directory. Users who are no longer in the directory, or no longer matched by
+// the user filter, are deactivated.
+func (l *MatterfossLdap) Synchronize(c *request.Context, since int64, includeRemovedMembers bool) (*SyncResult, *model.AppError) {
+	if license := l.app.License(); license == nil || !*license.Features.LDAP {
+		return nil, model.NewAppError("Synchronize", "ent.ldap.do_login.licence_disable.app_error", nil, "", http.StatusNotImplemented)
+	}
+	settings := l.app.Config().LdapSettings
+
+	conn, appErr := l.connect()
+	if appErr != nil {
+		return nil, appErr
+	}
+	defer conn.Close()
+
+	entries, appErr := l.allEntries(conn)
+	if appErr != nil {
+		return nil, appErr
+	}
+	// An empty result is far more likely a misconfiguration than an empty directory,
+	// and would deactivate everybody.
+	if len(entries) == 0 {
+		return nil, model.NewAppError("Synchronize", "ent.ldap.no.users.checkcertificate", nil, "", http.StatusInternalServerError)
+	}
+
+	byAuthData := make(map[string]*ldap.Entry, len(entries))
+	for _, entry := range entries {
+		byAuthData[attributeValue(entry, *settings.IdAttribute)] = entry
+	}
+
+	result := &SyncResult{LdapUsers: len(entries)}
+	userIDsByDN, appErr := l.syncUsers(c, conn, byAuthData, result)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if license := l.app.License(); *license.Features.LDAPGroups {
+		if appErr := l.syncGroups(c, conn, userIDsByDN, since, includeRemovedMembers, result); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return result, nil
+}
+
+// syncUsers updates every user signing in through the directory and returns the ids
+// of the active ones keyed by both their normalized DN and their uid, the two ways
+// groups list their members.
+func (l *MatterfossLdap) syncUsers(c *request.Context, conn *ldap.Conn, byAuthData map[string]*ldap.Entry, result *SyncResult) (map[string]string, *model.AppError) {
+	cfg := l.app.Config()
+	settings := cfg.LdapSettings
+
+	users, appErr := l.app.GetUsersUsingAuthService(model.UserAuthServiceLdap)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if *cfg.SamlSettings.Enable && *cfg.SamlSettings.EnableSyncWithLdap {
+		samlUsers, appErr := l.app.GetUsersUsingAuthService(model.UserAuthServiceSaml)
+		if appErr != nil {
+			return nil, appErr
+		}
+		users = append(users, samlUsers...)
+	}
+
+	userIDsByDN := make(map[string]string, len(users))
+	for _, user := range users {
+		if user.AuthData == nil {
+			continue
+		}
+
+		authData := *user.AuthData
+		if user.AuthService == model.UserAuthServiceSaml {
+			authData = l.GetADLdapIdFromSAMLId(authData)
+		}
+
+		entry, ok := byAuthData[authData]
+		if !ok {
+			if user.DeleteAt == 0 {
+				if _, appErr := l.app.UpdateActive(c, user, false); appErr != nil {
+					l.app.Log().Error("Failed to deactivate a user removed from AD/LDAP", mlog.String("user_id", user.Id), mlog.Err(appErr))
+					continue
+				}
+				result.Deactivated++
+			}
+			continue
+		}
+
+		ldapUser := entryToUser(&settings, entry)
+		if user.AuthService == model.UserAuthServiceSaml {
+			// SAML stays in charge of the usernames and emails of its users.
+			ldapUser.Username = user.Username
+			ldapUser.Email = user.Email
+		}
+
+		if user.DeleteAt != 0 {
+			reactivated, appErr := l.app.UpdateActive(c, user, true)
+			if appErr != nil {
+				l.app.Log().Error("Failed to reactivate a user back in AD/LDAP", mlog.String("user_id", user.Id), mlog.Err(appErr))
+				continue
+			}
+			user = reactivated
+			result.Reactivated++
+		}
+
+		if updateUserAttributes(&settings, user, ldapUser) {
+			updated, appErr := l.app.UpdateUser(user, false)
+			if appErr != nil {
+				l.app.Log().Error("Failed to update the attributes of an AD/LDAP user", mlog.String("user_id", user.Id), mlog.Err(appErr))
+				continue
+			}
+			user = updated
+			result.Updated++
+		}
+
+		isAdmin, isGuest, appErr := l.userRoles(conn, entry.DN)
+		if appErr != nil {
+			return nil, appErr
+		}
+		if _, appErr := l.applyRoles(c, user, isAdmin, isGuest); appErr != nil {
+			l.app.Log().Error("Failed to update the roles of an AD/LDAP user", mlog.String("user_id", user.Id), mlog.Err(appErr))
+		}
+
+		userIDsByDN[normalizeDN(entry.DN)] = user.Id
+		if uid := attributeValue(entry, *settings.UsernameAttribute); uid != "" {
+			userIDsByDN[uid] = user.Id
+		}
+	}
+
+	return userIDsByDN, nil
+}
+
+// syncGroups updates the name and members of every linked group and then the teams
+// and channels synchronized with them.
+func (l *MatterfossLdap) syncGroups(c *request.Context, conn *ldap.Conn, userIDsByDN map[string]string, since int64, includeRemovedMembers bool, result *SyncResult) *model.AppError {
+	settings := l.app.Config().LdapSettings
+
+	linked, appErr := l.linkedGroups()
+	if appErr != nil {
+		return model.NewAppError("syncGroups", "ent.ldap.syncronize.get_all_groups.app_error", nil, appErr.Error(), http.StatusInternalServerError)
+	}
+	if len(linked) == 0 {
+		return nil
+	}
+
+	entries, appErr := l.groupEntries(conn, "")
+	if appErr != nil {
+		return model.NewAppError("syncGroups", "ent.ldap.syncronize.get_all_groups.app_error", nil, appErr.Error(), http.StatusInternalServerError)
+	}
+
+	for _, entry := range entries {
+		ldapGroup := entryToGroup(&settings, entry)
+		group, ok := linked[*ldapGroup.RemoteId]
+		if !ok {
+			continue
+		}
+		result.Groups++
+
+		if group.DisplayName != ldapGroup.DisplayName {
+			group.DisplayName = ldapGroup.DisplayName
+			if len(group.DisplayName) > model.GroupDisplayNameMaxLength {
+				group.DisplayName = group.DisplayName[:model.GroupDisplayNameMaxLength]
+			}
+			if _, appErr := l.app.UpdateGroup(group); appErr != nil {
+				l.app.Log().Error("Failed to update the name of an AD/LDAP group", mlog.String("group_id", group.Id), mlog.Err(appErr))
+			}
+		}
+
+		if appErr := l.syncGroupMembers(group, entry, userIDsByDN); appErr != nil {
+			return appErr
+		}
+	}
+
+	if err := l.app.CreateDefaultMemberships(c, since, includeRemovedMembers); err != nil {
+		return model.NewAppError("syncGroups", "ent.ldap.syncronize.populate_syncables", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if err := l.app.DeleteGroupConstrainedMemberships(c); err != nil {
+		return model.NewAppError("syncGroups", "ent.ldap.syncronize.delete_group_constained_memberships", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return nil
+}
+
+func (l *MatterfossLdap) syncGroupMembers(group *model.Group, entry *ldap.Entry, userIDsByDN map[string]string) *model.AppError {
+	wanted := make(map[string]bool)
+	for _, attribute := range groupMemberAttributes {
+		for _, dn := range entry.GetAttributeValues(attribute) {
+			if userID, ok := userIDsByDN[normalizeDN(dn)]; ok {
+				wanted[userID] = true
+			}
+		}
+	}
+	for _, uid := range entry.GetAttributeValues(groupMemberUidAttribute) {
+		if userID, ok := userIDsByDN[uid]; ok {
+			wanted[userID] = true
+		}
+	}
+
+	members, appErr := l.app.GetGroupMemberUsers(group.Id)
+	if appErr != nil {
+		return model.NewAppError("syncGroupMembers", "ent.ldap_groups.members_of_group_error", nil, appErr.Error(), http.StatusInternalServerError)
+	}
+
+	var removed []string
+	for _, member := range members {
+		if wanted[member.Id] {
+			delete(wanted, member.Id)
+		} else {
+			removed = append(removed, member.Id)
+		}
+	}
+
+	added := make([]string, 0, len(wanted))
+	for userID := range wanted {
+		added = append(added, userID)
+	}
+
+	if len(added) > 0 {
+		if _, appErr := l.app.UpsertGroupMembers(group.Id, added); appErr != nil {
+			return appErr
+		}
+	}
+	if len(removed) > 0 {
+		if _, appErr := l.app.DeleteGroupMembers(group.Id, removed); appErr != nil {
+			return appErr
+		}
+	}
+
+	return nil
+}
+
+// normalizeDN makes DNs that only differ by case or spacing compare equal.
+func normalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(dn)
+	}
+
+	rdns := make([]string, 0, len(parsed.RDNs))
+	for _, rdn := range parsed.RDNs {
+		attributes := make([]string, 0, len(rdn.Attributes))
+		for _, attribute := range rdn.Attributes {
+			attributes = append(attributes, strings.ToLower(attribute.Type)+"="+strings.ToLower(attribute.Value))
+		}
+		rdns = append(rdns, strings.Join(attributes, "+"))
+	}
+
+	return strings.Join(rdns, ",")
+}
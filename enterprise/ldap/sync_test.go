@@ -0,0 +1,110 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestSynchronize(t *testing.T) {
+	server, ma, l := newTestDirectory(t)
+	c := request.EmptyContext()
+
+	alice, appErr := l.DoLogin(c, "alice", "alice-password")
+	require.Nil(t, appErr)
+	bob, appErr := l.DoLogin(c, "bob", "bob-password")
+	require.Nil(t, appErr)
+	developers := ma.addGroup("developers", true)
+	designers := ma.addGroup("designers", true)
+
+	t.Run("updates attributes and groups", func(t *testing.T) {
+		server.set(personDN("alice"), "title", "Manager")
+
+		result, appErr := l.Synchronize(c, 10, true)
+		require.Nil(t, appErr)
+		assert.Equal(t, 2, result.LdapUsers)
+		assert.Equal(t, 1, result.Updated)
+		assert.Equal(t, 0, result.Deactivated)
+		assert.Equal(t, 2, result.Groups)
+
+		assert.Equal(t, "Manager", ma.users[alice.Id].Position)
+		assert.Equal(t, []string{alice.Id}, ma.members(developers.Id))
+		assert.Equal(t, []string{bob.Id}, ma.members(designers.Id))
+		assert.Equal(t, int64(10), ma.defaultMembershipsSince[len(ma.defaultMembershipsSince)-1])
+		assert.Equal(t, 1, ma.constrainedDeletes)
+	})
+
+	t.Run("deactivates removed users", func(t *testing.T) {
+		server.remove(personDN("bob"))
+
+		result, appErr := l.Synchronize(c, 0, false)
+		require.Nil(t, appErr)
+		assert.Equal(t, 1, result.Deactivated)
+		assert.NotZero(t, ma.users[bob.Id].DeleteAt)
+		assert.Zero(t, ma.users[alice.Id].DeleteAt)
+		assert.Empty(t, ma.members(designers.Id))
+	})
+
+	t.Run("deactivates users no longer matched by the user filter", func(t *testing.T) {
+		ma.config.LdapSettings.UserFilter = model.NewString("(title=Engineer)")
+		defer func() { ma.config.LdapSettings.UserFilter = model.NewString("(objectClass=person)") }()
+		addPerson(server, "carol", "Carol", "Clark")
+
+		result, appErr := l.Synchronize(c, 0, false)
+		require.Nil(t, appErr)
+		assert.Equal(t, 1, result.Deactivated)
+		assert.NotZero(t, ma.users[alice.Id].DeleteAt)
+		assert.Empty(t, ma.members(developers.Id))
+	})
+
+	t.Run("reactivates returning users", func(t *testing.T) {
+		addPerson(server, "bob", "Bob", "Brown")
+
+		result, appErr := l.Synchronize(c, 0, false)
+		require.Nil(t, appErr)
+		assert.Equal(t, 2, result.Reactivated)
+		assert.Zero(t, ma.users[alice.Id].DeleteAt)
+		assert.Zero(t, ma.users[bob.Id].DeleteAt)
+		assert.Equal(t, []string{bob.Id}, ma.members(designers.Id))
+	})
+
+	t.Run("refuses an empty directory", func(t *testing.T) {
+		ma.config.LdapSettings.UserFilter = model.NewString("(uid=nobody)")
+		defer func() { ma.config.LdapSettings.UserFilter = model.NewString("(objectClass=person)") }()
+
+		_, appErr := l.Synchronize(c, 0, false)
+		require.NotNil(t, appErr)
+		assert.Zero(t, ma.users[alice.Id].DeleteAt)
+	})
+
+	t.Run("skips groups without the license", func(t *testing.T) {
+		ma.license = model.NewTestLicense("ldap")
+		ma.license.Features.LDAPGroups = model.NewBool(false)
+		server.set("cn=developers,ou=groups,dc=example,dc=com", "member", personDN("bob"))
+
+		result, appErr := l.Synchronize(c, 0, false)
+		require.Nil(t, appErr)
+		assert.Equal(t, 0, result.Groups)
+		assert.Equal(t, []string{alice.Id}, ma.members(developers.Id))
+	})
+}
+
+func TestSyncScheduler(t *testing.T) {
+	_, ma, l := newTestDirectory(t)
+	job := &LdapSyncJob{ldap: l}
+
+	assert.True(t, job.isEnabled(ma.config))
+	ma.config.LdapSettings.EnableSync = model.NewBool(false)
+	assert.False(t, job.isEnabled(ma.config))
+}
+
+func TestNormalizeDN(t *testing.T) {
+	assert.Equal(t, "uid=alice,ou=people,dc=example,dc=com", normalizeDN("UID=Alice, OU=People,DC=example,DC=com"))
+}
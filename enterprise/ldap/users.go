@@ -0,0 +1,137 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ldap
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/ldap"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// objectGUIDAttribute is the binary Active Directory identifier.
+const objectGUIDAttribute = "objectguid"
+
+// attributeValue returns the first value of attribute, decoding binary GUIDs.
+func attributeValue(entry *ldap.Entry, attribute string) string {
+	if attribute == "" {
+		return ""
+	}
+
+	if strings.EqualFold(attribute, objectGUIDAttribute) {
+		if raw := entry.GetRawAttributeValue(attribute); len(raw) == 16 {
+			return guidToString(raw)
+		}
+	}
+
+	return entry.GetAttributeValue(attribute)
+}
+
+// entryToUser maps a directory entry to a user, the way it would be saved on
+// first login.
+func entryToUser(settings *model.LdapSettings, entry *ldap.Entry) *model.User {
+	authData := attributeValue(entry, *settings.IdAttribute)
+
+	user := &model.User{
+		AuthService: model.UserAuthServiceLdap,
+		AuthData:    model.NewString(authData),
+		Username:    model.CleanUsername(attributeValue(entry, *settings.UsernameAttribute)),
+		Email:       strings.ToLower(attributeValue(entry, *settings.EmailAttribute)),
+		FirstName:   attributeValue(entry, *settings.FirstNameAttribute),
+		LastName:    attributeValue(entry, *settings.LastNameAttribute),
+		Nickname:    attributeValue(entry, *settings.NicknameAttribute),
+		Position:    attributeValue(entry, *settings.PositionAttribute),
+	}
+	user.EmailVerified = true
+
+	return user
+}
+
+// updateUserAttributes copies the directory controlled fields of from onto to
+// and reports whether anything changed.
+func updateUserAttributes(settings *model.LdapSettings, to, from *model.User) bool {
+	changed := false
+	update := func(attribute string, dest *string, value string) {
+		if attribute != "" && *dest != value {
+			*dest = value
+			changed = true
+		}
+	}
+
+	update(*settings.UsernameAttribute, &to.Username, from.Username)
+	update(*settings.EmailAttribute, &to.Email, from.Email)
+	update(*settings.FirstNameAttribute, &to.FirstName, from.FirstName)
+	update(*settings.LastNameAttribute, &to.LastName, from.LastName)
+	update(*settings.NicknameAttribute, &to.Nickname, from.Nickname)
+	update(*settings.PositionAttribute, &to.Position, from.Position)
+
+	return changed
+}
+
+// guidToString formats a binary objectGUID, whose first three fields are little
+// endian, as the canonical GUID string.
+func guidToString(raw []byte) string {
+	return fmt.Sprintf("%08x-%04x-%04x-%s-%s",
+		binary.LittleEndian.Uint32(raw[0:4]),
+		binary.LittleEndian.Uint16(raw[4:6]),
+		binary.LittleEndian.Uint16(raw[6:8]),
+		hex.EncodeToString(raw[8:10]),
+		hex.EncodeToString(raw[10:16]),
+	)
+}
+
+// guidFromString is the inverse of guidToString.
+func guidFromString(guid string) ([]byte, bool) {
+	parts := strings.Split(guid, "-")
+	if len(parts) != 5 {
+		return nil, false
+	}
+
+	decoded, err := hex.DecodeString(strings.Join(parts, ""))
+	if err != nil || len(decoded) != 16 {
+		return nil, false
+	}
+
+	raw := make([]byte, 16)
+	binary.LittleEndian.PutUint32(raw[0:4], binary.BigEndian.Uint32(decoded[0:4]))
+	binary.LittleEndian.PutUint16(raw[4:6], binary.BigEndian.Uint16(decoded[4:6]))
+	binary.LittleEndian.PutUint16(raw[6:8], binary.BigEndian.Uint16(decoded[6:8]))
+	copy(raw[8:], decoded[8:])
+
+	return raw, true
+}
+
+// GetADLdapIdFromSAMLId converts the base64 objectGUID sent by ADFS to the form
+// stored as the AuthData of AD/LDAP users.
+func (l *MatterfossLdap) GetADLdapIdFromSAMLId(authData string) string {
+	if !strings.EqualFold(*l.app.Config().LdapSettings.IdAttribute, objectGUIDAttribute) {
+		return authData
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(authData)
+	if err != nil || len(raw) != 16 {
+		return authData
+	}
+
+	return guidToString(raw)
+}
+
+// GetSAMLIdFromADLdapId is the inverse of GetADLdapIdFromSAMLId.
+func (l *MatterfossLdap) GetSAMLIdFromADLdapId(authData string) string {
+	if !strings.EqualFold(*l.app.Config().LdapSettings.IdAttribute, objectGUIDAttribute) {
+		return authData
+	}
+
+	raw, ok := guidFromString(authData)
+	if !ok {
+		return authData
+	}
+
+	return base64.StdEncoding.EncodeToString(raw)
+}
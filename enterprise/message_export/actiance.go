@@ -0,0 +1,191 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package message_export
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+const (
+	actianceExportFileName = "actiance_export.xml"
+	actianceXMLNamespace   = "http://www.w3.org/2001/XMLSchema-instance"
+
+	actianceTransferCompleted = "Completed"
+	actianceTransferFailed    = "Failed"
+)
+
+// actianceFileDump is the root element of an Actiance export.
+type actianceFileDump struct {
+	XMLName       xml.Name               `xml:"FileDump"`
+	XMLNS         string                 `xml:"xmlns:xsi,attr"`
+	Conversations []actianceConversation `xml:"Conversation"`
+}
+
+// actianceConversation is what happened in one channel during a batch. Its elements
+// are in chronological order.
+type actianceConversation struct {
+	XMLName      xml.Name `xml:"Conversation"`
+	Perspective  string   `xml:"Perspective,attr"`
+	RoomId       string   `xml:"RoomID"`
+	StartTimeUTC int64    `xml:"StartTimeUTC"`
+	Elements     []interface{}
+	EndTimeUTC   int64 `xml:"EndTimeUTC"`
+}
+
+type actianceParticipant struct {
+	LoginName        string `xml:"LoginName"`
+	UserType         string `xml:"UserType"`
+	DateTimeUTC      int64  `xml:"DateTimeUTC"`
+	CorporateEmailId string `xml:"CorporateEmailID"`
+}
+
+type actianceParticipantEntered struct {
+	XMLName xml.Name `xml:"ParticipantEntered"`
+	actianceParticipant
+}
+
+type actianceParticipantLeft struct {
+	XMLName xml.Name `xml:"ParticipantLeft"`
+	actianceParticipant
+}
+
+type actianceMessage struct {
+	XMLName      xml.Name `xml:"Message"`
+	LoginName    string   `xml:"LoginName"`
+	UserType     string   `xml:"UserType"`
+	DateTimeUTC  int64    `xml:"DateTimeUTC"`
+	Content      string   `xml:"Content"`
+	PreviewsPost string   `xml:"PreviewsPost"`
+}
+
+type actianceFileTransferStarted struct {
+	XMLName      xml.Name `xml:"FileTransferStarted"`
+	LoginName    string   `xml:"LoginName"`
+	DateTimeUTC  int64    `xml:"DateTimeUTC"`
+	UserFileName string   `xml:"UserFileName"`
+	FileName     string   `xml:"FileName"`
+}
+
+type actianceFileTransferEnded struct {
+	XMLName      xml.Name `xml:"FileTransferEnded"`
+	LoginName    string   `xml:"LoginName"`
+	DateTimeUTC  int64    `xml:"DateTimeUTC"`
+	UserFileName string   `xml:"UserFileName"`
+	FileName     string   `xml:"FileName"`
+	Status       string   `xml:"Status"`
+}
+
+// writeActianceBatch writes a batch to a zip file in dir holding an Actiance XML file,
+// with one conversation per channel, and the attachments.
+func (me *MessageExport) writeActianceBatch(b *batch, dir string) (string, int64, *model.AppError) {
+	z, appErr := newZipExport(me.app)
+	if appErr != nil {
+		return "", 0, appErr
+	}
+	defer z.close()
+
+	dump := &actianceFileDump{XMLNS: actianceXMLNamespace}
+	var transfers []*actianceFileTransferEnded
+	var attachments []*model.FileInfo
+	for _, channel := range b.Channels {
+		conversation := actianceConversation{
+			Perspective:  channel.ChannelDisplayName,
+			RoomId:       roomId(channel),
+			StartTimeUTC: actianceTime(b.Start),
+			EndTimeUTC:   actianceTime(b.End),
+		}
+
+		for _, e := range channel.events(b.Start, b.End) {
+			switch e.Type {
+			case eventJoin:
+				conversation.Elements = append(conversation.Elements, &actianceParticipantEntered{actianceParticipant: actianceMember(e.Member, e.Time)})
+			case eventLeave:
+				conversation.Elements = append(conversation.Elements, &actianceParticipantLeft{actianceParticipant: actianceMember(e.Member, e.Time)})
+			case eventPost:
+				loginName := stringValue(e.Post.UserEmail)
+				conversation.Elements = append(conversation.Elements, &actianceMessage{
+					LoginName:    loginName,
+					UserType:     userType(e.Post.IsBot),
+					DateTimeUTC:  actianceTime(e.Time),
+					Content:      stringValue(e.Post.PostMessage),
+					PreviewsPost: e.Post.PreviewID(),
+				})
+
+				infos, appErr := me.postAttachments(e.Post, "ent.message_export.actiance_export.get_attachment_error")
+				if appErr != nil {
+					return "", 0, appErr
+				}
+				for _, info := range infos {
+					started := &actianceFileTransferStarted{
+						LoginName:    loginName,
+						DateTimeUTC:  actianceTime(info.CreateAt),
+						UserFileName: info.Name,
+						FileName:     attachmentPath(info),
+					}
+					ended := &actianceFileTransferEnded{
+						LoginName:    loginName,
+						DateTimeUTC:  actianceTime(info.CreateAt),
+						UserFileName: info.Name,
+						FileName:     attachmentPath(info),
+						Status:       actianceTransferCompleted,
+					}
+					conversation.Elements = append(conversation.Elements, started, ended)
+					transfers = append(transfers, ended)
+					attachments = append(attachments, info)
+				}
+			}
+		}
+		dump.Conversations = append(dump.Conversations, conversation)
+	}
+
+	// Attachments are copied first so that the transfers of missing files are marked
+	// as failed in the XML file.
+	for i, info := range attachments {
+		added, appErr := z.addAttachment(attachmentPath(info), info)
+		if appErr != nil {
+			return "", 0, appErr
+		}
+		if !added {
+			transfers[i].Status = actianceTransferFailed
+		}
+	}
+
+	w, appErr := z.create(actianceExportFileName)
+	if appErr != nil {
+		return "", 0, appErr
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return "", 0, model.NewAppError("writeActianceBatch", "ent.compliance.csv.write_file.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(dump); err != nil {
+		return "", 0, model.NewAppError("writeActianceBatch", "ent.compliance.csv.write_file.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	filePath := path.Join(dir, batchFileName(b))
+	if appErr := z.save(filePath); appErr != nil {
+		return "", 0, appErr
+	}
+	return filePath, int64(len(z.warnings)), nil
+}
+
+func actianceMember(member *model.ChannelMemberHistoryResult, time int64) actianceParticipant {
+	return actianceParticipant{
+		LoginName:        member.UserEmail,
+		UserType:         userType(member.IsBot),
+		DateTimeUTC:      actianceTime(time),
+		CorporateEmailId: member.UserEmail,
+	}
+}
+
+// actianceTime converts milliseconds to the seconds used by Actiance.
+func actianceTime(millis int64) int64 {
+	return millis / 1000
+}
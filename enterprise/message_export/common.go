@@ -0,0 +1,258 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package message_export
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const (
+	// warningFileName is written next to the exported data when some of it is missing.
+	warningFileName = "warning.txt"
+	// attachmentsDirectory holds the attachments inside an export zip file, by post id.
+	attachmentsDirectory = "files"
+)
+
+// batch is one batch of posts from ComplianceStore.MessageExport, grouped by channel.
+type batch struct {
+	// Index counts the batches of an export from zero.
+	Index int
+	// Start and End bound the UpdateAt of the posts in the batch, exclusive and
+	// inclusive respectively.
+	Start    int64
+	End      int64
+	Channels []*channelExport
+}
+
+// channelExport holds the posts of one channel in a batch, along with the users that
+// were members of the channel at some point during the batch.
+type channelExport struct {
+	TeamId          string
+	TeamName        string
+	TeamDisplayName string
+
+	ChannelId          string
+	ChannelName        string
+	ChannelDisplayName string
+	ChannelType        model.ChannelType
+
+	Posts   []*model.MessageExport
+	Members []*model.ChannelMemberHistoryResult
+}
+
+type eventType int
+
+const (
+	eventJoin eventType = iota
+	eventPost
+	eventLeave
+)
+
+// event is something which happened in a channel during a batch.
+type event struct {
+	Type   eventType
+	Time   int64
+	Post   *model.MessageExport
+	Member *model.ChannelMemberHistoryResult
+}
+
+// newBatch groups posts by channel and looks up who was in each channel between start
+// and end.
+func (me *MessageExport) newBatch(index int, start, end int64, posts []*model.MessageExport) (*batch, *model.AppError) {
+	b := &batch{Index: index, Start: start, End: end}
+	byChannel := map[string]*channelExport{}
+	for _, post := range posts {
+		channelId := stringValue(post.ChannelId)
+		channel, ok := byChannel[channelId]
+		if !ok {
+			channel = &channelExport{
+				TeamId:             stringValue(post.TeamId),
+				TeamName:           stringValue(post.TeamName),
+				TeamDisplayName:    stringValue(post.TeamDisplayName),
+				ChannelId:          channelId,
+				ChannelName:        stringValue(post.ChannelName),
+				ChannelDisplayName: stringValue(post.ChannelDisplayName),
+			}
+			if post.ChannelType != nil {
+				channel.ChannelType = *post.ChannelType
+			}
+			byChannel[channelId] = channel
+			b.Channels = append(b.Channels, channel)
+		}
+		channel.Posts = append(channel.Posts, post)
+	}
+
+	for _, channel := range b.Channels {
+		members, err := me.store.ChannelMemberHistory().GetUsersInChannelDuring(start, end, channel.ChannelId)
+		if err != nil {
+			return nil, model.NewAppError("RunExport", "ent.message_export.run_export.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		channel.Members = members
+	}
+
+	return b, nil
+}
+
+// events returns what happened in the channel during the batch in chronological
+// order. Joins come before posts and posts before leaves made at the same time.
+func (c *channelExport) events(start, end int64) []event {
+	var events []event
+	for _, member := range c.Members {
+		if member.JoinTime > start && member.JoinTime <= end {
+			events = append(events, event{Type: eventJoin, Time: member.JoinTime, Member: member})
+		}
+		if member.LeaveTime != nil && *member.LeaveTime > start && *member.LeaveTime <= end {
+			events = append(events, event{Type: eventLeave, Time: *member.LeaveTime, Member: member})
+		}
+	}
+	for _, post := range c.Posts {
+		events = append(events, event{Type: eventPost, Time: int64Value(post.PostCreateAt), Post: post})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Time != events[j].Time {
+			return events[i].Time < events[j].Time
+		}
+		return events[i].Type < events[j].Type
+	})
+	return events
+}
+
+// postAttachments returns the files attached to post, including deleted ones.
+func (me *MessageExport) postAttachments(post *model.MessageExport, errorId string) ([]*model.FileInfo, *model.AppError) {
+	if len(post.PostFileIds) == 0 {
+		return nil, nil
+	}
+	infos, err := me.store.FileInfo().GetForPost(stringValue(post.PostId), true, true, false)
+	if err != nil {
+		return nil, model.NewAppError("postAttachments", errorId, nil, err.Error(), http.StatusInternalServerError)
+	}
+	return infos, nil
+}
+
+// zipExport builds a zip file in a temporary file before saving it to the file store.
+type zipExport struct {
+	app      AppIface
+	tmp      *os.File
+	writer   *zip.Writer
+	warnings []string
+}
+
+func newZipExport(app AppIface) (*zipExport, *model.AppError) {
+	tmp, err := ioutil.TempFile("", "message_export")
+	if err != nil {
+		return nil, model.NewAppError("newZipExport", "ent.message_export.temporary_file.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return &zipExport{app: app, tmp: tmp, writer: zip.NewWriter(tmp)}, nil
+}
+
+// create adds a file called name to the zip file and returns its writer, which is
+// valid until the next call to create.
+func (z *zipExport) create(name string) (io.Writer, *model.AppError) {
+	w, err := z.writer.Create(name)
+	if err != nil {
+		return nil, model.NewAppError("create", "ent.message_export.global_relay.create_file_in_zip.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return w, nil
+}
+
+// warn records a problem which does not stop the export.
+func (z *zipExport) warn(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	mlog.Warn("Message export warning", mlog.String("warning", message))
+	z.warnings = append(z.warnings, message)
+}
+
+// addAttachment copies the file of info into the zip file as name. An attachment which
+// cannot be read is recorded as a warning, and false is returned.
+func (z *zipExport) addAttachment(name string, info *model.FileInfo) (bool, *model.AppError) {
+	reader, appErr := z.app.FileReader(info.Path)
+	if appErr != nil {
+		z.warn("Unable to read the attachment %s (%s) of post %s: %s", info.Name, info.Id, info.PostId, appErr.Error())
+		return false, nil
+	}
+	defer reader.Close()
+
+	w, appErr := z.create(name)
+	if appErr != nil {
+		return false, appErr
+	}
+	if _, err := io.Copy(w, reader); err != nil {
+		return false, model.NewAppError("addAttachment", "ent.compliance.csv.attachment.copy.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return true, nil
+}
+
+// save writes the warnings, if any, and then the zip file to filePath in the file store.
+func (z *zipExport) save(filePath string) *model.AppError {
+	if len(z.warnings) > 0 {
+		w, appErr := z.create(warningFileName)
+		if appErr != nil {
+			return appErr
+		}
+		if _, err := io.WriteString(w, strings.Join(z.warnings, "\n")+"\n"); err != nil {
+			return model.NewAppError("save", "ent.compliance.csv.warning.appError", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	if err := z.writer.Close(); err != nil {
+		return model.NewAppError("save", "ent.message_export.global_relay.close_zip_file.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	if _, err := z.tmp.Seek(0, io.SeekStart); err != nil {
+		return model.NewAppError("save", "ent.compliance.global_relay.rewind_temporary_file.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+	if _, appErr := z.app.WriteFile(z.tmp, filePath); appErr != nil {
+		return appErr
+	}
+	return nil
+}
+
+// close removes the temporary file.
+func (z *zipExport) close() {
+	z.tmp.Close()
+	os.Remove(z.tmp.Name())
+}
+
+// attachmentPath is where an attachment is stored inside an export zip file.
+func attachmentPath(info *model.FileInfo) string {
+	return path.Join(attachmentsDirectory, info.PostId, info.Id+"-"+path.Base(info.Name))
+}
+
+// batchFileName is the name of the file a batch is exported to.
+func batchFileName(b *batch) string {
+	return fmt.Sprintf("batch%03d-%d-%d.zip", b.Index, b.Start, b.End)
+}
+
+// userType describes a user the way the export formats do.
+func userType(isBot bool) string {
+	if isBot {
+		return "bot"
+	}
+	return "user"
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func int64Value(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package message_export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+const (
+	csvPostsFileName    = "posts.csv"
+	csvMetadataFileName = "metadata.json"
+
+	csvPostTypeEnterChannel = "EnterChannel"
+	csvPostTypeLeaveChannel = "LeaveChannel"
+	csvPostTypeAttachment   = "FileAttachment"
+)
+
+// csvFormulaPrefix matches the values which spreadsheets would run as formulas.
+var csvFormulaPrefix = regexp.MustCompile(`^\s*(=|\+|-|@)`)
+
+var csvHeader = []string{
+	"Post Creation Time",
+	"Team Id",
+	"Team Name",
+	"Team Display Name",
+	"Channel Id",
+	"Channel Name",
+	"Channel Display Name",
+	"Channel Type",
+	"User Id",
+	"User Email",
+	"Username",
+	"Post Id",
+	"Original Post Id",
+	"Replied to Post Id",
+	"Post Message",
+	"Post Type",
+	"User Type",
+	"Previews Post Id",
+	"Post Update Time",
+	"Post Delete Time",
+}
+
+// csvChannelMetadata describes a channel of a CSV export in its metadata file.
+type csvChannelMetadata struct {
+	TeamId             string
+	TeamName           string
+	TeamDisplayName    string
+	ChannelId          string
+	ChannelName        string
+	ChannelDisplayName string
+	ChannelType        model.ChannelType
+	RoomId             string
+	StartTime          int64
+	EndTime            int64
+	MessagesCount      int
+	AttachmentsCount   int
+}
+
+// csvMetadata is written to the metadata file of a CSV export.
+type csvMetadata struct {
+	Channels         map[string]*csvChannelMetadata
+	MessagesCount    int
+	AttachmentsCount int
+	StartTime        int64
+	EndTime          int64
+}
+
+// writeCsvBatch writes a batch to a zip file in dir holding the posts, joins and leaves
+// as CSV, the attachments and a metadata file describing the channels.
+func (me *MessageExport) writeCsvBatch(b *batch, dir string) (string, int64, *model.AppError) {
+	z, appErr := newZipExport(me.app)
+	if appErr != nil {
+		return "", 0, appErr
+	}
+	defer z.close()
+
+	w, appErr := z.create(csvPostsFileName)
+	if appErr != nil {
+		return "", 0, appErr
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return "", 0, model.NewAppError("writeCsvBatch", "ent.compliance.csv.header.export.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	metadata := &csvMetadata{Channels: map[string]*csvChannelMetadata{}, StartTime: b.Start, EndTime: b.End}
+	var attachments []*model.FileInfo
+	for _, channel := range b.Channels {
+		channelMetadata := &csvChannelMetadata{
+			TeamId:             channel.TeamId,
+			TeamName:           channel.TeamName,
+			TeamDisplayName:    channel.TeamDisplayName,
+			ChannelId:          channel.ChannelId,
+			ChannelName:        channel.ChannelName,
+			ChannelDisplayName: channel.ChannelDisplayName,
+			ChannelType:        channel.ChannelType,
+			RoomId:             roomId(channel),
+			StartTime:          b.Start,
+			EndTime:            b.End,
+		}
+		metadata.Channels[channel.ChannelId] = channelMetadata
+
+		for _, e := range channel.events(b.Start, b.End) {
+			var rows [][]string
+			switch e.Type {
+			case eventJoin:
+				rows = append(rows, csvMemberRow(channel, e.Member, e.Time, csvPostTypeEnterChannel, "User "+e.Member.Username+" ("+e.Member.UserEmail+") joined the channel"))
+			case eventLeave:
+				rows = append(rows, csvMemberRow(channel, e.Member, e.Time, csvPostTypeLeaveChannel, "User "+e.Member.Username+" ("+e.Member.UserEmail+") left the channel"))
+			case eventPost:
+				rows = append(rows, csvPostRow(e.Post, stringValue(e.Post.PostMessage), stringValue(e.Post.PostType)))
+				channelMetadata.MessagesCount++
+
+				infos, appErr := me.postAttachments(e.Post, "ent.message_export.csv_export.get_attachment_error")
+				if appErr != nil {
+					return "", 0, appErr
+				}
+				for _, info := range infos {
+					rows = append(rows, csvPostRow(e.Post, attachmentPath(info), csvPostTypeAttachment))
+					attachments = append(attachments, info)
+					channelMetadata.AttachmentsCount++
+				}
+			}
+
+			for _, row := range rows {
+				if err := writer.Write(row); err != nil {
+					return "", 0, model.NewAppError("writeCsvBatch", "ent.compliance.csv.post.export.appError", nil, err.Error(), http.StatusInternalServerError)
+				}
+			}
+		}
+		metadata.MessagesCount += channelMetadata.MessagesCount
+		metadata.AttachmentsCount += channelMetadata.AttachmentsCount
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", 0, model.NewAppError("writeCsvBatch", "ent.compliance.csv.write_file.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	// The CSV file must be complete before the next file is added to the zip file.
+	for _, info := range attachments {
+		if _, appErr := z.addAttachment(attachmentPath(info), info); appErr != nil {
+			return "", 0, appErr
+		}
+	}
+
+	metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return "", 0, model.NewAppError("writeCsvBatch", "ent.compliance.csv.metadata.json.marshalling.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+	w, appErr = z.create(csvMetadataFileName)
+	if appErr != nil {
+		return "", 0, appErr
+	}
+	if _, err := w.Write(metadataJSON); err != nil {
+		return "", 0, model.NewAppError("writeCsvBatch", "ent.compliance.csv.metadata.export.appError", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	filePath := path.Join(dir, batchFileName(b))
+	if appErr := z.save(filePath); appErr != nil {
+		return "", 0, appErr
+	}
+	return filePath, int64(len(z.warnings)), nil
+}
+
+func csvPostRow(post *model.MessageExport, message, postType string) []string {
+	return []string{
+		strconv.FormatInt(int64Value(post.PostCreateAt), 10),
+		stringValue(post.TeamId),
+		csvClean(stringValue(post.TeamName)),
+		csvClean(stringValue(post.TeamDisplayName)),
+		stringValue(post.ChannelId),
+		csvClean(stringValue(post.ChannelName)),
+		csvClean(stringValue(post.ChannelDisplayName)),
+		csvChannelType(post.ChannelType),
+		stringValue(post.UserId),
+		csvClean(stringValue(post.UserEmail)),
+		csvClean(stringValue(post.Username)),
+		stringValue(post.PostId),
+		stringValue(post.PostOriginalId),
+		stringValue(post.PostRootId),
+		csvClean(message),
+		postType,
+		userType(post.IsBot),
+		post.PreviewID(),
+		strconv.FormatInt(int64Value(post.PostUpdateAt), 10),
+		strconv.FormatInt(int64Value(post.PostDeleteAt), 10),
+	}
+}
+
+func csvMemberRow(channel *channelExport, member *model.ChannelMemberHistoryResult, time int64, postType, message string) []string {
+	return []string{
+		strconv.FormatInt(time, 10),
+		channel.TeamId,
+		csvClean(channel.TeamName),
+		csvClean(channel.TeamDisplayName),
+		channel.ChannelId,
+		csvClean(channel.ChannelName),
+		csvClean(channel.ChannelDisplayName),
+		string(channel.ChannelType),
+		member.UserId,
+		csvClean(member.UserEmail),
+		csvClean(member.Username),
+		"",
+		"",
+		"",
+		csvClean(message),
+		postType,
+		userType(member.IsBot),
+		"",
+		"",
+		"",
+	}
+}
+
+// csvClean stops spreadsheets from running user content as formulas.
+func csvClean(value string) string {
+	if csvFormulaPrefix.MatchString(value) {
+		return "'" + value
+	}
+	return value
+}
+
+func csvChannelType(channelType *model.ChannelType) string {
+	if channelType == nil {
+		return ""
+	}
+	return string(*channelType)
+}
+
+// roomId identifies a channel across export formats.
+func roomId(channel *channelExport) string {
+	return string(channel.ChannelType) + " - " + channel.ChannelName + " - " + channel.ChannelId
+}
@@ -0,0 +1,382 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package message_export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	gomail "gopkg.in/mail.v2"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mail"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/templates"
+)
+
+const (
+	// globalRelayMaxAttachmentsSize is how many bytes of attachments an email may carry;
+	// Global Relay refuses larger emails.
+	globalRelayMaxAttachmentsSize = 240 * 1024 * 1024
+
+	globalRelayTimeFormat = "2006-01-02 15:04:05 MST"
+
+	globalRelayTemplate               = "globalrelay_compliance_export"
+	globalRelayMessageTemplate        = "globalrelay_compliance_export_message"
+	globalRelayParticipantRowTemplate = "globalrelay_compliance_export_participant_row"
+
+	postPropsOverrideUsername = "override_username"
+)
+
+var (
+	// globalRelayServers are the SMTP servers accepting the emails of each type of
+	// Global Relay customer.
+	globalRelayServers = map[string]string{
+		model.GlobalrelayCustomerTypeA9:  "mailarchivespool1.globalrelay.com",
+		model.GlobalrelayCustomerTypeA10: "feeds.globalrelay.com",
+	}
+	globalRelaySMTPPort = "25"
+
+	// globalRelaySMTPConfig builds the configuration used to deliver emails to Global Relay.
+	globalRelaySMTPConfig = func(settings *model.GlobalRelayMessageExportSettings) *mail.SMTPConfig {
+		server := globalRelayServers[*settings.CustomerType]
+		return &mail.SMTPConfig{
+			ConnectionSecurity: mail.StartTLS,
+			ServerName:         server,
+			Server:             server,
+			Port:               globalRelaySMTPPort,
+			ServerTimeout:      *settings.SMTPServerTimeout,
+			Username:           *settings.SMTPUsername,
+			Password:           *settings.SMTPPassword,
+			EnableSMTPAuth:     true,
+		}
+	}
+)
+
+// globalRelayParticipant is a row of the participants table of an email.
+type globalRelayParticipant struct {
+	UserId   string
+	Username string
+	Email    string
+	IsBot    bool
+	Joined   int64
+	Left     int64
+	Messages int
+}
+
+// globalRelayEmail is the email for one channel of a batch.
+type globalRelayEmail struct {
+	Message  *gomail.Message
+	Channel  *channelExport
+	Warnings []string
+}
+
+// writeGlobalRelayZipBatch writes the emails of a batch to a zip file in dir instead of
+// delivering them.
+func (me *MessageExport) writeGlobalRelayZipBatch(b *batch, dir string) (string, int64, *model.AppError) {
+	z, appErr := newZipExport(me.app)
+	if appErr != nil {
+		return "", 0, appErr
+	}
+	defer z.close()
+
+	emails, appErr := me.globalRelayEmails(b)
+	if appErr != nil {
+		return "", 0, appErr
+	}
+	for _, email := range emails {
+		for _, warning := range email.Warnings {
+			z.warn("%s", warning)
+		}
+
+		w, appErr := z.create(globalRelayEmailFileName(email.Channel))
+		if appErr != nil {
+			return "", 0, appErr
+		}
+		if _, err := email.Message.WriteTo(w); err != nil {
+			return "", 0, model.NewAppError("writeGlobalRelayZipBatch", "ent.message_export.global_relay.generate_email.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	filePath := path.Join(dir, batchFileName(b))
+	if appErr := z.save(filePath); appErr != nil {
+		return "", 0, appErr
+	}
+	return filePath, int64(len(z.warnings)), nil
+}
+
+// deliverGlobalRelayBatch sends the emails of a batch to the Global Relay archive
+// address. Warnings are only logged, as nothing is written to the file store.
+func (me *MessageExport) deliverGlobalRelayBatch(b *batch) (int64, *model.AppError) {
+	settings := me.app.Config().MessageExportSettings.GlobalRelaySettings
+
+	emails, appErr := me.globalRelayEmails(b)
+	if appErr != nil {
+		return 0, appErr
+	}
+	if len(emails) == 0 {
+		return 0, nil
+	}
+
+	config := globalRelaySMTPConfig(settings)
+	conn, err := mail.ConnectToSMTPServerAdvanced(config)
+	if err != nil {
+		return 0, model.NewAppError("deliverGlobalRelayBatch", "ent.message_export.global_relay_export.deliver.unable_to_connect_smtp_server.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.ServerTimeout)*time.Second)
+	defer cancel()
+	client, err := mail.NewSMTPClientAdvanced(ctx, conn, config)
+	if err != nil {
+		return 0, model.NewAppError("deliverGlobalRelayBatch", "ent.message_export.global_relay_export.deliver.unable_to_connect_smtp_server.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	defer client.Close()
+
+	var warnings int64
+	for _, email := range emails {
+		for _, warning := range email.Warnings {
+			mlog.Warn("Message export warning", mlog.String("warning", warning))
+		}
+		warnings += int64(len(email.Warnings))
+
+		if err := client.Mail(email.Message.GetHeader("From")[0]); err != nil {
+			return warnings, model.NewAppError("deliverGlobalRelayBatch", "ent.message_export.global_relay_export.deliver.from_address.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		if err := client.Rcpt(*settings.EmailAddress); err != nil {
+			return warnings, model.NewAppError("deliverGlobalRelayBatch", "ent.message_export.global_relay_export.deliver.to_address.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		w, err := client.Data()
+		if err != nil {
+			return warnings, model.NewAppError("deliverGlobalRelayBatch", "ent.message_export.global_relay_export.deliver.msg.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		if _, err := email.Message.WriteTo(w); err != nil {
+			w.Close()
+			return warnings, model.NewAppError("deliverGlobalRelayBatch", "ent.message_export.global_relay_export.deliver.msg_data.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		if err := w.Close(); err != nil {
+			return warnings, model.NewAppError("deliverGlobalRelayBatch", "ent.message_export.global_relay_export.deliver.close.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	if err := client.Quit(); err != nil {
+		mlog.Warn("Failed to end the Global Relay SMTP session", mlog.Err(err))
+	}
+	return warnings, nil
+}
+
+// globalRelayEmails builds an email for each channel of the batch, with an HTML
+// transcript of the conversation and the attachments.
+func (me *MessageExport) globalRelayEmails(b *batch) ([]*globalRelayEmail, *model.AppError) {
+	container := me.app.TemplatesContainer()
+	if container == nil {
+		return nil, model.NewAppError("globalRelayEmails", "ent.compliance.run_export.template_watcher.appError", nil, "", http.StatusInternalServerError)
+	}
+	emailAddress := *me.app.Config().MessageExportSettings.GlobalRelaySettings.EmailAddress
+
+	var emails []*globalRelayEmail
+	for _, channel := range b.Channels {
+		email := &globalRelayEmail{
+			Message: gomail.NewMessage(gomail.SetCharset("UTF-8")),
+			Channel: channel,
+		}
+
+		body, from, appErr := me.globalRelayBody(container, b, channel, email)
+		if appErr != nil {
+			return nil, appErr
+		}
+		if from == "" {
+			from = emailAddress
+		}
+
+		email.Message.SetHeader("From", from)
+		email.Message.SetHeader("To", emailAddress)
+		email.Message.SetHeader("Subject", fmt.Sprintf("Matterfoss Compliance Export: %s", channel.ChannelDisplayName))
+		email.Message.SetDateHeader("Date", millisToTime(b.End))
+		email.Message.SetBody("text/html", body)
+		emails = append(emails, email)
+	}
+	return emails, nil
+}
+
+// globalRelayBody renders the transcript of a channel and attaches the files of its
+// posts to email. It also returns the email address of the first poster.
+func (me *MessageExport) globalRelayBody(container *templates.Container, b *batch, channel *channelExport, email *globalRelayEmail) (string, string, *model.AppError) {
+	participants := map[string]*globalRelayParticipant{}
+	var order []*globalRelayParticipant
+	for _, member := range channel.Members {
+		participant := &globalRelayParticipant{
+			UserId:   member.UserId,
+			Username: member.Username,
+			Email:    member.UserEmail,
+			IsBot:    member.IsBot,
+			Joined:   maxInt64(member.JoinTime, b.Start),
+			Left:     b.End,
+		}
+		if member.LeaveTime != nil && *member.LeaveTime < b.End {
+			participant.Left = *member.LeaveTime
+		}
+		if existing, ok := participants[member.UserId]; ok {
+			// Users who left and came back have several rows of history.
+			existing.Joined = minInt64(existing.Joined, participant.Joined)
+			existing.Left = maxInt64(existing.Left, participant.Left)
+			continue
+		}
+		participants[member.UserId] = participant
+		order = append(order, participant)
+	}
+
+	var from string
+	var messages strings.Builder
+	var attachmentsSize int64
+	for _, post := range channel.Posts {
+		userId := stringValue(post.UserId)
+		participant, ok := participants[userId]
+		if !ok {
+			// Webhooks and some bots post without being members of the channel.
+			participant = &globalRelayParticipant{
+				UserId:   userId,
+				Username: stringValue(post.Username),
+				Email:    stringValue(post.UserEmail),
+				IsBot:    post.IsBot,
+				Joined:   b.Start,
+				Left:     b.End,
+			}
+			participants[userId] = participant
+			order = append(order, participant)
+		}
+		participant.Messages++
+		if from == "" {
+			from = participant.Email
+		}
+
+		message, err := container.RenderToString(globalRelayMessageTemplate, templates.Data{Props: map[string]interface{}{
+			"SentTime":     formatGlobalRelayTime(int64Value(post.PostCreateAt)),
+			"Username":     stringValue(post.Username),
+			"PostUsername": overrideUsername(post),
+			"UserType":     userType(post.IsBot),
+			"Email":        stringValue(post.UserEmail),
+			"Message":      stringValue(post.PostMessage),
+			"PreviewsPost": post.PreviewID(),
+		}})
+		if err != nil {
+			return "", "", model.NewAppError("globalRelayBody", "ent.message_export.global_relay.generate_email.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		messages.WriteString(message)
+
+		infos, appErr := me.postAttachments(post, "ent.message_export.global_relay_export.get_attachment_error")
+		if appErr != nil {
+			return "", "", appErr
+		}
+		for _, info := range infos {
+			if attachmentsSize+info.Size > globalRelayMaxAttachmentsSize {
+				email.Warnings = append(email.Warnings, fmt.Sprintf("The attachment %s (%s) of post %s was removed because the email would be too large.", info.Name, info.Id, info.PostId))
+				continue
+			}
+			data, appErr := me.readAttachment(info)
+			if appErr != nil {
+				email.Warnings = append(email.Warnings, fmt.Sprintf("Unable to read the attachment %s (%s) of post %s: %s", info.Name, info.Id, info.PostId, appErr.Error()))
+				continue
+			}
+			attachmentsSize += info.Size
+			email.Message.Attach(path.Base(info.Name), gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(data)
+				return err
+			}))
+		}
+	}
+
+	var rows strings.Builder
+	for _, participant := range order {
+		row, err := container.RenderToString(globalRelayParticipantRowTemplate, templates.Data{Props: map[string]interface{}{
+			"Username":    participant.Username,
+			"UserType":    userType(participant.IsBot),
+			"Email":       participant.Email,
+			"Joined":      formatGlobalRelayTime(participant.Joined),
+			"Left":        formatGlobalRelayTime(participant.Left),
+			"Duration":    formatGlobalRelayDuration(participant.Left - participant.Joined),
+			"NumMessages": participant.Messages,
+		}})
+		if err != nil {
+			return "", "", model.NewAppError("globalRelayBody", "ent.message_export.global_relay.generate_email.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		rows.WriteString(row)
+	}
+
+	body, err := container.RenderToString(globalRelayTemplate, templates.Data{Props: map[string]interface{}{
+		"ChannelName":     channel.ChannelDisplayName,
+		"Started":         formatGlobalRelayTime(b.Start),
+		"Ended":           formatGlobalRelayTime(b.End),
+		"Duration":        formatGlobalRelayDuration(b.End - b.Start),
+		"ParticipantRows": template.HTML(rows.String()),
+		"Messages":        template.HTML(messages.String()),
+		"ExportDate":      formatGlobalRelayTime(model.GetMillis()),
+	}})
+	if err != nil {
+		return "", "", model.NewAppError("globalRelayBody", "ent.message_export.global_relay.generate_email.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return body, from, nil
+}
+
+func (me *MessageExport) readAttachment(info *model.FileInfo) ([]byte, *model.AppError) {
+	reader, appErr := me.app.FileReader(info.Path)
+	if appErr != nil {
+		return nil, appErr
+	}
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, model.NewAppError("readAttachment", "ent.message_export.global_relay.attach_file.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return data, nil
+}
+
+// overrideUsername returns the name a webhook or bot posted under, if any.
+func overrideUsername(post *model.MessageExport) string {
+	props := map[string]interface{}{}
+	if post.PostProps == nil || json.Unmarshal([]byte(*post.PostProps), &props) != nil {
+		return ""
+	}
+	username, _ := props[postPropsOverrideUsername].(string)
+	return username
+}
+
+func globalRelayEmailFileName(channel *channelExport) string {
+	return fmt.Sprintf("%s - %s.eml", channel.ChannelName, channel.ChannelId)
+}
+
+func millisToTime(millis int64) time.Time {
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC()
+}
+
+func formatGlobalRelayTime(millis int64) string {
+	return millisToTime(millis).Format(globalRelayTimeFormat)
+}
+
+func formatGlobalRelayDuration(millis int64) string {
+	return (time.Duration(millis) * time.Millisecond).Round(time.Second).String()
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package message_export
+
+import (
+	"bufio"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mail"
+)
+
+// smtpServer accepts one SMTP session and records the envelope and data of the
+// emails sent during it.
+type smtpServer struct {
+	listener net.Listener
+	from     []string
+	to       []string
+	data     []string
+	done     chan struct{}
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpServer{listener: listener, done: make(chan struct{})}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		defer close(s.done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch command {
+			case "EHLO", "HELO":
+				text.PrintfLine("250-localhost")
+				text.PrintfLine("250 8BITMIME")
+			case "MAIL":
+				s.from = append(s.from, line)
+				text.PrintfLine("250 OK")
+			case "RCPT":
+				s.to = append(s.to, line)
+				text.PrintfLine("250 OK")
+			case "DATA":
+				text.PrintfLine("354 Go ahead")
+				data, err := text.ReadDotLines()
+				if err != nil {
+					return
+				}
+				s.data = append(s.data, strings.Join(data, "\n"))
+				text.PrintfLine("250 OK")
+			case "QUIT":
+				text.PrintfLine("221 Bye")
+				return
+			default:
+				text.PrintfLine("502 Not implemented")
+			}
+		}
+	}()
+	return s
+}
+
+func TestDeliverGlobalRelay(t *testing.T) {
+	th := setup(t)
+	d := mockExport(t, th)
+	server := newSMTPServer(t)
+
+	defaultConfig := globalRelaySMTPConfig
+	defer func() { globalRelaySMTPConfig = defaultConfig }()
+	globalRelaySMTPConfig = func(settings *model.GlobalRelayMessageExportSettings) *mail.SMTPConfig {
+		config := defaultConfig(settings)
+		host, port, err := net.SplitHostPort(server.listener.Addr().String())
+		require.NoError(t, err)
+		config.Server, config.ServerName, config.Port = host, host, port
+		// The local server does not support encryption, which authentication requires.
+		config.ConnectionSecurity = ""
+		config.EnableSMTPAuth = false
+		return config
+	}
+
+	th.complianceStore.ExpectedCalls = nil
+	done := model.MessageExportCursor{LastPostUpdateAt: 200}
+	th.complianceStore.On("MessageExport", model.MessageExportCursor{}, 2).Return(d.posts[:2], done, nil).Once()
+	th.complianceStore.On("MessageExport", done, 2).Return([]*model.MessageExport{}, done, nil).Once()
+
+	result, appErr := th.messageExport.export(model.ComplianceExportTypeGlobalrelay, 0, "unused", nil)
+	require.Nil(t, appErr)
+	<-server.done
+
+	assert.Equal(t, int64(2), result.Messages)
+	assert.Equal(t, int64(1), result.Warnings)
+	assert.Empty(t, result.Files)
+
+	assert.Equal(t, []string{"MAIL FROM:<alice@example.com> BODY=8BITMIME"}, server.from)
+	assert.Equal(t, []string{"RCPT TO:<archive@example.com>"}, server.to)
+	require.Len(t, server.data, 1)
+	message, err := textproto.NewReader(bufio.NewReader(strings.NewReader(server.data[0] + "\n"))).ReadMIMEHeader()
+	require.NoError(t, err)
+	assert.Equal(t, "Matterfoss Compliance Export: Town Square", message.Get("Subject"))
+	assert.True(t, strings.HasPrefix(message.Get("Content-Type"), "multipart/mixed"))
+	assert.Contains(t, server.data[0], `filename="report.txt"`)
+}
+
+func TestGlobalRelayLimits(t *testing.T) {
+	th := setup(t)
+	d := mockExport(t, th)
+
+	d.file.Size = globalRelayMaxAttachmentsSize
+	d.missing.Size = 1
+	b, appErr := th.messageExport.newBatch(0, 0, 200, d.posts[:2])
+	require.Nil(t, appErr)
+	emails, appErr := th.messageExport.globalRelayEmails(b)
+	require.Nil(t, appErr)
+
+	// The second attachment does not fit once the first one has been added.
+	require.Len(t, emails, 1)
+	require.Len(t, emails[0].Warnings, 1)
+	assert.Contains(t, emails[0].Warnings[0], "missing.txt")
+	assert.Contains(t, emails[0].Warnings[0], "too large")
+
+	th.app.templates = nil
+	_, appErr = th.messageExport.globalRelayEmails(b)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.compliance.run_export.template_watcher.appError", appErr.Id)
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package message_export
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/cjdelisle/matterfoss-server/v6/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const (
+	jobName = "MessageExport"
+
+	// JobDataExportFromTimestamp is set on a job to export the posts updated after it,
+	// rather than carry on from where the previous job stopped.
+	JobDataExportFromTimestamp = "export_from_timestamp"
+	JobDataExportType          = "export_type"
+	JobDataMessagesExported    = "messages_exported"
+	JobDataWarningCount        = "warning_count"
+	JobDataEndTimestamp        = "end_timestamp"
+	JobDataIsDownloadable      = "is_downloadable"
+)
+
+// MessageExportJob implements ejobs.MessageExportJobInterface.
+type MessageExportJob struct {
+	messageExport *MessageExport
+	jobServer     *jobs.JobServer
+}
+
+func (j *MessageExportJob) isEnabled(cfg *model.Config) bool {
+	license := j.messageExport.app.License()
+	return license != nil && *license.Features.MessageExport && *cfg.MessageExportSettings.EnableExport
+}
+
+func (j *MessageExportJob) MakeWorker() model.Worker {
+	return &Worker{
+		stop:      make(chan bool, 1),
+		stopped:   make(chan bool, 1),
+		jobs:      make(chan model.Job),
+		job:       j,
+		jobServer: j.jobServer,
+	}
+}
+
+func (j *MessageExportJob) MakeScheduler() model.Scheduler {
+	startTime := func(cfg *model.Config) *time.Time {
+		scheduled, err := time.Parse("15:04", *cfg.MessageExportSettings.DailyRunTime)
+		if err != nil {
+			mlog.Error("Cannot schedule the message export job", mlog.String("daily_run_time", *cfg.MessageExportSettings.DailyRunTime), mlog.Err(err))
+			return nil
+		}
+		return &scheduled
+	}
+	return jobs.NewDailyScheduler(j.jobServer, model.JobTypeMessageExport, startTime, j.isEnabled)
+}
+
+// Worker runs message export jobs. Unlike a jobs.SimpleWorker, it finishes jobs which
+// met missing data with a warning status.
+type Worker struct {
+	stop      chan bool
+	stopped   chan bool
+	jobs      chan model.Job
+	job       *MessageExportJob
+	jobServer *jobs.JobServer
+}
+
+func (worker *Worker) Run() {
+	mlog.Debug("Worker started", mlog.String("worker", jobName))
+
+	defer func() {
+		mlog.Debug("Worker finished", mlog.String("worker", jobName))
+		worker.stopped <- true
+	}()
+
+	for {
+		select {
+		case <-worker.stop:
+			mlog.Debug("Worker received stop signal", mlog.String("worker", jobName))
+			return
+		case job := <-worker.jobs:
+			mlog.Debug("Worker received a new candidate job.", mlog.String("worker", jobName))
+			worker.DoJob(&job)
+		}
+	}
+}
+
+func (worker *Worker) Stop() {
+	mlog.Debug("Worker stopping", mlog.String("worker", jobName))
+	worker.stop <- true
+	<-worker.stopped
+}
+
+func (worker *Worker) JobChannel() chan<- model.Job {
+	return worker.jobs
+}
+
+func (worker *Worker) IsEnabled(cfg *model.Config) bool {
+	return worker.job.isEnabled(cfg)
+}
+
+func (worker *Worker) DoJob(job *model.Job) {
+	if claimed, err := worker.jobServer.ClaimJob(job); err != nil {
+		mlog.Warn("Worker experienced an error while trying to claim job", mlog.String("worker", jobName), mlog.String("job_id", job.Id), mlog.Err(err))
+		return
+	} else if !claimed {
+		return
+	}
+
+	lastJob, appErr := worker.jobServer.GetLastSuccessfulJobByType(model.JobTypeMessageExport)
+	if appErr != nil {
+		worker.setJobError(job, appErr)
+		return
+	}
+
+	save := func(job *model.Job) {
+		if appErr := worker.jobServer.UpdateInProgressJobData(job); appErr != nil {
+			mlog.Warn("Worker: Failed to save the message export progress", mlog.String("worker", jobName), mlog.String("job_id", job.Id), mlog.Err(appErr))
+		}
+	}
+	result, appErr := worker.job.messageExport.runJob(job, lastJob, save)
+	if appErr != nil {
+		mlog.Error("Worker: Failed to export messages", mlog.String("worker", jobName), mlog.String("job_id", job.Id), mlog.Err(appErr))
+		worker.setJobError(job, appErr)
+		return
+	}
+
+	mlog.Info("Worker: Job is complete", mlog.String("worker", jobName), mlog.String("job_id", job.Id), mlog.Int64("messages_exported", result.Messages), mlog.Int64("warning_count", result.Warnings))
+	if result.Warnings > 0 {
+		appErr = worker.jobServer.SetJobWarning(job)
+	} else {
+		appErr = worker.jobServer.SetJobSuccess(job)
+	}
+	if appErr != nil {
+		mlog.Error("Worker: Failed to set the job status", mlog.String("worker", jobName), mlog.String("job_id", job.Id), mlog.Err(appErr))
+		worker.setJobError(job, appErr)
+	}
+}
+
+func (worker *Worker) setJobError(job *model.Job, appError *model.AppError) {
+	if err := worker.jobServer.SetJobError(job, appError); err != nil {
+		mlog.Error("Worker: Failed to set job error", mlog.String("worker", jobName), mlog.String("job_id", job.Id), mlog.Err(err))
+	}
+}
+
+// runJob exports the posts updated since the previous successful job, lastJob, into a
+// directory named after the job. The job data is saved with save as the export goes.
+func (me *MessageExport) runJob(job *model.Job, lastJob *model.Job, save func(job *model.Job)) (*exportResult, *model.AppError) {
+	if appErr := me.checkLicense("runJob"); appErr != nil {
+		return nil, appErr
+	}
+	settings := me.app.Config().MessageExportSettings
+
+	if job.Data == nil {
+		job.Data = make(map[string]string)
+	}
+	format := job.Data[JobDataExportType]
+	if format == "" {
+		format = *settings.ExportFormat
+		job.Data[JobDataExportType] = format
+	}
+	start, appErr := exportStart(job, lastJob, *settings.ExportFromTimestamp)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	exportDirectory := *me.app.Config().ExportSettings.Directory
+	result, appErr := me.export(format, start, path.Join(exportDirectory, job.Id), func(result *exportResult) {
+		setJobData(job, result)
+		save(job)
+	})
+	if appErr != nil {
+		return nil, appErr
+	}
+	setJobData(job, result)
+
+	if *settings.DownloadExportResults && format != model.ComplianceExportTypeGlobalrelay && len(result.Files) > 0 {
+		if appErr := me.combineFiles(result.Files, path.Join(exportDirectory, job.Id+".zip")); appErr != nil {
+			return nil, appErr
+		}
+		job.Data[JobDataIsDownloadable] = "true"
+	}
+	save(job)
+
+	return result, nil
+}
+
+// exportStart decides which posts a job exports: those updated after the timestamp
+// set on the job, or else after the last post of the previous successful job, or
+// else after the configured timestamp.
+func exportStart(job *model.Job, lastJob *model.Job, exportFromTimestamp int64) (int64, *model.AppError) {
+	if value, ok := job.Data[JobDataExportFromTimestamp]; ok {
+		start, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, model.NewAppError("exportStart", "ent.jobs.do_job.batch_start_timestamp.parse_error", nil, err.Error(), http.StatusBadRequest)
+		}
+		return start, nil
+	}
+
+	if lastJob != nil {
+		if value, ok := lastJob.Data[JobDataEndTimestamp]; ok {
+			if start, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return start, nil
+			}
+			mlog.Warn("Ignoring the end timestamp of the previous message export job", mlog.String("job_id", lastJob.Id), mlog.String("end_timestamp", value))
+		}
+	}
+	return exportFromTimestamp, nil
+}
+
+func setJobData(job *model.Job, result *exportResult) {
+	job.Data[JobDataMessagesExported] = strconv.FormatInt(result.Messages, 10)
+	job.Data[JobDataWarningCount] = strconv.FormatInt(result.Warnings, 10)
+	job.Data[JobDataEndTimestamp] = strconv.FormatInt(result.LastUpdateAt, 10)
+}
+
+// combineFiles zips the files of an export together, so that they can be downloaded
+// as one.
+func (me *MessageExport) combineFiles(files []string, filePath string) *model.AppError {
+	z, appErr := newZipExport(me.app)
+	if appErr != nil {
+		return appErr
+	}
+	defer z.close()
+
+	for _, file := range files {
+		reader, appErr := me.app.FileReader(file)
+		if appErr != nil {
+			return appErr
+		}
+		w, appErr := z.create(path.Base(file))
+		if appErr != nil {
+			reader.Close()
+			return appErr
+		}
+		_, err := io.Copy(w, reader)
+		reader.Close()
+		if err != nil {
+			return model.NewAppError("combineFiles", "ent.compliance.csv.zip.creation.appError", nil, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	return z.save(filePath)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package message_export
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestExportStart(t *testing.T) {
+	lastJob := &model.Job{Data: map[string]string{JobDataEndTimestamp: "200"}}
+
+	for name, tc := range map[string]struct {
+		job      *model.Job
+		lastJob  *model.Job
+		expected int64
+	}{
+		"set on the job":       {job: &model.Job{Data: map[string]string{JobDataExportFromTimestamp: "100"}}, lastJob: lastJob, expected: 100},
+		"previous job":         {job: &model.Job{Data: map[string]string{}}, lastJob: lastJob, expected: 200},
+		"first job":            {job: &model.Job{Data: map[string]string{}}, expected: 300},
+		"invalid previous job": {job: &model.Job{Data: map[string]string{}}, lastJob: &model.Job{Data: map[string]string{JobDataEndTimestamp: "x"}}, expected: 300},
+	} {
+		t.Run(name, func(t *testing.T) {
+			start, appErr := exportStart(tc.job, tc.lastJob, 300)
+			require.Nil(t, appErr)
+			assert.Equal(t, tc.expected, start)
+		})
+	}
+
+	_, appErr := exportStart(&model.Job{Data: map[string]string{JobDataExportFromTimestamp: "x"}}, nil, 0)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.jobs.do_job.batch_start_timestamp.parse_error", appErr.Id)
+}
+
+func TestRunJob(t *testing.T) {
+	th := setup(t)
+	mockExport(t, th)
+	th.app.config.MessageExportSettings.DownloadExportResults = model.NewBool(true)
+
+	job := &model.Job{Id: model.NewId(), Type: model.JobTypeMessageExport}
+	var saved []map[string]string
+	save := func(job *model.Job) {
+		data := map[string]string{}
+		for key, value := range job.Data {
+			data[key] = value
+		}
+		saved = append(saved, data)
+	}
+
+	result, appErr := th.messageExport.runJob(job, nil, save)
+	require.Nil(t, appErr)
+	assert.Equal(t, int64(3), result.Messages)
+
+	// The data is saved after each batch and at the end.
+	require.Len(t, saved, 3)
+	assert.Equal(t, "2", saved[0][JobDataMessagesExported])
+	assert.Equal(t, map[string]string{
+		JobDataExportType:       model.ComplianceExportTypeActiance,
+		JobDataMessagesExported: "3",
+		JobDataWarningCount:     "1",
+		JobDataEndTimestamp:     "300",
+		JobDataIsDownloadable:   "true",
+	}, saved[2])
+
+	// The download holds the files of every batch, where the job API expects them.
+	files := readZip(t, th, path.Join("export", job.Id+".zip"))
+	assert.Len(t, files, 2)
+	assert.Contains(t, files, "batch000-0-200.zip")
+	assert.Contains(t, files, "batch001-200-300.zip")
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package message_export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	ejobs "github.com/cjdelisle/matterfoss-server/v6/einterfaces/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/filestore"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/templates"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+const (
+	// SynchronizeJobTimeout bounds how long StartSynchronizeJob waits when its context
+	// has no deadline.
+	SynchronizeJobTimeout = 24 * time.Hour
+	// synchronizeJobPollInterval is how often a waiting StartSynchronizeJob checks the job.
+	synchronizeJobPollInterval = time.Second
+)
+
+// AppIface is the subset of the app layer used by the message export implementation.
+type AppIface interface {
+	Config() *model.Config
+	License() *model.License
+	CreateJob(job *model.Job) (*model.Job, *model.AppError)
+	GetJob(id string) (*model.Job, *model.AppError)
+	FileReader(path string) (filestore.ReadCloseSeeker, *model.AppError)
+	WriteFile(fr io.Reader, path string) (int64, *model.AppError)
+	TemplatesContainer() *templates.Container
+}
+
+// appAdapter exposes the templates, which only the server holds.
+type appAdapter struct {
+	*app.App
+}
+
+func (a appAdapter) TemplatesContainer() *templates.Container {
+	return a.Srv().TemplatesContainer()
+}
+
+func init() {
+	app.RegisterMessageExportInterface(func(a *app.App) einterfaces.MessageExportInterface {
+		return New(appAdapter{a}, a.Srv().Store)
+	})
+	app.RegisterJobsMessageExportJobInterface(func(s *app.Server) ejobs.MessageExportJobInterface {
+		a := app.New(app.ServerConnector(s.Channels()))
+		return &MessageExportJob{
+			messageExport: New(appAdapter{a}, s.Store),
+			jobServer:     s.Jobs,
+		}
+	})
+}
+
+// MessageExport implements einterfaces.MessageExportInterface. Posts are read from
+// ComplianceStore.MessageExport in batches ordered by UpdateAt, so that an export
+// can carry on from where the previous one stopped.
+type MessageExport struct {
+	app   AppIface
+	store store.Store
+}
+
+func New(app AppIface, store store.Store) *MessageExport {
+	return &MessageExport{app: app, store: store}
+}
+
+func (me *MessageExport) checkLicense(where string) *model.AppError {
+	if license := me.app.License(); license == nil || !*license.Features.MessageExport {
+		return model.NewAppError(where, "ent.compliance.licence_disable.app_error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+// StartSynchronizeJob creates a message export job starting at exportFromTimestamp
+// and waits for it to finish, or for ctx to be done.
+func (me *MessageExport) StartSynchronizeJob(ctx context.Context, exportFromTimestamp int64) (*model.Job, *model.AppError) {
+	job, appErr := me.app.CreateJob(&model.Job{
+		Type: model.JobTypeMessageExport,
+		Data: map[string]string{
+			JobDataExportFromTimestamp: strconv.FormatInt(exportFromTimestamp, 10),
+		},
+	})
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, SynchronizeJobTimeout)
+		defer cancel()
+	}
+	ticker := time.NewTicker(synchronizeJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			job, appErr = me.app.GetJob(job.Id)
+			if appErr != nil {
+				return nil, appErr
+			}
+			switch job.Status {
+			case model.JobStatusSuccess, model.JobStatusWarning, model.JobStatusError, model.JobStatusCanceled:
+				return job, nil
+			}
+		case <-ctx.Done():
+			return job, model.NewAppError("StartSynchronizeJob", "ent.jobs.start_synchronize_job.timeout", nil, ctx.Err().Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// RunExport exports every post updated after since in the given format, and returns
+// the number of warnings, such as missing attachments, met on the way.
+func (me *MessageExport) RunExport(format string, since int64) (int64, *model.AppError) {
+	if appErr := me.checkLicense("RunExport"); appErr != nil {
+		return 0, appErr
+	}
+	dir := path.Join(*me.app.Config().ExportSettings.Directory, fmt.Sprintf("%s-%d", format, model.GetMillis()))
+
+	result, appErr := me.export(format, since, dir, nil)
+	if appErr != nil {
+		return 0, appErr
+	}
+	return result.Warnings, nil
+}
+
+// exportResult summarizes an export.
+type exportResult struct {
+	// Messages is the number of posts exported.
+	Messages int64
+	// Warnings counts the problems which did not stop the export.
+	Warnings int64
+	// LastUpdateAt is the UpdateAt of the last post exported, where the next export
+	// should start.
+	LastUpdateAt int64
+	// Files are the paths of the files written to the file store.
+	Files []string
+}
+
+// export writes the posts updated after since to dir, one file per batch. progress,
+// if not nil, is called after each batch.
+func (me *MessageExport) export(format string, since int64, dir string, progress func(result *exportResult)) (*exportResult, *model.AppError) {
+	var writeBatch func(b *batch) (string, int64, *model.AppError)
+	switch format {
+	case model.ComplianceExportTypeCsv:
+		writeBatch = func(b *batch) (string, int64, *model.AppError) { return me.writeCsvBatch(b, dir) }
+	case model.ComplianceExportTypeActiance:
+		writeBatch = func(b *batch) (string, int64, *model.AppError) { return me.writeActianceBatch(b, dir) }
+	case model.ComplianceExportTypeGlobalrelayZip:
+		writeBatch = func(b *batch) (string, int64, *model.AppError) { return me.writeGlobalRelayZipBatch(b, dir) }
+	case model.ComplianceExportTypeGlobalrelay:
+		writeBatch = func(b *batch) (string, int64, *model.AppError) {
+			warnings, appErr := me.deliverGlobalRelayBatch(b)
+			return "", warnings, appErr
+		}
+	default:
+		return nil, model.NewAppError("RunExport", "ent.compliance.bad_export_type.appError", map[string]interface{}{"ExportType": format}, "", http.StatusBadRequest)
+	}
+
+	batchSize := *me.app.Config().MessageExportSettings.BatchSize
+	result := &exportResult{LastUpdateAt: since}
+	cursor := model.MessageExportCursor{LastPostUpdateAt: since}
+	for index := 0; ; index++ {
+		posts, next, err := me.store.Compliance().MessageExport(cursor, batchSize)
+		if err != nil {
+			return nil, model.NewAppError("RunExport", "ent.message_export.run_export.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		if len(posts) == 0 {
+			break
+		}
+
+		b, appErr := me.newBatch(index, cursor.LastPostUpdateAt, next.LastPostUpdateAt, posts)
+		if appErr != nil {
+			return nil, appErr
+		}
+		file, warnings, appErr := writeBatch(b)
+		if appErr != nil {
+			return nil, appErr
+		}
+
+		if file != "" {
+			result.Files = append(result.Files, file)
+		}
+		result.Messages += int64(len(posts))
+		result.Warnings += warnings
+		result.LastUpdateAt = next.LastPostUpdateAt
+		cursor = next
+
+		mlog.Debug("Exported a batch of messages", mlog.String("format", format), mlog.Int("count", len(posts)), mlog.Int64("last_update_at", cursor.LastPostUpdateAt))
+		if progress != nil {
+			progress(result)
+		}
+		if len(posts) < batchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
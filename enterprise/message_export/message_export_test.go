@@ -0,0 +1,336 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package message_export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/filestore"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/templates"
+	"github.com/cjdelisle/matterfoss-server/v6/store/storetest/mocks"
+)
+
+// mockApp keeps its files in a local file store.
+type mockApp struct {
+	config    *model.Config
+	license   *model.License
+	backend   filestore.FileBackend
+	templates *templates.Container
+	jobs      map[string]*model.Job
+}
+
+func (ma *mockApp) Config() *model.Config                    { return ma.config }
+func (ma *mockApp) License() *model.License                  { return ma.license }
+func (ma *mockApp) TemplatesContainer() *templates.Container { return ma.templates }
+
+func (ma *mockApp) CreateJob(job *model.Job) (*model.Job, *model.AppError) {
+	job.Id = model.NewId()
+	job.Status = model.JobStatusPending
+	ma.jobs[job.Id] = job
+	return job, nil
+}
+
+// GetJob finishes jobs as soon as they are looked at.
+func (ma *mockApp) GetJob(id string) (*model.Job, *model.AppError) {
+	job := ma.jobs[id]
+	job.Status = model.JobStatusSuccess
+	return job, nil
+}
+
+func (ma *mockApp) FileReader(path string) (filestore.ReadCloseSeeker, *model.AppError) {
+	reader, err := ma.backend.Reader(path)
+	if err != nil {
+		return nil, model.NewAppError("FileReader", "api.file.file_reader.app_error", nil, err.Error(), 404)
+	}
+	return reader, nil
+}
+
+func (ma *mockApp) WriteFile(fr io.Reader, path string) (int64, *model.AppError) {
+	written, err := ma.backend.WriteFile(fr, path)
+	if err != nil {
+		return 0, model.NewAppError("WriteFile", "api.file.write_file.app_error", nil, err.Error(), 500)
+	}
+	return written, nil
+}
+
+type testHelper struct {
+	messageExport *MessageExport
+	app           *mockApp
+	root          string
+
+	complianceStore           *mocks.ComplianceStore
+	channelMemberHistoryStore *mocks.ChannelMemberHistoryStore
+	fileInfoStore             *mocks.FileInfoStore
+}
+
+func setup(t *testing.T) *testHelper {
+	config := &model.Config{}
+	config.SetDefaults()
+	config.MessageExportSettings.EnableExport = model.NewBool(true)
+	config.MessageExportSettings.BatchSize = model.NewInt(2)
+	config.MessageExportSettings.GlobalRelaySettings.EmailAddress = model.NewString("archive@example.com")
+
+	root := t.TempDir()
+	backend, err := filestore.NewFileBackend(filestore.FileBackendSettings{DriverName: model.ImageDriverLocal, Directory: root})
+	require.NoError(t, err)
+	templatesDir, ok := templates.GetTemplateDirectory()
+	require.True(t, ok)
+	container, err := templates.New(templatesDir)
+	require.NoError(t, err)
+
+	th := &testHelper{
+		app: &mockApp{
+			config:    config,
+			license:   model.NewTestLicense("message_export"),
+			backend:   backend,
+			templates: container,
+			jobs:      map[string]*model.Job{},
+		},
+		root:                      root,
+		complianceStore:           &mocks.ComplianceStore{},
+		channelMemberHistoryStore: &mocks.ChannelMemberHistoryStore{},
+		fileInfoStore:             &mocks.FileInfoStore{},
+	}
+	mockStore := &mocks.Store{}
+	mockStore.On("Compliance").Return(th.complianceStore)
+	mockStore.On("ChannelMemberHistory").Return(th.channelMemberHistoryStore)
+	mockStore.On("FileInfo").Return(th.fileInfoStore)
+	th.messageExport = New(th.app, mockStore)
+	return th
+}
+
+type testData struct {
+	channelId string
+	alice     *model.ChannelMemberHistoryResult
+	bob       *model.ChannelMemberHistoryResult
+	posts     []*model.MessageExport
+	file      *model.FileInfo
+	missing   *model.FileInfo
+}
+
+// mockExport sets up three posts in one channel, exported in two batches. Bob joins
+// the channel, posts a file and a missing file, and leaves.
+func mockExport(t *testing.T, th *testHelper) *testData {
+	channelId := model.NewId()
+	channelType := model.ChannelTypeOpen
+	newPost := func(user *model.ChannelMemberHistoryResult, at int64, message string) *model.MessageExport {
+		return &model.MessageExport{
+			TeamId:             model.NewString("team-id"),
+			TeamName:           model.NewString("team"),
+			TeamDisplayName:    model.NewString("Team"),
+			ChannelId:          model.NewString(channelId),
+			ChannelName:        model.NewString("town-square"),
+			ChannelDisplayName: model.NewString("Town Square"),
+			ChannelType:        &channelType,
+			UserId:             model.NewString(user.UserId),
+			UserEmail:          model.NewString(user.UserEmail),
+			Username:           model.NewString(user.Username),
+			PostId:             model.NewString(model.NewId()),
+			PostCreateAt:       model.NewInt64(at),
+			PostUpdateAt:       model.NewInt64(at),
+			PostDeleteAt:       model.NewInt64(0),
+			PostMessage:        model.NewString(message),
+			PostType:           model.NewString(""),
+			PostRootId:         model.NewString(""),
+			PostOriginalId:     model.NewString(""),
+		}
+	}
+
+	d := &testData{
+		channelId: channelId,
+		alice:     &model.ChannelMemberHistoryResult{ChannelId: channelId, UserId: model.NewId(), JoinTime: 1, UserEmail: "alice@example.com", Username: "alice"},
+		bob:       &model.ChannelMemberHistoryResult{ChannelId: channelId, UserId: model.NewId(), JoinTime: 150, LeaveTime: model.NewInt64(250), UserEmail: "bob@example.com", Username: "bob"},
+	}
+	d.posts = []*model.MessageExport{
+		newPost(d.alice, 100, "=1+1"),
+		newPost(d.bob, 200, "here are the files"),
+		newPost(d.alice, 300, "thanks"),
+	}
+	d.posts[1].PostFileIds = model.StringArray{model.NewId(), model.NewId()}
+	d.file = &model.FileInfo{Id: d.posts[1].PostFileIds[0], PostId: *d.posts[1].PostId, Name: "report.txt", Path: "data/report.txt", Size: 6, CreateAt: 200}
+	d.missing = &model.FileInfo{Id: d.posts[1].PostFileIds[1], PostId: *d.posts[1].PostId, Name: "missing.txt", Path: "data/missing.txt", Size: 7, CreateAt: 200}
+	_, err := th.app.backend.WriteFile(strings.NewReader("report"), d.file.Path)
+	require.NoError(t, err)
+
+	afterFirst := model.MessageExportCursor{LastPostUpdateAt: 200, LastPostId: *d.posts[1].PostId}
+	afterSecond := model.MessageExportCursor{LastPostUpdateAt: 300, LastPostId: *d.posts[2].PostId}
+	th.complianceStore.On("MessageExport", model.MessageExportCursor{}, 2).Return(d.posts[:2], afterFirst, nil).Once()
+	th.complianceStore.On("MessageExport", afterFirst, 2).Return(d.posts[2:], afterSecond, nil).Once()
+	th.channelMemberHistoryStore.On("GetUsersInChannelDuring", int64(0), int64(200), channelId).Return([]*model.ChannelMemberHistoryResult{d.alice, d.bob}, nil)
+	th.channelMemberHistoryStore.On("GetUsersInChannelDuring", int64(200), int64(300), channelId).Return([]*model.ChannelMemberHistoryResult{d.alice, d.bob}, nil)
+	th.fileInfoStore.On("GetForPost", *d.posts[1].PostId, true, true, false).Return([]*model.FileInfo{d.file, d.missing}, nil)
+
+	return d
+}
+
+// readZip returns the files of a zip file in the file store by name.
+func readZip(t *testing.T, th *testHelper, path string) map[string]string {
+	data, err := th.app.backend.ReadFile(path)
+	require.NoError(t, err)
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, file := range reader.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+		files[file.Name] = string(content)
+	}
+	return files
+}
+
+func TestRunExport(t *testing.T) {
+	t.Run("unknown format", func(t *testing.T) {
+		th := setup(t)
+		_, appErr := th.messageExport.RunExport("pdf", 0)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.compliance.bad_export_type.appError", appErr.Id)
+	})
+
+	t.Run("unlicensed", func(t *testing.T) {
+		th := setup(t)
+		th.app.license.Features.MessageExport = model.NewBool(false)
+		_, appErr := th.messageExport.RunExport(model.ComplianceExportTypeCsv, 0)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.compliance.licence_disable.app_error", appErr.Id)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		th := setup(t)
+		d := mockExport(t, th)
+
+		warnings, appErr := th.messageExport.RunExport(model.ComplianceExportTypeCsv, 0)
+		require.Nil(t, appErr)
+		assert.Equal(t, int64(1), warnings)
+
+		matches, err := filepath.Glob(filepath.Join(th.root, "export", "csv-*", "*.zip"))
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+
+		files := readZip(t, th, filepath.Join("export", filepath.Base(filepath.Dir(matches[0])), "batch000-0-200.zip"))
+		assert.Equal(t, "report", files[attachmentPath(d.file)])
+		assert.Contains(t, files[warningFileName], "missing.txt")
+		assert.Contains(t, files[csvMetadataFileName], `"MessagesCount": 2`)
+
+		records, err := csv.NewReader(strings.NewReader(files[csvPostsFileName])).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 7)
+		assert.Equal(t, csvHeader, records[0])
+		assert.Equal(t, []string{"1", "alice", "EnterChannel"}, []string{records[1][0], records[1][10], records[1][15]})
+		assert.Equal(t, "'=1+1", records[2][14])
+		assert.Equal(t, []string{"150", "bob", "EnterChannel"}, []string{records[3][0], records[3][10], records[3][15]})
+		assert.Equal(t, "here are the files", records[4][14])
+		assert.Equal(t, []string{attachmentPath(d.file), csvPostTypeAttachment}, records[5][14:16])
+		// The missing file is still listed, so that it is known to have existed.
+		assert.Equal(t, []string{attachmentPath(d.missing), csvPostTypeAttachment}, records[6][14:16])
+		assert.NotContains(t, files, attachmentPath(d.missing))
+
+		files = readZip(t, th, filepath.Join("export", filepath.Base(filepath.Dir(matches[1])), "batch001-200-300.zip"))
+		records, err = csv.NewReader(strings.NewReader(files[csvPostsFileName])).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"250", "bob", "LeaveChannel"}, []string{records[1][0], records[1][10], records[1][15]})
+		assert.Equal(t, "thanks", records[2][14])
+		assert.NotContains(t, files, warningFileName)
+
+		th.complianceStore.AssertExpectations(t)
+	})
+}
+
+func TestActianceExport(t *testing.T) {
+	th := setup(t)
+	d := mockExport(t, th)
+
+	result, appErr := th.messageExport.export(model.ComplianceExportTypeActiance, 0, "actiance", nil)
+	require.Nil(t, appErr)
+	assert.Equal(t, &exportResult{
+		Messages:     3,
+		Warnings:     1,
+		LastUpdateAt: 300,
+		Files:        []string{"actiance/batch000-0-200.zip", "actiance/batch001-200-300.zip"},
+	}, result)
+
+	files := readZip(t, th, result.Files[0])
+	assert.Equal(t, "report", files[attachmentPath(d.file)])
+
+	// The elements of a conversation are in chronological order.
+	decoder := xml.NewDecoder(strings.NewReader(files[actianceExportFileName]))
+	var elements []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local != "LoginName" && start.Name.Local != "UserType" && start.Name.Local != "DateTimeUTC" {
+			elements = append(elements, start.Name.Local)
+		}
+	}
+	assert.Equal(t, []string{
+		"FileDump", "Conversation", "RoomID", "StartTimeUTC",
+		"ParticipantEntered", "CorporateEmailID",
+		"Message", "Content", "PreviewsPost",
+		"ParticipantEntered", "CorporateEmailID",
+		"Message", "Content", "PreviewsPost",
+		"FileTransferStarted", "UserFileName", "FileName",
+		"FileTransferEnded", "UserFileName", "FileName", "Status",
+		"FileTransferStarted", "UserFileName", "FileName",
+		"FileTransferEnded", "UserFileName", "FileName", "Status",
+		"EndTimeUTC",
+	}, elements)
+	assert.Contains(t, files[actianceExportFileName], `<Conversation Perspective="Town Square">`)
+	assert.Contains(t, files[actianceExportFileName], "<Status>Completed</Status>")
+	assert.Contains(t, files[actianceExportFileName], "<Status>Failed</Status>")
+}
+
+func TestGlobalRelayZipExport(t *testing.T) {
+	th := setup(t)
+	d := mockExport(t, th)
+
+	result, appErr := th.messageExport.export(model.ComplianceExportTypeGlobalrelayZip, 0, "globalrelay", nil)
+	require.Nil(t, appErr)
+	assert.Equal(t, int64(1), result.Warnings)
+	require.Len(t, result.Files, 2)
+
+	files := readZip(t, th, result.Files[0])
+	email := files[globalRelayEmailFileName(&channelExport{ChannelName: "town-square", ChannelId: d.channelId})]
+	require.NotEmpty(t, email)
+	assert.Contains(t, email, "From: alice@example.com")
+	assert.Contains(t, email, "To: archive@example.com")
+	assert.Contains(t, email, "Subject: Matterfoss Compliance Export: Town Square")
+	assert.Contains(t, email, `filename="report.txt"`)
+	assert.NotContains(t, email, `filename="missing.txt"`)
+	assert.Contains(t, files[warningFileName], "missing.txt")
+}
+
+func TestStartSynchronizeJob(t *testing.T) {
+	th := setup(t)
+
+	job, appErr := th.messageExport.StartSynchronizeJob(context.Background(), 1234)
+	require.Nil(t, appErr)
+	assert.Equal(t, model.JobTypeMessageExport, job.Type)
+	assert.Equal(t, "1234", job.Data[JobDataExportFromTimestamp])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, appErr = th.messageExport.StartSynchronizeJob(ctx, 0)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.jobs.start_synchronize_job.timeout", appErr.Id)
+}
@@ -0,0 +1,475 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/mattermost/logr/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const (
+	namespace = "matterfoss"
+
+	subsystemPost          = "post"
+	subsystemHTTP          = "http"
+	subsystemCluster       = "cluster"
+	subsystemLogin         = "login"
+	subsystemCaching       = "cache"
+	subsystemWebsocket     = "websocket"
+	subsystemSearch        = "search"
+	subsystemDB            = "db"
+	subsystemAPI           = "api"
+	subsystemPlugin        = "plugin"
+	subsystemSystem        = "system"
+	subsystemLogging       = "logging"
+	subsystemRemoteCluster = "remote_cluster"
+	subsystemJobs          = "jobs"
+
+	// sessionCacheName labels the session cache, which has its own methods.
+	sessionCacheName = "Session"
+)
+
+// ServerIface is the subset of the server used by the metrics implementation.
+type ServerIface interface {
+	License() *model.License
+	HandleMetrics(route string, h http.Handler)
+}
+
+func init() {
+	app.RegisterMetricsInterface(func(s *app.Server) einterfaces.MetricsInterface {
+		return New(s)
+	})
+}
+
+// Metrics implements einterfaces.MetricsInterface with Prometheus collectors, served
+// at /metrics on MetricsSettings.ListenAddress.
+type Metrics struct {
+	server   ServerIface
+	registry *prometheus.Registry
+
+	registerOnce sync.Once
+
+	PostCreate         prometheus.Counter
+	WebhookPost        prometheus.Counter
+	PostSentEmail      prometheus.Counter
+	PostSentPush       prometheus.Counter
+	PostBroadcast      prometheus.Counter
+	PostFileAttachment prometheus.Counter
+
+	HTTPRequests prometheus.Counter
+	HTTPErrors   prometheus.Counter
+
+	ClusterRequests        prometheus.Counter
+	ClusterRequestDuration prometheus.Histogram
+	ClusterEvents          *prometheus.CounterVec
+
+	Logins     prometheus.Counter
+	LoginFails prometheus.Counter
+
+	EtagHits   *prometheus.CounterVec
+	EtagMisses *prometheus.CounterVec
+
+	MemCacheHits          *prometheus.CounterVec
+	MemCacheMisses        *prometheus.CounterVec
+	MemCacheInvalidations *prometheus.CounterVec
+
+	WebsocketEvents                   *prometheus.CounterVec
+	WebsocketBroadcasts               *prometheus.CounterVec
+	WebsocketBroadcastBufferSize      *prometheus.GaugeVec
+	WebsocketBroadcastUsersRegistered *prometheus.GaugeVec
+	WebsocketReconnects               *prometheus.CounterVec
+
+	PostsSearches       prometheus.Counter
+	PostsSearchDuration prometheus.Histogram
+	FilesSearches       prometheus.Counter
+	FilesSearchDuration prometheus.Histogram
+
+	PostsIndexed    prometheus.Counter
+	FilesIndexed    prometheus.Counter
+	UsersIndexed    prometheus.Counter
+	ChannelsIndexed prometheus.Counter
+
+	StoreMethodDuration *prometheus.HistogramVec
+	ReplicaLagAbsolute  *prometheus.GaugeVec
+	ReplicaLagTime      *prometheus.GaugeVec
+
+	APIEndpointDuration *prometheus.HistogramVec
+	EnabledUsers        prometheus.Gauge
+
+	PluginHookDuration  *prometheus.HistogramVec
+	PluginMultiHookIter *prometheus.HistogramVec
+	PluginMultiHook     prometheus.Histogram
+	PluginAPIDuration   *prometheus.HistogramVec
+
+	LoggerQueueSize *prometheus.GaugeVec
+	LoggerLogged    *prometheus.CounterVec
+	LoggerErrors    *prometheus.CounterVec
+	LoggerDropped   *prometheus.CounterVec
+	LoggerBlocked   *prometheus.CounterVec
+
+	RemoteClusterSent    *prometheus.CounterVec
+	RemoteClusterRecv    *prometheus.CounterVec
+	RemoteClusterErrors  *prometheus.CounterVec
+	RemoteClusterPing    *prometheus.HistogramVec
+	RemoteClusterSkew    *prometheus.GaugeVec
+	RemoteClusterChanges *prometheus.CounterVec
+
+	JobsActive *prometheus.GaugeVec
+}
+
+func New(server ServerIface) *Metrics {
+	m := &Metrics{server: server, registry: prometheus.NewRegistry()}
+
+	counter := func(subsystem, name, help string) prometheus.Counter {
+		return prometheus.NewCounter(prometheus.CounterOpts{Namespace: namespace, Subsystem: subsystem, Name: name, Help: help})
+	}
+	counterVec := func(subsystem, name, help string, labels ...string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: namespace, Subsystem: subsystem, Name: name, Help: help}, labels)
+	}
+	gaugeVec := func(subsystem, name, help string, labels ...string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: namespace, Subsystem: subsystem, Name: name, Help: help}, labels)
+	}
+	histogram := func(subsystem, name, help string) prometheus.Histogram {
+		return prometheus.NewHistogram(prometheus.HistogramOpts{Namespace: namespace, Subsystem: subsystem, Name: name, Help: help})
+	}
+	histogramVec := func(subsystem, name, help string, labels ...string) *prometheus.HistogramVec {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{Namespace: namespace, Subsystem: subsystem, Name: name, Help: help}, labels)
+	}
+
+	m.PostCreate = counter(subsystemPost, "total", "The total number of posts created.")
+	m.WebhookPost = counter(subsystemPost, "webhooks_total", "Total number of webhook posts.")
+	m.PostSentEmail = counter(subsystemPost, "emails_sent_total", "The total number of emails sent because a post was made.")
+	m.PostSentPush = counter(subsystemPost, "pushes_sent_total", "The total number of mobile push notifications sent because a post was made.")
+	m.PostBroadcast = counter(subsystemPost, "broadcasts_total", "The total number of websocket broadcasts sent because a post was made.")
+	m.PostFileAttachment = counter(subsystemPost, "file_attachments_total", "The total number of file attachments created because a post was made.")
+
+	m.HTTPRequests = counter(subsystemHTTP, "requests_total", "The total number of http API requests.")
+	m.HTTPErrors = counter(subsystemHTTP, "errors_total", "The total number of http API errors.")
+
+	m.ClusterRequests = counter(subsystemCluster, "cluster_requests_total", "The total number of inter-node requests.")
+	m.ClusterRequestDuration = histogram(subsystemCluster, "cluster_request_duration_seconds", "The total duration in seconds of the inter-node cluster requests.")
+	m.ClusterEvents = counterVec(subsystemCluster, "cluster_event_type_totals", "The total number of cluster requests sent for any type.", "name")
+
+	m.Logins = counter(subsystemLogin, "logins_total", "The total number of successful logins.")
+	m.LoginFails = counter(subsystemLogin, "logins_fail_total", "The total number of failed logins.")
+
+	m.EtagHits = counterVec(subsystemAPI, "etag_hit_total", "Total number of etag hits for a specific api.", "route")
+	m.EtagMisses = counterVec(subsystemAPI, "etag_miss_total", "Total number of etag misses for a specific api.", "route")
+
+	m.MemCacheHits = counterVec(subsystemCaching, "mem_hit_total", "Total number of memory cache hits for a specific cache.", "name")
+	m.MemCacheMisses = counterVec(subsystemCaching, "mem_miss_total", "Total number of memory cache misses for a specific cache.", "name")
+	m.MemCacheInvalidations = counterVec(subsystemCaching, "mem_invalidation_total", "Total number of memory cache invalidations for a specific cache.", "name")
+
+	m.WebsocketEvents = counterVec(subsystemWebsocket, "event_total", "Total number of websocket events.", "type")
+	m.WebsocketBroadcasts = counterVec(subsystemWebsocket, "broadcasts_total", "The total number of websocket broadcasts sent, by type.", "name")
+	m.WebsocketBroadcastBufferSize = gaugeVec(subsystemWebsocket, "broadcast_buffer_size", "Number of events waiting in the broadcast buffer of each hub.", "hub")
+	m.WebsocketBroadcastUsersRegistered = gaugeVec(subsystemWebsocket, "broadcast_buffer_users_registered", "Number of users registered in each hub.", "hub")
+	m.WebsocketReconnects = counterVec(subsystemWebsocket, "reconnects_total", "Total number of websocket reconnect attempts.", "type")
+
+	m.PostsSearches = counter(subsystemSearch, "posts_searches_total", "The total number of post searches carried out.")
+	m.PostsSearchDuration = histogram(subsystemSearch, "posts_searches_duration_seconds", "The total duration in seconds of post searches.")
+	m.FilesSearches = counter(subsystemSearch, "files_searches_total", "The total number of file searches carried out.")
+	m.FilesSearchDuration = histogram(subsystemSearch, "files_searches_duration_seconds", "The total duration in seconds of file searches.")
+	m.PostsIndexed = counter(subsystemSearch, "post_index_total", "The total number of posts indexed.")
+	m.FilesIndexed = counter(subsystemSearch, "file_index_total", "The total number of files indexed.")
+	m.UsersIndexed = counter(subsystemSearch, "user_index_total", "The total number of users indexed.")
+	m.ChannelsIndexed = counter(subsystemSearch, "channel_index_total", "The total number of channels indexed.")
+
+	m.StoreMethodDuration = histogramVec(subsystemDB, "store_time", "Time to execute the store method.", "method", "success")
+	m.ReplicaLagAbsolute = gaugeVec(subsystemDB, "replica_lag_abs", "Replica lag, as given by the absolute replica lag query.", "node")
+	m.ReplicaLagTime = gaugeVec(subsystemDB, "replica_lag_time", "Replica lag, as given by the replica lag time query.", "node")
+
+	m.APIEndpointDuration = histogramVec(subsystemAPI, "time", "Time to execute the api handler.", "handler", "method", "status_code")
+	m.EnabledUsers = prometheus.NewGauge(prometheus.GaugeOpts{Namespace: namespace, Subsystem: subsystemDB, Name: "enabled_users", Help: "The number of enabled users."})
+
+	m.PluginHookDuration = histogramVec(subsystemPlugin, "hook_time", "Time to execute a plugin hook handler, in seconds.", "plugin_id", "hook_name", "success")
+	m.PluginMultiHookIter = histogramVec(subsystemPlugin, "multi_hook_time", "Time to execute a plugin hook handler during a multi-plugin hook, in seconds.", "plugin_id")
+	m.PluginMultiHook = histogram(subsystemPlugin, "multi_hook_server_time", "Time for the server to execute a multi-plugin hook, in seconds.")
+	m.PluginAPIDuration = histogramVec(subsystemPlugin, "api_time", "Time to execute a plugin API call, in seconds.", "plugin_id", "api_name", "success")
+
+	m.LoggerQueueSize = gaugeVec(subsystemLogging, "logger_queue_used", "Number of records in the log target queues.", "name")
+	m.LoggerLogged = counterVec(subsystemLogging, "logger_logged_total", "The total number of records logged.", "name")
+	m.LoggerErrors = counterVec(subsystemLogging, "logger_error_total", "The total number of logger errors.", "name")
+	m.LoggerDropped = counterVec(subsystemLogging, "logger_dropped_total", "The total number of records dropped because the queue was full.", "name")
+	m.LoggerBlocked = counterVec(subsystemLogging, "logger_blocked_total", "The total number of records which blocked while the queue was full.", "name")
+
+	m.RemoteClusterSent = counterVec(subsystemRemoteCluster, "msg_sent_total", "Total number of messages sent to the remote cluster.", "remote_id")
+	m.RemoteClusterRecv = counterVec(subsystemRemoteCluster, "msg_received_total", "Total number of messages received from the remote cluster.", "remote_id")
+	m.RemoteClusterErrors = counterVec(subsystemRemoteCluster, "msg_errors_total", "Total number of message errors with the remote cluster.", "remote_id", "timeout")
+	m.RemoteClusterPing = histogramVec(subsystemRemoteCluster, "ping_time", "The ping roundtrip times to the remote cluster, in seconds.", "remote_id")
+	m.RemoteClusterSkew = gaugeVec(subsystemRemoteCluster, "clock_skew", "An approximated clock skew between the remote cluster and this one, in milliseconds.", "remote_id")
+	m.RemoteClusterChanges = counterVec(subsystemRemoteCluster, "conn_state_change_total", "Total number of connection state changes with the remote cluster.", "remote_id", "online")
+
+	m.JobsActive = gaugeVec(subsystemJobs, "active", "Number of active jobs.", "type")
+
+	return m
+}
+
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{Namespace: namespace}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystemSystem,
+			Name:        "server_start_time",
+			Help:        "The time the server started, in milliseconds.",
+			ConstLabels: prometheus.Labels{"version": model.CurrentVersion},
+		}, startTime()),
+
+		m.PostCreate, m.WebhookPost, m.PostSentEmail, m.PostSentPush, m.PostBroadcast, m.PostFileAttachment,
+		m.HTTPRequests, m.HTTPErrors,
+		m.ClusterRequests, m.ClusterRequestDuration, m.ClusterEvents,
+		m.Logins, m.LoginFails,
+		m.EtagHits, m.EtagMisses,
+		m.MemCacheHits, m.MemCacheMisses, m.MemCacheInvalidations,
+		m.WebsocketEvents, m.WebsocketBroadcasts, m.WebsocketBroadcastBufferSize, m.WebsocketBroadcastUsersRegistered, m.WebsocketReconnects,
+		m.PostsSearches, m.PostsSearchDuration, m.FilesSearches, m.FilesSearchDuration,
+		m.PostsIndexed, m.FilesIndexed, m.UsersIndexed, m.ChannelsIndexed,
+		m.StoreMethodDuration, m.ReplicaLagAbsolute, m.ReplicaLagTime,
+		m.APIEndpointDuration, m.EnabledUsers,
+		m.PluginHookDuration, m.PluginMultiHookIter, m.PluginMultiHook, m.PluginAPIDuration,
+		m.LoggerQueueSize, m.LoggerLogged, m.LoggerErrors, m.LoggerDropped, m.LoggerBlocked,
+		m.RemoteClusterSent, m.RemoteClusterRecv, m.RemoteClusterErrors, m.RemoteClusterPing, m.RemoteClusterSkew, m.RemoteClusterChanges,
+		m.JobsActive,
+	}
+}
+
+func startTime() func() float64 {
+	start := float64(model.GetMillis())
+	return func() float64 { return start }
+}
+
+// Register adds the /metrics route to the metrics router, which is rebuilt each time
+// the metrics server is started. It does nothing unless the license allows metrics.
+func (m *Metrics) Register() {
+	if license := m.server.License(); license == nil || !*license.Features.Metrics {
+		mlog.Debug("Not serving metrics, as the license does not allow it")
+		return
+	}
+
+	m.registerOnce.Do(func() {
+		m.registry.MustRegister(m.collectors()...)
+	})
+	m.server.HandleMetrics("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+func (m *Metrics) IncrementPostCreate()    { m.PostCreate.Inc() }
+func (m *Metrics) IncrementWebhookPost()   { m.WebhookPost.Inc() }
+func (m *Metrics) IncrementPostSentEmail() { m.PostSentEmail.Inc() }
+func (m *Metrics) IncrementPostSentPush()  { m.PostSentPush.Inc() }
+func (m *Metrics) IncrementPostBroadcast() { m.PostBroadcast.Inc() }
+
+func (m *Metrics) IncrementPostFileAttachment(count int) {
+	m.PostFileAttachment.Add(float64(count))
+}
+
+func (m *Metrics) IncrementHTTPRequest() { m.HTTPRequests.Inc() }
+func (m *Metrics) IncrementHTTPError()   { m.HTTPErrors.Inc() }
+
+func (m *Metrics) IncrementClusterRequest() { m.ClusterRequests.Inc() }
+
+func (m *Metrics) ObserveClusterRequestDuration(elapsed float64) {
+	m.ClusterRequestDuration.Observe(elapsed)
+}
+
+func (m *Metrics) IncrementClusterEventType(eventType model.ClusterEvent) {
+	m.ClusterEvents.WithLabelValues(string(eventType)).Inc()
+}
+
+func (m *Metrics) IncrementLogin()     { m.Logins.Inc() }
+func (m *Metrics) IncrementLoginFail() { m.LoginFails.Inc() }
+
+func (m *Metrics) IncrementEtagHitCounter(route string) {
+	m.EtagHits.WithLabelValues(route).Inc()
+}
+
+func (m *Metrics) IncrementEtagMissCounter(route string) {
+	m.EtagMisses.WithLabelValues(route).Inc()
+}
+
+func (m *Metrics) IncrementMemCacheHitCounter(cacheName string) {
+	m.MemCacheHits.WithLabelValues(cacheName).Inc()
+}
+
+func (m *Metrics) IncrementMemCacheMissCounter(cacheName string) {
+	m.MemCacheMisses.WithLabelValues(cacheName).Inc()
+}
+
+func (m *Metrics) IncrementMemCacheInvalidationCounter(cacheName string) {
+	m.MemCacheInvalidations.WithLabelValues(cacheName).Inc()
+}
+
+func (m *Metrics) IncrementMemCacheMissCounterSession() {
+	m.IncrementMemCacheMissCounter(sessionCacheName)
+}
+
+func (m *Metrics) IncrementMemCacheHitCounterSession() {
+	m.IncrementMemCacheHitCounter(sessionCacheName)
+}
+
+func (m *Metrics) IncrementMemCacheInvalidationCounterSession() {
+	m.IncrementMemCacheInvalidationCounter(sessionCacheName)
+}
+
+func (m *Metrics) AddMemCacheHitCounter(cacheName string, amount float64) {
+	m.MemCacheHits.WithLabelValues(cacheName).Add(amount)
+}
+
+func (m *Metrics) AddMemCacheMissCounter(cacheName string, amount float64) {
+	m.MemCacheMisses.WithLabelValues(cacheName).Add(amount)
+}
+
+func (m *Metrics) IncrementWebsocketEvent(eventType string) {
+	m.WebsocketEvents.WithLabelValues(eventType).Inc()
+}
+
+func (m *Metrics) IncrementWebSocketBroadcast(eventType string) {
+	m.WebsocketBroadcasts.WithLabelValues(eventType).Inc()
+}
+
+func (m *Metrics) IncrementWebSocketBroadcastBufferSize(hub string, amount float64) {
+	m.WebsocketBroadcastBufferSize.WithLabelValues(hub).Add(amount)
+}
+
+func (m *Metrics) DecrementWebSocketBroadcastBufferSize(hub string, amount float64) {
+	m.WebsocketBroadcastBufferSize.WithLabelValues(hub).Sub(amount)
+}
+
+func (m *Metrics) IncrementWebSocketBroadcastUsersRegistered(hub string, amount float64) {
+	m.WebsocketBroadcastUsersRegistered.WithLabelValues(hub).Add(amount)
+}
+
+func (m *Metrics) DecrementWebSocketBroadcastUsersRegistered(hub string, amount float64) {
+	m.WebsocketBroadcastUsersRegistered.WithLabelValues(hub).Sub(amount)
+}
+
+func (m *Metrics) IncrementWebsocketReconnectEvent(eventType string) {
+	m.WebsocketReconnects.WithLabelValues(eventType).Inc()
+}
+
+func (m *Metrics) IncrementPostsSearchCounter() { m.PostsSearches.Inc() }
+
+func (m *Metrics) ObservePostsSearchDuration(elapsed float64) {
+	m.PostsSearchDuration.Observe(elapsed)
+}
+
+func (m *Metrics) IncrementFilesSearchCounter() { m.FilesSearches.Inc() }
+
+func (m *Metrics) ObserveFilesSearchDuration(elapsed float64) {
+	m.FilesSearchDuration.Observe(elapsed)
+}
+
+func (m *Metrics) ObserveStoreMethodDuration(method, success string, elapsed float64) {
+	m.StoreMethodDuration.WithLabelValues(method, success).Observe(elapsed)
+}
+
+func (m *Metrics) ObserveAPIEndpointDuration(endpoint, method, statusCode string, elapsed float64) {
+	m.APIEndpointDuration.WithLabelValues(endpoint, method, statusCode).Observe(elapsed)
+}
+
+func (m *Metrics) IncrementPostIndexCounter()    { m.PostsIndexed.Inc() }
+func (m *Metrics) IncrementFileIndexCounter()    { m.FilesIndexed.Inc() }
+func (m *Metrics) IncrementUserIndexCounter()    { m.UsersIndexed.Inc() }
+func (m *Metrics) IncrementChannelIndexCounter() { m.ChannelsIndexed.Inc() }
+
+func (m *Metrics) ObservePluginHookDuration(pluginID, hookName string, success bool, elapsed float64) {
+	m.PluginHookDuration.WithLabelValues(pluginID, hookName, strconv.FormatBool(success)).Observe(elapsed)
+}
+
+func (m *Metrics) ObservePluginMultiHookIterationDuration(pluginID string, elapsed float64) {
+	m.PluginMultiHookIter.WithLabelValues(pluginID).Observe(elapsed)
+}
+
+func (m *Metrics) ObservePluginMultiHookDuration(elapsed float64) {
+	m.PluginMultiHook.Observe(elapsed)
+}
+
+func (m *Metrics) ObservePluginAPIDuration(pluginID, apiName string, success bool, elapsed float64) {
+	m.PluginAPIDuration.WithLabelValues(pluginID, apiName, strconv.FormatBool(success)).Observe(elapsed)
+}
+
+func (m *Metrics) ObserveEnabledUsers(users int64) {
+	m.EnabledUsers.Set(float64(users))
+}
+
+func (m *Metrics) GetLoggerMetricsCollector() mlog.MetricsCollector {
+	return &loggerCollector{metrics: m}
+}
+
+func (m *Metrics) IncrementRemoteClusterMsgSentCounter(remoteID string) {
+	m.RemoteClusterSent.WithLabelValues(remoteID).Inc()
+}
+
+func (m *Metrics) IncrementRemoteClusterMsgReceivedCounter(remoteID string) {
+	m.RemoteClusterRecv.WithLabelValues(remoteID).Inc()
+}
+
+func (m *Metrics) IncrementRemoteClusterMsgErrorsCounter(remoteID string, timeout bool) {
+	m.RemoteClusterErrors.WithLabelValues(remoteID, strconv.FormatBool(timeout)).Inc()
+}
+
+func (m *Metrics) ObserveRemoteClusterPingDuration(remoteID string, elapsed float64) {
+	m.RemoteClusterPing.WithLabelValues(remoteID).Observe(elapsed)
+}
+
+func (m *Metrics) ObserveRemoteClusterClockSkew(remoteID string, skew float64) {
+	m.RemoteClusterSkew.WithLabelValues(remoteID).Set(skew)
+}
+
+func (m *Metrics) IncrementRemoteClusterConnStateChangeCounter(remoteID string, online bool) {
+	m.RemoteClusterChanges.WithLabelValues(remoteID, strconv.FormatBool(online)).Inc()
+}
+
+func (m *Metrics) IncrementJobActive(jobType string) {
+	m.JobsActive.WithLabelValues(jobType).Inc()
+}
+
+func (m *Metrics) DecrementJobActive(jobType string) {
+	m.JobsActive.WithLabelValues(jobType).Dec()
+}
+
+func (m *Metrics) SetReplicaLagAbsolute(node string, value float64) {
+	m.ReplicaLagAbsolute.WithLabelValues(node).Set(value)
+}
+
+func (m *Metrics) SetReplicaLagTime(node string, value float64) {
+	m.ReplicaLagTime.WithLabelValues(node).Set(value)
+}
+
+// loggerCollector hands out the metrics updated by the log targets.
+type loggerCollector struct {
+	metrics *Metrics
+}
+
+func (c *loggerCollector) QueueSizeGauge(target string) (logr.Gauge, error) {
+	return c.metrics.LoggerQueueSize.GetMetricWithLabelValues(target)
+}
+
+func (c *loggerCollector) LoggedCounter(target string) (logr.Counter, error) {
+	return c.metrics.LoggerLogged.GetMetricWithLabelValues(target)
+}
+
+func (c *loggerCollector) ErrorCounter(target string) (logr.Counter, error) {
+	return c.metrics.LoggerErrors.GetMetricWithLabelValues(target)
+}
+
+func (c *loggerCollector) DroppedCounter(target string) (logr.Counter, error) {
+	return c.metrics.LoggerDropped.GetMetricWithLabelValues(target)
+}
+
+func (c *loggerCollector) BlockedCounter(target string) (logr.Counter, error) {
+	return c.metrics.LoggerBlocked.GetMetricWithLabelValues(target)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// mockServer records the handlers added to the metrics router.
+type mockServer struct {
+	license  *model.License
+	handlers map[string]http.Handler
+}
+
+func (ms *mockServer) License() *model.License { return ms.license }
+
+func (ms *mockServer) HandleMetrics(route string, h http.Handler) {
+	ms.handlers[route] = h
+}
+
+func scrape(t *testing.T, handler http.Handler) string {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	body, err := ioutil.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestRegister(t *testing.T) {
+	server := &mockServer{handlers: map[string]http.Handler{}}
+	m := New(server)
+
+	m.Register()
+	assert.Empty(t, server.handlers, "served without a license")
+
+	server.license = model.NewTestLicense("metrics")
+	m.Register()
+	require.Contains(t, server.handlers, "/metrics")
+
+	// The router is rebuilt whenever the metrics server restarts.
+	server.handlers = map[string]http.Handler{}
+	m.Register()
+	require.Contains(t, server.handlers, "/metrics")
+
+	body := scrape(t, server.handlers["/metrics"])
+	assert.Contains(t, body, "matterfoss_system_server_start_time")
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestMetrics(t *testing.T) {
+	server := &mockServer{license: model.NewTestLicense("metrics"), handlers: map[string]http.Handler{}}
+	m := New(server)
+	m.Register()
+
+	m.IncrementPostCreate()
+	m.IncrementPostFileAttachment(3)
+	m.IncrementHTTPRequest()
+	m.IncrementClusterEventType(model.ClusterEventPublish)
+	m.IncrementMemCacheHitCounterSession()
+	m.AddMemCacheMissCounter("User", 2)
+	m.IncrementWebSocketBroadcastBufferSize("0", 5)
+	m.DecrementWebSocketBroadcastBufferSize("0", 2)
+	m.ObserveStoreMethodDuration("PostStore.Get", "true", 0.1)
+	m.ObservePluginHookDuration("com.example.plugin", "MessageWillBePosted", false, 0.2)
+	m.IncrementJobActive(model.JobTypeMessageExport)
+	m.SetReplicaLagTime("replica-1", 4)
+
+	counter, err := m.GetLoggerMetricsCollector().LoggedCounter("console")
+	require.NoError(t, err)
+	counter.Add(7)
+
+	body := scrape(t, server.handlers["/metrics"])
+	for _, line := range []string{
+		"matterfoss_post_total 1",
+		"matterfoss_post_file_attachments_total 3",
+		"matterfoss_http_requests_total 1",
+		`matterfoss_cluster_cluster_event_type_totals{name="publish"} 1`,
+		`matterfoss_cache_mem_hit_total{name="Session"} 1`,
+		`matterfoss_cache_mem_miss_total{name="User"} 2`,
+		`matterfoss_websocket_broadcast_buffer_size{hub="0"} 3`,
+		`matterfoss_db_store_time_count{method="PostStore.Get",success="true"} 1`,
+		`matterfoss_plugin_hook_time_count{hook_name="MessageWillBePosted",plugin_id="com.example.plugin",success="false"} 1`,
+		`matterfoss_jobs_active{type="message_export"} 1`,
+		`matterfoss_db_replica_lag_time{node="replica-1"} 4`,
+		`matterfoss_logging_logger_logged_total{name="console"} 7`,
+	} {
+		assert.Contains(t, body, line)
+	}
+}
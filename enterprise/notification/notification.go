@@ -0,0 +1,116 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notification
+
+import (
+	"net/http"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// AppIface is the subset of the app layer used by the notification implementation.
+type AppIface interface {
+	Config() *model.Config
+	License() *model.License
+	GetSinglePost(postID string) (*model.Post, *model.AppError)
+	GetChannel(channelID string) (*model.Channel, *model.AppError)
+	GetUser(userID string) (*model.User, *model.AppError)
+	GetUsersInChannelMap(options *model.UserGetOptions, asAdmin bool) (map[string]*model.User, *model.AppError)
+	HasPermissionToChannel(askingUserId string, channelID string, permission *model.Permission) bool
+	GetNotificationNameFormat(user *model.User) string
+	BuildPushNotificationMessage(contentsConfig string, post *model.Post, user *model.User, channel *model.Channel, channelName string, senderName string,
+		explicitMention bool, channelWideMention bool, replyToThreadType string) (*model.PushNotification, *model.AppError)
+}
+
+func init() {
+	app.RegisterNotificationInterface(func(a *app.App) einterfaces.NotificationInterface {
+		return New(a)
+	})
+}
+
+// Notification implements einterfaces.NotificationInterface. With ID-loaded push
+// notifications the push proxy only carries the ids of the post and channel, and
+// the mobile app fetches the contents of the notification from the server once
+// it acknowledges receiving it.
+type Notification struct {
+	app AppIface
+}
+
+func New(app AppIface) *Notification {
+	return &Notification{app: app}
+}
+
+func (n *Notification) CheckLicense() *model.AppError {
+	license := n.app.License()
+	if license == nil || !*license.Features.IDLoadedPushNotifications {
+		return model.NewAppError("CheckLicense", "ent.id_loaded.license_disable.app_error", nil, "", http.StatusNotImplemented)
+	}
+	return nil
+}
+
+// GetNotificationMessage returns the full contents of the notification for the
+// post acknowledged by the user, as long as the user can still read its channel.
+func (n *Notification) GetNotificationMessage(ack *model.PushNotificationAck, userID string) (*model.PushNotification, *model.AppError) {
+	if appErr := n.CheckLicense(); appErr != nil {
+		return nil, appErr
+	}
+
+	if !ack.IsIdLoaded || ack.NotificationType != model.PushTypeMessage || !model.IsValidId(ack.PostId) {
+		return nil, model.NewAppError("GetNotificationMessage", "ent.id_loaded.invalid_ack.app_error", nil, "ack_id="+ack.Id, http.StatusBadRequest)
+	}
+
+	post, appErr := n.app.GetSinglePost(ack.PostId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	// Checked again, as the user may have left the channel since the notification was sent.
+	if !n.app.HasPermissionToChannel(userID, post.ChannelId, model.PermissionReadChannel) {
+		return nil, model.NewAppError("GetNotificationMessage", "ent.id_loaded.no_permission.app_error", nil, "post_id="+post.Id+", user_id="+userID, http.StatusForbidden)
+	}
+
+	channel, appErr := n.app.GetChannel(post.ChannelId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	user, appErr := n.app.GetUser(userID)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	sender, appErr := n.app.GetUser(post.UserId)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	notification := &app.PostNotification{
+		Channel: channel,
+		Post:    post,
+		Sender:  sender,
+	}
+	if channel.Type == model.ChannelTypeGroup {
+		notification.ProfileMap, appErr = n.app.GetUsersInChannelMap(&model.UserGetOptions{
+			InChannelId: channel.Id,
+			PerPage:     model.ChannelGroupMaxUsers,
+		}, true)
+		if appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	nameFormat := n.app.GetNotificationNameFormat(user)
+	channelName := notification.GetChannelName(nameFormat, user.Id)
+	senderName := notification.GetSenderName(nameFormat, *n.app.Config().ServiceSettings.EnablePostUsernameOverride)
+
+	msg, appErr := n.app.BuildPushNotificationMessage(model.FullNotification, post, user, channel, channelName, senderName, false, false, "")
+	if appErr != nil {
+		return nil, appErr
+	}
+	msg.AckId = ack.Id
+
+	return msg, nil
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package notification
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// mockApp holds the posts, channels and users of the tests, along with the
+// channels each user can read.
+type mockApp struct {
+	config   *model.Config
+	license  *model.License
+	posts    map[string]*model.Post
+	channels map[string]*model.Channel
+	users    map[string]*model.User
+	members  map[string][]string
+
+	contentsConfig string
+}
+
+func newMockApp() *mockApp {
+	config := &model.Config{}
+	config.SetDefaults()
+	return &mockApp{
+		config:   config,
+		license:  model.NewTestLicense("id_loaded"),
+		posts:    make(map[string]*model.Post),
+		channels: make(map[string]*model.Channel),
+		users:    make(map[string]*model.User),
+		members:  make(map[string][]string),
+	}
+}
+
+func (ma *mockApp) Config() *model.Config   { return ma.config }
+func (ma *mockApp) License() *model.License { return ma.license }
+
+func (ma *mockApp) GetSinglePost(postID string) (*model.Post, *model.AppError) {
+	if post, ok := ma.posts[postID]; ok {
+		return post, nil
+	}
+	return nil, model.NewAppError("GetSinglePost", "app.post.get.app_error", nil, "", http.StatusNotFound)
+}
+
+func (ma *mockApp) GetChannel(channelID string) (*model.Channel, *model.AppError) {
+	if channel, ok := ma.channels[channelID]; ok {
+		return channel, nil
+	}
+	return nil, model.NewAppError("GetChannel", "app.channel.get.existing.app_error", nil, "", http.StatusNotFound)
+}
+
+func (ma *mockApp) GetUser(userID string) (*model.User, *model.AppError) {
+	if user, ok := ma.users[userID]; ok {
+		return user, nil
+	}
+	return nil, model.NewAppError("GetUser", "app.user.missing_account.const", nil, "", http.StatusNotFound)
+}
+
+func (ma *mockApp) GetUsersInChannelMap(options *model.UserGetOptions, asAdmin bool) (map[string]*model.User, *model.AppError) {
+	users := map[string]*model.User{}
+	for _, userID := range ma.members[options.InChannelId] {
+		users[userID] = ma.users[userID]
+	}
+	return users, nil
+}
+
+func (ma *mockApp) HasPermissionToChannel(askingUserId string, channelID string, permission *model.Permission) bool {
+	for _, userID := range ma.members[channelID] {
+		if userID == askingUserId {
+			return permission == model.PermissionReadChannel
+		}
+	}
+	return false
+}
+
+func (ma *mockApp) GetNotificationNameFormat(user *model.User) string {
+	return model.ShowUsername
+}
+
+func (ma *mockApp) BuildPushNotificationMessage(contentsConfig string, post *model.Post, user *model.User, channel *model.Channel, channelName string, senderName string,
+	explicitMention bool, channelWideMention bool, replyToThreadType string) (*model.PushNotification, *model.AppError) {
+	ma.contentsConfig = contentsConfig
+	return &model.PushNotification{
+		Type:        model.PushTypeMessage,
+		PostId:      post.Id,
+		ChannelId:   channel.Id,
+		ChannelName: channelName,
+		SenderId:    post.UserId,
+		SenderName:  senderName,
+		Message:     senderName + ": " + post.Message,
+	}, nil
+}
+
+func (ma *mockApp) addUser(username string) *model.User {
+	user := &model.User{Id: model.NewId(), Username: username}
+	ma.users[user.Id] = user
+	return user
+}
+
+func (ma *mockApp) addChannel(channelType model.ChannelType, members ...*model.User) *model.Channel {
+	channel := &model.Channel{Id: model.NewId(), Type: channelType, DisplayName: "Town Square"}
+	ma.channels[channel.Id] = channel
+	for _, member := range members {
+		ma.members[channel.Id] = append(ma.members[channel.Id], member.Id)
+	}
+	return channel
+}
+
+func (ma *mockApp) addPost(channel *model.Channel, sender *model.User, message string) *model.Post {
+	post := &model.Post{Id: model.NewId(), ChannelId: channel.Id, UserId: sender.Id, Message: message}
+	ma.posts[post.Id] = post
+	return post
+}
+
+func newAck(post *model.Post) *model.PushNotificationAck {
+	return &model.PushNotificationAck{
+		Id:               model.NewId(),
+		ClientReceivedAt: model.GetMillis(),
+		ClientPlatform:   "ios",
+		NotificationType: model.PushTypeMessage,
+		PostId:           post.Id,
+		IsIdLoaded:       true,
+	}
+}
+
+func TestCheckLicense(t *testing.T) {
+	ma := newMockApp()
+	n := New(ma)
+	require.Nil(t, n.CheckLicense())
+
+	ma.license = model.NewTestLicense()
+	ma.license.Features.IDLoadedPushNotifications = model.NewBool(false)
+	appErr := n.CheckLicense()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "ent.id_loaded.license_disable.app_error", appErr.Id)
+
+	ma.license = nil
+	require.NotNil(t, n.CheckLicense())
+}
+
+func TestGetNotificationMessage(t *testing.T) {
+	ma := newMockApp()
+	n := New(ma)
+
+	alice := ma.addUser("alice")
+	bob := ma.addUser("bob")
+	carol := ma.addUser("carol")
+
+	t.Run("channel", func(t *testing.T) {
+		channel := ma.addChannel(model.ChannelTypeOpen, alice, bob)
+		post := ma.addPost(channel, alice, "hello")
+		ack := newAck(post)
+
+		msg, appErr := n.GetNotificationMessage(ack, bob.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, model.FullNotification, ma.contentsConfig)
+		assert.Equal(t, ack.Id, msg.AckId)
+		assert.Equal(t, post.Id, msg.PostId)
+		assert.Equal(t, "Town Square", msg.ChannelName)
+		assert.Equal(t, "@alice", msg.SenderName)
+		assert.Equal(t, "@alice: hello", msg.Message)
+	})
+
+	t.Run("direct channel", func(t *testing.T) {
+		channel := ma.addChannel(model.ChannelTypeDirect, alice, bob)
+		post := ma.addPost(channel, alice, "hi bob")
+
+		msg, appErr := n.GetNotificationMessage(newAck(post), bob.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, "@alice", msg.ChannelName)
+	})
+
+	t.Run("group channel", func(t *testing.T) {
+		channel := ma.addChannel(model.ChannelTypeGroup, alice, bob, carol)
+		post := ma.addPost(channel, alice, "hi all")
+
+		msg, appErr := n.GetNotificationMessage(newAck(post), bob.Id)
+		require.Nil(t, appErr)
+		assert.Equal(t, "alice, carol", msg.ChannelName)
+	})
+
+	t.Run("no longer a member", func(t *testing.T) {
+		channel := ma.addChannel(model.ChannelTypePrivate, alice)
+		post := ma.addPost(channel, alice, "secret")
+
+		msg, appErr := n.GetNotificationMessage(newAck(post), bob.Id)
+		require.NotNil(t, appErr)
+		assert.Nil(t, msg)
+		assert.Equal(t, "ent.id_loaded.no_permission.app_error", appErr.Id)
+		assert.Equal(t, http.StatusForbidden, appErr.StatusCode)
+	})
+
+	t.Run("deleted post", func(t *testing.T) {
+		channel := ma.addChannel(model.ChannelTypeOpen, alice, bob)
+		post := ma.addPost(channel, alice, "gone")
+		delete(ma.posts, post.Id)
+
+		_, appErr := n.GetNotificationMessage(newAck(post), bob.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.StatusCode)
+	})
+
+	t.Run("invalid ack", func(t *testing.T) {
+		channel := ma.addChannel(model.ChannelTypeOpen, alice, bob)
+		post := ma.addPost(channel, alice, "hello")
+
+		for name, modify := range map[string]func(ack *model.PushNotificationAck){
+			"not id loaded": func(ack *model.PushNotificationAck) { ack.IsIdLoaded = false },
+			"not a message": func(ack *model.PushNotificationAck) { ack.NotificationType = model.PushTypeClear },
+			"no post":       func(ack *model.PushNotificationAck) { ack.PostId = "" },
+		} {
+			t.Run(name, func(t *testing.T) {
+				ack := newAck(post)
+				modify(ack)
+
+				_, appErr := n.GetNotificationMessage(ack, bob.Id)
+				require.NotNil(t, appErr)
+				assert.Equal(t, "ent.id_loaded.invalid_ack.app_error", appErr.Id)
+			})
+		}
+	})
+
+	t.Run("not licensed", func(t *testing.T) {
+		ma.license = nil
+		defer func() { ma.license = model.NewTestLicense("id_loaded") }()
+
+		channel := ma.addChannel(model.ChannelTypeOpen, alice, bob)
+		post := ma.addPost(channel, alice, "hello")
+
+		_, appErr := n.GetNotificationMessage(newAck(post), bob.Id)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.id_loaded.license_disable.app_error", appErr.Id)
+	})
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package saml
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/mattermost/gosaml2/types"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	protocolNamespace  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	assertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+)
+
+// keyPair is a self-signed certificate with its private key, in both PEM and parsed
+// form.
+type keyPair struct {
+	certificatePEM []byte
+	keyPEM         []byte
+	tls            tls.Certificate
+}
+
+func newKeyPair(t *testing.T, commonName string) *keyPair {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	pair := &keyPair{
+		certificatePEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:         pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	}
+	pair.tls, err = tls.X509KeyPair(pair.certificatePEM, pair.keyPEM)
+	require.NoError(t, err)
+
+	return pair
+}
+
+// testIdP issues responses the way an Identity Provider configured for the mock
+// app would.
+type testIdP struct {
+	t    *testing.T
+	keys *keyPair
+	ma   *mockApp
+}
+
+// responseOptions tweaks the response issued by the test Identity Provider.
+type responseOptions struct {
+	unsigned  bool
+	signedBy  *keyPair
+	encryptTo *keyPair
+	audience  string
+	notBefore time.Time
+}
+
+func (idp *testIdP) response(attributes map[string][]string, options responseOptions) string {
+	t := idp.t
+	settings := idp.ma.config.SamlSettings
+	now := time.Now().UTC()
+
+	if options.audience == "" {
+		options.audience = *settings.ServiceProviderIdentifier
+	}
+	if options.notBefore.IsZero() {
+		options.notBefore = now.Add(-time.Minute)
+	}
+
+	response := etree.NewElement("samlp:Response")
+	response.CreateAttr("xmlns:samlp", protocolNamespace)
+	response.CreateAttr("xmlns:saml", assertionNamespace)
+	response.CreateAttr("ID", "_response")
+	response.CreateAttr("Version", "2.0")
+	response.CreateAttr("IssueInstant", now.Format(time.RFC3339))
+	response.CreateAttr("Destination", *settings.AssertionConsumerServiceURL)
+	response.CreateElement("saml:Issuer").SetText(*settings.IdpDescriptorURL)
+	response.CreateElement("samlp:Status").CreateElement("samlp:StatusCode").CreateAttr("Value", "urn:oasis:names:tc:SAML:2.0:status:Success")
+
+	assertion := etree.NewElement("saml:Assertion")
+	assertion.CreateAttr("xmlns:saml", assertionNamespace)
+	assertion.CreateAttr("ID", "_assertion")
+	assertion.CreateAttr("Version", "2.0")
+	assertion.CreateAttr("IssueInstant", now.Format(time.RFC3339))
+	assertion.CreateElement("saml:Issuer").SetText(*settings.IdpDescriptorURL)
+
+	subject := assertion.CreateElement("saml:Subject")
+	subject.CreateElement("saml:NameID").SetText("name-id")
+	confirmation := subject.CreateElement("saml:SubjectConfirmation")
+	confirmation.CreateAttr("Method", "urn:oasis:names:tc:SAML:2.0:cm:bearer")
+	confirmationData := confirmation.CreateElement("saml:SubjectConfirmationData")
+	confirmationData.CreateAttr("Recipient", *settings.AssertionConsumerServiceURL)
+	confirmationData.CreateAttr("NotOnOrAfter", now.Add(5*time.Minute).Format(time.RFC3339))
+
+	conditions := assertion.CreateElement("saml:Conditions")
+	conditions.CreateAttr("NotBefore", options.notBefore.Format(time.RFC3339))
+	conditions.CreateAttr("NotOnOrAfter", now.Add(5*time.Minute).Format(time.RFC3339))
+	conditions.CreateElement("saml:AudienceRestriction").CreateElement("saml:Audience").SetText(options.audience)
+
+	statement := assertion.CreateElement("saml:AttributeStatement")
+	for name, values := range attributes {
+		attribute := statement.CreateElement("saml:Attribute")
+		attribute.CreateAttr("Name", name)
+		for _, value := range values {
+			attribute.CreateElement("saml:AttributeValue").SetText(value)
+		}
+	}
+
+	if options.encryptTo != nil {
+		response.AddChild(idp.encrypt(assertion, options.encryptTo))
+	} else {
+		response.AddChild(assertion)
+	}
+
+	if !options.unsigned {
+		signer := idp.keys
+		if options.signedBy != nil {
+			signer = options.signedBy
+		}
+		var err error
+		response, err = dsig.NewDefaultSigningContext(dsig.TLSCertKeyStore(signer.tls)).SignEnveloped(response)
+		require.NoError(t, err)
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(response)
+	raw, err := doc.WriteToBytes()
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// encrypt wraps assertion in an EncryptedAssertion readable by the holder of to.
+func (idp *testIdP) encrypt(assertion *etree.Element, to *keyPair) *etree.Element {
+	t := idp.t
+
+	doc := etree.NewDocument()
+	doc.SetRoot(assertion.Copy())
+	plaintext, err := doc.WriteToBytes()
+	require.NoError(t, err)
+
+	key := make([]byte, 16)
+	_, err = rand.Read(key)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+
+	certificate, err := x509.ParseCertificate(to.tls.Certificate[0])
+	require.NoError(t, err)
+	encryptedKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, certificate.PublicKey.(*rsa.PublicKey), key, nil)
+	require.NoError(t, err)
+
+	encrypted := etree.NewElement("saml:EncryptedAssertion")
+	data := encrypted.CreateElement("xenc:EncryptedData")
+	data.CreateAttr("xmlns:xenc", "http://www.w3.org/2001/04/xmlenc#")
+	data.CreateElement("xenc:EncryptionMethod").CreateAttr("Algorithm", types.MethodAES128GCM)
+	keyInfo := data.CreateElement("ds:KeyInfo")
+	keyInfo.CreateAttr("xmlns:ds", "http://www.w3.org/2000/09/xmldsig#")
+	keyElement := keyInfo.CreateElement("xenc:EncryptedKey")
+	keyElement.CreateElement("xenc:EncryptionMethod").CreateAttr("Algorithm", types.MethodRSAOAEP)
+	keyElement.CreateElement("xenc:CipherData").CreateElement("xenc:CipherValue").SetText(base64.StdEncoding.EncodeToString(encryptedKey))
+	data.CreateElement("xenc:CipherData").CreateElement("xenc:CipherValue").SetText(base64.StdEncoding.EncodeToString(ciphertext))
+
+	return encrypted
+}
@@ -0,0 +1,387 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/beevik/etree"
+	saml2 "github.com/mattermost/gosaml2"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// DoLogin verifies the response posted back by the Identity Provider and returns the
+// user it authenticates, creating the user on first login.
+func (s *MatterfossSaml) DoLogin(c *request.Context, encodedXML string, relayState map[string]string) (*model.User, *model.AppError) {
+	sp, appErr := s.serviceProvider("DoLogin")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	info, appErr := s.retrieveAssertion(sp, encodedXML)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	cfg := s.app.Config()
+	settings := cfg.SamlSettings
+
+	samlUser, appErr := assertionToUser(&settings, info.Values)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	isAdmin := *settings.EnableAdminAttribute && matchesAttribute(info.Values, *settings.AdminAttribute)
+	isGuest := *cfg.GuestAccountsSettings.Enable && matchesAttribute(info.Values, *settings.GuestAttribute)
+
+	var user *model.User
+	if relayState["action"] == model.OAuthActionEmailToSSO {
+		user, appErr = s.switchToSaml(relayState["email"], samlUser)
+	} else {
+		user, appErr = s.app.GetUserByAuth(samlUser.AuthData, model.UserAuthServiceSaml)
+		if appErr != nil && appErr.Id == app.MissingAuthAccountError {
+			return s.createUser(c, samlUser, isAdmin, isGuest)
+		}
+	}
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if updateUserAttributes(&settings, user, samlUser) {
+		if user, appErr = s.app.UpdateUser(user, false); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return s.applyRoles(c, user, isAdmin, isGuest)
+}
+
+// retrieveAssertion decodes, decrypts and verifies the response and returns the
+// assertion it carries.
+func (s *MatterfossSaml) retrieveAssertion(sp *saml2.SAMLServiceProvider, encodedXML string) (*saml2.AssertionInfo, *model.AppError) {
+	if encodedXML == "" {
+		return nil, model.NewAppError("DoLogin", "ent.saml.do_login.empty_response.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encodedXML)
+	if err != nil {
+		return nil, model.NewAppError("DoLogin", "ent.saml.do_login.parse.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+	doc, err := parseResponse(raw)
+	if err != nil {
+		return nil, model.NewAppError("DoLogin", "ent.saml.do_login.parse.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	encrypted := len(doc.Root().FindElements("./"+saml2.EncryptedAssertionTag)) > 0
+	if *s.app.Config().SamlSettings.Encrypt && !encrypted {
+		return nil, model.NewAppError("DoLogin", "ent.saml.configure.not_encrypted_response.app_error", nil, "", http.StatusBadRequest)
+	}
+	if encrypted && sp.SPKeyStore == nil {
+		return nil, model.NewAppError("DoLogin", "ent.saml.configure.encryption_not_enabled.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	info, err := sp.RetrieveAssertionInfo(encodedXML)
+	if err != nil {
+		return nil, assertionError(err)
+	}
+
+	if info.WarningInfo.InvalidTime {
+		return nil, model.NewAppError("DoLogin", "ent.saml.do_login.invalid_time.app_error", nil, "", http.StatusBadRequest)
+	}
+	if info.WarningInfo.NotInAudience {
+		return nil, model.NewAppError("DoLogin", "ent.saml.do_login.validate.app_error", nil, "assertion is not intended for this service provider", http.StatusBadRequest)
+	}
+
+	return info, nil
+}
+
+// parseResponse parses a response, which some Identity Providers deflate.
+func parseResponse(raw []byte) (*etree.Document, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(raw); err != nil {
+		inflated, inflateErr := ioutil.ReadAll(flate.NewReader(bytes.NewReader(raw)))
+		if inflateErr != nil {
+			return nil, err
+		}
+		if err = doc.ReadFromBytes(inflated); err != nil {
+			return nil, err
+		}
+	}
+
+	if doc.Root() == nil {
+		return nil, errors.New("empty response")
+	}
+	return doc, nil
+}
+
+// assertionError maps an error returned while retrieving an assertion to the error
+// shown to the user.
+func assertionError(err error) *model.AppError {
+	verification, ok := err.(saml2.ErrVerification)
+	if !ok {
+		return model.NewAppError("DoLogin", "ent.saml.do_login.validate.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	cause := strings.ToLower(verification.Cause.Error())
+	switch {
+	case strings.Contains(cause, "decrypt"):
+		return model.NewAppError("DoLogin", "ent.saml.do_login.decrypt.app_error", nil, err.Error(), http.StatusBadRequest)
+	case strings.Contains(cause, "sign") || strings.Contains(cause, "certificate"):
+		return model.NewAppError("DoLogin", "ent.saml.do_login.invalid_signature.app_error", nil, err.Error(), http.StatusBadRequest)
+	}
+
+	return model.NewAppError("DoLogin", "ent.saml.do_login.validate.app_error", nil, err.Error(), http.StatusBadRequest)
+}
+
+// attributeValue returns the first value of the named attribute, which may be given
+// either by name or by friendly name.
+func attributeValue(values saml2.Values, name string) string {
+	if name == "" {
+		return ""
+	}
+	if value := values.Get(name); value != "" {
+		return strings.TrimSpace(value)
+	}
+	for _, attribute := range values {
+		if attribute.FriendlyName == name && len(attribute.Values) > 0 {
+			return strings.TrimSpace(attribute.Values[0].Value)
+		}
+	}
+	return ""
+}
+
+// matchesAttribute reports whether the assertion satisfies a condition of the form
+// "attribute=value", as used by the guest and admin attribute settings.
+func matchesAttribute(values saml2.Values, condition string) bool {
+	parts := strings.SplitN(condition, "=", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	name, expected := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+	for _, attribute := range values {
+		if attribute.Name != name && attribute.FriendlyName != name {
+			continue
+		}
+		for _, value := range attribute.Values {
+			if strings.TrimSpace(value.Value) == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// assertionToUser maps the assertion attributes to a user, the way it would be saved
+// on first login.
+func assertionToUser(settings *model.SamlSettings, values saml2.Values) (*model.User, *model.AppError) {
+	email := strings.ToLower(attributeValue(values, *settings.EmailAttribute))
+	if email == "" {
+		return nil, model.NewAppError("DoLogin", "ent.saml.attribute.app_error", nil, "missing email attribute", http.StatusBadRequest)
+	}
+
+	username := attributeValue(values, *settings.UsernameAttribute)
+	if username == "" {
+		return nil, model.NewAppError("DoLogin", "ent.saml.attribute.app_error", nil, "missing username attribute", http.StatusBadRequest)
+	}
+
+	// Without an id attribute users are identified by their email address.
+	authData := email
+	if *settings.IdAttribute != "" {
+		if authData = attributeValue(values, *settings.IdAttribute); authData == "" {
+			return nil, model.NewAppError("DoLogin", "ent.saml.attribute.app_error", nil, "missing id attribute", http.StatusBadRequest)
+		}
+	}
+
+	user := &model.User{
+		AuthService: model.UserAuthServiceSaml,
+		AuthData:    model.NewString(authData),
+		Username:    model.CleanUsername(username),
+		Email:       email,
+		FirstName:   attributeValue(values, *settings.FirstNameAttribute),
+		LastName:    attributeValue(values, *settings.LastNameAttribute),
+		Nickname:    attributeValue(values, *settings.NicknameAttribute),
+		Position:    attributeValue(values, *settings.PositionAttribute),
+	}
+	if locale := attributeValue(values, *settings.LocaleAttribute); locale != "" && model.IsValidLocale(locale) {
+		user.Locale = locale
+	}
+	user.EmailVerified = true
+
+	return user, nil
+}
+
+// updateUserAttributes copies the Identity Provider controlled fields of from onto to
+// and reports whether anything changed.
+func updateUserAttributes(settings *model.SamlSettings, to, from *model.User) bool {
+	changed := false
+	update := func(attribute string, dest *string, value string) {
+		if attribute != "" && *dest != value {
+			*dest = value
+			changed = true
+		}
+	}
+
+	update(*settings.UsernameAttribute, &to.Username, from.Username)
+	update(*settings.EmailAttribute, &to.Email, from.Email)
+	update(*settings.FirstNameAttribute, &to.FirstName, from.FirstName)
+	update(*settings.LastNameAttribute, &to.LastName, from.LastName)
+	update(*settings.NicknameAttribute, &to.Nickname, from.Nickname)
+	update(*settings.PositionAttribute, &to.Position, from.Position)
+	if from.Locale != "" {
+		update(*settings.LocaleAttribute, &to.Locale, from.Locale)
+	}
+
+	return changed
+}
+
+// switchToSaml moves the account with the given email address, which signed in with
+// email and password until now, to SAML.
+func (s *MatterfossSaml) switchToSaml(email string, samlUser *model.User) (*model.User, *model.AppError) {
+	user, appErr := s.app.GetUserByEmail(email)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if !strings.EqualFold(user.Email, samlUser.Email) {
+		return nil, model.NewAppError("DoLogin", "ent.saml.do_login.email_mismatch.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if existing, appErr := s.app.GetUserByAuth(samlUser.AuthData, model.UserAuthServiceSaml); appErr == nil && existing.Id != user.Id {
+		return nil, model.NewAppError("DoLogin", "ent.saml.save_user.email_exists.saml_app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if _, appErr = s.app.UpdateUserAuth(user.Id, &model.UserAuth{
+		AuthService: model.UserAuthServiceSaml,
+		AuthData:    samlUser.AuthData,
+	}); appErr != nil {
+		return nil, appErr
+	}
+
+	return s.app.GetUser(user.Id)
+}
+
+// createUser saves the user signing in for the first time and, when configured,
+// synchronizes them with AD/LDAP.
+func (s *MatterfossSaml) createUser(c *request.Context, samlUser *model.User, isAdmin, isGuest bool) (*model.User, *model.AppError) {
+	if existing, appErr := s.app.GetUserByEmail(samlUser.Email); appErr == nil {
+		if existing.AuthService != model.UserAuthServiceSaml {
+			return nil, model.NewAppError("DoLogin", "ent.saml.save_user.email_exists.saml_app_error", nil, "", http.StatusBadRequest)
+		}
+
+		// The id attribute of an existing SAML user changed, adopt the new value.
+		if _, appErr = s.app.UpdateUserAuth(existing.Id, &model.UserAuth{
+			AuthService: model.UserAuthServiceSaml,
+			AuthData:    samlUser.AuthData,
+		}); appErr != nil {
+			return nil, appErr
+		}
+		existing.AuthData = samlUser.AuthData
+
+		settings := s.app.Config().SamlSettings
+		if updateUserAttributes(&settings, existing, samlUser) {
+			if existing, appErr = s.app.UpdateUser(existing, false); appErr != nil {
+				return nil, appErr
+			}
+		}
+		return s.applyRoles(c, existing, isAdmin, isGuest)
+	}
+
+	var user *model.User
+	var appErr *model.AppError
+	if isGuest {
+		user, appErr = s.app.CreateGuest(c, samlUser)
+	} else {
+		user, appErr = s.app.CreateUser(c, samlUser)
+	}
+	if appErr != nil {
+		if appErr.Id == "app.user.save.username_exists.app_error" {
+			return nil, model.NewAppError("DoLogin", "ent.saml.save_user.username_exists.saml_app_error", nil, "", http.StatusBadRequest)
+		}
+		return nil, appErr
+	}
+
+	if ldap := s.app.Ldap(); ldap != nil && *s.app.Config().SamlSettings.EnableSyncWithLdap {
+		if appErr = ldap.FirstLoginSync(c, user, model.UserAuthServiceSaml, *user.AuthData, user.Email); appErr != nil {
+			s.app.Log().Warn("Failed to synchronize the new SAML user with AD/LDAP", mlog.String("user_id", user.Id), mlog.Err(appErr))
+		} else if user, appErr = s.app.GetUser(user.Id); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	return s.applyRoles(c, user, isAdmin, isGuest)
+}
+
+// applyRoles brings the system roles of user in line with the guest and admin
+// attributes.
+func (s *MatterfossSaml) applyRoles(c *request.Context, user *model.User, isAdmin, isGuest bool) (*model.User, *model.AppError) {
+	cfg := s.app.Config()
+	settings := cfg.SamlSettings
+
+	if *cfg.GuestAccountsSettings.Enable && *settings.GuestAttribute != "" && isGuest != user.IsGuest() {
+		var appErr *model.AppError
+		if isGuest {
+			appErr = s.app.DemoteUserToGuest(user)
+		} else {
+			appErr = s.app.PromoteGuestToUser(c, user, "")
+		}
+		if appErr != nil {
+			return nil, appErr
+		}
+		if user, appErr = s.app.GetUser(user.Id); appErr != nil {
+			return nil, appErr
+		}
+	}
+
+	if !*settings.EnableAdminAttribute || *settings.AdminAttribute == "" || user.IsGuest() || isAdmin == user.IsSystemAdmin() {
+		return user, nil
+	}
+
+	var roles []string
+	for _, role := range user.GetRoles() {
+		if role != model.SystemAdminRoleId {
+			roles = append(roles, role)
+		}
+	}
+	if isAdmin {
+		roles = append(roles, model.SystemAdminRoleId)
+	}
+
+	return s.app.UpdateUserRolesWithUser(user, strings.Join(roles, " "), true)
+}
+
+// CheckProviderAttributes returns the name of the first field of patch that is
+// controlled by the Identity Provider, or an empty string if the patch may be applied.
+func (s *MatterfossSaml) CheckProviderAttributes(SS *model.SamlSettings, ouser *model.User, patch *model.UserPatch) string {
+	tryingToChange := func(attribute string, current *string, value *string) bool {
+		return attribute != "" && value != nil && *value != *current
+	}
+
+	switch {
+	case tryingToChange(*SS.FirstNameAttribute, &ouser.FirstName, patch.FirstName):
+		return "first name"
+	case tryingToChange(*SS.LastNameAttribute, &ouser.LastName, patch.LastName):
+		return "last name"
+	case tryingToChange(*SS.NicknameAttribute, &ouser.Nickname, patch.Nickname):
+		return "nickname"
+	case tryingToChange(*SS.PositionAttribute, &ouser.Position, patch.Position):
+		return "position"
+	case tryingToChange(*SS.EmailAttribute, &ouser.Email, patch.Email):
+		return "email"
+	case tryingToChange(*SS.UsernameAttribute, &ouser.Username, patch.Username):
+		return "username"
+	}
+
+	return ""
+}
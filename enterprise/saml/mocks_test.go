@@ -0,0 +1,182 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package saml
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// mockApp is an in-memory AppIface holding just enough users and configuration
+// files for the tests.
+type mockApp struct {
+	mut     sync.Mutex
+	config  *model.Config
+	license *model.License
+	logger  *mlog.Logger
+	ldap    einterfaces.LdapInterface
+	files   map[string][]byte
+	users   map[string]*model.User
+}
+
+func newMockApp(t *testing.T) *mockApp {
+	config := &model.Config{}
+	config.SetDefaults()
+	config.SamlSettings.Enable = model.NewBool(true)
+	config.SamlSettings.Verify = model.NewBool(true)
+	config.SamlSettings.Encrypt = model.NewBool(false)
+	config.SamlSettings.IdpURL = model.NewString("https://idp.example.com/sso")
+	config.SamlSettings.IdpDescriptorURL = model.NewString("https://idp.example.com")
+	config.SamlSettings.ServiceProviderIdentifier = model.NewString("https://chat.example.com")
+	config.SamlSettings.AssertionConsumerServiceURL = model.NewString("https://chat.example.com/login/sso/saml")
+	config.SamlSettings.IdpCertificateFile = model.NewString(app.SamlIdpCertificateName)
+	config.SamlSettings.IdAttribute = model.NewString("id")
+	config.SamlSettings.EmailAttribute = model.NewString("email")
+	config.SamlSettings.UsernameAttribute = model.NewString("username")
+	config.SamlSettings.FirstNameAttribute = model.NewString("firstName")
+	config.SamlSettings.LastNameAttribute = model.NewString("lastName")
+	config.SamlSettings.PositionAttribute = model.NewString("title")
+	config.SamlSettings.LocaleAttribute = model.NewString("locale")
+
+	ma := &mockApp{
+		config:  config,
+		license: model.NewTestLicense("saml"),
+		logger:  mlog.CreateConsoleTestLogger(true, mlog.LvlError),
+		files:   make(map[string][]byte),
+		users:   make(map[string]*model.User),
+	}
+	t.Cleanup(func() { ma.logger.Shutdown() })
+	return ma
+}
+
+func (ma *mockApp) Config() *model.Config { return ma.config }
+func (ma *mockApp) GetConfigFile(name string) ([]byte, error) {
+	data, ok := ma.files[name]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+func (ma *mockApp) License() *model.License         { return ma.license }
+func (ma *mockApp) Log() *mlog.Logger               { return ma.logger }
+func (ma *mockApp) Ldap() einterfaces.LdapInterface { return ma.ldap }
+
+func (ma *mockApp) save(user *model.User) *model.User {
+	copy := *user
+	ma.users[user.Id] = &copy
+	result := copy
+	return &result
+}
+
+func (ma *mockApp) GetUser(userID string) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	user, ok := ma.users[userID]
+	if !ok {
+		return nil, model.NewAppError("GetUser", app.MissingAccountError, nil, "", http.StatusNotFound)
+	}
+	copy := *user
+	return &copy, nil
+}
+
+func (ma *mockApp) GetUserByAuth(authData *string, authService string) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	for _, user := range ma.users {
+		if user.AuthService == authService && user.AuthData != nil && *user.AuthData == *authData {
+			copy := *user
+			return &copy, nil
+		}
+	}
+	return nil, model.NewAppError("GetUserByAuth", app.MissingAuthAccountError, nil, "", http.StatusInternalServerError)
+}
+
+func (ma *mockApp) GetUserByEmail(email string) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	for _, user := range ma.users {
+		if user.Email == email {
+			copy := *user
+			return &copy, nil
+		}
+	}
+	return nil, model.NewAppError("GetUserByEmail", app.MissingAccountError, nil, "", http.StatusNotFound)
+}
+
+func (ma *mockApp) createUser(user *model.User, roles string) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	for _, existing := range ma.users {
+		if existing.Username == user.Username {
+			return nil, model.NewAppError("createUserOrGuest", "app.user.save.username_exists.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+	user.Id = model.NewId()
+	user.Roles = roles
+	user.CreateAt = model.GetMillis()
+	return ma.save(user), nil
+}
+
+func (ma *mockApp) CreateUser(c *request.Context, user *model.User) (*model.User, *model.AppError) {
+	return ma.createUser(user, model.SystemUserRoleId)
+}
+
+func (ma *mockApp) CreateGuest(c *request.Context, user *model.User) (*model.User, *model.AppError) {
+	return ma.createUser(user, model.SystemGuestRoleId)
+}
+
+func (ma *mockApp) UpdateUser(user *model.User, sendNotifications bool) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	return ma.save(user), nil
+}
+
+func (ma *mockApp) UpdateUserAuth(userID string, userAuth *model.UserAuth) (*model.UserAuth, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	user := ma.users[userID]
+	user.AuthService = userAuth.AuthService
+	user.AuthData = userAuth.AuthData
+	user.Password = ""
+	return userAuth, nil
+}
+
+func (ma *mockApp) UpdateUserRolesWithUser(user *model.User, newRoles string, sendWebSocketEvent bool) (*model.User, *model.AppError) {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	user.Roles = newRoles
+	return ma.save(user), nil
+}
+
+func (ma *mockApp) PromoteGuestToUser(c *request.Context, user *model.User, requestorId string) *model.AppError {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	ma.users[user.Id].Roles = model.SystemUserRoleId
+	return nil
+}
+
+func (ma *mockApp) DemoteUserToGuest(user *model.User) *model.AppError {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	ma.users[user.Id].Roles = model.SystemGuestRoleId
+	return nil
+}
+
+func (ma *mockApp) addUser(user *model.User) *model.User {
+	ma.mut.Lock()
+	defer ma.mut.Unlock()
+	user.Id = model.NewId()
+	if user.Roles == "" {
+		user.Roles = model.SystemUserRoleId
+	}
+	return ma.save(user)
+}
@@ -0,0 +1,315 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package saml
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"net/http"
+	"sync"
+	"time"
+
+	saml2 "github.com/mattermost/gosaml2"
+	"github.com/mattermost/gosaml2/types"
+	dsig "github.com/russellhaering/goxmldsig"
+	dsigtypes "github.com/russellhaering/goxmldsig/types"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// metadataValidity is how long the published Service Provider metadata stays valid.
+const metadataValidity = 7 * 24 * time.Hour
+
+var signatureMethods = map[string]string{
+	model.SamlSettingsSignatureAlgorithmSha1:   dsig.RSASHA1SignatureMethod,
+	model.SamlSettingsSignatureAlgorithmSha256: dsig.RSASHA256SignatureMethod,
+	model.SamlSettingsSignatureAlgorithmSha512: dsig.RSASHA512SignatureMethod,
+}
+
+// AppIface is the subset of the app layer used by the SAML implementation. It allows
+// the implementation to be tested without a running server.
+type AppIface interface {
+	Config() *model.Config
+	GetConfigFile(name string) ([]byte, error)
+	License() *model.License
+	Log() *mlog.Logger
+	Ldap() einterfaces.LdapInterface
+	GetUser(userID string) (*model.User, *model.AppError)
+	GetUserByAuth(authData *string, authService string) (*model.User, *model.AppError)
+	GetUserByEmail(email string) (*model.User, *model.AppError)
+	CreateUser(c *request.Context, user *model.User) (*model.User, *model.AppError)
+	CreateGuest(c *request.Context, user *model.User) (*model.User, *model.AppError)
+	UpdateUser(user *model.User, sendNotifications bool) (*model.User, *model.AppError)
+	UpdateUserAuth(userID string, userAuth *model.UserAuth) (*model.UserAuth, *model.AppError)
+	UpdateUserRolesWithUser(user *model.User, newRoles string, sendWebSocketEvent bool) (*model.User, *model.AppError)
+	PromoteGuestToUser(c *request.Context, user *model.User, requestorId string) *model.AppError
+	DemoteUserToGuest(user *model.User) *model.AppError
+}
+
+func init() {
+	app.RegisterNewSamlInterface(func(a *app.App) einterfaces.SamlInterface {
+		return NewMatterfossSaml(a)
+	})
+}
+
+// MatterfossSaml implements einterfaces.SamlInterface as a SAML 2.0 Service Provider.
+type MatterfossSaml struct {
+	app AppIface
+
+	mut sync.RWMutex
+	sp  *saml2.SAMLServiceProvider
+}
+
+func NewMatterfossSaml(app AppIface) *MatterfossSaml {
+	return &MatterfossSaml{app: app}
+}
+
+// ConfigureSP rebuilds the Service Provider from the current configuration. It is
+// called on startup and whenever the configuration changes.
+func (s *MatterfossSaml) ConfigureSP() error {
+	settings := s.app.Config().SamlSettings
+
+	var sp *saml2.SAMLServiceProvider
+	if *settings.Enable {
+		var appErr *model.AppError
+		if sp, appErr = s.buildServiceProvider(&settings); appErr != nil {
+			s.mut.Lock()
+			s.sp = nil
+			s.mut.Unlock()
+			return appErr
+		}
+	}
+
+	s.mut.Lock()
+	s.sp = sp
+	s.mut.Unlock()
+
+	return nil
+}
+
+func (s *MatterfossSaml) buildServiceProvider(settings *model.SamlSettings) (*saml2.SAMLServiceProvider, *model.AppError) {
+	certificateStore := &dsig.MemoryX509CertificateStore{}
+	if *settings.Verify || *settings.IdpCertificateFile != "" {
+		certificates, appErr := s.idpCertificates(*settings.IdpCertificateFile)
+		if appErr != nil {
+			return nil, appErr
+		}
+		certificateStore.Roots = certificates
+	}
+
+	sp := &saml2.SAMLServiceProvider{
+		IdentityProviderSSOURL:      *settings.IdpURL,
+		IdentityProviderIssuer:      *settings.IdpDescriptorURL,
+		AssertionConsumerServiceURL: *settings.AssertionConsumerServiceURL,
+		ServiceProviderIssuer:       *settings.ServiceProviderIdentifier,
+		AudienceURI:                 *settings.ServiceProviderIdentifier,
+		IDPCertificateStore:         certificateStore,
+		SkipSignatureValidation:     !*settings.Verify,
+		SignAuthnRequests:           *settings.SignRequest,
+		SignAuthnRequestsAlgorithm:  signatureMethods[*settings.SignatureAlgorithm],
+		NameIdFormat:                saml2.NameIdFormatUnspecified,
+		ScopingIDPProviderId:        *settings.ScopingIDPProviderId,
+		ScopingIDPProviderName:      *settings.ScopingIDPName,
+	}
+
+	if *settings.CanonicalAlgorithm == model.SamlSettingsCanonicalAlgorithmC14n11 {
+		sp.SignAuthnRequestsCanonicalizer = dsig.MakeC14N11Canonicalizer()
+	} else {
+		sp.SignAuthnRequestsCanonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	}
+
+	// The key pair decrypts assertions and signs requests, so it is only required when
+	// either is enabled, but it is loaded whenever it is configured.
+	keysConfigured := *settings.PublicCertificateFile != "" && *settings.PrivateKeyFile != ""
+	if *settings.Encrypt || *settings.SignRequest || keysConfigured {
+		keyStore, appErr := s.keyStore(settings)
+		if appErr != nil {
+			return nil, appErr
+		}
+		sp.SPKeyStore = keyStore
+	}
+
+	return sp, nil
+}
+
+// idpCertificates loads the Identity Provider certificates from the configuration
+// store. The file may hold several PEM certificates to allow for key rotation.
+func (s *MatterfossSaml) idpCertificates(name string) ([]*x509.Certificate, *model.AppError) {
+	if name == "" {
+		return nil, model.NewAppError("ConfigureSP", "ent.saml.configure.load_idp_cert.app_error", nil, "no certificate configured", http.StatusInternalServerError)
+	}
+
+	data, err := s.app.GetConfigFile(name)
+	if err != nil {
+		return nil, model.NewAppError("ConfigureSP", "ent.saml.configure.load_idp_cert.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	var certificates []*x509.Certificate
+	for {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, model.NewAppError("ConfigureSP", "ent.saml.configure.load_idp_cert.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, model.NewAppError("ConfigureSP", "ent.saml.configure.load_idp_cert.app_error", nil, "no certificate found in "+name, http.StatusInternalServerError)
+	}
+
+	return certificates, nil
+}
+
+// keyStore loads the Service Provider key pair from the configuration store.
+func (s *MatterfossSaml) keyStore(settings *model.SamlSettings) (dsig.X509KeyStore, *model.AppError) {
+	if *settings.PublicCertificateFile == "" {
+		return nil, model.NewAppError("ConfigureSP", "ent.saml.configure.load_public_cert.app_error", nil, "no certificate configured", http.StatusInternalServerError)
+	}
+	certificate, err := s.app.GetConfigFile(*settings.PublicCertificateFile)
+	if err != nil {
+		return nil, model.NewAppError("ConfigureSP", "ent.saml.configure.load_public_cert.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	if *settings.PrivateKeyFile == "" {
+		return nil, model.NewAppError("ConfigureSP", "ent.saml.configure.load_private_key.app_error", nil, "no private key configured", http.StatusInternalServerError)
+	}
+	key, err := s.app.GetConfigFile(*settings.PrivateKeyFile)
+	if err != nil {
+		return nil, model.NewAppError("ConfigureSP", "ent.saml.configure.load_private_key.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	pair, err := tls.X509KeyPair(certificate, key)
+	if err != nil {
+		return nil, model.NewAppError("ConfigureSP", "ent.saml.configure.load_private_key.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return dsig.TLSCertKeyStore(pair), nil
+}
+
+// serviceProvider returns the configured Service Provider, or an error if SAML is
+// not licensed or not enabled.
+func (s *MatterfossSaml) serviceProvider(where string) (*saml2.SAMLServiceProvider, *model.AppError) {
+	if license := s.app.License(); license == nil || !*license.Features.SAML {
+		return nil, model.NewAppError(where, "ent.saml.license_disable.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	s.mut.RLock()
+	sp := s.sp
+	s.mut.RUnlock()
+
+	if sp == nil || !*s.app.Config().SamlSettings.Enable {
+		return nil, model.NewAppError(where, "ent.saml.service_disable.app_error", nil, "", http.StatusNotImplemented)
+	}
+
+	return sp, nil
+}
+
+// BuildRequest builds an AuthnRequest for both bindings: URL redirects the browser to
+// the Identity Provider with the HTTP-Redirect binding, while Base64AuthRequest holds
+// the request to submit with the HTTP-POST binding.
+func (s *MatterfossSaml) BuildRequest(relayState string) (*model.SamlAuthRequest, *model.AppError) {
+	sp, appErr := s.serviceProvider("BuildRequest")
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	postDoc, err := sp.BuildAuthRequestDocument()
+	if err != nil {
+		return nil, model.NewAppError("BuildRequest", "ent.saml.build_request.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	postRequest, err := postDoc.WriteToString()
+	if err != nil {
+		return nil, model.NewAppError("BuildRequest", "ent.saml.build_request.encoding.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	// The redirect binding carries its signature in the query string rather than in
+	// the document.
+	redirectDoc, err := sp.BuildAuthRequestDocumentNoSig()
+	if err != nil {
+		return nil, model.NewAppError("BuildRequest", "ent.saml.build_request.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	redirectURL, err := sp.BuildAuthURLRedirect(relayState, redirectDoc)
+	if err != nil {
+		return nil, model.NewAppError("BuildRequest", "ent.saml.build_request.encoding.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return &model.SamlAuthRequest{
+		Base64AuthRequest: base64.StdEncoding.EncodeToString([]byte(postRequest)),
+		URL:               redirectURL,
+		RelayState:        relayState,
+	}, nil
+}
+
+// GetMetadata returns the Service Provider metadata to register with the Identity
+// Provider.
+func (s *MatterfossSaml) GetMetadata() (string, *model.AppError) {
+	sp, appErr := s.serviceProvider("GetMetadata")
+	if appErr != nil {
+		return "", appErr
+	}
+
+	descriptor := &types.EntityDescriptor{
+		ValidUntil: time.Now().UTC().Add(metadataValidity),
+		EntityID:   sp.ServiceProviderIssuer,
+		SPSSODescriptor: &types.SPSSODescriptor{
+			AuthnRequestsSigned:        sp.SignAuthnRequests,
+			WantAssertionsSigned:       !sp.SkipSignatureValidation,
+			ProtocolSupportEnumeration: saml2.SAMLProtocolNamespace,
+			NameIDFormats:              []string{sp.NameIdFormat},
+			AssertionConsumerServices: []types.IndexedEndpoint{{
+				Binding:  saml2.BindingHttpPost,
+				Location: sp.AssertionConsumerServiceURL,
+				Index:    1,
+			}},
+		},
+	}
+
+	if sp.SPKeyStore != nil {
+		_, certificate, err := sp.SPKeyStore.GetKeyPair()
+		if err != nil {
+			return "", model.NewAppError("GetMetadata", "ent.saml.metadata.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+		keyInfo := dsigtypes.KeyInfo{
+			X509Data: dsigtypes.X509Data{
+				X509Certificates: []dsigtypes.X509Certificate{{
+					Data: base64.StdEncoding.EncodeToString(certificate),
+				}},
+			},
+		}
+		descriptor.SPSSODescriptor.KeyDescriptors = []types.KeyDescriptor{
+			{Use: "signing", KeyInfo: keyInfo},
+			{
+				Use:     "encryption",
+				KeyInfo: keyInfo,
+				EncryptionMethods: []types.EncryptionMethod{
+					{Algorithm: types.MethodAES128GCM},
+					{Algorithm: types.MethodAES128CBC},
+					{Algorithm: types.MethodAES256CBC},
+				},
+			},
+		}
+	}
+
+	metadata, err := xml.MarshalIndent(descriptor, "", "  ")
+	if err != nil {
+		return "", model.NewAppError("GetMetadata", "ent.saml.metadata.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return xml.Header + string(metadata), nil
+}
@@ -0,0 +1,375 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package saml
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	saml2 "github.com/mattermost/gosaml2"
+	"github.com/mattermost/gosaml2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/app"
+	"github.com/cjdelisle/matterfoss-server/v6/app/request"
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces/mocks"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// newTestSaml returns a configured Service Provider trusting a test Identity Provider.
+func newTestSaml(t *testing.T) (*testIdP, *mockApp, *MatterfossSaml) {
+	ma := newMockApp(t)
+	idp := &testIdP{t: t, keys: newKeyPair(t, "idp"), ma: ma}
+	ma.files[app.SamlIdpCertificateName] = idp.keys.certificatePEM
+
+	s := NewMatterfossSaml(ma)
+	require.NoError(t, s.ConfigureSP())
+	return idp, ma, s
+}
+
+// addServiceProviderKeys configures a key pair for the Service Provider.
+func addServiceProviderKeys(t *testing.T, ma *mockApp) *keyPair {
+	keys := newKeyPair(t, "sp")
+	ma.files[app.SamlPublicCertificateName] = keys.certificatePEM
+	ma.files[app.SamlPrivateKeyName] = keys.keyPEM
+	ma.config.SamlSettings.PublicCertificateFile = model.NewString(app.SamlPublicCertificateName)
+	ma.config.SamlSettings.PrivateKeyFile = model.NewString(app.SamlPrivateKeyName)
+	return keys
+}
+
+func aliceAttributes() map[string][]string {
+	return map[string][]string{
+		"id":        {"alice-id"},
+		"email":     {"Alice@Example.com"},
+		"username":  {"alice"},
+		"firstName": {"Alice"},
+		"lastName":  {"Anderson"},
+		"title":     {"Engineer"},
+		"locale":    {"fr"},
+	}
+}
+
+func TestConfigureSP(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		ma := newMockApp(t)
+		ma.config.SamlSettings.Enable = model.NewBool(false)
+		s := NewMatterfossSaml(ma)
+
+		require.NoError(t, s.ConfigureSP())
+		_, appErr := s.BuildRequest("")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.saml.service_disable.app_error", appErr.Id)
+	})
+
+	t.Run("missing idp certificate", func(t *testing.T) {
+		ma := newMockApp(t)
+		s := NewMatterfossSaml(ma)
+
+		err := s.ConfigureSP()
+		require.Error(t, err)
+		assert.Equal(t, "ent.saml.configure.load_idp_cert.app_error", err.(*model.AppError).Id)
+		_, appErr := s.GetMetadata()
+		require.NotNil(t, appErr)
+	})
+
+	t.Run("encryption without keys", func(t *testing.T) {
+		_, ma, s := newTestSaml(t)
+		ma.config.SamlSettings.Encrypt = model.NewBool(true)
+
+		err := s.ConfigureSP()
+		require.Error(t, err)
+		assert.Equal(t, "ent.saml.configure.load_public_cert.app_error", err.(*model.AppError).Id)
+	})
+
+	t.Run("mismatched keys", func(t *testing.T) {
+		_, ma, s := newTestSaml(t)
+		addServiceProviderKeys(t, ma)
+		ma.files[app.SamlPrivateKeyName] = newKeyPair(t, "other").keyPEM
+
+		err := s.ConfigureSP()
+		require.Error(t, err)
+		assert.Equal(t, "ent.saml.configure.load_private_key.app_error", err.(*model.AppError).Id)
+	})
+
+	t.Run("unlicensed", func(t *testing.T) {
+		_, ma, s := newTestSaml(t)
+		ma.license = nil
+
+		_, appErr := s.BuildRequest("")
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.saml.license_disable.app_error", appErr.Id)
+	})
+}
+
+func TestBuildRequest(t *testing.T) {
+	_, ma, s := newTestSaml(t)
+
+	t.Run("unsigned", func(t *testing.T) {
+		request, appErr := s.BuildRequest("state")
+		require.Nil(t, appErr)
+		assert.Equal(t, "state", request.RelayState)
+
+		redirect, err := url.Parse(request.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "idp.example.com", redirect.Host)
+		assert.NotEmpty(t, redirect.Query().Get("SAMLRequest"))
+		assert.Equal(t, "state", redirect.Query().Get("RelayState"))
+		assert.Empty(t, redirect.Query().Get("Signature"))
+
+		post, err := base64.StdEncoding.DecodeString(request.Base64AuthRequest)
+		require.NoError(t, err)
+		assert.Contains(t, string(post), "AuthnRequest")
+		assert.Contains(t, string(post), "https://chat.example.com/login/sso/saml")
+		assert.NotContains(t, string(post), "Signature")
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		addServiceProviderKeys(t, ma)
+		ma.config.SamlSettings.SignRequest = model.NewBool(true)
+		ma.config.SamlSettings.SignatureAlgorithm = model.NewString(model.SamlSettingsSignatureAlgorithmSha256)
+		require.NoError(t, s.ConfigureSP())
+
+		request, appErr := s.BuildRequest("state")
+		require.Nil(t, appErr)
+
+		redirect, err := url.Parse(request.URL)
+		require.NoError(t, err)
+		assert.NotEmpty(t, redirect.Query().Get("Signature"))
+		assert.Equal(t, "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256", redirect.Query().Get("SigAlg"))
+
+		post, err := base64.StdEncoding.DecodeString(request.Base64AuthRequest)
+		require.NoError(t, err)
+		assert.Contains(t, string(post), "SignatureValue")
+	})
+}
+
+func TestGetMetadata(t *testing.T) {
+	_, ma, s := newTestSaml(t)
+
+	metadata, appErr := s.GetMetadata()
+	require.Nil(t, appErr)
+	assert.Contains(t, metadata, `entityID="https://chat.example.com"`)
+	assert.Contains(t, metadata, `Location="https://chat.example.com/login/sso/saml"`)
+	assert.NotContains(t, metadata, "KeyDescriptor")
+
+	addServiceProviderKeys(t, ma)
+	require.NoError(t, s.ConfigureSP())
+
+	metadata, appErr = s.GetMetadata()
+	require.Nil(t, appErr)
+	assert.Contains(t, metadata, `use="signing"`)
+	assert.Contains(t, metadata, `use="encryption"`)
+}
+
+func TestDoLogin(t *testing.T) {
+	idp, ma, s := newTestSaml(t)
+	c := request.EmptyContext()
+
+	t.Run("creates the user on first login", func(t *testing.T) {
+		user, appErr := s.DoLogin(c, idp.response(aliceAttributes(), responseOptions{}), nil)
+		require.Nil(t, appErr)
+		assert.Equal(t, model.UserAuthServiceSaml, user.AuthService)
+		assert.Equal(t, "alice-id", *user.AuthData)
+		assert.Equal(t, "alice", user.Username)
+		assert.Equal(t, "alice@example.com", user.Email)
+		assert.Equal(t, "Alice", user.FirstName)
+		assert.Equal(t, "Engineer", user.Position)
+		assert.Equal(t, "fr", user.Locale)
+		assert.True(t, user.EmailVerified)
+		assert.Len(t, ma.users, 1)
+	})
+
+	t.Run("updates attributes on later logins", func(t *testing.T) {
+		attributes := aliceAttributes()
+		attributes["title"] = []string{"Manager"}
+
+		user, appErr := s.DoLogin(c, idp.response(attributes, responseOptions{}), nil)
+		require.Nil(t, appErr)
+		assert.Equal(t, "Manager", user.Position)
+		assert.Len(t, ma.users, 1)
+	})
+
+	t.Run("applies the guest and admin attributes", func(t *testing.T) {
+		ma.config.GuestAccountsSettings.Enable = model.NewBool(true)
+		ma.config.SamlSettings.GuestAttribute = model.NewString("role=guest")
+		ma.config.SamlSettings.EnableAdminAttribute = model.NewBool(true)
+		ma.config.SamlSettings.AdminAttribute = model.NewString("role=admin")
+		defer func() {
+			ma.config.SamlSettings.GuestAttribute = model.NewString("")
+			ma.config.SamlSettings.EnableAdminAttribute = model.NewBool(false)
+		}()
+
+		attributes := aliceAttributes()
+		attributes["role"] = []string{"staff", "admin"}
+		user, appErr := s.DoLogin(c, idp.response(attributes, responseOptions{}), nil)
+		require.Nil(t, appErr)
+		assert.True(t, user.IsSystemAdmin())
+
+		attributes["role"] = []string{"guest"}
+		user, appErr = s.DoLogin(c, idp.response(attributes, responseOptions{}), nil)
+		require.Nil(t, appErr)
+		assert.True(t, user.IsGuest())
+		assert.False(t, user.IsSystemAdmin())
+
+		delete(attributes, "role")
+		user, appErr = s.DoLogin(c, idp.response(attributes, responseOptions{}), nil)
+		require.Nil(t, appErr)
+		assert.False(t, user.IsGuest())
+	})
+
+	t.Run("rejects invalid responses", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			encodedXML string
+			errorID    string
+		}{
+			"empty":           {"", "ent.saml.do_login.empty_response.app_error"},
+			"not base64":      {"%%%", "ent.saml.do_login.parse.app_error"},
+			"not xml":         {base64.StdEncoding.EncodeToString([]byte("<broken")), "ent.saml.do_login.parse.app_error"},
+			"unsigned":        {idp.response(aliceAttributes(), responseOptions{unsigned: true}), "ent.saml.do_login.invalid_signature.app_error"},
+			"untrusted":       {idp.response(aliceAttributes(), responseOptions{signedBy: newKeyPair(t, "attacker")}), "ent.saml.do_login.invalid_signature.app_error"},
+			"not yet valid":   {idp.response(aliceAttributes(), responseOptions{notBefore: time.Now().Add(time.Hour)}), "ent.saml.do_login.invalid_time.app_error"},
+			"wrong audience":  {idp.response(aliceAttributes(), responseOptions{audience: "https://other.example.com"}), "ent.saml.do_login.validate.app_error"},
+			"missing email":   {idp.response(map[string][]string{"id": {"x"}, "username": {"x"}}, responseOptions{}), "ent.saml.attribute.app_error"},
+			"missing id":      {idp.response(map[string][]string{"email": {"x@example.com"}, "username": {"x"}}, responseOptions{}), "ent.saml.attribute.app_error"},
+			"missing usename": {idp.response(map[string][]string{"id": {"x"}, "email": {"x@example.com"}}, responseOptions{}), "ent.saml.attribute.app_error"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, appErr := s.DoLogin(c, tc.encodedXML, nil)
+				require.NotNil(t, appErr)
+				assert.Equal(t, tc.errorID, appErr.Id)
+			})
+		}
+	})
+
+	t.Run("accepts unsigned responses when verification is disabled", func(t *testing.T) {
+		ma.config.SamlSettings.Verify = model.NewBool(false)
+		defer func() { ma.config.SamlSettings.Verify = model.NewBool(true) }()
+		require.NoError(t, s.ConfigureSP())
+		defer func() { require.NoError(t, s.ConfigureSP()) }()
+
+		_, appErr := s.DoLogin(c, idp.response(aliceAttributes(), responseOptions{unsigned: true}), nil)
+		require.Nil(t, appErr)
+	})
+
+	t.Run("refuses an email used by another account", func(t *testing.T) {
+		ma.addUser(&model.User{Username: "carol", Email: "carol@example.com"})
+		attributes := map[string][]string{"id": {"carol-id"}, "email": {"carol@example.com"}, "username": {"carol"}}
+
+		_, appErr := s.DoLogin(c, idp.response(attributes, responseOptions{}), nil)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.saml.save_user.email_exists.saml_app_error", appErr.Id)
+	})
+
+	t.Run("refuses a taken username", func(t *testing.T) {
+		attributes := map[string][]string{"id": {"other-id"}, "email": {"other@example.com"}, "username": {"alice"}}
+
+		_, appErr := s.DoLogin(c, idp.response(attributes, responseOptions{}), nil)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.saml.save_user.username_exists.saml_app_error", appErr.Id)
+	})
+
+	t.Run("switches an email account to SAML", func(t *testing.T) {
+		dave := ma.addUser(&model.User{Username: "dave", Email: "dave@example.com", Password: "hash"})
+		attributes := map[string][]string{"id": {"dave-id"}, "email": {"dave@example.com"}, "username": {"dave"}}
+		relayState := map[string]string{"action": model.OAuthActionEmailToSSO, "email": "dave@example.com"}
+
+		user, appErr := s.DoLogin(c, idp.response(attributes, responseOptions{}), relayState)
+		require.Nil(t, appErr)
+		assert.Equal(t, dave.Id, user.Id)
+		assert.Equal(t, model.UserAuthServiceSaml, user.AuthService)
+		assert.Equal(t, "dave-id", *user.AuthData)
+		assert.Empty(t, ma.users[dave.Id].Password)
+
+		relayState["email"] = "alice@example.com"
+		_, appErr = s.DoLogin(c, idp.response(attributes, responseOptions{}), relayState)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.saml.do_login.email_mismatch.app_error", appErr.Id)
+	})
+
+	t.Run("synchronizes new users with AD/LDAP", func(t *testing.T) {
+		ldap := &mocks.LdapInterface{}
+		ldap.On("FirstLoginSync", mock.Anything, mock.AnythingOfType("*model.User"), model.UserAuthServiceSaml, "erin-id", "erin@example.com").Return(nil).Once()
+		ma.ldap = ldap
+		ma.config.SamlSettings.EnableSyncWithLdap = model.NewBool(true)
+		defer func() {
+			ma.ldap = nil
+			ma.config.SamlSettings.EnableSyncWithLdap = model.NewBool(false)
+		}()
+
+		attributes := map[string][]string{"id": {"erin-id"}, "email": {"erin@example.com"}, "username": {"erin"}}
+		_, appErr := s.DoLogin(c, idp.response(attributes, responseOptions{}), nil)
+		require.Nil(t, appErr)
+		ldap.AssertExpectations(t)
+	})
+}
+
+func TestDoLoginEncrypted(t *testing.T) {
+	idp, ma, s := newTestSaml(t)
+	c := request.EmptyContext()
+
+	t.Run("encrypted response without keys", func(t *testing.T) {
+		_, appErr := s.DoLogin(c, idp.response(aliceAttributes(), responseOptions{encryptTo: newKeyPair(t, "sp")}), nil)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.saml.configure.encryption_not_enabled.app_error", appErr.Id)
+	})
+
+	keys := addServiceProviderKeys(t, ma)
+	ma.config.SamlSettings.Encrypt = model.NewBool(true)
+	require.NoError(t, s.ConfigureSP())
+
+	t.Run("decrypts the assertion", func(t *testing.T) {
+		user, appErr := s.DoLogin(c, idp.response(aliceAttributes(), responseOptions{encryptTo: keys}), nil)
+		require.Nil(t, appErr)
+		assert.Equal(t, "alice-id", *user.AuthData)
+	})
+
+	t.Run("requires encryption", func(t *testing.T) {
+		_, appErr := s.DoLogin(c, idp.response(aliceAttributes(), responseOptions{}), nil)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.saml.configure.not_encrypted_response.app_error", appErr.Id)
+	})
+
+	t.Run("encrypted for another key", func(t *testing.T) {
+		_, appErr := s.DoLogin(c, idp.response(aliceAttributes(), responseOptions{encryptTo: newKeyPair(t, "other")}), nil)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "ent.saml.do_login.decrypt.app_error", appErr.Id)
+	})
+}
+
+func TestMatchesAttribute(t *testing.T) {
+	values := saml2.Values{
+		"role": types.Attribute{
+			Name:         "role",
+			FriendlyName: "Role",
+			Values:       []types.AttributeValue{{Value: "staff"}, {Value: "guest"}},
+		},
+	}
+
+	for condition, expected := range map[string]bool{
+		"role=guest":    true,
+		" role = guest": true,
+		"Role=guest":    true,
+		"role=admin":    false,
+		"other=guest":   false,
+		"role":          false,
+		"":              false,
+	} {
+		assert.Equal(t, expected, matchesAttribute(values, condition), condition)
+	}
+}
+
+func TestCheckProviderAttributes(t *testing.T) {
+	_, ma, s := newTestSaml(t)
+	settings := &ma.config.SamlSettings
+	user := &model.User{FirstName: "Alice", Nickname: "al", Position: "Engineer"}
+
+	assert.Equal(t, "", s.CheckProviderAttributes(settings, user, &model.UserPatch{Nickname: model.NewString("ally")}))
+	assert.Equal(t, "", s.CheckProviderAttributes(settings, user, &model.UserPatch{FirstName: model.NewString("Alice")}))
+	assert.Equal(t, "first name", s.CheckProviderAttributes(settings, user, &model.UserPatch{FirstName: model.NewString("Ally")}))
+	assert.Equal(t, "position", s.CheckProviderAttributes(settings, user, &model.UserPatch{Position: model.NewString("Manager")}))
+}
@@ -13,6 +13,7 @@ require (
 	github.com/araddon/dateparse v0.0.0-20210429162001-6b43995a97de // indirect
 	github.com/avct/uasurfer v0.0.0-20191028135549-26b5daa857f1
 	github.com/aws/aws-sdk-go v1.43.6
+	github.com/beevik/etree v1.1.0
 	github.com/bits-and-blooms/bitset v1.2.1 // indirect
 	github.com/blang/semver v3.5.1+incompatible
 	github.com/blevesearch/bleve/v2 v2.3.1
@@ -26,8 +27,8 @@ require (
 	github.com/francoispqt/gojay v1.2.13
 	github.com/fsnotify/fsnotify v1.5.1
 	github.com/getsentry/sentry-go v0.12.0
-	github.com/go-asn1-ber/asn1-ber v1.5.3 // indirect
-	github.com/go-redis/redis/v8 v8.11.4 // indirect
+	github.com/go-asn1-ber/asn1-ber v1.5.3
+	github.com/go-redis/redis/v8 v8.11.4
 	github.com/go-resty/resty/v2 v2.7.0 // indirect
 	github.com/go-sql-driver/mysql v1.6.0
 	github.com/golang-migrate/migrate/v4 v4.15.1
@@ -124,6 +125,7 @@ require (
 	gopkg.in/mail.v2 v2.3.1
 	gopkg.in/olivere/elastic.v6 v6.2.37
 	gopkg.in/yaml.v2 v2.4.0
+	modernc.org/sqlite v1.17.3
 )
 
 // Hack to prevent the willf/bitset module from being upgraded to 1.2.0.
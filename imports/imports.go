@@ -0,0 +1,17 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package imports
+
+import (
+	// Each package registers its implementation of an einterfaces interface with the app layer.
+	_ "github.com/cjdelisle/matterfoss-server/v6/enterprise/cluster"
+	_ "github.com/cjdelisle/matterfoss-server/v6/enterprise/compliance"
+	_ "github.com/cjdelisle/matterfoss-server/v6/enterprise/data_retention"
+	_ "github.com/cjdelisle/matterfoss-server/v6/enterprise/elasticsearch"
+	_ "github.com/cjdelisle/matterfoss-server/v6/enterprise/ldap"
+	_ "github.com/cjdelisle/matterfoss-server/v6/enterprise/message_export"
+	_ "github.com/cjdelisle/matterfoss-server/v6/enterprise/metrics"
+	_ "github.com/cjdelisle/matterfoss-server/v6/enterprise/notification"
+	_ "github.com/cjdelisle/matterfoss-server/v6/enterprise/saml"
+)
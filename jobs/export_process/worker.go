@@ -6,6 +6,10 @@ package export_process
 import (
 	"io"
 	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 
 	"github.com/cjdelisle/matterfoss-server/v6/jobs"
 	"github.com/cjdelisle/matterfoss-server/v6/model"
@@ -33,6 +37,21 @@ func MakeWorker(jobServer *jobs.JobServer, app AppIface) model.Worker {
 			opts.IncludeAttachments = true
 		}
 
+		if teamIds, ok := job.Data["team_ids"]; ok && teamIds != "" {
+			opts.TeamIds = strings.Split(teamIds, ",")
+		}
+
+		if channelIds, ok := job.Data["channel_ids"]; ok && channelIds != "" {
+			opts.ChannelIds = strings.Split(channelIds, ",")
+		}
+
+		if since, ok := job.Data["since"]; ok && since != "" {
+			var err error
+			if opts.Since, err = strconv.ParseInt(since, 10, 64); err != nil {
+				return errors.Wrap(err, "invalid since timestamp")
+			}
+		}
+
 		outPath := *app.Config().ExportSettings.Directory
 		exportFilename := model.NewId() + "_export.zip"
 
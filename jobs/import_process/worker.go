@@ -5,8 +5,10 @@ package import_process
 
 import (
 	"archive/zip"
+	"bufio"
 	"io"
 	"net/http"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -17,9 +19,15 @@ import (
 	"github.com/cjdelisle/matterfoss-server/v6/model"
 	"github.com/cjdelisle/matterfoss-server/v6/services/configservice"
 	"github.com/cjdelisle/matterfoss-server/v6/shared/filestore"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
 )
 
-const jobName = "ImportProcess"
+const (
+	jobName = "ImportProcess"
+
+	// checkpointInterval is the number of lines after which the progress of the job is saved.
+	checkpointInterval = 10000
+)
 
 type AppIface interface {
 	configservice.ConfigService
@@ -27,9 +35,20 @@ type AppIface interface {
 	FileExists(path string) (bool, *model.AppError)
 	FileSize(path string) (int64, *model.AppError)
 	FileReader(path string) (filestore.ReadCloseSeeker, *model.AppError)
-	BulkImportWithPath(c *request.Context, jsonlReader io.Reader, attachmentsReader *zip.Reader, dryRun bool, workers int, importPath string) (*model.AppError, int)
+	BulkImportWithOpts(c *request.Context, jsonlReader io.Reader, attachmentsReader *zip.Reader, opts model.BulkImportOpts) (*model.AppError, int)
+	SlackConvertToBulkImport(zipReader *zip.Reader, w io.Writer, teamID string) (int, *model.AppError)
+	ZulipConvertToBulkImport(zipReader *zip.Reader, w io.Writer, teamID string) (int, *model.AppError)
+	RocketChatConvertToBulkImport(zipReader *zip.Reader, w io.Writer, teamID string) (int, *model.AppError)
 }
 
+// MakeWorker returns the worker importing the file job.Data["import_file"] of the import
+// directory, which is either a bulk import zip file, or a Slack, Zulip or Rocket.Chat
+// export zip file when job.Data["import_from"] is "slack", "zulip" or "rocketchat",
+// imported into the team job.Data["team_id"].
+//
+// The number of lines of the data file imported so far is saved as job.Data["processed_lines"].
+// A job given the processed lines of a failed job for the same file resumes its import, and
+// the app gives them to the import jobs it creates.
 func MakeWorker(jobServer *jobs.JobServer, app AppIface) model.Worker {
 	appContext := &request.Context{}
 	isEnabled := func(cfg *model.Config) bool {
@@ -64,32 +83,96 @@ func MakeWorker(jobServer *jobs.JobServer, app AppIface) model.Worker {
 			return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.open_file", nil, err.Error(), http.StatusInternalServerError)
 		}
 
-		// find JSONL import file.
-		var jsonFile io.ReadCloser
-		for _, f := range importZipReader.File {
-			if filepath.Ext(f.Name) != ".jsonl" {
-				continue
+		opts := model.BulkImportOpts{
+			Workers:            runtime.NumCPU(),
+			ImportPath:         model.ExportDataDir,
+			CheckpointInterval: checkpointInterval,
+		}
+
+		var jsonFile io.Reader
+		var totalLines int
+		switch job.Data["import_from"] {
+		case "":
+			jsonEntry, appErr := findJSONLFile(importZipReader)
+			if appErr != nil {
+				return appErr
 			}
-			// avoid "zip slip"
-			if strings.Contains(f.Name, "..") {
-				return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.open_file", nil, "jsonFilePath contains path traversal", http.StatusForbidden)
+			if totalLines, appErr = countLines(jsonEntry); appErr != nil {
+				return appErr
 			}
 
-			jsonFile, err = f.Open()
+			jsonEntryReader, err := jsonEntry.Open()
 			if err != nil {
 				return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.open_file", nil, err.Error(), http.StatusInternalServerError)
 			}
+			defer jsonEntryReader.Close()
+			jsonFile = jsonEntryReader
+		case "slack", "zulip", "rocketchat":
+			convert := app.SlackConvertToBulkImport
+			switch job.Data["import_from"] {
+			case "zulip":
+				convert = app.ZulipConvertToBulkImport
+			case "rocketchat":
+				convert = app.RocketChatConvertToBulkImport
+			}
+
+			teamID, ok := job.Data["team_id"]
+			if !ok || !model.IsValidId(teamID) {
+				return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.missing_team", nil, "", http.StatusBadRequest)
+			}
+
+			// The export is converted into a temporary data file, which refers to the files
+			// of the export as its attachments.
+			tmpFile, err := os.CreateTemp("", job.Data["import_from"]+"_import_*.jsonl")
+			if err != nil {
+				return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.convert", nil, err.Error(), http.StatusInternalServerError)
+			}
+			defer os.Remove(tmpFile.Name())
+			defer tmpFile.Close()
 
-			defer jsonFile.Close()
-			break
+			if totalLines, appErr = convert(importZipReader, tmpFile, teamID); appErr != nil {
+				return appErr
+			}
+			if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+				return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.convert", nil, err.Error(), http.StatusInternalServerError)
+			}
+			jsonFile = tmpFile
+			opts.ImportPath = ""
+
+			// The import of an export goes on past the lines which fail to import.
+			failedLines, _ := strconv.Atoi(job.Data["failed_lines"])
+			opts.OnLineError = func(lineNumber int, appErr *model.AppError) {
+				mlog.Warn("Worker: Failed to import line", mlog.String("worker", jobName), mlog.String("job_id", job.Id), mlog.Int("line_number", lineNumber), mlog.Err(appErr))
+				failedLines++
+				job.Data["failed_lines"] = strconv.Itoa(failedLines)
+			}
+		default:
+			return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.unknown_import_from", nil, "", http.StatusBadRequest)
 		}
 
-		if jsonFile == nil {
-			return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.missing_jsonl", nil, "jsonFile was nil", http.StatusBadRequest)
+		if processedLines, ok := job.Data["processed_lines"]; ok {
+			var err error
+			if opts.SkipLines, err = strconv.Atoi(processedLines); err != nil {
+				return model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.processed_lines", nil, err.Error(), http.StatusBadRequest)
+			}
+		}
+
+		opts.OnProgress = func(lines int) {
+			if lines <= opts.SkipLines {
+				return
+			}
+			job.Data["processed_lines"] = strconv.Itoa(lines)
+			var progress int64
+			if totalLines > 0 {
+				progress = int64(lines) * 100 / int64(totalLines)
+			}
+			if appErr := jobServer.SetJobProgress(job, progress); appErr != nil {
+				mlog.Warn("Worker: Failed to set the progress of the job", mlog.String("worker", jobName), mlog.String("job_id", job.Id), mlog.Err(appErr))
+			}
 		}
 
 		// do the actual import.
-		appErr, lineNumber := app.BulkImportWithPath(appContext, jsonFile, importZipReader, false, runtime.NumCPU(), model.ExportDataDir)
+		appErr, lineNumber := app.BulkImportWithOpts(appContext, jsonFile, importZipReader, opts)
 		if appErr != nil {
 			job.Data["line_number"] = strconv.Itoa(lineNumber)
 			return appErr
@@ -104,3 +187,42 @@ func MakeWorker(jobServer *jobs.JobServer, app AppIface) model.Worker {
 	worker := jobs.NewSimpleWorker(jobName, jobServer, execute, isEnabled)
 	return worker
 }
+
+// findJSONLFile returns the JSONL data file of the import zip file.
+func findJSONLFile(importZipReader *zip.Reader) (*zip.File, *model.AppError) {
+	for _, f := range importZipReader.File {
+		if filepath.Ext(f.Name) != ".jsonl" {
+			continue
+		}
+		// avoid "zip slip"
+		if strings.Contains(f.Name, "..") {
+			return nil, model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.open_file", nil, "jsonFilePath contains path traversal", http.StatusForbidden)
+		}
+		return f, nil
+	}
+
+	return nil, model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.missing_jsonl", nil, "jsonFile was nil", http.StatusBadRequest)
+}
+
+// countLines returns the number of lines of the data file, to compute the progress of the import.
+func countLines(f *zip.File) (int, *model.AppError) {
+	reader, err := f.Open()
+	if err != nil {
+		return 0, model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.open_file", nil, err.Error(), http.StatusInternalServerError)
+	}
+	defer reader.Close()
+
+	lines := 0
+	bufReader := bufio.NewReader(reader)
+	for {
+		_, isPrefix, err := bufReader.ReadLine()
+		if err == io.EOF {
+			return lines, nil
+		} else if err != nil {
+			return 0, model.NewAppError("ImportProcessWorker", "import_process.worker.do_job.open_file", nil, err.Error(), http.StatusInternalServerError)
+		}
+		if !isPrefix {
+			lines++
+		}
+	}
+}
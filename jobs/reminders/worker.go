@@ -0,0 +1,30 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package reminders
+
+import (
+	"github.com/cjdelisle/matterfoss-server/v6/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const jobName = "Reminders"
+
+type AppIface interface {
+	ProcessReminders() *model.AppError
+}
+
+func MakeWorker(jobServer *jobs.JobServer, app AppIface) model.Worker {
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.ServiceSettings.EnableReminders
+	}
+	execute := func(job *model.Job) error {
+		if err := app.ProcessReminders(); err != nil {
+			mlog.Error("Worker: Failed to send the due reminders", mlog.String("worker", model.JobTypeReminders), mlog.String("job_id", job.Id), mlog.Err(err))
+			return err
+		}
+		return nil
+	}
+	return jobs.NewSimpleWorker(jobName, jobServer, execute, isEnabled)
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package scheduled_posts
+
+import (
+	"github.com/cjdelisle/matterfoss-server/v6/jobs"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+const jobName = "ScheduledPosts"
+
+type AppIface interface {
+	ProcessScheduledPosts() *model.AppError
+}
+
+func MakeWorker(jobServer *jobs.JobServer, app AppIface) model.Worker {
+	isEnabled := func(cfg *model.Config) bool {
+		return *cfg.ServiceSettings.EnableScheduledPosts
+	}
+	execute := func(job *model.Job) error {
+		if err := app.ProcessScheduledPosts(); err != nil {
+			mlog.Error("Worker: Failed to send the scheduled posts", mlog.String("worker", model.JobTypeScheduledPosts), mlog.String("job_id", job.Id), mlog.Err(err))
+			return err
+		}
+		return nil
+	}
+	return jobs.NewSimpleWorker(jobName, jobServer, execute, isEnabled)
+}
@@ -32,6 +32,19 @@ type AccessResponse struct {
 	IdToken      string `json:"id_token"`
 }
 
+// IntrospectionResponse is the state of an OAuth 2.0 token, as defined by RFC 7662. Only Active
+// is set for the tokens which aren't active.
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientId  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+}
+
 // IsValid validates the AccessData and returns an error if it isn't configured
 // correctly.
 func (ad *AccessData) IsValid() *AppError {
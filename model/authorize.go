@@ -4,7 +4,11 @@
 package model
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"net/http"
+	"regexp"
 )
 
 const (
@@ -12,25 +16,41 @@ const (
 	AuthCodeResponseType = "code"
 	ImplicitResponseType = "token"
 	DefaultScope         = "user"
+
+	PKCEMethodPlain = "plain"
+	PKCEMethodS256  = "S256"
+
+	// AuthNonceMaxLength is the length limit of the nonce given by OpenID Connect clients
+	// to be returned in their ID tokens.
+	AuthNonceMaxLength = 256
 )
 
+// validPKCECode matches the code verifiers and code challenges of RFC 7636.
+var validPKCECode = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+
 type AuthData struct {
-	ClientId    string `json:"client_id"`
-	UserId      string `json:"user_id"`
-	Code        string `json:"code"`
-	ExpiresIn   int32  `json:"expires_in"`
-	CreateAt    int64  `json:"create_at"`
-	RedirectUri string `json:"redirect_uri"`
-	State       string `json:"state"`
-	Scope       string `json:"scope"`
+	ClientId            string `json:"client_id"`
+	UserId              string `json:"user_id"`
+	Code                string `json:"code"`
+	ExpiresIn           int32  `json:"expires_in"`
+	CreateAt            int64  `json:"create_at"`
+	RedirectUri         string `json:"redirect_uri"`
+	State               string `json:"state"`
+	Scope               string `json:"scope"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	Nonce               string `json:"nonce"`
 }
 
 type AuthorizeRequest struct {
-	ResponseType string `json:"response_type"`
-	ClientId     string `json:"client_id"`
-	RedirectURI  string `json:"redirect_uri"`
-	Scope        string `json:"scope"`
-	State        string `json:"state"`
+	ResponseType        string `json:"response_type"`
+	ClientId            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	Nonce               string `json:"nonce"`
 }
 
 // IsValid validates the AuthData and returns an error if it isn't configured
@@ -69,6 +89,18 @@ func (ad *AuthData) IsValid() *AppError {
 		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.scope.app_error", nil, "client_id="+ad.ClientId, http.StatusBadRequest)
 	}
 
+	if len(ad.Nonce) > AuthNonceMaxLength {
+		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.nonce.app_error", nil, "client_id="+ad.ClientId, http.StatusBadRequest)
+	}
+
+	if ad.CodeChallenge != "" && !validPKCECode.MatchString(ad.CodeChallenge) {
+		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.code_challenge.app_error", nil, "client_id="+ad.ClientId, http.StatusBadRequest)
+	}
+
+	if ad.CodeChallengeMethod != "" && ad.CodeChallengeMethod != PKCEMethodPlain && ad.CodeChallengeMethod != PKCEMethodS256 {
+		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.code_challenge_method.app_error", nil, "client_id="+ad.ClientId, http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -96,6 +128,18 @@ func (ar *AuthorizeRequest) IsValid() *AppError {
 		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.scope.app_error", nil, "client_id="+ar.ClientId, http.StatusBadRequest)
 	}
 
+	if len(ar.Nonce) > AuthNonceMaxLength {
+		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.nonce.app_error", nil, "client_id="+ar.ClientId, http.StatusBadRequest)
+	}
+
+	if ar.CodeChallenge != "" && !validPKCECode.MatchString(ar.CodeChallenge) {
+		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.code_challenge.app_error", nil, "client_id="+ar.ClientId, http.StatusBadRequest)
+	}
+
+	if ar.CodeChallengeMethod != "" && (ar.CodeChallenge == "" || ar.CodeChallengeMethod != PKCEMethodPlain && ar.CodeChallengeMethod != PKCEMethodS256) {
+		return NewAppError("AuthData.IsValid", "model.authorize.is_valid.code_challenge_method.app_error", nil, "client_id="+ar.ClientId, http.StatusBadRequest)
+	}
+
 	return nil
 }
 
@@ -111,8 +155,33 @@ func (ad *AuthData) PreSave() {
 	if ad.Scope == "" {
 		ad.Scope = DefaultScope
 	}
+
+	if ad.CodeChallenge != "" && ad.CodeChallengeMethod == "" {
+		ad.CodeChallengeMethod = PKCEMethodPlain
+	}
 }
 
 func (ad *AuthData) IsExpired() bool {
 	return GetMillis() > ad.CreateAt+int64(ad.ExpiresIn*1000)
 }
+
+// VerifyCodeVerifier returns whether the code verifier sent to exchange the authorization code
+// matches the code challenge it was requested with, as defined by RFC 7636. A code verifier is
+// only accepted when a code challenge was sent, so that PKCE can't be downgraded.
+func (ad *AuthData) VerifyCodeVerifier(codeVerifier string) bool {
+	if ad.CodeChallenge == "" {
+		return codeVerifier == ""
+	}
+
+	if !validPKCECode.MatchString(codeVerifier) {
+		return false
+	}
+
+	challenge := codeVerifier
+	if ad.CodeChallengeMethod == PKCEMethodS256 {
+		hash := sha256.Sum256([]byte(codeVerifier))
+		challenge = base64.RawURLEncoding.EncodeToString(hash[:])
+	}
+
+	return subtle.ConstantTimeCompare([]byte(challenge), []byte(ad.CodeChallenge)) == 1
+}
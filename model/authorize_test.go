@@ -80,4 +80,45 @@ func TestAuthIsValid(t *testing.T) {
 
 	ad.RedirectUri = "http://example.com"
 	require.Nil(t, ad.IsValid())
+
+	ad.CodeChallenge = NewRandomString(42)
+	require.NotNil(t, ad.IsValid(), "Should have failed invalid CodeChallenge")
+
+	ad.CodeChallenge = NewRandomString(43)
+	ad.CodeChallengeMethod = "S512"
+	require.NotNil(t, ad.IsValid(), "Should have failed invalid CodeChallengeMethod")
+
+	ad.CodeChallengeMethod = PKCEMethodS256
+	require.Nil(t, ad.IsValid())
+
+	ad.Nonce = NewRandomString(AuthNonceMaxLength + 1)
+	require.NotNil(t, ad.IsValid(), "Should have failed invalid Nonce")
+
+	ad.Nonce = NewRandomString(AuthNonceMaxLength)
+	require.Nil(t, ad.IsValid())
+}
+
+func TestAuthVerifyCodeVerifier(t *testing.T) {
+	codeVerifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+	t.Run("S256", func(t *testing.T) {
+		ad := AuthData{CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallengeMethod: PKCEMethodS256}
+		require.True(t, ad.VerifyCodeVerifier(codeVerifier))
+		require.False(t, ad.VerifyCodeVerifier(""))
+		require.False(t, ad.VerifyCodeVerifier(ad.CodeChallenge))
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		ad := AuthData{CodeChallenge: codeVerifier}
+		ad.PreSave()
+		require.Equal(t, PKCEMethodPlain, ad.CodeChallengeMethod)
+		require.True(t, ad.VerifyCodeVerifier(codeVerifier))
+		require.False(t, ad.VerifyCodeVerifier(codeVerifier[1:]))
+	})
+
+	t.Run("without a code challenge", func(t *testing.T) {
+		ad := AuthData{}
+		require.True(t, ad.VerifyCodeVerifier(""))
+		require.False(t, ad.VerifyCodeVerifier(codeVerifier))
+	})
 }
@@ -10,4 +10,12 @@ const ExportDataDir = "data"
 type BulkExportOpts struct {
 	IncludeAttachments bool
 	CreateArchive      bool
+	// TeamIds restricts the export to these teams, with all of their channels.
+	TeamIds []string
+	// ChannelIds restricts the export to these channels, along with their teams. When
+	// either TeamIds or ChannelIds is set, direct and group messages are left out.
+	ChannelIds []string
+	// Since restricts the exported posts to the threads created or edited at or after
+	// this time, in milliseconds. The teams, channels and users are always exported.
+	Since int64
 }
@@ -0,0 +1,23 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+type BulkImportOpts struct {
+	DryRun  bool
+	Workers int
+	// ImportPath is the directory the paths of the attachments are relative to.
+	ImportPath string
+	// SkipLines is the number of lines of the data file already imported by a previous
+	// run, which are skipped to resume the import. The version line is always read.
+	SkipLines int
+	// CheckpointInterval is the number of lines after which the import waits for the
+	// lines being imported and reports its progress through OnProgress. The progress
+	// is also reported at every change of line type.
+	CheckpointInterval int
+	// OnProgress is called with the number of lines of the data file imported so far.
+	OnProgress func(lines int)
+	// OnLineError is called with the lines which failed to import, and the import goes
+	// on with the next lines. When nil, the import stops at the first failing line.
+	OnLineError func(lineNumber int, err *AppError)
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"unicode/utf8"
+)
+
+type ChannelBookmarkType string
+
+const (
+	ChannelBookmarkLink ChannelBookmarkType = "link"
+	ChannelBookmarkFile ChannelBookmarkType = "file"
+
+	ChannelBookmarksPerChannelMax      = 50
+	ChannelBookmarkDisplayNameMaxRunes = 64
+	ChannelBookmarkURLMaxLength        = 1024
+)
+
+// ChannelBookmark is a link or a file shown in the header of a channel, giving its members
+// quick access to the important resources of the channel.
+type ChannelBookmark struct {
+	Id        string `json:"id"`
+	CreateAt  int64  `json:"create_at"`
+	UpdateAt  int64  `json:"update_at"`
+	DeleteAt  int64  `json:"delete_at"`
+	ChannelId string `json:"channel_id"`
+	OwnerId   string `json:"owner_id"`
+	// The file of a file bookmark, empty for a link bookmark.
+	FileId      string `json:"file_id"`
+	DisplayName string `json:"display_name"`
+	SortOrder   int64  `json:"sort_order"`
+	// The url of a link bookmark, empty for a file bookmark.
+	LinkUrl  string              `json:"link_url,omitempty"`
+	ImageUrl string              `json:"image_url,omitempty"`
+	Emoji    string              `json:"emoji,omitempty"`
+	Type     ChannelBookmarkType `json:"type"`
+	RemoteId *string             `json:"remote_id,omitempty"`
+
+	// The info of the file of a file bookmark, populated when the bookmark is sent to clients.
+	FileInfo *FileInfo `json:"file,omitempty" db:"-"`
+}
+
+type ChannelBookmarkPatch struct {
+	FileId      *string `json:"file_id"`
+	DisplayName *string `json:"display_name"`
+	LinkUrl     *string `json:"link_url"`
+	ImageUrl    *string `json:"image_url"`
+	Emoji       *string `json:"emoji"`
+}
+
+func (o *ChannelBookmark) Clone() *ChannelBookmark {
+	bookmark := *o
+	if o.RemoteId != nil {
+		bookmark.RemoteId = NewString(*o.RemoteId)
+	}
+	return &bookmark
+}
+
+func (o *ChannelBookmark) IsValid() *AppError {
+	if !IsValidId(o.Id) {
+		return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.update_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.ChannelId) {
+		return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.channel_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.OwnerId) {
+		return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.owner_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.DisplayName == "" || utf8.RuneCountInString(o.DisplayName) > ChannelBookmarkDisplayNameMaxRunes {
+		return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.display_name.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	switch o.Type {
+	case ChannelBookmarkLink:
+		if o.FileId != "" || len(o.LinkUrl) > ChannelBookmarkURLMaxLength || !IsValidHTTPURL(o.LinkUrl) {
+			return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.link_url.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+		}
+	case ChannelBookmarkFile:
+		if !IsValidId(o.FileId) || o.LinkUrl != "" {
+			return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.file_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+		}
+	default:
+		return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.type.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.ImageUrl != "" && (len(o.ImageUrl) > ChannelBookmarkURLMaxLength || !IsValidHTTPURL(o.ImageUrl)) {
+		return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.image_url.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.Emoji != "" && (len(o.Emoji) > EmojiNameMaxLength || !IsValidAlphaNumHyphenUnderscorePlus(o.Emoji)) {
+		return NewAppError("ChannelBookmark.IsValid", "model.channel_bookmark.is_valid.emoji.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *ChannelBookmark) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+	o.UpdateAt = o.CreateAt
+	o.DeleteAt = 0
+
+	if o.RemoteId == nil {
+		o.RemoteId = NewString("")
+	}
+}
+
+func (o *ChannelBookmark) PreUpdate() {
+	o.UpdateAt = GetMillis()
+
+	if o.RemoteId == nil {
+		o.RemoteId = NewString("")
+	}
+}
+
+func (o *ChannelBookmark) Patch(patch *ChannelBookmarkPatch) {
+	if patch.FileId != nil {
+		o.FileId = *patch.FileId
+	}
+
+	if patch.DisplayName != nil {
+		o.DisplayName = *patch.DisplayName
+	}
+
+	if patch.LinkUrl != nil {
+		o.LinkUrl = *patch.LinkUrl
+	}
+
+	if patch.ImageUrl != nil {
+		o.ImageUrl = *patch.ImageUrl
+	}
+
+	if patch.Emoji != nil {
+		o.Emoji = *patch.Emoji
+	}
+}
+
+// IsRemote tells whether the bookmark was synchronized from a remote cluster.
+func (o *ChannelBookmark) IsRemote() bool {
+	return o.RemoteId != nil && *o.RemoteId != ""
+}
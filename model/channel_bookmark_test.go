@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelBookmarkIsValid(t *testing.T) {
+	bookmark := &ChannelBookmark{
+		ChannelId:   NewId(),
+		OwnerId:     NewId(),
+		DisplayName: "Docs",
+		LinkUrl:     "https://example.com/docs",
+		Type:        ChannelBookmarkLink,
+	}
+	require.NotNil(t, bookmark.IsValid())
+
+	bookmark.PreSave()
+	require.Nil(t, bookmark.IsValid())
+
+	bookmark.DisplayName = strings.Repeat("a", ChannelBookmarkDisplayNameMaxRunes+1)
+	require.NotNil(t, bookmark.IsValid())
+	bookmark.DisplayName = "Docs"
+
+	bookmark.LinkUrl = "javascript:alert(1)"
+	appErr := bookmark.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.channel_bookmark.is_valid.link_url.app_error", appErr.Id)
+	bookmark.LinkUrl = "https://example.com/docs"
+
+	bookmark.Emoji = ":smile:"
+	require.NotNil(t, bookmark.IsValid())
+	bookmark.Emoji = "smile"
+	require.Nil(t, bookmark.IsValid())
+
+	bookmark.ImageUrl = "not a url"
+	require.NotNil(t, bookmark.IsValid())
+	bookmark.ImageUrl = "https://example.com/icon.png"
+	require.Nil(t, bookmark.IsValid())
+
+	bookmark.Type = ChannelBookmarkFile
+	appErr = bookmark.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.channel_bookmark.is_valid.file_id.app_error", appErr.Id)
+
+	bookmark.LinkUrl = ""
+	bookmark.FileId = NewId()
+	require.Nil(t, bookmark.IsValid())
+
+	bookmark.Type = "other"
+	require.NotNil(t, bookmark.IsValid())
+}
+
+func TestChannelBookmarkPatch(t *testing.T) {
+	bookmark := &ChannelBookmark{
+		DisplayName: "Docs",
+		LinkUrl:     "https://example.com/docs",
+		Emoji:       "smile",
+	}
+
+	bookmark.Patch(&ChannelBookmarkPatch{
+		DisplayName: NewString("Handbook"),
+		Emoji:       NewString(""),
+	})
+	assert.Equal(t, "Handbook", bookmark.DisplayName)
+	assert.Equal(t, "https://example.com/docs", bookmark.LinkUrl)
+	assert.Equal(t, "", bookmark.Emoji)
+}
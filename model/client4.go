@@ -15,6 +15,8 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+
+	"github.com/cjdelisle/matterfoss-server/v6/shared/webauthn"
 )
 
 const (
@@ -276,6 +278,26 @@ func (c *Client4) postsRoute() string {
 	return "/posts"
 }
 
+func (c *Client4) scheduledPostsRoute() string {
+	return "/posts/schedule"
+}
+
+func (c *Client4) scheduledPostRoute(scheduledPostId string) string {
+	return fmt.Sprintf(c.scheduledPostsRoute()+"/%v", scheduledPostId)
+}
+
+func (c *Client4) draftsRoute() string {
+	return "/drafts"
+}
+
+func (c *Client4) remindersRoute() string {
+	return "/reminders"
+}
+
+func (c *Client4) reminderRoute(reminderId string) string {
+	return fmt.Sprintf(c.remindersRoute()+"/%v", reminderId)
+}
+
 func (c *Client4) postsEphemeralRoute() string {
 	return "/posts/ephemeral"
 }
@@ -692,7 +714,7 @@ func (c *Client4) DoEmojiUploadFile(url string, data []byte, contentType string)
 	return &e, BuildResponse(rp), nil
 }
 
-func (c *Client4) DoUploadImportTeam(url string, data []byte, contentType string) (map[string]string, *Response, error) {
+func (c *Client4) DoUploadImportTeam(url string, data []byte, contentType string) (*Job, *Response, error) {
 	rq, err := http.NewRequest("POST", c.APIURL+url, bytes.NewReader(data))
 	if err != nil {
 		return nil, nil, err
@@ -713,7 +735,11 @@ func (c *Client4) DoUploadImportTeam(url string, data []byte, contentType string
 		return nil, BuildResponse(rp), AppErrorFromJSON(rp.Body)
 	}
 
-	return MapFromJSON(rp.Body), BuildResponse(rp), nil
+	var j Job
+	if jsonErr := json.NewDecoder(rp.Body).Decode(&j); jsonErr != nil {
+		return nil, nil, NewAppError("DoUploadImportTeam", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &j, BuildResponse(rp), nil
 }
 
 // Authentication Section
@@ -764,6 +790,44 @@ func (c *Client4) LoginWithMFA(loginId, password, mfaToken string) (*User, *Resp
 	return c.login(m)
 }
 
+// BeginWebAuthnLogin returns the options with which an authenticator makes an assertion to
+// log in. The login id is optional, without it the user picks a discoverable credential.
+func (c *Client4) BeginWebAuthnLogin(loginId string) (*webauthn.RequestOptions, *Response, error) {
+	r, err := c.DoAPIPost(c.usersRoute()+"/webauthn/login/begin", MapToJSON(map[string]string{"login_id": loginId}))
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var options webauthn.RequestOptions
+	if jsonErr := json.NewDecoder(r.Body).Decode(&options); jsonErr != nil {
+		return nil, nil, NewAppError("BeginWebAuthnLogin", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &options, BuildResponse(r), nil
+}
+
+// LoginByWebAuthn authenticates a user by the assertion made by an authenticator for the
+// options returned by BeginWebAuthnLogin.
+func (c *Client4) LoginByWebAuthn(login *WebAuthnLoginRequest) (*User, *Response, error) {
+	buf, err := json.Marshal(login)
+	if err != nil {
+		return nil, nil, NewAppError("LoginByWebAuthn", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPostBytes(c.usersRoute()+"/webauthn/login/finish", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	c.AuthToken = r.Header.Get(HeaderToken)
+	c.AuthType = HeaderBearer
+
+	var user User
+	if jsonErr := json.NewDecoder(r.Body).Decode(&user); jsonErr != nil {
+		return nil, nil, NewAppError("LoginByWebAuthn", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &user, BuildResponse(r), nil
+}
+
 func (c *Client4) login(m map[string]string) (*User, *Response, error) {
 	r, err := c.DoAPIPost("/users/login", MapToJSON(m))
 	if err != nil {
@@ -1380,6 +1444,179 @@ func (c *Client4) UpdateUserMfa(userId, code string, activate bool) (*Response,
 	return BuildResponse(r), nil
 }
 
+// BeginWebAuthnRegistration returns the options with which an authenticator creates a new
+// WebAuthn credential for the user.
+func (c *Client4) BeginWebAuthnRegistration(userId string) (*webauthn.CreationOptions, *Response, error) {
+	r, err := c.DoAPIPost(c.userRoute(userId)+"/webauthn/register/begin", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var options webauthn.CreationOptions
+	if jsonErr := json.NewDecoder(r.Body).Decode(&options); jsonErr != nil {
+		return nil, nil, NewAppError("BeginWebAuthnRegistration", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &options, BuildResponse(r), nil
+}
+
+// FinishWebAuthnRegistration registers the credential created by an authenticator for the
+// options returned by BeginWebAuthnRegistration.
+func (c *Client4) FinishWebAuthnRegistration(userId string, registration *WebAuthnRegistrationRequest) (*WebAuthnCredential, *Response, error) {
+	buf, err := json.Marshal(registration)
+	if err != nil {
+		return nil, nil, NewAppError("FinishWebAuthnRegistration", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPostBytes(c.userRoute(userId)+"/webauthn/register/finish", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var credential WebAuthnCredential
+	if jsonErr := json.NewDecoder(r.Body).Decode(&credential); jsonErr != nil {
+		return nil, nil, NewAppError("FinishWebAuthnRegistration", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &credential, BuildResponse(r), nil
+}
+
+// GetWebAuthnCredentials returns the WebAuthn credentials registered by a user.
+func (c *Client4) GetWebAuthnCredentials(userId string) ([]*WebAuthnCredential, *Response, error) {
+	r, err := c.DoAPIGet(c.userRoute(userId)+"/webauthn/credentials", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var credentials []*WebAuthnCredential
+	if jsonErr := json.NewDecoder(r.Body).Decode(&credentials); jsonErr != nil {
+		return nil, nil, NewAppError("GetWebAuthnCredentials", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return credentials, BuildResponse(r), nil
+}
+
+// PatchWebAuthnCredential partially updates a WebAuthn credential of a user.
+func (c *Client4) PatchWebAuthnCredential(userId, credentialId string, patch *WebAuthnCredentialPatch) (*WebAuthnCredential, *Response, error) {
+	buf, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, NewAppError("PatchWebAuthnCredential", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPutBytes(c.userRoute(userId)+"/webauthn/credentials/"+credentialId, buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var credential WebAuthnCredential
+	if jsonErr := json.NewDecoder(r.Body).Decode(&credential); jsonErr != nil {
+		return nil, nil, NewAppError("PatchWebAuthnCredential", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &credential, BuildResponse(r), nil
+}
+
+// DeleteWebAuthnCredential removes a WebAuthn credential of a user, which can no longer be
+// used to sign in.
+func (c *Client4) DeleteWebAuthnCredential(userId, credentialId string) (*Response, error) {
+	r, err := c.DoAPIDelete(c.userRoute(userId) + "/webauthn/credentials/" + credentialId)
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+// ActivateUserMfa activates multi-factor authentication for a user with a code generated by
+// the device of the user, and returns the recovery codes of the user, which are only
+// returned once.
+func (c *Client4) ActivateUserMfa(userId, code string) (*MfaRecoveryCodes, *Response, error) {
+	requestBody := map[string]interface{}{"activate": true, "code": code}
+	r, err := c.DoAPIPut(c.userRoute(userId)+"/mfa", StringInterfaceToJSON(requestBody))
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var codes MfaRecoveryCodes
+	if jsonErr := json.NewDecoder(r.Body).Decode(&codes); jsonErr != nil {
+		return nil, nil, NewAppError("ActivateUserMfa", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &codes, BuildResponse(r), nil
+}
+
+// RegenerateMfaRecoveryCodes replaces the MFA recovery codes of a user, confirmed by a code
+// generated by the device of the user or an unused recovery code.
+func (c *Client4) RegenerateMfaRecoveryCodes(userId, code string) (*MfaRecoveryCodes, *Response, error) {
+	r, err := c.DoAPIPost(c.userRoute(userId)+"/mfa/recovery_codes", MapToJSON(map[string]string{"code": code}))
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var codes MfaRecoveryCodes
+	if jsonErr := json.NewDecoder(r.Body).Decode(&codes); jsonErr != nil {
+		return nil, nil, NewAppError("RegenerateMfaRecoveryCodes", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &codes, BuildResponse(r), nil
+}
+
+// SendMfaResetEmail sends a link to reset the multi-factor authentication of the user with
+// the given email.
+func (c *Client4) SendMfaResetEmail(email string) (*Response, error) {
+	r, err := c.DoAPIPost(c.usersRoute()+"/mfa/reset/send", MapToJSON(map[string]string{"email": email}))
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+// ResetMfa uses the token of an MFA reset email to reset the multi-factor authentication of
+// its user. The returned status is MfaResetStatusPending when an administrator must
+// approve the reset.
+func (c *Client4) ResetMfa(token string) (string, *Response, error) {
+	r, err := c.DoAPIPost(c.usersRoute()+"/mfa/reset", MapToJSON(map[string]string{"token": token}))
+	if err != nil {
+		return "", BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return MapFromJSON(r.Body)["status"], BuildResponse(r), nil
+}
+
+// GetMfaResetRequests returns the MFA resets awaiting the approval of an administrator.
+func (c *Client4) GetMfaResetRequests() ([]*MfaResetRequest, *Response, error) {
+	r, err := c.DoAPIGet(c.usersRoute()+"/mfa/reset/requests", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var requests []*MfaResetRequest
+	if jsonErr := json.NewDecoder(r.Body).Decode(&requests); jsonErr != nil {
+		return nil, nil, NewAppError("GetMfaResetRequests", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return requests, BuildResponse(r), nil
+}
+
+// ApproveMfaReset resets the multi-factor authentication of a user who requested it.
+func (c *Client4) ApproveMfaReset(userId string) (*Response, error) {
+	r, err := c.DoAPIPost(c.userRoute(userId)+"/mfa/reset/approve", "")
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+// DenyMfaReset rejects the reset of the multi-factor authentication requested by a user.
+func (c *Client4) DenyMfaReset(userId string) (*Response, error) {
+	r, err := c.DoAPIDelete(c.userRoute(userId) + "/mfa/reset")
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
 // GenerateMfaSecret will generate a new MFA secret for a user and return it as a string and
 // as a base64 encoded image QR code.
 func (c *Client4) GenerateMfaSecret(userId string) (*MfaSecret, *Response, error) {
@@ -2567,8 +2804,8 @@ func (c *Client4) GetTeamUnread(teamId, userId string) (*TeamUnread, *Response,
 	return &tu, BuildResponse(r), nil
 }
 
-// ImportTeam will import an exported team from other app into a existing team.
-func (c *Client4) ImportTeam(data []byte, filesize int, importFrom, filename, teamId string) (map[string]string, *Response, error) {
+// ImportTeam will create a job importing an exported team from other app into a existing team.
+func (c *Client4) ImportTeam(data []byte, filesize int, importFrom, filename, teamId string) (*Job, *Response, error) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
@@ -3748,6 +3985,20 @@ func (c *Client4) GetPostThread(postId string, etag string, collapsedThreads boo
 	return &list, BuildResponse(r), nil
 }
 
+// GetPostEditHistory gets the revisions a post had before its edits, most recent first.
+func (c *Client4) GetPostEditHistory(postId string) ([]*PostRevision, *Response, error) {
+	r, err := c.DoAPIGet(c.postRoute(postId)+"/edit_history", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var list []*PostRevision
+	if jsonErr := json.NewDecoder(r.Body).Decode(&list); jsonErr != nil {
+		return nil, nil, NewAppError("GetPostEditHistory", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return list, BuildResponse(r), nil
+}
+
 // GetPostsForChannel gets a page of posts with an array for ordering for a channel.
 func (c *Client4) GetPostsForChannel(channelId string, page, perPage int, etag string, collapsedThreads bool) (*PostList, *Response, error) {
 	query := fmt.Sprintf("?page=%v&per_page=%v", page, perPage)
@@ -4249,6 +4500,323 @@ func (c *Client4) GetFileInfosForPost(postId string, etag string) ([]*FileInfo,
 	return list, BuildResponse(r), nil
 }
 
+// Drafts Section
+
+// UpsertDraft saves the draft of the current user, replacing their previous draft in
+// the same channel or thread.
+func (c *Client4) UpsertDraft(draft *Draft) (*Draft, *Response, error) {
+	buf, err := json.Marshal(draft)
+	if err != nil {
+		return nil, nil, NewAppError("UpsertDraft", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPostBytes(c.draftsRoute(), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var d Draft
+	if jsonErr := json.NewDecoder(r.Body).Decode(&d); jsonErr != nil {
+		return nil, nil, NewAppError("UpsertDraft", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &d, BuildResponse(r), nil
+}
+
+// GetDrafts returns the drafts of the user in the team, and in their direct and group
+// message channels.
+func (c *Client4) GetDrafts(userId, teamId string) ([]*Draft, *Response, error) {
+	r, err := c.DoAPIGet(c.userRoute(userId)+c.teamRoute(teamId)+c.draftsRoute(), "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var drafts []*Draft
+	if jsonErr := json.NewDecoder(r.Body).Decode(&drafts); jsonErr != nil {
+		return nil, nil, NewAppError("GetDrafts", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return drafts, BuildResponse(r), nil
+}
+
+// DeleteDraft deletes the draft of the user in the channel, or in the thread of the
+// channel if the root id isn't empty.
+func (c *Client4) DeleteDraft(userId, channelId, rootId string) (*Response, error) {
+	route := c.userRoute(userId) + c.channelRoute(channelId) + c.draftsRoute()
+	if rootId != "" {
+		route += "/" + rootId
+	}
+	r, err := c.DoAPIDelete(route)
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+// Scheduled Posts Section
+
+// CreateScheduledPost schedules a post of the current user to be sent at its scheduled
+// time.
+func (c *Client4) CreateScheduledPost(scheduledPost *ScheduledPost) (*ScheduledPost, *Response, error) {
+	buf, err := json.Marshal(scheduledPost)
+	if err != nil {
+		return nil, nil, NewAppError("CreateScheduledPost", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPostBytes(c.scheduledPostsRoute(), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var sp ScheduledPost
+	if jsonErr := json.NewDecoder(r.Body).Decode(&sp); jsonErr != nil {
+		return nil, nil, NewAppError("CreateScheduledPost", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &sp, BuildResponse(r), nil
+}
+
+// GetScheduledPosts returns the posts scheduled by the user in the team, and in their
+// direct and group message channels.
+func (c *Client4) GetScheduledPosts(userId, teamId string) ([]*ScheduledPost, *Response, error) {
+	r, err := c.DoAPIGet(c.userRoute(userId)+c.teamRoute(teamId)+"/scheduled_posts", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var scheduledPosts []*ScheduledPost
+	if jsonErr := json.NewDecoder(r.Body).Decode(&scheduledPosts); jsonErr != nil {
+		return nil, nil, NewAppError("GetScheduledPosts", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return scheduledPosts, BuildResponse(r), nil
+}
+
+// UpdateScheduledPost updates the message, files, props and scheduled time of a post
+// scheduled by the current user.
+func (c *Client4) UpdateScheduledPost(scheduledPost *ScheduledPost) (*ScheduledPost, *Response, error) {
+	buf, err := json.Marshal(scheduledPost)
+	if err != nil {
+		return nil, nil, NewAppError("UpdateScheduledPost", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPutBytes(c.scheduledPostRoute(scheduledPost.Id), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var sp ScheduledPost
+	if jsonErr := json.NewDecoder(r.Body).Decode(&sp); jsonErr != nil {
+		return nil, nil, NewAppError("UpdateScheduledPost", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &sp, BuildResponse(r), nil
+}
+
+// DeleteScheduledPost deletes a post scheduled by the current user.
+func (c *Client4) DeleteScheduledPost(scheduledPostId string) (*Response, error) {
+	r, err := c.DoAPIDelete(c.scheduledPostRoute(scheduledPostId))
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+// Reminders Section
+
+// CreateReminder sets a reminder of the current user, about a post or with a message.
+func (c *Client4) CreateReminder(reminder *Reminder) (*Reminder, *Response, error) {
+	buf, err := json.Marshal(reminder)
+	if err != nil {
+		return nil, nil, NewAppError("CreateReminder", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPostBytes(c.remindersRoute(), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var rm Reminder
+	if jsonErr := json.NewDecoder(r.Body).Decode(&rm); jsonErr != nil {
+		return nil, nil, NewAppError("CreateReminder", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &rm, BuildResponse(r), nil
+}
+
+// GetReminders returns the reminders of the user, ordered by their target time.
+func (c *Client4) GetReminders(userId string) ([]*Reminder, *Response, error) {
+	r, err := c.DoAPIGet(c.userRoute(userId)+"/reminders", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var reminders []*Reminder
+	if jsonErr := json.NewDecoder(r.Body).Decode(&reminders); jsonErr != nil {
+		return nil, nil, NewAppError("GetReminders", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return reminders, BuildResponse(r), nil
+}
+
+// DeleteReminder deletes a reminder of the current user.
+func (c *Client4) DeleteReminder(reminderId string) (*Response, error) {
+	r, err := c.DoAPIDelete(c.reminderRoute(reminderId))
+	if err != nil {
+		return BuildResponse(r), err
+	}
+	defer closeBody(r)
+	return BuildResponse(r), nil
+}
+
+// Polls Section
+
+// GetPollResults gets the tallies of a poll post along with the options the current user voted for.
+func (c *Client4) GetPollResults(postId string) (*PollResults, *Response, error) {
+	r, err := c.DoAPIGet(c.postRoute(postId)+"/poll", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var results PollResults
+	if jsonErr := json.NewDecoder(r.Body).Decode(&results); jsonErr != nil {
+		return nil, nil, NewAppError("GetPollResults", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &results, BuildResponse(r), nil
+}
+
+// VotePoll replaces the votes of the current user for a poll post with votes for the options
+// at the given indexes. An empty list of options withdraws the votes of the user.
+func (c *Client4) VotePoll(postId string, optionIndexes []int) (*PollResults, *Response, error) {
+	buf, err := json.Marshal(optionIndexes)
+	if err != nil {
+		return nil, nil, NewAppError("VotePoll", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPutBytes(c.postRoute(postId)+"/poll/votes", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var results PollResults
+	if jsonErr := json.NewDecoder(r.Body).Decode(&results); jsonErr != nil {
+		return nil, nil, NewAppError("VotePoll", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &results, BuildResponse(r), nil
+}
+
+// ClosePoll stops the voting on a poll post.
+func (c *Client4) ClosePoll(postId string) (*Post, *Response, error) {
+	r, err := c.DoAPIPost(c.postRoute(postId)+"/poll/close", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var post Post
+	if jsonErr := json.NewDecoder(r.Body).Decode(&post); jsonErr != nil {
+		return nil, nil, NewAppError("ClosePoll", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &post, BuildResponse(r), nil
+}
+
+// Channel Bookmarks Section
+
+func (c *Client4) channelBookmarksRoute(channelId string) string {
+	return c.channelRoute(channelId) + "/bookmarks"
+}
+
+func (c *Client4) channelBookmarkRoute(channelId, bookmarkId string) string {
+	return c.channelBookmarksRoute(channelId) + "/" + bookmarkId
+}
+
+// GetChannelBookmarks gets the bookmarks of a channel in their sort order. When since is
+// not zero, the bookmarks updated at or after that time are returned instead, the deleted
+// ones included.
+func (c *Client4) GetChannelBookmarks(channelId string, since int64) ([]*ChannelBookmark, *Response, error) {
+	query := fmt.Sprintf("?since=%v", since)
+	r, err := c.DoAPIGet(c.channelBookmarksRoute(channelId)+query, "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var bookmarks []*ChannelBookmark
+	if jsonErr := json.NewDecoder(r.Body).Decode(&bookmarks); jsonErr != nil {
+		return nil, nil, NewAppError("GetChannelBookmarks", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return bookmarks, BuildResponse(r), nil
+}
+
+// CreateChannelBookmark adds a bookmark after the other bookmarks of its channel.
+func (c *Client4) CreateChannelBookmark(bookmark *ChannelBookmark) (*ChannelBookmark, *Response, error) {
+	buf, err := json.Marshal(bookmark)
+	if err != nil {
+		return nil, nil, NewAppError("CreateChannelBookmark", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPostBytes(c.channelBookmarksRoute(bookmark.ChannelId), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var saved ChannelBookmark
+	if jsonErr := json.NewDecoder(r.Body).Decode(&saved); jsonErr != nil {
+		return nil, nil, NewAppError("CreateChannelBookmark", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &saved, BuildResponse(r), nil
+}
+
+// PatchChannelBookmark partially updates a bookmark of a channel.
+func (c *Client4) PatchChannelBookmark(channelId, bookmarkId string, patch *ChannelBookmarkPatch) (*ChannelBookmark, *Response, error) {
+	buf, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, NewAppError("PatchChannelBookmark", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPatchBytes(c.channelBookmarkRoute(channelId, bookmarkId), buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var patched ChannelBookmark
+	if jsonErr := json.NewDecoder(r.Body).Decode(&patched); jsonErr != nil {
+		return nil, nil, NewAppError("PatchChannelBookmark", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &patched, BuildResponse(r), nil
+}
+
+// UpdateChannelBookmarkSortOrder moves a bookmark to the given index among the bookmarks of
+// its channel, and returns the bookmarks of the channel in their new order.
+func (c *Client4) UpdateChannelBookmarkSortOrder(channelId, bookmarkId string, newIndex int64) ([]*ChannelBookmark, *Response, error) {
+	buf, err := json.Marshal(newIndex)
+	if err != nil {
+		return nil, nil, NewAppError("UpdateChannelBookmarkSortOrder", "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	r, err := c.DoAPIPostBytes(c.channelBookmarkRoute(channelId, bookmarkId)+"/sort_order", buf)
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var bookmarks []*ChannelBookmark
+	if jsonErr := json.NewDecoder(r.Body).Decode(&bookmarks); jsonErr != nil {
+		return nil, nil, NewAppError("UpdateChannelBookmarkSortOrder", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return bookmarks, BuildResponse(r), nil
+}
+
+// DeleteChannelBookmark deletes a bookmark of a channel.
+func (c *Client4) DeleteChannelBookmark(channelId, bookmarkId string) (*ChannelBookmark, *Response, error) {
+	r, err := c.DoAPIDelete(c.channelBookmarkRoute(channelId, bookmarkId))
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+
+	var deleted ChannelBookmark
+	if jsonErr := json.NewDecoder(r.Body).Decode(&deleted); jsonErr != nil {
+		return nil, nil, NewAppError("DeleteChannelBookmark", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &deleted, BuildResponse(r), nil
+}
+
 // General/System Section
 
 // GenerateSupportPacket downloads the generated support packet
@@ -5664,6 +6232,101 @@ func (c *Client4) GetOAuthAccessToken(data url.Values) (*AccessResponse, *Respon
 	return ar, BuildResponse(rp), nil
 }
 
+// IntrospectOAuthToken returns the state of an OAuth access or refresh token, as defined by
+// RFC 7662. The data must hold the token and the credentials of the app it was issued to.
+func (c *Client4) IntrospectOAuthToken(data url.Values) (*IntrospectionResponse, *Response, error) {
+	url := c.URL + "/oauth/introspect"
+	rq, err := http.NewRequest(http.MethodPost, url, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, nil, err
+	}
+	rq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rp, err := c.HTTPClient.Do(rq)
+	if err != nil {
+		return nil, BuildResponse(rp), err
+	}
+	defer closeBody(rp)
+
+	if rp.StatusCode >= 300 {
+		return nil, BuildResponse(rp), AppErrorFromJSON(rp.Body)
+	}
+
+	var ir *IntrospectionResponse
+	err = json.NewDecoder(rp.Body).Decode(&ir)
+	if err != nil {
+		return nil, BuildResponse(rp), NewAppError(url, "api.marshal_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+
+	return ir, BuildResponse(rp), nil
+}
+
+// RevokeOAuthToken revokes an OAuth access or refresh token, as defined by RFC 7009. The data
+// must hold the token and the credentials of the app it was issued to.
+func (c *Client4) RevokeOAuthToken(data url.Values) (*Response, error) {
+	url := c.URL + "/oauth/revoke"
+	rq, err := http.NewRequest(http.MethodPost, url, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	rq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rp, err := c.HTTPClient.Do(rq)
+	if err != nil {
+		return BuildResponse(rp), err
+	}
+	defer closeBody(rp)
+
+	if rp.StatusCode >= 300 {
+		return BuildResponse(rp), AppErrorFromJSON(rp.Body)
+	}
+
+	return BuildResponse(rp), nil
+}
+
+// GetOpenIDConfiguration gets the discovery document of Matterfoss acting as an OpenID Provider.
+func (c *Client4) GetOpenIDConfiguration() (*OpenIDProviderMetadata, *Response, error) {
+	r, err := c.DoAPIRequest(http.MethodGet, c.URL+OpenIDDiscoveryPath, "", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var metadata OpenIDProviderMetadata
+	if jsonErr := json.NewDecoder(r.Body).Decode(&metadata); jsonErr != nil {
+		return nil, nil, NewAppError("GetOpenIDConfiguration", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &metadata, BuildResponse(r), nil
+}
+
+// GetOpenIDJSONWebKeySet gets the public keys the ID tokens issued by Matterfoss are verified with.
+func (c *Client4) GetOpenIDJSONWebKeySet() (*JSONWebKeySet, *Response, error) {
+	r, err := c.DoAPIRequest(http.MethodGet, c.URL+OpenIDJWKSPath, "", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var keySet JSONWebKeySet
+	if jsonErr := json.NewDecoder(r.Body).Decode(&keySet); jsonErr != nil {
+		return nil, nil, NewAppError("GetOpenIDJSONWebKeySet", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &keySet, BuildResponse(r), nil
+}
+
+// GetOpenIDUserInfo gets the claims of the user the client is authenticated as with an OAuth
+// access token granted the openid scope.
+func (c *Client4) GetOpenIDUserInfo() (*OpenIDUserInfo, *Response, error) {
+	r, err := c.DoAPIRequest(http.MethodGet, c.URL+OAuthUserInfoPath, "", "")
+	if err != nil {
+		return nil, BuildResponse(r), err
+	}
+	defer closeBody(r)
+	var userInfo OpenIDUserInfo
+	if jsonErr := json.NewDecoder(r.Body).Decode(&userInfo); jsonErr != nil {
+		return nil, nil, NewAppError("GetOpenIDUserInfo", "api.unmarshal_error", nil, jsonErr.Error(), http.StatusInternalServerError)
+	}
+	return &userInfo, BuildResponse(r), nil
+}
+
 // Elasticsearch Section
 
 // TestElasticsearch will attempt to connect to the configured Elasticsearch server and return OK if configured.
@@ -35,9 +35,13 @@ const (
 
 	DatabaseDriverMysql    = "mysql"
 	DatabaseDriverPostgres = "postgres"
+	DatabaseDriverSqlite   = "sqlite"
 
 	SearchengineElasticsearch = "elasticsearch"
 
+	CacheTypeLRU   = "lru"
+	CacheTypeRedis = "redis"
+
 	MinioAccessKey = "minioaccesskey"
 	MinioSecretKey = "miniosecretkey"
 	MinioBucket    = "matterfoss-test"
@@ -127,6 +131,9 @@ const (
 	ExportSettingsDefaultDirectory     = "./export"
 	ExportSettingsDefaultRetentionDays = 30
 
+	CacheSettingsDefaultRedisAddress = "localhost:6379"
+	CacheSettingsDefaultRedisPrefix  = "mm"
+
 	EmailSettingsDefaultFeedbackOrganization = ""
 
 	SupportSettingsDefaultTermsOfServiceLink = "https://matterfoss.org/terms-of-use/"
@@ -315,6 +322,9 @@ type ServiceSettings struct {
 	AllowedUntrustedInternalConnections               *string  `access:"environment_web_server,write_restrictable,cloud_restrictable"`
 	EnableMultifactorAuthentication                   *bool    `access:"authentication_mfa"`
 	EnforceMultifactorAuthentication                  *bool    `access:"authentication_mfa"`
+	EnableWebAuthn                                    *bool    `access:"authentication_mfa"`
+	EnableMfaSelfServiceReset                         *bool    `access:"authentication_mfa"`
+	MfaResetRequiresApproval                          *bool    `access:"authentication_mfa"`
 	EnableUserAccessTokens                            *bool    `access:"integrations_integration_management"`
 	AllowCorsFrom                                     *string  `access:"integrations_cors,write_restrictable,cloud_restrictable"`
 	CorsExposedHeaders                                *string  `access:"integrations_cors,write_restrictable,cloud_restrictable"`
@@ -371,6 +381,9 @@ type ServiceSettings struct {
 	CollapsedThreads                                  *string `access:"experimental_features"`
 	ManagedResourcePaths                              *string `access:"environment_web_server,write_restrictable,cloud_restrictable"`
 	EnableCustomGroups                                *bool   `access:"site_users_and_teams"`
+	AllowSyncedDrafts                                 *bool   `access:"site_posts"`
+	EnableScheduledPosts                              *bool   `access:"site_posts"`
+	EnableReminders                                   *bool   `access:"site_posts"`
 }
 
 func (s *ServiceSettings) SetDefaults(isUpdate bool) {
@@ -455,6 +468,18 @@ func (s *ServiceSettings) SetDefaults(isUpdate bool) {
 		s.EnforceMultifactorAuthentication = NewBool(false)
 	}
 
+	if s.EnableWebAuthn == nil {
+		s.EnableWebAuthn = NewBool(false)
+	}
+
+	if s.EnableMfaSelfServiceReset == nil {
+		s.EnableMfaSelfServiceReset = NewBool(false)
+	}
+
+	if s.MfaResetRequiresApproval == nil {
+		s.MfaResetRequiresApproval = NewBool(true)
+	}
+
 	if s.EnableUserAccessTokens == nil {
 		s.EnableUserAccessTokens = NewBool(false)
 	}
@@ -795,6 +820,18 @@ func (s *ServiceSettings) SetDefaults(isUpdate bool) {
 	if s.EnableCustomGroups == nil {
 		s.EnableCustomGroups = NewBool(true)
 	}
+
+	if s.AllowSyncedDrafts == nil {
+		s.AllowSyncedDrafts = NewBool(true)
+	}
+
+	if s.EnableScheduledPosts == nil {
+		s.EnableScheduledPosts = NewBool(false)
+	}
+
+	if s.EnableReminders == nil {
+		s.EnableReminders = NewBool(true)
+	}
 }
 
 type ClusterSettings struct {
@@ -2982,6 +3019,62 @@ func (s *ExportSettings) SetDefaults() {
 	}
 }
 
+// CacheSettings defines the cache used by the server for its stores.
+type CacheSettings struct {
+	// The type of the cache: either lru, for an in-process cache on each node, or redis,
+	// for a cache shared by all of the nodes.
+	CacheType *string `access:"environment_high_availability,write_restrictable,cloud_restrictable"`
+	// The address of the Redis server, as host:port.
+	RedisAddress *string `access:"environment_high_availability,write_restrictable,cloud_restrictable"` // telemetry: none
+	// The password of the Redis server, if any.
+	RedisPassword *string `access:"environment_high_availability,write_restrictable,cloud_restrictable"` // telemetry: none
+	// The Redis database to use.
+	RedisDB *int `access:"environment_high_availability,write_restrictable,cloud_restrictable"` // telemetry: none
+	// The prefix of the keys, so that several servers can share a Redis database.
+	RedisPrefix *string `access:"environment_high_availability,write_restrictable,cloud_restrictable"` // telemetry: none
+}
+
+func (s *CacheSettings) isValid() *AppError {
+	if *s.CacheType != CacheTypeLRU && *s.CacheType != CacheTypeRedis {
+		return NewAppError("Config.IsValid", "model.config.is_valid.cache.type.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if *s.CacheType == CacheTypeRedis {
+		if *s.RedisAddress == "" {
+			return NewAppError("Config.IsValid", "model.config.is_valid.cache.redis_address.app_error", nil, "", http.StatusBadRequest)
+		}
+
+		if *s.RedisDB < 0 {
+			return NewAppError("Config.IsValid", "model.config.is_valid.cache.redis_db.app_error", nil, "", http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// SetDefaults applies the default settings to the struct.
+func (s *CacheSettings) SetDefaults() {
+	if s.CacheType == nil {
+		s.CacheType = NewString(CacheTypeLRU)
+	}
+
+	if s.RedisAddress == nil {
+		s.RedisAddress = NewString(CacheSettingsDefaultRedisAddress)
+	}
+
+	if s.RedisPassword == nil {
+		s.RedisPassword = NewString("")
+	}
+
+	if s.RedisDB == nil {
+		s.RedisDB = NewInt(0)
+	}
+
+	if s.RedisPrefix == nil {
+		s.RedisPrefix = NewString(CacheSettingsDefaultRedisPrefix)
+	}
+}
+
 type ConfigFunc func() *Config
 
 const ConfigAccessTagType = "access"
@@ -3067,6 +3160,7 @@ type Config struct {
 	FeatureFlags              *FeatureFlags  `access:"*_read" json:",omitempty"`
 	ImportSettings            ImportSettings // telemetry: none
 	ExportSettings            ExportSettings
+	CacheSettings             CacheSettings
 }
 
 func (o *Config) Clone() *Config {
@@ -3175,6 +3269,7 @@ func (o *Config) SetDefaults() {
 	}
 	o.ImportSettings.SetDefaults()
 	o.ExportSettings.SetDefaults()
+	o.CacheSettings.SetDefaults()
 }
 
 func (o *Config) IsValid() *AppError {
@@ -3257,6 +3352,10 @@ func (o *Config) IsValid() *AppError {
 	if err := o.ImportSettings.isValid(); err != nil {
 		return err
 	}
+
+	if err := o.CacheSettings.isValid(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -3293,7 +3392,7 @@ func (s *SqlSettings) isValid() *AppError {
 		return NewAppError("Config.IsValid", "model.config.is_valid.encrypt_sql.app_error", nil, "", http.StatusBadRequest)
 	}
 
-	if !(*s.DriverName == DatabaseDriverMysql || *s.DriverName == DatabaseDriverPostgres) {
+	if !(*s.DriverName == DatabaseDriverMysql || *s.DriverName == DatabaseDriverPostgres || *s.DriverName == DatabaseDriverSqlite) {
 		return NewAppError("Config.IsValid", "model.config.is_valid.sql_driver.app_error", nil, "", http.StatusBadRequest)
 	}
 
@@ -3840,6 +3939,10 @@ func (o *Config) Sanitize() {
 	if o.ServiceSettings.SplitKey != nil {
 		*o.ServiceSettings.SplitKey = FakeSetting
 	}
+
+	if o.CacheSettings.RedisPassword != nil && *o.CacheSettings.RedisPassword != "" {
+		*o.CacheSettings.RedisPassword = FakeSetting
+	}
 }
 
 // structToMapFilteredByTag converts a struct into a map removing those fields that has the tag passed
@@ -0,0 +1,103 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"unicode/utf8"
+)
+
+// Draft is the unsent message of a user in a channel or thread, synced across the
+// devices of the user. A user has at most one draft per channel and thread.
+type Draft struct {
+	CreateAt  int64  `json:"create_at"`
+	UpdateAt  int64  `json:"update_at"`
+	UserId    string `json:"user_id"`
+	ChannelId string `json:"channel_id"`
+	// The id of the root post of the thread the draft is a reply to, if any.
+	RootId  string          `json:"root_id"`
+	Message string          `json:"message"`
+	Props   StringInterface `json:"props"`
+	FileIds StringArray     `json:"file_ids,omitempty"`
+}
+
+func (o *Draft) IsValid(maxDraftSize int) *AppError {
+	if o.CreateAt == 0 {
+		return NewAppError("Draft.IsValid", "model.draft.is_valid.create_at.app_error", nil, "channelid="+o.ChannelId, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("Draft.IsValid", "model.draft.is_valid.update_at.app_error", nil, "channelid="+o.ChannelId, http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.UserId) {
+		return NewAppError("Draft.IsValid", "model.draft.is_valid.user_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.ChannelId) {
+		return NewAppError("Draft.IsValid", "model.draft.is_valid.channel_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !(IsValidId(o.RootId) || o.RootId == "") {
+		return NewAppError("Draft.IsValid", "model.draft.is_valid.root_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(o.Message) > maxDraftSize {
+		return NewAppError("Draft.IsValid", "model.draft.is_valid.msg.app_error", nil, "channelid="+o.ChannelId, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(ArrayToJSON(o.FileIds)) > PostFileidsMaxRunes {
+		return NewAppError("Draft.IsValid", "model.draft.is_valid.file_ids.app_error", nil, "channelid="+o.ChannelId, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(StringInterfaceToJSON(o.Props)) > PostPropsMaxUserRunes {
+		return NewAppError("Draft.IsValid", "model.draft.is_valid.props.app_error", nil, "channelid="+o.ChannelId, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *Draft) PreSave() {
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+		o.UpdateAt = o.CreateAt
+	} else {
+		o.UpdateAt = GetMillis()
+	}
+
+	o.PreCommit()
+}
+
+func (o *Draft) PreUpdate() {
+	o.UpdateAt = GetMillis()
+	o.PreCommit()
+}
+
+func (o *Draft) PreCommit() {
+	if o.Props == nil {
+		o.Props = make(map[string]interface{})
+	}
+
+	if o.FileIds == nil {
+		o.FileIds = []string{}
+	}
+
+	// There's a rare bug where the client sends up duplicate FileIds so protect against that
+	o.FileIds = RemoveDuplicateStrings(o.FileIds)
+}
+
+// ToPost returns the post the draft is sent as.
+func (o *Draft) ToPost() *Post {
+	post := &Post{
+		UserId:    o.UserId,
+		ChannelId: o.ChannelId,
+		RootId:    o.RootId,
+		Message:   o.Message,
+		FileIds:   o.FileIds,
+	}
+	for key, value := range o.Props {
+		post.AddProp(key, value)
+	}
+	return post
+}
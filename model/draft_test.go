@@ -0,0 +1,61 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraftIsValid(t *testing.T) {
+	draft := &Draft{
+		UserId:    NewId(),
+		ChannelId: NewId(),
+		Message:   "message",
+	}
+	require.NotNil(t, draft.IsValid(PostMessageMaxRunesV2))
+
+	draft.PreSave()
+	require.Nil(t, draft.IsValid(PostMessageMaxRunesV2))
+
+	draft.RootId = "invalid"
+	require.NotNil(t, draft.IsValid(PostMessageMaxRunesV2))
+	draft.RootId = NewId()
+	require.Nil(t, draft.IsValid(PostMessageMaxRunesV2))
+
+	draft.Message = strings.Repeat("a", 11)
+	appErr := draft.IsValid(10)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.draft.is_valid.msg.app_error", appErr.Id)
+}
+
+func TestDraftPreSave(t *testing.T) {
+	draft := &Draft{FileIds: StringArray{"a", "a", "b"}}
+	draft.PreSave()
+	assert.NotZero(t, draft.CreateAt)
+	assert.Equal(t, draft.CreateAt, draft.UpdateAt)
+	assert.Equal(t, StringArray{"a", "b"}, draft.FileIds)
+	assert.NotNil(t, draft.Props)
+}
+
+func TestDraftToPost(t *testing.T) {
+	draft := &Draft{
+		UserId:    NewId(),
+		ChannelId: NewId(),
+		RootId:    NewId(),
+		Message:   "message",
+		Props:     StringInterface{"key": "value"},
+		FileIds:   StringArray{NewId()},
+	}
+	post := draft.ToPost()
+	assert.Equal(t, draft.UserId, post.UserId)
+	assert.Equal(t, draft.ChannelId, post.ChannelId)
+	assert.Equal(t, draft.RootId, post.RootId)
+	assert.Equal(t, draft.Message, post.Message)
+	assert.Equal(t, draft.FileIds, post.FileIds)
+	assert.Equal(t, "value", post.GetProp("key"))
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package oauthgoogle
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+type GoogleProvider struct {
+}
+
+type googleFieldMetadata struct {
+	Primary  bool `json:"primary"`
+	Verified bool `json:"verified"`
+}
+
+type googleName struct {
+	Metadata    googleFieldMetadata `json:"metadata"`
+	DisplayName string              `json:"displayName"`
+	FamilyName  string              `json:"familyName"`
+	GivenName   string              `json:"givenName"`
+}
+
+type googleNickname struct {
+	Metadata googleFieldMetadata `json:"metadata"`
+	Value    string              `json:"value"`
+}
+
+type googleEmailAddress struct {
+	Metadata googleFieldMetadata `json:"metadata"`
+	Value    string              `json:"value"`
+}
+
+// GoogleUser is the person returned by the People API for the signed in user.
+type GoogleUser struct {
+	ResourceName   string               `json:"resourceName"`
+	Names          []googleName         `json:"names"`
+	Nicknames      []googleNickname     `json:"nicknames"`
+	EmailAddresses []googleEmailAddress `json:"emailAddresses"`
+}
+
+func init() {
+	provider := &GoogleProvider{}
+	einterfaces.RegisterOAuthProvider(model.ServiceGoogle, provider)
+}
+
+func userFromGoogleUser(gu *GoogleUser) *model.User {
+	user := &model.User{}
+	email := gu.getEmail()
+
+	username := ""
+	if nickname := gu.getNickname(); nickname != "" {
+		user.Nickname = nickname
+		username = nickname
+	}
+	if username == "" {
+		username = strings.Split(email, "@")[0]
+	}
+	user.Username = model.CleanUsername(username)
+
+	if name := gu.getName(); name != nil {
+		user.FirstName = name.GivenName
+		user.LastName = name.FamilyName
+		if user.FirstName == "" && user.LastName == "" {
+			splitName := strings.SplitN(name.DisplayName, " ", 2)
+			user.FirstName = splitName[0]
+			if len(splitName) == 2 {
+				user.LastName = splitName[1]
+			}
+		}
+	}
+
+	user.Email = strings.ToLower(email)
+	userId := gu.getAuthData()
+	user.AuthData = &userId
+	user.AuthService = model.ServiceGoogle
+
+	return user
+}
+
+func googleUserFromJSON(data io.Reader) (*GoogleUser, error) {
+	decoder := json.NewDecoder(data)
+	var gu GoogleUser
+	err := decoder.Decode(&gu)
+	if err != nil {
+		return nil, err
+	}
+	return &gu, nil
+}
+
+func (gu *GoogleUser) IsValid() error {
+	if gu.getAuthData() == "" {
+		return errors.New("user id should not be empty")
+	}
+
+	if gu.getEmail() == "" {
+		return errors.New("user should have a verified e-mail")
+	}
+
+	return nil
+}
+
+// getAuthData returns the id of the account, the resource name being people/<id>.
+func (gu *GoogleUser) getAuthData() string {
+	return strings.TrimPrefix(gu.ResourceName, "people/")
+}
+
+// getEmail returns the primary e-mail address of the account when verified, or else its
+// first verified one.
+func (gu *GoogleUser) getEmail() string {
+	email := ""
+	for _, address := range gu.EmailAddresses {
+		if !address.Metadata.Verified || address.Value == "" {
+			continue
+		}
+		if address.Metadata.Primary {
+			return address.Value
+		}
+		if email == "" {
+			email = address.Value
+		}
+	}
+	return email
+}
+
+func (gu *GoogleUser) getName() *googleName {
+	for i := range gu.Names {
+		if gu.Names[i].Metadata.Primary {
+			return &gu.Names[i]
+		}
+	}
+	if len(gu.Names) > 0 {
+		return &gu.Names[0]
+	}
+	return nil
+}
+
+func (gu *GoogleUser) getNickname() string {
+	for _, nickname := range gu.Nicknames {
+		if nickname.Metadata.Primary {
+			return nickname.Value
+		}
+	}
+	if len(gu.Nicknames) > 0 {
+		return gu.Nicknames[0].Value
+	}
+	return ""
+}
+
+func (m *GoogleProvider) GetUserFromJSON(data io.Reader, tokenUser *model.User) (*model.User, error) {
+	gu, err := googleUserFromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = gu.IsValid(); err != nil {
+		return nil, err
+	}
+
+	return userFromGoogleUser(gu), nil
+}
+
+func (m *GoogleProvider) GetSSOSettings(config *model.Config, service string) (*model.SSOSettings, error) {
+	return &config.GoogleSettings, nil
+}
+
+func (m *GoogleProvider) GetUserFromIdToken(idToken string) (*model.User, error) {
+	return nil, nil
+}
+
+func (m *GoogleProvider) IsSameUser(dbUser, oauthUser *model.User) bool {
+	return dbUser.AuthData != nil && oauthUser.AuthData != nil && *dbUser.AuthData == *oauthUser.AuthData
+}
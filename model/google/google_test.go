@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package oauthgoogle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestGetUserFromJSON(t *testing.T) {
+	provider := &GoogleProvider{}
+
+	t.Run("should map the person", func(t *testing.T) {
+		data := `{
+			"resourceName": "people/1234",
+			"names": [{"metadata": {"primary": true}, "displayName": "Jane Doe", "givenName": "Jane", "familyName": "Doe"}],
+			"emailAddresses": [
+				{"metadata": {"verified": true}, "value": "jane@other.com"},
+				{"metadata": {"primary": true, "verified": true}, "value": "Jane.Doe@Example.com"}
+			]
+		}`
+		user, err := provider.GetUserFromJSON(strings.NewReader(data), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "1234", *user.AuthData)
+		assert.Equal(t, model.ServiceGoogle, user.AuthService)
+		assert.Equal(t, "jane.doe@example.com", user.Email)
+		assert.Equal(t, "jane.doe", user.Username)
+		assert.Equal(t, "Jane", user.FirstName)
+		assert.Equal(t, "Doe", user.LastName)
+	})
+
+	t.Run("should use the nickname as username", func(t *testing.T) {
+		data := `{
+			"resourceName": "people/1234",
+			"nicknames": [{"metadata": {"primary": true}, "value": "jdoe"}],
+			"emailAddresses": [{"metadata": {"primary": true, "verified": true}, "value": "jane.doe@example.com"}]
+		}`
+		user, err := provider.GetUserFromJSON(strings.NewReader(data), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "jdoe", user.Username)
+		assert.Equal(t, "jdoe", user.Nickname)
+	})
+
+	t.Run("should reject a person without a verified e-mail", func(t *testing.T) {
+		data := `{
+			"resourceName": "people/1234",
+			"emailAddresses": [{"metadata": {"primary": true}, "value": "jane.doe@example.com"}]
+		}`
+		_, err := provider.GetUserFromJSON(strings.NewReader(data), nil)
+		require.Error(t, err)
+	})
+}
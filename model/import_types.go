@@ -0,0 +1,322 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"archive/zip"
+)
+
+// Import Data Models
+
+type LineImportData struct {
+	Type            string                     `json:"type"`
+	Role            *RoleImportData            `json:"role,omitempty"`
+	Scheme          *SchemeImportData          `json:"scheme,omitempty"`
+	Team            *TeamImportData            `json:"team,omitempty"`
+	Channel         *ChannelImportData         `json:"channel,omitempty"`
+	User            *UserImportData            `json:"user,omitempty"`
+	Bot             *BotImportData             `json:"bot,omitempty"`
+	Post            *PostImportData            `json:"post,omitempty"`
+	DirectChannel   *DirectChannelImportData   `json:"direct_channel,omitempty"`
+	DirectPost      *DirectPostImportData      `json:"direct_post,omitempty"`
+	Emoji           *EmojiImportData           `json:"emoji,omitempty"`
+	Command         *CommandImportData         `json:"command,omitempty"`
+	IncomingWebhook *IncomingWebhookImportData `json:"incoming_webhook,omitempty"`
+	OutgoingWebhook *OutgoingWebhookImportData `json:"outgoing_webhook,omitempty"`
+	OAuthApp        *OAuthAppImportData        `json:"oauth_app,omitempty"`
+	ChannelBookmark *ChannelBookmarkImportData `json:"channel_bookmark,omitempty"`
+	Version         *int                       `json:"version,omitempty"`
+}
+
+type TeamImportData struct {
+	Name            *string `json:"name"`
+	DisplayName     *string `json:"display_name"`
+	Type            *string `json:"type"`
+	Description     *string `json:"description,omitempty"`
+	AllowOpenInvite *bool   `json:"allow_open_invite,omitempty"`
+	Scheme          *string `json:"scheme,omitempty"`
+}
+
+type ChannelImportData struct {
+	Team        *string      `json:"team"`
+	Name        *string      `json:"name"`
+	DisplayName *string      `json:"display_name"`
+	Type        *ChannelType `json:"type"`
+	Header      *string      `json:"header,omitempty"`
+	Purpose     *string      `json:"purpose,omitempty"`
+	Scheme      *string      `json:"scheme,omitempty"`
+}
+
+type UserImportData struct {
+	ProfileImage       *string   `json:"profile_image,omitempty"`
+	ProfileImageData   *zip.File `json:"-"`
+	Username           *string   `json:"username"`
+	Email              *string   `json:"email"`
+	AuthService        *string   `json:"auth_service"`
+	AuthData           *string   `json:"auth_data,omitempty"`
+	Password           *string   `json:"password,omitempty"`
+	Nickname           *string   `json:"nickname"`
+	FirstName          *string   `json:"first_name"`
+	LastName           *string   `json:"last_name"`
+	Position           *string   `json:"position"`
+	Roles              *string   `json:"roles"`
+	Locale             *string   `json:"locale"`
+	UseMarkdownPreview *string   `json:"feature_enabled_markdown_preview,omitempty"`
+	UseFormatting      *string   `json:"formatting,omitempty"`
+	ShowUnreadSection  *string   `json:"show_unread_section,omitempty"`
+	DeleteAt           *int64    `json:"delete_at,omitempty"`
+
+	Teams *[]UserTeamImportData `json:"teams,omitempty"`
+
+	Theme              *string `json:"theme,omitempty"`
+	UseMilitaryTime    *string `json:"military_time,omitempty"`
+	CollapsePreviews   *string `json:"link_previews,omitempty"`
+	MessageDisplay     *string `json:"message_display,omitempty"`
+	ChannelDisplayMode *string `json:"channel_display_mode,omitempty"`
+	TutorialStep       *string `json:"tutorial_step,omitempty"`
+	EmailInterval      *string `json:"email_interval,omitempty"`
+
+	NotifyProps *UserNotifyPropsImportData `json:"notify_props,omitempty"`
+}
+
+type UserNotifyPropsImportData struct {
+	Desktop      *string `json:"desktop"`
+	DesktopSound *string `json:"desktop_sound"`
+
+	Email *string `json:"email"`
+
+	Mobile           *string `json:"mobile"`
+	MobilePushStatus *string `json:"mobile_push_status"`
+
+	ChannelTrigger  *string `json:"channel"`
+	CommentsTrigger *string `json:"comments"`
+	MentionKeys     *string `json:"mention_keys"`
+}
+
+type UserTeamImportData struct {
+	Name       *string                      `json:"name"`
+	Roles      *string                      `json:"roles"`
+	Theme      *string                      `json:"theme,omitempty"`
+	Channels   *[]UserChannelImportData     `json:"channels,omitempty"`
+	Categories *[]SidebarCategoryImportData `json:"categories,omitempty"`
+}
+
+type UserChannelImportData struct {
+	Name        *string                           `json:"name"`
+	Roles       *string                           `json:"roles"`
+	NotifyProps *UserChannelNotifyPropsImportData `json:"notify_props,omitempty"`
+	Favorite    *bool                             `json:"favorite,omitempty"`
+}
+
+// SidebarCategoryImportData is a custom sidebar category of a user, holding channels of the team.
+type SidebarCategoryImportData struct {
+	DisplayName *string   `json:"display_name"`
+	Sorting     *string   `json:"sorting,omitempty"`
+	Muted       *bool     `json:"muted,omitempty"`
+	Collapsed   *bool     `json:"collapsed,omitempty"`
+	Channels    *[]string `json:"channels,omitempty"`
+}
+
+type UserChannelNotifyPropsImportData struct {
+	Desktop    *string `json:"desktop"`
+	Mobile     *string `json:"mobile"`
+	MarkUnread *string `json:"mark_unread"`
+}
+
+type BotImportData struct {
+	Username    *string `json:"username"`
+	DisplayName *string `json:"display_name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	// Owner is the username of the user owning the bot, or the id of the plugin which created it.
+	Owner    *string `json:"owner"`
+	DeleteAt *int64  `json:"delete_at,omitempty"`
+
+	Teams *[]UserTeamImportData `json:"teams,omitempty"`
+}
+
+type EmojiImportData struct {
+	Name  *string   `json:"name"`
+	Image *string   `json:"image"`
+	Data  *zip.File `json:"-"`
+}
+
+type ReactionImportData struct {
+	User      *string `json:"user"`
+	CreateAt  *int64  `json:"create_at"`
+	EmojiName *string `json:"emoji_name"`
+}
+
+type PostRevisionImportData struct {
+	// Editor is the username of the user who made the edit replacing the revision, if known.
+	Editor   *string          `json:"editor,omitempty"`
+	Message  *string          `json:"message"`
+	Props    *StringInterface `json:"props,omitempty"`
+	CreateAt *int64           `json:"create_at"`
+}
+
+// PollVoteImportData holds the votes of a user for the poll of a poll post.
+type PollVoteImportData struct {
+	User     *string `json:"user"`
+	Options  *[]int  `json:"options"`
+	CreateAt *int64  `json:"create_at"`
+}
+
+type ReplyImportData struct {
+	User *string `json:"user"`
+
+	Type     *string          `json:"type"`
+	Message  *string          `json:"message"`
+	Props    *StringInterface `json:"props,omitempty"`
+	CreateAt *int64           `json:"create_at"`
+	EditAt   *int64           `json:"edit_at"`
+	IsPinned *bool            `json:"is_pinned,omitempty"`
+
+	FlaggedBy   *[]string                 `json:"flagged_by,omitempty"`
+	Reactions   *[]ReactionImportData     `json:"reactions,omitempty"`
+	Attachments *[]AttachmentImportData   `json:"attachments,omitempty"`
+	EditHistory *[]PostRevisionImportData `json:"edit_history,omitempty"`
+	PollVotes   *[]PollVoteImportData     `json:"poll_votes,omitempty"`
+}
+
+type PostImportData struct {
+	Team    *string `json:"team"`
+	Channel *string `json:"channel"`
+	User    *string `json:"user"`
+
+	Type     *string          `json:"type"`
+	Message  *string          `json:"message"`
+	Props    *StringInterface `json:"props"`
+	CreateAt *int64           `json:"create_at"`
+	EditAt   *int64           `json:"edit_at"`
+	IsPinned *bool            `json:"is_pinned,omitempty"`
+
+	FlaggedBy   *[]string                 `json:"flagged_by,omitempty"`
+	Reactions   *[]ReactionImportData     `json:"reactions,omitempty"`
+	Replies     *[]ReplyImportData        `json:"replies,omitempty"`
+	Attachments *[]AttachmentImportData   `json:"attachments,omitempty"`
+	EditHistory *[]PostRevisionImportData `json:"edit_history,omitempty"`
+	PollVotes   *[]PollVoteImportData     `json:"poll_votes,omitempty"`
+}
+
+type DirectChannelImportData struct {
+	Members     *[]string `json:"members"`
+	FavoritedBy *[]string `json:"favorited_by"`
+
+	Header *string `json:"header"`
+}
+
+type DirectPostImportData struct {
+	ChannelMembers *[]string `json:"channel_members"`
+	User           *string   `json:"user"`
+
+	Type     *string          `json:"type"`
+	Message  *string          `json:"message"`
+	Props    *StringInterface `json:"props"`
+	CreateAt *int64           `json:"create_at"`
+	EditAt   *int64           `json:"edit_at"`
+	IsPinned *bool            `json:"is_pinned,omitempty"`
+
+	FlaggedBy   *[]string                 `json:"flagged_by"`
+	Reactions   *[]ReactionImportData     `json:"reactions"`
+	Replies     *[]ReplyImportData        `json:"replies"`
+	Attachments *[]AttachmentImportData   `json:"attachments"`
+	EditHistory *[]PostRevisionImportData `json:"edit_history,omitempty"`
+	PollVotes   *[]PollVoteImportData     `json:"poll_votes,omitempty"`
+}
+
+type SchemeImportData struct {
+	Name                    *string         `json:"name"`
+	DisplayName             *string         `json:"display_name"`
+	Description             *string         `json:"description"`
+	Scope                   *string         `json:"scope"`
+	DefaultTeamAdminRole    *RoleImportData `json:"default_team_admin_role"`
+	DefaultTeamUserRole     *RoleImportData `json:"default_team_user_role"`
+	DefaultChannelAdminRole *RoleImportData `json:"default_channel_admin_role"`
+	DefaultChannelUserRole  *RoleImportData `json:"default_channel_user_role"`
+	DefaultTeamGuestRole    *RoleImportData `json:"default_team_guest_role"`
+	DefaultChannelGuestRole *RoleImportData `json:"default_channel_guest_role"`
+}
+
+type RoleImportData struct {
+	Name        *string   `json:"name"`
+	DisplayName *string   `json:"display_name"`
+	Description *string   `json:"description"`
+	Permissions *[]string `json:"permissions"`
+}
+
+type CommandImportData struct {
+	Team    *string `json:"team"`
+	Creator *string `json:"creator"`
+	Trigger *string `json:"trigger"`
+	Token   *string `json:"token,omitempty"`
+	URL     *string `json:"url"`
+	Method  *string `json:"method"`
+
+	Username         *string `json:"username,omitempty"`
+	IconURL          *string `json:"icon_url,omitempty"`
+	AutoComplete     *bool   `json:"auto_complete,omitempty"`
+	AutoCompleteDesc *string `json:"auto_complete_desc,omitempty"`
+	AutoCompleteHint *string `json:"auto_complete_hint,omitempty"`
+	DisplayName      *string `json:"display_name,omitempty"`
+	Description      *string `json:"description,omitempty"`
+}
+
+type IncomingWebhookImportData struct {
+	Team    *string `json:"team"`
+	Channel *string `json:"channel"`
+	Creator *string `json:"creator"`
+
+	DisplayName   *string `json:"display_name,omitempty"`
+	Description   *string `json:"description,omitempty"`
+	Username      *string `json:"username,omitempty"`
+	IconURL       *string `json:"icon_url,omitempty"`
+	ChannelLocked *bool   `json:"channel_locked,omitempty"`
+}
+
+type OutgoingWebhookImportData struct {
+	Team         *string   `json:"team"`
+	Channel      *string   `json:"channel,omitempty"`
+	Creator      *string   `json:"creator"`
+	Token        *string   `json:"token,omitempty"`
+	CallbackURLs *[]string `json:"callback_urls"`
+
+	TriggerWords *[]string `json:"trigger_words,omitempty"`
+	TriggerWhen  *int      `json:"trigger_when,omitempty"`
+	DisplayName  *string   `json:"display_name,omitempty"`
+	Description  *string   `json:"description,omitempty"`
+	ContentType  *string   `json:"content_type,omitempty"`
+	Username     *string   `json:"username,omitempty"`
+	IconURL      *string   `json:"icon_url,omitempty"`
+}
+
+type OAuthAppImportData struct {
+	Creator      *string   `json:"creator"`
+	Name         *string   `json:"name"`
+	ClientSecret *string   `json:"client_secret,omitempty"`
+	CallbackURLs *[]string `json:"callback_urls"`
+	Homepage     *string   `json:"homepage"`
+
+	Description *string `json:"description,omitempty"`
+	IconURL     *string `json:"icon_url,omitempty"`
+	IsTrusted   *bool   `json:"is_trusted,omitempty"`
+}
+
+type ChannelBookmarkImportData struct {
+	Team        *string `json:"team"`
+	Channel     *string `json:"channel"`
+	Owner       *string `json:"owner"`
+	DisplayName *string `json:"display_name"`
+	Type        *string `json:"type"`
+
+	LinkUrl   *string               `json:"link_url,omitempty"`
+	File      *AttachmentImportData `json:"file,omitempty"`
+	ImageUrl  *string               `json:"image_url,omitempty"`
+	Emoji     *string               `json:"emoji,omitempty"`
+	SortOrder *int64                `json:"sort_order,omitempty"`
+}
+
+type AttachmentImportData struct {
+	Path *string   `json:"path"`
+	Data *zip.File `json:"-"`
+}
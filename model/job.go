@@ -27,6 +27,8 @@ const (
 	JobTypeCloud                        = "cloud"
 	JobTypeResendInvitationEmail        = "resend_invitation_email"
 	JobTypeExtractContent               = "extract_content"
+	JobTypeScheduledPosts               = "scheduled_posts"
+	JobTypeReminders                    = "reminders"
 
 	JobStatusPending         = "pending"
 	JobStatusInProgress      = "in_progress"
@@ -55,6 +57,8 @@ var AllJobTypes = [...]string{
 	JobTypeExportDelete,
 	JobTypeCloud,
 	JobTypeExtractContent,
+	JobTypeScheduledPosts,
+	JobTypeReminders,
 }
 
 type Job struct {
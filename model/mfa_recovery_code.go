@@ -0,0 +1,66 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+)
+
+const (
+	MfaResetStatusCompleted = "completed"
+	MfaResetStatusPending   = "pending"
+)
+
+// MfaRecoveryCode is a one-time code with which a user completes the multi-factor
+// authentication without the device generating the tokens. Only the hash of the code is
+// stored.
+type MfaRecoveryCode struct {
+	Id       string `json:"id"`
+	UserId   string `json:"user_id"`
+	CodeHash string `json:"-"`
+	CreateAt int64  `json:"create_at"`
+	UsedAt   int64  `json:"used_at"`
+}
+
+// MfaRecoveryCodes are the recovery codes returned once, when they are generated.
+type MfaRecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// MfaResetRequest is a reset of the multi-factor authentication requested by a user, which
+// awaits the approval of an administrator.
+type MfaResetRequest struct {
+	UserId   string `json:"user_id"`
+	CreateAt int64  `json:"create_at"`
+}
+
+func (o *MfaRecoveryCode) IsValid() *AppError {
+	if !IsValidId(o.Id) {
+		return NewAppError("MfaRecoveryCode.IsValid", "model.mfa_recovery_code.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.UserId) {
+		return NewAppError("MfaRecoveryCode.IsValid", "model.mfa_recovery_code.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.CodeHash) != 64 {
+		return NewAppError("MfaRecoveryCode.IsValid", "model.mfa_recovery_code.is_valid.code_hash.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("MfaRecoveryCode.IsValid", "model.mfa_recovery_code.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *MfaRecoveryCode) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMfaRecoveryCodeIsValid(t *testing.T) {
+	code := &MfaRecoveryCode{
+		UserId:   NewId(),
+		CodeHash: strings.Repeat("a", 64),
+	}
+	require.NotNil(t, code.IsValid())
+
+	code.PreSave()
+	require.Nil(t, code.IsValid())
+
+	code.CodeHash = "abcd"
+	appErr := code.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.mfa_recovery_code.is_valid.code_hash.app_error", appErr.Id)
+	code.CodeHash = strings.Repeat("a", 64)
+
+	code.UserId = "invalid"
+	appErr = code.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.mfa_recovery_code.is_valid.user_id.app_error", appErr.Id)
+}
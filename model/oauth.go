@@ -30,6 +30,10 @@ type OAuthApp struct {
 	Homepage        string      `json:"homepage"`
 	IsTrusted       bool        `json:"is_trusted"`
 	MatterfossAppID string      `json:"matterfoss_app_id"`
+
+	// IsPublic is set for the apps which can't keep their client secret confidential, such
+	// as single-page and native apps. They must use PKCE rather than their client secret.
+	IsPublic bool `json:"is_public"`
 }
 
 // IsValid validates the app and returns an error if it isn't configured
@@ -0,0 +1,112 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+const (
+	OpenIDDiscoveryPath = "/.well-known/openid-configuration"
+	OpenIDJWKSPath      = "/oauth/jwks"
+	OAuthUserInfoPath   = "/oauth/userinfo"
+
+	// OpenIDSigningAlgorithm is the algorithm the ID tokens are signed with.
+	OpenIDSigningAlgorithm = "RS256"
+
+	// OpenIDTokenExpireTime is how long the ID tokens are valid, in seconds.
+	OpenIDTokenExpireTime = 60 * 60 // 1 hour
+)
+
+// OpenIDProviderMetadata is the discovery document of the OpenID Provider, as defined by
+// OpenID Connect Discovery 1.0.
+type OpenIDProviderMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksURI                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// NewOpenIDProviderMetadata returns the discovery document of the OpenID Provider served at
+// the given site URL, which is the issuer of its ID tokens.
+func NewOpenIDProviderMetadata(siteURL string) *OpenIDProviderMetadata {
+	return &OpenIDProviderMetadata{
+		Issuer:                            siteURL,
+		AuthorizationEndpoint:             siteURL + "/oauth/authorize",
+		TokenEndpoint:                     siteURL + "/oauth/access_token",
+		UserinfoEndpoint:                  siteURL + OAuthUserInfoPath,
+		JwksURI:                           siteURL + OpenIDJWKSPath,
+		IntrospectionEndpoint:             siteURL + "/oauth/introspect",
+		RevocationEndpoint:                siteURL + "/oauth/revoke",
+		ScopesSupported:                   []string{OAuthScopeOpenID, OAuthScopeProfile, OAuthScopeEmail, OAuthScopeUser, OAuthScopeRead, OAuthScopeReadUsers, OAuthScopeWriteUsers, OAuthScopeReadTeams, OAuthScopeWriteTeams, OAuthScopeReadChannels, OAuthScopeWriteChannels, OAuthScopeReadPosts, OAuthScopeWritePosts, OAuthScopeReadFiles, OAuthScopeWriteFiles},
+		ResponseTypesSupported:            []string{AuthCodeResponseType, ImplicitResponseType},
+		GrantTypesSupported:               []string{AccessTokenGrantType, RefreshTokenGrantType, "implicit"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{OpenIDSigningAlgorithm},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{PKCEMethodS256, PKCEMethodPlain},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "nonce", "name", "given_name", "family_name", "preferred_username", "nickname", "picture", "locale", "updated_at", "email", "email_verified"},
+	}
+}
+
+// OpenIDUserInfo holds the standard claims of a user, as returned by the userinfo endpoint
+// and added to the ID tokens.
+type OpenIDUserInfo struct {
+	Sub               string `json:"sub"`
+	Name              string `json:"name,omitempty"`
+	GivenName         string `json:"given_name,omitempty"`
+	FamilyName        string `json:"family_name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Nickname          string `json:"nickname,omitempty"`
+	Picture           string `json:"picture,omitempty"`
+	Locale            string `json:"locale,omitempty"`
+	UpdatedAt         int64  `json:"updated_at,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
+}
+
+// NewOpenIDUserInfo returns the claims of the user allowed by the given space-delimited
+// scope. The profile claims need OAuthScopeProfile and the e-mail ones OAuthScopeEmail.
+func NewOpenIDUserInfo(user *User, scope, siteURL string) *OpenIDUserInfo {
+	info := &OpenIDUserInfo{Sub: user.Id}
+
+	if IsOAuthScopeGranted(scope, OAuthScopeProfile) {
+		info.Name = user.GetFullName()
+		info.GivenName = user.FirstName
+		info.FamilyName = user.LastName
+		info.PreferredUsername = user.Username
+		info.Nickname = user.Nickname
+		info.Picture = siteURL + APIURLSuffix + "/users/" + user.Id + "/image"
+		info.Locale = user.Locale
+		info.UpdatedAt = user.UpdateAt / 1000
+	}
+
+	if IsOAuthScopeGranted(scope, OAuthScopeEmail) {
+		info.Email = user.Email
+		info.EmailVerified = NewBool(user.EmailVerified)
+	}
+
+	return info
+}
+
+// JSONWebKey is a public key of a JSON Web Key Set, as defined by RFC 7517.
+type JSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JSONWebKeySet struct {
+	Keys []*JSONWebKey `json:"keys"`
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOpenIDUserInfo(t *testing.T) {
+	user := &User{
+		Id:            NewId(),
+		Username:      "jdoe",
+		FirstName:     "John",
+		LastName:      "Doe",
+		Nickname:      "johnny",
+		Email:         "jdoe@example.com",
+		EmailVerified: true,
+		Locale:        "fr",
+		UpdateAt:      1600000000123,
+	}
+
+	t.Run("openid scope only", func(t *testing.T) {
+		info := NewOpenIDUserInfo(user, OAuthScopeOpenID, "http://localhost:8065")
+		assert.Equal(t, &OpenIDUserInfo{Sub: user.Id}, info)
+	})
+
+	t.Run("profile scope", func(t *testing.T) {
+		info := NewOpenIDUserInfo(user, "openid profile", "http://localhost:8065")
+		assert.Equal(t, user.Id, info.Sub)
+		assert.Equal(t, "John Doe", info.Name)
+		assert.Equal(t, "John", info.GivenName)
+		assert.Equal(t, "Doe", info.FamilyName)
+		assert.Equal(t, "jdoe", info.PreferredUsername)
+		assert.Equal(t, "johnny", info.Nickname)
+		assert.Equal(t, "http://localhost:8065/api/v4/users/"+user.Id+"/image", info.Picture)
+		assert.Equal(t, "fr", info.Locale)
+		assert.Equal(t, int64(1600000000), info.UpdatedAt)
+		assert.Empty(t, info.Email)
+		assert.Nil(t, info.EmailVerified)
+	})
+
+	t.Run("email scope", func(t *testing.T) {
+		info := NewOpenIDUserInfo(user, "openid email", "http://localhost:8065")
+		assert.Empty(t, info.PreferredUsername)
+		assert.Equal(t, "jdoe@example.com", info.Email)
+		require.NotNil(t, info.EmailVerified)
+		assert.True(t, *info.EmailVerified)
+	})
+}
+
+func TestNewOpenIDProviderMetadata(t *testing.T) {
+	metadata := NewOpenIDProviderMetadata("http://localhost:8065/chat")
+	assert.Equal(t, "http://localhost:8065/chat", metadata.Issuer)
+	assert.Equal(t, "http://localhost:8065/chat/oauth/authorize", metadata.AuthorizationEndpoint)
+	assert.Equal(t, "http://localhost:8065/chat/oauth/access_token", metadata.TokenEndpoint)
+	assert.Equal(t, "http://localhost:8065/chat/oauth/userinfo", metadata.UserinfoEndpoint)
+	assert.Equal(t, "http://localhost:8065/chat/oauth/jwks", metadata.JwksURI)
+
+	for _, scope := range metadata.ScopesSupported {
+		assert.True(t, IsValidOAuthScope(scope), scope)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"strings"
+)
+
+// The scopes which can be granted to an OAuth 2.0 app. OAuthScopeUser grants full access to
+// the API on behalf of the user, and is granted when no known scope is requested so that the
+// apps created before scopes were enforced keep working. The OpenID Connect scopes only grant
+// access to the identity of the user, and are never granted by the other scopes.
+const (
+	OAuthScopeUser          = DefaultScope
+	OAuthScopeRead          = "read"
+	OAuthScopeReadUsers     = "read:users"
+	OAuthScopeWriteUsers    = "write:users"
+	OAuthScopeReadTeams     = "read:teams"
+	OAuthScopeWriteTeams    = "write:teams"
+	OAuthScopeReadChannels  = "read:channels"
+	OAuthScopeWriteChannels = "write:channels"
+	OAuthScopeReadPosts     = "read:posts"
+	OAuthScopeWritePosts    = "write:posts"
+	OAuthScopeReadFiles     = "read:files"
+	OAuthScopeWriteFiles    = "write:files"
+	OAuthScopeOpenID        = "openid"
+	OAuthScopeProfile       = "profile"
+	OAuthScopeEmail         = "email"
+
+	oauthScopeReadPrefix  = "read:"
+	oauthScopeWritePrefix = "write:"
+)
+
+var oauthScopes = map[string]bool{
+	OAuthScopeUser:          true,
+	OAuthScopeRead:          true,
+	OAuthScopeReadUsers:     true,
+	OAuthScopeWriteUsers:    true,
+	OAuthScopeReadTeams:     true,
+	OAuthScopeWriteTeams:    true,
+	OAuthScopeReadChannels:  true,
+	OAuthScopeWriteChannels: true,
+	OAuthScopeReadPosts:     true,
+	OAuthScopeWritePosts:    true,
+	OAuthScopeReadFiles:     true,
+	OAuthScopeWriteFiles:    true,
+	OAuthScopeOpenID:        true,
+	OAuthScopeProfile:       true,
+	OAuthScopeEmail:         true,
+}
+
+var oauthOpenIDScopes = map[string]bool{
+	OAuthScopeOpenID:  true,
+	OAuthScopeProfile: true,
+	OAuthScopeEmail:   true,
+}
+
+// oauthScopeResources are the resources of the API which can be accessed with a scope of their
+// own, named after the first segment of their routes.
+var oauthScopeResources = map[string]bool{
+	"users":    true,
+	"teams":    true,
+	"channels": true,
+	"posts":    true,
+	"files":    true,
+}
+
+// IsValidOAuthScope returns whether the scope is one which can be granted to an OAuth 2.0 app.
+func IsValidOAuthScope(scope string) bool {
+	return oauthScopes[scope]
+}
+
+// NormalizeOAuthScope returns the known scopes of the given space-delimited scope, without
+// duplicates. Unknown scopes are ignored as allowed by RFC 6749, and DefaultScope is returned
+// when no known scope remains.
+func NormalizeOAuthScope(scope string) string {
+	var scopes []string
+	seen := map[string]bool{}
+	for _, s := range strings.Fields(scope) {
+		if IsValidOAuthScope(s) && !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return DefaultScope
+	}
+	return strings.Join(scopes, " ")
+}
+
+// OAuthScopeForRequest returns the scope needed by an OAuth 2.0 access token to be used for
+// a request with the given method to the route with the given path template. The resource of
+// the route is the last one named in its path, so that the posts of a channel are read with
+// OAuthScopeReadPosts. Routes of no resource need OAuthScopeRead, or OAuthScopeUser when they
+// modify anything. The userinfo endpoint needs OAuthScopeOpenID.
+func OAuthScopeForRequest(method, pathTemplate string) string {
+	if strings.HasSuffix(pathTemplate, OAuthUserInfoPath) {
+		return OAuthScopeOpenID
+	}
+
+	resource := ""
+	for _, segment := range strings.Split(pathTemplate, "/") {
+		if oauthScopeResources[segment] {
+			resource = segment
+		}
+	}
+
+	readOnly := method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+	switch {
+	case resource == "" && readOnly:
+		return OAuthScopeRead
+	case resource == "":
+		return OAuthScopeUser
+	case readOnly:
+		return oauthScopeReadPrefix + resource
+	default:
+		return oauthScopeWritePrefix + resource
+	}
+}
+
+// IsOAuthScopeGranted returns whether the given space-delimited scopes grant the required
+// scope. The write scope of a resource grants its read scope too, while the OpenID Connect
+// scopes are only granted by themselves.
+func IsOAuthScopeGranted(granted, required string) bool {
+	for _, scope := range strings.Fields(granted) {
+		switch {
+		case scope == required:
+			return true
+		case oauthOpenIDScopes[required]:
+			continue
+		case scope == OAuthScopeUser:
+			return true
+		case scope == OAuthScopeRead && (required == OAuthScopeRead || strings.HasPrefix(required, oauthScopeReadPrefix)):
+			return true
+		case strings.HasPrefix(required, oauthScopeReadPrefix) && scope == oauthScopeWritePrefix+strings.TrimPrefix(required, oauthScopeReadPrefix):
+			return true
+		}
+	}
+	return false
+}
+
+// IsOAuthScopeSubset returns whether all the given space-delimited scopes are granted by the
+// given space-delimited granted scopes.
+func IsOAuthScopeSubset(scope, granted string) bool {
+	for _, s := range strings.Fields(scope) {
+		if !IsOAuthScopeGranted(granted, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeOAuthScope(t *testing.T) {
+	assert.Equal(t, DefaultScope, NormalizeOAuthScope(""))
+	assert.Equal(t, DefaultScope, NormalizeOAuthScope("all"))
+	assert.Equal(t, "read:posts write:channels", NormalizeOAuthScope("read:posts  all write:channels read:posts"))
+	assert.Equal(t, "openid email", NormalizeOAuthScope("openid email"))
+}
+
+func TestOAuthScopeForRequest(t *testing.T) {
+	assert.Equal(t, OAuthScopeReadUsers, OAuthScopeForRequest(http.MethodGet, "/api/v4/users/me"))
+	assert.Equal(t, OAuthScopeWriteUsers, OAuthScopeForRequest(http.MethodPut, "/api/v4/users/{user_id:[A-Za-z0-9]+}/patch"))
+	assert.Equal(t, OAuthScopeReadPosts, OAuthScopeForRequest(http.MethodGet, "/api/v4/channels/{channel_id:[A-Za-z0-9]+}/posts"))
+	assert.Equal(t, OAuthScopeWriteFiles, OAuthScopeForRequest(http.MethodPost, "/api/v4/files"))
+	assert.Equal(t, OAuthScopeRead, OAuthScopeForRequest(http.MethodGet, "/api/v4/emoji"))
+	assert.Equal(t, OAuthScopeUser, OAuthScopeForRequest(http.MethodDelete, "/api/v4/emoji/{emoji_id:[A-Za-z0-9]+}"))
+	assert.Equal(t, OAuthScopeOpenID, OAuthScopeForRequest(http.MethodGet, OAuthUserInfoPath))
+	assert.Equal(t, OAuthScopeOpenID, OAuthScopeForRequest(http.MethodPost, "/chat"+OAuthUserInfoPath))
+}
+
+func TestIsOAuthScopeGranted(t *testing.T) {
+	for _, tc := range []struct {
+		granted  string
+		required string
+		expected bool
+	}{
+		{OAuthScopeUser, OAuthScopeWritePosts, true},
+		{OAuthScopeUser, OAuthScopeUser, true},
+		{OAuthScopeRead, OAuthScopeReadPosts, true},
+		{OAuthScopeRead, OAuthScopeRead, true},
+		{OAuthScopeRead, OAuthScopeWritePosts, false},
+		{OAuthScopeRead, OAuthScopeUser, false},
+		{OAuthScopeWritePosts, OAuthScopeReadPosts, true},
+		{OAuthScopeReadPosts, OAuthScopeWritePosts, false},
+		{"read:users write:posts", OAuthScopeWritePosts, true},
+		{"read:users write:posts", OAuthScopeReadChannels, false},
+		{"read:users write:posts", OAuthScopeRead, false},
+		{"", OAuthScopeRead, false},
+		{"openid profile", OAuthScopeOpenID, true},
+		{"openid profile", OAuthScopeEmail, false},
+		{"openid profile", OAuthScopeRead, false},
+		{OAuthScopeUser, OAuthScopeOpenID, false},
+		{OAuthScopeRead, OAuthScopeProfile, false},
+	} {
+		assert.Equal(t, tc.expected, IsOAuthScopeGranted(tc.granted, tc.required), "granted=%q required=%q", tc.granted, tc.required)
+	}
+
+	assert.True(t, IsOAuthScopeSubset("read:posts read:users", "read"))
+	assert.False(t, IsOAuthScopeSubset("read:posts write:users", "read"))
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package oauthoffice365
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+const commonTenantPath = "/common/"
+
+// multiTenantPaths are the tenant paths of the Microsoft identity platform endpoints
+// accepting the accounts of any directory. The e-mail addresses of those accounts are
+// set by the administrators of their directories, and can't be trusted.
+var multiTenantPaths = []string{commonTenantPath, "/organizations/", "/consumers/"}
+
+type Office365Provider struct {
+}
+
+// Office365User is the user returned by the /me endpoint of Microsoft Graph.
+type Office365User struct {
+	Id          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	GivenName   string `json:"givenName"`
+	Surname     string `json:"surname"`
+	Mail        string `json:"mail"`
+}
+
+func init() {
+	provider := &Office365Provider{}
+	einterfaces.RegisterOAuthProvider(model.ServiceOffice365, provider)
+}
+
+func userFromOffice365User(ou *Office365User) *model.User {
+	user := &model.User{}
+	email := ou.Mail
+	user.Username = model.CleanUsername(strings.Split(email, "@")[0])
+
+	user.FirstName = ou.GivenName
+	user.LastName = ou.Surname
+	if user.FirstName == "" && user.LastName == "" {
+		splitName := strings.SplitN(ou.DisplayName, " ", 2)
+		user.FirstName = splitName[0]
+		if len(splitName) == 2 {
+			user.LastName = splitName[1]
+		}
+	}
+
+	user.Email = strings.ToLower(email)
+	userId := ou.Id
+	user.AuthData = &userId
+	user.AuthService = model.ServiceOffice365
+
+	return user
+}
+
+func office365UserFromJSON(data io.Reader) (*Office365User, error) {
+	decoder := json.NewDecoder(data)
+	var ou Office365User
+	err := decoder.Decode(&ou)
+	if err != nil {
+		return nil, err
+	}
+	return &ou, nil
+}
+
+func (ou *Office365User) IsValid() error {
+	if ou.Id == "" {
+		return errors.New("user id should not be empty")
+	}
+
+	// The user principal name isn't used as the e-mail address, as it is not always one.
+	if ou.Mail == "" {
+		return errors.New("user e-mail should not be empty")
+	}
+
+	return nil
+}
+
+func (m *Office365Provider) GetUserFromJSON(data io.Reader, tokenUser *model.User) (*model.User, error) {
+	ou, err := office365UserFromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = ou.IsValid(); err != nil {
+		return nil, err
+	}
+
+	return userFromOffice365User(ou), nil
+}
+
+// GetSSOSettings returns the settings of the service, restricting the endpoints to the
+// configured directory so that only its accounts can sign in. The multi-tenant endpoints
+// are refused, as they would let the accounts of any directory sign in with an e-mail
+// address of their choice.
+func (m *Office365Provider) GetSSOSettings(config *model.Config, service string) (*model.SSOSettings, error) {
+	settings := config.Office365Settings.SSOSettings()
+
+	if directoryId := *config.Office365Settings.DirectoryId; directoryId != "" {
+		tenantPath := "/" + directoryId + "/"
+		settings.AuthEndpoint = model.NewString(strings.Replace(*settings.AuthEndpoint, commonTenantPath, tenantPath, 1))
+		settings.TokenEndpoint = model.NewString(strings.Replace(*settings.TokenEndpoint, commonTenantPath, tenantPath, 1))
+	}
+
+	for _, path := range multiTenantPaths {
+		if strings.Contains(*settings.AuthEndpoint, path) || strings.Contains(*settings.TokenEndpoint, path) {
+			return nil, errors.New("the directory id should be set, as the multi-tenant endpoints are not supported")
+		}
+	}
+
+	return settings, nil
+}
+
+func (m *Office365Provider) GetUserFromIdToken(idToken string) (*model.User, error) {
+	return nil, nil
+}
+
+func (m *Office365Provider) IsSameUser(dbUser, oauthUser *model.User) bool {
+	return dbUser.AuthData != nil && oauthUser.AuthData != nil && *dbUser.AuthData == *oauthUser.AuthData
+}
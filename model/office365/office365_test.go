@@ -0,0 +1,77 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package oauthoffice365
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func TestGetUserFromJSON(t *testing.T) {
+	provider := &Office365Provider{}
+
+	t.Run("should map the user", func(t *testing.T) {
+		data := `{"id": "abcd", "displayName": "Jane Doe", "givenName": "Jane", "surname": "Doe", "mail": "Jane.Doe@Example.com", "userPrincipalName": "jdoe@example.onmicrosoft.com"}`
+		user, err := provider.GetUserFromJSON(strings.NewReader(data), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "abcd", *user.AuthData)
+		assert.Equal(t, model.ServiceOffice365, user.AuthService)
+		assert.Equal(t, "jane.doe@example.com", user.Email)
+		assert.Equal(t, "jane.doe", user.Username)
+		assert.Equal(t, "Jane", user.FirstName)
+		assert.Equal(t, "Doe", user.LastName)
+	})
+
+	t.Run("should not use the user principal name as the e-mail", func(t *testing.T) {
+		data := `{"id": "abcd", "displayName": "Jane Doe", "userPrincipalName": "jdoe@example.onmicrosoft.com"}`
+		_, err := provider.GetUserFromJSON(strings.NewReader(data), nil)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject a user without an e-mail", func(t *testing.T) {
+		_, err := provider.GetUserFromJSON(strings.NewReader(`{"id": "abcd"}`), nil)
+		require.Error(t, err)
+	})
+}
+
+func TestGetSSOSettings(t *testing.T) {
+	provider := &Office365Provider{}
+	config := &model.Config{}
+	config.SetDefaults()
+
+	t.Run("should refuse the multi-tenant endpoints", func(t *testing.T) {
+		_, err := provider.GetSSOSettings(config, model.ServiceOffice365)
+		require.Error(t, err)
+
+		organizationsConfig := config.Clone()
+		organizationsConfig.Office365Settings.AuthEndpoint = model.NewString("https://login.microsoftonline.com/organizations/oauth2/v2.0/authorize")
+		organizationsConfig.Office365Settings.TokenEndpoint = model.NewString("https://login.microsoftonline.com/organizations/oauth2/v2.0/token")
+		_, err = provider.GetSSOSettings(organizationsConfig, model.ServiceOffice365)
+		require.Error(t, err)
+	})
+
+	t.Run("should restrict the endpoints to the directory", func(t *testing.T) {
+		directoryConfig := config.Clone()
+		directoryConfig.Office365Settings.DirectoryId = model.NewString("tenant-id")
+		settings, err := provider.GetSSOSettings(directoryConfig, model.ServiceOffice365)
+		require.NoError(t, err)
+		assert.Equal(t, "https://login.microsoftonline.com/tenant-id/oauth2/v2.0/authorize", *settings.AuthEndpoint)
+		assert.Equal(t, "https://login.microsoftonline.com/tenant-id/oauth2/v2.0/token", *settings.TokenEndpoint)
+		assert.Equal(t, model.Office365SettingsDefaultAuthEndpoint, *directoryConfig.Office365Settings.AuthEndpoint)
+	})
+
+	t.Run("should accept the endpoints of a directory", func(t *testing.T) {
+		tenantConfig := config.Clone()
+		tenantConfig.Office365Settings.AuthEndpoint = model.NewString("https://login.microsoftonline.com/tenant-id/oauth2/v2.0/authorize")
+		tenantConfig.Office365Settings.TokenEndpoint = model.NewString("https://login.microsoftonline.com/tenant-id/oauth2/v2.0/token")
+		settings, err := provider.GetSSOSettings(tenantConfig, model.ServiceOffice365)
+		require.NoError(t, err)
+		assert.Equal(t, "https://login.microsoftonline.com/tenant-id/oauth2/v2.0/authorize", *settings.AuthEndpoint)
+	})
+}
@@ -0,0 +1,440 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package oauthopenid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+const (
+	DiscoveryPath = "/.well-known/openid-configuration"
+
+	cacheTTL             = time.Hour
+	keySetRefreshMinWait = 30 * time.Second
+	requestTimeout       = 30 * time.Second
+	maxResponseSize      = 1 << 20
+)
+
+// signingMethods are the algorithms the ID tokens may be signed with. The symmetric and
+// none algorithms are excluded, since the keys come from the key set of the provider.
+var signingMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// Discovery is the part of the OpenID Provider metadata used to sign users in.
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+func (d *Discovery) IsValid() error {
+	if d.Issuer == "" {
+		return errors.New("issuer should not be empty")
+	}
+
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.UserinfoEndpoint == "" {
+		return errors.New("authorization, token and userinfo endpoints should not be empty")
+	}
+
+	if d.JwksURI == "" {
+		return errors.New("jwks_uri should not be empty")
+	}
+
+	return nil
+}
+
+// OpenIdUser holds the standard claims of the ID tokens and of the userinfo responses.
+type OpenIdUser struct {
+	Sub               string      `json:"sub"`
+	Email             string      `json:"email"`
+	EmailVerified     interface{} `json:"email_verified"`
+	PreferredUsername string      `json:"preferred_username"`
+	Nickname          string      `json:"nickname"`
+	Name              string      `json:"name"`
+	GivenName         string      `json:"given_name"`
+	FamilyName        string      `json:"family_name"`
+}
+
+// trustedIssuer is an issuer of a configured provider, along with the clients its ID
+// tokens may be issued to.
+type trustedIssuer struct {
+	jwksURI   string
+	clientIds map[string]bool
+}
+
+type cachedDiscovery struct {
+	discovery *Discovery
+	fetchedAt time.Time
+}
+
+type cachedKeySet struct {
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+type OpenIdProvider struct {
+	client *http.Client
+
+	mutex       sync.Mutex
+	discoveries map[string]*cachedDiscovery
+	issuers     map[string]*trustedIssuer
+	keySets     map[string]*cachedKeySet
+}
+
+func init() {
+	einterfaces.RegisterOAuthProvider(model.ServiceOpenid, NewOpenIdProvider(&http.Client{Timeout: requestTimeout}))
+}
+
+func NewOpenIdProvider(client *http.Client) *OpenIdProvider {
+	return &OpenIdProvider{
+		client:      client,
+		discoveries: make(map[string]*cachedDiscovery),
+		issuers:     make(map[string]*trustedIssuer),
+		keySets:     make(map[string]*cachedKeySet),
+	}
+}
+
+func userFromOpenIdUser(ou *OpenIdUser) *model.User {
+	user := &model.User{}
+
+	username := ou.PreferredUsername
+	if username == "" {
+		username = ou.Nickname
+	}
+	if username == "" {
+		username = ou.Email
+	}
+	if i := strings.Index(username, "@"); i > 0 {
+		username = username[:i]
+	}
+	user.Username = model.CleanUsername(username)
+
+	user.FirstName = ou.GivenName
+	user.LastName = ou.FamilyName
+	if user.FirstName == "" && user.LastName == "" {
+		splitName := strings.SplitN(strings.TrimSpace(ou.Name), " ", 2)
+		user.FirstName = splitName[0]
+		if len(splitName) == 2 {
+			user.LastName = splitName[1]
+		}
+	}
+
+	user.Nickname = ou.Nickname
+	user.Email = strings.ToLower(ou.Email)
+	sub := ou.Sub
+	user.AuthData = &sub
+
+	return user
+}
+
+func (ou *OpenIdUser) IsValid() error {
+	if ou.Sub == "" {
+		return errors.New("user subject should not be empty")
+	}
+
+	if ou.Email == "" {
+		return errors.New("user e-mail should not be empty")
+	}
+
+	// The e-mail address is trusted as verified by the provider unless it says otherwise.
+	switch verified := ou.EmailVerified.(type) {
+	case bool:
+		if !verified {
+			return errors.New("user e-mail is not verified")
+		}
+	case string:
+		if verified == "false" {
+			return errors.New("user e-mail is not verified")
+		}
+	}
+
+	return nil
+}
+
+// GetUserFromJSON reads the user from the response of the userinfo endpoint. The claims
+// of the ID token fill in the ones the endpoint doesn't return.
+func (p *OpenIdProvider) GetUserFromJSON(data io.Reader, tokenUser *model.User) (*model.User, error) {
+	var ou OpenIdUser
+	if err := json.NewDecoder(data).Decode(&ou); err != nil {
+		return nil, err
+	}
+
+	if tokenUser != nil {
+		if tokenUser.AuthData == nil || *tokenUser.AuthData != ou.Sub {
+			return nil, errors.New("the subject of the userinfo response doesn't match the ID token")
+		}
+
+		if ou.Email == "" {
+			ou.Email = tokenUser.Email
+		}
+		if ou.PreferredUsername == "" && ou.Nickname == "" {
+			ou.PreferredUsername = tokenUser.Username
+		}
+		if ou.GivenName == "" && ou.FamilyName == "" && ou.Name == "" {
+			ou.GivenName = tokenUser.FirstName
+			ou.FamilyName = tokenUser.LastName
+		}
+	}
+
+	if err := ou.IsValid(); err != nil {
+		return nil, err
+	}
+
+	return userFromOpenIdUser(&ou), nil
+}
+
+// GetSSOSettings returns the settings of the service, with the endpoints found by the
+// discovery of its OpenID Provider. The issuer of the provider is then trusted to sign the
+// ID tokens of the service.
+func (p *OpenIdProvider) GetSSOSettings(config *model.Config, service string) (*model.SSOSettings, error) {
+	sso := config.GetSSOService(service)
+	if sso == nil {
+		return nil, fmt.Errorf("unknown service %s", service)
+	}
+
+	discoveryURL := discoveryEndpoint(*sso.DiscoveryEndpoint)
+	if discoveryURL == "" {
+		return nil, fmt.Errorf("no discovery endpoint configured for service %s", service)
+	}
+
+	discovery, err := p.getDiscovery(discoveryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	p.trustIssuer(discovery, *sso.Id)
+
+	settings := *sso
+	settings.AuthEndpoint = model.NewString(discovery.AuthorizationEndpoint)
+	settings.TokenEndpoint = model.NewString(discovery.TokenEndpoint)
+	settings.UserAPIEndpoint = model.NewString(discovery.UserinfoEndpoint)
+
+	return &settings, nil
+}
+
+// GetUserFromIdToken validates the ID token against the keys of its issuer, which must be
+// the provider of a configured service, and reads the user from its claims.
+func (p *OpenIdProvider) GetUserFromIdToken(idToken string) (*model.User, error) {
+	claims := jwt.MapClaims{}
+	parser := &jwt.Parser{ValidMethods: signingMethods}
+
+	var issuer *trustedIssuer
+	_, err := parser.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		var err error
+		if issuer, err = p.getTrustedIssuer(claims); err != nil {
+			return nil, err
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(issuer.jwksURI, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	if !issuer.isAudience(claims) {
+		return nil, errors.New("invalid ID token: the token was not issued to this client")
+	}
+
+	// The parser only checks exp and iat when they are present, while the ID tokens must
+	// have both.
+	if !hasTimeClaim(claims, "exp") || !hasTimeClaim(claims, "iat") {
+		return nil, errors.New("invalid ID token: the expiration and issue times should not be empty")
+	}
+
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	var ou OpenIdUser
+	if err := json.Unmarshal(b, &ou); err != nil {
+		return nil, err
+	}
+
+	if ou.Sub == "" {
+		return nil, errors.New("invalid ID token: the subject should not be empty")
+	}
+
+	return userFromOpenIdUser(&ou), nil
+}
+
+func (p *OpenIdProvider) IsSameUser(dbUser, oauthUser *model.User) bool {
+	return dbUser.AuthData != nil && oauthUser.AuthData != nil && *dbUser.AuthData == *oauthUser.AuthData
+}
+
+// discoveryEndpoint returns the URL of the OpenID Provider metadata of the service. The
+// configured endpoint may be the issuer, to which the well-known path is added.
+func discoveryEndpoint(endpoint string) string {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint != "" && !strings.HasSuffix(endpoint, DiscoveryPath) {
+		endpoint = strings.TrimSuffix(endpoint, "/") + DiscoveryPath
+	}
+	return endpoint
+}
+
+func (p *OpenIdProvider) getDiscovery(discoveryURL string) (*Discovery, error) {
+	p.mutex.Lock()
+	cached, ok := p.discoveries[discoveryURL]
+	p.mutex.Unlock()
+	if ok && time.Since(cached.fetchedAt) < cacheTTL {
+		return cached.discovery, nil
+	}
+
+	var discovery Discovery
+	if err := p.getJSON(discoveryURL, &discovery); err != nil {
+		return nil, fmt.Errorf("could not fetch the OpenID Provider metadata: %w", err)
+	}
+	if err := discovery.IsValid(); err != nil {
+		return nil, fmt.Errorf("invalid OpenID Provider metadata: %w", err)
+	}
+
+	p.mutex.Lock()
+	p.discoveries[discoveryURL] = &cachedDiscovery{discovery: &discovery, fetchedAt: time.Now()}
+	p.mutex.Unlock()
+
+	return &discovery, nil
+}
+
+func (p *OpenIdProvider) trustIssuer(discovery *Discovery, clientId string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	issuer, ok := p.issuers[discovery.Issuer]
+	if !ok || issuer.jwksURI != discovery.JwksURI {
+		issuer = &trustedIssuer{jwksURI: discovery.JwksURI, clientIds: make(map[string]bool)}
+		p.issuers[discovery.Issuer] = issuer
+	}
+	issuer.clientIds[clientId] = true
+}
+
+func (p *OpenIdProvider) getTrustedIssuer(claims jwt.MapClaims) (*trustedIssuer, error) {
+	iss, _ := claims["iss"].(string)
+	if iss == "" {
+		return nil, errors.New("the issuer should not be empty")
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if issuer, ok := p.issuers[iss]; ok {
+		return issuer, nil
+	}
+
+	return nil, fmt.Errorf("untrusted issuer %s", iss)
+}
+
+func (issuer *trustedIssuer) isAudience(claims jwt.MapClaims) bool {
+	var audience []string
+	switch aud := claims["aud"].(type) {
+	case string:
+		audience = []string{aud}
+	case []interface{}:
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				audience = append(audience, s)
+			}
+		}
+	}
+
+	// When the token has several audiences, the authorized party must be the client.
+	if azp, ok := claims["azp"].(string); ok && len(audience) > 1 {
+		return issuer.clientIds[azp]
+	}
+
+	for _, a := range audience {
+		if issuer.clientIds[a] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTimeClaim(claims jwt.MapClaims, name string) bool {
+	switch value := claims[name].(type) {
+	case float64:
+		return value != 0
+	case json.Number:
+		v, err := value.Float64()
+		return err == nil && v != 0
+	}
+	return false
+}
+
+// getKey returns the key of the key set with the given id. The key set is fetched again
+// when the key is unknown, since the provider may have rotated its keys.
+func (p *OpenIdProvider) getKey(jwksURI, kid string) (interface{}, error) {
+	p.mutex.Lock()
+	cached, ok := p.keySets[jwksURI]
+	p.mutex.Unlock()
+
+	if ok && time.Since(cached.fetchedAt) < cacheTTL {
+		if key, found := findKey(cached.keys, kid); found {
+			return key, nil
+		}
+		if time.Since(cached.fetchedAt) < keySetRefreshMinWait {
+			return nil, fmt.Errorf("unknown key %s", kid)
+		}
+	}
+
+	var keySet jsonWebKeySet
+	if err := p.getJSON(jwksURI, &keySet); err != nil {
+		return nil, fmt.Errorf("could not fetch the key set: %w", err)
+	}
+	keys := keySet.publicKeys()
+
+	p.mutex.Lock()
+	p.keySets[jwksURI] = &cachedKeySet{keys: keys, fetchedAt: time.Now()}
+	p.mutex.Unlock()
+
+	if key, found := findKey(keys, kid); found {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %s", kid)
+}
+
+// findKey returns the key with the given id, or the only key of the set when the token
+// doesn't tell which key signed it.
+func findKey(keys map[string]interface{}, kid string) (interface{}, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	key, ok := keys[kid]
+	return key, ok
+}
+
+func (p *OpenIdProvider) getJSON(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v)
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package oauthopenid
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+const (
+	testClientId = "matterfoss-client"
+	testKeyId    = "test-key"
+)
+
+type stubIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &stubIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc(DiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&Discovery{
+			Issuer:                idp.server.URL,
+			AuthorizationEndpoint: idp.server.URL + "/auth",
+			TokenEndpoint:         idp.server.URL + "/token",
+			UserinfoEndpoint:      idp.server.URL + "/userinfo",
+			JwksURI:               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&jsonWebKeySet{Keys: []jsonWebKey{{
+			Kid: testKeyId,
+			Kty: "RSA",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+		}}})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (idp *stubIdP) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                idp.server.URL,
+		"aud":                testClientId,
+		"sub":                "user-sub",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"iat":                time.Now().Unix(),
+		"email":              "Jane.Doe@Example.com",
+		"email_verified":     true,
+		"preferred_username": "jane.doe",
+		"given_name":         "Jane",
+		"family_name":        "Doe",
+	}
+}
+
+func (idp *stubIdP) sign(t *testing.T, claims jwt.MapClaims, key *rsa.PrivateKey) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKeyId
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func newConfig(discoveryEndpoint string) *model.Config {
+	config := &model.Config{}
+	config.SetDefaults()
+	config.OpenIdSettings.Enable = model.NewBool(true)
+	config.OpenIdSettings.Id = model.NewString(testClientId)
+	config.OpenIdSettings.DiscoveryEndpoint = model.NewString(discoveryEndpoint)
+	return config
+}
+
+func TestGetSSOSettings(t *testing.T) {
+	idp := newStubIdP(t)
+
+	t.Run("should fill the endpoints from the discovery", func(t *testing.T) {
+		provider := NewOpenIdProvider(http.DefaultClient)
+		settings, err := provider.GetSSOSettings(newConfig(idp.server.URL), model.ServiceOpenid)
+		require.NoError(t, err)
+		assert.Equal(t, idp.server.URL+"/auth", *settings.AuthEndpoint)
+		assert.Equal(t, idp.server.URL+"/token", *settings.TokenEndpoint)
+		assert.Equal(t, idp.server.URL+"/userinfo", *settings.UserAPIEndpoint)
+		assert.Equal(t, testClientId, *settings.Id)
+	})
+
+	t.Run("should accept the full discovery endpoint", func(t *testing.T) {
+		provider := NewOpenIdProvider(http.DefaultClient)
+		settings, err := provider.GetSSOSettings(newConfig(idp.server.URL+DiscoveryPath), model.ServiceOpenid)
+		require.NoError(t, err)
+		assert.Equal(t, idp.server.URL+"/auth", *settings.AuthEndpoint)
+	})
+
+	t.Run("should fail without a discovery endpoint", func(t *testing.T) {
+		provider := NewOpenIdProvider(http.DefaultClient)
+		_, err := provider.GetSSOSettings(newConfig(""), model.ServiceOpenid)
+		require.Error(t, err)
+	})
+
+	t.Run("should fail when the discovery fails", func(t *testing.T) {
+		provider := NewOpenIdProvider(http.DefaultClient)
+		_, err := provider.GetSSOSettings(newConfig(idp.server.URL+"/missing"), model.ServiceOpenid)
+		require.Error(t, err)
+	})
+}
+
+func TestGetUserFromIdToken(t *testing.T) {
+	idp := newStubIdP(t)
+	provider := NewOpenIdProvider(http.DefaultClient)
+	_, err := provider.GetSSOSettings(newConfig(idp.server.URL), model.ServiceOpenid)
+	require.NoError(t, err)
+
+	t.Run("should map the claims of a valid token", func(t *testing.T) {
+		user, err := provider.GetUserFromIdToken(idp.sign(t, idp.claims(), idp.key))
+		require.NoError(t, err)
+		assert.Equal(t, "user-sub", *user.AuthData)
+		assert.Equal(t, "jane.doe@example.com", user.Email)
+		assert.Equal(t, "jane.doe", user.Username)
+		assert.Equal(t, "Jane", user.FirstName)
+		assert.Equal(t, "Doe", user.LastName)
+		assert.Empty(t, user.AuthService)
+	})
+
+	t.Run("should accept the client among several audiences", func(t *testing.T) {
+		claims := idp.claims()
+		claims["aud"] = []string{"other-client", testClientId}
+		claims["azp"] = testClientId
+		_, err := provider.GetUserFromIdToken(idp.sign(t, claims, idp.key))
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject a token for another client", func(t *testing.T) {
+		claims := idp.claims()
+		claims["aud"] = "other-client"
+		_, err := provider.GetUserFromIdToken(idp.sign(t, claims, idp.key))
+		require.Error(t, err)
+	})
+
+	t.Run("should reject an expired token", func(t *testing.T) {
+		claims := idp.claims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		_, err := provider.GetUserFromIdToken(idp.sign(t, claims, idp.key))
+		require.Error(t, err)
+	})
+
+	t.Run("should reject a token without expiration time", func(t *testing.T) {
+		claims := idp.claims()
+		delete(claims, "exp")
+		_, err := provider.GetUserFromIdToken(idp.sign(t, claims, idp.key))
+		require.Error(t, err)
+	})
+
+	t.Run("should reject a token without issue time", func(t *testing.T) {
+		claims := idp.claims()
+		claims["iat"] = 0
+		_, err := provider.GetUserFromIdToken(idp.sign(t, claims, idp.key))
+		require.Error(t, err)
+	})
+
+	t.Run("should reject an unknown issuer", func(t *testing.T) {
+		claims := idp.claims()
+		claims["iss"] = "https://idp.example.com"
+		_, err := provider.GetUserFromIdToken(idp.sign(t, claims, idp.key))
+		require.Error(t, err)
+	})
+
+	t.Run("should reject a token signed with another key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = provider.GetUserFromIdToken(idp.sign(t, idp.claims(), otherKey))
+		require.Error(t, err)
+	})
+
+	t.Run("should reject an unsigned token", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodNone, idp.claims())
+		signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		_, err = provider.GetUserFromIdToken(signed)
+		require.Error(t, err)
+	})
+}
+
+func TestGetUserFromJSON(t *testing.T) {
+	provider := NewOpenIdProvider(http.DefaultClient)
+	tokenUser := &model.User{
+		AuthData:  model.NewString("user-sub"),
+		Email:     "jane.doe@example.com",
+		Username:  "jane.doe",
+		FirstName: "Jane",
+		LastName:  "Doe",
+	}
+
+	t.Run("should map the userinfo claims", func(t *testing.T) {
+		user, err := provider.GetUserFromJSON(strings.NewReader(`{"sub":"user-sub","email":"jdoe@example.com","nickname":"jdoe","name":"Janet Q. Doe"}`), tokenUser)
+		require.NoError(t, err)
+		assert.Equal(t, "user-sub", *user.AuthData)
+		assert.Equal(t, "jdoe@example.com", user.Email)
+		assert.Equal(t, "jdoe", user.Username)
+		assert.Equal(t, "Janet", user.FirstName)
+		assert.Equal(t, "Q. Doe", user.LastName)
+	})
+
+	t.Run("should fill the missing claims from the ID token", func(t *testing.T) {
+		user, err := provider.GetUserFromJSON(strings.NewReader(`{"sub":"user-sub"}`), tokenUser)
+		require.NoError(t, err)
+		assert.Equal(t, "jane.doe@example.com", user.Email)
+		assert.Equal(t, "jane.doe", user.Username)
+		assert.Equal(t, "Jane", user.FirstName)
+		assert.Equal(t, "Doe", user.LastName)
+	})
+
+	t.Run("should reject another subject than the ID token", func(t *testing.T) {
+		_, err := provider.GetUserFromJSON(strings.NewReader(`{"sub":"other-sub","email":"jane.doe@example.com"}`), tokenUser)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject an unverified e-mail", func(t *testing.T) {
+		_, err := provider.GetUserFromJSON(strings.NewReader(`{"sub":"user-sub","email":"jane.doe@example.com","email_verified":false}`), nil)
+		require.Error(t, err)
+
+		_, err = provider.GetUserFromJSON(strings.NewReader(`{"sub":"user-sub","email":"jane.doe@example.com","email_verified":"false"}`), nil)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject a user without an e-mail", func(t *testing.T) {
+		_, err := provider.GetUserFromJSON(strings.NewReader(`{"sub":"user-sub"}`), nil)
+		require.Error(t, err)
+	})
+}
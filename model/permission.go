@@ -100,6 +100,7 @@ var PermissionPromoteGuest *Permission
 var PermissionDemoteToGuest *Permission
 var PermissionUseChannelMentions *Permission
 var PermissionUseGroupMentions *Permission
+var PermissionManageChannelBookmarks *Permission
 var PermissionReadOtherUsersTeams *Permission
 var PermissionEditBrand *Permission
 var PermissionManageSharedChannels *Permission
@@ -1147,6 +1148,12 @@ func initializePermissions() {
 		"authentication.permissions.use_group_mentions.description",
 		PermissionScopeChannel,
 	}
+	PermissionManageChannelBookmarks = &Permission{
+		"manage_channel_bookmarks",
+		"authentication.permissions.manage_channel_bookmarks.name",
+		"authentication.permissions.manage_channel_bookmarks.description",
+		PermissionScopeChannel,
+	}
 	PermissionReadOtherUsersTeams = &Permission{
 		"read_other_users_teams",
 		"authentication.permissions.read_other_users_teams.name",
@@ -2308,6 +2315,7 @@ func initializePermissions() {
 		PermissionDeleteOthersPosts,
 		PermissionUseChannelMentions,
 		PermissionUseGroupMentions,
+		PermissionManageChannelBookmarks,
 	}
 
 	GroupScopedPermissions := []*Permission{
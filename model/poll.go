@@ -0,0 +1,164 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	PostTypePoll  = "custom_poll"
+	PostPropsPoll = "poll"
+
+	PollMinOptions     = 2
+	PollMaxOptions     = 20
+	PollOptionMaxRunes = 300
+)
+
+// Poll holds the options of a poll post, which is a post of the PostTypePoll type asking
+// the question of its message. The poll is kept in the PostPropsPoll prop of the post.
+type Poll struct {
+	Options []string `json:"options"`
+	// Anonymous polls don't tell who voted for which option.
+	Anonymous bool `json:"anonymous"`
+	// MultiChoice polls let users vote for several options.
+	MultiChoice bool `json:"multi_choice"`
+	// The time the poll was closed, zero while users can vote.
+	ClosedAt int64 `json:"closed_at"`
+}
+
+func (p *Poll) IsValid() *AppError {
+	if len(p.Options) < PollMinOptions || len(p.Options) > PollMaxOptions {
+		return NewAppError("Poll.IsValid", "model.poll.is_valid.options.app_error", map[string]interface{}{"Min": PollMinOptions, "Max": PollMaxOptions}, "", http.StatusBadRequest)
+	}
+
+	for _, option := range p.Options {
+		if option == "" || utf8.RuneCountInString(option) > PollOptionMaxRunes {
+			return NewAppError("Poll.IsValid", "model.poll.is_valid.option.app_error", map[string]interface{}{"Max": PollOptionMaxRunes}, "", http.StatusBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// IsValidVote tells whether a user can vote for the options at the given indexes. An empty
+// vote withdraws the votes of the user.
+func (p *Poll) IsValidVote(optionIndexes []int) bool {
+	if len(optionIndexes) > 1 && !p.MultiChoice {
+		return false
+	}
+
+	seen := make(map[int]bool, len(optionIndexes))
+	for _, index := range optionIndexes {
+		if index < 0 || index >= len(p.Options) || seen[index] {
+			return false
+		}
+		seen[index] = true
+	}
+	return true
+}
+
+// GetPoll returns the poll of a poll post, or nil if the post has no valid poll.
+func (o *Post) GetPoll() *Poll {
+	if o.Type != PostTypePoll {
+		return nil
+	}
+
+	prop := o.GetProp(PostPropsPoll)
+	if prop == nil {
+		return nil
+	}
+	if poll, ok := prop.(*Poll); ok {
+		return poll
+	}
+
+	// The props read from the database hold the poll as a map.
+	b, err := json.Marshal(prop)
+	if err != nil {
+		return nil
+	}
+	var poll Poll
+	if err := json.Unmarshal(b, &poll); err != nil {
+		return nil
+	}
+	return &poll
+}
+
+// SetPoll makes the post a poll post with the given poll.
+func (o *Post) SetPoll(poll *Poll) {
+	o.Type = PostTypePoll
+	o.AddProp(PostPropsPoll, poll)
+}
+
+// PollVote is the vote of a user for one of the options of a poll. A user votes for at
+// most one option of a poll, unless it is a multiple choice poll.
+type PollVote struct {
+	PostId      string `json:"post_id"`
+	UserId      string `json:"user_id"`
+	OptionIndex int    `json:"option_index"`
+	CreateAt    int64  `json:"create_at"`
+}
+
+func (v *PollVote) IsValid() *AppError {
+	if !IsValidId(v.PostId) {
+		return NewAppError("PollVote.IsValid", "model.poll_vote.is_valid.post_id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(v.UserId) {
+		return NewAppError("PollVote.IsValid", "model.poll_vote.is_valid.user_id.app_error", nil, "post_id="+v.PostId, http.StatusBadRequest)
+	}
+
+	if v.OptionIndex < 0 || v.OptionIndex >= PollMaxOptions {
+		return NewAppError("PollVote.IsValid", "model.poll_vote.is_valid.option_index.app_error", nil, "post_id="+v.PostId, http.StatusBadRequest)
+	}
+
+	if v.CreateAt == 0 {
+		return NewAppError("PollVote.IsValid", "model.poll_vote.is_valid.create_at.app_error", nil, "post_id="+v.PostId, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+// PollResults are the tallies of a poll.
+type PollResults struct {
+	// The number of votes for each option.
+	Counts []int `json:"counts"`
+	// The ids of the users who voted for each option, omitted for anonymous polls.
+	Voters [][]string `json:"voters,omitempty"`
+	// The number of users who voted.
+	TotalVoters int `json:"total_voters"`
+	// The options the user asking for the results voted for. It is only set in the
+	// responses to that user, never in the metadata of the post sent to every user.
+	UserVotes []int `json:"user_votes,omitempty"`
+}
+
+// NewPollResults tallies the votes of the poll.
+func NewPollResults(poll *Poll, votes []*PollVote) *PollResults {
+	results := &PollResults{
+		Counts: make([]int, len(poll.Options)),
+	}
+	if !poll.Anonymous {
+		results.Voters = make([][]string, len(poll.Options))
+		for i := range results.Voters {
+			results.Voters[i] = []string{}
+		}
+	}
+
+	voters := map[string]bool{}
+	for _, vote := range votes {
+		if vote.OptionIndex >= len(poll.Options) {
+			continue
+		}
+		results.Counts[vote.OptionIndex]++
+		if results.Voters != nil {
+			results.Voters[vote.OptionIndex] = append(results.Voters[vote.OptionIndex], vote.UserId)
+		}
+		voters[vote.UserId] = true
+	}
+	results.TotalVoters = len(voters)
+
+	return results
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollIsValid(t *testing.T) {
+	poll := &Poll{Options: []string{"yes"}}
+	require.NotNil(t, poll.IsValid())
+
+	poll.Options = append(poll.Options, "no")
+	require.Nil(t, poll.IsValid())
+
+	poll.Options = append(poll.Options, "")
+	require.NotNil(t, poll.IsValid())
+
+	poll.Options[2] = strings.Repeat("a", PollOptionMaxRunes+1)
+	require.NotNil(t, poll.IsValid())
+
+	poll.Options = make([]string, PollMaxOptions+1)
+	for i := range poll.Options {
+		poll.Options[i] = "option"
+	}
+	require.NotNil(t, poll.IsValid())
+}
+
+func TestPollIsValidVote(t *testing.T) {
+	poll := &Poll{Options: []string{"a", "b", "c"}}
+	assert.True(t, poll.IsValidVote(nil))
+	assert.True(t, poll.IsValidVote([]int{2}))
+	assert.False(t, poll.IsValidVote([]int{3}))
+	assert.False(t, poll.IsValidVote([]int{-1}))
+	assert.False(t, poll.IsValidVote([]int{0, 1}))
+
+	poll.MultiChoice = true
+	assert.True(t, poll.IsValidVote([]int{0, 1}))
+	assert.False(t, poll.IsValidVote([]int{1, 1}))
+}
+
+func TestPostGetPoll(t *testing.T) {
+	post := &Post{Message: "question"}
+	assert.Nil(t, post.GetPoll())
+
+	post.SetPoll(&Poll{Options: []string{"a", "b"}, MultiChoice: true})
+	assert.Equal(t, PostTypePoll, post.Type)
+	require.NotNil(t, post.GetPoll())
+
+	// The props read back from the database hold the poll as a map.
+	b, err := json.Marshal(post)
+	require.NoError(t, err)
+	var decoded Post
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	poll := decoded.GetPoll()
+	require.NotNil(t, poll)
+	assert.Equal(t, []string{"a", "b"}, poll.Options)
+	assert.True(t, poll.MultiChoice)
+
+	t.Run("post validation", func(t *testing.T) {
+		post := &Post{Id: NewId(), ChannelId: NewId(), UserId: NewId(), CreateAt: GetMillis(), UpdateAt: GetMillis()}
+		post.SetPoll(&Poll{Options: []string{"a", "b"}})
+		require.Nil(t, post.IsValid(PostMessageMaxRunesV2))
+
+		post.SetPoll(&Poll{Options: []string{"a"}})
+		require.NotNil(t, post.IsValid(PostMessageMaxRunesV2))
+
+		post.DelProp(PostPropsPoll)
+		require.NotNil(t, post.IsValid(PostMessageMaxRunesV2))
+	})
+}
+
+func TestNewPollResults(t *testing.T) {
+	poll := &Poll{Options: []string{"a", "b", "c"}, MultiChoice: true}
+	userID1 := NewId()
+	userID2 := NewId()
+	votes := []*PollVote{
+		{UserId: userID1, OptionIndex: 0},
+		{UserId: userID1, OptionIndex: 2},
+		{UserId: userID2, OptionIndex: 2},
+	}
+
+	results := NewPollResults(poll, votes)
+	assert.Equal(t, []int{1, 0, 2}, results.Counts)
+	assert.Equal(t, [][]string{{userID1}, {}, {userID1, userID2}}, results.Voters)
+	assert.Equal(t, 2, results.TotalVoters)
+
+	poll.Anonymous = true
+	results = NewPollResults(poll, votes)
+	assert.Equal(t, []int{1, 0, 2}, results.Counts)
+	assert.Nil(t, results.Voters)
+}
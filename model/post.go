@@ -341,6 +341,16 @@ func (o *Post) IsValid(maxPostSize int) *AppError {
 		}
 	}
 
+	if o.Type == PostTypePoll {
+		poll := o.GetPoll()
+		if poll == nil {
+			return NewAppError("Post.IsValid", "model.post.is_valid.poll.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+		}
+		if appErr := poll.IsValid(); appErr != nil {
+			return appErr
+		}
+	}
+
 	if utf8.RuneCountInString(ArrayToJSON(o.Filenames)) > PostFilenamesMaxRunes {
 		return NewAppError("Post.IsValid", "model.post.is_valid.filenames.app_error", nil, "id="+o.Id, http.StatusBadRequest)
 	}
@@ -22,6 +22,9 @@ type PostMetadata struct {
 
 	// Reactions holds reactions made to the post.
 	Reactions []*Reaction `json:"reactions,omitempty"`
+
+	// Poll holds the results of the poll of a poll post.
+	Poll *PollResults `json:"poll,omitempty"`
 }
 
 type PostImage struct {
@@ -60,5 +63,6 @@ func (p *PostMetadata) Copy() *PostMetadata {
 		Files:     filesCopy,
 		Images:    imagesCopy,
 		Reactions: reactionsCopy,
+		Poll:      p.Poll,
 	}
 }
@@ -0,0 +1,100 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"unicode/utf8"
+)
+
+// PostRevision is the content a post had before one of its edits. Every edit of the
+// message, the files or the attachments of a post keeps a revision, so that the edit
+// history of the post can be reviewed.
+type PostRevision struct {
+	Id        string `json:"id"`
+	PostId    string `json:"post_id"`
+	ChannelId string `json:"channel_id"`
+	// The author of the post.
+	UserId string `json:"user_id"`
+	// The user who made the edit replacing this revision, empty when it is not known,
+	// such as for edits made by the server or imported.
+	EditorId string `json:"editor_id"`
+	// The time of the edit replacing this revision.
+	CreateAt int64           `json:"create_at"`
+	Message  string          `json:"message"`
+	Props    StringInterface `json:"props"`
+	FileIds  StringArray     `json:"file_ids,omitempty"`
+}
+
+// NewPostRevision returns the revision of oldPost replaced by an edit of editorID at editAt.
+func NewPostRevision(oldPost *Post, editorID string, editAt int64) *PostRevision {
+	return &PostRevision{
+		PostId:    oldPost.Id,
+		ChannelId: oldPost.ChannelId,
+		UserId:    oldPost.UserId,
+		EditorId:  editorID,
+		CreateAt:  editAt,
+		Message:   oldPost.Message,
+		Props:     oldPost.GetProps(),
+		FileIds:   oldPost.FileIds,
+	}
+}
+
+func (o *PostRevision) IsValid(maxPostSize int) *AppError {
+	if !IsValidId(o.Id) {
+		return NewAppError("PostRevision.IsValid", "model.post_revision.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.PostId) {
+		return NewAppError("PostRevision.IsValid", "model.post_revision.is_valid.post_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.ChannelId) {
+		return NewAppError("PostRevision.IsValid", "model.post_revision.is_valid.channel_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.UserId) {
+		return NewAppError("PostRevision.IsValid", "model.post_revision.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !(IsValidId(o.EditorId) || o.EditorId == "") {
+		return NewAppError("PostRevision.IsValid", "model.post_revision.is_valid.editor_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("PostRevision.IsValid", "model.post_revision.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(o.Message) > maxPostSize {
+		return NewAppError("PostRevision.IsValid", "model.post_revision.is_valid.msg.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(ArrayToJSON(o.FileIds)) > PostFileidsMaxRunes {
+		return NewAppError("PostRevision.IsValid", "model.post_revision.is_valid.file_ids.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(StringInterfaceToJSON(o.Props)) > PostPropsMaxRunes {
+		return NewAppError("PostRevision.IsValid", "model.post_revision.is_valid.props.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *PostRevision) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+
+	if o.Props == nil {
+		o.Props = make(map[string]interface{})
+	}
+
+	if o.FileIds == nil {
+		o.FileIds = []string{}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostRevisionIsValid(t *testing.T) {
+	revision := &PostRevision{
+		PostId:    NewId(),
+		ChannelId: NewId(),
+		UserId:    NewId(),
+		Message:   "message",
+	}
+	require.NotNil(t, revision.IsValid(PostMessageMaxRunesV2))
+
+	revision.PreSave()
+	require.Nil(t, revision.IsValid(PostMessageMaxRunesV2))
+
+	revision.EditorId = "invalid"
+	require.NotNil(t, revision.IsValid(PostMessageMaxRunesV2))
+	revision.EditorId = NewId()
+	require.Nil(t, revision.IsValid(PostMessageMaxRunesV2))
+
+	revision.Message = strings.Repeat("a", 11)
+	appErr := revision.IsValid(10)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.post_revision.is_valid.msg.app_error", appErr.Id)
+}
+
+func TestNewPostRevision(t *testing.T) {
+	post := &Post{
+		Id:        NewId(),
+		ChannelId: NewId(),
+		UserId:    NewId(),
+		Message:   "message",
+		FileIds:   StringArray{NewId()},
+	}
+	post.AddProp("key", "value")
+	editorID := NewId()
+
+	revision := NewPostRevision(post, editorID, 1234)
+	assert.Empty(t, revision.Id)
+	assert.Equal(t, post.Id, revision.PostId)
+	assert.Equal(t, post.ChannelId, revision.ChannelId)
+	assert.Equal(t, post.UserId, revision.UserId)
+	assert.Equal(t, editorID, revision.EditorId)
+	assert.Equal(t, int64(1234), revision.CreateAt)
+	assert.Equal(t, post.Message, revision.Message)
+	assert.Equal(t, post.FileIds, revision.FileIds)
+	assert.Equal(t, "value", revision.Props["key"])
+
+	revision.PreSave()
+	assert.True(t, IsValidId(revision.Id))
+	assert.Equal(t, int64(1234), revision.CreateAt)
+}
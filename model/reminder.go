@@ -0,0 +1,77 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+	"unicode/utf8"
+)
+
+const (
+	ReminderMessageMaxRunes = 4000
+
+	// The paths of the post actions of the reminder messages, handled by the server.
+	ReminderActionPathPrefix = "/reminders/"
+	ReminderActionSnooze     = ReminderActionPathPrefix + "snooze"
+	ReminderActionComplete   = ReminderActionPathPrefix + "complete"
+)
+
+// Reminder is a note a user asks to be reminded of at a future time, either about a post
+// or with a free text message. When the reminder fires the user receives a direct message
+// from the system bot, and the reminder is kept until it is completed or snoozed.
+type Reminder struct {
+	Id     string `json:"id"`
+	UserId string `json:"user_id"`
+	// The post to remind the user about, empty for a free text reminder.
+	PostId   string `json:"post_id"`
+	Message  string `json:"message"`
+	CreateAt int64  `json:"create_at"`
+	TargetAt int64  `json:"target_at"`
+	// The time the reminder was delivered, zero while it is pending.
+	FiredAt int64 `json:"fired_at"`
+}
+
+func (o *Reminder) IsValid() *AppError {
+	if !IsValidId(o.Id) {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.UserId) {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if !(IsValidId(o.PostId) || o.PostId == "") {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.post_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.PostId == "" && o.Message == "" {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.empty.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(o.Message) > ReminderMessageMaxRunes {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.message.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.TargetAt == 0 {
+		return NewAppError("Reminder.IsValid", "model.reminder.is_valid.target_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *Reminder) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+
+	o.FiredAt = 0
+}
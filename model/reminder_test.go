@@ -0,0 +1,52 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminderIsValid(t *testing.T) {
+	reminder := &Reminder{
+		UserId:   NewId(),
+		PostId:   NewId(),
+		TargetAt: GetMillis(),
+	}
+	require.NotNil(t, reminder.IsValid())
+
+	reminder.PreSave()
+	require.Nil(t, reminder.IsValid())
+
+	reminder.PostId = "invalid"
+	require.NotNil(t, reminder.IsValid())
+
+	reminder.PostId = ""
+	appErr := reminder.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.reminder.is_valid.empty.app_error", appErr.Id)
+
+	reminder.Message = "message"
+	require.Nil(t, reminder.IsValid())
+
+	reminder.Message = strings.Repeat("a", ReminderMessageMaxRunes+1)
+	require.NotNil(t, reminder.IsValid())
+	reminder.Message = "message"
+
+	reminder.TargetAt = 0
+	appErr = reminder.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.reminder.is_valid.target_at.app_error", appErr.Id)
+}
+
+func TestReminderPreSave(t *testing.T) {
+	reminder := &Reminder{FiredAt: 1234}
+	reminder.PreSave()
+	assert.True(t, IsValidId(reminder.Id))
+	assert.NotZero(t, reminder.CreateAt)
+	assert.Zero(t, reminder.FiredAt)
+}
@@ -735,6 +735,7 @@ func MakeDefaultRoles() map[string]*Role {
 			PermissionManagePrivateChannelMembers.Id,
 			PermissionDeletePost.Id,
 			PermissionEditPost.Id,
+			PermissionManageChannelBookmarks.Id,
 		},
 		SchemeManaged: true,
 		BuiltIn:       true,
@@ -0,0 +1,59 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"net/http"
+)
+
+// The error codes of the scheduled posts which couldn't be sent.
+const (
+	ScheduledPostErrorUnknown             = "unknown"
+	ScheduledPostErrorChannelArchived     = "channel_archived"
+	ScheduledPostErrorChannelNotFound     = "channel_not_found"
+	ScheduledPostErrorUserDeleted         = "user_deleted"
+	ScheduledPostErrorNoChannelPermission = "no_channel_permission"
+	ScheduledPostErrorThreadDeleted       = "thread_deleted"
+)
+
+// ScheduledPost is a post a user schedules to be sent at a future time. Once processed,
+// a scheduled post either has been sent and is deleted, or has an error code telling
+// why it couldn't be sent.
+type ScheduledPost struct {
+	Draft
+	Id          string `json:"id"`
+	ScheduledAt int64  `json:"scheduled_at"`
+	ProcessedAt int64  `json:"processed_at"`
+	ErrorCode   string `json:"error_code"`
+}
+
+func (o *ScheduledPost) IsValid(maxMessageSize int) *AppError {
+	if !IsValidId(o.Id) {
+		return NewAppError("ScheduledPost.IsValid", "model.scheduled_post.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if o.ScheduledAt == 0 {
+		return NewAppError("ScheduledPost.IsValid", "model.scheduled_post.is_valid.scheduled_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.Message == "" && len(o.FileIds) == 0 {
+		return NewAppError("ScheduledPost.IsValid", "model.scheduled_post.is_valid.empty.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return o.Draft.IsValid(maxMessageSize)
+}
+
+func (o *ScheduledPost) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	o.ProcessedAt = 0
+	o.ErrorCode = ""
+	o.Draft.PreSave()
+}
+
+func (o *ScheduledPost) PreUpdate() {
+	o.Draft.PreUpdate()
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledPostIsValid(t *testing.T) {
+	scheduledPost := &ScheduledPost{
+		Draft: Draft{
+			UserId:    NewId(),
+			ChannelId: NewId(),
+			Message:   "message",
+		},
+		ScheduledAt: GetMillis(),
+	}
+	require.NotNil(t, scheduledPost.IsValid(PostMessageMaxRunesV2))
+
+	scheduledPost.PreSave()
+	require.Nil(t, scheduledPost.IsValid(PostMessageMaxRunesV2))
+
+	scheduledPost.Message = ""
+	appErr := scheduledPost.IsValid(PostMessageMaxRunesV2)
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.scheduled_post.is_valid.empty.app_error", appErr.Id)
+
+	scheduledPost.FileIds = StringArray{NewId()}
+	require.Nil(t, scheduledPost.IsValid(PostMessageMaxRunesV2))
+
+	scheduledPost.ScheduledAt = 0
+	require.NotNil(t, scheduledPost.IsValid(PostMessageMaxRunesV2))
+}
+
+func TestScheduledPostPreSave(t *testing.T) {
+	scheduledPost := &ScheduledPost{ProcessedAt: 1, ErrorCode: ScheduledPostErrorUnknown}
+	scheduledPost.PreSave()
+	assert.True(t, IsValidId(scheduledPost.Id))
+	assert.NotZero(t, scheduledPost.CreateAt)
+	assert.Zero(t, scheduledPost.ProcessedAt)
+	assert.Empty(t, scheduledPost.ErrorCode)
+}
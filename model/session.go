@@ -26,6 +26,7 @@ const (
 	SessionPropIsBotValue         = "true"
 	SessionPropOAuthAppID         = "oauth_app_id"
 	SessionPropMatterfossAppID    = "matterfoss_app_id"
+	SessionPropOAuthScope         = "oauth_scope"
 	SessionTypeUserAccessToken    = "UserAccessToken"
 	SessionTypeCloudKey           = "CloudKey"
 	SessionTypeRemoteclusterToken = "RemoteClusterToken"
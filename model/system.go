@@ -15,6 +15,7 @@ const (
 	SystemLicenseRenewalToken              = "LicenseRenewalToken"
 	SystemLastComplianceTime               = "LastComplianceTime"
 	SystemAsymmetricSigningKeyKey          = "AsymmetricSigningKey"
+	SystemOpenIDSigningKeyPrefix           = "OpenIDSigningKey_"
 	SystemPostActionCookieSecretKey        = "PostActionCookieSecret"
 	SystemInstallationDateKey              = "InstallationDate"
 	SystemFirstServerRunTimestampKey       = "FirstServerRunTimestamp"
@@ -69,6 +70,14 @@ type SystemECDSAKey struct {
 	D     *big.Int `json:"d,omitempty"`
 }
 
+// SystemOpenIDSigningKey is the RSA key signing the ID tokens issued during one rotation
+// period. The key is stored in its PKCS #1 DER encoding.
+type SystemOpenIDSigningKey struct {
+	Kid      string `json:"kid"`
+	CreateAt int64  `json:"create_at"`
+	RSAKey   []byte `json:"rsa_key"`
+}
+
 // ServerBusyState provides serialization for app.Busy.
 type ServerBusyState struct {
 	Busy      bool   `json:"busy"`
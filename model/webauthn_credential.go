@@ -0,0 +1,159 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cjdelisle/matterfoss-server/v6/shared/webauthn"
+)
+
+const (
+	WebAuthnCredentialsPerUserMax   = 20
+	WebAuthnCredentialNameMaxRunes  = 64
+	WebAuthnCredentialIdMaxLength   = 1400
+	WebAuthnCredentialTransportsMax = 256
+)
+
+// WebAuthnCredential is a public key credential registered by an authenticator, such as a
+// security key or a passkey, with which its user signs in without a password or completes
+// the multi-factor authentication.
+type WebAuthnCredential struct {
+	Id     string `json:"id"`
+	UserId string `json:"user_id"`
+	Name   string `json:"name"`
+	// The base64url encoded id of the credential on the authenticator.
+	CredentialId string `json:"credential_id"`
+	// The base64url encoded COSE public key of the credential.
+	PublicKey  string `json:"-"`
+	SignCount  int64  `json:"-"`
+	AAGUID     string `json:"aaguid"`
+	Transports string `json:"-"`
+	CreateAt   int64  `json:"create_at"`
+	UpdateAt   int64  `json:"update_at"`
+	LastUsedAt int64  `json:"last_used_at"`
+}
+
+type WebAuthnCredentialPatch struct {
+	Name *string `json:"name"`
+}
+
+// WebAuthnRegistrationRequest is the credential created by an authenticator for the
+// options returned when the registration began, to be registered under the given name.
+type WebAuthnRegistrationRequest struct {
+	Name       string                         `json:"name"`
+	Credential *webauthn.RegistrationResponse `json:"credential"`
+}
+
+// WebAuthnLoginRequest is the assertion made by an authenticator for the options returned
+// when the login began.
+type WebAuthnLoginRequest struct {
+	Credential *webauthn.AssertionResponse `json:"credential"`
+	DeviceId   string                      `json:"device_id"`
+}
+
+// NewWebAuthnCredential returns the credential registered by an authenticator for the user.
+func NewWebAuthnCredential(userId, name string, credential *webauthn.Credential) *WebAuthnCredential {
+	return &WebAuthnCredential{
+		UserId:       userId,
+		Name:         strings.TrimSpace(name),
+		CredentialId: webauthn.EncodeBase64(credential.Id),
+		PublicKey:    webauthn.EncodeBase64(credential.PublicKey),
+		SignCount:    int64(credential.SignCount),
+		AAGUID:       hex.EncodeToString(credential.AAGUID),
+		Transports:   strings.Join(credential.Transports, ","),
+	}
+}
+
+func (o *WebAuthnCredential) IsValid() *AppError {
+	if !IsValidId(o.Id) {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.id.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	if !IsValidId(o.UserId) {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.user_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.Name == "" || utf8.RuneCountInString(o.Name) > WebAuthnCredentialNameMaxRunes {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.name.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CredentialId == "" || len(o.CredentialId) > WebAuthnCredentialIdMaxLength {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.credential_id.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.PublicKey == "" {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.public_key.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if len(o.Transports) > WebAuthnCredentialTransportsMax {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.transports.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.CreateAt == 0 {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.create_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	if o.UpdateAt == 0 {
+		return NewAppError("WebAuthnCredential.IsValid", "model.webauthn_credential.is_valid.update_at.app_error", nil, "id="+o.Id, http.StatusBadRequest)
+	}
+
+	return nil
+}
+
+func (o *WebAuthnCredential) PreSave() {
+	if o.Id == "" {
+		o.Id = NewId()
+	}
+
+	if o.CreateAt == 0 {
+		o.CreateAt = GetMillis()
+	}
+	o.UpdateAt = o.CreateAt
+}
+
+func (o *WebAuthnCredential) PreUpdate() {
+	o.UpdateAt = GetMillis()
+}
+
+func (o *WebAuthnCredential) Patch(patch *WebAuthnCredentialPatch) {
+	if patch.Name != nil {
+		o.Name = strings.TrimSpace(*patch.Name)
+	}
+}
+
+// Descriptor returns the descriptor of the credential, telling an authenticator which
+// credential to use or not to register again.
+func (o *WebAuthnCredential) Descriptor() webauthn.CredentialDescriptor {
+	descriptor := webauthn.CredentialDescriptor{
+		Type: webauthn.CredentialTypePublicKey,
+		Id:   o.CredentialId,
+	}
+	if o.Transports != "" {
+		descriptor.Transports = strings.Split(o.Transports, ",")
+	}
+	return descriptor
+}
+
+// ToCredential returns the credential to verify the assertions of its authenticator.
+func (o *WebAuthnCredential) ToCredential() (*webauthn.Credential, error) {
+	id, err := webauthn.DecodeBase64(o.CredentialId)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := webauthn.DecodeBase64(o.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webauthn.Credential{
+		Id:        id,
+		PublicKey: publicKey,
+		SignCount: uint32(o.SignCount),
+	}, nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/shared/webauthn"
+)
+
+func TestWebAuthnCredentialIsValid(t *testing.T) {
+	credential := NewWebAuthnCredential(NewId(), " Security key ", &webauthn.Credential{
+		Id:         []byte{1, 2, 3, 4},
+		PublicKey:  []byte{5, 6, 7, 8},
+		SignCount:  3,
+		Transports: []string{"usb", "nfc"},
+	})
+	assert.Equal(t, "Security key", credential.Name)
+	require.NotNil(t, credential.IsValid())
+
+	credential.PreSave()
+	require.Nil(t, credential.IsValid())
+
+	credential.Name = ""
+	appErr := credential.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.webauthn_credential.is_valid.name.app_error", appErr.Id)
+
+	credential.Name = strings.Repeat("a", WebAuthnCredentialNameMaxRunes+1)
+	require.NotNil(t, credential.IsValid())
+	credential.Name = "Security key"
+
+	credential.CredentialId = strings.Repeat("a", WebAuthnCredentialIdMaxLength+1)
+	appErr = credential.IsValid()
+	require.NotNil(t, appErr)
+	assert.Equal(t, "model.webauthn_credential.is_valid.credential_id.app_error", appErr.Id)
+}
+
+func TestWebAuthnCredentialToCredential(t *testing.T) {
+	credential := NewWebAuthnCredential(NewId(), "Security key", &webauthn.Credential{
+		Id:         []byte{1, 2, 3, 4},
+		PublicKey:  []byte{5, 6, 7, 8},
+		SignCount:  3,
+		Transports: []string{"usb", "nfc"},
+	})
+
+	descriptor := credential.Descriptor()
+	assert.Equal(t, credential.CredentialId, descriptor.Id)
+	assert.Equal(t, []string{"usb", "nfc"}, descriptor.Transports)
+
+	converted, err := credential.ToCredential()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4}, converted.Id)
+	assert.Equal(t, []byte{5, 6, 7, 8}, converted.PublicKey)
+	assert.Equal(t, uint32(3), converted.SignCount)
+}
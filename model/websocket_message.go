@@ -75,6 +75,17 @@ const (
 	WebsocketEventThreadFollowChanged                 = "thread_follow_changed"
 	WebsocketEventThreadReadChanged                   = "thread_read_changed"
 	WebsocketFirstAdminVisitMarketplaceStatusReceived = "first_admin_visit_marketplace_status_received"
+	WebsocketEventDraftCreated                        = "draft_created"
+	WebsocketEventDraftUpdated                        = "draft_updated"
+	WebsocketEventDraftDeleted                        = "draft_deleted"
+	WebsocketEventScheduledPostCreated                = "scheduled_post_created"
+	WebsocketEventScheduledPostUpdated                = "scheduled_post_updated"
+	WebsocketEventScheduledPostDeleted                = "scheduled_post_deleted"
+	WebsocketEventPostRevisionCreated                 = "post_revision_created"
+	WebsocketEventChannelBookmarkCreated              = "channel_bookmark_created"
+	WebsocketEventChannelBookmarkUpdated              = "channel_bookmark_updated"
+	WebsocketEventChannelBookmarkDeleted              = "channel_bookmark_deleted"
+	WebsocketEventChannelBookmarkSorted               = "channel_bookmark_sorted"
 )
 
 type WebSocketMessage interface {
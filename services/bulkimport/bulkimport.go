@@ -0,0 +1,154 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bulkimport
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// Writer writes the lines of a bulk import data file, and counts them.
+type Writer struct {
+	encoder *json.Encoder
+	lines   int
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{encoder: json.NewEncoder(w)}
+}
+
+// WriteVersion writes the version line, which starts the data file.
+func (w *Writer) WriteVersion() error {
+	version := 1
+	return w.Write(&model.LineImportData{Type: "version", Version: &version})
+}
+
+func (w *Writer) Write(line *model.LineImportData) error {
+	w.lines++
+	return w.encoder.Encode(line)
+}
+
+// Lines returns the number of lines written.
+func (w *Writer) Lines() int {
+	return w.lines
+}
+
+// toReplies converts the post into thread replies, splitting its message if it is too long.
+func toReplies(post *model.PostImportData, maxPostSize int) []model.ReplyImportData {
+	var replies []model.ReplyImportData
+	for i, message := range SplitMessage(*post.Message, maxPostSize) {
+		reply := model.ReplyImportData{
+			User:     post.User,
+			Type:     post.Type,
+			Message:  model.NewString(message),
+			CreateAt: model.NewInt64(*post.CreateAt + int64(i)),
+		}
+		if i == 0 {
+			reply.EditAt = post.EditAt
+			reply.IsPinned = post.IsPinned
+			reply.Reactions = post.Reactions
+			reply.Attachments = post.Attachments
+		}
+		replies = append(replies, reply)
+	}
+	return replies
+}
+
+// split moves the end of the message of the post into replies, if it is too long.
+func split(post *model.PostImportData, maxPostSize int) {
+	messages := SplitMessage(*post.Message, maxPostSize)
+	if len(messages) == 1 {
+		return
+	}
+
+	post.Message = model.NewString(messages[0])
+	var replies []model.ReplyImportData
+	for i, message := range messages[1:] {
+		replies = append(replies, model.ReplyImportData{
+			User:     post.User,
+			Message:  model.NewString(message),
+			CreateAt: model.NewInt64(*post.CreateAt + int64(i+1)),
+		})
+	}
+	if post.Replies != nil {
+		replies = append(replies, *post.Replies...)
+	}
+	post.Replies = &replies
+}
+
+// SplitMessage splits the message into messages of at most maxPostSize runes.
+func SplitMessage(message string, maxPostSize int) []string {
+	var messages []string
+	for utf8.RuneCountInString(message) > maxPostSize {
+		runes := []rune(message)
+		messages = append(messages, string(runes[:maxPostSize]))
+		message = string(runes[maxPostSize:])
+	}
+	return append(messages, message)
+}
+
+// truncateChannel truncates the properties of the channel which are too long.
+func truncateChannel(channel *model.ChannelImportData) {
+	channel.DisplayName = model.NewString(TruncateRunes(*channel.DisplayName, model.ChannelDisplayNameMaxRunes))
+	if channel.Header != nil {
+		channel.Header = model.NewString(TruncateRunes(*channel.Header, model.ChannelHeaderMaxRunes))
+	}
+	if channel.Purpose != nil {
+		channel.Purpose = model.NewString(TruncateRunes(*channel.Purpose, model.ChannelPurposeMaxRunes))
+	}
+}
+
+// TruncateRunes truncates the string to at most max runes.
+func TruncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9\-_]+`)
+
+// ChannelName returns a valid channel name for the display name, or the fallback
+// if the display name has no valid characters.
+func ChannelName(displayName string, fallback string) string {
+	name := strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(displayName), "-"), "-_")
+	if name == "" {
+		name = strings.ToLower(fallback)
+	}
+	if len(name) == 1 {
+		name = "channel-" + name
+	}
+	if len(name) > model.ChannelNameMaxLength {
+		name = name[:model.ChannelNameMaxLength]
+	}
+	return name
+}
+
+var invalidUsernameCharacters = regexp.MustCompile(`[^a-z0-9\.\-_]+`)
+
+// Username returns a valid username for the name, which isn't in the set of taken
+// usernames, and adds it to the set.
+func Username(name string, taken map[string]bool) string {
+	username := invalidUsernameCharacters.ReplaceAllString(strings.ToLower(name), "")
+	if username == "" || username[0] < 'a' || username[0] > 'z' {
+		username = "user" + username
+	}
+	// Leave room for the suffix making the username unique.
+	if len(username) > model.UserNameMaxLength-8 {
+		username = username[:model.UserNameMaxLength-8]
+	}
+
+	candidate := username
+	for i := 1; taken[candidate] || !model.IsValidUsername(candidate); i++ {
+		candidate = username + "-" + strconv.Itoa(i)
+	}
+	taken[candidate] = true
+	return candidate
+}
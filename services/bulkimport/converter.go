@@ -0,0 +1,191 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package bulkimport
+
+import (
+	"io"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// ConvertOptions are the options of the conversion of an export to a bulk import data
+// file.
+type ConvertOptions struct {
+	// TeamName is the name of the team the channels are imported into.
+	TeamName string
+	// MaxPostSize is the maximum number of runes of a message. Longer messages are split
+	// into several posts.
+	MaxPostSize int
+	// ExistingUser returns the user with the given email, if any. The users of the export
+	// are merged with the existing users sharing their email address.
+	ExistingUser func(email string) *model.User
+}
+
+// ChannelState holds the header and the purpose of a channel, which the messages of the
+// exports changing them don't record the old values of.
+type ChannelState struct {
+	Header  string
+	Purpose string
+}
+
+// Converter writes the bulk import data file an export is converted into. The posts are
+// kept until WritePosts, so that the messages of their threads can be added to them as
+// replies.
+type Converter struct {
+	Opts        ConvertOptions
+	writer      *Writer
+	threads     map[string]*model.PostImportData
+	posts       []*model.PostImportData
+	directPosts []*directPost
+}
+
+// directPost is a post of a direct or group channel, written as a direct post line.
+type directPost struct {
+	members []string
+	post    *model.PostImportData
+}
+
+func NewConverter(w io.Writer, opts ConvertOptions) *Converter {
+	if opts.MaxPostSize <= 0 {
+		opts.MaxPostSize = model.PostMessageMaxRunesV1
+	}
+	return &Converter{
+		Opts:    opts,
+		writer:  NewWriter(w),
+		threads: make(map[string]*model.PostImportData),
+	}
+}
+
+// Lines returns the number of lines written.
+func (c *Converter) Lines() int {
+	return c.writer.Lines()
+}
+
+// WriteVersion writes the version line, which starts the data file.
+func (c *Converter) WriteVersion() error {
+	return c.writer.WriteVersion()
+}
+
+// WriteChannel writes the line of the channel of the team, truncating the properties
+// which are too long. An empty header or purpose is left out, so that the one of an
+// existing channel is kept.
+func (c *Converter) WriteChannel(channel *model.ChannelImportData) error {
+	channel.Team = model.NewString(c.Opts.TeamName)
+	if channel.Header != nil && *channel.Header == "" {
+		channel.Header = nil
+	}
+	if channel.Purpose != nil && *channel.Purpose == "" {
+		channel.Purpose = nil
+	}
+	truncateChannel(channel)
+	return c.writer.Write(&model.LineImportData{Type: "channel", Channel: channel})
+}
+
+// WriteUser writes the line of the user, member of the given channels of the team. The
+// profile of the existing user the user is merged with, if any, is kept.
+func (c *Converter) WriteUser(user *model.UserImportData, existingUser *model.User, channels []model.UserChannelImportData) error {
+	if existingUser != nil {
+		user = &model.UserImportData{
+			Username: model.NewString(existingUser.Username),
+			Email:    model.NewString(existingUser.Email),
+			Locale:   model.NewString(existingUser.Locale),
+		}
+	}
+	user.Teams = c.teams(channels)
+	return c.writer.Write(&model.LineImportData{Type: "user", User: user})
+}
+
+// WriteBot writes the line of the bot, member of the given channels of the team.
+func (c *Converter) WriteBot(bot *model.BotImportData, channels []model.UserChannelImportData) error {
+	bot.Teams = c.teams(channels)
+	return c.writer.Write(&model.LineImportData{Type: "bot", Bot: bot})
+}
+
+func (c *Converter) teams(channels []model.UserChannelImportData) *[]model.UserTeamImportData {
+	team := model.UserTeamImportData{Name: model.NewString(c.Opts.TeamName)}
+	if len(channels) > 0 {
+		team.Channels = &channels
+	}
+	return &[]model.UserTeamImportData{team}
+}
+
+// WriteDirectChannel writes the line of the direct or group channel of the members.
+func (c *Converter) WriteDirectChannel(members []string, header string) error {
+	channel := &model.DirectChannelImportData{Members: &members}
+	if header != "" {
+		channel.Header = model.NewString(header)
+	}
+	return c.writer.Write(&model.LineImportData{Type: "direct_channel", DirectChannel: channel})
+}
+
+// AddPost adds the post to the channel of the team. The post is the root of the thread
+// with the given id, unless it is empty.
+func (c *Converter) AddPost(channel string, post *model.PostImportData, threadId string) {
+	post.Team = model.NewString(c.Opts.TeamName)
+	post.Channel = model.NewString(channel)
+	c.posts = append(c.posts, post)
+	if threadId != "" {
+		c.threads[threadId] = post
+	}
+}
+
+// AddDirectPost adds the post to the direct or group channel of the members. The post is
+// the root of the thread with the given id, unless it is empty.
+func (c *Converter) AddDirectPost(members []string, post *model.PostImportData, threadId string) {
+	c.directPosts = append(c.directPosts, &directPost{members: members, post: post})
+	if threadId != "" {
+		c.threads[threadId] = post
+	}
+}
+
+// AddReply adds the post to the replies of the thread with the given id, and returns
+// false if there is no such thread. The replies are never older than their root post.
+func (c *Converter) AddReply(threadId string, post *model.PostImportData) bool {
+	root, ok := c.threads[threadId]
+	if !ok {
+		return false
+	}
+	if *post.CreateAt < *root.CreateAt {
+		post.CreateAt = root.CreateAt
+	}
+	var replies []model.ReplyImportData
+	if root.Replies != nil {
+		replies = *root.Replies
+	}
+	replies = append(replies, toReplies(post, c.Opts.MaxPostSize)...)
+	root.Replies = &replies
+	return true
+}
+
+// WritePosts writes the lines of the posts added, then the lines of the direct posts,
+// which follow their channels.
+func (c *Converter) WritePosts() error {
+	for _, post := range c.posts {
+		// The messages which are too long are split once the replies are all known.
+		split(post, c.Opts.MaxPostSize)
+		if err := c.writer.Write(&model.LineImportData{Type: "post", Post: post}); err != nil {
+			return err
+		}
+	}
+	for _, direct := range c.directPosts {
+		post := direct.post
+		split(post, c.Opts.MaxPostSize)
+		if err := c.writer.Write(&model.LineImportData{Type: "direct_post", DirectPost: &model.DirectPostImportData{
+			ChannelMembers: &direct.members,
+			User:           post.User,
+			Type:           post.Type,
+			Message:        post.Message,
+			Props:          post.Props,
+			CreateAt:       post.CreateAt,
+			EditAt:         post.EditAt,
+			IsPinned:       post.IsPinned,
+			Reactions:      post.Reactions,
+			Replies:        post.Replies,
+			Attachments:    post.Attachments,
+		}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
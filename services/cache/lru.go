@@ -143,15 +143,7 @@ func (l *LRU) set(key string, value interface{}, ttl time.Duration) error {
 		expires = time.Now().Add(ttl)
 	}
 
-	var buf []byte
-	var err error
-	// We use a fast path for hot structs.
-	if msgpVal, ok := value.(msgp.Marshaler); ok {
-		buf, err = msgpVal.MarshalMsg(nil)
-	} else {
-		// Slow path for other structs.
-		buf, err = msgpack.Marshal(value)
-	}
+	buf, err := encode(value)
 	if err != nil {
 		return err
 	}
@@ -190,6 +182,22 @@ func (l *LRU) get(key string, value interface{}) error {
 		return err
 	}
 
+	return decode(val, value)
+}
+
+// encode serializes a value to be stored in a cache.
+func encode(value interface{}) ([]byte, error) {
+	// We use a fast path for hot structs.
+	if msgpVal, ok := value.(msgp.Marshaler); ok {
+		return msgpVal.MarshalMsg(nil)
+	}
+
+	// Slow path for other structs.
+	return msgpack.Marshal(value)
+}
+
+// decode deserializes a value read from a cache into the value interface.
+func decode(val []byte, value interface{}) error {
 	// We use a fast path for hot structs.
 	if msgpVal, ok := value.(msgp.Unmarshaler); ok {
 		_, err := msgpVal.UnmarshalMsg(val)
@@ -0,0 +1,225 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// redisScanCount is the number of keys asked to the server by each SCAN call.
+const redisScanCount = 1000
+
+// RedisOptions contains options for connecting to a Redis server
+type RedisOptions struct {
+	Address  string
+	Password string
+	DB       int
+	// Prefix is prepended to all the keys, so that several servers can share the same database.
+	Prefix string
+}
+
+type redisProvider struct {
+	opts   RedisOptions
+	client *redis.Client
+}
+
+// NewRedisProvider creates a new CacheProvider backed by a Redis server,
+// which lets all the nodes of a cluster share the same caches.
+func NewRedisProvider(opts RedisOptions) Provider {
+	return &redisProvider{opts: opts}
+}
+
+// NewCache creates a new cache with given opts. The size and striping options are
+// ignored, as evictions are left to the maxmemory policy of the Redis server.
+func (r *redisProvider) NewCache(opts *CacheOptions) (Cache, error) {
+	if r.client == nil {
+		return nil, errors.New("redis cache provider is not connected")
+	}
+
+	return &Redis{
+		client:                 r.client,
+		prefix:                 r.opts.Prefix + ":" + opts.Name + ":",
+		defaultExpiry:          opts.DefaultExpiry,
+		name:                   opts.Name,
+		invalidateClusterEvent: opts.InvalidateClusterEvent,
+	}, nil
+}
+
+// Connect opens a new connection to the cache using specific provider parameters.
+func (r *redisProvider) Connect() error {
+	client := redis.NewClient(&redis.Options{
+		Addr:     r.opts.Address,
+		Password: r.opts.Password,
+		DB:       r.opts.DB,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return err
+	}
+
+	r.client = client
+	return nil
+}
+
+// Close releases any resources used by the cache provider.
+func (r *redisProvider) Close() error {
+	if r.client == nil {
+		return nil
+	}
+
+	err := r.client.Close()
+	r.client = nil
+	return err
+}
+
+// Redis is a cache stored in a Redis server. Each cache keeps its keys under its own prefix.
+type Redis struct {
+	client                 *redis.Client
+	prefix                 string
+	defaultExpiry          time.Duration
+	name                   string
+	invalidateClusterEvent model.ClusterEvent
+}
+
+// Purge is used to completely clear the cache.
+func (r *Redis) Purge() error {
+	keys, err := r.scan()
+	if err != nil {
+		return err
+	}
+
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > redisScanCount {
+			batch = batch[:redisScanCount]
+		}
+		if err := r.client.Del(context.Background(), batch...).Err(); err != nil {
+			return err
+		}
+		keys = keys[len(batch):]
+	}
+	return nil
+}
+
+// Set adds the given key and value to the store without an expiry. If the key already exists,
+// it will overwrite the previous value.
+func (r *Redis) Set(key string, value interface{}) error {
+	return r.SetWithExpiry(key, value, 0)
+}
+
+// SetWithDefaultExpiry adds the given key and value to the store with the default expiry. If
+// the key already exists, it will overwrite the previous value
+func (r *Redis) SetWithDefaultExpiry(key string, value interface{}) error {
+	return r.SetWithExpiry(key, value, r.defaultExpiry)
+}
+
+// SetWithExpiry adds the given key and value to the cache with the given expiry. If the key
+// already exists, it will overwrite the previous value
+func (r *Redis) SetWithExpiry(key string, value interface{}, ttl time.Duration) error {
+	buf, err := encode(value)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(context.Background(), r.prefix+key, buf, ttl).Err()
+}
+
+// Get the content stored in the cache for the given key, and decode it into the value interface.
+// return ErrKeyNotFound if the key is missing from the cache
+func (r *Redis) Get(key string, value interface{}) error {
+	buf, err := r.client.Get(context.Background(), r.prefix+key).Bytes()
+	if err == redis.Nil {
+		return ErrKeyNotFound
+	} else if err != nil {
+		return err
+	}
+
+	return decode(buf, value)
+}
+
+// Remove deletes the value for a key.
+func (r *Redis) Remove(key string) error {
+	return r.client.Del(context.Background(), r.prefix+key).Err()
+}
+
+// Keys returns a slice of the keys in the cache.
+func (r *Redis) Keys() ([]string, error) {
+	keys, err := r.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, r.prefix)
+	}
+	return keys, nil
+}
+
+// Len returns the number of items in the cache.
+func (r *Redis) Len() (int, error) {
+	keys, err := r.scan()
+	if err != nil {
+		return 0, err
+	}
+	return len(keys), nil
+}
+
+// GetInvalidateClusterEvent returns the cluster event configured when this cache was created.
+func (r *Redis) GetInvalidateClusterEvent() model.ClusterEvent {
+	return r.invalidateClusterEvent
+}
+
+// Name returns the name of the cache
+func (r *Redis) Name() string {
+	return r.name
+}
+
+// scan returns the full Redis keys of all the items in the cache.
+func (r *Redis) scan() ([]string, error) {
+	match := escapeRedisPattern(r.prefix) + "*"
+
+	keys := []string{}
+	seen := map[string]bool{}
+	var cursor uint64
+	for {
+		batch, next, err := r.client.Scan(context.Background(), cursor, match, redisScanCount).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		// SCAN may return the same key more than once.
+		for _, key := range batch {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+
+		if next == 0 {
+			return keys, nil
+		}
+		cursor = next
+	}
+}
+
+// escapeRedisPattern escapes the characters having a special meaning in the
+// patterns of the Redis commands.
+func escapeRedisPattern(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
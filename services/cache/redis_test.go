@@ -0,0 +1,166 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func newTestRedisProvider(t *testing.T) Provider {
+	server := newRESPServer(t)
+	p := NewRedisProvider(RedisOptions{
+		Address: server.Addr(),
+		Prefix:  "mmtest",
+	})
+	require.NoError(t, p.Connect())
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestRedisProvider(t *testing.T) {
+	t.Run("not connected", func(t *testing.T) {
+		p := NewRedisProvider(RedisOptions{Address: "127.0.0.1:0"})
+		_, err := p.NewCache(&CacheOptions{Name: "test"})
+		require.Error(t, err)
+		require.Error(t, p.Connect())
+		require.NoError(t, p.Close())
+	})
+
+	t.Run("options", func(t *testing.T) {
+		p := newTestRedisProvider(t)
+		c, err := p.NewCache(&CacheOptions{
+			Name:                   "name",
+			DefaultExpiry:          time.Second,
+			InvalidateClusterEvent: model.ClusterEvent("clusterEvent"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "name", c.Name())
+		assert.Equal(t, model.ClusterEvent("clusterEvent"), c.GetInvalidateClusterEvent())
+	})
+}
+
+func TestRedis(t *testing.T) {
+	p := newTestRedisProvider(t)
+	c, err := p.NewCache(&CacheOptions{Name: "test"})
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, c.Set(fmt.Sprintf("%d", i), i))
+	}
+
+	l, err := c.Len()
+	require.NoError(t, err)
+	require.Equal(t, 10, l)
+
+	keys, err := c.Keys()
+	require.NoError(t, err)
+	sort.Strings(keys)
+	require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, keys)
+
+	var v int
+	require.NoError(t, c.Get("5", &v))
+	require.Equal(t, 5, v)
+
+	require.NoError(t, c.Set("5", 50))
+	require.NoError(t, c.Get("5", &v))
+	require.Equal(t, 50, v)
+
+	require.NoError(t, c.Remove("5"))
+	require.Equal(t, ErrKeyNotFound, c.Get("5", &v))
+	l, err = c.Len()
+	require.NoError(t, err)
+	require.Equal(t, 9, l)
+
+	require.NoError(t, c.Purge())
+	l, err = c.Len()
+	require.NoError(t, err)
+	require.Equal(t, 0, l)
+	require.Equal(t, ErrKeyNotFound, c.Get("0", &v))
+}
+
+func TestRedisExpiry(t *testing.T) {
+	p := newTestRedisProvider(t)
+	c, err := p.NewCache(&CacheOptions{Name: "test", DefaultExpiry: 100 * time.Millisecond})
+	require.NoError(t, err)
+
+	require.NoError(t, c.SetWithExpiry("short", "value", 50*time.Millisecond))
+	require.NoError(t, c.SetWithDefaultExpiry("default", "value"))
+	require.NoError(t, c.Set("never", "value"))
+
+	var v string
+	require.NoError(t, c.Get("short", &v))
+	require.Equal(t, "value", v)
+
+	time.Sleep(60 * time.Millisecond)
+	require.Equal(t, ErrKeyNotFound, c.Get("short", &v))
+	require.NoError(t, c.Get("default", &v))
+
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, ErrKeyNotFound, c.Get("default", &v))
+	require.NoError(t, c.Get("never", &v))
+
+	keys, err := c.Keys()
+	require.NoError(t, err)
+	require.Equal(t, []string{"never"}, keys)
+}
+
+func TestRedisSeparateCaches(t *testing.T) {
+	p := newTestRedisProvider(t)
+	c1, err := p.NewCache(&CacheOptions{Name: "first"})
+	require.NoError(t, err)
+	c2, err := p.NewCache(&CacheOptions{Name: "first*"})
+	require.NoError(t, err)
+
+	require.NoError(t, c1.Set("key", "one"))
+	require.NoError(t, c2.Set("key", "two"))
+
+	var v string
+	require.NoError(t, c1.Get("key", &v))
+	require.Equal(t, "one", v)
+	require.NoError(t, c2.Get("key", &v))
+	require.Equal(t, "two", v)
+
+	// The caches must not see each other's keys, even when a name looks like a pattern.
+	keys, err := c1.Keys()
+	require.NoError(t, err)
+	require.Equal(t, []string{"key"}, keys)
+
+	require.NoError(t, c2.Purge())
+	require.NoError(t, c1.Get("key", &v))
+	require.Equal(t, "one", v)
+}
+
+func TestRedisHotStructs(t *testing.T) {
+	p := newTestRedisProvider(t)
+	c, err := p.NewCache(&CacheOptions{Name: "users"})
+	require.NoError(t, err)
+
+	user := &model.User{
+		Id:          model.NewId(),
+		Username:    "alice",
+		Props:       model.StringMap{},
+		NotifyProps: model.StringMap{"email": "true"},
+		Timezone:    model.StringMap{},
+	}
+	require.NoError(t, c.Set("user", user))
+
+	var u *model.User
+	require.NoError(t, c.Get("user", &u))
+	require.Equal(t, user, u)
+
+	users := map[string]*model.User{user.Id: user}
+	require.NoError(t, c.Set("users", model.UserMap(users)))
+
+	var m map[string]*model.User
+	require.NoError(t, c.Get("users", &m))
+	require.Equal(t, users, m)
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// respServer is an in-memory stand-in for a Redis server, implementing the
+// subset of the RESP protocol and of the commands used by the Redis cache.
+type respServer struct {
+	listener net.Listener
+
+	lock    sync.Mutex
+	values  map[string][]byte
+	expires map[string]time.Time
+}
+
+func newRESPServer(t *testing.T) *respServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &respServer{
+		listener: listener,
+		values:   map[string][]byte{},
+		expires:  map[string]time.Time{},
+	}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *respServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *respServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *respServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		args, err := readRESPCommand(r)
+		if err != nil {
+			return
+		}
+		s.exec(w, args)
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func readRESPCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected command line %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err = r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (s *respServer) exec(w *bufio.Writer, args []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		fmt.Fprint(w, "+PONG\r\n")
+	case "SET":
+		s.values[args[1]] = []byte(args[2])
+		delete(s.expires, args[1])
+		for i := 3; i+1 < len(args); i += 2 {
+			amount, _ := strconv.Atoi(args[i+1])
+			switch strings.ToUpper(args[i]) {
+			case "EX":
+				s.expires[args[1]] = time.Now().Add(time.Duration(amount) * time.Second)
+			case "PX":
+				s.expires[args[1]] = time.Now().Add(time.Duration(amount) * time.Millisecond)
+			}
+		}
+		fmt.Fprint(w, "+OK\r\n")
+	case "GET":
+		value, ok := s.get(args[1])
+		if !ok {
+			fmt.Fprint(w, "$-1\r\n")
+			return
+		}
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(value), value)
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.get(key); ok {
+				deleted++
+			}
+			delete(s.values, key)
+			delete(s.expires, key)
+		}
+		fmt.Fprintf(w, ":%d\r\n", deleted)
+	case "SCAN":
+		// All the keys are returned at once, with a cursor ending the iteration.
+		match := "*"
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				match = args[i+1]
+			}
+		}
+		keys := []string{}
+		for key := range s.values {
+			if _, ok := s.get(key); !ok {
+				continue
+			}
+			if ok, _ := path.Match(match, key); ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(w, "*2\r\n$1\r\n0\r\n*%d\r\n", len(keys))
+		for _, key := range keys {
+			fmt.Fprintf(w, "$%d\r\n%s\r\n", len(key), key)
+		}
+	default:
+		fmt.Fprintf(w, "-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+// get returns the value of a key, expiring it if needed. The lock must be held.
+func (s *respServer) get(key string) ([]byte, bool) {
+	value, ok := s.values[key]
+	if !ok {
+		return nil, false
+	}
+	if expires, ok := s.expires[key]; ok && time.Now().After(expires) {
+		delete(s.values, key)
+		delete(s.expires, key)
+		return nil, false
+	}
+	return value, true
+}
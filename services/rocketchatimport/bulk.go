@@ -0,0 +1,416 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package rocketchatimport
+
+import (
+	"archive/zip"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/services/bulkimport"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// bulkImportBotOwner is the owner of the Rocket.Chat bots.
+const bulkImportBotOwner = "rocketchatimport"
+
+// bulkConverter holds the state of the conversion of a Rocket.Chat export.
+type bulkConverter struct {
+	*bulkimport.Converter
+	export        *rocketChatExport
+	users         map[string]string      // Rocket.Chat user ID to username.
+	usernames     map[string]string      // Rocket.Chat username to username, for the mentions.
+	existingUsers map[string]*model.User // Rocket.Chat user ID to the existing user they are merged with.
+	channels      map[string]string      // Room ID of the rooms imported as channels to channel name.
+	roomNames     map[string]string      // Rocket.Chat room name to channel name, for the channel mentions.
+	directRooms   map[string][]string    // Room ID of the rooms imported as direct channels to their members.
+}
+
+var (
+	bulkMentionRegex        = regexp.MustCompile(`(^|[^\w@])@([\w.\-]+)`)
+	bulkChannelMentionRegex = regexp.MustCompile(`(^|[^\w#])#([\w.\-]+)`)
+)
+
+// ConvertToBulkImport converts the Rocket.Chat export zip file into a bulk import data
+// file, written to w, and returns the number of lines written. The public and private
+// rooms become channels, and the threads of the rooms become threads. The paths of the
+// attachments of the posts are the paths of the files in the Rocket.Chat export, which
+// has to be given as the attachments of the bulk import. The conversion is
+// deterministic, so that an interrupted import can be resumed from the same line of a
+// new conversion.
+func ConvertToBulkImport(zipReader *zip.Reader, w io.Writer, opts bulkimport.ConvertOptions) (int, *model.AppError) {
+	export, appErr := rocketChatParseExport(zipReader)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	conv := &bulkConverter{
+		Converter:     bulkimport.NewConverter(w, opts),
+		export:        export,
+		users:         make(map[string]string, len(export.users)),
+		usernames:     make(map[string]string, len(export.users)),
+		existingUsers: make(map[string]*model.User),
+		channels:      make(map[string]string),
+		roomNames:     make(map[string]string),
+		directRooms:   make(map[string][]string),
+	}
+	conv.resolveUsernames()
+
+	if err := conv.convert(); err != nil {
+		return conv.Lines(), model.NewAppError("ConvertToBulkImport", "api.rocketchatimport.bulk_convert.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return conv.Lines(), nil
+}
+
+func rocketChatIsBot(rUser rocketChatUser) bool {
+	return rUser.Type == "bot" || rUser.Type == "app"
+}
+
+func rocketChatUserEmail(rUser rocketChatUser) string {
+	if len(rUser.Emails) == 0 || rUser.Emails[0].Address == "" {
+		return strings.ToLower(rUser.Username) + "@example.com"
+	}
+	return strings.ToLower(rUser.Emails[0].Address)
+}
+
+func (conv *bulkConverter) resolveUsernames() {
+	// The usernames of the existing users are resolved first, so that no other user is
+	// given one of them.
+	taken := make(map[string]bool)
+	if conv.Opts.ExistingUser != nil {
+		for _, rUser := range conv.export.users {
+			if rocketChatIsBot(rUser) {
+				continue
+			}
+			if existingUser := conv.Opts.ExistingUser(rocketChatUserEmail(rUser)); existingUser != nil {
+				conv.existingUsers[rUser.Id] = existingUser
+				conv.users[rUser.Id] = existingUser.Username
+				taken[existingUser.Username] = true
+			}
+		}
+	}
+	for _, rUser := range conv.export.users {
+		if _, ok := conv.users[rUser.Id]; !ok {
+			conv.users[rUser.Id] = bulkimport.Username(rUser.Username, taken)
+		}
+		conv.usernames[rUser.Username] = conv.users[rUser.Id]
+	}
+}
+
+func (conv *bulkConverter) convert() error {
+	if err := conv.WriteVersion(); err != nil {
+		return err
+	}
+
+	members := make(map[string][]string)
+	for _, subscription := range conv.export.subscriptions {
+		members[subscription.RoomId] = append(members[subscription.RoomId], subscription.User.Id)
+	}
+
+	// Direct rooms with too many members for a group message become private channels.
+	memberships := make(map[string][]model.UserChannelImportData)
+	takenChannels := make(map[string]bool)
+	for _, room := range conv.export.rooms {
+		roomMembers := room.Uids
+		if len(roomMembers) == 0 {
+			roomMembers = members[room.Id]
+		}
+
+		displayName := room.FName
+		if displayName == "" {
+			displayName = room.Name
+		}
+		var channelType model.ChannelType
+		switch room.Type {
+		case rocketChatRoomPublic:
+			channelType = model.ChannelTypeOpen
+		case rocketChatRoomPrivate:
+			channelType = model.ChannelTypePrivate
+		case rocketChatRoomDirect:
+			usernames, ok := conv.directMembers(roomMembers)
+			if !ok {
+				mlog.Warn("Rocket.Chat Import: Unable to import the direct room as some of its members are not found in the export.", mlog.String("room_id", room.Id))
+				continue
+			}
+			if len(usernames) <= model.ChannelGroupMaxUsers {
+				conv.directRooms[room.Id] = usernames
+				continue
+			}
+			channelType = model.ChannelTypePrivate
+			displayName = strings.Join(usernames, ", ")
+		default:
+			mlog.Warn("Rocket.Chat Import: Unable to import the room as its type is not supported.", mlog.String("room_id", room.Id), mlog.String("room_type", room.Type))
+			continue
+		}
+
+		name := bulkimport.ChannelName(room.Name, room.Id)
+		if room.Type == rocketChatRoomDirect || takenChannels[name] {
+			name = bulkimport.ChannelName(room.Id, room.Id)
+		}
+		takenChannels[name] = true
+		if err := conv.WriteChannel(&model.ChannelImportData{
+			Name:        model.NewString(name),
+			DisplayName: model.NewString(displayName),
+			Type:        &channelType,
+			Header:      model.NewString(room.Topic),
+			Purpose:     model.NewString(room.Description),
+		}); err != nil {
+			return err
+		}
+		conv.channels[room.Id] = name
+		if room.Name != "" {
+			conv.roomNames[room.Name] = name
+		}
+		for _, member := range members[room.Id] {
+			memberships[member] = append(memberships[member], model.UserChannelImportData{Name: model.NewString(name)})
+		}
+	}
+
+	var bots []rocketChatUser
+	for _, rUser := range conv.export.users {
+		if rocketChatIsBot(rUser) {
+			bots = append(bots, rUser)
+			continue
+		}
+		if err := conv.WriteUser(conv.convertUser(rUser), conv.existingUsers[rUser.Id], memberships[rUser.Id]); err != nil {
+			return err
+		}
+	}
+	for _, rUser := range bots {
+		bot := &model.BotImportData{
+			Username:    model.NewString(conv.users[rUser.Id]),
+			DisplayName: model.NewString(rUser.Name),
+			Owner:       model.NewString(bulkImportBotOwner),
+		}
+		if !rUser.Active {
+			bot.DeleteAt = model.NewInt64(1)
+		}
+		if err := conv.WriteBot(bot, memberships[rUser.Id]); err != nil {
+			return err
+		}
+	}
+
+	for _, room := range conv.export.rooms {
+		if usernames, ok := conv.directRooms[room.Id]; ok {
+			if err := conv.WriteDirectChannel(usernames, room.Topic); err != nil {
+				return err
+			}
+		}
+	}
+
+	conv.convertMessages()
+	return conv.WritePosts()
+}
+
+func (conv *bulkConverter) convertUser(rUser rocketChatUser) *model.UserImportData {
+	user := &model.UserImportData{
+		Username: model.NewString(conv.users[rUser.Id]),
+		Email:    model.NewString(rocketChatUserEmail(rUser)),
+	}
+
+	if _, ok := conv.existingUsers[rUser.Id]; !ok && (len(rUser.Emails) == 0 || rUser.Emails[0].Address == "") {
+		mlog.Warn("Rocket.Chat Import: User does not have an email address in the Rocket.Chat export. Used username as a placeholder. The user should update their email address once logged in to the system.", mlog.String("user_email", *user.Email), mlog.String("user_name", rUser.Username))
+	}
+	names := strings.SplitN(rUser.Name, " ", 2)
+	user.FirstName = model.NewString(bulkimport.TruncateRunes(names[0], model.UserFirstNameMaxRunes))
+	if len(names) > 1 {
+		user.LastName = model.NewString(bulkimport.TruncateRunes(names[1], model.UserLastNameMaxRunes))
+	}
+	if !rUser.Active {
+		// The export doesn't tell when the user was deactivated.
+		user.DeleteAt = model.NewInt64(1)
+	}
+	return user
+}
+
+// directMembers returns the sorted usernames of the members of the direct room. The
+// room of the direct messages of a user to themself has a single member.
+func (conv *bulkConverter) directMembers(rocketChatUserIds []string) ([]string, bool) {
+	usernames := make([]string, 0, len(rocketChatUserIds))
+	for _, id := range rocketChatUserIds {
+		username, ok := conv.users[id]
+		if !ok {
+			return nil, false
+		}
+		usernames = append(usernames, username)
+	}
+	if len(usernames) == 0 {
+		return nil, false
+	}
+	if len(usernames) == 1 {
+		usernames = append(usernames, usernames[0])
+	}
+	sort.Strings(usernames)
+	return usernames, true
+}
+
+// convertMessages converts the messages into posts, with the thread replies added to
+// their root posts.
+func (conv *bulkConverter) convertMessages() {
+	states := make(map[string]*bulkimport.ChannelState)
+	for _, room := range conv.export.rooms {
+		states[room.Id] = &bulkimport.ChannelState{Header: room.Topic, Purpose: room.Description}
+	}
+	for _, message := range conv.export.messages {
+		if message.Hidden {
+			// The previous versions of the edited messages are hidden.
+			continue
+		}
+		channel, isChannel := conv.channels[message.RoomId]
+		members, isDirect := conv.directRooms[message.RoomId]
+		if !isChannel && !isDirect {
+			mlog.Warn("Rocket.Chat Import: Unable to import the message as its room is not imported.", mlog.String("message_id", message.Id), mlog.String("room_id", message.RoomId))
+			continue
+		}
+		state, ok := states[message.RoomId]
+		if !ok {
+			state = &bulkimport.ChannelState{}
+			states[message.RoomId] = state
+		}
+		post := conv.convertMessage(message, state)
+		if post == nil {
+			continue
+		}
+
+		if message.ThreadId != "" && conv.AddReply(message.ThreadId, post) {
+			continue
+		}
+
+		if isChannel {
+			conv.AddPost(channel, post, message.Id)
+		} else {
+			conv.AddDirectPost(members, post, message.Id)
+		}
+	}
+}
+
+// convertMessage converts the Rocket.Chat message into a post, or returns nil if it can't be imported.
+func (conv *bulkConverter) convertMessage(message rocketChatMessage, state *bulkimport.ChannelState) *model.PostImportData {
+	username, ok := conv.users[message.User.Id]
+	if !ok {
+		mlog.Debug("Rocket.Chat Import: Unable to add the message as the Rocket.Chat user does not exist in the export.", mlog.String("user_id", message.User.Id))
+		return nil
+	}
+
+	post := &model.PostImportData{
+		User:     model.NewString(username),
+		Message:  model.NewString(conv.convertText(message.Message)),
+		CreateAt: model.NewInt64(int64(message.TimeStamp)),
+	}
+	if message.Pinned {
+		post.IsPinned = model.NewBool(true)
+	}
+	if message.EditedAt != nil {
+		post.EditAt = model.NewInt64(int64(*message.EditedAt))
+	}
+
+	switch message.Type {
+	case "":
+		post.Attachments = conv.convertFiles(message)
+	case "uj", "ul":
+		post.Type = model.NewString(model.PostTypeJoinChannel)
+		if message.Type == "ul" {
+			post.Type = model.NewString(model.PostTypeLeaveChannel)
+		}
+		post.Message = model.NewString("")
+		post.Props = &model.StringInterface{"username": *post.User}
+	case "au", "ru":
+		target, ok := conv.usernames[message.Message]
+		if !ok {
+			mlog.Debug("Rocket.Chat Import: Unable to add the message as the Rocket.Chat user does not exist in the export.", mlog.String("username", message.Message))
+			return nil
+		}
+		post.Message = model.NewString("")
+		if message.Type == "au" {
+			post.Type = model.NewString(model.PostTypeAddToChannel)
+			post.Props = &model.StringInterface{"username": *post.User, "addedUsername": target}
+		} else {
+			post.Type = model.NewString(model.PostTypeRemoveFromChannel)
+			post.Props = &model.StringInterface{"removedUsername": target}
+		}
+	case "room_changed_topic":
+		post.Type = model.NewString(model.PostTypeHeaderChange)
+		post.Message = model.NewString("")
+		post.Props = &model.StringInterface{"username": *post.User, "old_header": state.Header, "new_header": message.Message}
+		state.Header = message.Message
+	case "room_changed_description":
+		post.Type = model.NewString(model.PostTypePurposeChange)
+		post.Message = model.NewString("")
+		post.Props = &model.StringInterface{"username": *post.User, "old_purpose": state.Purpose, "new_purpose": message.Message}
+		state.Purpose = message.Message
+	case "r":
+		post.Type = model.NewString(model.PostTypeDisplaynameChange)
+		post.Message = model.NewString("")
+		post.Props = &model.StringInterface{"username": *post.User, "new_displayname": message.Message}
+	default:
+		mlog.Warn("Rocket.Chat Import: Unable to import the message as its type is not supported", mlog.String("message_id", message.Id), mlog.String("message_type", message.Type))
+		return nil
+	}
+
+	var emojis []string
+	for emoji := range message.Reactions {
+		emojis = append(emojis, emoji)
+	}
+	sort.Strings(emojis)
+	var reactions []model.ReactionImportData
+	for _, emoji := range emojis {
+		for _, rUsername := range message.Reactions[emoji].Usernames {
+			if username, ok := conv.usernames[rUsername]; ok {
+				reactions = append(reactions, model.ReactionImportData{User: model.NewString(username), CreateAt: post.CreateAt, EmojiName: model.NewString(strings.Trim(emoji, ":"))})
+			}
+		}
+	}
+	if len(reactions) > 0 {
+		post.Reactions = &reactions
+	}
+
+	return post
+}
+
+// convertText converts the mentions of the users and channels of the message to the
+// names they are imported with.
+func (conv *bulkConverter) convertText(text string) string {
+	text = bulkMentionRegex.ReplaceAllStringFunc(text, func(mention string) string {
+		match := bulkMentionRegex.FindStringSubmatch(mention)
+		if username, ok := conv.usernames[match[2]]; ok {
+			return match[1] + "@" + username
+		}
+		return mention
+	})
+	return bulkChannelMentionRegex.ReplaceAllStringFunc(text, func(mention string) string {
+		match := bulkChannelMentionRegex.FindStringSubmatch(mention)
+		if name, ok := conv.roomNames[match[2]]; ok {
+			return match[1] + "~" + name
+		}
+		return mention
+	})
+}
+
+func (conv *bulkConverter) convertFiles(message rocketChatMessage) *[]model.AttachmentImportData {
+	files := message.Files
+	if len(files) == 0 && message.File != nil {
+		files = []*rocketChatFile{message.File}
+	}
+
+	var attachments []model.AttachmentImportData
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+		upload, ok := conv.export.uploads[file.Id]
+		if !ok {
+			mlog.Warn("Rocket.Chat Import: Unable to import file as the file is missing from the Rocket.Chat export zip file.", mlog.String("file_id", file.Id))
+			continue
+		}
+		attachments = append(attachments, model.AttachmentImportData{Path: model.NewString(upload.Name)})
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+	return &attachments
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package rocketchatimport
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/services/bulkimport"
+)
+
+func makeRocketChatExportZip(t *testing.T, files map[string]string) *zip.Reader {
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+
+	zipReader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return zipReader
+}
+
+func convertRocketChatExport(t *testing.T, zipReader *zip.Reader, opts bulkimport.ConvertOptions) []model.LineImportData {
+	buf := &bytes.Buffer{}
+	count, appErr := ConvertToBulkImport(zipReader, buf, opts)
+	require.Nil(t, appErr)
+
+	var lines []model.LineImportData
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var line model.LineImportData
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, count)
+	return lines
+}
+
+func TestConvertToBulkImport(t *testing.T) {
+	zipReader := makeRocketChatExportZip(t, map[string]string{
+		// The collections are exported either as JSON arrays or as one document per line.
+		"dump/users.json": `[
+			{"_id": "u1", "username": "Alice", "name": "Alice Smith", "emails": [{"address": "alice@example.com"}], "active": true, "type": "user"},
+			{"_id": "u2", "username": "bob", "name": "Bob", "active": false, "type": "user"},
+			{"_id": "u3", "username": "carol", "name": "Carol", "emails": [{"address": "carol@example.com"}], "active": true, "type": "user"},
+			{"_id": "rocket.cat", "username": "rocket.cat", "name": "Rocket.Cat", "active": true, "type": "bot"}
+		]`,
+		"dump/rocketchat_room.json": `{"_id": "GENERAL", "t": "c", "name": "general", "fname": "General", "topic": "Topic", "description": "Purpose"}
+{"_id": "r2", "t": "p", "name": "secret"}
+{"_id": "u1u2", "t": "d", "uids": ["u2", "u1"]}
+{"_id": "l1", "t": "l", "name": "livechat"}`,
+		"dump/rocketchat_subscription.json": `[
+			{"rid": "GENERAL", "u": {"_id": "u1", "username": "Alice"}},
+			{"rid": "GENERAL", "u": {"_id": "rocket.cat", "username": "rocket.cat"}},
+			{"rid": "r2", "u": {"_id": "u1", "username": "Alice"}}
+		]`,
+		"dump/rocketchat_message.json": `[
+			{"_id": "m1", "rid": "GENERAL", "msg": "Hello @bob, see #secret", "ts": {"$date": "2021-01-01T00:00:00.500Z"}, "u": {"_id": "u1", "username": "Alice"},
+				"pinned": true, "reactions": {":tada:": {"usernames": ["bob", "unknown"]}}},
+			{"_id": "m2", "rid": "GENERAL", "msg": "Reply", "ts": {"$date": 1609459201000}, "u": {"_id": "u2", "username": "bob"}, "tmid": "m1",
+				"editedAt": {"$date": {"$numberLong": "1609459300000"}}},
+			{"_id": "m2h", "rid": "GENERAL", "msg": "Rep", "ts": {"$date": 1609459201000}, "u": {"_id": "u2", "username": "bob"}, "_hidden": true},
+			{"_id": "m3", "rid": "GENERAL", "msg": "", "ts": {"$date": 1609459202000}, "u": {"_id": "u3", "username": "carol"},
+				"files": [{"_id": "f1", "name": "file.txt"}, {"_id": "f2", "name": "missing.txt"}]},
+			{"_id": "m4", "rid": "GENERAL", "msg": "New topic", "t": "room_changed_topic", "ts": {"$date": 1609459203000}, "u": {"_id": "u1", "username": "Alice"}},
+			{"_id": "m5", "rid": "GENERAL", "msg": "` + strings.Repeat("a", 25) + `", "ts": {"$date": 1609459204000}, "u": {"_id": "rocket.cat", "username": "rocket.cat"}},
+			{"_id": "m6", "rid": "GENERAL", "msg": "Unknown user", "ts": {"$date": 1609459205000}, "u": {"_id": "u9", "username": "unknown"}},
+			{"_id": "m7", "rid": "u1u2", "msg": "Direct", "ts": {"$date": 1609459206000}, "u": {"_id": "u2", "username": "bob"}},
+			{"_id": "m8", "rid": "l1", "msg": "Livechat", "ts": {"$date": 1609459207000}, "u": {"_id": "u1", "username": "Alice"}}
+		]`,
+		"dump/uploads/f1/file.txt": "content",
+	})
+
+	opts := bulkimport.ConvertOptions{
+		TeamName:    "team",
+		MaxPostSize: 10,
+		ExistingUser: func(email string) *model.User {
+			if email == "carol@example.com" {
+				return &model.User{Username: "carol.existing", Email: email, Locale: "fr"}
+			}
+			return nil
+		},
+	}
+	lines := convertRocketChatExport(t, zipReader, opts)
+
+	var types []string
+	for _, line := range lines {
+		types = append(types, line.Type)
+	}
+	assert.Equal(t, []string{"version", "channel", "channel", "user", "user", "user", "bot", "direct_channel", "post", "post", "post", "post", "direct_post"}, types)
+
+	t.Run("channels", func(t *testing.T) {
+		channel := lines[1].Channel
+		assert.Equal(t, "team", *channel.Team)
+		assert.Equal(t, "general", *channel.Name)
+		assert.Equal(t, "General", *channel.DisplayName)
+		assert.Equal(t, model.ChannelTypeOpen, *channel.Type)
+		assert.Equal(t, "Topic", *channel.Header)
+		assert.Equal(t, "Purpose", *channel.Purpose)
+
+		assert.Equal(t, "secret", *lines[2].Channel.Name)
+		assert.Equal(t, model.ChannelTypePrivate, *lines[2].Channel.Type)
+	})
+
+	t.Run("users", func(t *testing.T) {
+		alice := lines[3].User
+		assert.Equal(t, "alice", *alice.Username)
+		assert.Equal(t, "Alice", *alice.FirstName)
+		assert.Equal(t, "Smith", *alice.LastName)
+		require.Len(t, *(*alice.Teams)[0].Channels, 2)
+
+		bob := lines[4].User
+		assert.Equal(t, "bob", *bob.Username)
+		assert.Equal(t, "bob@example.com", *bob.Email)
+		assert.NotZero(t, *bob.DeleteAt)
+
+		carol := lines[5].User
+		assert.Equal(t, "carol.existing", *carol.Username)
+		assert.Nil(t, carol.FirstName)
+		assert.Equal(t, "fr", *carol.Locale)
+
+		bot := lines[6].Bot
+		assert.Equal(t, "rocket.cat", *bot.Username)
+		assert.Equal(t, bulkImportBotOwner, *bot.Owner)
+		require.Len(t, *(*bot.Teams)[0].Channels, 1)
+	})
+
+	t.Run("thread", func(t *testing.T) {
+		post := lines[8].Post
+		assert.Equal(t, "alice", *post.User)
+		assert.Equal(t, "Hello @bob", *post.Message)
+		assert.EqualValues(t, 1609459200500, *post.CreateAt)
+		assert.True(t, *post.IsPinned)
+		require.Len(t, *post.Reactions, 1)
+		assert.Equal(t, "bob", *(*post.Reactions)[0].User)
+		assert.Equal(t, "tada", *(*post.Reactions)[0].EmojiName)
+
+		replies := *post.Replies
+		require.Len(t, replies, 3)
+		assert.Equal(t, ", see ~secret", *replies[0].Message+*replies[1].Message)
+		reply := replies[2]
+		assert.Equal(t, "bob", *reply.User)
+		assert.Equal(t, "Reply", *reply.Message)
+		assert.EqualValues(t, 1609459201000, *reply.CreateAt)
+		assert.EqualValues(t, 1609459300000, *reply.EditAt)
+	})
+
+	t.Run("attachments", func(t *testing.T) {
+		post := lines[9].Post
+		assert.Equal(t, "carol.existing", *post.User)
+		require.Len(t, *post.Attachments, 1)
+		assert.Equal(t, "dump/uploads/f1/file.txt", *(*post.Attachments)[0].Path)
+	})
+
+	t.Run("system and bot messages", func(t *testing.T) {
+		post := lines[10].Post
+		assert.Equal(t, model.PostTypeHeaderChange, *post.Type)
+		assert.Equal(t, "Topic", (*post.Props)["old_header"])
+		assert.Equal(t, "New topic", (*post.Props)["new_header"])
+
+		post = lines[11].Post
+		assert.Equal(t, "rocket.cat", *post.User)
+		assert.Equal(t, strings.Repeat("a", 10), *post.Message)
+		require.Len(t, *post.Replies, 2)
+	})
+
+	t.Run("direct messages", func(t *testing.T) {
+		assert.Equal(t, []string{"alice", "bob"}, *lines[7].DirectChannel.Members)
+
+		post := lines[12].DirectPost
+		assert.Equal(t, []string{"alice", "bob"}, *post.ChannelMembers)
+		assert.Equal(t, "bob", *post.User)
+		assert.Equal(t, "Direct", *post.Message)
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		assert.Equal(t, lines, convertRocketChatExport(t, zipReader, opts))
+	})
+
+	t.Run("missing messages", func(t *testing.T) {
+		_, appErr := ConvertToBulkImport(makeRocketChatExportZip(t, map[string]string{"users.json": "[]"}), &bytes.Buffer{}, opts)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.rocketchatimport.parse.missing_messages.app_error", appErr.Id)
+	})
+}
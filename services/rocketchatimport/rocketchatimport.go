@@ -0,0 +1,266 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package rocketchatimport
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// The types of the Rocket.Chat rooms.
+const (
+	rocketChatRoomPublic  = "c"
+	rocketChatRoomPrivate = "p"
+	rocketChatRoomDirect  = "d"
+)
+
+// rocketChatDate is a date exported by mongoexport, either as {"$date": ...} holding an
+// ISO 8601 string, a number of milliseconds or {"$numberLong": ...}, or as a bare value.
+type rocketChatDate int64
+
+func (d *rocketChatDate) UnmarshalJSON(data []byte) error {
+	var wrapped struct {
+		Date       json.RawMessage `json:"$date"`
+		NumberLong string          `json:"$numberLong"`
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return err
+		}
+		if wrapped.NumberLong != "" {
+			data = []byte(wrapped.NumberLong)
+		} else {
+			return d.UnmarshalJSON(wrapped.Date)
+		}
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		// A number of milliseconds.
+		s = string(data)
+	} else if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		*d = rocketChatDate(model.GetMillisForTime(t))
+		return nil
+	}
+	millis, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return errors.New("invalid date " + string(data))
+	}
+	*d = rocketChatDate(millis)
+	return nil
+}
+
+type rocketChatUser struct {
+	Id       string `json:"_id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Emails   []struct {
+		Address string `json:"address"`
+	} `json:"emails"`
+	Active bool   `json:"active"`
+	Type   string `json:"type"`
+}
+
+type rocketChatRoom struct {
+	Id          string   `json:"_id"`
+	Type        string   `json:"t"`
+	Name        string   `json:"name"`
+	FName       string   `json:"fname"`
+	Topic       string   `json:"topic"`
+	Description string   `json:"description"`
+	Uids        []string `json:"uids"`
+	Usernames   []string `json:"usernames"`
+}
+
+type rocketChatUserRef struct {
+	Id       string `json:"_id"`
+	Username string `json:"username"`
+}
+
+type rocketChatSubscription struct {
+	RoomId string            `json:"rid"`
+	User   rocketChatUserRef `json:"u"`
+}
+
+type rocketChatFile struct {
+	Id   string `json:"_id"`
+	Name string `json:"name"`
+}
+
+type rocketChatReaction struct {
+	Usernames []string `json:"usernames"`
+}
+
+type rocketChatMessage struct {
+	Id        string                        `json:"_id"`
+	RoomId    string                        `json:"rid"`
+	Message   string                        `json:"msg"`
+	TimeStamp rocketChatDate                `json:"ts"`
+	User      rocketChatUserRef             `json:"u"`
+	ThreadId  string                        `json:"tmid"`
+	Type      string                        `json:"t"`
+	EditedAt  *rocketChatDate               `json:"editedAt"`
+	Pinned    bool                          `json:"pinned"`
+	Hidden    bool                          `json:"_hidden"`
+	Reactions map[string]rocketChatReaction `json:"reactions"`
+	File      *rocketChatFile               `json:"file"`
+	Files     []*rocketChatFile             `json:"files"`
+}
+
+// rocketChatExport holds the parsed collections of a Rocket.Chat database export.
+type rocketChatExport struct {
+	users         []rocketChatUser
+	rooms         []rocketChatRoom
+	subscriptions []rocketChatSubscription
+	messages      []rocketChatMessage
+	// uploads are the files of the export, by the id of their upload in Rocket.Chat.
+	uploads map[string]*zip.File
+}
+
+// rocketChatParseDocuments parses the documents of the collection exported by mongoexport,
+// either as a JSON array or as one document per line.
+func rocketChatParseDocuments(file *zip.File) ([]json.RawMessage, *model.AppError) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, model.NewAppError("RocketChatImport", "api.rocketchatimport.parse.open.app_error", map[string]interface{}{"Filename": file.Name}, err.Error(), http.StatusBadRequest)
+	}
+	defer reader.Close()
+
+	bufReader := bufio.NewReader(reader)
+	first, err := bufReader.Peek(1)
+	for err == nil && (first[0] == ' ' || first[0] == '\t' || first[0] == '\r' || first[0] == '\n') {
+		bufReader.ReadByte()
+		first, err = bufReader.Peek(1)
+	}
+
+	decoder := json.NewDecoder(bufReader)
+	var documents []json.RawMessage
+	if err == nil && first[0] == '[' {
+		err = decoder.Decode(&documents)
+	} else if err == nil {
+		for {
+			var document json.RawMessage
+			if err = decoder.Decode(&document); err != nil {
+				break
+			}
+			documents = append(documents, document)
+		}
+	}
+	if err != nil && err != io.EOF {
+		return nil, model.NewAppError("RocketChatImport", "api.rocketchatimport.parse.decode.app_error", map[string]interface{}{"Filename": file.Name}, err.Error(), http.StatusBadRequest)
+	}
+	return documents, nil
+}
+
+func rocketChatParseCollection(file *zip.File, add func(document json.RawMessage) error) *model.AppError {
+	if file == nil {
+		return nil
+	}
+	documents, appErr := rocketChatParseDocuments(file)
+	if appErr != nil {
+		return appErr
+	}
+	for _, document := range documents {
+		if err := add(document); err != nil {
+			return model.NewAppError("RocketChatImport", "api.rocketchatimport.parse.decode.app_error", map[string]interface{}{"Filename": file.Name}, err.Error(), http.StatusBadRequest)
+		}
+	}
+	return nil
+}
+
+// rocketChatParseExport parses the Rocket.Chat export zip file, holding the users,
+// rocketchat_room, rocketchat_subscription and rocketchat_message collections exported
+// by mongoexport in the same directory, and the uploaded files in its uploads directory.
+// An uploaded file is either named after the id of its upload, or in a directory named
+// after it.
+func rocketChatParseExport(zipReader *zip.Reader) (*rocketChatExport, *model.AppError) {
+	var messagesFile *zip.File
+	for _, file := range zipReader.File {
+		if path.Base(file.Name) == "rocketchat_message.json" && (messagesFile == nil || len(file.Name) < len(messagesFile.Name)) {
+			messagesFile = file
+		}
+	}
+	if messagesFile == nil {
+		return nil, model.NewAppError("RocketChatImport", "api.rocketchatimport.parse.missing_messages.app_error", nil, "", http.StatusBadRequest)
+	}
+	base := path.Dir(messagesFile.Name)
+	if base == "." {
+		base = ""
+	} else {
+		base += "/"
+	}
+
+	export := &rocketChatExport{uploads: make(map[string]*zip.File)}
+	collections := make(map[string]*zip.File)
+	for _, file := range zipReader.File {
+		if !strings.HasPrefix(file.Name, base) || strings.HasSuffix(file.Name, "/") {
+			continue
+		}
+		name := strings.TrimPrefix(file.Name, base)
+		if strings.HasPrefix(name, "uploads/") {
+			id := strings.SplitN(strings.TrimPrefix(name, "uploads/"), "/", 2)[0]
+			if _, ok := export.uploads[id]; !ok {
+				export.uploads[id] = file
+			}
+		} else {
+			collections[name] = file
+		}
+	}
+
+	if appErr := rocketChatParseCollection(collections["users.json"], func(document json.RawMessage) error {
+		var user rocketChatUser
+		err := json.Unmarshal(document, &user)
+		export.users = append(export.users, user)
+		return err
+	}); appErr != nil {
+		return nil, appErr
+	}
+	if appErr := rocketChatParseCollection(collections["rocketchat_room.json"], func(document json.RawMessage) error {
+		var room rocketChatRoom
+		err := json.Unmarshal(document, &room)
+		export.rooms = append(export.rooms, room)
+		return err
+	}); appErr != nil {
+		return nil, appErr
+	}
+	if appErr := rocketChatParseCollection(collections["rocketchat_subscription.json"], func(document json.RawMessage) error {
+		var subscription rocketChatSubscription
+		err := json.Unmarshal(document, &subscription)
+		export.subscriptions = append(export.subscriptions, subscription)
+		return err
+	}); appErr != nil {
+		return nil, appErr
+	}
+	if appErr := rocketChatParseCollection(messagesFile, func(document json.RawMessage) error {
+		var message rocketChatMessage
+		err := json.Unmarshal(document, &message)
+		export.messages = append(export.messages, message)
+		return err
+	}); appErr != nil {
+		return nil, appErr
+	}
+
+	sort.SliceStable(export.users, func(i, j int) bool { return export.users[i].Id < export.users[j].Id })
+	sort.SliceStable(export.rooms, func(i, j int) bool { return export.rooms[i].Id < export.rooms[j].Id })
+	sort.SliceStable(export.messages, func(i, j int) bool {
+		if export.messages[i].TimeStamp != export.messages[j].TimeStamp {
+			return export.messages[i].TimeStamp < export.messages[j].TimeStamp
+		}
+		return export.messages[i].Id < export.messages[j].Id
+	})
+
+	return export, nil
+}
@@ -59,6 +59,31 @@ func (_m *MockAppIface) AddUserToTeamByTeamId(c *request.Context, teamId string,
 	return r0
 }
 
+// CreateChannelBookmark provides a mock function with given fields: c, bookmark
+func (_m *MockAppIface) CreateChannelBookmark(c *request.Context, bookmark *model.ChannelBookmark) (*model.ChannelBookmark, *model.AppError) {
+	ret := _m.Called(c, bookmark)
+
+	var r0 *model.ChannelBookmark
+	if rf, ok := ret.Get(0).(func(*request.Context, *model.ChannelBookmark) *model.ChannelBookmark); ok {
+		r0 = rf(c, bookmark)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ChannelBookmark)
+		}
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(*request.Context, *model.ChannelBookmark) *model.AppError); ok {
+		r1 = rf(c, bookmark)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // CreateChannelWithUser provides a mock function with given fields: c, channel, userId
 func (_m *MockAppIface) CreateChannelWithUser(c *request.Context, channel *model.Channel, userId string) (*model.Channel, *model.AppError) {
 	ret := _m.Called(c, channel, userId)
@@ -134,6 +159,31 @@ func (_m *MockAppIface) CreateUploadSession(us *model.UploadSession) (*model.Upl
 	return r0, r1
 }
 
+// DeleteChannelBookmark provides a mock function with given fields: bookmarkID
+func (_m *MockAppIface) DeleteChannelBookmark(bookmarkID string) (*model.ChannelBookmark, *model.AppError) {
+	ret := _m.Called(bookmarkID)
+
+	var r0 *model.ChannelBookmark
+	if rf, ok := ret.Get(0).(func(string) *model.ChannelBookmark); ok {
+		r0 = rf(bookmarkID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ChannelBookmark)
+		}
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(string) *model.AppError); ok {
+		r1 = rf(bookmarkID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // DeletePost provides a mock function with given fields: postID, deleteByID
 func (_m *MockAppIface) DeletePost(postID string, deleteByID string) (*model.Post, *model.AppError) {
 	ret := _m.Called(postID, deleteByID)
@@ -372,6 +422,31 @@ func (_m *MockAppIface) SendEphemeralPost(userId string, post *model.Post) *mode
 	return r0
 }
 
+// UpdateChannelBookmark provides a mock function with given fields: c, bookmark
+func (_m *MockAppIface) UpdateChannelBookmark(c *request.Context, bookmark *model.ChannelBookmark) (*model.ChannelBookmark, *model.AppError) {
+	ret := _m.Called(c, bookmark)
+
+	var r0 *model.ChannelBookmark
+	if rf, ok := ret.Get(0).(func(*request.Context, *model.ChannelBookmark) *model.ChannelBookmark); ok {
+		r0 = rf(c, bookmark)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ChannelBookmark)
+		}
+	}
+
+	var r1 *model.AppError
+	if rf, ok := ret.Get(1).(func(*request.Context, *model.ChannelBookmark) *model.AppError); ok {
+		r1 = rf(c, bookmark)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // UpdatePost provides a mock function with given fields: c, post, safeUpdate
 func (_m *MockAppIface) UpdatePost(c *request.Context, post *model.Post, safeUpdate bool) (*model.Post, *model.AppError) {
 	ret := _m.Called(c, post, safeUpdate)
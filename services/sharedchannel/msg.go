@@ -9,14 +9,16 @@ import (
 	"github.com/cjdelisle/matterfoss-server/v6/model"
 )
 
-// syncMsg represents a change in content (post add/edit/delete, reaction add/remove, users).
+// syncMsg represents a change in content (post add/edit/delete, reaction add/remove, users,
+// channel bookmarks).
 // It is sent to remote clusters as the payload of a `RemoteClusterMsg`.
 type syncMsg struct {
-	Id        string                 `json:"id"`
-	ChannelId string                 `json:"channel_id"`
-	Users     map[string]*model.User `json:"users,omitempty"`
-	Posts     []*model.Post          `json:"posts,omitempty"`
-	Reactions []*model.Reaction      `json:"reactions,omitempty"`
+	Id        string                   `json:"id"`
+	ChannelId string                   `json:"channel_id"`
+	Users     map[string]*model.User   `json:"users,omitempty"`
+	Posts     []*model.Post            `json:"posts,omitempty"`
+	Reactions []*model.Reaction        `json:"reactions,omitempty"`
+	Bookmarks []*model.ChannelBookmark `json:"bookmarks,omitempty"`
 }
 
 func newSyncMsg(channelID string) *syncMsg {
@@ -13,4 +13,6 @@ type SyncResponse struct {
 
 	ReactionsLastUpdateAt int64    `json:"reactions_last_update_at"`
 	ReactionErrors        []string `json:"reaction_errors"`
+
+	BookmarkErrors []string `json:"bookmark_errors"`
 }
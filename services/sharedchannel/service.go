@@ -56,6 +56,9 @@ type AppIface interface {
 	DeletePost(postID, deleteByID string) (*model.Post, *model.AppError)
 	SaveReactionForPost(c *request.Context, reaction *model.Reaction) (*model.Reaction, *model.AppError)
 	DeleteReactionForPost(c *request.Context, reaction *model.Reaction) *model.AppError
+	CreateChannelBookmark(c *request.Context, bookmark *model.ChannelBookmark) (*model.ChannelBookmark, *model.AppError)
+	UpdateChannelBookmark(c *request.Context, bookmark *model.ChannelBookmark) (*model.ChannelBookmark, *model.AppError)
+	DeleteChannelBookmark(bookmarkID string) (*model.ChannelBookmark, *model.AppError)
 	PatchChannelModerationsForChannel(channel *model.Channel, channelModerationsPatch []*model.ChannelModerationPatch) ([]*model.ChannelModeration, *model.AppError)
 	CreateUploadSession(us *model.UploadSession) (*model.UploadSession, *model.AppError)
 	FileReader(path string) (filestore.ReadCloseSeeker, *model.AppError)
@@ -51,6 +51,7 @@ func (scs *Service) processSyncMessage(syncMsg *syncMsg, rc *model.RemoteCluster
 		UsersSyncd:     make([]string, 0),
 		PostErrors:     make([]string, 0),
 		ReactionErrors: make([]string, 0),
+		BookmarkErrors: make([]string, 0),
 	}
 
 	scs.server.GetLogger().Log(mlog.LvlSharedChannelServiceDebug, "Sync msg received",
@@ -59,6 +60,7 @@ func (scs *Service) processSyncMessage(syncMsg *syncMsg, rc *model.RemoteCluster
 		mlog.Int("user_count", len(syncMsg.Users)),
 		mlog.Int("post_count", len(syncMsg.Posts)),
 		mlog.Int("reaction_count", len(syncMsg.Reactions)),
+		mlog.Int("bookmark_count", len(syncMsg.Bookmarks)),
 	)
 
 	if channel, err = scs.server.GetStore().Channel().Get(syncMsg.ChannelId, true); err != nil {
@@ -160,6 +162,30 @@ func (scs *Service) processSyncMessage(syncMsg *syncMsg, rc *model.RemoteCluster
 		}
 	}
 
+	// add/update/remove channel bookmarks
+	for _, bookmark := range syncMsg.Bookmarks {
+		if syncMsg.ChannelId != bookmark.ChannelId {
+			scs.server.GetLogger().Log(mlog.LvlSharedChannelServiceError, "ChannelId mismatch",
+				mlog.String("remote", rc.Name),
+				mlog.String("sm.ChannelId", syncMsg.ChannelId),
+				mlog.String("sm.Bookmark.ChannelId", bookmark.ChannelId),
+				mlog.String("BookmarkId", bookmark.Id),
+			)
+			syncResp.BookmarkErrors = append(syncResp.BookmarkErrors, bookmark.Id)
+			continue
+		}
+
+		if err := scs.upsertSyncBookmark(bookmark, rc); err != nil {
+			syncResp.BookmarkErrors = append(syncResp.BookmarkErrors, bookmark.Id)
+			scs.server.GetLogger().Log(mlog.LvlSharedChannelServiceError, "Error upserting sync bookmark",
+				mlog.String("bookmark_id", bookmark.Id),
+				mlog.String("channel_id", bookmark.ChannelId),
+				mlog.String("remote", rc.Name),
+				mlog.Err(err),
+			)
+		}
+	}
+
 	response.SetPayload(syncResp)
 
 	return nil
@@ -397,3 +423,74 @@ func (scs *Service) upsertSyncReaction(reaction *model.Reaction, rc *model.Remot
 	}
 	return savedReaction, err
 }
+
+func (scs *Service) upsertSyncBookmark(bookmark *model.ChannelBookmark, rc *model.RemoteCluster) error {
+	var appErr *model.AppError
+
+	bookmark.RemoteId = model.NewString(rc.RemoteId)
+	bookmark.FileInfo = nil
+
+	rbookmark, err := scs.server.GetStore().ChannelBookmark().Get(bookmark.Id, true)
+	if err != nil {
+		if _, ok := err.(errNotFound); !ok {
+			return fmt.Errorf("error checking sync bookmark: %w", err)
+		}
+	}
+
+	switch {
+	case rbookmark == nil && bookmark.DeleteAt == 0:
+		// bookmark doesn't exist; create new one
+		_, appErr = scs.app.CreateChannelBookmark(request.EmptyContext(), bookmark)
+		if appErr == nil {
+			scs.server.GetLogger().Log(mlog.LvlSharedChannelServiceDebug, "Created sync bookmark",
+				mlog.String("bookmark_id", bookmark.Id),
+				mlog.String("channel_id", bookmark.ChannelId),
+			)
+		}
+	case rbookmark == nil || rbookmark.DeleteAt > 0:
+		// nothing to update; a deleted bookmark stays deleted.
+		scs.server.GetLogger().Log(mlog.LvlSharedChannelServiceDebug, "Update to sync bookmark ignored",
+			mlog.String("bookmark_id", bookmark.Id),
+			mlog.String("channel_id", bookmark.ChannelId),
+		)
+	case bookmark.DeleteAt > 0:
+		// delete bookmark
+		_, appErr = scs.app.DeleteChannelBookmark(bookmark.Id)
+		if appErr == nil {
+			scs.server.GetLogger().Log(mlog.LvlSharedChannelServiceDebug, "Deleted sync bookmark",
+				mlog.String("bookmark_id", bookmark.Id),
+				mlog.String("channel_id", bookmark.ChannelId),
+			)
+		}
+	case isBookmarkChanged(rbookmark, bookmark):
+		// update bookmark
+		_, appErr = scs.app.UpdateChannelBookmark(request.EmptyContext(), bookmark)
+		if appErr == nil {
+			scs.server.GetLogger().Log(mlog.LvlSharedChannelServiceDebug, "Updated sync bookmark",
+				mlog.String("bookmark_id", bookmark.Id),
+				mlog.String("channel_id", bookmark.ChannelId),
+			)
+		}
+	default:
+		// nothing to update
+		scs.server.GetLogger().Log(mlog.LvlSharedChannelServiceDebug, "Update to sync bookmark ignored",
+			mlog.String("bookmark_id", bookmark.Id),
+			mlog.String("channel_id", bookmark.ChannelId),
+		)
+	}
+
+	if appErr != nil {
+		return errors.New(appErr.Error())
+	}
+	return nil
+}
+
+// isBookmarkChanged tells whether the synchronized bookmark differs from the local copy.
+func isBookmarkChanged(local, synced *model.ChannelBookmark) bool {
+	return local.DisplayName != synced.DisplayName ||
+		local.LinkUrl != synced.LinkUrl ||
+		local.ImageUrl != synced.ImageUrl ||
+		local.Emoji != synced.Emoji ||
+		local.FileId != synced.FileId ||
+		local.SortOrder != synced.SortOrder
+}
@@ -32,6 +32,7 @@ type syncData struct {
 	profileImages map[string]*model.User
 	posts         []*model.Post
 	reactions     []*model.Reaction
+	bookmarks     []*model.ChannelBookmark
 	attachments   []attachment
 
 	resultRepeat     bool
@@ -50,7 +51,7 @@ func newSyncData(task syncTask, rc *model.RemoteCluster, scr *model.SharedChanne
 }
 
 func (sd *syncData) isEmpty() bool {
-	return len(sd.users) == 0 && len(sd.profileImages) == 0 && len(sd.posts) == 0 && len(sd.reactions) == 0 && len(sd.attachments) == 0
+	return len(sd.users) == 0 && len(sd.profileImages) == 0 && len(sd.posts) == 0 && len(sd.reactions) == 0 && len(sd.bookmarks) == 0 && len(sd.attachments) == 0
 }
 
 func (sd *syncData) isCursorChanged() bool {
@@ -79,6 +80,7 @@ func (scs *Service) syncForRemote(task syncTask, rc *model.RemoteCluster) error
 		sd.users = task.retryMsg.Users
 		sd.posts = task.retryMsg.Posts
 		sd.reactions = task.retryMsg.Reactions
+		sd.bookmarks = task.retryMsg.Bookmarks
 		return scs.sendSyncData(sd)
 	}
 
@@ -114,7 +116,12 @@ func (scs *Service) syncForRemote(task syncTask, rc *model.RemoteCluster) error
 		return fmt.Errorf("cannot fetch reactions for sync %v: %w", sd, err)
 	}
 
-	// fetch users associated with posts & reactions
+	// fetch channel bookmarks
+	if err := scs.fetchBookmarksForSync(sd); err != nil {
+		return fmt.Errorf("cannot fetch bookmarks for sync %v: %w", sd, err)
+	}
+
+	// fetch users associated with posts, reactions & bookmarks
 	if err := scs.fetchPostUsersForSync(sd); err != nil {
 		return fmt.Errorf("cannot fetch post users for sync %v: %w", sd, err)
 	}
@@ -147,6 +154,7 @@ func (scs *Service) syncForRemote(task syncTask, rc *model.RemoteCluster) error
 		mlog.Int("images", len(sd.profileImages)),
 		mlog.Int("posts", len(sd.posts)),
 		mlog.Int("reactions", len(sd.reactions)),
+		mlog.Int("bookmarks", len(sd.bookmarks)),
 		mlog.Int("attachments", len(sd.attachments)),
 	)
 
@@ -248,7 +256,43 @@ func (scs *Service) fetchReactionsForSync(sd *syncData) error {
 	return merr.ErrorOrNil()
 }
 
-// fetchPostUsersForSync populates the sync data with all users associated with posts.
+// fetchBookmarksForSync populates the sync data with the channel bookmarks changed since the last
+// sync, along with the files of the file bookmarks.
+func (scs *Service) fetchBookmarksForSync(sd *syncData) error {
+	bookmarks, err := scs.server.GetStore().ChannelBookmark().GetBookmarksForChannelSince(sd.task.channelID, sd.scr.LastPostUpdateAt)
+	if err != nil {
+		return fmt.Errorf("could not fetch bookmarks for sync: %w", err)
+	}
+
+	merr := merror.New()
+	for _, bookmark := range bookmarks {
+		// don't sync a bookmark back to the remote it came from.
+		if bookmark.IsRemote() && *bookmark.RemoteId == sd.rc.RemoteId {
+			continue
+		}
+		sd.bookmarks = append(sd.bookmarks, bookmark)
+
+		if bookmark.FileId == "" || bookmark.DeleteAt > 0 {
+			continue
+		}
+
+		fi, err := scs.server.GetStore().FileInfo().Get(bookmark.FileId)
+		if err != nil {
+			merr.Append(fmt.Errorf("could not get file info for bookmark %s: %w", bookmark.Id, err))
+			continue
+		}
+
+		if scs.shouldSyncAttachment(fi, sd.rc) {
+			// the file is uploaded on behalf of the owner of the bookmark.
+			post := &model.Post{UserId: bookmark.OwnerId, ChannelId: bookmark.ChannelId}
+			sd.attachments = append(sd.attachments, attachment{fi: fi, post: post})
+		}
+	}
+	return merr.ErrorOrNil()
+}
+
+// fetchPostUsersForSync populates the sync data with all users associated with posts, reactions
+// and bookmarks.
 func (scs *Service) fetchPostUsersForSync(sd *syncData) error {
 	sc, err := scs.server.GetStore().SharedChannel().Get(sd.task.channelID)
 	if err != nil {
@@ -266,6 +310,10 @@ func (scs *Service) fetchPostUsersForSync(sd *syncData) error {
 		userIDs[reaction.UserId] = p2mm{}
 	}
 
+	for _, bookmark := range sd.bookmarks {
+		userIDs[bookmark.OwnerId] = p2mm{}
+	}
+
 	for _, post := range sd.posts {
 		// add author
 		userIDs[post.UserId] = p2mm{}
@@ -365,7 +413,7 @@ func (scs *Service) filterPostsForSync(sd *syncData) {
 
 // sendSyncData sends all the collected users, posts, reactions, images, and attachments to the
 // remote cluster.
-// The order of items sent is important: users -> attachments -> posts -> reactions -> profile images
+// The order of items sent is important: users -> attachments -> posts -> reactions -> bookmarks -> profile images
 func (scs *Service) sendSyncData(sd *syncData) error {
 	merr := merror.New()
 
@@ -397,6 +445,13 @@ func (scs *Service) sendSyncData(sd *syncData) error {
 		}
 	}
 
+	// send bookmarks
+	if len(sd.bookmarks) != 0 {
+		if err := scs.sendBookmarkSyncData(sd); err != nil {
+			merr.Append(fmt.Errorf("cannot send bookmark sync data: %w", err))
+		}
+	}
+
 	// send user profile images
 	if len(sd.profileImages) != 0 {
 		scs.sendProfileImageSyncData(sd)
@@ -484,6 +539,22 @@ func (scs *Service) sendReactionSyncData(sd *syncData) error {
 	})
 }
 
+// sendBookmarkSyncData sends the collected channel bookmark updates to the remote cluster.
+func (scs *Service) sendBookmarkSyncData(sd *syncData) error {
+	msg := newSyncMsg(sd.task.channelID)
+	msg.Bookmarks = sd.bookmarks
+
+	return scs.sendSyncMsgToRemote(msg, sd.rc, func(syncResp SyncResponse, errResp error) {
+		if len(syncResp.BookmarkErrors) != 0 {
+			scs.server.GetLogger().Log(mlog.LvlSharedChannelServiceError, "Response indicates error for bookmark(s) sync",
+				mlog.String("channel_id", sd.task.channelID),
+				mlog.String("remote_id", sd.rc.RemoteId),
+				mlog.Any("bookmarks", syncResp.BookmarkErrors),
+			)
+		}
+	})
+}
+
 // sendProfileImageSyncData sends the collected user profile image updates to the remote cluster.
 func (scs *Service) sendProfileImageSyncData(sd *syncData) {
 	for _, user := range sd.profileImages {
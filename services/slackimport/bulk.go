@@ -0,0 +1,369 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package slackimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/services/bulkimport"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// BulkImportBotUsername is the username of the bot the Slack bot messages are posted as.
+const BulkImportBotUsername = "slack-import"
+
+const bulkImportBotOwner = "slackimport"
+
+// bulkConverter holds the state of the conversion of a Slack export.
+type bulkConverter struct {
+	*bulkimport.Converter
+	export        *slackExport
+	users         map[string]string      // Slack user ID to username.
+	existingUsers map[string]*model.User // Slack user ID to the existing user they are merged with.
+	hasBots       bool
+}
+
+var bulkLinkWithTextRegex = regexp.MustCompile(`<([^<\|]+)\|([^>]+)>`)
+
+// ConvertToBulkImport converts the Slack export zip file into a bulk import data file,
+// written to w, and returns the number of lines written. The paths of the attachments
+// of the posts are the paths of the files in the Slack export, which has to be given
+// as the attachments of the bulk import. The conversion is deterministic, so that an
+// interrupted import can be resumed from the same line of a new conversion.
+func ConvertToBulkImport(zipReader *zip.Reader, w io.Writer, opts bulkimport.ConvertOptions) (int, *model.AppError) {
+	export, appErr := slackParseExport(zipReader, &bytes.Buffer{})
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	conv := &bulkConverter{
+		Converter:     bulkimport.NewConverter(w, opts),
+		export:        export,
+		users:         make(map[string]string, len(export.users)),
+		existingUsers: make(map[string]*model.User),
+	}
+	for i, sUser := range export.users {
+		username := strings.ToLower(sUser.Username)
+		// Merge the Slack user with the existing user sharing their email address.
+		if opts.ExistingUser != nil {
+			if existingUser := opts.ExistingUser(slackUserEmail(sUser)); existingUser != nil {
+				conv.existingUsers[sUser.Id] = existingUser
+				username = existingUser.Username
+			}
+		}
+		conv.users[sUser.Id] = username
+		// The mentions are converted to the usernames the users are imported with.
+		export.users[i].Username = username
+	}
+	export.convertPosts()
+
+	if err := conv.convert(); err != nil {
+		return conv.Lines(), model.NewAppError("ConvertToBulkImport", "api.slackimport.bulk_convert.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return conv.Lines(), nil
+}
+
+func (conv *bulkConverter) convert() error {
+	if err := conv.WriteVersion(); err != nil {
+		return err
+	}
+
+	// Group channels with too many members for a group message become private channels.
+	channels := make([]slackChannel, 0, len(conv.export.channels))
+	members := make(map[string][]model.UserChannelImportData)
+	for _, sChannel := range conv.export.channels {
+		if sChannel.Type == model.ChannelTypeGroup && len(sChannel.Members) > model.ChannelGroupMaxUsers {
+			sChannel.Type = model.ChannelTypePrivate
+		}
+		channels = append(channels, sChannel)
+		if sChannel.Type != model.ChannelTypeOpen && sChannel.Type != model.ChannelTypePrivate {
+			continue
+		}
+
+		channel := slackSanitiseChannelProperties(model.Channel{
+			Type:        sChannel.Type,
+			DisplayName: sChannel.Name,
+			Name:        strings.ToLower(slackConvertChannelName(sChannel.Name, sChannel.Id)),
+			Purpose:     sChannel.Purpose.Value,
+			Header:      sChannel.Topic.Value,
+		})
+		if err := conv.WriteChannel(&model.ChannelImportData{
+			Name:        model.NewString(channel.Name),
+			DisplayName: model.NewString(channel.DisplayName),
+			Type:        &channel.Type,
+			Header:      model.NewString(channel.Header),
+			Purpose:     model.NewString(channel.Purpose),
+		}); err != nil {
+			return err
+		}
+		for _, member := range sChannel.Members {
+			members[member] = append(members[member], model.UserChannelImportData{Name: model.NewString(channel.Name)})
+		}
+	}
+
+	for _, sUser := range conv.export.users {
+		if err := conv.WriteUser(conv.convertUser(sUser), conv.existingUsers[sUser.Id], members[sUser.Id]); err != nil {
+			return err
+		}
+	}
+
+	// The posts are converted before the bot, which is only added when there are bot
+	// messages.
+	for _, sChannel := range channels {
+		conv.convertChannelPosts(sChannel)
+	}
+
+	if conv.hasBots {
+		if err := conv.WriteBot(&model.BotImportData{
+			Username:    model.NewString(BulkImportBotUsername),
+			DisplayName: model.NewString("Slack Import"),
+			Owner:       model.NewString(bulkImportBotOwner),
+		}, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, sChannel := range channels {
+		if sChannel.Type != model.ChannelTypeDirect && sChannel.Type != model.ChannelTypeGroup {
+			continue
+		}
+		usernames, ok := conv.usernames(sChannel.Members)
+		if !ok {
+			mlog.Warn("Slack Import: Unable to import the direct channel as some of its members are not found in users.json.", mlog.String("channel_id", sChannel.Id))
+			continue
+		}
+		if err := conv.WriteDirectChannel(usernames, sChannel.Topic.Value); err != nil {
+			return err
+		}
+	}
+
+	return conv.WritePosts()
+}
+
+func slackUserEmail(sUser slackUser) string {
+	if sUser.Profile.Email == "" {
+		return strings.ToLower(sUser.Username) + "@example.com"
+	}
+	return strings.ToLower(sUser.Profile.Email)
+}
+
+func (conv *bulkConverter) convertUser(sUser slackUser) *model.UserImportData {
+	user := &model.UserImportData{
+		Username: model.NewString(conv.users[sUser.Id]),
+		Email:    model.NewString(slackUserEmail(sUser)),
+	}
+
+	if _, ok := conv.existingUsers[sUser.Id]; !ok && sUser.Profile.Email == "" {
+		mlog.Warn("Slack Import: User does not have an email address in the Slack export. Used username as a placeholder. The user should update their email address once logged in to the system.", mlog.String("user_email", *user.Email), mlog.String("user_name", sUser.Username))
+	}
+	user.FirstName = model.NewString(sUser.Profile.FirstName)
+	user.LastName = model.NewString(sUser.Profile.LastName)
+	if sUser.Deleted {
+		// The export doesn't tell when the user was deactivated.
+		user.DeleteAt = model.NewInt64(1)
+	}
+	return user
+}
+
+func (conv *bulkConverter) usernames(slackUserIds []string) ([]string, bool) {
+	usernames := make([]string, 0, len(slackUserIds))
+	for _, id := range slackUserIds {
+		username, ok := conv.users[id]
+		if !ok {
+			return nil, false
+		}
+		usernames = append(usernames, username)
+	}
+	return usernames, true
+}
+
+// convertChannelPosts converts the messages of the channel into posts, with the thread
+// replies added to their root posts.
+func (conv *bulkConverter) convertChannelPosts(sChannel slackChannel) {
+	var sPosts []slackPost
+	var channel string
+	var members []string
+	switch sChannel.Type {
+	case model.ChannelTypeDirect, model.ChannelTypeGroup:
+		sPosts = conv.export.posts[sChannel.Name]
+		if sChannel.Type == model.ChannelTypeDirect {
+			// Direct message channels in Slack don't have a name, and their messages are in the folder named after their id.
+			sPosts = conv.export.posts[sChannel.Id]
+		}
+		var ok bool
+		members, ok = conv.usernames(sChannel.Members)
+		if !ok {
+			return
+		}
+	default:
+		sPosts = conv.export.posts[sChannel.Name]
+		channel = slackSanitiseChannelProperties(model.Channel{
+			Name: strings.ToLower(slackConvertChannelName(sChannel.Name, sChannel.Id)),
+		}).Name
+	}
+
+	sort.SliceStable(sPosts, func(i, j int) bool {
+		return slackConvertTimeStamp(sPosts[i].TimeStamp) < slackConvertTimeStamp(sPosts[j].TimeStamp)
+	})
+
+	state := &bulkimport.ChannelState{
+		Header:  sChannel.Topic.Value,
+		Purpose: sChannel.Purpose.Value,
+	}
+	for _, sPost := range sPosts {
+		post := conv.convertPost(sPost, state)
+		if post == nil {
+			continue
+		}
+
+		// The thread timestamps are only unique in their channel.
+		threadId := sChannel.Id + ":" + sPost.ThreadTS
+
+		// If post in thread
+		if sPost.ThreadTS != "" && sPost.ThreadTS != sPost.TimeStamp {
+			if conv.AddReply(threadId, post) {
+				continue
+			}
+		}
+
+		// If post is thread starter
+		if sPost.ThreadTS != sPost.TimeStamp {
+			threadId = ""
+		}
+		if members != nil {
+			conv.AddDirectPost(members, post, threadId)
+		} else {
+			conv.AddPost(channel, post, threadId)
+		}
+	}
+}
+
+// convertPost converts the Slack message into a post, or returns nil if it can't be imported.
+func (conv *bulkConverter) convertPost(sPost slackPost, state *bulkimport.ChannelState) *model.PostImportData {
+	if sPost.Type != "message" {
+		mlog.Warn("Slack Import: Unable to import the message as its type is not supported", mlog.String("post_type", sPost.Type), mlog.String("post_subtype", sPost.SubType))
+		return nil
+	}
+
+	post := &model.PostImportData{
+		User:     model.NewString(conv.users[sPost.User]),
+		Message:  model.NewString(sPost.Text),
+		CreateAt: model.NewInt64(slackConvertTimeStamp(sPost.TimeStamp)),
+	}
+	if len(sPost.PinnedTo) > 0 {
+		post.IsPinned = model.NewBool(true)
+	}
+	if sPost.Edited != nil {
+		post.EditAt = model.NewInt64(slackConvertTimeStamp(sPost.Edited.TimeStamp))
+	}
+
+	switch sPost.SubType {
+	case "", "file_share", "thread_broadcast":
+		post.Attachments = conv.convertFiles(sPost)
+	case "file_comment":
+		if sPost.Comment == nil {
+			mlog.Debug("Slack Import: Unable to import the message as it has no comments.")
+			return nil
+		}
+		post.User = model.NewString(conv.users[sPost.Comment.User])
+		post.Message = model.NewString(sPost.Comment.Comment)
+	case "bot_message":
+		if sPost.BotId == "" {
+			mlog.Warn("Slack Import: Unable to import bot message as the BotId field is missing.")
+			return nil
+		}
+		conv.hasBots = true
+		post.User = model.NewString(BulkImportBotUsername)
+		post.Type = model.NewString(model.PostTypeSlackAttachment)
+
+		// The props are built the way the webhooks build them.
+		webhookPost := &model.Post{Message: bulkLinkWithTextRegex.ReplaceAllString(sPost.Text, "[${2}](${1})")}
+		webhookPost.AddProp("from_webhook", "true")
+		if sPost.BotUsername != "" {
+			webhookPost.AddProp("override_username", sPost.BotUsername)
+		} else {
+			webhookPost.AddProp("override_username", model.DefaultWebhookUsername)
+		}
+		if len(sPost.Attachments) > 0 {
+			model.ParseSlackAttachment(webhookPost, sPost.Attachments)
+		}
+		post.Message = model.NewString(webhookPost.Message)
+		props := webhookPost.GetProps()
+		post.Props = &props
+	case "me_message":
+		post.Message = model.NewString("*" + sPost.Text + "*")
+	case "channel_join", "channel_leave":
+		post.Type = model.NewString(model.PostTypeJoinChannel)
+		if sPost.SubType == "channel_leave" {
+			post.Type = model.NewString(model.PostTypeLeaveChannel)
+		}
+		post.Props = &model.StringInterface{"username": *post.User}
+	case "channel_topic":
+		post.Type = model.NewString(model.PostTypeHeaderChange)
+		post.Props = &model.StringInterface{"username": *post.User, "old_header": state.Header, "new_header": sPost.Topic}
+		state.Header = sPost.Topic
+	case "channel_purpose":
+		post.Type = model.NewString(model.PostTypePurposeChange)
+		post.Props = &model.StringInterface{"username": *post.User, "old_purpose": state.Purpose, "new_purpose": sPost.Purpose}
+		state.Purpose = sPost.Purpose
+	case "channel_name":
+		post.Type = model.NewString(model.PostTypeDisplaynameChange)
+		post.Props = &model.StringInterface{"username": *post.User, "old_displayname": sPost.OldName, "new_displayname": sPost.Name}
+	default:
+		mlog.Warn("Slack Import: Unable to import the message as its type is not supported", mlog.String("post_type", sPost.Type), mlog.String("post_subtype", sPost.SubType))
+		return nil
+	}
+
+	if *post.User == "" {
+		mlog.Debug("Slack Import: Unable to add the message as the Slack user does not exist in Matterfoss.", mlog.String("user", sPost.User))
+		return nil
+	}
+
+	var reactions []model.ReactionImportData
+	for _, sReaction := range sPost.Reactions {
+		// Skin tones are part of the emoji name in Slack.
+		emojiName := strings.SplitN(sReaction.Name, "::", 2)[0]
+		for _, id := range sReaction.Users {
+			if username, ok := conv.users[id]; ok {
+				reactions = append(reactions, model.ReactionImportData{User: model.NewString(username), CreateAt: post.CreateAt, EmojiName: model.NewString(emojiName)})
+			}
+		}
+	}
+	if len(reactions) > 0 {
+		post.Reactions = &reactions
+	}
+
+	return post
+}
+
+func (conv *bulkConverter) convertFiles(sPost slackPost) *[]model.AttachmentImportData {
+	files := sPost.Files
+	if sPost.File != nil {
+		files = append([]*slackFile{sPost.File}, files...)
+	}
+
+	var attachments []model.AttachmentImportData
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+		upload, ok := conv.export.uploads[file.Id]
+		if !ok {
+			mlog.Warn("Slack Import: Unable to import file as the file is missing from the Slack export zip file.", mlog.String("file_id", file.Id))
+			continue
+		}
+		attachments = append(attachments, model.AttachmentImportData{Path: model.NewString(upload.Name)})
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+	return &attachments
+}
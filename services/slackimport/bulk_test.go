@@ -0,0 +1,192 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package slackimport
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/services/bulkimport"
+)
+
+func makeSlackExportZip(t *testing.T, files map[string]string) *zip.Reader {
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+
+	zipReader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return zipReader
+}
+
+func convertSlackExport(t *testing.T, zipReader *zip.Reader, opts bulkimport.ConvertOptions) []model.LineImportData {
+	buf := &bytes.Buffer{}
+	count, appErr := ConvertToBulkImport(zipReader, buf, opts)
+	require.Nil(t, appErr)
+
+	var lines []model.LineImportData
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var line model.LineImportData
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, count)
+	return lines
+}
+
+func TestConvertToBulkImport(t *testing.T) {
+	zipReader := makeSlackExportZip(t, map[string]string{
+		"users.json": `[
+			{"id": "U1", "name": "alice", "profile": {"first_name": "Alice", "email": "alice@example.com"}},
+			{"id": "U2", "name": "Bob", "deleted": true, "profile": {"email": "bob@example.com"}},
+			{"id": "U3", "name": "carol", "profile": {"email": "carol@example.com"}}
+		]`,
+		"channels.json": `[{"id": "C1", "name": "general", "members": ["U1", "U2"], "topic": {"value": "Topic"}, "purpose": {"value": "Purpose"}}]`,
+		"dms.json":      `[{"id": "D1", "members": ["U1", "U2"]}]`,
+		"general/2021-01-01.json": `[
+			{"type": "message", "user": "U1", "text": "Hello <@U2>", "ts": "1609459200.000100", "thread_ts": "1609459200.000100",
+				"reactions": [{"name": "+1::skin-tone-2", "users": ["U2", "U9"], "count": 2}], "pinned_to": ["C1"]},
+			{"type": "message", "user": "U2", "text": "Reply", "ts": "1609459201.500200", "thread_ts": "1609459200.000100",
+				"edited": {"user": "U2", "ts": "1609459300.000000"}},
+			{"type": "message", "subtype": "file_share", "user": "U1", "text": "A file", "ts": "1609459202.000000",
+				"files": [{"id": "F1"}, {"id": "F2"}]},
+			{"type": "message", "subtype": "channel_topic", "user": "U1", "text": "set the topic", "topic": "New topic", "ts": "1609459203.000000"},
+			{"type": "message", "subtype": "bot_message", "bot_id": "B1", "username": "ci", "text": "Build passed", "ts": "1609459204.000000"},
+			{"type": "message", "user": "U9", "text": "Unknown user", "ts": "1609459205.000000"}
+		]`,
+		"general/2021-01-02.json": `[{"type": "message", "user": "U3", "text": "` + strings.Repeat("a", 25) + `", "ts": "1609545600.000000"}]`,
+		"D1/2021-01-01.json":      `[{"type": "message", "user": "U2", "text": "Direct", "ts": "1609459200.000000"}]`,
+		"__uploads/F1/file.txt":   "content",
+	})
+
+	lines := convertSlackExport(t, zipReader, bulkimport.ConvertOptions{
+		TeamName:    "team",
+		MaxPostSize: 10,
+		ExistingUser: func(email string) *model.User {
+			if email == "carol@example.com" {
+				return &model.User{Username: "carol.existing", Email: email, Locale: "fr"}
+			}
+			return nil
+		},
+	})
+
+	var types []string
+	for _, line := range lines {
+		types = append(types, line.Type)
+	}
+	assert.Equal(t, []string{"version", "channel", "user", "user", "user", "bot", "direct_channel", "post", "post", "post", "post", "post", "direct_post"}, types)
+
+	t.Run("channel", func(t *testing.T) {
+		channel := lines[1].Channel
+		assert.Equal(t, "team", *channel.Team)
+		assert.Equal(t, "general", *channel.Name)
+		assert.Equal(t, "Topic", *channel.Header)
+		assert.Equal(t, "Purpose", *channel.Purpose)
+	})
+
+	t.Run("users", func(t *testing.T) {
+		alice := lines[2].User
+		assert.Equal(t, "alice", *alice.Username)
+		require.Len(t, *alice.Teams, 1)
+		channels := (*alice.Teams)[0].Channels
+		require.Len(t, *channels, 1)
+		assert.Equal(t, "general", *(*channels)[0].Name)
+
+		bob := lines[3].User
+		assert.Equal(t, "bob", *bob.Username)
+		assert.NotZero(t, *bob.DeleteAt)
+
+		carol := lines[4].User
+		assert.Equal(t, "carol.existing", *carol.Username)
+		assert.Nil(t, carol.FirstName)
+		assert.Equal(t, "fr", *carol.Locale)
+	})
+
+	t.Run("thread", func(t *testing.T) {
+		post := lines[7].Post
+		assert.Equal(t, "alice", *post.User)
+		assert.Equal(t, "Hello @bob", *post.Message)
+		assert.EqualValues(t, 1609459200000, *post.CreateAt)
+		assert.True(t, *post.IsPinned)
+		require.Len(t, *post.Reactions, 1)
+		assert.Equal(t, "bob", *(*post.Reactions)[0].User)
+		assert.Equal(t, "+1", *(*post.Reactions)[0].EmojiName)
+
+		require.Len(t, *post.Replies, 1)
+		reply := (*post.Replies)[0]
+		assert.Equal(t, "bob", *reply.User)
+		assert.EqualValues(t, 1609459201500, *reply.CreateAt)
+		assert.EqualValues(t, 1609459300000, *reply.EditAt)
+	})
+
+	t.Run("attachments", func(t *testing.T) {
+		post := lines[8].Post
+		require.Len(t, *post.Attachments, 1)
+		assert.Equal(t, "__uploads/F1/file.txt", *(*post.Attachments)[0].Path)
+	})
+
+	t.Run("system and bot messages", func(t *testing.T) {
+		post := lines[9].Post
+		assert.Equal(t, model.PostTypeHeaderChange, *post.Type)
+		assert.Equal(t, "Topic", (*post.Props)["old_header"])
+		assert.Equal(t, "New topic", (*post.Props)["new_header"])
+
+		post = lines[10].Post
+		assert.Equal(t, BulkImportBotUsername, *post.User)
+		assert.Equal(t, model.PostTypeSlackAttachment, *post.Type)
+		assert.Equal(t, "ci", (*post.Props)["override_username"])
+		assert.Equal(t, "true", (*post.Props)["from_webhook"])
+	})
+
+	t.Run("long messages", func(t *testing.T) {
+		var post *model.PostImportData
+		for _, line := range lines {
+			if line.Post != nil && *line.Post.User == "carol.existing" {
+				post = line.Post
+			}
+		}
+		require.NotNil(t, post)
+		assert.Equal(t, strings.Repeat("a", 10), *post.Message)
+		require.Len(t, *post.Replies, 2)
+		assert.Equal(t, strings.Repeat("a", 5), *(*post.Replies)[1].Message)
+		assert.Equal(t, *post.CreateAt+2, *(*post.Replies)[1].CreateAt)
+	})
+
+	t.Run("direct messages", func(t *testing.T) {
+		assert.Equal(t, []string{"alice", "bob"}, *lines[6].DirectChannel.Members)
+
+		post := lines[12].DirectPost
+		assert.Equal(t, []string{"alice", "bob"}, *post.ChannelMembers)
+		assert.Equal(t, "bob", *post.User)
+		assert.Equal(t, "Direct", *post.Message)
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		assert.Equal(t, lines, convertSlackExport(t, zipReader, bulkimport.ConvertOptions{
+			TeamName:    "team",
+			MaxPostSize: 10,
+			ExistingUser: func(email string) *model.User {
+				if email == "carol@example.com" {
+					return &model.User{Username: "carol.existing", Email: email, Locale: "fr"}
+				}
+				return nil
+			},
+		}))
+	})
+}
@@ -12,14 +12,22 @@ import (
 )
 
 func slackConvertTimeStamp(ts string) int64 {
-	timeString := strings.SplitN(ts, ".", 2)[0]
+	parts := strings.SplitN(ts, ".", 2)
 
-	timeStamp, err := strconv.ParseInt(timeString, 10, 64)
+	timeStamp, err := strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
 		mlog.Warn("Slack Import: Bad timestamp detected.")
 		return 1
 	}
-	return timeStamp * 1000 // Convert to milliseconds
+	timeStamp *= 1000 // Convert to milliseconds
+
+	// Keep the milliseconds, so that the messages posted within the same second keep their order.
+	if len(parts) == 2 && len(parts[1]) >= 3 {
+		if millis, err := strconv.ParseInt(parts[1][:3], 10, 64); err == nil {
+			timeStamp += millis
+		}
+	}
+	return timeStamp
 }
 
 func slackConvertChannelName(channelName string, channelId string) string {
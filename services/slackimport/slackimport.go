@@ -47,6 +47,8 @@ type slackProfile struct {
 type slackUser struct {
 	Id       string       `json:"id"`
 	Username string       `json:"name"`
+	Deleted  bool         `json:"deleted"`
+	IsBot    bool         `json:"is_bot"`
 	Profile  slackProfile `json:"profile"`
 }
 
@@ -69,6 +71,23 @@ type slackPost struct {
 	File        *slackFile               `json:"file"`
 	Files       []*slackFile             `json:"files"`
 	Attachments []*model.SlackAttachment `json:"attachments"`
+	Edited      *slackEdited             `json:"edited"`
+	Reactions   []slackReaction          `json:"reactions"`
+	PinnedTo    []string                 `json:"pinned_to"`
+	Topic       string                   `json:"topic"`
+	Purpose     string                   `json:"purpose"`
+	Name        string                   `json:"name"`
+	OldName     string                   `json:"old_name"`
+}
+
+type slackEdited struct {
+	User      string `json:"user"`
+	TimeStamp string `json:"ts"`
+}
+
+type slackReaction struct {
+	Name  string   `json:"name"`
+	Users []string `json:"users"`
 }
 
 var isValidChannelNameCharacters = regexp.MustCompile(`^[a-zA-Z0-9\-_]+$`).MatchString
@@ -123,63 +142,16 @@ func (si *SlackImporter) SlackImport(fileData multipart.File, fileSize int64, te
 		return model.NewAppError("SlackImport", "api.slackimport.slack_import.zip.app_error", nil, err.Error(), http.StatusBadRequest), log
 	}
 
-	var channels []slackChannel
-	var publicChannels []slackChannel
-	var privateChannels []slackChannel
-	var groupChannels []slackChannel
-	var directChannels []slackChannel
-
-	var users []slackUser
-	posts := make(map[string][]slackPost)
-	uploads := make(map[string]*zip.File)
-	for _, file := range zipreader.File {
-		if file.UncompressedSize64 > slackImportMaxFileSize {
-			log.WriteString(i18n.T("api.slackimport.slack_import.zip.file_too_large", map[string]interface{}{"Filename": file.Name}))
-			continue
-		}
-		reader, err := file.Open()
-		if err != nil {
-			log.WriteString(i18n.T("api.slackimport.slack_import.open.app_error", map[string]interface{}{"Filename": file.Name}))
-			return model.NewAppError("SlackImport", "api.slackimport.slack_import.open.app_error", map[string]interface{}{"Filename": file.Name}, err.Error(), http.StatusInternalServerError), log
-		}
-		if file.Name == "channels.json" {
-			publicChannels, _ = slackParseChannels(reader, model.ChannelTypeOpen)
-			channels = append(channels, publicChannels...)
-		} else if file.Name == "dms.json" {
-			directChannels, _ = slackParseChannels(reader, model.ChannelTypeDirect)
-			channels = append(channels, directChannels...)
-		} else if file.Name == "groups.json" {
-			privateChannels, _ = slackParseChannels(reader, model.ChannelTypePrivate)
-			channels = append(channels, privateChannels...)
-		} else if file.Name == "mpims.json" {
-			groupChannels, _ = slackParseChannels(reader, model.ChannelTypeGroup)
-			channels = append(channels, groupChannels...)
-		} else if file.Name == "users.json" {
-			users, _ = slackParseUsers(reader)
-		} else {
-			spl := strings.Split(file.Name, "/")
-			if len(spl) == 2 && strings.HasSuffix(spl[1], ".json") {
-				newposts, _ := slackParsePosts(reader)
-				channel := spl[0]
-				if _, ok := posts[channel]; !ok {
-					posts[channel] = newposts
-				} else {
-					posts[channel] = append(posts[channel], newposts...)
-				}
-			} else if len(spl) == 3 && spl[0] == "__uploads" {
-				uploads[spl[1]] = file
-			}
-		}
+	export, appErr := slackParseExport(zipreader, log)
+	if appErr != nil {
+		return appErr, log
 	}
+	export.convertPosts()
 
-	posts = slackConvertUserMentions(users, posts)
-	posts = slackConvertChannelMentions(channels, posts)
-	posts = slackConvertPostsMarkup(posts)
-
-	addedUsers := si.slackAddUsers(teamID, users, log)
+	addedUsers := si.slackAddUsers(teamID, export.users, log)
 	botUser := si.slackAddBotUser(teamID, log)
 
-	si.slackAddChannels(teamID, channels, posts, addedUsers, uploads, botUser, log)
+	si.slackAddChannels(teamID, export.channels, export.posts, addedUsers, export.uploads, botUser, log)
 
 	if botUser != nil {
 		si.deactivateSlackBotUser(botUser)
@@ -197,6 +169,66 @@ func (si *SlackImporter) SlackImport(fileData multipart.File, fileSize int64, te
 	return nil, log
 }
 
+// slackExport holds the content of a Slack export zip file.
+type slackExport struct {
+	channels []slackChannel
+	users    []slackUser
+	posts    map[string][]slackPost
+	uploads  map[string]*zip.File
+}
+
+// slackParseExport reads the channels, users, posts and uploads of a Slack export zip file.
+func slackParseExport(zipreader *zip.Reader, log *bytes.Buffer) (*slackExport, *model.AppError) {
+	export := &slackExport{
+		posts:   make(map[string][]slackPost),
+		uploads: make(map[string]*zip.File),
+	}
+	for _, file := range zipreader.File {
+		if file.UncompressedSize64 > slackImportMaxFileSize {
+			log.WriteString(i18n.T("api.slackimport.slack_import.zip.file_too_large", map[string]interface{}{"Filename": file.Name}))
+			continue
+		}
+		spl := strings.Split(file.Name, "/")
+		if len(spl) == 3 && spl[0] == "__uploads" {
+			export.uploads[spl[1]] = file
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			log.WriteString(i18n.T("api.slackimport.slack_import.open.app_error", map[string]interface{}{"Filename": file.Name}))
+			return nil, model.NewAppError("SlackImport", "api.slackimport.slack_import.open.app_error", map[string]interface{}{"Filename": file.Name}, err.Error(), http.StatusInternalServerError)
+		}
+		if file.Name == "channels.json" {
+			publicChannels, _ := slackParseChannels(reader, model.ChannelTypeOpen)
+			export.channels = append(export.channels, publicChannels...)
+		} else if file.Name == "dms.json" {
+			directChannels, _ := slackParseChannels(reader, model.ChannelTypeDirect)
+			export.channels = append(export.channels, directChannels...)
+		} else if file.Name == "groups.json" {
+			privateChannels, _ := slackParseChannels(reader, model.ChannelTypePrivate)
+			export.channels = append(export.channels, privateChannels...)
+		} else if file.Name == "mpims.json" {
+			groupChannels, _ := slackParseChannels(reader, model.ChannelTypeGroup)
+			export.channels = append(export.channels, groupChannels...)
+		} else if file.Name == "users.json" {
+			export.users, _ = slackParseUsers(reader)
+		} else if len(spl) == 2 && strings.HasSuffix(spl[1], ".json") {
+			newposts, _ := slackParsePosts(reader)
+			export.posts[spl[0]] = append(export.posts[spl[0]], newposts...)
+		}
+		reader.Close()
+	}
+
+	return export, nil
+}
+
+// convertPosts converts the mentions and the markup of the posts.
+func (export *slackExport) convertPosts() {
+	export.posts = slackConvertUserMentions(export.users, export.posts)
+	export.posts = slackConvertChannelMentions(export.channels, export.posts)
+	export.posts = slackConvertPostsMarkup(export.posts)
+}
+
 func truncateRunes(s string, i int) string {
 	runes := []rune(s)
 	if len(runes) > i {
@@ -66,6 +66,7 @@ const (
 	TrackConfigImageProxy        = "config_image_proxy"
 	TrackConfigBleve             = "config_bleve"
 	TrackConfigExport            = "config_export"
+	TrackConfigCache             = "config_cache"
 	TrackFeatureFlags            = "config_feature_flags"
 	TrackPermissionsGeneral      = "permissions_general"
 	TrackPermissionsSystemScheme = "permissions_system_scheme"
@@ -378,6 +379,9 @@ func (ts *TelemetryService) trackConfig() {
 		"enable_client_performance_debugging":                     *cfg.ServiceSettings.EnableClientPerformanceDebugging,
 		"enable_multifactor_authentication":                       *cfg.ServiceSettings.EnableMultifactorAuthentication,
 		"enforce_multifactor_authentication":                      *cfg.ServiceSettings.EnforceMultifactorAuthentication,
+		"enable_webauthn":                                         *cfg.ServiceSettings.EnableWebAuthn,
+		"enable_mfa_self_service_reset":                           *cfg.ServiceSettings.EnableMfaSelfServiceReset,
+		"mfa_reset_requires_approval":                             *cfg.ServiceSettings.MfaResetRequiresApproval,
 		"enable_oauth_service_provider":                           cfg.ServiceSettings.EnableOAuthServiceProvider,
 		"connection_security":                                     *cfg.ServiceSettings.ConnectionSecurity,
 		"tls_strict_transport":                                    *cfg.ServiceSettings.TLSStrictTransport,
@@ -438,6 +442,9 @@ func (ts *TelemetryService) trackConfig() {
 		"enable_file_search":                                      *cfg.ServiceSettings.EnableFileSearch,
 		"restrict_link_previews":                                  isDefault(*cfg.ServiceSettings.RestrictLinkPreviews, ""),
 		"enable_custom_groups":                                    *cfg.ServiceSettings.EnableCustomGroups,
+		"allow_synced_drafts":                                     *cfg.ServiceSettings.AllowSyncedDrafts,
+		"enable_scheduled_posts":                                  *cfg.ServiceSettings.EnableScheduledPosts,
+		"enable_reminders":                                        *cfg.ServiceSettings.EnableReminders,
 	})
 
 	ts.SendTelemetry(TrackConfigTeam, map[string]interface{}{
@@ -814,6 +821,10 @@ func (ts *TelemetryService) trackConfig() {
 		"retention_days": *cfg.ExportSettings.RetentionDays,
 	})
 
+	ts.SendTelemetry(TrackConfigCache, map[string]interface{}{
+		"cache_type": *cfg.CacheSettings.CacheType,
+	})
+
 	// Convert feature flags to map[string]interface{} for sending
 	flags := cfg.FeatureFlags.ToMap()
 	interfaceFlags := make(map[string]interface{})
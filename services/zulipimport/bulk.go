@@ -0,0 +1,421 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package zulipimport
+
+import (
+	"archive/zip"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/services/bulkimport"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mlog"
+)
+
+// bulkImportBotOwner is the owner of the Zulip bots which don't have one.
+const bulkImportBotOwner = "zulipimport"
+
+// bulkConverter holds the state of the conversion of a Zulip export.
+type bulkConverter struct {
+	*bulkimport.Converter
+	export        *zulipExport
+	users         map[int]string         // Zulip user ID to username.
+	fullNames     map[string]string      // Lower case full name to username, for the mentions.
+	existingUsers map[int]*model.User    // Zulip user ID to the existing user they are merged with.
+	channels      map[int]string         // Recipient ID of the streams and large huddles to channel name.
+	streams       map[string]string      // Lower case stream name to channel name, for the stream links.
+	huddles       map[int][]string       // Recipient ID of the huddles imported as group messages to their members.
+	recipients    map[int]zulipRecipient // Recipient ID to recipient.
+	reactions     map[int][]zulipReaction
+	attachments   map[int][]model.AttachmentImportData
+}
+
+var (
+	bulkMentionRegex    = regexp.MustCompile(`@_?\*\*([^*\n]+)\*\*`)
+	bulkStreamLinkRegex = regexp.MustCompile(`#\*\*([^*\n>]+)(>[^*\n]*)?\*\*`)
+)
+
+// ConvertToBulkImport converts the Zulip realm export zip file into a bulk import data
+// file, written to w, and returns the number of lines written. The streams become
+// channels, and the topics of the streams become threads, the root post of which starts
+// with the name of the topic. The paths of the attachments of the posts are the paths
+// of the files in the Zulip export, which has to be given as the attachments of the
+// bulk import. The conversion is deterministic, so that an interrupted import can be
+// resumed from the same line of a new conversion.
+func ConvertToBulkImport(zipReader *zip.Reader, w io.Writer, opts bulkimport.ConvertOptions) (int, *model.AppError) {
+	export, appErr := zulipParseExport(zipReader)
+	if appErr != nil {
+		return 0, appErr
+	}
+
+	conv := &bulkConverter{
+		Converter:     bulkimport.NewConverter(w, opts),
+		export:        export,
+		users:         make(map[int]string, len(export.realm.Users)),
+		fullNames:     make(map[string]string, len(export.realm.Users)),
+		existingUsers: make(map[int]*model.User),
+		channels:      make(map[int]string),
+		streams:       make(map[string]string),
+		huddles:       make(map[int][]string),
+		recipients:    make(map[int]zulipRecipient, len(export.realm.Recipients)),
+		reactions:     make(map[int][]zulipReaction),
+		attachments:   make(map[int][]model.AttachmentImportData),
+	}
+	conv.prepare()
+
+	if err := conv.convert(); err != nil {
+		return conv.Lines(), model.NewAppError("ConvertToBulkImport", "api.zulipimport.bulk_convert.write.app_error", nil, err.Error(), http.StatusInternalServerError)
+	}
+	return conv.Lines(), nil
+}
+
+func zulipUserEmail(zUser zulipUser) string {
+	if zUser.DeliveryEmail != "" {
+		return strings.ToLower(zUser.DeliveryEmail)
+	}
+	return strings.ToLower(zUser.Email)
+}
+
+// prepare resolves the usernames of the users, and indexes the export.
+func (conv *bulkConverter) prepare() {
+	realm := &conv.export.realm
+	sort.SliceStable(realm.Users, func(i, j int) bool { return realm.Users[i].Id < realm.Users[j].Id })
+	sort.SliceStable(realm.Streams, func(i, j int) bool { return realm.Streams[i].Id < realm.Streams[j].Id })
+	sort.SliceStable(realm.Recipients, func(i, j int) bool { return realm.Recipients[i].Id < realm.Recipients[j].Id })
+
+	// The usernames of the existing users are resolved first, so that no other user is
+	// given one of them.
+	taken := make(map[string]bool)
+	if conv.Opts.ExistingUser != nil {
+		for _, zUser := range realm.Users {
+			if zUser.IsBot {
+				continue
+			}
+			if existingUser := conv.Opts.ExistingUser(zulipUserEmail(zUser)); existingUser != nil {
+				conv.existingUsers[zUser.Id] = existingUser
+				conv.users[zUser.Id] = existingUser.Username
+				taken[existingUser.Username] = true
+			}
+		}
+	}
+	for _, zUser := range realm.Users {
+		if _, ok := conv.users[zUser.Id]; !ok {
+			conv.users[zUser.Id] = bulkimport.Username(strings.SplitN(zulipUserEmail(zUser), "@", 2)[0], taken)
+		}
+		if zUser.FullName != "" {
+			if _, ok := conv.fullNames[strings.ToLower(zUser.FullName)]; !ok {
+				conv.fullNames[strings.ToLower(zUser.FullName)] = conv.users[zUser.Id]
+			}
+		}
+	}
+
+	for _, recipient := range realm.Recipients {
+		conv.recipients[recipient.Id] = recipient
+	}
+	for _, reaction := range realm.Reactions {
+		conv.reactions[reaction.Message] = append(conv.reactions[reaction.Message], reaction)
+	}
+	sort.SliceStable(realm.Attachments, func(i, j int) bool { return realm.Attachments[i].Id < realm.Attachments[j].Id })
+	for _, attachment := range realm.Attachments {
+		file, ok := conv.export.uploads[attachment.PathId]
+		if !ok {
+			mlog.Warn("Zulip Import: Unable to import file as the file is missing from the Zulip export zip file.", mlog.String("path_id", attachment.PathId))
+			continue
+		}
+		for _, messageId := range attachment.Messages {
+			conv.attachments[messageId] = append(conv.attachments[messageId], model.AttachmentImportData{Path: model.NewString(file.Name)})
+		}
+	}
+}
+
+func (conv *bulkConverter) convert() error {
+	if err := conv.WriteVersion(); err != nil {
+		return err
+	}
+
+	realm := &conv.export.realm
+	recipientsByStream := make(map[int]int)
+	members := make(map[int][]int)
+	for _, recipient := range realm.Recipients {
+		if recipient.Type == zulipRecipientStream {
+			recipientsByStream[recipient.TypeId] = recipient.Id
+		}
+	}
+	for _, subscription := range realm.Subscriptions {
+		members[subscription.Recipient] = append(members[subscription.Recipient], subscription.UserProfile)
+	}
+
+	memberships := make(map[int][]model.UserChannelImportData)
+	addMembers := func(recipientId int, name string) {
+		for _, subscription := range realm.Subscriptions {
+			if subscription.Recipient == recipientId && subscription.Active {
+				memberships[subscription.UserProfile] = append(memberships[subscription.UserProfile], model.UserChannelImportData{Name: model.NewString(name)})
+			}
+		}
+	}
+
+	takenChannels := make(map[string]bool)
+	writeChannel := func(channel *model.ChannelImportData, fallback string) (string, error) {
+		name := bulkimport.ChannelName(*channel.DisplayName, fallback)
+		if takenChannels[name] {
+			// The fallback makes the names of the channels unique.
+			if max := model.ChannelNameMaxLength - len(fallback) - 1; len(name) > max {
+				name = name[:max]
+			}
+			name += "-" + fallback
+		}
+		takenChannels[name] = true
+		channel.Name = model.NewString(name)
+		return name, conv.WriteChannel(channel)
+	}
+
+	for _, stream := range realm.Streams {
+		recipientId, ok := recipientsByStream[stream.Id]
+		if !ok {
+			continue
+		}
+		channelType := model.ChannelTypeOpen
+		if stream.InviteOnly {
+			channelType = model.ChannelTypePrivate
+		}
+		name, err := writeChannel(&model.ChannelImportData{
+			DisplayName: model.NewString(stream.Name),
+			Type:        &channelType,
+			Purpose:     model.NewString(stream.Description),
+		}, "stream-"+strconv.Itoa(stream.Id))
+		if err != nil {
+			return err
+		}
+		conv.channels[recipientId] = name
+		conv.streams[strings.ToLower(stream.Name)] = name
+		addMembers(recipientId, name)
+	}
+
+	// Huddles with too many members for a group message become private channels.
+	for _, recipient := range realm.Recipients {
+		if recipient.Type != zulipRecipientHuddle {
+			continue
+		}
+		usernames, ok := conv.usernames(members[recipient.Id])
+		if !ok {
+			mlog.Warn("Zulip Import: Unable to import the huddle as some of its members are not found in the export.", mlog.Int("recipient_id", recipient.Id))
+			continue
+		}
+		if len(usernames) <= model.ChannelGroupMaxUsers {
+			conv.huddles[recipient.Id] = usernames
+			continue
+		}
+		channelType := model.ChannelTypePrivate
+		name, err := writeChannel(&model.ChannelImportData{
+			DisplayName: model.NewString(strings.Join(usernames, ", ")),
+			Type:        &channelType,
+		}, "group-"+strconv.Itoa(recipient.Id))
+		if err != nil {
+			return err
+		}
+		conv.channels[recipient.Id] = name
+		addMembers(recipient.Id, name)
+	}
+
+	var bots []zulipUser
+	for _, zUser := range realm.Users {
+		if zUser.IsBot {
+			bots = append(bots, zUser)
+			continue
+		}
+		if err := conv.WriteUser(conv.convertUser(zUser), conv.existingUsers[zUser.Id], memberships[zUser.Id]); err != nil {
+			return err
+		}
+	}
+	for _, zUser := range bots {
+		bot := &model.BotImportData{
+			Username:    model.NewString(conv.users[zUser.Id]),
+			DisplayName: model.NewString(zUser.FullName),
+			Owner:       model.NewString(bulkImportBotOwner),
+		}
+		if zUser.BotOwner != nil {
+			if owner, ok := conv.users[*zUser.BotOwner]; ok {
+				bot.Owner = model.NewString(owner)
+			}
+		}
+		if !zUser.IsActive {
+			bot.DeleteAt = model.NewInt64(1)
+		}
+		if err := conv.WriteBot(bot, memberships[zUser.Id]); err != nil {
+			return err
+		}
+	}
+
+	for _, members := range conv.convertMessages() {
+		if err := conv.WriteDirectChannel(members, ""); err != nil {
+			return err
+		}
+	}
+
+	return conv.WritePosts()
+}
+
+func (conv *bulkConverter) convertUser(zUser zulipUser) *model.UserImportData {
+	user := &model.UserImportData{
+		Username: model.NewString(conv.users[zUser.Id]),
+		Email:    model.NewString(zulipUserEmail(zUser)),
+	}
+
+	names := strings.SplitN(zUser.FullName, " ", 2)
+	user.FirstName = model.NewString(bulkimport.TruncateRunes(names[0], model.UserFirstNameMaxRunes))
+	if len(names) > 1 {
+		user.LastName = model.NewString(bulkimport.TruncateRunes(names[1], model.UserLastNameMaxRunes))
+	}
+	if !zUser.IsActive {
+		// The export doesn't tell when the user was deactivated.
+		user.DeleteAt = model.NewInt64(1)
+	}
+	return user
+}
+
+func (conv *bulkConverter) usernames(zulipUserIds []int) ([]string, bool) {
+	usernames := make([]string, 0, len(zulipUserIds))
+	for _, id := range zulipUserIds {
+		username, ok := conv.users[id]
+		if !ok {
+			return nil, false
+		}
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	return usernames, true
+}
+
+// convertMessages converts the messages into posts, with the messages of the topics
+// added to their first message as replies, and returns the members of the direct
+// channels they are posted in.
+func (conv *bulkConverter) convertMessages() [][]string {
+	var directChannels [][]string
+	seenDirectChannels := make(map[string]bool)
+	for _, message := range conv.export.messages {
+		recipient, ok := conv.recipients[message.Recipient]
+		if !ok {
+			mlog.Warn("Zulip Import: Unable to import the message as its recipient is not found in the export.", mlog.Int("message_id", message.Id))
+			continue
+		}
+		post := conv.convertMessage(message)
+		if post == nil {
+			continue
+		}
+
+		if channel, ok := conv.channels[recipient.Id]; ok {
+			// The messages of a topic are the replies of its first message.
+			var topicKey string
+			if recipient.Type == zulipRecipientStream {
+				topicKey = strconv.Itoa(recipient.Id) + ":" + strings.ToLower(message.Subject)
+				if conv.AddReply(topicKey, post) {
+					continue
+				}
+				if message.Subject != "" {
+					post.Message = model.NewString("**" + message.Subject + "**\n" + *post.Message)
+				}
+			}
+			conv.AddPost(channel, post, topicKey)
+			continue
+		}
+
+		var members []string
+		switch recipient.Type {
+		case zulipRecipientPersonal:
+			members, ok = conv.usernames([]int{message.Sender, recipient.TypeId})
+		case zulipRecipientHuddle:
+			members, ok = conv.huddles[recipient.Id]
+		default:
+			ok = false
+		}
+		if !ok {
+			mlog.Warn("Zulip Import: Unable to import the direct message as some of its members are not found in the export.", mlog.Int("message_id", message.Id))
+			continue
+		}
+		if key := strings.Join(members, ","); !seenDirectChannels[key] {
+			seenDirectChannels[key] = true
+			directChannels = append(directChannels, members)
+		}
+		conv.AddDirectPost(members, post, "")
+	}
+	return directChannels
+}
+
+// convertMessage converts the Zulip message into a post, or returns nil if it can't be imported.
+func (conv *bulkConverter) convertMessage(message zulipMessage) *model.PostImportData {
+	username, ok := conv.users[message.Sender]
+	if !ok {
+		mlog.Debug("Zulip Import: Unable to add the message as the Zulip user does not exist in the export.", mlog.Int("user_id", message.Sender))
+		return nil
+	}
+
+	post := &model.PostImportData{
+		User:     model.NewString(username),
+		Message:  model.NewString(conv.convertContent(message.Content)),
+		CreateAt: model.NewInt64(message.createAt()),
+	}
+	if attachments := conv.attachments[message.Id]; len(attachments) > 0 {
+		post.Attachments = &attachments
+	}
+	if message.LastEditTime != nil {
+		post.EditAt = model.NewInt64(zulipConvertTime(*message.LastEditTime))
+	}
+
+	var reactions []model.ReactionImportData
+	seen := make(map[string]bool)
+	for _, reaction := range conv.reactions[message.Id] {
+		username, ok := conv.users[reaction.UserProfile]
+		if !ok || seen[username+":"+reaction.EmojiName] {
+			continue
+		}
+		seen[username+":"+reaction.EmojiName] = true
+		reactions = append(reactions, model.ReactionImportData{User: model.NewString(username), CreateAt: post.CreateAt, EmojiName: model.NewString(reaction.EmojiName)})
+	}
+	if len(reactions) > 0 {
+		post.Reactions = &reactions
+	}
+
+	return post
+}
+
+// convertContent converts the Zulip markdown of the message, replacing the mentions
+// of users and the links to streams.
+func (conv *bulkConverter) convertContent(content string) string {
+	if strings.HasPrefix(content, "/me ") {
+		content = "*" + strings.TrimPrefix(content, "/me ") + "*"
+	}
+
+	content = bulkMentionRegex.ReplaceAllStringFunc(content, func(mention string) string {
+		parts := strings.SplitN(bulkMentionRegex.FindStringSubmatch(mention)[1], "|", 2)
+		if len(parts) == 2 {
+			if id, err := strconv.Atoi(parts[1]); err == nil {
+				if username, ok := conv.users[id]; ok {
+					return "@" + username
+				}
+			}
+		}
+		switch name := strings.ToLower(parts[0]); name {
+		case "all", "everyone":
+			return "@all"
+		case "stream", "channel", "topic":
+			return "@channel"
+		default:
+			if username, ok := conv.fullNames[name]; ok {
+				return "@" + username
+			}
+		}
+		return mention
+	})
+
+	return bulkStreamLinkRegex.ReplaceAllStringFunc(content, func(link string) string {
+		if name, ok := conv.streams[strings.ToLower(bulkStreamLinkRegex.FindStringSubmatch(link)[1])]; ok {
+			return "~" + name
+		}
+		return link
+	})
+}
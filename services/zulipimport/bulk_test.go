@@ -0,0 +1,212 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package zulipimport
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/services/bulkimport"
+)
+
+func makeZulipExportZip(t *testing.T, files map[string]string) *zip.Reader {
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+
+	zipReader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return zipReader
+}
+
+func convertZulipExport(t *testing.T, zipReader *zip.Reader, opts bulkimport.ConvertOptions) []model.LineImportData {
+	buf := &bytes.Buffer{}
+	count, appErr := ConvertToBulkImport(zipReader, buf, opts)
+	require.Nil(t, appErr)
+
+	var lines []model.LineImportData
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var line model.LineImportData
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, count)
+	return lines
+}
+
+func TestConvertToBulkImport(t *testing.T) {
+	zipReader := makeZulipExportZip(t, map[string]string{
+		"zulip-export/realm.json": `{
+			"zerver_userprofile": [
+				{"id": 1, "email": "user1@zulip.example.com", "delivery_email": "Alice@example.com", "full_name": "Alice Smith", "is_active": true},
+				{"id": 2, "email": "bob@example.com", "full_name": "Bob", "is_active": false},
+				{"id": 3, "email": "carol@example.com", "full_name": "Carol", "is_active": true},
+				{"id": 4, "email": "notify-bot@zulip.example.com", "full_name": "Notify", "is_active": true, "is_bot": true, "bot_owner": 1}
+			],
+			"zerver_stream": [
+				{"id": 10, "name": "General Chat", "description": "Talk", "invite_only": false},
+				{"id": 11, "name": "Secret", "invite_only": true}
+			],
+			"zerver_recipient": [
+				{"id": 100, "type": 2, "type_id": 10},
+				{"id": 101, "type": 2, "type_id": 11},
+				{"id": 102, "type": 1, "type_id": 2},
+				{"id": 103, "type": 3, "type_id": 1}
+			],
+			"zerver_subscription": [
+				{"user_profile": 1, "recipient": 100, "active": true},
+				{"user_profile": 2, "recipient": 100, "active": false},
+				{"user_profile": 1, "recipient": 101, "active": true},
+				{"user_profile": 4, "recipient": 100, "active": true},
+				{"user_profile": 1, "recipient": 103, "active": true},
+				{"user_profile": 2, "recipient": 103, "active": true},
+				{"user_profile": 3, "recipient": 103, "active": true}
+			],
+			"zerver_reaction": [
+				{"user_profile": 2, "message": 1000, "emoji_name": "tada"},
+				{"user_profile": 9, "message": 1000, "emoji_name": "tada"}
+			],
+			"zerver_attachment": [
+				{"id": 1, "path_id": "2/ab/file.txt", "messages": [1002]},
+				{"id": 2, "path_id": "2/cd/missing.txt", "messages": [1002]}
+			]
+		}`,
+		"zulip-export/messages-000001.json": `{"zerver_message": [
+			{"id": 1000, "sender": 1, "recipient": 100, "subject": "Plans", "content": "Hello @**Bob** and @**Carol|3**, see #**Secret>topic**", "date_sent": 1609459200.5},
+			{"id": 1001, "sender": 2, "recipient": 100, "subject": "plans", "content": "Reply", "date_sent": 1609459201, "last_edit_time": 1609459300},
+			{"id": 1002, "sender": 3, "recipient": 100, "subject": "Other", "content": "/me shares a file", "date_sent": 1609459202}
+		]}`,
+		"zulip-export/messages-000002.json": `{"zerver_message": [
+			{"id": 1003, "sender": 1, "recipient": 102, "subject": "", "content": "Direct", "date_sent": 1609459203},
+			{"id": 1004, "sender": 3, "recipient": 103, "subject": "", "content": "` + strings.Repeat("a", 25) + `", "date_sent": 1609459204},
+			{"id": 1005, "sender": 9, "recipient": 100, "subject": "Plans", "content": "Unknown user", "date_sent": 1609459205}
+		]}`,
+		"zulip-export/uploads/2/ab/file.txt": "content",
+	})
+
+	opts := bulkimport.ConvertOptions{
+		TeamName:    "team",
+		MaxPostSize: 10,
+		ExistingUser: func(email string) *model.User {
+			if email == "carol@example.com" {
+				return &model.User{Username: "carol.existing", Email: email, Locale: "fr"}
+			}
+			return nil
+		},
+	}
+	lines := convertZulipExport(t, zipReader, opts)
+
+	var types []string
+	for _, line := range lines {
+		types = append(types, line.Type)
+	}
+	assert.Equal(t, []string{"version", "channel", "channel", "user", "user", "user", "bot", "direct_channel", "direct_channel", "post", "post", "direct_post", "direct_post"}, types)
+
+	t.Run("channels", func(t *testing.T) {
+		channel := lines[1].Channel
+		assert.Equal(t, "team", *channel.Team)
+		assert.Equal(t, "general-chat", *channel.Name)
+		assert.Equal(t, "General Chat", *channel.DisplayName)
+		assert.Equal(t, model.ChannelTypeOpen, *channel.Type)
+		assert.Equal(t, "Talk", *channel.Purpose)
+
+		assert.Equal(t, model.ChannelTypePrivate, *lines[2].Channel.Type)
+	})
+
+	t.Run("users", func(t *testing.T) {
+		alice := lines[3].User
+		assert.Equal(t, "alice", *alice.Username)
+		assert.Equal(t, "alice@example.com", *alice.Email)
+		assert.Equal(t, "Alice", *alice.FirstName)
+		assert.Equal(t, "Smith", *alice.LastName)
+		require.Len(t, *alice.Teams, 1)
+		require.Len(t, *(*alice.Teams)[0].Channels, 2)
+
+		bob := lines[4].User
+		assert.Equal(t, "bob", *bob.Username)
+		assert.NotZero(t, *bob.DeleteAt)
+		assert.Nil(t, (*bob.Teams)[0].Channels)
+
+		carol := lines[5].User
+		assert.Equal(t, "carol.existing", *carol.Username)
+		assert.Nil(t, carol.FirstName)
+		assert.Equal(t, "fr", *carol.Locale)
+
+		bot := lines[6].Bot
+		assert.Equal(t, "notify-bot", *bot.Username)
+		assert.Equal(t, "alice", *bot.Owner)
+		require.Len(t, *(*bot.Teams)[0].Channels, 1)
+	})
+
+	t.Run("topics", func(t *testing.T) {
+		post := lines[9].Post
+		assert.Equal(t, "general-chat", *post.Channel)
+		assert.Equal(t, "alice", *post.User)
+		assert.Equal(t, "**Plans**\n", (*post.Message)[:10])
+		assert.EqualValues(t, 1609459200500, *post.CreateAt)
+		require.Len(t, *post.Reactions, 1)
+		assert.Equal(t, "bob", *(*post.Reactions)[0].User)
+		assert.Equal(t, "tada", *(*post.Reactions)[0].EmojiName)
+
+		replies := *post.Replies
+		messages := []string{*post.Message}
+		for _, reply := range replies[:len(replies)-1] {
+			messages = append(messages, *reply.Message)
+		}
+		assert.Equal(t, "**Plans**\nHello @bob and @carol.existing, see ~secret", strings.Join(messages, ""))
+
+		reply := replies[len(replies)-1]
+		assert.Equal(t, "bob", *reply.User)
+		assert.Equal(t, "Reply", *reply.Message)
+		assert.EqualValues(t, 1609459201000, *reply.CreateAt)
+		assert.EqualValues(t, 1609459300000, *reply.EditAt)
+	})
+
+	t.Run("attachments", func(t *testing.T) {
+		post := lines[10].Post
+		assert.Equal(t, "**Other**\n", *post.Message)
+		require.Len(t, *post.Replies, 2)
+		require.Len(t, *post.Attachments, 1)
+		assert.Equal(t, "zulip-export/uploads/2/ab/file.txt", *(*post.Attachments)[0].Path)
+	})
+
+	t.Run("direct messages", func(t *testing.T) {
+		assert.Equal(t, []string{"alice", "bob"}, *lines[7].DirectChannel.Members)
+		assert.Equal(t, []string{"alice", "bob", "carol.existing"}, *lines[8].DirectChannel.Members)
+
+		post := lines[11].DirectPost
+		assert.Equal(t, []string{"alice", "bob"}, *post.ChannelMembers)
+		assert.Equal(t, "Direct", *post.Message)
+
+		post = lines[12].DirectPost
+		assert.Equal(t, []string{"alice", "bob", "carol.existing"}, *post.ChannelMembers)
+		assert.Equal(t, strings.Repeat("a", 10), *post.Message)
+		require.Len(t, *post.Replies, 2)
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		assert.Equal(t, lines, convertZulipExport(t, zipReader, opts))
+	})
+
+	t.Run("missing realm", func(t *testing.T) {
+		_, appErr := ConvertToBulkImport(makeZulipExportZip(t, map[string]string{"users.json": "[]"}), &bytes.Buffer{}, opts)
+		require.NotNil(t, appErr)
+		assert.Equal(t, "api.zulipimport.parse.missing_realm.app_error", appErr.Id)
+	})
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package zulipimport
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+// The recipient types of the Zulip messages.
+const (
+	zulipRecipientPersonal = 1
+	zulipRecipientStream   = 2
+	zulipRecipientHuddle   = 3
+)
+
+type zulipUser struct {
+	Id            int    `json:"id"`
+	Email         string `json:"email"`
+	DeliveryEmail string `json:"delivery_email"`
+	FullName      string `json:"full_name"`
+	IsActive      bool   `json:"is_active"`
+	IsBot         bool   `json:"is_bot"`
+	BotOwner      *int   `json:"bot_owner"`
+	IsMirrorDummy bool   `json:"is_mirror_dummy"`
+}
+
+type zulipStream struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InviteOnly  bool   `json:"invite_only"`
+	Deactivated bool   `json:"deactivated"`
+}
+
+type zulipRecipient struct {
+	Id     int `json:"id"`
+	Type   int `json:"type"`
+	TypeId int `json:"type_id"`
+}
+
+type zulipSubscription struct {
+	UserProfile int  `json:"user_profile"`
+	Recipient   int  `json:"recipient"`
+	Active      bool `json:"active"`
+}
+
+type zulipReaction struct {
+	UserProfile int    `json:"user_profile"`
+	Message     int    `json:"message"`
+	EmojiName   string `json:"emoji_name"`
+}
+
+type zulipAttachment struct {
+	Id       int    `json:"id"`
+	FileName string `json:"file_name"`
+	PathId   string `json:"path_id"`
+	Messages []int  `json:"messages"`
+}
+
+type zulipMessage struct {
+	Id        int    `json:"id"`
+	Sender    int    `json:"sender"`
+	Recipient int    `json:"recipient"`
+	Subject   string `json:"subject"`
+	Content   string `json:"content"`
+	// DateSent is named PubDate in the exports of older Zulip versions.
+	DateSent     *float64 `json:"date_sent"`
+	PubDate      *float64 `json:"pub_date"`
+	LastEditTime *float64 `json:"last_edit_time"`
+}
+
+type zulipRealm struct {
+	Users         []zulipUser         `json:"zerver_userprofile"`
+	Streams       []zulipStream       `json:"zerver_stream"`
+	Recipients    []zulipRecipient    `json:"zerver_recipient"`
+	Subscriptions []zulipSubscription `json:"zerver_subscription"`
+	Reactions     []zulipReaction     `json:"zerver_reaction"`
+	Attachments   []zulipAttachment   `json:"zerver_attachment"`
+}
+
+type zulipMessages struct {
+	Messages []zulipMessage `json:"zerver_message"`
+}
+
+// zulipExport holds the parsed contents of a Zulip realm export.
+type zulipExport struct {
+	realm    zulipRealm
+	messages []zulipMessage
+	// uploads are the files of the export, by the path of their upload in Zulip.
+	uploads map[string]*zip.File
+}
+
+// zulipConvertTime converts the Zulip timestamp, in seconds, into milliseconds.
+func zulipConvertTime(seconds float64) int64 {
+	return int64(seconds*1000 + 0.5)
+}
+
+func (m *zulipMessage) createAt() int64 {
+	if m.DateSent != nil {
+		return zulipConvertTime(*m.DateSent)
+	}
+	if m.PubDate != nil {
+		return zulipConvertTime(*m.PubDate)
+	}
+	return 0
+}
+
+func zulipParseJSON(file *zip.File, v interface{}) *model.AppError {
+	reader, err := file.Open()
+	if err != nil {
+		return model.NewAppError("ZulipImport", "api.zulipimport.parse.open.app_error", map[string]interface{}{"Filename": file.Name}, err.Error(), http.StatusBadRequest)
+	}
+	defer reader.Close()
+
+	if err := json.NewDecoder(reader).Decode(v); err != nil {
+		return model.NewAppError("ZulipImport", "api.zulipimport.parse.decode.app_error", map[string]interface{}{"Filename": file.Name}, err.Error(), http.StatusBadRequest)
+	}
+	return nil
+}
+
+// zulipParseExport parses the Zulip realm export zip file, the files of which are in
+// the directory of its realm.json file.
+func zulipParseExport(zipReader *zip.Reader) (*zulipExport, *model.AppError) {
+	var realmFile *zip.File
+	for _, file := range zipReader.File {
+		if path.Base(file.Name) == "realm.json" && (realmFile == nil || len(file.Name) < len(realmFile.Name)) {
+			realmFile = file
+		}
+	}
+	if realmFile == nil {
+		return nil, model.NewAppError("ZulipImport", "api.zulipimport.parse.missing_realm.app_error", nil, "", http.StatusBadRequest)
+	}
+	base := path.Dir(realmFile.Name)
+	if base == "." {
+		base = ""
+	} else {
+		base += "/"
+	}
+
+	export := &zulipExport{uploads: make(map[string]*zip.File)}
+	if appErr := zulipParseJSON(realmFile, &export.realm); appErr != nil {
+		return nil, appErr
+	}
+
+	var messageFiles []*zip.File
+	for _, file := range zipReader.File {
+		if !strings.HasPrefix(file.Name, base) {
+			continue
+		}
+		name := strings.TrimPrefix(file.Name, base)
+		if strings.HasPrefix(name, "uploads/") {
+			export.uploads[strings.TrimPrefix(name, "uploads/")] = file
+		} else if strings.HasPrefix(name, "messages-") && strings.HasSuffix(name, ".json") {
+			messageFiles = append(messageFiles, file)
+		}
+	}
+
+	sort.Slice(messageFiles, func(i, j int) bool {
+		return messageFiles[i].Name < messageFiles[j].Name
+	})
+	for _, file := range messageFiles {
+		var messages zulipMessages
+		if appErr := zulipParseJSON(file, &messages); appErr != nil {
+			return nil, appErr
+		}
+		export.messages = append(export.messages, messages.Messages...)
+	}
+	sort.SliceStable(export.messages, func(i, j int) bool {
+		return export.messages[i].Id < export.messages[j].Id
+	})
+
+	return export, nil
+}
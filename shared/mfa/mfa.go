@@ -5,7 +5,9 @@ package mfa
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base32"
+	"encoding/hex"
 	"fmt"
 	"net/url"
 	"strings"
@@ -135,3 +137,41 @@ func (m *MFA) ValidateToken(secret, token string) (bool, error) {
 
 	return ok, nil
 }
+
+const (
+	// RecoveryCodeCount is the number of recovery codes generated at once.
+	RecoveryCodeCount = 10
+	// This will result in 80 bits of entropy per code, 16 characters once base32 encoded.
+	recoveryCodeSize = 10
+)
+
+// GenerateRecoveryCodes returns new one-time recovery codes, with which a user can complete
+// the multi-factor authentication when the device generating the tokens is lost.
+func GenerateRecoveryCodes() []string {
+	codes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		code := strings.ToLower(newRandomBase32String(recoveryCodeSize))
+		codes[i] = code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16]
+	}
+	return codes
+}
+
+// HashRecoveryCode returns the hash under which a recovery code is stored. Codes are random
+// enough not to need a slow hash, which lets them be looked up by their hash. The case,
+// the dashes and the spaces of the code are ignored.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsRecoveryCode tells whether the given token looks like a recovery code rather than a
+// token generated by a device.
+func IsRecoveryCode(token string) bool {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(token))
+	if len(normalized) != 16 {
+		return false
+	}
+	_, err := base32.StdEncoding.DecodeString(normalized)
+	return err == nil
+}
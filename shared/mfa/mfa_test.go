@@ -169,3 +169,27 @@ func TestRandomBase32String(t *testing.T) {
 		require.Len(t, str, base32.StdEncoding.EncodedLen(i))
 	}
 }
+
+func TestRecoveryCodes(t *testing.T) {
+	codes := GenerateRecoveryCodes()
+	require.Len(t, codes, RecoveryCodeCount)
+
+	hashes := map[string]bool{}
+	for _, code := range codes {
+		assert.Regexp(t, "^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$", code)
+		assert.True(t, IsRecoveryCode(code))
+		hashes[HashRecoveryCode(code)] = true
+	}
+	assert.Len(t, hashes, RecoveryCodeCount, "codes should be unique")
+
+	t.Run("hash ignores the case, dashes and spaces", func(t *testing.T) {
+		assert.Equal(t, HashRecoveryCode("abcd-efgh-ijkl-mnop"), HashRecoveryCode(" ABCDEFGH IJKLMNOP"))
+		assert.NotEqual(t, HashRecoveryCode("abcd-efgh-ijkl-mnop"), HashRecoveryCode("abcd-efgh-ijkl-mnoq"))
+	})
+
+	t.Run("tokens are not recovery codes", func(t *testing.T) {
+		assert.False(t, IsRecoveryCode("123456"))
+		assert.False(t, IsRecoveryCode("abcd-efgh-ijkl-mno1"))
+		assert.False(t, IsRecoveryCode(""))
+	})
+}
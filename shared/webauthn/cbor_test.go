@@ -0,0 +1,57 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package webauthn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Data     []byte
+		Expected interface{}
+	}{
+		{"small integer", []byte{0x0a}, int64(10)},
+		{"integer", []byte{0x19, 0x03, 0xe8}, int64(1000)},
+		{"negative integer", []byte{0x38, 0x63}, int64(-100)},
+		{"byte string", []byte{0x44, 0x01, 0x02, 0x03, 0x04}, []byte{1, 2, 3, 4}},
+		{"text string", []byte{0x64, 0x49, 0x45, 0x54, 0x46}, "IETF"},
+		{"array", []byte{0x83, 0x01, 0x02, 0x03}, []interface{}{int64(1), int64(2), int64(3)}},
+		{"map", []byte{0xa2, 0x61, 0x61, 0x01, 0x20, 0xf5}, map[interface{}]interface{}{"a": int64(1), int64(-1): true}},
+		{"tagged item", []byte{0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0}, int64(1363896240)},
+		{"null", []byte{0xf6}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			item, rest, err := decodeCBOR(append(tc.Data, 0xff))
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, item)
+			assert.Equal(t, []byte{0xff}, rest)
+		})
+	}
+
+	t.Run("should reject truncated data", func(t *testing.T) {
+		_, _, err := decodeCBOR([]byte{0x44, 0x01, 0x02})
+		require.Error(t, err)
+	})
+
+	t.Run("should reject indefinite lengths", func(t *testing.T) {
+		_, _, err := decodeCBOR([]byte{0x9f, 0x01, 0xff})
+		require.Error(t, err)
+	})
+
+	t.Run("should reject deep nesting", func(t *testing.T) {
+		data := make([]byte, 0, 40)
+		for i := 0; i < 40; i++ {
+			data = append(data, 0x81)
+		}
+		_, _, err := decodeCBOR(append(data, 0x01))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// The COSE algorithms accepted for the credentials, as registered by IANA.
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgES384 int64 = -35
+	AlgES512 int64 = -36
+	AlgRS256 int64 = -257
+)
+
+// The COSE key parameters, as defined by RFC 8152.
+const (
+	coseKeyType    int64 = 1
+	coseKeyAlg     int64 = 3
+	coseKeyCrv     int64 = -1
+	coseKeyX       int64 = -2
+	coseKeyY       int64 = -3
+	coseKeyRSAN    int64 = -1
+	coseKeyRSAE    int64 = -2
+	coseKtyOKP     int64 = 1
+	coseKtyEC2     int64 = 2
+	coseKtyRSA     int64 = 3
+	coseCrvP256    int64 = 1
+	coseCrvP384    int64 = 2
+	coseCrvP521    int64 = 3
+	coseCrvEd25519 int64 = 6
+)
+
+// publicKey is a credential public key decoded from its COSE encoding.
+type publicKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+func parsePublicKey(data []byte) (*publicKey, error) {
+	item, rest, err := decodeCBOR(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the public key")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("unexpected data after the public key")
+	}
+
+	m, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("the public key should be a map")
+	}
+
+	kty, _ := m[coseKeyType].(int64)
+	alg, _ := m[coseKeyAlg].(int64)
+
+	switch kty {
+	case coseKtyEC2:
+		var curve elliptic.Curve
+		crv, _ := m[coseKeyCrv].(int64)
+		switch {
+		case alg == AlgES256 && crv == coseCrvP256:
+			curve = elliptic.P256()
+		case alg == AlgES384 && crv == coseCrvP384:
+			curve = elliptic.P384()
+		case alg == AlgES512 && crv == coseCrvP521:
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported EC2 algorithm %d with curve %d", alg, crv)
+		}
+		x, xOk := m[coseKeyX].([]byte)
+		y, yOk := m[coseKeyY].([]byte)
+		if !xOk || !yOk {
+			return nil, errors.New("missing EC2 coordinates")
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("the EC2 point is not on the curve")
+		}
+		return &publicKey{alg: alg, key: key}, nil
+	case coseKtyOKP:
+		crv, _ := m[coseKeyCrv].(int64)
+		x, xOk := m[coseKeyX].([]byte)
+		if alg != AlgEdDSA || crv != coseCrvEd25519 || !xOk || len(x) != ed25519.PublicKeySize {
+			return nil, errors.Errorf("unsupported OKP algorithm %d with curve %d", alg, crv)
+		}
+		return &publicKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+	case coseKtyRSA:
+		n, nOk := m[coseKeyRSAN].([]byte)
+		e, eOk := m[coseKeyRSAE].([]byte)
+		if alg != AlgRS256 || !nOk || !eOk {
+			return nil, errors.Errorf("unsupported RSA algorithm %d", alg)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &publicKey{alg: alg, key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}}, nil
+	}
+
+	return nil, errors.Errorf("unsupported key type %d", kty)
+}
+
+// verify checks the signature of the message by the key.
+func (k *publicKey) verify(message, signature []byte) error {
+	if !verifySignature(k.alg, k.key, message, signature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func verifySignature(alg int64, key crypto.PublicKey, message, signature []byte) bool {
+	switch alg {
+	case AlgES256, AlgES384, AlgES512:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return false
+		}
+		return ecdsa.VerifyASN1(ecKey, hashMessage(alg, message), signature)
+	case AlgEdDSA:
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return false
+		}
+		return ed25519.Verify(edKey, message, signature)
+	case AlgRS256:
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return false
+		}
+		return rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, hashMessage(alg, message), signature) == nil
+	}
+	return false
+}
+
+func hashMessage(alg int64, message []byte) []byte {
+	hash := crypto.SHA256
+	switch alg {
+	case AlgES384:
+		hash = crypto.SHA384
+	case AlgES512:
+		hash = crypto.SHA512
+	}
+	h := hash.New()
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+// x509SignatureAlgorithm returns the algorithm with which an attestation certificate
+// signs, for the given COSE algorithm.
+func x509SignatureAlgorithm(alg int64) x509.SignatureAlgorithm {
+	switch alg {
+	case AlgES256:
+		return x509.ECDSAWithSHA256
+	case AlgES384:
+		return x509.ECDSAWithSHA384
+	case AlgES512:
+		return x509.ECDSAWithSHA512
+	case AlgEdDSA:
+		return x509.PureEd25519
+	case AlgRS256:
+		return x509.SHA256WithRSA
+	}
+	return x509.UnknownSignatureAlgorithm
+}
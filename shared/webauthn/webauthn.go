@@ -0,0 +1,550 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package webauthn implements the relying party side of the Web Authentication
+// ceremonies, registering the credentials of the authenticators and verifying their
+// assertions, as defined by https://www.w3.org/TR/webauthn-2/.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	CredentialTypePublicKey = "public-key"
+
+	ClientDataTypeCreate = "webauthn.create"
+	ClientDataTypeGet    = "webauthn.get"
+
+	UserVerificationRequired  = "required"
+	UserVerificationPreferred = "preferred"
+
+	ResidentKeyPreferred = "preferred"
+
+	AttestationNone = "none"
+
+	attestationFormatNone   = "none"
+	attestationFormatPacked = "packed"
+
+	flagUserPresent            byte = 0x01
+	flagUserVerified           byte = 0x04
+	flagAttestedCredentialData byte = 0x40
+
+	// authenticatorDataMinSize is the size of the RP ID hash, the flags and the signature
+	// counter of the authenticator data.
+	authenticatorDataMinSize = 37
+	aaguidSize               = 16
+
+	// MaxCredentialIdSize is the maximum size of the credential ids of the authenticators.
+	MaxCredentialIdSize = 1023
+
+	DefaultTimeout = 5 * time.Minute
+)
+
+// ErrSignCount indicates that the signature counter of an authenticator went backwards,
+// which is the sign of a cloned authenticator.
+var ErrSignCount = errors.New("the signature counter of the authenticator did not increase")
+
+// RelyingParty is the server the credentials are registered with.
+type RelyingParty struct {
+	// ID is the domain the credentials are scoped to.
+	ID string
+	// Name is the name of the relying party displayed by the authenticators.
+	Name string
+	// Origin is the origin of the pages running the ceremonies.
+	Origin string
+	// Timeout is the time the users have to complete a ceremony.
+	Timeout time.Duration
+}
+
+// NewRelyingParty returns the relying party serving the given site URL.
+func NewRelyingParty(siteURL, name string) (*RelyingParty, error) {
+	u, err := url.Parse(strings.TrimSpace(siteURL))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse the site URL")
+	}
+
+	if u.Scheme != "https" && u.Scheme != "http" || u.Hostname() == "" {
+		return nil, errors.New("the site URL should be an absolute http or https URL")
+	}
+
+	if net.ParseIP(u.Hostname()) != nil {
+		return nil, errors.New("the site URL should use a domain name")
+	}
+
+	return &RelyingParty{
+		ID:      strings.ToLower(u.Hostname()),
+		Name:    name,
+		Origin:  strings.ToLower(u.Scheme + "://" + u.Host),
+		Timeout: DefaultTimeout,
+	}, nil
+}
+
+type RelyingPartyEntity struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserEntity struct {
+	// Id is the base64url encoded user handle.
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type CredentialDescriptor struct {
+	Type string `json:"type"`
+	// Id is the base64url encoded credential id.
+	Id         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey,omitempty"`
+	UserVerification string `json:"userVerification,omitempty"`
+}
+
+// CreationOptions are the options of navigator.credentials.create, in the JSON form
+// read by PublicKeyCredential.parseCreationOptionsFromJSON.
+type CreationOptions struct {
+	RP                     RelyingPartyEntity     `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	Challenge              string                 `json:"challenge"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout,omitempty"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions are the options of navigator.credentials.get, in the JSON form read by
+// PublicKeyCredential.parseRequestOptionsFromJSON.
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout,omitempty"`
+	RPId             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                 `json:"userVerification,omitempty"`
+}
+
+type AuthenticatorAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON"`
+	AttestationObject string   `json:"attestationObject"`
+	Transports        []string `json:"transports,omitempty"`
+}
+
+// RegistrationResponse is the credential created by the authenticator, in the JSON form
+// returned by PublicKeyCredential.toJSON, with the binary fields base64url encoded.
+type RegistrationResponse struct {
+	Id       string                           `json:"id"`
+	RawId    string                           `json:"rawId"`
+	Type     string                           `json:"type"`
+	Response AuthenticatorAttestationResponse `json:"response"`
+}
+
+type AuthenticatorAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+// AssertionResponse is the assertion of the authenticator, in the JSON form returned by
+// PublicKeyCredential.toJSON, with the binary fields base64url encoded.
+type AssertionResponse struct {
+	Id       string                         `json:"id"`
+	RawId    string                         `json:"rawId"`
+	Type     string                         `json:"type"`
+	Response AuthenticatorAssertionResponse `json:"response"`
+}
+
+// Credential is a credential registered by an authenticator.
+type Credential struct {
+	Id         []byte
+	PublicKey  []byte
+	SignCount  uint32
+	AAGUID     []byte
+	Transports []string
+}
+
+type collectedClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+type authenticatorData struct {
+	rpIdHash     []byte
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialId []byte
+	publicKey    []byte
+}
+
+// EncodeBase64 encodes the data in the unpadded base64url encoding used by WebAuthn.
+func EncodeBase64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeBase64 decodes base64url encoded data, with or without padding.
+func DecodeBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func (rp *RelyingParty) timeout() int64 {
+	return rp.Timeout.Milliseconds()
+}
+
+// NewCreationOptions returns the options to register a credential for the user, which
+// must not be one of the excluded ones already registered.
+func (rp *RelyingParty) NewCreationOptions(challenge []byte, user UserEntity, excluded []CredentialDescriptor) *CreationOptions {
+	return &CreationOptions{
+		RP:        RelyingPartyEntity{Id: rp.ID, Name: rp.Name},
+		User:      user,
+		Challenge: EncodeBase64(challenge),
+		PubKeyCredParams: []CredentialParameter{
+			{Type: CredentialTypePublicKey, Alg: AlgES256},
+			{Type: CredentialTypePublicKey, Alg: AlgEdDSA},
+			{Type: CredentialTypePublicKey, Alg: AlgRS256},
+		},
+		Timeout:            rp.timeout(),
+		ExcludeCredentials: excluded,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      ResidentKeyPreferred,
+			UserVerification: UserVerificationRequired,
+		},
+		Attestation: AttestationNone,
+	}
+}
+
+// NewRequestOptions returns the options to get an assertion from one of the allowed
+// credentials, or from any discoverable credential of the relying party when none are.
+func (rp *RelyingParty) NewRequestOptions(challenge []byte, allowed []CredentialDescriptor, userVerification string) *RequestOptions {
+	return &RequestOptions{
+		Challenge:        EncodeBase64(challenge),
+		Timeout:          rp.timeout(),
+		RPId:             rp.ID,
+		AllowCredentials: allowed,
+		UserVerification: userVerification,
+	}
+}
+
+// Challenge returns the challenge the credential was created for, so that the ceremony
+// it completes can be looked up. The challenge is verified by VerifyRegistration.
+func (r *RegistrationResponse) Challenge() ([]byte, error) {
+	clientData, err := parseClientData(r.Response.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBase64(clientData.Challenge)
+}
+
+// Challenge returns the challenge the assertion was made for, so that the ceremony it
+// completes can be looked up. The challenge is verified by VerifyAssertion.
+func (r *AssertionResponse) Challenge() ([]byte, error) {
+	clientData, err := parseClientData(r.Response.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBase64(clientData.Challenge)
+}
+
+// CredentialId returns the id of the credential which made the assertion.
+func (r *AssertionResponse) CredentialId() ([]byte, error) {
+	return DecodeBase64(r.RawId)
+}
+
+// UserHandle returns the user handle of the credential which made the assertion, which
+// is only returned by the discoverable credentials.
+func (r *AssertionResponse) UserHandle() ([]byte, error) {
+	return DecodeBase64(r.Response.UserHandle)
+}
+
+// VerifyRegistration verifies the credential created by the authenticator for the given
+// challenge, and returns it to be stored.
+func (rp *RelyingParty) VerifyRegistration(challenge []byte, response *RegistrationResponse, requireUserVerification bool) (*Credential, error) {
+	if response.Type != CredentialTypePublicKey {
+		return nil, errors.Errorf("unexpected credential type %s", response.Type)
+	}
+
+	clientDataJSON, err := DecodeBase64(response.Response.ClientDataJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the client data")
+	}
+	if err = rp.verifyClientData(clientDataJSON, ClientDataTypeCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	attestationObject, err := DecodeBase64(response.Response.AttestationObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the attestation object")
+	}
+	item, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the attestation object")
+	}
+	attestation, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("the attestation object should be a map")
+	}
+	format, _ := attestation["fmt"].(string)
+	statement, _ := attestation["attStmt"].(map[interface{}]interface{})
+	rawAuthData, _ := attestation["authData"].([]byte)
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err = rp.verifyAuthenticatorData(authData, requireUserVerification); err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttestedCredentialData == 0 {
+		return nil, errors.New("missing attested credential data")
+	}
+
+	rawId, err := DecodeBase64(response.RawId)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the credential id")
+	}
+	if !bytes.Equal(rawId, authData.credentialId) {
+		return nil, errors.New("the credential id doesn't match the attested credential data")
+	}
+
+	key, err := parsePublicKey(authData.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = verifyAttestationStatement(format, statement, key, signedData(rawAuthData, clientDataJSON)); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		Id:         authData.credentialId,
+		PublicKey:  authData.publicKey,
+		SignCount:  authData.signCount,
+		AAGUID:     authData.aaguid,
+		Transports: response.Response.Transports,
+	}, nil
+}
+
+// VerifyAssertion verifies the assertion made by the credential for the given challenge,
+// and returns the new signature counter of the credential to be stored.
+func (rp *RelyingParty) VerifyAssertion(challenge []byte, response *AssertionResponse, credential *Credential, requireUserVerification bool) (uint32, error) {
+	if response.Type != CredentialTypePublicKey {
+		return 0, errors.Errorf("unexpected credential type %s", response.Type)
+	}
+
+	rawId, err := DecodeBase64(response.RawId)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to decode the credential id")
+	}
+	if !bytes.Equal(rawId, credential.Id) {
+		return 0, errors.New("the assertion was made by another credential")
+	}
+
+	clientDataJSON, err := DecodeBase64(response.Response.ClientDataJSON)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to decode the client data")
+	}
+	if err = rp.verifyClientData(clientDataJSON, ClientDataTypeGet, challenge); err != nil {
+		return 0, err
+	}
+
+	rawAuthData, err := DecodeBase64(response.Response.AuthenticatorData)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to decode the authenticator data")
+	}
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return 0, err
+	}
+	if err = rp.verifyAuthenticatorData(authData, requireUserVerification); err != nil {
+		return 0, err
+	}
+
+	signature, err := DecodeBase64(response.Response.Signature)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to decode the signature")
+	}
+	key, err := parsePublicKey(credential.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+	if err = key.verify(signedData(rawAuthData, clientDataJSON), signature); err != nil {
+		return 0, err
+	}
+
+	// The authenticators without a counter always return 0.
+	if (authData.signCount != 0 || credential.SignCount != 0) && authData.signCount <= credential.SignCount {
+		return 0, ErrSignCount
+	}
+
+	return authData.signCount, nil
+}
+
+// signedData returns the data signed by the authenticators, which is the authenticator
+// data followed by the hash of the client data.
+func signedData(authData, clientDataJSON []byte) []byte {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	data := make([]byte, 0, len(authData)+len(clientDataHash))
+	data = append(data, authData...)
+	return append(data, clientDataHash[:]...)
+}
+
+func parseClientData(encoded string) (*collectedClientData, error) {
+	data, err := DecodeBase64(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the client data")
+	}
+
+	var clientData collectedClientData
+	if err := json.Unmarshal(data, &clientData); err != nil {
+		return nil, errors.Wrap(err, "unable to parse the client data")
+	}
+	return &clientData, nil
+}
+
+func (rp *RelyingParty) verifyClientData(clientDataJSON []byte, ceremonyType string, challenge []byte) error {
+	var clientData collectedClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return errors.Wrap(err, "unable to parse the client data")
+	}
+
+	if clientData.Type != ceremonyType {
+		return errors.Errorf("unexpected client data type %s", clientData.Type)
+	}
+
+	received, err := DecodeBase64(clientData.Challenge)
+	if err != nil {
+		return errors.Wrap(err, "unable to decode the challenge")
+	}
+	if len(challenge) == 0 || subtle.ConstantTimeCompare(received, challenge) != 1 {
+		return errors.New("the challenge doesn't match")
+	}
+
+	if !strings.EqualFold(clientData.Origin, rp.Origin) {
+		return errors.Errorf("unexpected origin %s", clientData.Origin)
+	}
+
+	if clientData.CrossOrigin {
+		return errors.New("cross-origin ceremonies are not allowed")
+	}
+
+	return nil
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < authenticatorDataMinSize {
+		return nil, errors.New("the authenticator data is too short")
+	}
+
+	authData := &authenticatorData{
+		rpIdHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	if authData.flags&flagAttestedCredentialData == 0 {
+		return authData, nil
+	}
+
+	rest := data[authenticatorDataMinSize:]
+	if len(rest) < aaguidSize+2 {
+		return nil, errors.New("the attested credential data is too short")
+	}
+	authData.aaguid = rest[:aaguidSize]
+	idLength := int(binary.BigEndian.Uint16(rest[aaguidSize:]))
+	rest = rest[aaguidSize+2:]
+	if idLength > MaxCredentialIdSize || idLength > len(rest) {
+		return nil, errors.New("invalid credential id length")
+	}
+	authData.credentialId = rest[:idLength]
+	rest = rest[idLength:]
+
+	// The public key is followed by the extensions, if any, so its size is only known
+	// once decoded.
+	_, afterKey, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the credential public key")
+	}
+	authData.publicKey = rest[:len(rest)-len(afterKey)]
+
+	return authData, nil
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(authData *authenticatorData, requireUserVerification bool) error {
+	rpIdHash := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(authData.rpIdHash, rpIdHash[:]) != 1 {
+		return errors.New("the relying party id doesn't match")
+	}
+
+	if authData.flags&flagUserPresent == 0 {
+		return errors.New("the user was not present")
+	}
+
+	if requireUserVerification && authData.flags&flagUserVerified == 0 {
+		return errors.New("the user was not verified")
+	}
+
+	return nil
+}
+
+// verifyAttestationStatement verifies the signature of the attestation statement. As no
+// attestation is requested, the trust path of the attestation certificates is not
+// validated, and the credentials are accepted for any authenticator.
+func verifyAttestationStatement(format string, statement map[interface{}]interface{}, key *publicKey, signedData []byte) error {
+	switch format {
+	case attestationFormatNone:
+		if len(statement) != 0 {
+			return errors.New("the none attestation statement should be empty")
+		}
+		return nil
+	case attestationFormatPacked:
+		alg, _ := statement["alg"].(int64)
+		signature, _ := statement["sig"].([]byte)
+		certificates, hasCertificates := statement["x5c"].([]interface{})
+
+		if !hasCertificates {
+			// Self attestation, signed by the credential itself.
+			if alg != key.alg {
+				return errors.New("the attestation algorithm doesn't match the credential")
+			}
+			return key.verify(signedData, signature)
+		}
+
+		if len(certificates) == 0 {
+			return errors.New("missing attestation certificate")
+		}
+		der, _ := certificates[0].([]byte)
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return errors.Wrap(err, "unable to parse the attestation certificate")
+		}
+		if err := certificate.CheckSignature(x509SignatureAlgorithm(alg), signedData, signature); err != nil {
+			return errors.Wrap(err, "invalid attestation signature")
+		}
+		return nil
+	}
+
+	return errors.Errorf("unsupported attestation format %s", format)
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package webauthn_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/shared/webauthn"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/webauthn/webauthntest"
+)
+
+var (
+	testChallenge = []byte("01234567890123456789012345678901")
+	testUser      = webauthn.UserEntity{Id: webauthn.EncodeBase64([]byte("user-id")), Name: "user", DisplayName: "User"}
+)
+
+func newRelyingParty(t *testing.T) *webauthn.RelyingParty {
+	rp, err := webauthn.NewRelyingParty("https://chat.example.com/subpath", "Matterfoss")
+	require.NoError(t, err)
+	return rp
+}
+
+func register(t *testing.T, rp *webauthn.RelyingParty, authenticator *webauthntest.Authenticator) *webauthn.Credential {
+	response, err := authenticator.Register(rp.NewCreationOptions(testChallenge, testUser, nil))
+	require.NoError(t, err)
+	credential, err := rp.VerifyRegistration(testChallenge, response, true)
+	require.NoError(t, err)
+	return credential
+}
+
+func TestNewRelyingParty(t *testing.T) {
+	rp, err := webauthn.NewRelyingParty("https://Chat.Example.com:8443/subpath", "Matterfoss")
+	require.NoError(t, err)
+	assert.Equal(t, "chat.example.com", rp.ID)
+	assert.Equal(t, "https://chat.example.com:8443", rp.Origin)
+
+	_, err = webauthn.NewRelyingParty("", "Matterfoss")
+	require.Error(t, err)
+
+	_, err = webauthn.NewRelyingParty("https://10.0.0.1", "Matterfoss")
+	require.Error(t, err)
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	rp := newRelyingParty(t)
+
+	t.Run("should return the registered credential", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(rp.Origin)
+		response, err := authenticator.Register(rp.NewCreationOptions(testChallenge, testUser, nil))
+		require.NoError(t, err)
+
+		credential, err := rp.VerifyRegistration(testChallenge, response, true)
+		require.NoError(t, err)
+		assert.Equal(t, response.RawId, webauthn.EncodeBase64(credential.Id))
+		assert.NotEmpty(t, credential.PublicKey)
+		assert.Equal(t, uint32(0), credential.SignCount)
+		assert.Equal(t, []string{"internal"}, credential.Transports)
+
+		challenge, err := response.Challenge()
+		require.NoError(t, err)
+		assert.Equal(t, testChallenge, challenge)
+	})
+
+	t.Run("should reject another challenge", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(rp.Origin)
+		response, err := authenticator.Register(rp.NewCreationOptions([]byte("another challenge"), testUser, nil))
+		require.NoError(t, err)
+
+		_, err = rp.VerifyRegistration(testChallenge, response, true)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject another origin", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator("https://phishing.example.com")
+		response, err := authenticator.Register(rp.NewCreationOptions(testChallenge, testUser, nil))
+		require.NoError(t, err)
+
+		_, err = rp.VerifyRegistration(testChallenge, response, true)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject another relying party", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(rp.Origin)
+		options := rp.NewCreationOptions(testChallenge, testUser, nil)
+		options.RP.Id = "example.org"
+		response, err := authenticator.Register(options)
+		require.NoError(t, err)
+
+		_, err = rp.VerifyRegistration(testChallenge, response, true)
+		require.Error(t, err)
+	})
+
+	t.Run("should require user verification when asked to", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(rp.Origin)
+		authenticator.UserVerified = false
+		response, err := authenticator.Register(rp.NewCreationOptions(testChallenge, testUser, nil))
+		require.NoError(t, err)
+
+		_, err = rp.VerifyRegistration(testChallenge, response, true)
+		require.Error(t, err)
+
+		_, err = rp.VerifyRegistration(testChallenge, response, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject a login assertion", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(rp.Origin)
+		register(t, rp, authenticator)
+		assertion, err := authenticator.Login(rp.NewRequestOptions(testChallenge, nil, webauthn.UserVerificationRequired))
+		require.NoError(t, err)
+
+		response := &webauthn.RegistrationResponse{
+			Id:    assertion.Id,
+			RawId: assertion.RawId,
+			Type:  assertion.Type,
+			Response: webauthn.AuthenticatorAttestationResponse{
+				ClientDataJSON: assertion.Response.ClientDataJSON,
+			},
+		}
+		_, err = rp.VerifyRegistration(testChallenge, response, true)
+		require.Error(t, err)
+	})
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	rp := newRelyingParty(t)
+
+	t.Run("should verify the assertion of the credential", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(rp.Origin)
+		credential := register(t, rp, authenticator)
+
+		allowed := []webauthn.CredentialDescriptor{{Type: webauthn.CredentialTypePublicKey, Id: webauthn.EncodeBase64(credential.Id)}}
+		response, err := authenticator.Login(rp.NewRequestOptions(testChallenge, allowed, webauthn.UserVerificationRequired))
+		require.NoError(t, err)
+
+		signCount, err := rp.VerifyAssertion(testChallenge, response, credential, true)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), signCount)
+
+		userHandle, err := response.UserHandle()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("user-id"), userHandle)
+	})
+
+	t.Run("should reject a replayed assertion", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(rp.Origin)
+		credential := register(t, rp, authenticator)
+
+		response, err := authenticator.Login(rp.NewRequestOptions(testChallenge, nil, webauthn.UserVerificationRequired))
+		require.NoError(t, err)
+		credential.SignCount, err = rp.VerifyAssertion(testChallenge, response, credential, true)
+		require.NoError(t, err)
+
+		_, err = rp.VerifyAssertion(testChallenge, response, credential, true)
+		require.Equal(t, webauthn.ErrSignCount, err)
+	})
+
+	t.Run("should reject another challenge", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(rp.Origin)
+		credential := register(t, rp, authenticator)
+
+		response, err := authenticator.Login(rp.NewRequestOptions([]byte("another challenge"), nil, webauthn.UserVerificationRequired))
+		require.NoError(t, err)
+		_, err = rp.VerifyAssertion(testChallenge, response, credential, true)
+		require.Error(t, err)
+	})
+
+	t.Run("should reject the signature of another credential", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(rp.Origin)
+		credential := register(t, rp, authenticator)
+		other := register(t, rp, webauthntest.NewAuthenticator(rp.Origin))
+		credential.PublicKey = other.PublicKey
+
+		response, err := authenticator.Login(rp.NewRequestOptions(testChallenge, nil, webauthn.UserVerificationRequired))
+		require.NoError(t, err)
+		_, err = rp.VerifyAssertion(testChallenge, response, credential, true)
+		require.Error(t, err)
+	})
+
+	t.Run("should require user verification when asked to", func(t *testing.T) {
+		authenticator := webauthntest.NewAuthenticator(rp.Origin)
+		credential := register(t, rp, authenticator)
+		authenticator.UserVerified = false
+
+		response, err := authenticator.Login(rp.NewRequestOptions(testChallenge, nil, webauthn.UserVerificationRequired))
+		require.NoError(t, err)
+		_, err = rp.VerifyAssertion(testChallenge, response, credential, true)
+		require.Error(t, err)
+
+		_, err = rp.VerifyAssertion(testChallenge, response, credential, false)
+		require.NoError(t, err)
+	})
+}
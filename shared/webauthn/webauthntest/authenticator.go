@@ -0,0 +1,204 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package webauthntest provides a software authenticator to run the WebAuthn ceremonies
+// in tests.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/shared/webauthn"
+)
+
+const (
+	flagUserPresent            byte = 0x01
+	flagUserVerified           byte = 0x04
+	flagAttestedCredentialData byte = 0x40
+)
+
+type credential struct {
+	id         []byte
+	rpId       string
+	userHandle []byte
+	key        *ecdsa.PrivateKey
+	signCount  uint32
+}
+
+// Authenticator is a software authenticator holding ES256 discoverable credentials.
+type Authenticator struct {
+	// Origin is the origin of the page reported in the client data.
+	Origin string
+	// UserVerified tells whether the authenticator verifies the user.
+	UserVerified bool
+
+	mutex       sync.Mutex
+	credentials []*credential
+}
+
+// NewAuthenticator returns an authenticator verifying the user, used from the given origin.
+func NewAuthenticator(origin string) *Authenticator {
+	return &Authenticator{
+		Origin:       origin,
+		UserVerified: true,
+	}
+}
+
+func (a *Authenticator) flags() byte {
+	flags := flagUserPresent
+	if a.UserVerified {
+		flags |= flagUserVerified
+	}
+	return flags
+}
+
+func (a *Authenticator) clientData(ceremonyType, challenge string) string {
+	data, _ := json.Marshal(map[string]interface{}{
+		"type":        ceremonyType,
+		"challenge":   challenge,
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+	return webauthn.EncodeBase64(data)
+}
+
+// Register creates a credential with the given options, as navigator.credentials.create.
+func (a *Authenticator) Register(options *webauthn.CreationOptions) (*webauthn.RegistrationResponse, error) {
+	userHandle, err := webauthn.DecodeBase64(options.User.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the user handle")
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for _, excluded := range options.ExcludeCredentials {
+		for _, c := range a.credentials {
+			if c.rpId == options.RP.Id && webauthn.EncodeBase64(c.id) == excluded.Id {
+				return nil, errors.New("the authenticator already holds an excluded credential")
+			}
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	c := &credential{
+		id:         make([]byte, 32),
+		rpId:       options.RP.Id,
+		userHandle: userHandle,
+		key:        key,
+	}
+	if _, err := rand.Read(c.id); err != nil {
+		return nil, err
+	}
+
+	authData := a.authenticatorData(c.rpId, a.flags()|flagAttestedCredentialData, c.signCount)
+	authData = append(authData, make([]byte, 16)...)
+	authData = append(authData, byte(len(c.id)>>8), byte(len(c.id)))
+	authData = append(authData, c.id...)
+	authData = append(authData, EncodePublicKey(&key.PublicKey)...)
+
+	attestationObject := encodeCBOR(map[interface{}]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[interface{}]interface{}{},
+		"authData": authData,
+	})
+
+	a.credentials = append(a.credentials, c)
+
+	return &webauthn.RegistrationResponse{
+		Id:    webauthn.EncodeBase64(c.id),
+		RawId: webauthn.EncodeBase64(c.id),
+		Type:  webauthn.CredentialTypePublicKey,
+		Response: webauthn.AuthenticatorAttestationResponse{
+			ClientDataJSON:    a.clientData(webauthn.ClientDataTypeCreate, options.Challenge),
+			AttestationObject: webauthn.EncodeBase64(attestationObject),
+			Transports:        []string{"internal"},
+		},
+	}, nil
+}
+
+// Login makes an assertion with the given options, as navigator.credentials.get. When no
+// credentials are allowed, any credential of the relying party is used.
+func (a *Authenticator) Login(options *webauthn.RequestOptions) (*webauthn.AssertionResponse, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	c := a.findCredential(options)
+	if c == nil {
+		return nil, errors.New("no credential for the relying party")
+	}
+	c.signCount++
+
+	authData := a.authenticatorData(c.rpId, a.flags(), c.signCount)
+	clientData := a.clientData(webauthn.ClientDataTypeGet, options.Challenge)
+	clientDataJSON, _ := webauthn.DecodeBase64(clientData)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, c.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &webauthn.AssertionResponse{
+		Id:    webauthn.EncodeBase64(c.id),
+		RawId: webauthn.EncodeBase64(c.id),
+		Type:  webauthn.CredentialTypePublicKey,
+		Response: webauthn.AuthenticatorAssertionResponse{
+			ClientDataJSON:    clientData,
+			AuthenticatorData: webauthn.EncodeBase64(authData),
+			Signature:         webauthn.EncodeBase64(signature),
+			UserHandle:        webauthn.EncodeBase64(c.userHandle),
+		},
+	}, nil
+}
+
+func (a *Authenticator) findCredential(options *webauthn.RequestOptions) *credential {
+	for _, c := range a.credentials {
+		if c.rpId != options.RPId {
+			continue
+		}
+		if len(options.AllowCredentials) == 0 {
+			return c
+		}
+		for _, allowed := range options.AllowCredentials {
+			if webauthn.EncodeBase64(c.id) == allowed.Id {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+func (a *Authenticator) authenticatorData(rpId string, flags byte, signCount uint32) []byte {
+	rpIdHash := sha256.Sum256([]byte(rpId))
+	data := append([]byte{}, rpIdHash[:]...)
+	data = append(data, flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], signCount)
+	return data
+}
+
+// EncodePublicKey returns the COSE encoding of an ES256 public key.
+func EncodePublicKey(key *ecdsa.PublicKey) []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+	return encodeCBOR(map[interface{}]interface{}{
+		int64(1):  int64(2),
+		int64(3):  webauthn.AlgES256,
+		int64(-1): int64(1),
+		int64(-2): x,
+		int64(-3): y,
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package webauthntest
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// encodeCBOR encodes the subset of CBOR produced by the authenticators: integers, byte
+// and text strings, and maps with integer or text keys.
+func encodeCBOR(v interface{}) []byte {
+	switch value := v.(type) {
+	case int64:
+		if value < 0 {
+			return encodeCBORHead(1, uint64(-1-value))
+		}
+		return encodeCBORHead(0, uint64(value))
+	case []byte:
+		return append(encodeCBORHead(2, uint64(len(value))), value...)
+	case string:
+		return append(encodeCBORHead(3, uint64(len(value))), value...)
+	case map[interface{}]interface{}:
+		data := encodeCBORHead(5, uint64(len(value)))
+		for key, item := range value {
+			data = append(data, encodeCBOR(key)...)
+			data = append(data, encodeCBOR(item)...)
+		}
+		return data
+	}
+	panic(fmt.Sprintf("cbor: unsupported type %T", v))
+}
+
+func encodeCBORHead(majorType byte, arg uint64) []byte {
+	head := majorType << 5
+	switch {
+	case arg < 24:
+		return []byte{head | byte(arg)}
+	case arg <= 0xff:
+		return []byte{head | 24, byte(arg)}
+	case arg <= 0xffff:
+		data := []byte{head | 25, 0, 0}
+		binary.BigEndian.PutUint16(data[1:], uint16(arg))
+		return data
+	case arg <= 0xffffffff:
+		data := []byte{head | 26, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(data[1:], uint32(arg))
+		return data
+	}
+	data := []byte{head | 27, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint64(data[1:], arg)
+	return data
+}
@@ -25,6 +25,7 @@ func genStore() *mocks.Store {
 	mock.On("Command").Return(&mocks.CommandStore{})
 	mock.On("CommandWebhook").Return(&mocks.CommandWebhookStore{})
 	mock.On("Compliance").Return(&mocks.ComplianceStore{})
+	mock.On("Draft").Return(&mocks.DraftStore{})
 	mock.On("Emoji").Return(&mocks.EmojiStore{})
 	mock.On("FileInfo").Return(&mocks.FileInfoStore{})
 	mock.On("UploadSession").Return(&mocks.UploadSessionStore{})
@@ -42,6 +43,13 @@ func genStore() *mocks.Store {
 	mock.On("Reaction").Return(&mocks.ReactionStore{})
 	mock.On("RetentionPolicy").Return(&mocks.RetentionPolicyStore{})
 	mock.On("Role").Return(&mocks.RoleStore{})
+	mock.On("ScheduledPost").Return(&mocks.ScheduledPostStore{})
+	mock.On("PostRevision").Return(&mocks.PostRevisionStore{})
+	mock.On("Reminder").Return(&mocks.ReminderStore{})
+	mock.On("PollVote").Return(&mocks.PollVoteStore{})
+	mock.On("ChannelBookmark").Return(&mocks.ChannelBookmarkStore{})
+	mock.On("WebAuthnCredential").Return(&mocks.WebAuthnCredentialStore{})
+	mock.On("MfaRecoveryCode").Return(&mocks.MfaRecoveryCodeStore{})
 	mock.On("Scheme").Return(&mocks.SchemeStore{})
 	mock.On("Session").Return(&mocks.SessionStore{})
 	mock.On("Status").Return(&mocks.StatusStore{})
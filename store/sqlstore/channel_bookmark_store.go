@@ -0,0 +1,256 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+type SqlChannelBookmarkStore struct {
+	*SqlStore
+}
+
+func newSqlChannelBookmarkStore(sqlStore *SqlStore) store.ChannelBookmarkStore {
+	return &SqlChannelBookmarkStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlChannelBookmarkStore) bookmarksQuery() sq.SelectBuilder {
+	return s.getQueryBuilder().
+		Select("Id", "CreateAt", "UpdateAt", "DeleteAt", "ChannelId", "OwnerId", "FileId", "DisplayName",
+			"SortOrder", "LinkUrl", "ImageUrl", "Emoji", "Type", "RemoteId").
+		From("ChannelBookmarks")
+}
+
+func (s *SqlChannelBookmarkStore) Save(bookmark *model.ChannelBookmark) (*model.ChannelBookmark, error) {
+	if bookmark.Id != "" && !bookmark.IsRemote() {
+		return nil, store.NewErrInvalidInput("ChannelBookmark", "id", bookmark.Id)
+	}
+
+	bookmark.PreSave()
+	if err := bookmark.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query, args, err := s.getQueryBuilder().
+		Insert("ChannelBookmarks").
+		Columns("Id", "CreateAt", "UpdateAt", "DeleteAt", "ChannelId", "OwnerId", "FileId", "DisplayName",
+			"SortOrder", "LinkUrl", "ImageUrl", "Emoji", "Type", "RemoteId").
+		Values(bookmark.Id, bookmark.CreateAt, bookmark.UpdateAt, bookmark.DeleteAt, bookmark.ChannelId, bookmark.OwnerId, bookmark.FileId, bookmark.DisplayName,
+			bookmark.SortOrder, bookmark.LinkUrl, bookmark.ImageUrl, bookmark.Emoji, bookmark.Type, bookmark.RemoteId).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "channel_bookmark_save_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to save ChannelBookmark with id=%s", bookmark.Id)
+	}
+	return bookmark, nil
+}
+
+func (s *SqlChannelBookmarkStore) Get(id string, includeDeleted bool) (*model.ChannelBookmark, error) {
+	builder := s.bookmarksQuery().
+		Where(sq.Eq{"Id": id})
+	if !includeDeleted {
+		builder = builder.Where(sq.Eq{"DeleteAt": 0})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "channel_bookmark_get_tosql")
+	}
+
+	var bookmark model.ChannelBookmark
+	if err := s.GetReplicaX().Get(&bookmark, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("ChannelBookmark", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get ChannelBookmark with id=%s", id)
+	}
+	return &bookmark, nil
+}
+
+func (s *SqlChannelBookmarkStore) GetBookmarksForChannelSince(channelID string, since int64) ([]*model.ChannelBookmark, error) {
+	builder := s.bookmarksQuery().
+		Where(sq.Eq{"ChannelId": channelID}).
+		OrderBy("SortOrder", "CreateAt", "Id")
+	if since == 0 {
+		builder = builder.Where(sq.Eq{"DeleteAt": 0})
+	} else {
+		builder = builder.Where(sq.GtOrEq{"UpdateAt": since})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "channel_bookmark_getforchannel_tosql")
+	}
+
+	bookmarks := []*model.ChannelBookmark{}
+	if err := s.GetReplicaX().Select(&bookmarks, query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to find ChannelBookmarks with channelId=%s", channelID)
+	}
+	return bookmarks, nil
+}
+
+func (s *SqlChannelBookmarkStore) GetForFile(fileID string) ([]*model.ChannelBookmark, error) {
+	query, args, err := s.bookmarksQuery().
+		Where(sq.Eq{"FileId": fileID, "DeleteAt": 0}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "channel_bookmark_getforfile_tosql")
+	}
+
+	bookmarks := []*model.ChannelBookmark{}
+	if err := s.GetReplicaX().Select(&bookmarks, query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to find ChannelBookmarks with fileId=%s", fileID)
+	}
+	return bookmarks, nil
+}
+
+func (s *SqlChannelBookmarkStore) Update(bookmark *model.ChannelBookmark) error {
+	bookmark.PreUpdate()
+	if err := bookmark.IsValid(); err != nil {
+		return err
+	}
+
+	query, args, err := s.getQueryBuilder().
+		Update("ChannelBookmarks").
+		Set("UpdateAt", bookmark.UpdateAt).
+		Set("FileId", bookmark.FileId).
+		Set("DisplayName", bookmark.DisplayName).
+		Set("SortOrder", bookmark.SortOrder).
+		Set("LinkUrl", bookmark.LinkUrl).
+		Set("ImageUrl", bookmark.ImageUrl).
+		Set("Emoji", bookmark.Emoji).
+		Set("RemoteId", bookmark.RemoteId).
+		Where(sq.Eq{"Id": bookmark.Id, "DeleteAt": 0}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "channel_bookmark_update_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update ChannelBookmark with id=%s", bookmark.Id)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return store.NewErrNotFound("ChannelBookmark", bookmark.Id)
+	}
+	return nil
+}
+
+func (s *SqlChannelBookmarkStore) UpdateSortOrder(bookmarkID, channelID string, newIndex int64) ([]*model.ChannelBookmark, error) {
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return nil, errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction)
+
+	query, args, err := s.bookmarksQuery().
+		Where(sq.Eq{"ChannelId": channelID, "DeleteAt": 0}).
+		OrderBy("SortOrder", "CreateAt", "Id").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "channel_bookmark_updatesortorder_select_tosql")
+	}
+
+	bookmarks := []*model.ChannelBookmark{}
+	if err := transaction.Select(&bookmarks, query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to find ChannelBookmarks with channelId=%s", channelID)
+	}
+
+	current := -1
+	for i, bookmark := range bookmarks {
+		if bookmark.Id == bookmarkID {
+			current = i
+			break
+		}
+	}
+	if current == -1 {
+		return nil, store.NewErrNotFound("ChannelBookmark", bookmarkID)
+	}
+	if newIndex < 0 || newIndex >= int64(len(bookmarks)) {
+		return nil, store.NewErrInvalidInput("ChannelBookmark", "newIndex", strconv.FormatInt(newIndex, 10))
+	}
+
+	moved := bookmarks[current]
+	bookmarks = append(bookmarks[:current], bookmarks[current+1:]...)
+	bookmarks = append(bookmarks[:newIndex], append([]*model.ChannelBookmark{moved}, bookmarks[newIndex:]...)...)
+
+	now := model.GetMillis()
+	for i, bookmark := range bookmarks {
+		if bookmark.SortOrder == int64(i) {
+			continue
+		}
+		bookmark.SortOrder = int64(i)
+		bookmark.UpdateAt = now
+		bookmark.RemoteId = model.NewString("")
+
+		query, args, err := s.getQueryBuilder().
+			Update("ChannelBookmarks").
+			Set("SortOrder", bookmark.SortOrder).
+			Set("UpdateAt", bookmark.UpdateAt).
+			Set("RemoteId", "").
+			Where(sq.Eq{"Id": bookmark.Id}).
+			ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "channel_bookmark_updatesortorder_update_tosql")
+		}
+		if _, err := transaction.Exec(query, args...); err != nil {
+			return nil, errors.Wrapf(err, "failed to update ChannelBookmark with id=%s", bookmark.Id)
+		}
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, errors.Wrap(err, "commit_transaction")
+	}
+	return bookmarks, nil
+}
+
+func (s *SqlChannelBookmarkStore) Delete(id string) error {
+	now := model.GetMillis()
+	query, args, err := s.getQueryBuilder().
+		Update("ChannelBookmarks").
+		Set("DeleteAt", now).
+		Set("UpdateAt", now).
+		Set("RemoteId", "").
+		Where(sq.Eq{"Id": id, "DeleteAt": 0}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "channel_bookmark_delete_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete ChannelBookmark with id=%s", id)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return store.NewErrNotFound("ChannelBookmark", id)
+	}
+	return nil
+}
+
+func (s *SqlChannelBookmarkStore) PermanentDeleteByChannel(channelID string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("ChannelBookmarks").
+		Where(sq.Eq{"ChannelId": channelID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "channel_bookmark_permanentdeletebychannel_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return errors.Wrapf(err, "failed to delete ChannelBookmarks with channelId=%s", channelID)
+	}
+	return nil
+}
@@ -205,10 +205,14 @@ func (s SqlChannelMemberHistoryStore) PermanentDeleteBatch(endTime int64, limit
 		err   error
 	)
 
-	if s.DriverName() == model.DatabaseDriverPostgres {
+	if s.DriverName() == model.DatabaseDriverPostgres || s.DriverName() == model.DatabaseDriverSqlite {
+		rowIDColumn := "ctid"
+		if s.DriverName() == model.DatabaseDriverSqlite {
+			rowIDColumn = "rowid"
+		}
 		var innerSelect string
 		innerSelect, args, err = s.getQueryBuilder().
-			Select("ctid").
+			Select(rowIDColumn).
 			From("ChannelMemberHistory").
 			Where(sq.And{
 				sq.NotEq{"LeaveTime": nil},
@@ -221,7 +225,7 @@ func (s SqlChannelMemberHistoryStore) PermanentDeleteBatch(endTime int64, limit
 		query, _, err = s.getQueryBuilder().
 			Delete("ChannelMemberHistory").
 			Where(fmt.Sprintf(
-				"ctid IN (%s)", innerSelect,
+				"%s IN (%s)", rowIDColumn, innerSelect,
 			)).ToSql()
 	} else {
 		query, args, err = s.getQueryBuilder().
@@ -645,9 +645,9 @@ func (s SqlChannelStore) SaveDirectChannel(directChannel *model.Channel, member1
 	member2.ChannelId = newChannel.Id
 
 	if member1.UserId != member2.UserId {
-		_, err = s.saveMultipleMembers([]*model.ChannelMember{member1, member2})
+		_, err = s.saveMultipleMembers(transaction, []*model.ChannelMember{member1, member2})
 	} else {
-		_, err = s.saveMemberT(member2)
+		_, err = s.saveMemberT(transaction, member2)
 	}
 	if err != nil {
 		return nil, err
@@ -1619,7 +1619,7 @@ func (s SqlChannelStore) SaveMultipleMembers(members []*model.ChannelMember) ([]
 		defer s.InvalidateAllChannelMembersForUser(member.UserId)
 	}
 
-	newMembers, err := s.saveMultipleMembers(members)
+	newMembers, err := s.saveMultipleMembers(s.GetMasterX(), members)
 	if err != nil {
 		return nil, err
 	}
@@ -1635,7 +1635,7 @@ func (s SqlChannelStore) SaveMember(member *model.ChannelMember) (*model.Channel
 	return newMembers[0], nil
 }
 
-func (s SqlChannelStore) saveMultipleMembers(members []*model.ChannelMember) ([]*model.ChannelMember, error) {
+func (s SqlChannelStore) saveMultipleMembers(db sqlxExecutor, members []*model.ChannelMember) ([]*model.ChannelMember, error) {
 	newChannelMembers := map[string]int{}
 	users := map[string]bool{}
 	for _, member := range members {
@@ -1686,7 +1686,7 @@ func (s SqlChannelStore) saveMultipleMembers(members []*model.ChannelMember) ([]
 		User  sql.NullString
 		Admin sql.NullString
 	}{}
-	err = s.GetMasterX().Select(&defaultChannelsRoles, channelRolesSql, channelRolesArgs...)
+	err = db.Select(&defaultChannelsRoles, channelRolesSql, channelRolesArgs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "default_channel_roles_select")
 	}
@@ -1725,7 +1725,7 @@ func (s SqlChannelStore) saveMultipleMembers(members []*model.ChannelMember) ([]
 		User  sql.NullString
 		Admin sql.NullString
 	}{}
-	err = s.GetMasterX().Select(&defaultTeamsRoles, teamRolesSql, teamRolesArgs...)
+	err = db.Select(&defaultTeamsRoles, teamRolesSql, teamRolesArgs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "default_team_roles_select")
 	}
@@ -1744,7 +1744,7 @@ func (s SqlChannelStore) saveMultipleMembers(members []*model.ChannelMember) ([]
 		return nil, errors.Wrap(err, "channel_members_tosql")
 	}
 
-	if _, err := s.GetMasterX().Exec(sql, args...); err != nil {
+	if _, err := db.Exec(sql, args...); err != nil {
 		if IsUniqueConstraintError(err, []string{"ChannelId", "channelmembers_pkey", "PRIMARY"}) {
 			return nil, store.NewErrConflict("ChannelMembers", err, "")
 		}
@@ -1776,8 +1776,8 @@ func (s SqlChannelStore) saveMultipleMembers(members []*model.ChannelMember) ([]
 	return newMembers, nil
 }
 
-func (s SqlChannelStore) saveMemberT(member *model.ChannelMember) (*model.ChannelMember, error) {
-	members, err := s.saveMultipleMembers([]*model.ChannelMember{member})
+func (s SqlChannelStore) saveMemberT(transaction *sqlxTxWrapper, member *model.ChannelMember) (*model.ChannelMember, error) {
+	members, err := s.saveMultipleMembers(transaction, []*model.ChannelMember{member})
 	if err != nil {
 		return nil, err
 	}
@@ -2234,6 +2234,16 @@ func (s SqlChannelStore) GetMemberCountsByGroup(ctx context.Context, channelID s
 					THEN Timezone->>'manualTimezone'
 					END
 				)) AS ChannelMemberTimezonesCount`
+		} else if s.DriverName() == model.DatabaseDriverSqlite {
+			selectStr += `,
+				COUNT(DISTINCT
+				(
+					CASE WHEN JSON_EXTRACT(Timezone, '$.useAutomaticTimezone') = 'true' AND LENGTH(JSON_EXTRACT(Timezone, '$.automaticTimezone')) > 0
+					THEN JSON_EXTRACT(Timezone, '$.automaticTimezone')
+					WHEN JSON_EXTRACT(Timezone, '$.useAutomaticTimezone') = 'false' AND LENGTH(JSON_EXTRACT(Timezone, '$.manualTimezone')) > 0
+					THEN JSON_EXTRACT(Timezone, '$.manualTimezone')
+					END
+				)) AS ChannelMemberTimezonesCount`
 		}
 	}
 
@@ -2463,12 +2473,18 @@ func (s SqlChannelStore) UpdateLastViewedAt(channelIds []string, userId string)
 		Set("MsgCount", msgCountQuery).
 		Set("MsgCountRoot", msgCountQueryRoot).
 		Set("LastViewedAt", lastViewedQuery).
-		Set("LastUpdateAt", sq.Expr("LastViewedAt")).
 		Where(sq.Eq{
 			"UserId":    userId,
 			"ChannelId": channelIds,
 		})
 
+	// Unlike MySQL, SQLite evaluates every assignment against the original row.
+	if s.DriverName() == model.DatabaseDriverSqlite {
+		updateQuery = updateQuery.Set("LastUpdateAt", lastViewedQuery)
+	} else {
+		updateQuery = updateQuery.Set("LastUpdateAt", sq.Expr("LastViewedAt"))
+	}
+
 	sql, args, err = updateQuery.ToSql()
 	if err != nil {
 		return nil, errors.Wrap(err, "UpdateLastViewedAt_Update_Tosql")
@@ -3224,12 +3240,10 @@ func (s SqlChannelStore) channelSearchQuery(opts *store.ChannelSearchOpts) sq.Se
 	likeClause, likeTerm := s.buildLIKEClause(opts.Term, "c.Name, c.DisplayName, c.Purpose")
 	if likeTerm != "" {
 		likeClause = strings.ReplaceAll(likeClause, ":LikeTerm", "?")
-		fulltextClause, fulltextTerm := s.buildFulltextClause(opts.Term, "c.Name, c.DisplayName, c.Purpose")
-		fulltextClause = strings.ReplaceAll(fulltextClause, ":FulltextTerm", "?")
 		query = query.Where(sq.Or{
 			sq.Expr(likeClause, likeTerm, likeTerm, likeTerm), // Keep the number of likeTerms same as the number
 			// of columns (c.Name, c.DisplayName, c.Purpose)
-			sq.Expr(fulltextClause, fulltextTerm),
+			s.buildFulltextClauseX(opts.Term, "c.Name", "c.DisplayName", "c.Purpose"),
 		})
 	}
 
@@ -3378,44 +3392,6 @@ func (s SqlChannelStore) buildLIKEClauseX(term string, searchColumns ...string)
 
 const spaceFulltextSearchChars = "<>+-()~:*\"!@"
 
-func (s SqlChannelStore) buildFulltextClause(term string, searchColumns string) (fulltextClause, fulltextTerm string) {
-	// Copy the terms as we will need to prepare them differently for each search type.
-	fulltextTerm = term
-
-	// These chars must be treated as spaces in the fulltext query.
-	fulltextTerm = strings.Map(func(r rune) rune {
-		if strings.ContainsRune(spaceFulltextSearchChars, r) {
-			return ' '
-		}
-		return r
-	}, fulltextTerm)
-
-	// Prepare the FULLTEXT portion of the query.
-	if s.DriverName() == model.DatabaseDriverPostgres {
-		fulltextTerm = strings.ReplaceAll(fulltextTerm, "|", "")
-
-		splitTerm := strings.Fields(fulltextTerm)
-		for i, t := range strings.Fields(fulltextTerm) {
-			splitTerm[i] = t + ":*"
-		}
-
-		fulltextTerm = strings.Join(splitTerm, " & ")
-
-		fulltextClause = fmt.Sprintf("((to_tsvector('english', %s)) @@ to_tsquery('english', :FulltextTerm))", convertMySQLFullTextColumnsToPostgres(searchColumns))
-	} else if s.DriverName() == model.DatabaseDriverMysql {
-		splitTerm := strings.Fields(fulltextTerm)
-		for i, t := range strings.Fields(fulltextTerm) {
-			splitTerm[i] = "+" + t + "*"
-		}
-
-		fulltextTerm = strings.Join(splitTerm, " ")
-
-		fulltextClause = fmt.Sprintf("MATCH(%s) AGAINST (:FulltextTerm IN BOOLEAN MODE)", searchColumns)
-	}
-
-	return
-}
-
 func (s SqlChannelStore) buildFulltextClauseX(term string, searchColumns ...string) sq.Sqlizer {
 	// Copy the terms as we will need to prepare them differently for each search type.
 	fulltextTerm := term
@@ -3445,6 +3421,17 @@ func (s SqlChannelStore) buildFulltextClauseX(term string, searchColumns ...stri
 		expr := fmt.Sprintf("((to_tsvector('english', %s)) @@ to_tsquery('english', ?))", strings.Join(searchColumns, " || ' ' || "))
 		return sq.Expr(expr, fulltextTerm)
 
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		// remove all pipes |
+		fulltextTerm = strings.ReplaceAll(fulltextTerm, "|", "")
+
+		// append * to each part to match the prefix of the words
+		splitTerm := strings.Fields(fulltextTerm)
+		for i, t := range splitTerm {
+			splitTerm[i] = t + "*"
+		}
+
+		return buildSqliteFulltextClause(strings.Join(splitTerm, " "), "", false, strings.Join(searchColumns, " || ' ' || "))
 	}
 
 	splitTerm := strings.Fields(fulltextTerm)
@@ -3524,6 +3511,9 @@ func (s SqlChannelStore) searchGroupChannelsQuery(userId, term string, isPostgre
 	}
 
 	baseLikeTerm = "GROUP_CONCAT(u.Username SEPARATOR ', ') LIKE ?"
+	if s.DriverName() == model.DatabaseDriverSqlite {
+		baseLikeTerm = "GROUP_CONCAT(u.Username, ', ') LIKE ? ESCAPE '\\'"
+	}
 
 	for _, term := range terms {
 		term = sanitizeSearchTerm(term, "\\")
@@ -4021,7 +4011,13 @@ func (s SqlChannelStore) GroupSyncedChannelCount() (int64, error) {
 
 // SetShared sets the Shared flag true/false
 func (s SqlChannelStore) SetShared(channelId string, shared bool) error {
-	squery, args, err := s.getQueryBuilder().
+	return s.setChannelSharedT(s.GetMasterX(), channelId, shared)
+}
+
+// setChannelSharedT sets the Shared flag of a channel through the given executor, so that
+// the shared channel store can set it within its own transactions.
+func (ss *SqlStore) setChannelSharedT(db sqlxExecutor, channelId string, shared bool) error {
+	squery, args, err := ss.getQueryBuilder().
 		Update("Channels").
 		Set("Shared", shared).
 		Where(sq.Eq{"Id": channelId}).
@@ -4030,7 +4026,7 @@ func (s SqlChannelStore) SetShared(channelId string, shared bool) error {
 		return errors.Wrap(err, "channel_set_shared_tosql")
 	}
 
-	result, err := s.GetMasterX().Exec(squery, args...)
+	result, err := db.Exec(squery, args...)
 	if err != nil {
 		return errors.Wrap(err, "failed to update `Shared` for Channels")
 	}
@@ -306,6 +306,14 @@ func (s SqlChannelStore) CreateSidebarCategory(userId, teamId string, newCategor
 					SidebarChannels.UserId = ?
 					AND SidebarChannels.ChannelId IN ` + placeHolder + `
 					AND SidebarCategories.TeamId = ?`
+		} else if s.DriverName() == model.DatabaseDriverSqlite {
+			deleteQuery = `
+				DELETE FROM
+					SidebarChannels
+				WHERE
+					UserId = ?
+					AND ChannelId IN ` + placeHolder + `
+					AND CategoryId IN (SELECT Id FROM SidebarCategories WHERE TeamId = ?)`
 		} else {
 			deleteQuery = `
 				DELETE FROM
@@ -830,6 +838,14 @@ func (s SqlChannelStore) removeSidebarEntriesForPreferenceT(transaction *sqlxTxW
 				SidebarChannels.UserId = ?
 				AND SidebarChannels.ChannelId = ?
 				AND SidebarCategories.Type = ?`
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		query = `
+			DELETE FROM
+				SidebarChannels
+			WHERE
+				UserId = ?
+				AND ChannelId = ?
+				AND CategoryId IN (SELECT Id FROM SidebarCategories WHERE Type = ?)`
 	} else {
 		query = `
 			DELETE FROM
@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+type SqlDraftStore struct {
+	*SqlStore
+}
+
+func newSqlDraftStore(sqlStore *SqlStore) store.DraftStore {
+	return &SqlDraftStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlDraftStore) draftsQuery() sq.SelectBuilder {
+	return s.getQueryBuilder().
+		Select("Drafts.CreateAt", "Drafts.UpdateAt", "Drafts.UserId", "Drafts.ChannelId", "Drafts.RootId", "Drafts.Message", "Drafts.Props", "Drafts.FileIds").
+		From("Drafts")
+}
+
+func (s *SqlDraftStore) Upsert(draft *model.Draft) (*model.Draft, error) {
+	draft.PreSave()
+	if err := draft.IsValid(model.PostMessageMaxRunesV2); err != nil {
+		return nil, err
+	}
+
+	query := s.getQueryBuilder().
+		Insert("Drafts").
+		Columns("CreateAt", "UpdateAt", "UserId", "ChannelId", "RootId", "Message", "Props", "FileIds").
+		Values(draft.CreateAt, draft.UpdateAt, draft.UserId, draft.ChannelId, draft.RootId, draft.Message, draft.Props, draft.FileIds)
+
+	// The creation time of an existing draft is kept.
+	if s.DriverName() == model.DatabaseDriverMysql {
+		query = query.SuffixExpr(sq.Expr("ON DUPLICATE KEY UPDATE UpdateAt = ?, Message = ?, Props = ?, FileIds = ?", draft.UpdateAt, draft.Message, draft.Props, draft.FileIds))
+	} else {
+		query = query.SuffixExpr(sq.Expr("ON CONFLICT (UserId, ChannelId, RootId) DO UPDATE SET UpdateAt = ?, Message = ?, Props = ?, FileIds = ?", draft.UpdateAt, draft.Message, draft.Props, draft.FileIds))
+	}
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "draft_upsert_tosql")
+	}
+	if _, err := s.GetMasterX().Exec(queryString, args...); err != nil {
+		return nil, errors.Wrap(err, "failed to upsert Draft")
+	}
+
+	queryString, args, err = s.draftsQuery().
+		Where(sq.Eq{"UserId": draft.UserId, "ChannelId": draft.ChannelId, "RootId": draft.RootId}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "draft_tosql")
+	}
+	var saved model.Draft
+	if err := s.GetMasterX().Get(&saved, queryString, args...); err != nil {
+		return nil, errors.Wrap(err, "failed to get the upserted Draft")
+	}
+	return &saved, nil
+}
+
+func (s *SqlDraftStore) Get(userID, channelID, rootID string) (*model.Draft, error) {
+	query, args, err := s.draftsQuery().
+		Where(sq.Eq{"UserId": userID, "ChannelId": channelID, "RootId": rootID}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "draft_get_tosql")
+	}
+
+	var draft model.Draft
+	if err := s.GetReplicaX().Get(&draft, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("Draft", channelID)
+		}
+		return nil, errors.Wrapf(err, "failed to get Draft with channelId=%s", channelID)
+	}
+	return &draft, nil
+}
+
+func (s *SqlDraftStore) GetDraftsForUser(userID, teamID string) ([]*model.Draft, error) {
+	query, args, err := s.draftsQuery().
+		InnerJoin("Channels ON Channels.Id = Drafts.ChannelId").
+		Where(sq.Eq{"Drafts.UserId": userID, "Channels.DeleteAt": 0}).
+		Where(sq.Or{sq.Eq{"Channels.TeamId": teamID}, sq.Eq{"Channels.TeamId": ""}}).
+		OrderBy("Drafts.UpdateAt DESC").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "draft_getforuser_tosql")
+	}
+
+	drafts := []*model.Draft{}
+	if err := s.GetReplicaX().Select(&drafts, query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to find Drafts with userId=%s", userID)
+	}
+	return drafts, nil
+}
+
+func (s *SqlDraftStore) Delete(userID, channelID, rootID string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("Drafts").
+		Where(sq.Eq{"UserId": userID, "ChannelId": channelID, "RootId": rootID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "draft_delete_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete Draft with channelId=%s", channelID)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return store.NewErrNotFound("Draft", channelID)
+	}
+	return nil
+}
+
+func (s *SqlDraftStore) PermanentDeleteByUser(userID string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("Drafts").
+		Where(sq.Eq{"UserId": userID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "draft_permanentdeletebyuser_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return errors.Wrapf(err, "failed to delete Drafts with userId=%s", userID)
+	}
+	return nil
+}
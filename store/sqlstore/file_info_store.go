@@ -460,6 +460,8 @@ func (fs SqlFileInfoStore) PermanentDeleteBatch(endTime int64, limit int64) (int
 	var query string
 	if fs.DriverName() == "postgres" {
 		query = "DELETE from FileInfo WHERE Id = any (array (SELECT Id FROM FileInfo WHERE CreateAt < ? LIMIT ?))"
+	} else if fs.DriverName() == model.DatabaseDriverSqlite {
+		query = "DELETE from FileInfo WHERE Id IN (SELECT Id FROM FileInfo WHERE CreateAt < ? LIMIT ?)"
 	} else {
 		query = "DELETE from FileInfo WHERE CreateAt < ? LIMIT ?"
 	}
@@ -477,6 +479,54 @@ func (fs SqlFileInfoStore) PermanentDeleteBatch(endTime int64, limit int64) (int
 	return rowsAffected, nil
 }
 
+// GetExpiredBatch returns up to limit FileInfos, deleted or not, created before endTime,
+// oldest first. The batch is read from the master since its files are deleted right
+// away, and a lagging replica would return them again.
+func (fs SqlFileInfoStore) GetExpiredBatch(endTime int64, limit int) ([]*model.FileInfo, error) {
+	query := fs.getQueryBuilder().
+		Select(fs.queryFields...).
+		From("FileInfo").
+		Where(sq.Lt{"FileInfo.CreateAt": endTime}).
+		OrderBy("FileInfo.CreateAt ASC").
+		Limit(uint64(limit))
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "file_info_tosql")
+	}
+
+	infos := []*model.FileInfo{}
+	if err := fs.GetMasterX().Select(&infos, queryString, args...); err != nil {
+		return nil, errors.Wrap(err, "failed to find expired FileInfos")
+	}
+	return infos, nil
+}
+
+// GetOrphanedBatch returns up to limit FileInfos that were attached to a post which
+// no longer exists, such as one removed by a data retention policy. Like
+// GetExpiredBatch, it reads from the master.
+func (fs SqlFileInfoStore) GetOrphanedBatch(limit int) ([]*model.FileInfo, error) {
+	query := fs.getQueryBuilder().
+		Select(fs.queryFields...).
+		From("FileInfo").
+		LeftJoin("Posts ON FileInfo.PostId = Posts.Id").
+		Where(sq.NotEq{"FileInfo.PostId": ""}).
+		Where(sq.Eq{"Posts.Id": nil}).
+		OrderBy("FileInfo.CreateAt ASC").
+		Limit(uint64(limit))
+
+	queryString, args, err := query.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "file_info_tosql")
+	}
+
+	infos := []*model.FileInfo{}
+	if err := fs.GetMasterX().Select(&infos, queryString, args...); err != nil {
+		return nil, errors.Wrap(err, "failed to find orphaned FileInfos")
+	}
+	return infos, nil
+}
+
 func (fs SqlFileInfoStore) PermanentDeleteByUser(userId string) (int64, error) {
 	query := "DELETE from FileInfo WHERE CreatorId = ?"
 
@@ -643,6 +693,8 @@ func (fs SqlFileInfoStore) Search(paramsList []*model.SearchParams, userId, team
 				sq.Expr("MATCH (FileInfo.Name) AGAINST (? IN BOOLEAN MODE)", queryTerms),
 				sq.Expr("MATCH (FileInfo.Content) AGAINST (? IN BOOLEAN MODE)", queryTerms),
 			})
+		} else if fs.DriverName() == model.DatabaseDriverSqlite {
+			query = query.Where(buildSqliteFulltextClause(terms, excludedTerms, params.OrTerms, "FileInfo.Name", "FileInfo.Content"))
 		}
 	}
 
@@ -1241,11 +1241,13 @@ func (s *SqlGroupStore) groupsBySyncableBaseQuery(st model.GroupSyncableType, t
 
 	if opts.Q != "" {
 		pattern := fmt.Sprintf("%%%s%%", sanitizeSearchTerm(opts.Q, "\\"))
-		operatorKeyword := "ILIKE"
+		operatorKeyword, escapeClause := "ILIKE", ""
 		if s.DriverName() == model.DatabaseDriverMysql {
 			operatorKeyword = "LIKE"
+		} else if s.DriverName() == model.DatabaseDriverSqlite {
+			operatorKeyword, escapeClause = "LIKE", " ESCAPE '\\'"
 		}
-		query = query.Where(fmt.Sprintf("(ug.Name %[1]s ? OR ug.DisplayName %[1]s ?)", operatorKeyword), pattern, pattern)
+		query = query.Where(fmt.Sprintf("(ug.Name %[1]s ?%[2]s OR ug.DisplayName %[1]s ?%[2]s)", operatorKeyword, escapeClause), pattern, pattern)
 	}
 
 	return query
@@ -1306,11 +1308,13 @@ func (s *SqlGroupStore) getGroupsAssociatedToChannelsByTeam(teamID string, opts
 
 	if opts.Q != "" {
 		pattern := fmt.Sprintf("%%%s%%", sanitizeSearchTerm(opts.Q, "\\"))
-		operatorKeyword := "ILIKE"
+		operatorKeyword, escapeClause := "ILIKE", ""
 		if s.DriverName() == model.DatabaseDriverMysql {
 			operatorKeyword = "LIKE"
+		} else if s.DriverName() == model.DatabaseDriverSqlite {
+			operatorKeyword, escapeClause = "LIKE", " ESCAPE '\\'"
 		}
-		query = query.Where(fmt.Sprintf("(ug.Name %[1]s ? OR ug.DisplayName %[1]s ?)", operatorKeyword), pattern, pattern)
+		query = query.Where(fmt.Sprintf("(ug.Name %[1]s ?%[2]s OR ug.DisplayName %[1]s ?%[2]s)", operatorKeyword, escapeClause), pattern, pattern)
 	}
 
 	return query
@@ -1431,11 +1435,13 @@ func (s *SqlGroupStore) GetGroups(page, perPage int, opts model.GroupSearchOpts)
 
 	if opts.Q != "" {
 		pattern := fmt.Sprintf("%%%s%%", sanitizeSearchTerm(opts.Q, "\\"))
-		operatorKeyword := "ILIKE"
+		operatorKeyword, escapeClause := "ILIKE", ""
 		if s.DriverName() == model.DatabaseDriverMysql {
 			operatorKeyword = "LIKE"
+		} else if s.DriverName() == model.DatabaseDriverSqlite {
+			operatorKeyword, escapeClause = "LIKE", " ESCAPE '\\'"
 		}
-		groupsQuery = groupsQuery.Where(fmt.Sprintf("(g.Name %[1]s ? OR g.DisplayName %[1]s ?)", operatorKeyword), pattern, pattern)
+		groupsQuery = groupsQuery.Where(fmt.Sprintf("(g.Name %[1]s ?%[2]s OR g.DisplayName %[1]s ?%[2]s)", operatorKeyword, escapeClause), pattern, pattern)
 	}
 
 	if len(opts.NotAssociatedToTeam) == 26 {
@@ -1525,7 +1531,7 @@ func (s *SqlGroupStore) teamMembersMinusGroupMembersQuery(teamID string, groupID
 		selectStr = "count(DISTINCT Users.Id)"
 	} else {
 		tmpl := "Users.*, coalesce(TeamMembers.SchemeGuest, false) SchemeGuest, TeamMembers.SchemeAdmin, TeamMembers.SchemeUser, %s AS GroupIDs"
-		if s.DriverName() == model.DatabaseDriverMysql {
+		if s.DriverName() == model.DatabaseDriverMysql || s.DriverName() == model.DatabaseDriverSqlite {
 			selectStr = fmt.Sprintf(tmpl, "group_concat(UserGroups.Id)")
 		} else {
 			selectStr = fmt.Sprintf(tmpl, "string_agg(UserGroups.Id, ',')")
@@ -1603,7 +1609,7 @@ func (s *SqlGroupStore) channelMembersMinusGroupMembersQuery(channelID string, g
 		selectStr = "count(DISTINCT Users.Id)"
 	} else {
 		tmpl := "Users.*, coalesce(ChannelMembers.SchemeGuest, false) SchemeGuest, ChannelMembers.SchemeAdmin, ChannelMembers.SchemeUser, %s AS GroupIDs"
-		if s.DriverName() == model.DatabaseDriverMysql {
+		if s.DriverName() == model.DatabaseDriverMysql || s.DriverName() == model.DatabaseDriverSqlite {
 			selectStr = fmt.Sprintf(tmpl, "group_concat(UserGroups.Id)")
 		} else {
 			selectStr = fmt.Sprintf(tmpl, "string_agg(UserGroups.Id, ',')")
@@ -1810,7 +1816,7 @@ func (s *SqlGroupStore) buildUpsertMembersQuery(groupID string, userIDs []string
 
 	if s.DriverName() == model.DatabaseDriverMysql {
 		builder = builder.SuffixExpr(sq.Expr("ON DUPLICATE KEY UPDATE CreateAt = ?, DeleteAt = ?", createAt, 0))
-	} else if s.DriverName() == model.DatabaseDriverPostgres {
+	} else if s.DriverName() == model.DatabaseDriverPostgres || s.DriverName() == model.DatabaseDriverSqlite {
 		builder = builder.SuffixExpr(sq.Expr("ON CONFLICT (groupid, userid) DO UPDATE SET CreateAt = ?, DeleteAt = ?", createAt, 0))
 	}
 
@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+type SqlMfaRecoveryCodeStore struct {
+	*SqlStore
+}
+
+func newSqlMfaRecoveryCodeStore(sqlStore *SqlStore) store.MfaRecoveryCodeStore {
+	return &SqlMfaRecoveryCodeStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlMfaRecoveryCodeStore) Replace(userID string, codes []*model.MfaRecoveryCode) error {
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction)
+
+	query, args, err := s.getQueryBuilder().
+		Delete("MfaRecoveryCodes").
+		Where(sq.Eq{"UserId": userID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "mfa_recovery_code_replace_delete_tosql")
+	}
+	if _, err := transaction.Exec(query, args...); err != nil {
+		return errors.Wrapf(err, "failed to delete MfaRecoveryCodes with userId=%s", userID)
+	}
+
+	if len(codes) > 0 {
+		insert := s.getQueryBuilder().
+			Insert("MfaRecoveryCodes").
+			Columns("Id", "UserId", "CodeHash", "CreateAt", "UsedAt")
+		for _, code := range codes {
+			code.UserId = userID
+			code.PreSave()
+			if err := code.IsValid(); err != nil {
+				return err
+			}
+			insert = insert.Values(code.Id, code.UserId, code.CodeHash, code.CreateAt, code.UsedAt)
+		}
+
+		query, args, err = insert.ToSql()
+		if err != nil {
+			return errors.Wrap(err, "mfa_recovery_code_replace_insert_tosql")
+		}
+		if _, err := transaction.Exec(query, args...); err != nil {
+			return errors.Wrapf(err, "failed to save MfaRecoveryCodes with userId=%s", userID)
+		}
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return errors.Wrap(err, "commit_transaction")
+	}
+	return nil
+}
+
+func (s *SqlMfaRecoveryCodeStore) Use(userID, codeHash string, usedAt int64) error {
+	// The code is only marked as used when it was not already, so that it can't be used by
+	// two concurrent logins.
+	query, args, err := s.getQueryBuilder().
+		Update("MfaRecoveryCodes").
+		Set("UsedAt", usedAt).
+		Where(sq.Eq{"UserId": userID, "CodeHash": codeHash, "UsedAt": 0}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "mfa_recovery_code_use_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update MfaRecoveryCode with userId=%s", userID)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "unable to get rows affected")
+	}
+	if rows == 0 {
+		return store.NewErrNotFound("MfaRecoveryCode", "userId="+userID)
+	}
+	return nil
+}
+
+func (s *SqlMfaRecoveryCodeStore) CountUnusedForUser(userID string) (int64, error) {
+	query, args, err := s.getQueryBuilder().
+		Select("COUNT(*)").
+		From("MfaRecoveryCodes").
+		Where(sq.Eq{"UserId": userID, "UsedAt": 0}).
+		ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "mfa_recovery_code_countunusedforuser_tosql")
+	}
+
+	var count int64
+	if err := s.GetMasterX().Get(&count, query, args...); err != nil {
+		return 0, errors.Wrapf(err, "failed to count MfaRecoveryCodes with userId=%s", userID)
+	}
+	return count, nil
+}
+
+func (s *SqlMfaRecoveryCodeStore) PermanentDeleteByUser(userID string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("MfaRecoveryCodes").
+		Where(sq.Eq{"UserId": userID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "mfa_recovery_code_permanentdeletebyuser_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return errors.Wrapf(err, "failed to delete MfaRecoveryCodes with userId=%s", userID)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+type SqlPollVoteStore struct {
+	*SqlStore
+}
+
+func newSqlPollVoteStore(sqlStore *SqlStore) store.PollVoteStore {
+	return &SqlPollVoteStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlPollVoteStore) SetUserVotes(postID, userID string, votes []*model.PollVote) error {
+	insert := s.getQueryBuilder().
+		Insert("PollVotes").
+		Columns("PostId", "UserId", "OptionIndex", "CreateAt")
+	for _, vote := range votes {
+		if vote.PostId != postID || vote.UserId != userID {
+			return store.NewErrInvalidInput("PollVote", "post_id/user_id", vote.PostId+"/"+vote.UserId)
+		}
+		if err := vote.IsValid(); err != nil {
+			return err
+		}
+		insert = insert.Values(vote.PostId, vote.UserId, vote.OptionIndex, vote.CreateAt)
+	}
+
+	transaction, err := s.GetMasterX().Beginx()
+	if err != nil {
+		return errors.Wrap(err, "begin_transaction")
+	}
+	defer finalizeTransactionX(transaction)
+
+	query, args, err := s.getQueryBuilder().
+		Delete("PollVotes").
+		Where(sq.Eq{"PostId": postID, "UserId": userID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "poll_vote_setuservotes_delete_tosql")
+	}
+	if _, err := transaction.Exec(query, args...); err != nil {
+		return errors.Wrapf(err, "failed to delete PollVotes with postId=%s userId=%s", postID, userID)
+	}
+
+	if len(votes) > 0 {
+		query, args, err := insert.ToSql()
+		if err != nil {
+			return errors.Wrap(err, "poll_vote_setuservotes_insert_tosql")
+		}
+		if _, err := transaction.Exec(query, args...); err != nil {
+			return errors.Wrapf(err, "failed to save PollVotes with postId=%s userId=%s", postID, userID)
+		}
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return errors.Wrap(err, "commit_transaction")
+	}
+	return nil
+}
+
+func (s *SqlPollVoteStore) GetForPost(postID string) ([]*model.PollVote, error) {
+	query, args, err := s.getQueryBuilder().
+		Select("PostId", "UserId", "OptionIndex", "CreateAt").
+		From("PollVotes").
+		Where(sq.Eq{"PostId": postID}).
+		OrderBy("CreateAt", "UserId", "OptionIndex").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "poll_vote_getforpost_tosql")
+	}
+
+	// The votes are read from the master, as the tallies are sent right after a vote.
+	votes := []*model.PollVote{}
+	if err := s.GetMasterX().Select(&votes, query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to find PollVotes with postId=%s", postID)
+	}
+	return votes, nil
+}
+
+func (s *SqlPollVoteStore) PermanentDeleteByUser(userID string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("PollVotes").
+		Where(sq.Eq{"UserId": userID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "poll_vote_permanentdeletebyuser_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return errors.Wrapf(err, "failed to delete PollVotes with userId=%s", userID)
+	}
+	return nil
+}
+
+func (s *SqlPollVoteStore) DeleteOrphanedRows(limit int) (deleted int64, err error) {
+	// We need the extra level of nesting to deal with MySQL's locking
+	const query = `
+	DELETE FROM PollVotes WHERE PostId IN (
+		SELECT * FROM (
+			SELECT PollVotes.PostId FROM PollVotes
+			LEFT JOIN Posts ON PollVotes.PostId = Posts.Id
+			WHERE Posts.Id IS NULL
+			LIMIT ?
+		) AS A
+	)`
+	result, err := s.GetMasterX().Exec(query, limit)
+	if err != nil {
+		return
+	}
+	deleted, err = result.RowsAffected()
+	return
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+type SqlPostRevisionStore struct {
+	*SqlStore
+}
+
+func newSqlPostRevisionStore(sqlStore *SqlStore) store.PostRevisionStore {
+	return &SqlPostRevisionStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlPostRevisionStore) Save(revision *model.PostRevision) (*model.PostRevision, error) {
+	if revision.Id != "" {
+		return nil, store.NewErrInvalidInput("PostRevision", "id", revision.Id)
+	}
+
+	revision.PreSave()
+	if err := revision.IsValid(model.PostMessageMaxRunesV2); err != nil {
+		return nil, err
+	}
+
+	query, args, err := s.getQueryBuilder().
+		Insert("PostRevisions").
+		Columns("Id", "PostId", "ChannelId", "UserId", "EditorId", "CreateAt", "Message", "Props", "FileIds").
+		Values(revision.Id, revision.PostId, revision.ChannelId, revision.UserId, revision.EditorId, revision.CreateAt, revision.Message, revision.Props, revision.FileIds).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "post_revision_save_tosql")
+	}
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to save PostRevision with postId=%s", revision.PostId)
+	}
+	return revision, nil
+}
+
+func (s *SqlPostRevisionStore) GetForPost(postID string) ([]*model.PostRevision, error) {
+	query, args, err := s.getQueryBuilder().
+		Select("Id", "PostId", "ChannelId", "UserId", "EditorId", "CreateAt", "Message", "Props", "FileIds").
+		From("PostRevisions").
+		Where(sq.Eq{"PostId": postID}).
+		OrderBy("CreateAt DESC", "Id DESC").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "post_revision_getforpost_tosql")
+	}
+
+	revisions := []*model.PostRevision{}
+	if err := s.GetReplicaX().Select(&revisions, query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to find PostRevisions with postId=%s", postID)
+	}
+	return revisions, nil
+}
+
+func (s *SqlPostRevisionStore) PermanentDelete(id string) error {
+	return s.permanentDelete(sq.Eq{"Id": id})
+}
+
+func (s *SqlPostRevisionStore) PermanentDeleteByChannel(channelID string) error {
+	return s.permanentDelete(sq.Eq{"ChannelId": channelID})
+}
+
+func (s *SqlPostRevisionStore) PermanentDeleteByUser(userID string) error {
+	return s.permanentDelete(sq.Eq{"UserId": userID})
+}
+
+func (s *SqlPostRevisionStore) permanentDelete(where sq.Eq) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("PostRevisions").
+		Where(where).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "post_revision_permanentdelete_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return errors.Wrap(err, "failed to delete PostRevisions")
+	}
+	return nil
+}
+
+// PermanentDeleteBatchForRetentionPolicies deletes a batch of records which are affected by
+// the global or a granular retention policy.
+// See `genericPermanentDeleteBatchForRetentionPolicies` for details.
+func (s *SqlPostRevisionStore) PermanentDeleteBatchForRetentionPolicies(now, globalPolicyEndTime, limit int64, cursor model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error) {
+	builder := s.getQueryBuilder().
+		Select("PostRevisions.Id").
+		From("PostRevisions")
+	return genericPermanentDeleteBatchForRetentionPolicies(RetentionPolicyBatchDeletionInfo{
+		BaseBuilder:         builder,
+		Table:               "PostRevisions",
+		TimeColumn:          "CreateAt",
+		PrimaryKeys:         []string{"Id"},
+		ChannelIDTable:      "PostRevisions",
+		NowMillis:           now,
+		GlobalPolicyEndTime: globalPolicyEndTime,
+		Limit:               limit,
+	}, s.SqlStore, cursor)
+}
+
+func (s *SqlPostRevisionStore) DeleteOrphanedRows(limit int) (deleted int64, err error) {
+	// We need the extra level of nesting to deal with MySQL's locking
+	const query = `
+	DELETE FROM PostRevisions WHERE Id IN (
+		SELECT * FROM (
+			SELECT PostRevisions.Id FROM PostRevisions
+			LEFT JOIN Posts ON PostRevisions.PostId = Posts.Id
+			WHERE Posts.Id IS NULL
+			LIMIT ?
+		) AS A
+	)`
+	result, err := s.GetMasterX().Exec(query, limit)
+	if err != nil {
+		return
+	}
+	deleted, err = result.RowsAffected()
+	return
+}
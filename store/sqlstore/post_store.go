@@ -733,6 +733,12 @@ func (s *SqlPostStore) Delete(postID string, time int64, deleteByID string) erro
 				UpdateAt = $1,
 				Props = jsonb_set(Props, $2, $3)
 			WHERE Id = $4 OR RootId = $4`, time, jsonKeyPath(model.PostPropsDeleteBy), jsonStringVal(deleteByID), postID)
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		_, err = transaction.Exec(`UPDATE Posts
+			SET DeleteAt = ?,
+			UpdateAt = ?,
+			Props = JSON_SET(Props, ?, ?)
+			Where Id = ? OR RootId = ?`, time, time, "$."+model.PostPropsDeleteBy, deleteByID, postID, postID)
 	} else {
 		// We use ORDER BY clause for MySQL
 		// to trigger filesort optimization in the index_merge.
@@ -1145,6 +1151,20 @@ func (s *SqlPostStore) GetPostsSince(options model.GetPostsSinceOptions, allowFr
 		(SELECT *` + replyCountQuery1 + ` FROM Posts p1 WHERE id in (SELECT rootid FROM cte))
 		ORDER BY CreateAt ` + order
 
+		params = []interface{}{options.Time, options.ChannelId}
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		query = `WITH cte AS (SELECT
+		       *
+		FROM
+		       Posts
+		WHERE
+		       UpdateAt > ? AND ChannelId = ?
+		       LIMIT 1000)
+		SELECT *` + replyCountQuery2 + ` FROM cte
+		UNION
+		SELECT *` + replyCountQuery1 + ` FROM Posts p1 WHERE Id IN (SELECT RootId FROM cte)
+		ORDER BY CreateAt ` + order
+
 		params = []interface{}{options.Time, options.ChannelId}
 	}
 	err := s.GetReplicaX().Select(&posts, query, params...)
@@ -1798,6 +1818,8 @@ func (s *SqlPostStore) search(teamId string, userId string, params *model.Search
 
 		searchClause := fmt.Sprintf("MATCH (%s) AGAINST (? IN BOOLEAN MODE)", searchType)
 		baseQuery = baseQuery.Where(searchClause, termsClause)
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		baseQuery = baseQuery.Where(buildSqliteFulltextClause(terms, excludedTerms, params.OrTerms, searchType))
 	}
 
 	inQuery := s.getSubQueryBuilder().Select("Id").
@@ -2155,6 +2177,8 @@ func (s *SqlPostStore) PermanentDeleteBatch(endTime int64, limit int64) (int64,
 	var query string
 	if s.DriverName() == "postgres" {
 		query = "DELETE from Posts WHERE Id = any (array (SELECT Id FROM Posts WHERE CreateAt < ? LIMIT ?))"
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		query = "DELETE from Posts WHERE Id IN (SELECT Id FROM Posts WHERE CreateAt < ? LIMIT ?)"
 	} else {
 		query = "DELETE from Posts WHERE CreateAt < ? LIMIT ?"
 	}
@@ -2202,6 +2226,10 @@ func (s *SqlPostStore) determineMaxPostSize() int {
 		`); err != nil {
 			mlog.Warn("Unable to determine the maximum supported post size", mlog.Err(err))
 		}
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		// The Post.Message column in SQLite is TEXT, which has no limit, so the size
+		// of a TEXT column in MySQL is used instead.
+		maxPostSizeBytes = model.PostMessageMaxBytesV2
 	} else if s.DriverName() == model.DatabaseDriverMysql {
 		// The Post.Message column in MySQL has historically been TEXT, with a maximum
 		// limit of 65535.
@@ -2245,21 +2273,32 @@ func (s *SqlPostStore) GetMaxPostSize() int {
 	return s.maxPostSizeCached
 }
 
-func (s *SqlPostStore) GetParentsForExportAfter(limit int, afterId string) ([]*model.PostForExport, error) {
+func (s *SqlPostStore) GetParentsForExportAfter(limit int, afterId string, channelIds []string, since int64) ([]*model.PostForExport, error) {
 	for {
+		rootQuery := s.getQueryBuilder().
+			Select("Id").
+			From("Posts").
+			Where(sq.And{
+				sq.Gt{"Id": afterId},
+				sq.Eq{"RootId": ""},
+				sq.Eq{"DeleteAt": 0},
+			}).
+			OrderBy("Id").
+			Limit(uint64(limit))
+		if channelIds != nil {
+			rootQuery = rootQuery.Where(sq.Eq{"ChannelId": channelIds})
+		}
+		if since > 0 {
+			rootQuery = rootQuery.Where(exportSinceClause("Posts", since))
+		}
+
+		query, args, err := rootQuery.ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "rootIds_toSql")
+		}
+
 		rootIds := []string{}
-		err := s.GetReplicaX().Select(&rootIds,
-			`SELECT
-				Id
-			FROM
-				Posts
-			WHERE
-				Id > ?
-				AND RootId = ''
-				AND DeleteAt = 0
-			ORDER BY Id
-			LIMIT ?`,
-			afterId, limit)
+		err = s.GetReplicaX().Select(&rootIds, query, args...)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to find Posts")
 		}
@@ -2281,7 +2320,7 @@ func (s *SqlPostStore) GetParentsForExportAfter(limit int, afterId string) ([]*m
 			}).
 			OrderBy("p1.Id")
 
-		query, args, err := builder.ToSql()
+		query, args, err = builder.ToSql()
 		if err != nil {
 			return nil, errors.Wrap(err, "postsForExport_toSql")
 		}
@@ -2324,7 +2363,16 @@ func (s *SqlPostStore) GetRepliesForExport(rootId string) ([]*model.ReplyForExpo
 	return posts, nil
 }
 
-func (s *SqlPostStore) GetDirectPostParentsForExportAfter(limit int, afterId string) ([]*model.DirectPostForExport, error) {
+// exportSinceClause matches the root posts created or edited at or after since, or having
+// a reply which was.
+func exportSinceClause(table string, since int64) sq.Sqlizer {
+	return sq.Or{
+		sq.GtOrEq{table + ".UpdateAt": since},
+		sq.Expr("EXISTS (SELECT 1 FROM Posts r WHERE r.RootId = "+table+".Id AND r.UpdateAt >= ?)", since),
+	}
+}
+
+func (s *SqlPostStore) GetDirectPostParentsForExportAfter(limit int, afterId string, since int64) ([]*model.DirectPostForExport, error) {
 	query := s.getQueryBuilder().
 		Select("p.*", "Users.Username as User").
 		From("Posts p").
@@ -2340,6 +2388,9 @@ func (s *SqlPostStore) GetDirectPostParentsForExportAfter(limit int, afterId str
 		}).
 		OrderBy("p.Id").
 		Limit(uint64(limit))
+	if since > 0 {
+		query = query.Where(exportSinceClause("p", since))
+	}
 
 	queryString, args, err := query.ToSql()
 	if err != nil {
@@ -2507,6 +2558,11 @@ func (s *SqlPostStore) cleanupThreadComments(transaction *sqlxTxWrapper, postId,
 		if count == 0 {
 			if s.DriverName() == model.DatabaseDriverPostgres {
 				updateQuery = updateQuery.Set("Participants", sq.Expr("Participants - ?", userId))
+			} else if s.DriverName() == model.DatabaseDriverSqlite {
+				updateQuery = updateQuery.
+					Set("Participants", sq.Expr(
+						`(SELECT JSON_GROUP_ARRAY(value) FROM JSON_EACH(Participants) WHERE value != ?)`, userId,
+					))
 			} else {
 				updateQuery = updateQuery.
 					Set("Participants", sq.Expr(
@@ -72,7 +72,7 @@ func (s SqlPreferenceStore) save(transaction *sqlxTxWrapper, preference *model.P
 
 	if s.DriverName() == model.DatabaseDriverMysql {
 		query = query.SuffixExpr(sq.Expr("ON DUPLICATE KEY UPDATE Value = ?", preference.Value))
-	} else if s.DriverName() == model.DatabaseDriverPostgres {
+	} else if s.DriverName() == model.DatabaseDriverPostgres || s.DriverName() == model.DatabaseDriverSqlite {
 		query = query.SuffixExpr(sq.Expr("ON CONFLICT (userid, category, name) DO UPDATE SET Value = ?", preference.Value))
 	} else {
 		return store.NewErrNotImplemented("failed to update preference because of missing driver")
@@ -103,7 +103,7 @@ func (s SqlPreferenceStore) saveTx(transaction *sqlxTxWrapper, preference *model
 
 	if s.DriverName() == model.DatabaseDriverMysql {
 		query = query.SuffixExpr(sq.Expr("ON DUPLICATE KEY UPDATE Value = ?", preference.Value))
-	} else if s.DriverName() == model.DatabaseDriverPostgres {
+	} else if s.DriverName() == model.DatabaseDriverPostgres || s.DriverName() == model.DatabaseDriverSqlite {
 		query = query.SuffixExpr(sq.Expr("ON CONFLICT (userid, category, name) DO UPDATE SET Value = ?", preference.Value))
 	} else {
 		return store.NewErrNotImplemented("failed to update preference because of missing driver")
@@ -212,6 +212,8 @@ func (s *SqlReactionStore) PermanentDeleteBatch(endTime int64, limit int64) (int
 	var query string
 	if s.DriverName() == "postgres" {
 		query = "DELETE from Reactions WHERE CreateAt = any (array (SELECT CreateAt FROM Reactions WHERE CreateAt < ? LIMIT ?))"
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		query = "DELETE from Reactions WHERE CreateAt IN (SELECT CreateAt FROM Reactions WHERE CreateAt < ? LIMIT ?)"
 	} else {
 		query = "DELETE from Reactions WHERE CreateAt < ? LIMIT ?"
 	}
@@ -242,7 +244,7 @@ func (s *SqlReactionStore) saveReactionAndUpdatePost(transaction *sqlxTxWrapper,
 				UpdateAt = :UpdateAt, DeleteAt = :DeleteAt, RemoteId = :RemoteId`, reaction); err != nil {
 			return err
 		}
-	} else if s.DriverName() == model.DatabaseDriverPostgres {
+	} else if s.DriverName() == model.DatabaseDriverPostgres || s.DriverName() == model.DatabaseDriverSqlite {
 		if _, err := transaction.NamedExec(
 			`INSERT INTO
 				Reactions
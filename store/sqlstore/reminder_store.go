@@ -0,0 +1,173 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+type SqlReminderStore struct {
+	*SqlStore
+}
+
+func newSqlReminderStore(sqlStore *SqlStore) store.ReminderStore {
+	return &SqlReminderStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlReminderStore) remindersQuery() sq.SelectBuilder {
+	return s.getQueryBuilder().
+		Select("Id", "UserId", "PostId", "Message", "CreateAt", "TargetAt", "FiredAt").
+		From("Reminders")
+}
+
+func (s *SqlReminderStore) Save(reminder *model.Reminder) (*model.Reminder, error) {
+	if reminder.Id != "" {
+		return nil, store.NewErrInvalidInput("Reminder", "id", reminder.Id)
+	}
+
+	reminder.PreSave()
+	if err := reminder.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query, args, err := s.getQueryBuilder().
+		Insert("Reminders").
+		Columns("Id", "UserId", "PostId", "Message", "CreateAt", "TargetAt", "FiredAt").
+		Values(reminder.Id, reminder.UserId, reminder.PostId, reminder.Message, reminder.CreateAt, reminder.TargetAt, reminder.FiredAt).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "reminder_save_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to save Reminder with id=%s", reminder.Id)
+	}
+	return reminder, nil
+}
+
+func (s *SqlReminderStore) Get(id string) (*model.Reminder, error) {
+	query, args, err := s.remindersQuery().
+		Where(sq.Eq{"Id": id}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "reminder_get_tosql")
+	}
+
+	var reminder model.Reminder
+	if err := s.GetReplicaX().Get(&reminder, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("Reminder", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get Reminder with id=%s", id)
+	}
+	return &reminder, nil
+}
+
+func (s *SqlReminderStore) GetForUser(userID string) ([]*model.Reminder, error) {
+	query, args, err := s.remindersQuery().
+		Where(sq.Eq{"UserId": userID}).
+		OrderBy("TargetAt", "Id").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "reminder_getforuser_tosql")
+	}
+
+	reminders := []*model.Reminder{}
+	if err := s.GetReplicaX().Select(&reminders, query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to find Reminders with userId=%s", userID)
+	}
+	return reminders, nil
+}
+
+func (s *SqlReminderStore) GetDueReminders(beforeTime, afterTime int64, afterID string, limit int) ([]*model.Reminder, error) {
+	query, args, err := s.remindersQuery().
+		Where(sq.Eq{"FiredAt": 0}).
+		Where(sq.LtOrEq{"TargetAt": beforeTime}).
+		Where(sq.Or{
+			sq.Gt{"TargetAt": afterTime},
+			sq.And{sq.Eq{"TargetAt": afterTime}, sq.Gt{"Id": afterID}},
+		}).
+		OrderBy("TargetAt", "Id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "reminder_getdue_tosql")
+	}
+
+	// The due reminders are read from the master, as the job firing them updates or
+	// deletes them between pages.
+	reminders := []*model.Reminder{}
+	if err := s.GetMasterX().Select(&reminders, query, args...); err != nil {
+		return nil, errors.Wrap(err, "failed to find due Reminders")
+	}
+	return reminders, nil
+}
+
+func (s *SqlReminderStore) Update(reminder *model.Reminder) error {
+	if err := reminder.IsValid(); err != nil {
+		return err
+	}
+
+	query, args, err := s.getQueryBuilder().
+		Update("Reminders").
+		Set("Message", reminder.Message).
+		Set("TargetAt", reminder.TargetAt).
+		Set("FiredAt", reminder.FiredAt).
+		Where(sq.Eq{"Id": reminder.Id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "reminder_update_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update Reminder with id=%s", reminder.Id)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return store.NewErrNotFound("Reminder", reminder.Id)
+	}
+	return nil
+}
+
+func (s *SqlReminderStore) Delete(id string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("Reminders").
+		Where(sq.Eq{"Id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "reminder_delete_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete Reminder with id=%s", id)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return store.NewErrNotFound("Reminder", id)
+	}
+	return nil
+}
+
+func (s *SqlReminderStore) PermanentDeleteByUser(userID string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("Reminders").
+		Where(sq.Eq{"UserId": userID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "reminder_permanentdeletebyuser_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return errors.Wrapf(err, "failed to delete Reminders with userId=%s", userID)
+	}
+	return nil
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/cjdelisle/matterfoss-server/v6/model"
 	"github.com/cjdelisle/matterfoss-server/v6/store"
 	"github.com/pkg/errors"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
 type SqlRetentionPolicyStore struct {
@@ -371,8 +373,8 @@ func (s *SqlRetentionPolicyStore) buildGetPoliciesQuery(id string, offset, limit
 		return "", nil, errors.Wrap(err, "retention_policies_tosql")
 	}
 
-	// MySQL does not support positional params, so we add one param for each WHERE clause.
-	if s.DriverName() == model.DatabaseDriverMysql {
+	// MySQL and SQLite do not use positional params, so we add one param for each WHERE clause.
+	if s.DriverName() != model.DatabaseDriverPostgres {
 		args = append(args, args...)
 	}
 
@@ -431,7 +433,7 @@ func (s *SqlRetentionPolicyStore) Delete(id string) error {
 	if err != nil {
 		return errors.Wrap(err, "unable to get rows affected")
 	} else if numRowsAffected == 0 {
-		return errors.New("policy not found")
+		return store.NewErrNotFound("RetentionPolicy", id)
 	}
 
 	return nil
@@ -516,6 +518,10 @@ func (s *SqlRetentionPolicyStore) AddChannels(policyId string, channelIds []stri
 			if dbErr.Number == MySQLForeignKeyViolationErrorCode {
 				return store.NewErrNotFound("RetentionPolicy", policyId)
 			}
+		case *sqlite.Error:
+			if dbErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY {
+				return store.NewErrNotFound("RetentionPolicy", policyId)
+			}
 		}
 	}
 
@@ -956,7 +962,7 @@ func genericRetentionPoliciesDeletion(
 	if err != nil {
 		return 0, errors.Wrap(err, r.Table+"_tosql")
 	}
-	if s.DriverName() == model.DatabaseDriverPostgres {
+	if s.DriverName() == model.DatabaseDriverPostgres || s.DriverName() == model.DatabaseDriverSqlite {
 		primaryKeysStr := "(" + strings.Join(r.PrimaryKeys, ",") + ")"
 		query = `
 		DELETE FROM ` + r.Table + ` WHERE ` + primaryKeysStr + ` IN (
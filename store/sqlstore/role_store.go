@@ -337,6 +337,12 @@ func (s *SqlRoleStore) channelHigherScopedPermissionsQuery(roleNames []string) s
 			OR Teams.SchemeId IS NULL)
 	`
 
+	// SQLite does not support RIGHT JOIN. Since the WHERE clauses only keep the rows matching a scheme, the
+	// right joins behave like inner joins there anyway.
+	if s.DriverName() == model.DatabaseDriverSqlite {
+		sqlTmpl = strings.ReplaceAll(sqlTmpl, "RIGHT JOIN", "JOIN")
+	}
+
 	// The below three channel role names are referenced by their name value because there is no system scheme
 	// record that ships with Matterfoss, otherwise the system scheme would be referenced by name and the channel
 	// roles would be referenced by their column names.
@@ -0,0 +1,183 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+type SqlScheduledPostStore struct {
+	*SqlStore
+}
+
+func newSqlScheduledPostStore(sqlStore *SqlStore) store.ScheduledPostStore {
+	return &SqlScheduledPostStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlScheduledPostStore) scheduledPostsQuery() sq.SelectBuilder {
+	return s.getQueryBuilder().
+		Select("ScheduledPosts.Id", "ScheduledPosts.CreateAt", "ScheduledPosts.UpdateAt", "ScheduledPosts.UserId", "ScheduledPosts.ChannelId",
+			"ScheduledPosts.RootId", "ScheduledPosts.Message", "ScheduledPosts.Props", "ScheduledPosts.FileIds", "ScheduledPosts.ScheduledAt",
+			"ScheduledPosts.ProcessedAt", "ScheduledPosts.ErrorCode").
+		From("ScheduledPosts")
+}
+
+func (s *SqlScheduledPostStore) Save(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error) {
+	if scheduledPost.Id != "" {
+		return nil, store.NewErrInvalidInput("ScheduledPost", "id", scheduledPost.Id)
+	}
+
+	scheduledPost.PreSave()
+	if err := scheduledPost.IsValid(model.PostMessageMaxRunesV2); err != nil {
+		return nil, err
+	}
+
+	query, args, err := s.getQueryBuilder().
+		Insert("ScheduledPosts").
+		Columns("Id", "CreateAt", "UpdateAt", "UserId", "ChannelId", "RootId", "Message", "Props", "FileIds", "ScheduledAt", "ProcessedAt", "ErrorCode").
+		Values(scheduledPost.Id, scheduledPost.CreateAt, scheduledPost.UpdateAt, scheduledPost.UserId, scheduledPost.ChannelId, scheduledPost.RootId,
+			scheduledPost.Message, scheduledPost.Props, scheduledPost.FileIds, scheduledPost.ScheduledAt, scheduledPost.ProcessedAt, scheduledPost.ErrorCode).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "scheduled_post_save_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to save ScheduledPost with id=%s", scheduledPost.Id)
+	}
+	return scheduledPost, nil
+}
+
+func (s *SqlScheduledPostStore) Get(id string) (*model.ScheduledPost, error) {
+	query, args, err := s.scheduledPostsQuery().
+		Where(sq.Eq{"Id": id}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "scheduled_post_get_tosql")
+	}
+
+	var scheduledPost model.ScheduledPost
+	if err := s.GetReplicaX().Get(&scheduledPost, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("ScheduledPost", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get ScheduledPost with id=%s", id)
+	}
+	return &scheduledPost, nil
+}
+
+func (s *SqlScheduledPostStore) GetForUser(userID, teamID string) ([]*model.ScheduledPost, error) {
+	query, args, err := s.scheduledPostsQuery().
+		InnerJoin("Channels ON Channels.Id = ScheduledPosts.ChannelId").
+		Where(sq.Eq{"ScheduledPosts.UserId": userID}).
+		Where(sq.Or{sq.Eq{"Channels.TeamId": teamID}, sq.Eq{"Channels.TeamId": ""}}).
+		OrderBy("ScheduledPosts.ScheduledAt", "ScheduledPosts.Id").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "scheduled_post_getforuser_tosql")
+	}
+
+	scheduledPosts := []*model.ScheduledPost{}
+	if err := s.GetReplicaX().Select(&scheduledPosts, query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to find ScheduledPosts with userId=%s", userID)
+	}
+	return scheduledPosts, nil
+}
+
+func (s *SqlScheduledPostStore) GetPendingScheduledPosts(beforeTime, afterTime int64, afterID string, limit int) ([]*model.ScheduledPost, error) {
+	query, args, err := s.scheduledPostsQuery().
+		Where(sq.Eq{"ProcessedAt": 0}).
+		Where(sq.LtOrEq{"ScheduledAt": beforeTime}).
+		Where(sq.Or{
+			sq.Gt{"ScheduledAt": afterTime},
+			sq.And{sq.Eq{"ScheduledAt": afterTime}, sq.Gt{"Id": afterID}},
+		}).
+		OrderBy("ScheduledAt", "Id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "scheduled_post_getpending_tosql")
+	}
+
+	// The pending posts are read from the master, as the job processing them deletes or
+	// updates them between pages.
+	scheduledPosts := []*model.ScheduledPost{}
+	if err := s.GetMasterX().Select(&scheduledPosts, query, args...); err != nil {
+		return nil, errors.Wrap(err, "failed to find pending ScheduledPosts")
+	}
+	return scheduledPosts, nil
+}
+
+func (s *SqlScheduledPostStore) Update(scheduledPost *model.ScheduledPost) error {
+	scheduledPost.PreUpdate()
+	if err := scheduledPost.IsValid(model.PostMessageMaxRunesV2); err != nil {
+		return err
+	}
+
+	query, args, err := s.getQueryBuilder().
+		Update("ScheduledPosts").
+		Set("UpdateAt", scheduledPost.UpdateAt).
+		Set("Message", scheduledPost.Message).
+		Set("Props", scheduledPost.Props).
+		Set("FileIds", scheduledPost.FileIds).
+		Set("ScheduledAt", scheduledPost.ScheduledAt).
+		Set("ProcessedAt", scheduledPost.ProcessedAt).
+		Set("ErrorCode", scheduledPost.ErrorCode).
+		Where(sq.Eq{"Id": scheduledPost.Id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "scheduled_post_update_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update ScheduledPost with id=%s", scheduledPost.Id)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return store.NewErrNotFound("ScheduledPost", scheduledPost.Id)
+	}
+	return nil
+}
+
+func (s *SqlScheduledPostStore) Delete(id string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("ScheduledPosts").
+		Where(sq.Eq{"Id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "scheduled_post_delete_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete ScheduledPost with id=%s", id)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return store.NewErrNotFound("ScheduledPost", id)
+	}
+	return nil
+}
+
+func (s *SqlScheduledPostStore) PermanentDeleteByUser(userID string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("ScheduledPosts").
+		Where(sq.Eq{"UserId": userID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "scheduled_post_permanentdeletebyuser_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return errors.Wrapf(err, "failed to delete ScheduledPosts with userId=%s", userID)
+	}
+	return nil
+}
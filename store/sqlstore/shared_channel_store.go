@@ -60,7 +60,7 @@ func (s SqlSharedChannelStore) Save(sc *model.SharedChannel) (*model.SharedChann
 
 	// set `Shared` flag in Channels table if needed
 	if channel.Shared == nil || !*channel.Shared {
-		if err := s.stores.channel.SetShared(channel.Id, true); err != nil {
+		if err := s.setChannelSharedT(transaction, channel.Id, true); err != nil {
 			return nil, err
 		}
 	}
@@ -287,7 +287,7 @@ func (s SqlSharedChannelStore) Delete(channelId string) (bool, error) {
 
 	if count > 0 {
 		// unset the channel's Shared flag
-		if err = s.Channel().SetShared(channelId, false); err != nil {
+		if err = s.setChannelSharedT(transaction, channelId, false); err != nil {
 			return false, errors.Wrap(err, "error unsetting channel share flag")
 		}
 	}
@@ -681,7 +681,7 @@ func (s SqlSharedChannelStore) GetUsersForSync(filter model.GetUsersForSyncFilte
 // UpdateUserLastSyncAt updates the LastSyncAt timestamp for the specified SharedChannelUser.
 func (s SqlSharedChannelStore) UpdateUserLastSyncAt(userID string, channelID string, remoteID string) error {
 	var query string
-	if s.DriverName() == model.DatabaseDriverPostgres {
+	if s.DriverName() == model.DatabaseDriverPostgres || s.DriverName() == model.DatabaseDriverSqlite {
 		query = `
 		UPDATE
 			SharedChannelUsers AS scu
@@ -758,7 +758,7 @@ func (s SqlSharedChannelStore) UpsertAttachment(attachment *model.SharedChannelA
 
 	if s.DriverName() == model.DatabaseDriverMysql {
 		query = query.SuffixExpr(sq.Expr("ON DUPLICATE KEY UPDATE LastSyncAt = ?", attachment.LastSyncAt))
-	} else if s.DriverName() == model.DatabaseDriverPostgres {
+	} else if s.DriverName() == model.DatabaseDriverPostgres || s.DriverName() == model.DatabaseDriverSqlite {
 		query = query.SuffixExpr(sq.Expr("ON CONFLICT (id) DO UPDATE SET LastSyncAt = ?", attachment.LastSyncAt))
 	}
 
@@ -0,0 +1,220 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+)
+
+func init() {
+	// The SQLite queries use the same placeholders as the MySQL ones.
+	sqlx.BindDriver(model.DatabaseDriverSqlite, sqlx.QUESTION)
+
+	// SQLite lacks a few of the functions shared by MySQL and PostgreSQL, so they are
+	// provided here to avoid forking every query which uses them.
+	sqlite.MustRegisterDeterministicScalarFunction("greatest", -1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return sqlitePickValue(args, 1)
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("least", -1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return sqlitePickValue(args, -1)
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("concat", -1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		var sb strings.Builder
+		for _, arg := range args {
+			if arg == nil {
+				return nil, nil
+			}
+			sb.WriteString(sqliteValueToString(arg))
+		}
+		return sb.String(), nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("from_unixtime", 1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		seconds, ok := sqliteValueToFloat(args[0])
+		if !ok {
+			return nil, nil
+		}
+		return time.Unix(int64(seconds), 0).UTC().Format("2006-01-02 15:04:05"), nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("fulltext_words", 1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		return sqliteFulltextWords(sqliteValueToString(args[0])), nil
+	})
+}
+
+// sqlitePickValue returns the greatest of the values when sign is 1, and the least of them
+// when sign is -1. Like in MySQL, the result is NULL if any of the values is NULL.
+func sqlitePickValue(args []driver.Value, sign int) (driver.Value, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("wrong number of arguments")
+	}
+
+	picked := args[0]
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+		if compareSqliteValues(arg, picked)*sign > 0 {
+			picked = arg
+		}
+	}
+	return picked, nil
+}
+
+func compareSqliteValues(a, b driver.Value) int {
+	af, aNumeric := sqliteValueToFloat(a)
+	bf, bNumeric := sqliteValueToFloat(b)
+	if aNumeric && bNumeric {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(sqliteValueToString(a), sqliteValueToString(b))
+}
+
+func sqliteValueToFloat(value driver.Value) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func sqliteValueToString(value driver.Value) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// appendSqlitePragmas sets the pragmas needed by the SQLite connections, unless the data
+// source already sets them: foreign keys are enforced for the retention policy tables, and
+// the write-ahead log with a busy timeout lets the readers and the writer share the file.
+func appendSqlitePragmas(dataSource string) string {
+	pragmas := []string{
+		"foreign_keys(1)",
+		"journal_mode(wal)",
+		fmt.Sprintf("busy_timeout(%d)", SQLiteBusyTimeoutMilliseconds),
+	}
+
+	params := []string{}
+	for _, pragma := range pragmas {
+		name := pragma[:strings.Index(pragma, "(")]
+		if !strings.Contains(dataSource, "_pragma="+name) {
+			params = append(params, "_pragma="+pragma)
+		}
+	}
+	if len(params) == 0 {
+		return dataSource
+	}
+
+	separator := "?"
+	if strings.Contains(dataSource, "?") {
+		separator = "&"
+	}
+	return dataSource + separator + strings.Join(params, "&")
+}
+
+// sqliteFulltextWords normalizes a text for the full text search fallback: its words are
+// lowercased and separated by single spaces, with a space on both ends so that any of them
+// can be matched with LIKE '% word %'.
+func sqliteFulltextWords(text string) string {
+	var sb strings.Builder
+	sb.WriteByte(' ')
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			sb.WriteRune(unicode.ToLower(r))
+		} else if !strings.HasSuffix(sb.String(), " ") {
+			sb.WriteByte(' ')
+		}
+	}
+	if !strings.HasSuffix(sb.String(), " ") {
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
+
+// splitSqliteFulltextTerms splits the terms of a search into words and quoted phrases.
+func splitSqliteFulltextTerms(terms string) []string {
+	var split []string
+	for i, part := range strings.Split(terms, `"`) {
+		if i%2 == 1 {
+			split = append(split, part)
+		} else {
+			split = append(split, strings.Fields(part)...)
+		}
+	}
+	return split
+}
+
+// buildSqliteFulltextTermClause matches a single word or phrase of the terms, or a prefix
+// when the term ends with a wildcard.
+func buildSqliteFulltextTermClause(column, term string) sq.Sqlizer {
+	prefix := strings.HasSuffix(term, "*")
+	words := strings.TrimSpace(sqliteFulltextWords(strings.TrimSuffix(term, "*")))
+	if words == "" {
+		return nil
+	}
+
+	likeTerm := "% " + strings.ReplaceAll(words, "_", "\\_")
+	if prefix {
+		likeTerm += "%"
+	} else {
+		likeTerm += " %"
+	}
+	return sq.Expr("fulltext_words("+column+") LIKE ? ESCAPE '\\'", likeTerm)
+}
+
+// buildSqliteFulltextClause is the fallback for the full text searches, as SQLite has no
+// full text index. The search is done word by word over each of the columns: all the terms
+// have to be found in one of them, or any of the terms if orTerms is set, and none of the
+// excluded terms.
+func buildSqliteFulltextClause(terms, excludedTerms string, orTerms bool, columns ...string) sq.Sqlizer {
+	clause := sq.Or{}
+	for _, column := range columns {
+		termClauses := []sq.Sqlizer{}
+		for _, term := range splitSqliteFulltextTerms(terms) {
+			if termClause := buildSqliteFulltextTermClause(column, term); termClause != nil {
+				termClauses = append(termClauses, termClause)
+			}
+		}
+		if len(termClauses) == 0 {
+			continue
+		}
+
+		columnClause := sq.And{}
+		if orTerms {
+			columnClause = append(columnClause, sq.Or(termClauses))
+		} else {
+			columnClause = append(columnClause, termClauses...)
+		}
+		for _, term := range splitSqliteFulltextTerms(excludedTerms) {
+			if termClause := buildSqliteFulltextTermClause(column, term); termClause != nil {
+				columnClause = append(columnClause, sq.Expr("NOT (?)", termClause))
+			}
+		}
+		clause = append(clause, columnClause)
+	}
+	return clause
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSqlitePickValue(t *testing.T) {
+	testCases := []struct {
+		Scenario string
+		Args     []driver.Value
+		Greatest driver.Value
+		Least    driver.Value
+	}{
+		{"Should compare integers", []driver.Value{int64(3), int64(10), int64(-1)}, int64(10), int64(-1)},
+		{"Should compare integers with floats", []driver.Value{int64(3), float64(3.5)}, float64(3.5), int64(3)},
+		{"Should compare strings", []driver.Value{"b", "c", "a"}, "c", "a"},
+		{"Should return NULL if any value is NULL", []driver.Value{int64(1), nil}, nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			greatest, err := sqlitePickValue(tc.Args, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Greatest, greatest)
+
+			least, err := sqlitePickValue(tc.Args, -1)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Least, least)
+		})
+	}
+}
+
+func TestAppendSqlitePragmas(t *testing.T) {
+	testCases := []struct {
+		Scenario    string
+		DSN         string
+		ExpectedDSN string
+	}{
+		{
+			"Should append the pragmas to a file path",
+			"/var/lib/matterfoss/matterfoss.db",
+			"/var/lib/matterfoss/matterfoss.db?_pragma=foreign_keys(1)&_pragma=journal_mode(wal)&_pragma=busy_timeout(10000)",
+		},
+		{
+			"Should append the pragmas to a DSN with existing params",
+			"file:matterfoss.db?cache=shared",
+			"file:matterfoss.db?cache=shared&_pragma=foreign_keys(1)&_pragma=journal_mode(wal)&_pragma=busy_timeout(10000)",
+		},
+		{
+			"Should not override the pragmas set in the DSN",
+			"matterfoss.db?_pragma=journal_mode(delete)&_pragma=busy_timeout(500)",
+			"matterfoss.db?_pragma=journal_mode(delete)&_pragma=busy_timeout(500)&_pragma=foreign_keys(1)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Scenario, func(t *testing.T) {
+			assert.Equal(t, tc.ExpectedDSN, appendSqlitePragmas(tc.DSN))
+		})
+	}
+}
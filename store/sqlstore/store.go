@@ -22,6 +22,7 @@ import (
 	"github.com/mattermost/morph/drivers"
 	ms "github.com/mattermost/morph/drivers/mysql"
 	ps "github.com/mattermost/morph/drivers/postgres"
+	sl "github.com/mattermost/morph/drivers/sqlite"
 
 	"github.com/go-sql-driver/mysql"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -29,6 +30,8 @@ import (
 	"github.com/lib/pq"
 	mbindata "github.com/mattermost/morph/sources/embedded"
 	"github.com/pkg/errors"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 
 	"github.com/cjdelisle/matterfoss-server/v6/db"
 	"github.com/cjdelisle/matterfoss-server/v6/einterfaces"
@@ -49,6 +52,7 @@ const (
 	MySQLForeignKeyViolationErrorCode = 1452
 	PGDuplicateObjectErrorCode        = "42710"
 	MySQLDuplicateObjectErrorCode     = 1022
+	SQLiteBusyTimeoutMilliseconds     = 10000
 	DBPingAttempts                    = 18
 	DBPingTimeoutSecs                 = 10
 	// This is a numerical version string by postgres. The format is
@@ -106,6 +110,14 @@ type SqlStoreStores struct {
 	UserTermsOfService   store.UserTermsOfServiceStore
 	linkMetadata         store.LinkMetadataStore
 	sharedchannel        store.SharedChannelStore
+	draft                store.DraftStore
+	scheduledPost        store.ScheduledPostStore
+	postRevision         store.PostRevisionStore
+	reminder             store.ReminderStore
+	pollVote             store.PollVoteStore
+	channelBookmark      store.ChannelBookmarkStore
+	webAuthnCredential   store.WebAuthnCredentialStore
+	mfaRecoveryCode      store.MfaRecoveryCodeStore
 }
 
 type SqlStore struct {
@@ -197,6 +209,14 @@ func New(settings model.SqlSettings, metrics einterfaces.MetricsInterface) *SqlS
 	store.stores.scheme = newSqlSchemeStore(store)
 	store.stores.group = newSqlGroupStore(store)
 	store.stores.productNotices = newSqlProductNoticesStore(store)
+	store.stores.draft = newSqlDraftStore(store)
+	store.stores.scheduledPost = newSqlScheduledPostStore(store)
+	store.stores.postRevision = newSqlPostRevisionStore(store)
+	store.stores.reminder = newSqlReminderStore(store)
+	store.stores.pollVote = newSqlPollVoteStore(store)
+	store.stores.channelBookmark = newSqlChannelBookmarkStore(store)
+	store.stores.webAuthnCredential = newSqlWebAuthnCredentialStore(store)
+	store.stores.mfaRecoveryCode = newSqlMfaRecoveryCodeStore(store)
 
 	err = upgradeDatabase(store, model.CurrentVersion)
 	if err != nil {
@@ -274,11 +294,15 @@ func (ss *SqlStore) initConnection() {
 		}
 	}
 
+	if ss.DriverName() == model.DatabaseDriverSqlite {
+		dataSource = appendSqlitePragmas(dataSource)
+	}
+
 	handle := setupConnection("master", dataSource, ss.settings)
 	ss.masterX = newSqlxDBWrapper(sqlx.NewDb(handle, ss.DriverName()),
 		time.Duration(*ss.settings.QueryTimeout)*time.Second,
 		*ss.settings.Trace)
-	if ss.DriverName() == model.DatabaseDriverMysql {
+	if ss.DriverName() != model.DatabaseDriverPostgres {
 		ss.masterX.MapperFunc(noOpMapper)
 	}
 
@@ -289,7 +313,7 @@ func (ss *SqlStore) initConnection() {
 			ss.ReplicaXs[i] = newSqlxDBWrapper(sqlx.NewDb(handle, ss.DriverName()),
 				time.Duration(*ss.settings.QueryTimeout)*time.Second,
 				*ss.settings.Trace)
-			if ss.DriverName() == model.DatabaseDriverMysql {
+			if ss.DriverName() != model.DatabaseDriverPostgres {
 				ss.ReplicaXs[i].MapperFunc(noOpMapper)
 			}
 		}
@@ -302,7 +326,7 @@ func (ss *SqlStore) initConnection() {
 			ss.searchReplicaXs[i] = newSqlxDBWrapper(sqlx.NewDb(handle, ss.DriverName()),
 				time.Duration(*ss.settings.QueryTimeout)*time.Second,
 				*ss.settings.Trace)
-			if ss.DriverName() == model.DatabaseDriverMysql {
+			if ss.DriverName() != model.DatabaseDriverPostgres {
 				ss.searchReplicaXs[i].MapperFunc(noOpMapper)
 			}
 		}
@@ -342,6 +366,8 @@ func (ss *SqlStore) GetDbVersion(numerical bool) (string, error) {
 		}
 	} else if ss.DriverName() == model.DatabaseDriverMysql {
 		sqlVersion = `SELECT version()`
+	} else if ss.DriverName() == model.DatabaseDriverSqlite {
+		sqlVersion = `SELECT sqlite_version()`
 	} else {
 		return "", errors.New("Not supported driver")
 	}
@@ -364,7 +390,7 @@ func (ss *SqlStore) SetMasterX(db *sql.DB) {
 	ss.masterX = newSqlxDBWrapper(sqlx.NewDb(db, ss.DriverName()),
 		time.Duration(*ss.settings.QueryTimeout)*time.Second,
 		*ss.settings.Trace)
-	if ss.DriverName() == model.DatabaseDriverMysql {
+	if ss.DriverName() != model.DatabaseDriverPostgres {
 		ss.masterX.MapperFunc(noOpMapper)
 	}
 }
@@ -512,6 +538,19 @@ func (ss *SqlStore) DoesTableExist(tableName string) bool {
 
 		return count > 0
 
+	} else if ss.DriverName() == model.DatabaseDriverSqlite {
+		var count int64
+		err := ss.GetMasterX().Get(&count,
+			`SELECT COUNT(0) FROM sqlite_master WHERE type = 'table' AND name = ?`,
+			tableName,
+		)
+
+		if err != nil {
+			mlog.Fatal("Failed to check if table exists", mlog.Err(err))
+		}
+
+		return count > 0
+
 	} else {
 		mlog.Fatal("Failed to check if column exists because of missing driver")
 		return false
@@ -562,6 +601,20 @@ func (ss *SqlStore) DoesColumnExist(tableName string, columnName string) bool {
 
 		return count > 0
 
+	} else if ss.DriverName() == model.DatabaseDriverSqlite {
+		var count int64
+		err := ss.GetMasterX().Get(&count,
+			`SELECT COUNT(0) FROM pragma_table_info(?) WHERE name = ?`,
+			tableName,
+			columnName,
+		)
+
+		if err != nil {
+			mlog.Fatal("Failed to check if column exists", mlog.Err(err))
+		}
+
+		return count > 0
+
 	} else {
 		mlog.Fatal("Failed to check if column exists because of missing driver")
 		return false
@@ -604,6 +657,19 @@ func (ss *SqlStore) DoesTriggerExist(triggerName string) bool {
 
 		return count > 0
 
+	} else if ss.DriverName() == model.DatabaseDriverSqlite {
+		var count int64
+		err := ss.GetMasterX().Get(&count,
+			`SELECT COUNT(0) FROM sqlite_master WHERE type = 'trigger' AND name = ?`,
+			triggerName,
+		)
+
+		if err != nil {
+			mlog.Fatal("Failed to check if trigger exists", mlog.Err(err))
+		}
+
+		return count > 0
+
 	} else {
 		mlog.Fatal("Failed to check if column exists because of missing driver")
 		return false
@@ -624,7 +690,7 @@ func (ss *SqlStore) CreateColumnIfNotExists(tableName string, columnName string,
 
 		return true
 
-	} else if ss.DriverName() == model.DatabaseDriverMysql {
+	} else if ss.DriverName() == model.DatabaseDriverMysql || ss.DriverName() == model.DatabaseDriverSqlite {
 		_, err := ss.GetMasterX().ExecNoTimeout("ALTER TABLE " + tableName + " ADD " + columnName + " " + mySqlColType + " DEFAULT '" + defaultValue + "'")
 		if err != nil {
 			mlog.Fatal("Failed to create column", mlog.Err(err))
@@ -661,6 +727,10 @@ func IsConstraintAlreadyExistsError(err error) bool {
 		if dbErr.Number == MySQLDuplicateObjectErrorCode {
 			return true
 		}
+	case *sqlite.Error:
+		if dbErr.Code() == sqlite3.SQLITE_ERROR && strings.Contains(dbErr.Error(), "already exists") {
+			return true
+		}
 	}
 	return false
 }
@@ -675,6 +745,10 @@ func IsUniqueConstraintError(err error, indexName []string) bool {
 		unique = true
 	}
 
+	if sqliteErr, ok := err.(*sqlite.Error); ok && (sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY) {
+		unique = true
+	}
+
 	field := false
 	for _, contain := range indexName {
 		if strings.Contains(err.Error(), contain) {
@@ -877,6 +951,38 @@ func (ss *SqlStore) SharedChannel() store.SharedChannelStore {
 	return ss.stores.sharedchannel
 }
 
+func (ss *SqlStore) Draft() store.DraftStore {
+	return ss.stores.draft
+}
+
+func (ss *SqlStore) ScheduledPost() store.ScheduledPostStore {
+	return ss.stores.scheduledPost
+}
+
+func (ss *SqlStore) PostRevision() store.PostRevisionStore {
+	return ss.stores.postRevision
+}
+
+func (ss *SqlStore) Reminder() store.ReminderStore {
+	return ss.stores.reminder
+}
+
+func (ss *SqlStore) PollVote() store.PollVoteStore {
+	return ss.stores.pollVote
+}
+
+func (ss *SqlStore) ChannelBookmark() store.ChannelBookmarkStore {
+	return ss.stores.channelBookmark
+}
+
+func (ss *SqlStore) WebAuthnCredential() store.WebAuthnCredentialStore {
+	return ss.stores.webAuthnCredential
+}
+
+func (ss *SqlStore) MfaRecoveryCode() store.MfaRecoveryCodeStore {
+	return ss.stores.mfaRecoveryCode
+}
+
 func (ss *SqlStore) DropAllTables() {
 	if ss.DriverName() == model.DatabaseDriverPostgres {
 		ss.masterX.Exec(`DO
@@ -891,6 +997,14 @@ func (ss *SqlStore) DropAllTables() {
 			   );
 			END
 			$func$;`)
+	} else if ss.DriverName() == model.DatabaseDriverSqlite {
+		tables := []string{}
+		ss.masterX.Select(&tables, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+		for _, t := range tables {
+			if t != "db_migrations" {
+				ss.masterX.Exec(`DELETE FROM ` + t)
+			}
+		}
 	} else {
 		tables := []string{}
 		ss.masterX.Select(&tables, `show tables`)
@@ -982,6 +1096,12 @@ func (ss *SqlStore) migrate(direction migrationDirection) error {
 				StatementTimeoutInSecs: *ss.settings.MigrationsStatementTimeoutSeconds,
 			},
 		})
+	case model.DatabaseDriverSqlite:
+		driver, err = sl.WithInstance(ss.GetMasterX().DB.DB, &sl.Config{
+			Config: drivers.Config{
+				StatementTimeoutInSecs: *ss.settings.MigrationsStatementTimeoutSeconds,
+			},
+		})
 	default:
 		err = fmt.Errorf("unsupported database type %s for migration", ss.DriverName())
 	}
@@ -991,7 +1111,11 @@ func (ss *SqlStore) migrate(direction migrationDirection) error {
 
 	opts := []morph.EngineOption{
 		morph.WithLogger(log.New(&morphWriter{}, "", log.Lshortfile)),
-		morph.WithLock("mm-lock-key"),
+	}
+	// The SQLite driver cannot take a cluster wide lock, and doesn't need one as the
+	// database file is only ever used by a single server.
+	if ss.DriverName() != model.DatabaseDriverSqlite {
+		opts = append(opts, morph.WithLock("mm-lock-key"))
 	}
 	engine, err := morph.New(context.Background(), driver, src, opts...)
 	if err != nil {
@@ -1037,24 +1161,12 @@ func resetReadTimeout(dataSource string) (string, error) {
 	return config.FormatDSN(), nil
 }
 
-func convertMySQLFullTextColumnsToPostgres(columnNames string) string {
-	columns := strings.Split(columnNames, ", ")
-	concatenatedColumnNames := ""
-	for i, c := range columns {
-		concatenatedColumnNames += c
-		if i < len(columns)-1 {
-			concatenatedColumnNames += " || ' ' || "
-		}
-	}
-
-	return concatenatedColumnNames
-}
-
 // IsDuplicate checks whether an error is a duplicate key error, which comes when processes are competing on creating the same
 // tables in the database.
 func IsDuplicate(err error) bool {
 	var pqErr *pq.Error
 	var mysqlErr *mysql.MySQLError
+	var sqliteErr *sqlite.Error
 	switch {
 	case errors.As(errors.Cause(err), &pqErr):
 		if pqErr.Code == PGDupTableErrorCode {
@@ -1064,6 +1176,10 @@ func IsDuplicate(err error) bool {
 		if mysqlErr.Number == MySQLDupTableErrorCode {
 			return true
 		}
+	case errors.As(errors.Cause(err), &sqliteErr):
+		if sqliteErr.Code() == sqlite3.SQLITE_ERROR && strings.Contains(sqliteErr.Error(), "already exists") {
+			return true
+		}
 	}
 
 	return false
@@ -111,11 +111,14 @@ func initStores() {
 			storeTypes = append(storeTypes, newStoreType("MySQL", model.DatabaseDriverMysql))
 		case "postgres":
 			storeTypes = append(storeTypes, newStoreType("PostgreSQL", model.DatabaseDriverPostgres))
+		case "sqlite":
+			storeTypes = append(storeTypes, newStoreType("SQLite", model.DatabaseDriverSqlite))
 		}
 	} else {
 		storeTypes = append(storeTypes,
 			newStoreType("MySQL", model.DatabaseDriverMysql),
 			newStoreType("PostgreSQL", model.DatabaseDriverPostgres),
+			newStoreType("SQLite", model.DatabaseDriverSqlite),
 		)
 	}
 
@@ -395,6 +398,7 @@ func TestGetDbVersion(t *testing.T) {
 	testDrivers := []string{
 		model.DatabaseDriverPostgres,
 		model.DatabaseDriverMysql,
+		model.DatabaseDriverSqlite,
 	}
 
 	for _, driver := range testDrivers {
@@ -489,6 +493,7 @@ func TestUpAndDownMigrations(t *testing.T) {
 	testDrivers := []string{
 		model.DatabaseDriverPostgres,
 		model.DatabaseDriverMysql,
+		model.DatabaseDriverSqlite,
 	}
 
 	for _, driver := range testDrivers {
@@ -654,6 +659,7 @@ func TestReplicaLagQuery(t *testing.T) {
 	testDrivers := []string{
 		model.DatabaseDriverPostgres,
 		model.DatabaseDriverMysql,
+		model.DatabaseDriverSqlite,
 	}
 
 	for _, driver := range testDrivers {
@@ -668,6 +674,9 @@ func TestReplicaLagQuery(t *testing.T) {
 		case model.DatabaseDriverMysql:
 			query = `SELECT table_name, count(table_name) FROM information_schema.tables WHERE table_name='Posts' and table_schema=Database() GROUP BY table_name`
 			tableName = "Posts"
+		case model.DatabaseDriverSqlite:
+			query = `SELECT name, count(name) FROM sqlite_master WHERE type='table' AND name='Posts' GROUP BY name`
+			tableName = "Posts"
 		}
 
 		settings.ReplicaLagSettings = []*model.ReplicaLagSettings{{
@@ -745,6 +754,8 @@ func makeSqlSettings(driver string) *model.SqlSettings {
 		return storetest.MakeSqlSettings(driver, false)
 	case model.DatabaseDriverMysql:
 		return storetest.MakeSqlSettings(driver, false)
+	case model.DatabaseDriverSqlite:
+		return storetest.MakeSqlSettings(driver, false)
 	}
 
 	return nil
@@ -793,6 +804,7 @@ func TestGetDBSchemaVersion(t *testing.T) {
 	testDrivers := []string{
 		model.DatabaseDriverPostgres,
 		model.DatabaseDriverMysql,
+		model.DatabaseDriverSqlite,
 	}
 
 	assets := db.Assets()
@@ -827,6 +839,7 @@ func TestGetAppliedMigrations(t *testing.T) {
 	testDrivers := []string{
 		model.DatabaseDriverPostgres,
 		model.DatabaseDriverMysql,
+		model.DatabaseDriverSqlite,
 	}
 
 	assets := db.Assets()
@@ -396,12 +396,14 @@ func (s SqlTeamStore) teamSearchQuery(opts *model.TeamSearch, countQuery bool) s
 		term = sanitizeSearchTerm(term, "\\")
 		term = wildcardSearchTerm(term)
 
-		operatorKeyword := "ILIKE"
+		operatorKeyword, escapeClause := "ILIKE", ""
 		if s.DriverName() == model.DatabaseDriverMysql {
 			operatorKeyword = "LIKE"
+		} else if s.DriverName() == model.DatabaseDriverSqlite {
+			operatorKeyword, escapeClause = "LIKE", " ESCAPE '\\'"
 		}
 
-		query = query.Where(fmt.Sprintf("(Name %[1]s ? OR DisplayName %[1]s ?)", operatorKeyword), term, term)
+		query = query.Where(fmt.Sprintf("(Name %[1]s ?%[2]s OR DisplayName %[1]s ?%[2]s)", operatorKeyword, escapeClause), term, term)
 	}
 
 	if opts.PolicyID != nil && *opts.PolicyID != "" {
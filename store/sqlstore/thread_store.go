@@ -576,7 +576,7 @@ func (s *SqlThreadStore) MarkAllAsReadByChannels(userID string, channelIDs []str
 	channelIDsSql, channelIDsArgs := constructArrayArgs(channelIDs)
 
 	var query string
-	if s.DriverName() == model.DatabaseDriverPostgres {
+	if s.DriverName() == model.DatabaseDriverPostgres || s.DriverName() == model.DatabaseDriverSqlite {
 		query = `
 			UPDATE ThreadMemberships
 			SET LastViewed = ?, UnreadMentions = ?, LastUpdated = ?
@@ -872,6 +872,14 @@ func (s *SqlThreadStore) MaintainMembership(userId, postId string, opts store.Th
                         AND NOT participants ? $3`, jsonArray([]string{userId}), postId, userId); err2 != nil {
 				return nil, err2
 			}
+		} else if s.DriverName() == model.DatabaseDriverSqlite {
+			// '$[#]' is the position just past the end of the array.
+			if _, err2 := trx.Exec(`UPDATE Threads
+				SET Participants = JSON_INSERT(Participants, '$[#]', ?)
+				WHERE PostId=?
+				AND NOT EXISTS (SELECT 1 FROM JSON_EACH(Participants) WHERE value = ?)`, userId, postId, userId); err2 != nil {
+				return nil, err2
+			}
 		} else {
 			// CONCAT('$[', JSON_LENGTH(Participants), ']') just generates $[n]
 			// which is the positional syntax required for appending.
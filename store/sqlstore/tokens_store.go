@@ -62,8 +62,21 @@ func (s SqlTokenStore) GetByToken(tokenString string) (*model.Token, error) {
 	return &token, nil
 }
 
-func (s SqlTokenStore) Cleanup(expiryTime int64) {
-	if _, err := s.GetMasterX().Exec("DELETE FROM Tokens WHERE CreateAt < ?", expiryTime); err != nil {
+func (s SqlTokenStore) Cleanup(expiryTime int64, keptTypes []string) {
+	builder := s.getQueryBuilder().
+		Delete("Tokens").
+		Where(sq.Lt{"CreateAt": expiryTime})
+	if len(keptTypes) > 0 {
+		builder = builder.Where(sq.NotEq{"Type": keptTypes})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		mlog.Error("Unable to build the query to cleanup token store.", mlog.Err(err))
+		return
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
 		mlog.Error("Unable to cleanup token store.")
 	}
 }
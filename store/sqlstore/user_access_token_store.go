@@ -67,6 +67,8 @@ func (s SqlUserAccessTokenStore) deleteSessionsAndTokensById(transaction *sqlxTx
 		query = "DELETE FROM Sessions s USING UserAccessTokens o WHERE o.Token = s.Token AND o.Id = ?"
 	} else if s.DriverName() == model.DatabaseDriverMysql {
 		query = "DELETE s.* FROM Sessions s INNER JOIN UserAccessTokens o ON o.Token = s.Token WHERE o.Id = ?"
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		query = "DELETE FROM Sessions WHERE Token IN (SELECT Token FROM UserAccessTokens WHERE Id = ?)"
 	}
 
 	if _, err := transaction.Exec(query, tokenId); err != nil {
@@ -108,6 +110,8 @@ func (s SqlUserAccessTokenStore) deleteSessionsandTokensByUser(transaction *sqlx
 		query = "DELETE FROM Sessions s USING UserAccessTokens o WHERE o.Token = s.Token AND o.UserId = ?"
 	} else if s.DriverName() == model.DatabaseDriverMysql {
 		query = "DELETE s.* FROM Sessions s INNER JOIN UserAccessTokens o ON o.Token = s.Token WHERE o.UserId = ?"
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		query = "DELETE FROM Sessions WHERE Token IN (SELECT Token FROM UserAccessTokens WHERE UserId = ?)"
 	}
 
 	if _, err := transaction.Exec(query, userId); err != nil {
@@ -220,6 +224,8 @@ func (s SqlUserAccessTokenStore) deleteSessionsAndDisableToken(transaction *sqlx
 		query = "DELETE FROM Sessions s USING UserAccessTokens o WHERE o.Token = s.Token AND o.Id = ?"
 	} else if s.DriverName() == model.DatabaseDriverMysql {
 		query = "DELETE s.* FROM Sessions s INNER JOIN UserAccessTokens o ON o.Token = s.Token WHERE o.Id = ?"
+	} else if s.DriverName() == model.DatabaseDriverSqlite {
+		query = "DELETE FROM Sessions WHERE Token IN (SELECT Token FROM UserAccessTokens WHERE Id = ?)"
 	}
 
 	if _, err := transaction.Exec(query, tokenId); err != nil {
@@ -0,0 +1,213 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+type SqlWebAuthnCredentialStore struct {
+	*SqlStore
+}
+
+func newSqlWebAuthnCredentialStore(sqlStore *SqlStore) store.WebAuthnCredentialStore {
+	return &SqlWebAuthnCredentialStore{
+		SqlStore: sqlStore,
+	}
+}
+
+func (s *SqlWebAuthnCredentialStore) credentialsQuery() sq.SelectBuilder {
+	return s.getQueryBuilder().
+		Select("Id", "UserId", "Name", "CredentialId", "PublicKey", "SignCount", "AAGUID", "Transports",
+			"CreateAt", "UpdateAt", "LastUsedAt").
+		From("WebAuthnCredentials")
+}
+
+func (s *SqlWebAuthnCredentialStore) Save(credential *model.WebAuthnCredential) (*model.WebAuthnCredential, error) {
+	if credential.Id != "" {
+		return nil, store.NewErrInvalidInput("WebAuthnCredential", "id", credential.Id)
+	}
+
+	credential.PreSave()
+	if err := credential.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query, args, err := s.getQueryBuilder().
+		Insert("WebAuthnCredentials").
+		Columns("Id", "UserId", "Name", "CredentialId", "PublicKey", "SignCount", "AAGUID", "Transports",
+			"CreateAt", "UpdateAt", "LastUsedAt").
+		Values(credential.Id, credential.UserId, credential.Name, credential.CredentialId, credential.PublicKey, credential.SignCount, credential.AAGUID, credential.Transports,
+			credential.CreateAt, credential.UpdateAt, credential.LastUsedAt).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "webauthn_credential_save_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		if IsUniqueConstraintError(err, []string{"CredentialId", "idx_webauthncredentials_credentialid"}) {
+			return nil, store.NewErrConflict("WebAuthnCredential", err, "credentialId="+credential.CredentialId)
+		}
+		return nil, errors.Wrapf(err, "failed to save WebAuthnCredential with id=%s", credential.Id)
+	}
+	return credential, nil
+}
+
+func (s *SqlWebAuthnCredentialStore) Get(id string) (*model.WebAuthnCredential, error) {
+	query, args, err := s.credentialsQuery().
+		Where(sq.Eq{"Id": id}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "webauthn_credential_get_tosql")
+	}
+
+	var credential model.WebAuthnCredential
+	if err := s.GetReplicaX().Get(&credential, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("WebAuthnCredential", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get WebAuthnCredential with id=%s", id)
+	}
+	return &credential, nil
+}
+
+func (s *SqlWebAuthnCredentialStore) GetByCredentialId(credentialID string) (*model.WebAuthnCredential, error) {
+	query, args, err := s.credentialsQuery().
+		Where(sq.Eq{"CredentialId": credentialID}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "webauthn_credential_getbycredentialid_tosql")
+	}
+
+	// The sign counter is checked against the last assertion, which must not be missed
+	// because of the replication lag.
+	var credential model.WebAuthnCredential
+	if err := s.GetMasterX().Get(&credential, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.NewErrNotFound("WebAuthnCredential", "credentialId="+credentialID)
+		}
+		return nil, errors.Wrapf(err, "failed to get WebAuthnCredential with credentialId=%s", credentialID)
+	}
+	return &credential, nil
+}
+
+func (s *SqlWebAuthnCredentialStore) GetForUser(userID string) ([]*model.WebAuthnCredential, error) {
+	query, args, err := s.credentialsQuery().
+		Where(sq.Eq{"UserId": userID}).
+		OrderBy("CreateAt", "Id").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "webauthn_credential_getforuser_tosql")
+	}
+
+	credentials := []*model.WebAuthnCredential{}
+	if err := s.GetReplicaX().Select(&credentials, query, args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to find WebAuthnCredentials with userId=%s", userID)
+	}
+	return credentials, nil
+}
+
+func (s *SqlWebAuthnCredentialStore) CountForUser(userID string) (int64, error) {
+	query, args, err := s.getQueryBuilder().
+		Select("COUNT(*)").
+		From("WebAuthnCredentials").
+		Where(sq.Eq{"UserId": userID}).
+		ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "webauthn_credential_countforuser_tosql")
+	}
+
+	var count int64
+	if err := s.GetReplicaX().Get(&count, query, args...); err != nil {
+		return 0, errors.Wrapf(err, "failed to count WebAuthnCredentials with userId=%s", userID)
+	}
+	return count, nil
+}
+
+func (s *SqlWebAuthnCredentialStore) Update(credential *model.WebAuthnCredential) (*model.WebAuthnCredential, error) {
+	credential.PreUpdate()
+	if err := credential.IsValid(); err != nil {
+		return nil, err
+	}
+
+	query, args, err := s.getQueryBuilder().
+		Update("WebAuthnCredentials").
+		Set("Name", credential.Name).
+		Set("UpdateAt", credential.UpdateAt).
+		Where(sq.Eq{"Id": credential.Id}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "webauthn_credential_update_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update WebAuthnCredential with id=%s", credential.Id)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return nil, store.NewErrNotFound("WebAuthnCredential", credential.Id)
+	}
+	return credential, nil
+}
+
+func (s *SqlWebAuthnCredentialStore) UpdateSignCount(id string, signCount int64, lastUsedAt int64) error {
+	query, args, err := s.getQueryBuilder().
+		Update("WebAuthnCredentials").
+		Set("SignCount", signCount).
+		Set("LastUsedAt", lastUsedAt).
+		Where(sq.Eq{"Id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "webauthn_credential_updatesigncount_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update WebAuthnCredential with id=%s", id)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return store.NewErrNotFound("WebAuthnCredential", id)
+	}
+	return nil
+}
+
+func (s *SqlWebAuthnCredentialStore) Delete(id string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("WebAuthnCredentials").
+		Where(sq.Eq{"Id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "webauthn_credential_delete_tosql")
+	}
+
+	result, err := s.GetMasterX().Exec(query, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete WebAuthnCredential with id=%s", id)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return store.NewErrNotFound("WebAuthnCredential", id)
+	}
+	return nil
+}
+
+func (s *SqlWebAuthnCredentialStore) PermanentDeleteByUser(userID string) error {
+	query, args, err := s.getQueryBuilder().
+		Delete("WebAuthnCredentials").
+		Where(sq.Eq{"UserId": userID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "webauthn_credential_permanentdeletebyuser_tosql")
+	}
+
+	if _, err := s.GetMasterX().Exec(query, args...); err != nil {
+		return errors.Wrapf(err, "failed to delete WebAuthnCredentials with userId=%s", userID)
+	}
+	return nil
+}
@@ -57,6 +57,14 @@ type Store interface {
 	UserTermsOfService() UserTermsOfServiceStore
 	LinkMetadata() LinkMetadataStore
 	SharedChannel() SharedChannelStore
+	Draft() DraftStore
+	ScheduledPost() ScheduledPostStore
+	PostRevision() PostRevisionStore
+	Reminder() ReminderStore
+	PollVote() PollVoteStore
+	ChannelBookmark() ChannelBookmarkStore
+	WebAuthnCredential() WebAuthnCredentialStore
+	MfaRecoveryCode() MfaRecoveryCodeStore
 	MarkSystemRanUnitTests()
 	Close()
 	LockToMaster()
@@ -356,9 +364,9 @@ type PostStore interface {
 	PermanentDeleteBatch(endTime int64, limit int64) (int64, error)
 	GetOldest() (*model.Post, error)
 	GetMaxPostSize() int
-	GetParentsForExportAfter(limit int, afterID string) ([]*model.PostForExport, error)
+	GetParentsForExportAfter(limit int, afterID string, channelIDs []string, since int64) ([]*model.PostForExport, error)
 	GetRepliesForExport(parentID string) ([]*model.ReplyForExport, error)
-	GetDirectPostParentsForExportAfter(limit int, afterID string) ([]*model.DirectPostForExport, error)
+	GetDirectPostParentsForExportAfter(limit int, afterID string, since int64) ([]*model.DirectPostForExport, error)
 	SearchPostsForUser(paramsList []*model.SearchParams, userID, teamID string, page, perPage int) (*model.PostSearchResults, error)
 	GetOldestEntityCreationTime() (int64, error)
 	HasAutoResponsePostByUserSince(options model.GetPostsSinceOptions, userId string) (bool, error)
@@ -612,7 +620,9 @@ type TokenStore interface {
 	Save(recovery *model.Token) error
 	Delete(token string) error
 	GetByToken(token string) (*model.Token, error)
-	Cleanup(expiryTime int64)
+	// Cleanup removes the tokens created before the expiry time, except the tokens of the
+	// kept types, which are removed by their own cleanup.
+	Cleanup(expiryTime int64, keptTypes []string)
 	GetAllTokensByType(tokenType string) ([]*model.Token, error)
 	RemoveAllTokensByType(tokenType string) error
 }
@@ -653,6 +663,8 @@ type FileInfoStore interface {
 	PermanentDelete(fileID string) error
 	PermanentDeleteBatch(endTime int64, limit int64) (int64, error)
 	PermanentDeleteByUser(userID string) (int64, error)
+	GetExpiredBatch(endTime int64, limit int) ([]*model.FileInfo, error)
+	GetOrphanedBatch(limit int) ([]*model.FileInfo, error)
 	SetContent(fileID, content string) error
 	Search(paramsList []*model.SearchParams, userID, teamID string, page, perPage int) (*model.FileInfoList, error)
 	CountAll() (int64, error)
@@ -668,6 +680,113 @@ type UploadSessionStore interface {
 	Delete(id string) error
 }
 
+type DraftStore interface {
+	Upsert(draft *model.Draft) (*model.Draft, error)
+	Get(userID, channelID, rootID string) (*model.Draft, error)
+	// GetDraftsForUser returns the drafts of the user in the channels of the team, and in
+	// the direct and group message channels, most recently updated first.
+	GetDraftsForUser(userID, teamID string) ([]*model.Draft, error)
+	Delete(userID, channelID, rootID string) error
+	PermanentDeleteByUser(userID string) error
+}
+
+type ScheduledPostStore interface {
+	Save(scheduledPost *model.ScheduledPost) (*model.ScheduledPost, error)
+	Get(id string) (*model.ScheduledPost, error)
+	// GetForUser returns the scheduled posts of the user in the channels of the team, and
+	// in the direct and group message channels, ordered by their scheduled time.
+	GetForUser(userID, teamID string) ([]*model.ScheduledPost, error)
+	// GetPendingScheduledPosts returns the unprocessed posts scheduled at or before the
+	// given time, ordered by their scheduled time and id, after the given cursor.
+	GetPendingScheduledPosts(beforeTime, afterTime int64, afterID string, limit int) ([]*model.ScheduledPost, error)
+	Update(scheduledPost *model.ScheduledPost) error
+	Delete(id string) error
+	PermanentDeleteByUser(userID string) error
+}
+
+type PostRevisionStore interface {
+	Save(revision *model.PostRevision) (*model.PostRevision, error)
+	// GetForPost returns the revisions of the post, most recent first.
+	GetForPost(postID string) ([]*model.PostRevision, error)
+	PermanentDelete(id string) error
+	PermanentDeleteByChannel(channelID string) error
+	// PermanentDeleteByUser deletes the revisions of the posts authored by the user.
+	PermanentDeleteByUser(userID string) error
+	PermanentDeleteBatchForRetentionPolicies(now, globalPolicyEndTime, limit int64, cursor model.RetentionPolicyCursor) (int64, model.RetentionPolicyCursor, error)
+	// DeleteOrphanedRows removes the revisions of the posts which no longer exist.
+	DeleteOrphanedRows(limit int) (int64, error)
+}
+
+type ReminderStore interface {
+	Save(reminder *model.Reminder) (*model.Reminder, error)
+	Get(id string) (*model.Reminder, error)
+	// GetForUser returns the reminders of the user, ordered by their target time.
+	GetForUser(userID string) ([]*model.Reminder, error)
+	// GetDueReminders returns the reminders which did not fire and are due at or before
+	// the given time, ordered by their target time and id, after the given cursor.
+	GetDueReminders(beforeTime, afterTime int64, afterID string, limit int) ([]*model.Reminder, error)
+	Update(reminder *model.Reminder) error
+	Delete(id string) error
+	PermanentDeleteByUser(userID string) error
+}
+
+type PollVoteStore interface {
+	// SetUserVotes replaces the votes of the user for the poll of the post.
+	SetUserVotes(postID, userID string, votes []*model.PollVote) error
+	// GetForPost returns the votes for the poll of the post, in the order they were made.
+	GetForPost(postID string) ([]*model.PollVote, error)
+	PermanentDeleteByUser(userID string) error
+	// DeleteOrphanedRows removes the votes for the posts which no longer exist.
+	DeleteOrphanedRows(limit int) (int64, error)
+}
+
+type ChannelBookmarkStore interface {
+	Save(bookmark *model.ChannelBookmark) (*model.ChannelBookmark, error)
+	Get(id string, includeDeleted bool) (*model.ChannelBookmark, error)
+	// GetBookmarksForChannelSince returns the bookmarks of the channel ordered by their sort
+	// order. When since is not zero, the bookmarks updated at or after that time are returned
+	// instead, the deleted ones included.
+	GetBookmarksForChannelSince(channelID string, since int64) ([]*model.ChannelBookmark, error)
+	// GetForFile returns the bookmarks which were not deleted and point to the file.
+	GetForFile(fileID string) ([]*model.ChannelBookmark, error)
+	Update(bookmark *model.ChannelBookmark) error
+	// UpdateSortOrder moves the bookmark to the given index among the bookmarks of the
+	// channel, and returns the bookmarks of the channel in their new order. The moved
+	// bookmarks are no longer marked as synchronized from a remote cluster, so that the
+	// change is sent to all the remote clusters.
+	UpdateSortOrder(bookmarkID, channelID string, newIndex int64) ([]*model.ChannelBookmark, error)
+	// Delete soft deletes the bookmark, which is no longer marked as synchronized from a
+	// remote cluster either.
+	Delete(id string) error
+	PermanentDeleteByChannel(channelID string) error
+}
+
+type WebAuthnCredentialStore interface {
+	Save(credential *model.WebAuthnCredential) (*model.WebAuthnCredential, error)
+	Get(id string) (*model.WebAuthnCredential, error)
+	// GetByCredentialId returns the credential with the given base64url encoded id on its
+	// authenticator.
+	GetByCredentialId(credentialID string) (*model.WebAuthnCredential, error)
+	GetForUser(userID string) ([]*model.WebAuthnCredential, error)
+	CountForUser(userID string) (int64, error)
+	Update(credential *model.WebAuthnCredential) (*model.WebAuthnCredential, error)
+	// UpdateSignCount records the signature counter of the last assertion made by the
+	// credential, and the time it was made at.
+	UpdateSignCount(id string, signCount int64, lastUsedAt int64) error
+	Delete(id string) error
+	PermanentDeleteByUser(userID string) error
+}
+
+type MfaRecoveryCodeStore interface {
+	// Replace saves the given recovery codes of the user in place of the previous ones.
+	Replace(userID string, codes []*model.MfaRecoveryCode) error
+	// Use marks the unused recovery code of the user with the given hash as used, failing
+	// with store.ErrNotFound when there is no such code.
+	Use(userID, codeHash string, usedAt int64) error
+	CountUnusedForUser(userID string) (int64, error)
+	PermanentDeleteByUser(userID string) error
+}
+
 type ReactionStore interface {
 	Save(reaction *model.Reaction) (*model.Reaction, error)
 	Delete(reaction *model.Reaction) (*model.Reaction, error)
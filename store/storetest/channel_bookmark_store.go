@@ -0,0 +1,227 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func TestChannelBookmarkStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testChannelBookmarkStoreSave(t, ss) })
+	t.Run("GetBookmarksForChannelSince", func(t *testing.T) { testChannelBookmarkStoreGetBookmarksForChannelSince(t, ss) })
+	t.Run("GetForFile", func(t *testing.T) { testChannelBookmarkStoreGetForFile(t, ss) })
+	t.Run("Update", func(t *testing.T) { testChannelBookmarkStoreUpdate(t, ss) })
+	t.Run("UpdateSortOrder", func(t *testing.T) { testChannelBookmarkStoreUpdateSortOrder(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testChannelBookmarkStoreDelete(t, ss) })
+	t.Run("PermanentDeleteByChannel", func(t *testing.T) { testChannelBookmarkStorePermanentDeleteByChannel(t, ss) })
+}
+
+func makeLinkBookmark(channelID string, sortOrder int64) *model.ChannelBookmark {
+	return &model.ChannelBookmark{
+		ChannelId:   channelID,
+		OwnerId:     model.NewId(),
+		DisplayName: "Docs",
+		SortOrder:   sortOrder,
+		LinkUrl:     "https://example.com/docs",
+		Type:        model.ChannelBookmarkLink,
+	}
+}
+
+func saveLinkBookmarks(t *testing.T, ss store.Store, channelID string, count int) []*model.ChannelBookmark {
+	bookmarks := make([]*model.ChannelBookmark, 0, count)
+	for i := 0; i < count; i++ {
+		bookmark, err := ss.ChannelBookmark().Save(makeLinkBookmark(channelID, int64(i)))
+		require.NoError(t, err)
+		bookmarks = append(bookmarks, bookmark)
+	}
+	return bookmarks
+}
+
+func bookmarkIds(bookmarks []*model.ChannelBookmark) []string {
+	ids := make([]string, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		ids = append(ids, bookmark.Id)
+	}
+	return ids
+}
+
+func testChannelBookmarkStoreSave(t *testing.T, ss store.Store) {
+	bookmark, err := ss.ChannelBookmark().Save(makeLinkBookmark(model.NewId(), 0))
+	require.NoError(t, err)
+	assert.True(t, model.IsValidId(bookmark.Id))
+	assert.NotZero(t, bookmark.CreateAt)
+
+	got, err := ss.ChannelBookmark().Get(bookmark.Id, false)
+	require.NoError(t, err)
+	assert.Equal(t, bookmark, got)
+
+	t.Run("existing id", func(t *testing.T) {
+		_, err := ss.ChannelBookmark().Save(bookmark)
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+	})
+
+	t.Run("remote bookmark keeps its id", func(t *testing.T) {
+		remote := makeLinkBookmark(model.NewId(), 0)
+		remote.Id = model.NewId()
+		remote.RemoteId = model.NewString(model.NewId())
+		saved, err := ss.ChannelBookmark().Save(remote)
+		require.NoError(t, err)
+		assert.Equal(t, remote.Id, saved.Id)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		invalid := makeLinkBookmark(model.NewId(), 0)
+		invalid.Type = model.ChannelBookmarkFile
+		_, err := ss.ChannelBookmark().Save(invalid)
+		require.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := ss.ChannelBookmark().Get(model.NewId(), false)
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testChannelBookmarkStoreGetBookmarksForChannelSince(t *testing.T, ss store.Store) {
+	channelID := model.NewId()
+	bookmarks := saveLinkBookmarks(t, ss, channelID, 3)
+	saveLinkBookmarks(t, ss, model.NewId(), 1)
+
+	got, err := ss.ChannelBookmark().GetBookmarksForChannelSince(channelID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, bookmarkIds(bookmarks), bookmarkIds(got))
+
+	require.NoError(t, ss.ChannelBookmark().Delete(bookmarks[1].Id))
+
+	got, err = ss.ChannelBookmark().GetBookmarksForChannelSince(channelID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{bookmarks[0].Id, bookmarks[2].Id}, bookmarkIds(got))
+
+	deleted, err := ss.ChannelBookmark().Get(bookmarks[1].Id, true)
+	require.NoError(t, err)
+
+	got, err = ss.ChannelBookmark().GetBookmarksForChannelSince(channelID, deleted.UpdateAt)
+	require.NoError(t, err)
+	require.Contains(t, bookmarkIds(got), bookmarks[1].Id)
+	for _, bookmark := range got {
+		if bookmark.Id == bookmarks[1].Id {
+			assert.NotZero(t, bookmark.DeleteAt)
+		}
+	}
+}
+
+func testChannelBookmarkStoreGetForFile(t *testing.T, ss store.Store) {
+	fileID := model.NewId()
+	bookmark := makeLinkBookmark(model.NewId(), 0)
+	bookmark.Type = model.ChannelBookmarkFile
+	bookmark.LinkUrl = ""
+	bookmark.FileId = fileID
+	bookmark, err := ss.ChannelBookmark().Save(bookmark)
+	require.NoError(t, err)
+
+	got, err := ss.ChannelBookmark().GetForFile(fileID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{bookmark.Id}, bookmarkIds(got))
+
+	require.NoError(t, ss.ChannelBookmark().Delete(bookmark.Id))
+
+	got, err = ss.ChannelBookmark().GetForFile(fileID)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func testChannelBookmarkStoreUpdate(t *testing.T, ss store.Store) {
+	bookmark, err := ss.ChannelBookmark().Save(makeLinkBookmark(model.NewId(), 0))
+	require.NoError(t, err)
+
+	bookmark.DisplayName = "Handbook"
+	bookmark.Emoji = "book"
+	require.NoError(t, ss.ChannelBookmark().Update(bookmark))
+
+	got, err := ss.ChannelBookmark().Get(bookmark.Id, false)
+	require.NoError(t, err)
+	assert.Equal(t, "Handbook", got.DisplayName)
+	assert.Equal(t, "book", got.Emoji)
+
+	require.NoError(t, ss.ChannelBookmark().Delete(bookmark.Id))
+
+	err = ss.ChannelBookmark().Update(bookmark)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func testChannelBookmarkStoreUpdateSortOrder(t *testing.T, ss store.Store) {
+	channelID := model.NewId()
+	bookmarks := saveLinkBookmarks(t, ss, channelID, 4)
+
+	sorted, err := ss.ChannelBookmark().UpdateSortOrder(bookmarks[3].Id, channelID, 1)
+	require.NoError(t, err)
+	expected := []string{bookmarks[0].Id, bookmarks[3].Id, bookmarks[1].Id, bookmarks[2].Id}
+	assert.Equal(t, expected, bookmarkIds(sorted))
+	for i, bookmark := range sorted {
+		assert.Equal(t, int64(i), bookmark.SortOrder)
+	}
+
+	got, err := ss.ChannelBookmark().GetBookmarksForChannelSince(channelID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, expected, bookmarkIds(got))
+
+	t.Run("out of range", func(t *testing.T) {
+		_, err := ss.ChannelBookmark().UpdateSortOrder(bookmarks[0].Id, channelID, 4)
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+	})
+
+	t.Run("other channel", func(t *testing.T) {
+		_, err := ss.ChannelBookmark().UpdateSortOrder(bookmarks[0].Id, model.NewId(), 0)
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testChannelBookmarkStoreDelete(t *testing.T, ss store.Store) {
+	remote := makeLinkBookmark(model.NewId(), 0)
+	remote.Id = model.NewId()
+	remote.RemoteId = model.NewString(model.NewId())
+	bookmark, err := ss.ChannelBookmark().Save(remote)
+	require.NoError(t, err)
+
+	require.NoError(t, ss.ChannelBookmark().Delete(bookmark.Id))
+
+	_, err = ss.ChannelBookmark().Get(bookmark.Id, false)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	got, err := ss.ChannelBookmark().Get(bookmark.Id, true)
+	require.NoError(t, err)
+	assert.NotZero(t, got.DeleteAt)
+	assert.Equal(t, got.DeleteAt, got.UpdateAt)
+	assert.False(t, got.IsRemote())
+
+	err = ss.ChannelBookmark().Delete(bookmark.Id)
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func testChannelBookmarkStorePermanentDeleteByChannel(t *testing.T, ss store.Store) {
+	channelID := model.NewId()
+	bookmarks := saveLinkBookmarks(t, ss, channelID, 2)
+	other := saveLinkBookmarks(t, ss, model.NewId(), 1)
+
+	require.NoError(t, ss.ChannelBookmark().PermanentDeleteByChannel(channelID))
+
+	_, err := ss.ChannelBookmark().Get(bookmarks[0].Id, true)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	_, err = ss.ChannelBookmark().Get(other[0].Id, true)
+	require.NoError(t, err)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func TestDraftStore(t *testing.T, ss store.Store) {
+	t.Run("Upsert", func(t *testing.T) { testDraftStoreUpsert(t, ss) })
+	t.Run("GetDraftsForUser", func(t *testing.T) { testDraftStoreGetDraftsForUser(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testDraftStoreDelete(t, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testDraftStorePermanentDeleteByUser(t, ss) })
+}
+
+// makeDraftStoreChannel saves a channel of the team, or a direct channel if the team id
+// is empty.
+func makeDraftStoreChannel(t *testing.T, ss store.Store, teamID string) *model.Channel {
+	channel := &model.Channel{
+		TeamId:      teamID,
+		DisplayName: "DisplayName",
+		Name:        "channel" + model.NewId(),
+		Type:        model.ChannelTypeOpen,
+	}
+	var err error
+	if teamID == "" {
+		channel.Type = model.ChannelTypeDirect
+		member1 := &model.ChannelMember{UserId: model.NewId(), NotifyProps: model.GetDefaultChannelNotifyProps()}
+		member2 := &model.ChannelMember{UserId: model.NewId(), NotifyProps: model.GetDefaultChannelNotifyProps()}
+		channel, err = ss.Channel().SaveDirectChannel(channel, member1, member2)
+	} else {
+		channel, err = ss.Channel().Save(channel, -1)
+	}
+	require.NoError(t, err)
+	return channel
+}
+
+func testDraftStoreUpsert(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	channelID := model.NewId()
+	rootID := model.NewId()
+
+	draft, err := ss.Draft().Upsert(&model.Draft{
+		UserId:    userID,
+		ChannelId: channelID,
+		Message:   "message",
+		FileIds:   []string{"a", "a"},
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, draft.CreateAt)
+	assert.Equal(t, draft.CreateAt, draft.UpdateAt)
+	assert.Equal(t, model.StringArray{"a"}, draft.FileIds)
+
+	t.Run("a draft per thread", func(t *testing.T) {
+		reply, err := ss.Draft().Upsert(&model.Draft{
+			UserId:    userID,
+			ChannelId: channelID,
+			RootId:    rootID,
+			Message:   "reply",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, rootID, reply.RootId)
+
+		got, err := ss.Draft().Get(userID, channelID, "")
+		require.NoError(t, err)
+		assert.Equal(t, "message", got.Message)
+		got, err = ss.Draft().Get(userID, channelID, rootID)
+		require.NoError(t, err)
+		assert.Equal(t, "reply", got.Message)
+	})
+
+	t.Run("update keeps the creation time", func(t *testing.T) {
+		updated, err := ss.Draft().Upsert(&model.Draft{
+			UserId:    userID,
+			ChannelId: channelID,
+			Message:   "updated",
+			Props:     model.StringInterface{"key": "value"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, draft.CreateAt, updated.CreateAt)
+		assert.GreaterOrEqual(t, updated.UpdateAt, draft.UpdateAt)
+		assert.Equal(t, "updated", updated.Message)
+		assert.Equal(t, "value", updated.Props["key"])
+		assert.Empty(t, updated.FileIds)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ss.Draft().Upsert(&model.Draft{UserId: userID, ChannelId: "invalid"})
+		require.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := ss.Draft().Get(userID, model.NewId(), "")
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testDraftStoreGetDraftsForUser(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	teamID := model.NewId()
+	channel := makeDraftStoreChannel(t, ss, teamID)
+	otherTeamChannel := makeDraftStoreChannel(t, ss, model.NewId())
+	directChannel := makeDraftStoreChannel(t, ss, "")
+	archivedChannel := makeDraftStoreChannel(t, ss, teamID)
+	require.NoError(t, ss.Channel().Delete(archivedChannel.Id, model.GetMillis()))
+
+	for _, channelID := range []string{channel.Id, otherTeamChannel.Id, archivedChannel.Id, directChannel.Id} {
+		_, err := ss.Draft().Upsert(&model.Draft{
+			CreateAt:  1,
+			UserId:    userID,
+			ChannelId: channelID,
+			Message:   "message",
+		})
+		require.NoError(t, err)
+	}
+	_, err := ss.Draft().Upsert(&model.Draft{UserId: model.NewId(), ChannelId: channel.Id, Message: "other user"})
+	require.NoError(t, err)
+
+	drafts, err := ss.Draft().GetDraftsForUser(userID, teamID)
+	require.NoError(t, err)
+	require.Len(t, drafts, 2)
+	assert.Equal(t, directChannel.Id, drafts[0].ChannelId)
+	assert.Equal(t, channel.Id, drafts[1].ChannelId)
+}
+
+func testDraftStoreDelete(t *testing.T, ss store.Store) {
+	draft, err := ss.Draft().Upsert(&model.Draft{
+		UserId:    model.NewId(),
+		ChannelId: model.NewId(),
+		Message:   "message",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ss.Draft().Delete(draft.UserId, draft.ChannelId, draft.RootId))
+	_, err = ss.Draft().Get(draft.UserId, draft.ChannelId, draft.RootId)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	err = ss.Draft().Delete(draft.UserId, draft.ChannelId, draft.RootId)
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func testDraftStorePermanentDeleteByUser(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	channelID := model.NewId()
+	for _, rootID := range []string{"", model.NewId()} {
+		_, err := ss.Draft().Upsert(&model.Draft{UserId: userID, ChannelId: channelID, RootId: rootID, Message: "message"})
+		require.NoError(t, err)
+	}
+	other, err := ss.Draft().Upsert(&model.Draft{UserId: model.NewId(), ChannelId: channelID, Message: "message"})
+	require.NoError(t, err)
+
+	require.NoError(t, ss.Draft().PermanentDeleteByUser(userID))
+
+	_, err = ss.Draft().Get(userID, channelID, "")
+	require.Error(t, err)
+	_, err = ss.Draft().Get(other.UserId, channelID, "")
+	require.NoError(t, err)
+}
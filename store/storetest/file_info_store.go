@@ -27,6 +27,8 @@ func TestFileInfoStore(t *testing.T, ss store.Store) {
 	t.Run("FileInfoPermanentDelete", func(t *testing.T) { testFileInfoPermanentDelete(t, ss) })
 	t.Run("FileInfoPermanentDeleteBatch", func(t *testing.T) { testFileInfoPermanentDeleteBatch(t, ss) })
 	t.Run("FileInfoPermanentDeleteByUser", func(t *testing.T) { testFileInfoPermanentDeleteByUser(t, ss) })
+	t.Run("FileInfoGetExpiredBatch", func(t *testing.T) { testFileInfoGetExpiredBatch(t, ss) })
+	t.Run("FileInfoGetOrphanedBatch", func(t *testing.T) { testFileInfoGetOrphanedBatch(t, ss) })
 	t.Run("GetFilesBatchForIndexing", func(t *testing.T) { testFileInfoStoreGetFilesBatchForIndexing(t, ss) })
 	t.Run("CountAll", func(t *testing.T) { testFileInfoStoreCountAll(t, ss) })
 }
@@ -605,6 +607,92 @@ func testFileInfoPermanentDeleteByUser(t *testing.T, ss store.Store) {
 	require.NoError(t, err)
 }
 
+func testFileInfoGetExpiredBatch(t *testing.T, ss store.Store) {
+	endTime := model.GetMillis() - 1000
+
+	expired, err := ss.FileInfo().Save(&model.FileInfo{
+		CreatorId: model.NewId(),
+		Path:      "expired.txt",
+		CreateAt:  endTime - 1,
+	})
+	require.NoError(t, err)
+	defer ss.FileInfo().PermanentDelete(expired.Id)
+
+	deleted, err := ss.FileInfo().Save(&model.FileInfo{
+		CreatorId: model.NewId(),
+		Path:      "deleted.txt",
+		CreateAt:  endTime - 2,
+		DeleteAt:  endTime,
+	})
+	require.NoError(t, err)
+	defer ss.FileInfo().PermanentDelete(deleted.Id)
+
+	recent, err := ss.FileInfo().Save(&model.FileInfo{
+		CreatorId: model.NewId(),
+		Path:      "recent.txt",
+		CreateAt:  endTime,
+	})
+	require.NoError(t, err)
+	defer ss.FileInfo().PermanentDelete(recent.Id)
+
+	infos, err := ss.FileInfo().GetExpiredBatch(endTime, 10000)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(infos))
+	for i, info := range infos {
+		if i > 0 {
+			assert.LessOrEqual(t, infos[i-1].CreateAt, info.CreateAt)
+		}
+		ids = append(ids, info.Id)
+	}
+	assert.Contains(t, ids, expired.Id)
+	assert.Contains(t, ids, deleted.Id)
+	assert.NotContains(t, ids, recent.Id)
+}
+
+func testFileInfoGetOrphanedBatch(t *testing.T, ss store.Store) {
+	post, err := ss.Post().Save(&model.Post{
+		ChannelId: model.NewId(),
+		UserId:    model.NewId(),
+		Message:   "message",
+	})
+	require.NoError(t, err)
+
+	attached, err := ss.FileInfo().Save(&model.FileInfo{
+		PostId:    post.Id,
+		CreatorId: post.UserId,
+		Path:      "attached.txt",
+	})
+	require.NoError(t, err)
+	defer ss.FileInfo().PermanentDelete(attached.Id)
+
+	unattached, err := ss.FileInfo().Save(&model.FileInfo{
+		CreatorId: model.NewId(),
+		Path:      "unattached.txt",
+	})
+	require.NoError(t, err)
+	defer ss.FileInfo().PermanentDelete(unattached.Id)
+
+	orphaned, err := ss.FileInfo().Save(&model.FileInfo{
+		PostId:    model.NewId(),
+		CreatorId: model.NewId(),
+		Path:      "orphaned.txt",
+	})
+	require.NoError(t, err)
+	defer ss.FileInfo().PermanentDelete(orphaned.Id)
+
+	infos, err := ss.FileInfo().GetOrphanedBatch(10000)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.Id)
+	}
+	assert.Contains(t, ids, orphaned.Id)
+	assert.NotContains(t, ids, attached.Id)
+	assert.NotContains(t, ids, unattached.Id)
+}
+
 func testFileInfoStoreGetFilesBatchForIndexing(t *testing.T, ss store.Store) {
 	c1 := &model.Channel{}
 	c1.TeamId = model.NewId()
@@ -1592,6 +1592,8 @@ func testDeleteGroupSyncable(t *testing.T, ss store.Store) {
 	_, err = ss.Group().DeleteGroupSyncable(groupTeam.GroupId, model.NewId(), model.GroupSyncableTypeTeam)
 	require.True(t, errors.As(err, &nfErr))
 
+	time.Sleep(time.Millisecond)
+
 	// Happy path...
 	d1, err := ss.Group().DeleteGroupSyncable(groupTeam.GroupId, groupTeam.SyncableId, model.GroupSyncableTypeTeam)
 	require.NoError(t, err)
@@ -0,0 +1,92 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/shared/mfa"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func TestMfaRecoveryCodeStore(t *testing.T, ss store.Store) {
+	t.Run("Replace", func(t *testing.T) { testMfaRecoveryCodeStoreReplace(t, ss) })
+	t.Run("Use", func(t *testing.T) { testMfaRecoveryCodeStoreUse(t, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testMfaRecoveryCodeStorePermanentDeleteByUser(t, ss) })
+}
+
+func makeMfaRecoveryCodes(codes []string) []*model.MfaRecoveryCode {
+	recoveryCodes := make([]*model.MfaRecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		recoveryCodes = append(recoveryCodes, &model.MfaRecoveryCode{CodeHash: mfa.HashRecoveryCode(code)})
+	}
+	return recoveryCodes
+}
+
+func testMfaRecoveryCodeStoreReplace(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	codes := mfa.GenerateRecoveryCodes()
+	require.NoError(t, ss.MfaRecoveryCode().Replace(userID, makeMfaRecoveryCodes(codes)))
+
+	count, err := ss.MfaRecoveryCode().CountUnusedForUser(userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(codes)), count)
+
+	newCodes := mfa.GenerateRecoveryCodes()[:3]
+	require.NoError(t, ss.MfaRecoveryCode().Replace(userID, makeMfaRecoveryCodes(newCodes)))
+
+	count, err = ss.MfaRecoveryCode().CountUnusedForUser(userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	var nfErr *store.ErrNotFound
+	err = ss.MfaRecoveryCode().Use(userID, mfa.HashRecoveryCode(codes[0]), model.GetMillis())
+	require.True(t, errors.As(err, &nfErr), "replaced codes should not be usable")
+
+	t.Run("should not save an invalid code", func(t *testing.T) {
+		err := ss.MfaRecoveryCode().Replace(model.NewId(), []*model.MfaRecoveryCode{{CodeHash: "invalid"}})
+		require.Error(t, err)
+	})
+}
+
+func testMfaRecoveryCodeStoreUse(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	codes := mfa.GenerateRecoveryCodes()
+	require.NoError(t, ss.MfaRecoveryCode().Replace(userID, makeMfaRecoveryCodes(codes)))
+
+	require.NoError(t, ss.MfaRecoveryCode().Use(userID, mfa.HashRecoveryCode(codes[0]), model.GetMillis()))
+
+	count, err := ss.MfaRecoveryCode().CountUnusedForUser(userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(codes)-1), count)
+
+	var nfErr *store.ErrNotFound
+	err = ss.MfaRecoveryCode().Use(userID, mfa.HashRecoveryCode(codes[0]), model.GetMillis())
+	require.True(t, errors.As(err, &nfErr), "a code should only be usable once")
+
+	err = ss.MfaRecoveryCode().Use(model.NewId(), mfa.HashRecoveryCode(codes[1]), model.GetMillis())
+	require.True(t, errors.As(err, &nfErr), "a code should only be usable by its user")
+}
+
+func testMfaRecoveryCodeStorePermanentDeleteByUser(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	otherUserID := model.NewId()
+	require.NoError(t, ss.MfaRecoveryCode().Replace(userID, makeMfaRecoveryCodes(mfa.GenerateRecoveryCodes())))
+	require.NoError(t, ss.MfaRecoveryCode().Replace(otherUserID, makeMfaRecoveryCodes(mfa.GenerateRecoveryCodes())))
+
+	require.NoError(t, ss.MfaRecoveryCode().PermanentDeleteByUser(userID))
+
+	count, err := ss.MfaRecoveryCode().CountUnusedForUser(userID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	count, err = ss.MfaRecoveryCode().CountUnusedForUser(otherUserID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(mfa.RecoveryCodeCount), count)
+}
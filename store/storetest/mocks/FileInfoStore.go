@@ -144,6 +144,29 @@ func (_m *FileInfoStore) GetByPath(path string) (*model.FileInfo, error) {
 	return r0, r1
 }
 
+// GetExpiredBatch provides a mock function with given fields: endTime, limit
+func (_m *FileInfoStore) GetExpiredBatch(endTime int64, limit int) ([]*model.FileInfo, error) {
+	ret := _m.Called(endTime, limit)
+
+	var r0 []*model.FileInfo
+	if rf, ok := ret.Get(0).(func(int64, int) []*model.FileInfo); ok {
+		r0 = rf(endTime, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.FileInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int) error); ok {
+		r1 = rf(endTime, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFilesBatchForIndexing provides a mock function with given fields: startTime, endTime, limit
 func (_m *FileInfoStore) GetFilesBatchForIndexing(startTime int64, endTime int64, limit int) ([]*model.FileForIndexing, error) {
 	ret := _m.Called(startTime, endTime, limit)
@@ -236,6 +259,29 @@ func (_m *FileInfoStore) GetFromMaster(id string) (*model.FileInfo, error) {
 	return r0, r1
 }
 
+// GetOrphanedBatch provides a mock function with given fields: limit
+func (_m *FileInfoStore) GetOrphanedBatch(limit int) ([]*model.FileInfo, error) {
+	ret := _m.Called(limit)
+
+	var r0 []*model.FileInfo
+	if rf, ok := ret.Get(0).(func(int) []*model.FileInfo); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.FileInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetWithOptions provides a mock function with given fields: page, perPage, opt
 func (_m *FileInfoStore) GetWithOptions(page int, perPage int, opt *model.GetFileInfosOptions) ([]*model.FileInfo, error) {
 	ret := _m.Called(page, perPage, opt)
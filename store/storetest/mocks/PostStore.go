@@ -146,13 +146,13 @@ func (_m *PostStore) Get(ctx context.Context, id string, skipFetchThreads bool,
 	return r0, r1
 }
 
-// GetDirectPostParentsForExportAfter provides a mock function with given fields: limit, afterID
-func (_m *PostStore) GetDirectPostParentsForExportAfter(limit int, afterID string) ([]*model.DirectPostForExport, error) {
-	ret := _m.Called(limit, afterID)
+// GetDirectPostParentsForExportAfter provides a mock function with given fields: limit, afterID, since
+func (_m *PostStore) GetDirectPostParentsForExportAfter(limit int, afterID string, since int64) ([]*model.DirectPostForExport, error) {
+	ret := _m.Called(limit, afterID, since)
 
 	var r0 []*model.DirectPostForExport
-	if rf, ok := ret.Get(0).(func(int, string) []*model.DirectPostForExport); ok {
-		r0 = rf(limit, afterID)
+	if rf, ok := ret.Get(0).(func(int, string, int64) []*model.DirectPostForExport); ok {
+		r0 = rf(limit, afterID, since)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.DirectPostForExport)
@@ -160,8 +160,8 @@ func (_m *PostStore) GetDirectPostParentsForExportAfter(limit int, afterID strin
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int, string) error); ok {
-		r1 = rf(limit, afterID)
+	if rf, ok := ret.Get(1).(func(int, string, int64) error); ok {
+		r1 = rf(limit, afterID, since)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -331,13 +331,13 @@ func (_m *PostStore) GetOldestEntityCreationTime() (int64, error) {
 	return r0, r1
 }
 
-// GetParentsForExportAfter provides a mock function with given fields: limit, afterID
-func (_m *PostStore) GetParentsForExportAfter(limit int, afterID string) ([]*model.PostForExport, error) {
-	ret := _m.Called(limit, afterID)
+// GetParentsForExportAfter provides a mock function with given fields: limit, afterID, channelIDs, since
+func (_m *PostStore) GetParentsForExportAfter(limit int, afterID string, channelIDs []string, since int64) ([]*model.PostForExport, error) {
+	ret := _m.Called(limit, afterID, channelIDs, since)
 
 	var r0 []*model.PostForExport
-	if rf, ok := ret.Get(0).(func(int, string) []*model.PostForExport); ok {
-		r0 = rf(limit, afterID)
+	if rf, ok := ret.Get(0).(func(int, string, []string, int64) []*model.PostForExport); ok {
+		r0 = rf(limit, afterID, channelIDs, since)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.PostForExport)
@@ -345,8 +345,8 @@ func (_m *PostStore) GetParentsForExportAfter(limit int, afterID string) ([]*mod
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int, string) error); ok {
-		r1 = rf(limit, afterID)
+	if rf, ok := ret.Get(1).(func(int, string, []string, int64) error); ok {
+		r1 = rf(limit, afterID, channelIDs, since)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -14,9 +14,9 @@ type TokenStore struct {
 	mock.Mock
 }
 
-// Cleanup provides a mock function with given fields: expiryTime
-func (_m *TokenStore) Cleanup(expiryTime int64) {
-	_m.Called(expiryTime)
+// Cleanup provides a mock function with given fields: expiryTime, keptTypes
+func (_m *TokenStore) Cleanup(expiryTime int64, keptTypes []string) {
+	_m.Called(expiryTime, keptTypes)
 }
 
 // Delete provides a mock function with given fields: token
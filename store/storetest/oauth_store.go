@@ -165,9 +165,14 @@ func testOAuthUpdateAccessData(t *testing.T, ss store.Store) {
 
 	// Should update fine
 	a1.RedirectUri = "http://example.com"
+	a1.Scope = model.OAuthScopeReadPosts
 	ra1, err := ss.OAuth().UpdateAccessData(&a1)
 	require.NoError(t, err)
 	require.NotEqual(t, ra1.RefreshToken, refreshToken, "refresh tokens didn't match")
+
+	ra1, err = ss.OAuth().GetAccessData(a1.Token)
+	require.NoError(t, err)
+	require.Equal(t, model.OAuthScopeReadPosts, ra1.Scope)
 }
 
 func testOAuthStoreGetAccessData(t *testing.T, ss store.Store) {
@@ -254,11 +259,17 @@ func testOAuthStoreGetAuthData(t *testing.T, ss store.Store) {
 	a1.UserId = model.NewId()
 	a1.Code = model.NewId()
 	a1.RedirectUri = "http://example.com"
+	a1.CodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	a1.CodeChallengeMethod = model.PKCEMethodS256
+	a1.Nonce = model.NewId()
 	_, err := ss.OAuth().SaveAuthData(&a1)
 	require.NoError(t, err)
 
-	_, err = ss.OAuth().GetAuthData(a1.Code)
+	a2, err := ss.OAuth().GetAuthData(a1.Code)
 	require.NoError(t, err)
+	require.Equal(t, a1.CodeChallenge, a2.CodeChallenge)
+	require.Equal(t, a1.CodeChallengeMethod, a2.CodeChallengeMethod)
+	require.Equal(t, a1.Nonce, a2.Nonce)
 }
 
 func testOAuthStoreRemoveAuthData(t *testing.T, ss store.Store) {
@@ -0,0 +1,108 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func TestPollVoteStore(t *testing.T, ss store.Store) {
+	t.Run("SetUserVotes", func(t *testing.T) { testPollVoteStoreSetUserVotes(t, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testPollVoteStorePermanentDeleteByUser(t, ss) })
+	t.Run("DeleteOrphanedRows", func(t *testing.T) { testPollVoteStoreDeleteOrphanedRows(t, ss) })
+}
+
+func makePollVotes(postID, userID string, createAt int64, optionIndexes ...int) []*model.PollVote {
+	votes := make([]*model.PollVote, 0, len(optionIndexes))
+	for _, index := range optionIndexes {
+		votes = append(votes, &model.PollVote{PostId: postID, UserId: userID, OptionIndex: index, CreateAt: createAt})
+	}
+	return votes
+}
+
+func testPollVoteStoreSetUserVotes(t *testing.T, ss store.Store) {
+	postID := model.NewId()
+	userID1 := model.NewId()
+	userID2 := model.NewId()
+
+	require.NoError(t, ss.PollVote().SetUserVotes(postID, userID1, makePollVotes(postID, userID1, 1000, 0, 2)))
+	require.NoError(t, ss.PollVote().SetUserVotes(postID, userID2, makePollVotes(postID, userID2, 2000, 1)))
+
+	votes, err := ss.PollVote().GetForPost(postID)
+	require.NoError(t, err)
+	require.Len(t, votes, 3)
+	assert.Equal(t, userID1, votes[0].UserId)
+	assert.Equal(t, 0, votes[0].OptionIndex)
+	assert.Equal(t, 2, votes[1].OptionIndex)
+	assert.Equal(t, userID2, votes[2].UserId)
+
+	t.Run("replace", func(t *testing.T) {
+		require.NoError(t, ss.PollVote().SetUserVotes(postID, userID1, makePollVotes(postID, userID1, 3000, 1)))
+		votes, err := ss.PollVote().GetForPost(postID)
+		require.NoError(t, err)
+		require.Len(t, votes, 2)
+		assert.Equal(t, userID2, votes[0].UserId)
+		assert.Equal(t, userID1, votes[1].UserId)
+		assert.Equal(t, 1, votes[1].OptionIndex)
+	})
+
+	t.Run("withdraw", func(t *testing.T) {
+		require.NoError(t, ss.PollVote().SetUserVotes(postID, userID1, nil))
+		votes, err := ss.PollVote().GetForPost(postID)
+		require.NoError(t, err)
+		require.Len(t, votes, 1)
+		assert.Equal(t, userID2, votes[0].UserId)
+	})
+
+	t.Run("votes of another user", func(t *testing.T) {
+		err := ss.PollVote().SetUserVotes(postID, userID1, makePollVotes(postID, userID2, 1000, 0))
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		require.Error(t, ss.PollVote().SetUserVotes(postID, userID1, makePollVotes(postID, userID1, 0, 0)))
+	})
+}
+
+func testPollVoteStorePermanentDeleteByUser(t *testing.T, ss store.Store) {
+	postID := model.NewId()
+	userID1 := model.NewId()
+	userID2 := model.NewId()
+	require.NoError(t, ss.PollVote().SetUserVotes(postID, userID1, makePollVotes(postID, userID1, 1000, 0)))
+	require.NoError(t, ss.PollVote().SetUserVotes(postID, userID2, makePollVotes(postID, userID2, 1000, 0)))
+
+	require.NoError(t, ss.PollVote().PermanentDeleteByUser(userID1))
+	votes, err := ss.PollVote().GetForPost(postID)
+	require.NoError(t, err)
+	require.Len(t, votes, 1)
+	assert.Equal(t, userID2, votes[0].UserId)
+}
+
+func testPollVoteStoreDeleteOrphanedRows(t *testing.T, ss store.Store) {
+	channel := makeDraftStoreChannel(t, ss, model.NewId())
+	post := makePostRevisionStorePost(t, ss, channel.Id)
+	orphanedPostID := model.NewId()
+	userID := model.NewId()
+
+	require.NoError(t, ss.PollVote().SetUserVotes(post.Id, userID, makePollVotes(post.Id, userID, 1000, 0)))
+	require.NoError(t, ss.PollVote().SetUserVotes(orphanedPostID, userID, makePollVotes(orphanedPostID, userID, 1000, 0)))
+
+	deleted, err := ss.PollVote().DeleteOrphanedRows(1000)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1))
+
+	votes, err := ss.PollVote().GetForPost(post.Id)
+	require.NoError(t, err)
+	assert.Len(t, votes, 1)
+	votes, err = ss.PollVote().GetForPost(orphanedPostID)
+	require.NoError(t, err)
+	assert.Empty(t, votes)
+}
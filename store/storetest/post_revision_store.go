@@ -0,0 +1,190 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func TestPostRevisionStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testPostRevisionStoreSave(t, ss) })
+	t.Run("GetForPost", func(t *testing.T) { testPostRevisionStoreGetForPost(t, ss) })
+	t.Run("PermanentDelete", func(t *testing.T) { testPostRevisionStorePermanentDelete(t, ss) })
+	t.Run("PermanentDeleteBatchForRetentionPolicies", func(t *testing.T) { testPostRevisionStorePermanentDeleteBatchForRetentionPolicies(t, ss) })
+	t.Run("DeleteOrphanedRows", func(t *testing.T) { testPostRevisionStoreDeleteOrphanedRows(t, ss) })
+}
+
+func makePostRevision(post *model.Post, message string, createAt int64) *model.PostRevision {
+	revision := model.NewPostRevision(post, "", createAt)
+	revision.Message = message
+	return revision
+}
+
+func makePostRevisionStorePost(t *testing.T, ss store.Store, channelID string) *model.Post {
+	post, err := ss.Post().Save(&model.Post{
+		ChannelId: channelID,
+		UserId:    model.NewId(),
+		Message:   "message",
+	})
+	require.NoError(t, err)
+	return post
+}
+
+func testPostRevisionStoreSave(t *testing.T, ss store.Store) {
+	post := &model.Post{Id: model.NewId(), ChannelId: model.NewId(), UserId: model.NewId(), Message: "message"}
+
+	revision, err := ss.PostRevision().Save(model.NewPostRevision(post, model.NewId(), 0))
+	require.NoError(t, err)
+	assert.True(t, model.IsValidId(revision.Id))
+	assert.NotZero(t, revision.CreateAt)
+
+	t.Run("existing id", func(t *testing.T) {
+		_, err := ss.PostRevision().Save(revision)
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ss.PostRevision().Save(&model.PostRevision{PostId: post.Id, ChannelId: "invalid", UserId: post.UserId})
+		require.Error(t, err)
+	})
+}
+
+func testPostRevisionStoreGetForPost(t *testing.T, ss store.Store) {
+	post := &model.Post{Id: model.NewId(), ChannelId: model.NewId(), UserId: model.NewId()}
+	post.AddProp("key", "value")
+	for i, message := range []string{"first", "second", "third"} {
+		_, err := ss.PostRevision().Save(makePostRevision(post, message, int64(1000+i)))
+		require.NoError(t, err)
+	}
+	_, err := ss.PostRevision().Save(makePostRevision(&model.Post{Id: model.NewId(), ChannelId: post.ChannelId, UserId: post.UserId}, "other", 1000))
+	require.NoError(t, err)
+
+	revisions, err := ss.PostRevision().GetForPost(post.Id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, "third", revisions[0].Message)
+	assert.Equal(t, "second", revisions[1].Message)
+	assert.Equal(t, "first", revisions[2].Message)
+	assert.Equal(t, "value", revisions[0].Props["key"])
+
+	revisions, err = ss.PostRevision().GetForPost(model.NewId())
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+}
+
+func testPostRevisionStorePermanentDelete(t *testing.T, ss store.Store) {
+	channelID := model.NewId()
+	post := &model.Post{Id: model.NewId(), ChannelId: channelID, UserId: model.NewId()}
+	otherPost := &model.Post{Id: model.NewId(), ChannelId: model.NewId(), UserId: model.NewId()}
+
+	revision, err := ss.PostRevision().Save(makePostRevision(post, "first", 1000))
+	require.NoError(t, err)
+	_, err = ss.PostRevision().Save(makePostRevision(post, "second", 1001))
+	require.NoError(t, err)
+	_, err = ss.PostRevision().Save(makePostRevision(otherPost, "other", 1000))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.PostRevision().PermanentDelete(revision.Id))
+	revisions, err := ss.PostRevision().GetForPost(post.Id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "second", revisions[0].Message)
+
+	require.NoError(t, ss.PostRevision().PermanentDeleteByChannel(channelID))
+	revisions, err = ss.PostRevision().GetForPost(post.Id)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	require.NoError(t, ss.PostRevision().PermanentDeleteByUser(otherPost.UserId))
+	revisions, err = ss.PostRevision().GetForPost(otherPost.Id)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+}
+
+func testPostRevisionStorePermanentDeleteBatchForRetentionPolicies(t *testing.T, ss store.Store) {
+	const limit = 1000
+	team, err := ss.Team().Save(&model.Team{
+		DisplayName: "DisplayName",
+		Name:        "team" + model.NewId(),
+		Email:       MakeEmail(),
+		Type:        model.TeamOpen,
+	})
+	require.NoError(t, err)
+	channel := makeDraftStoreChannel(t, ss, team.Id)
+	policyChannel := makeDraftStoreChannel(t, ss, team.Id)
+
+	post := makePostRevisionStorePost(t, ss, channel.Id)
+	policyPost := makePostRevisionStorePost(t, ss, policyChannel.Id)
+
+	_, err = ss.PostRevision().Save(makePostRevision(post, "old", 1000))
+	require.NoError(t, err)
+	_, err = ss.PostRevision().Save(makePostRevision(post, "recent", 5000))
+	require.NoError(t, err)
+	_, err = ss.PostRevision().Save(makePostRevision(policyPost, "old", 1000))
+	require.NoError(t, err)
+
+	channelPolicy, err := ss.RetentionPolicy().Save(&model.RetentionPolicyWithTeamAndChannelIDs{
+		RetentionPolicy: model.RetentionPolicy{
+			DisplayName:  "DisplayName",
+			PostDuration: model.NewInt64(30),
+		},
+		ChannelIDs: []string{policyChannel.Id},
+	})
+	require.NoError(t, err)
+	defer ss.RetentionPolicy().Delete(channelPolicy.ID)
+
+	t.Run("global policy", func(t *testing.T) {
+		_, _, err := ss.PostRevision().PermanentDeleteBatchForRetentionPolicies(0, 2000, limit, model.RetentionPolicyCursor{})
+		require.NoError(t, err)
+
+		revisions, err := ss.PostRevision().GetForPost(post.Id)
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, "recent", revisions[0].Message)
+
+		// The granular policy of the channel overrides the global policy.
+		revisions, err = ss.PostRevision().GetForPost(policyPost.Id)
+		require.NoError(t, err)
+		assert.Len(t, revisions, 1)
+	})
+
+	t.Run("channel policy", func(t *testing.T) {
+		nowMillis := 1000 + *channelPolicy.PostDuration*24*60*60*1000 + 1
+		_, _, err := ss.PostRevision().PermanentDeleteBatchForRetentionPolicies(nowMillis, 0, limit, model.RetentionPolicyCursor{})
+		require.NoError(t, err)
+
+		revisions, err := ss.PostRevision().GetForPost(policyPost.Id)
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+	})
+}
+
+func testPostRevisionStoreDeleteOrphanedRows(t *testing.T, ss store.Store) {
+	channel := makeDraftStoreChannel(t, ss, model.NewId())
+	post := makePostRevisionStorePost(t, ss, channel.Id)
+	orphanedPost := &model.Post{Id: model.NewId(), ChannelId: channel.Id, UserId: model.NewId()}
+
+	_, err := ss.PostRevision().Save(makePostRevision(post, "message", 1000))
+	require.NoError(t, err)
+	_, err = ss.PostRevision().Save(makePostRevision(orphanedPost, "message", 1000))
+	require.NoError(t, err)
+
+	deleted, err := ss.PostRevision().DeleteOrphanedRows(1000)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1))
+
+	revisions, err := ss.PostRevision().GetForPost(post.Id)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 1)
+	revisions, err = ss.PostRevision().GetForPost(orphanedPost.Id)
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+}
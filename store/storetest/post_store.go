@@ -52,6 +52,7 @@ func TestPostStore(t *testing.T, ss store.Store, s SqlStore) {
 	t.Run("GetOldest", func(t *testing.T) { testPostStoreGetOldest(t, ss) })
 	t.Run("TestGetMaxPostSize", func(t *testing.T) { testGetMaxPostSize(t, ss) })
 	t.Run("GetParentsForExportAfter", func(t *testing.T) { testPostStoreGetParentsForExportAfter(t, ss) })
+	t.Run("GetParentsForExportAfterScoped", func(t *testing.T) { testPostStoreGetParentsForExportAfterScoped(t, ss) })
 	t.Run("GetRepliesForExport", func(t *testing.T) { testPostStoreGetRepliesForExport(t, ss) })
 	t.Run("GetDirectPostParentsForExportAfter", func(t *testing.T) { testPostStoreGetDirectPostParentsForExportAfter(t, ss, s) })
 	t.Run("GetDirectPostParentsForExportAfterDeleted", func(t *testing.T) { testPostStoreGetDirectPostParentsForExportAfterDeleted(t, ss, s) })
@@ -2208,7 +2209,7 @@ func testPostStoreGetFlaggedPostsForTeam(t *testing.T, ss store.Store, s SqlStor
 	require.Len(t, r4.Order, 3, "should have 3 posts")
 
 	// Manually truncate Channels table until testlib can handle cleanups
-	s.GetMasterX().Exec("TRUNCATE Channels")
+	s.GetMasterX().Exec("DELETE FROM Channels")
 }
 
 func testPostStoreGetFlaggedPosts(t *testing.T, ss store.Store) {
@@ -3149,7 +3150,7 @@ func testPostStoreGetParentsForExportAfter(t *testing.T, ss store.Store) {
 	p1, nErr = ss.Post().Save(p1)
 	require.NoError(t, nErr)
 
-	posts, err := ss.Post().GetParentsForExportAfter(10000, strings.Repeat("0", 26))
+	posts, err := ss.Post().GetParentsForExportAfter(10000, strings.Repeat("0", 26), nil, 0)
 	assert.NoError(t, err)
 
 	found := false
@@ -3166,6 +3167,90 @@ func testPostStoreGetParentsForExportAfter(t *testing.T, ss store.Store) {
 	assert.True(t, found)
 }
 
+func testPostStoreGetParentsForExportAfterScoped(t *testing.T, ss store.Store) {
+	t1 := model.Team{}
+	t1.DisplayName = "Name"
+	t1.Name = NewTestId()
+	t1.Email = MakeEmail()
+	t1.Type = model.TeamOpen
+	_, err := ss.Team().Save(&t1)
+	require.NoError(t, err)
+
+	c1 := model.Channel{}
+	c1.TeamId = t1.Id
+	c1.DisplayName = "Channel1"
+	c1.Name = NewTestId()
+	c1.Type = model.ChannelTypeOpen
+	_, err = ss.Channel().Save(&c1, -1)
+	require.NoError(t, err)
+
+	c2 := model.Channel{}
+	c2.TeamId = t1.Id
+	c2.DisplayName = "Channel2"
+	c2.Name = NewTestId()
+	c2.Type = model.ChannelTypeOpen
+	_, err = ss.Channel().Save(&c2, -1)
+	require.NoError(t, err)
+
+	u1 := model.User{}
+	u1.Username = model.NewId()
+	u1.Email = MakeEmail()
+	u1.Nickname = model.NewId()
+	_, err = ss.User().Save(&u1)
+	require.NoError(t, err)
+
+	savePost := func(channelId, rootId string, createAt int64) *model.Post {
+		post, err := ss.Post().Save(&model.Post{
+			ChannelId: channelId,
+			UserId:    u1.Id,
+			RootId:    rootId,
+			Message:   NewTestId(),
+			CreateAt:  createAt,
+		})
+		require.NoError(t, err)
+		return post
+	}
+
+	oldPost := savePost(c1.Id, "", 1000)
+	oldThread := savePost(c1.Id, "", 1000)
+	savePost(c1.Id, oldThread.Id, 5000)
+	newPost := savePost(c1.Id, "", 5000)
+	otherChannelPost := savePost(c2.Id, "", 5000)
+
+	exportedIds := func(channelIds []string, since int64) map[string]bool {
+		posts, err := ss.Post().GetParentsForExportAfter(10000, strings.Repeat("0", 26), channelIds, since)
+		require.NoError(t, err)
+
+		ids := map[string]bool{}
+		for _, p := range posts {
+			ids[p.Id] = true
+		}
+		return ids
+	}
+
+	t.Run("channels", func(t *testing.T) {
+		ids := exportedIds([]string{c1.Id}, 0)
+		assert.Equal(t, map[string]bool{oldPost.Id: true, oldThread.Id: true, newPost.Id: true}, ids)
+	})
+
+	t.Run("no channels", func(t *testing.T) {
+		assert.Empty(t, exportedIds([]string{}, 0))
+	})
+
+	t.Run("since", func(t *testing.T) {
+		ids := exportedIds(nil, 3000)
+		assert.False(t, ids[oldPost.Id])
+		assert.True(t, ids[oldThread.Id], "threads with a newer reply are exported")
+		assert.True(t, ids[newPost.Id])
+		assert.True(t, ids[otherChannelPost.Id])
+	})
+
+	t.Run("channels and since", func(t *testing.T) {
+		ids := exportedIds([]string{c1.Id}, 3000)
+		assert.Equal(t, map[string]bool{oldThread.Id: true, newPost.Id: true}, ids)
+	})
+}
+
 func testPostStoreGetRepliesForExport(t *testing.T, ss store.Store) {
 	t1 := model.Team{}
 	t1.DisplayName = "Name"
@@ -3278,13 +3363,13 @@ func testPostStoreGetDirectPostParentsForExportAfter(t *testing.T, ss store.Stor
 	p1, nErr = ss.Post().Save(p1)
 	require.NoError(t, nErr)
 
-	r1, nErr := ss.Post().GetDirectPostParentsForExportAfter(10000, strings.Repeat("0", 26))
+	r1, nErr := ss.Post().GetDirectPostParentsForExportAfter(10000, strings.Repeat("0", 26), 0)
 	assert.NoError(t, nErr)
 
 	assert.Equal(t, p1.Message, r1[0].Message)
 
 	// Manually truncate Channels table until testlib can handle cleanups
-	s.GetMasterX().Exec("TRUNCATE Channels")
+	s.GetMasterX().Exec("DELETE FROM Channels")
 }
 
 func testPostStoreGetDirectPostParentsForExportAfterDeleted(t *testing.T, ss store.Store, s SqlStore) {
@@ -3344,13 +3429,13 @@ func testPostStoreGetDirectPostParentsForExportAfterDeleted(t *testing.T, ss sto
 	_, nErr = ss.Post().Update(o1a, p1)
 	require.NoError(t, nErr)
 
-	r1, nErr := ss.Post().GetDirectPostParentsForExportAfter(10000, strings.Repeat("0", 26))
+	r1, nErr := ss.Post().GetDirectPostParentsForExportAfter(10000, strings.Repeat("0", 26), 0)
 	assert.NoError(t, nErr)
 
 	assert.Equal(t, 0, len(r1))
 
 	// Manually truncate Channels table until testlib can handle cleanups
-	s.GetMasterX().Exec("TRUNCATE Channels")
+	s.GetMasterX().Exec("DELETE FROM Channels")
 }
 
 func testPostStoreGetDirectPostParentsForExportAfterBatched(t *testing.T, ss store.Store, s SqlStore) {
@@ -3404,7 +3489,7 @@ func testPostStoreGetDirectPostParentsForExportAfterBatched(t *testing.T, ss sto
 	sort.Slice(postIds, func(i, j int) bool { return postIds[i] < postIds[j] })
 
 	// Get all posts
-	r1, err := ss.Post().GetDirectPostParentsForExportAfter(10000, strings.Repeat("0", 26))
+	r1, err := ss.Post().GetDirectPostParentsForExportAfter(10000, strings.Repeat("0", 26), 0)
 	assert.NoError(t, err)
 	assert.Equal(t, len(postIds), len(r1))
 	var exportedPostIds []string
@@ -3415,7 +3500,7 @@ func testPostStoreGetDirectPostParentsForExportAfterBatched(t *testing.T, ss sto
 	assert.ElementsMatch(t, postIds, exportedPostIds)
 
 	// Get 100
-	r1, err = ss.Post().GetDirectPostParentsForExportAfter(100, strings.Repeat("0", 26))
+	r1, err = ss.Post().GetDirectPostParentsForExportAfter(100, strings.Repeat("0", 26), 0)
 	assert.NoError(t, err)
 	assert.Equal(t, 100, len(r1))
 	exportedPostIds = []string{}
@@ -3426,7 +3511,7 @@ func testPostStoreGetDirectPostParentsForExportAfterBatched(t *testing.T, ss sto
 	assert.ElementsMatch(t, postIds[:100], exportedPostIds)
 
 	// Manually truncate Channels table until testlib can handle cleanups
-	s.GetMasterX().Exec("TRUNCATE Channels")
+	s.GetMasterX().Exec("DELETE FROM Channels")
 }
 
 func testHasAutoResponsePostByUserSince(t *testing.T, ss store.Store) {
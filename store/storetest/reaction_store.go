@@ -134,6 +134,8 @@ func testReactionDelete(t *testing.T, ss store.Store) {
 
 		firstUpdateAt := result.Posts[post.Id].UpdateAt
 
+		time.Sleep(time.Millisecond)
+
 		_, nErr = ss.Reaction().Delete(reaction)
 		require.NoError(t, nErr)
 
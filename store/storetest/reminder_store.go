@@ -0,0 +1,151 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func TestReminderStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testReminderStoreSave(t, ss) })
+	t.Run("GetForUser", func(t *testing.T) { testReminderStoreGetForUser(t, ss) })
+	t.Run("GetDueReminders", func(t *testing.T) { testReminderStoreGetDueReminders(t, ss) })
+	t.Run("Update", func(t *testing.T) { testReminderStoreUpdate(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testReminderStoreDelete(t, ss) })
+}
+
+func makeReminder(userID string, targetAt int64) *model.Reminder {
+	return &model.Reminder{
+		UserId:   userID,
+		Message:  "message",
+		TargetAt: targetAt,
+	}
+}
+
+func testReminderStoreSave(t *testing.T, ss store.Store) {
+	reminder, err := ss.Reminder().Save(&model.Reminder{UserId: model.NewId(), PostId: model.NewId(), TargetAt: 1000})
+	require.NoError(t, err)
+	assert.True(t, model.IsValidId(reminder.Id))
+	assert.NotZero(t, reminder.CreateAt)
+
+	got, err := ss.Reminder().Get(reminder.Id)
+	require.NoError(t, err)
+	assert.Equal(t, reminder, got)
+
+	t.Run("existing id", func(t *testing.T) {
+		_, err := ss.Reminder().Save(reminder)
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ss.Reminder().Save(&model.Reminder{UserId: model.NewId(), TargetAt: 1000})
+		require.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := ss.Reminder().Get(model.NewId())
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testReminderStoreGetForUser(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	later, err := ss.Reminder().Save(makeReminder(userID, 2000))
+	require.NoError(t, err)
+	sooner, err := ss.Reminder().Save(makeReminder(userID, 1000))
+	require.NoError(t, err)
+	_, err = ss.Reminder().Save(makeReminder(model.NewId(), 1000))
+	require.NoError(t, err)
+
+	reminders, err := ss.Reminder().GetForUser(userID)
+	require.NoError(t, err)
+	require.Len(t, reminders, 2)
+	assert.Equal(t, sooner.Id, reminders[0].Id)
+	assert.Equal(t, later.Id, reminders[1].Id)
+
+	reminders, err = ss.Reminder().GetForUser(model.NewId())
+	require.NoError(t, err)
+	assert.Empty(t, reminders)
+}
+
+func testReminderStoreGetDueReminders(t *testing.T, ss store.Store) {
+	// The reminders are far in the past so that no other test saves reminders before them.
+	userID := model.NewId()
+	var due []*model.Reminder
+	for i := 0; i < 3; i++ {
+		reminder, err := ss.Reminder().Save(makeReminder(userID, 10))
+		require.NoError(t, err)
+		due = append(due, reminder)
+	}
+	fired, err := ss.Reminder().Save(makeReminder(userID, 10))
+	require.NoError(t, err)
+	fired.FiredAt = 20
+	require.NoError(t, ss.Reminder().Update(fired))
+	_, err = ss.Reminder().Save(makeReminder(userID, 30))
+	require.NoError(t, err)
+
+	reminders, err := ss.Reminder().GetDueReminders(20, 0, "", 2)
+	require.NoError(t, err)
+	require.Len(t, reminders, 2)
+
+	last := reminders[1]
+	next, err := ss.Reminder().GetDueReminders(20, last.TargetAt, last.Id, 2)
+	require.NoError(t, err)
+	require.Len(t, next, 1)
+
+	ids := []string{reminders[0].Id, reminders[1].Id, next[0].Id}
+	for _, reminder := range due {
+		assert.Contains(t, ids, reminder.Id)
+	}
+}
+
+func testReminderStoreUpdate(t *testing.T, ss store.Store) {
+	reminder, err := ss.Reminder().Save(makeReminder(model.NewId(), 1000))
+	require.NoError(t, err)
+
+	reminder.TargetAt = 2000
+	reminder.FiredAt = 1500
+	require.NoError(t, ss.Reminder().Update(reminder))
+
+	got, err := ss.Reminder().Get(reminder.Id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2000), got.TargetAt)
+	assert.Equal(t, int64(1500), got.FiredAt)
+
+	t.Run("not found", func(t *testing.T) {
+		missing := makeReminder(model.NewId(), 1000)
+		missing.PreSave()
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, ss.Reminder().Update(missing), &nfErr)
+	})
+}
+
+func testReminderStoreDelete(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	reminder, err := ss.Reminder().Save(makeReminder(userID, 1000))
+	require.NoError(t, err)
+	_, err = ss.Reminder().Save(makeReminder(userID, 2000))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.Reminder().Delete(reminder.Id))
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, ss.Reminder().Delete(reminder.Id), &nfErr)
+
+	reminders, err := ss.Reminder().GetForUser(userID)
+	require.NoError(t, err)
+	assert.Len(t, reminders, 1)
+
+	require.NoError(t, ss.Reminder().PermanentDeleteByUser(userID))
+	reminders, err = ss.Reminder().GetForUser(userID)
+	require.NoError(t, err)
+	assert.Empty(t, reminders)
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func TestScheduledPostStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testScheduledPostStoreSave(t, ss) })
+	t.Run("GetForUser", func(t *testing.T) { testScheduledPostStoreGetForUser(t, ss) })
+	t.Run("GetPendingScheduledPosts", func(t *testing.T) { testScheduledPostStoreGetPendingScheduledPosts(t, ss) })
+	t.Run("Update", func(t *testing.T) { testScheduledPostStoreUpdate(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testScheduledPostStoreDelete(t, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testScheduledPostStorePermanentDeleteByUser(t, ss) })
+}
+
+func makeScheduledPost(userID, channelID string, scheduledAt int64) *model.ScheduledPost {
+	return &model.ScheduledPost{
+		Draft: model.Draft{
+			UserId:    userID,
+			ChannelId: channelID,
+			Message:   "message",
+		},
+		ScheduledAt: scheduledAt,
+	}
+}
+
+func testScheduledPostStoreSave(t *testing.T, ss store.Store) {
+	scheduledPost, err := ss.ScheduledPost().Save(makeScheduledPost(model.NewId(), model.NewId(), model.GetMillis()+60000))
+	require.NoError(t, err)
+	require.NotEmpty(t, scheduledPost.Id)
+
+	got, err := ss.ScheduledPost().Get(scheduledPost.Id)
+	require.NoError(t, err)
+	assert.Equal(t, scheduledPost, got)
+
+	t.Run("existing id", func(t *testing.T) {
+		_, err := ss.ScheduledPost().Save(scheduledPost)
+		var invErr *store.ErrInvalidInput
+		require.ErrorAs(t, err, &invErr)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		empty := makeScheduledPost(model.NewId(), model.NewId(), model.GetMillis())
+		empty.Message = ""
+		_, err := ss.ScheduledPost().Save(empty)
+		require.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := ss.ScheduledPost().Get(model.NewId())
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testScheduledPostStoreGetForUser(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	teamID := model.NewId()
+	channel := makeDraftStoreChannel(t, ss, teamID)
+	otherTeamChannel := makeDraftStoreChannel(t, ss, model.NewId())
+	directChannel := makeDraftStoreChannel(t, ss, "")
+
+	later, err := ss.ScheduledPost().Save(makeScheduledPost(userID, channel.Id, 2000))
+	require.NoError(t, err)
+	sooner, err := ss.ScheduledPost().Save(makeScheduledPost(userID, directChannel.Id, 1000))
+	require.NoError(t, err)
+	_, err = ss.ScheduledPost().Save(makeScheduledPost(userID, otherTeamChannel.Id, 1000))
+	require.NoError(t, err)
+	_, err = ss.ScheduledPost().Save(makeScheduledPost(model.NewId(), channel.Id, 1000))
+	require.NoError(t, err)
+
+	scheduledPosts, err := ss.ScheduledPost().GetForUser(userID, teamID)
+	require.NoError(t, err)
+	require.Len(t, scheduledPosts, 2)
+	assert.Equal(t, sooner.Id, scheduledPosts[0].Id)
+	assert.Equal(t, later.Id, scheduledPosts[1].Id)
+}
+
+func testScheduledPostStoreGetPendingScheduledPosts(t *testing.T, ss store.Store) {
+	// The scheduled times are in the future, to not get the posts of the other tests.
+	now := model.GetMillis() + 24*60*60*1000
+	userID := model.NewId()
+	channelID := model.NewId()
+
+	var expected []string
+	for i := 0; i < 3; i++ {
+		scheduledPost, err := ss.ScheduledPost().Save(makeScheduledPost(userID, channelID, now-1000))
+		require.NoError(t, err)
+		expected = append(expected, scheduledPost.Id)
+	}
+	// The posts with the same scheduled time are ordered by id.
+	sort.Strings(expected)
+	scheduledPost, err := ss.ScheduledPost().Save(makeScheduledPost(userID, channelID, now))
+	require.NoError(t, err)
+	expected = append(expected, scheduledPost.Id)
+
+	_, err = ss.ScheduledPost().Save(makeScheduledPost(userID, channelID, now+1000))
+	require.NoError(t, err)
+	processed, err := ss.ScheduledPost().Save(makeScheduledPost(userID, channelID, now))
+	require.NoError(t, err)
+	processed.ProcessedAt = model.GetMillis()
+	processed.ErrorCode = model.ScheduledPostErrorUnknown
+	require.NoError(t, ss.ScheduledPost().Update(processed))
+
+	var got []string
+	afterTime := now - 2000
+	afterID := ""
+	for {
+		page, err := ss.ScheduledPost().GetPendingScheduledPosts(now, afterTime, afterID, 2)
+		require.NoError(t, err)
+		for _, scheduledPost := range page {
+			got = append(got, scheduledPost.Id)
+		}
+		if len(page) < 2 {
+			break
+		}
+		afterTime = page[len(page)-1].ScheduledAt
+		afterID = page[len(page)-1].Id
+	}
+	assert.Equal(t, expected, got)
+}
+
+func testScheduledPostStoreUpdate(t *testing.T, ss store.Store) {
+	scheduledPost, err := ss.ScheduledPost().Save(makeScheduledPost(model.NewId(), model.NewId(), 1000))
+	require.NoError(t, err)
+
+	scheduledPost.Message = "updated"
+	scheduledPost.ScheduledAt = 2000
+	scheduledPost.ProcessedAt = 3000
+	scheduledPost.ErrorCode = model.ScheduledPostErrorChannelArchived
+	require.NoError(t, ss.ScheduledPost().Update(scheduledPost))
+
+	got, err := ss.ScheduledPost().Get(scheduledPost.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", got.Message)
+	assert.EqualValues(t, 2000, got.ScheduledAt)
+	assert.EqualValues(t, 3000, got.ProcessedAt)
+	assert.Equal(t, model.ScheduledPostErrorChannelArchived, got.ErrorCode)
+
+	t.Run("not found", func(t *testing.T) {
+		missing := makeScheduledPost(model.NewId(), model.NewId(), 1000)
+		missing.PreSave()
+		err := ss.ScheduledPost().Update(missing)
+		var nfErr *store.ErrNotFound
+		require.ErrorAs(t, err, &nfErr)
+	})
+}
+
+func testScheduledPostStoreDelete(t *testing.T, ss store.Store) {
+	scheduledPost, err := ss.ScheduledPost().Save(makeScheduledPost(model.NewId(), model.NewId(), 1000))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.ScheduledPost().Delete(scheduledPost.Id))
+	_, err = ss.ScheduledPost().Get(scheduledPost.Id)
+	var nfErr *store.ErrNotFound
+	require.ErrorAs(t, err, &nfErr)
+
+	err = ss.ScheduledPost().Delete(scheduledPost.Id)
+	require.ErrorAs(t, err, &nfErr)
+}
+
+func testScheduledPostStorePermanentDeleteByUser(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	scheduledPost, err := ss.ScheduledPost().Save(makeScheduledPost(userID, model.NewId(), 1000))
+	require.NoError(t, err)
+	other, err := ss.ScheduledPost().Save(makeScheduledPost(model.NewId(), model.NewId(), 1000))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.ScheduledPost().PermanentDeleteByUser(userID))
+
+	_, err = ss.ScheduledPost().Get(scheduledPost.Id)
+	require.Error(t, err)
+	_, err = ss.ScheduledPost().Get(other.Id)
+	require.NoError(t, err)
+}
@@ -10,11 +10,13 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
 
 	"github.com/go-sql-driver/mysql"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
 	"github.com/pkg/errors"
+	_ "modernc.org/sqlite"
 
 	"github.com/cjdelisle/matterfoss-server/v6/model"
 )
@@ -82,6 +84,15 @@ func PostgreSQLSettings() *model.SqlSettings {
 	return databaseSettings("postgres", dsnURL.String())
 }
 
+// SQLiteSettings returns the database settings to use a new SQLite unittesting database.
+// The database file is created on first use in the directory named by TEST_DATABASE_SQLITE_DIR,
+// or in the temporary directory.
+func SQLiteSettings() *model.SqlSettings {
+	dir := getEnv("TEST_DATABASE_SQLITE_DIR", os.TempDir())
+
+	return databaseSettings("sqlite", filepath.Join(dir, "db"+model.NewId()+".sqlite"))
+}
+
 func mySQLRootDSN(dsn string) string {
 	rootPwd := getEnv("TEST_DATABASE_MYSQL_ROOT_PASSWD", defaultMysqlRootPWD)
 	cfg, err := mysql.ParseDSN(dsn)
@@ -209,6 +220,11 @@ func MakeSqlSettings(driver string, withReplica bool) *model.SqlSettings {
 	case model.DatabaseDriverPostgres:
 		settings = PostgreSQLSettings()
 		dbName = postgreSQLDSNDatabase(*settings.DataSource)
+	case model.DatabaseDriverSqlite:
+		// The database file is created when the store first connects to it.
+		settings = SQLiteSettings()
+		log("Using temporary " + driver + " database " + *settings.DataSource)
+		return settings
 	default:
 		panic("unsupported driver " + driver)
 	}
@@ -244,6 +260,12 @@ func CleanupSqlSettings(settings *model.SqlSettings) {
 		dbName = mySQLDSNDatabase(*settings.DataSource)
 	case model.DatabaseDriverPostgres:
 		dbName = postgreSQLDSNDatabase(*settings.DataSource)
+	case model.DatabaseDriverSqlite:
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			os.Remove(*settings.DataSource + suffix)
+		}
+		log("Removed temporary database " + *settings.DataSource)
+		return
 	default:
 		panic("unsupported driver " + driver)
 	}
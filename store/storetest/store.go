@@ -53,6 +53,14 @@ type Store struct {
 	LinkMetadataStore         mocks.LinkMetadataStore
 	SharedChannelStore        mocks.SharedChannelStore
 	ProductNoticesStore       mocks.ProductNoticesStore
+	DraftStore                mocks.DraftStore
+	ScheduledPostStore        mocks.ScheduledPostStore
+	PostRevisionStore         mocks.PostRevisionStore
+	ReminderStore             mocks.ReminderStore
+	PollVoteStore             mocks.PollVoteStore
+	ChannelBookmarkStore      mocks.ChannelBookmarkStore
+	WebAuthnCredentialStore   mocks.WebAuthnCredentialStore
+	MfaRecoveryCodeStore      mocks.MfaRecoveryCodeStore
 	context                   context.Context
 }
 
@@ -94,9 +102,23 @@ func (s *Store) UserTermsOfService() store.UserTermsOfServiceStore { return &s.U
 func (s *Store) ChannelMemberHistory() store.ChannelMemberHistoryStore {
 	return &s.ChannelMemberHistoryStore
 }
+func (s *Store) ChannelBookmark() store.ChannelBookmarkStore {
+	return &s.ChannelBookmarkStore
+}
+func (s *Store) WebAuthnCredential() store.WebAuthnCredentialStore {
+	return &s.WebAuthnCredentialStore
+}
+func (s *Store) MfaRecoveryCode() store.MfaRecoveryCodeStore {
+	return &s.MfaRecoveryCodeStore
+}
 func (s *Store) Group() store.GroupStore                 { return &s.GroupStore }
 func (s *Store) LinkMetadata() store.LinkMetadataStore   { return &s.LinkMetadataStore }
 func (s *Store) SharedChannel() store.SharedChannelStore { return &s.SharedChannelStore }
+func (s *Store) Draft() store.DraftStore                 { return &s.DraftStore }
+func (s *Store) ScheduledPost() store.ScheduledPostStore { return &s.ScheduledPostStore }
+func (s *Store) PostRevision() store.PostRevisionStore   { return &s.PostRevisionStore }
+func (s *Store) Reminder() store.ReminderStore           { return &s.ReminderStore }
+func (s *Store) PollVote() store.PollVoteStore           { return &s.PollVoteStore }
 func (s *Store) MarkSystemRanUnitTests()                 { /* do nothing */ }
 func (s *Store) Close()                                  { /* do nothing */ }
 func (s *Store) LockToMaster()                           { /* do nothing */ }
@@ -151,5 +173,13 @@ func (s *Store) AssertExpectations(t mock.TestingT) bool {
 		&s.ThreadStore,
 		&s.ProductNoticesStore,
 		&s.SharedChannelStore,
+		&s.DraftStore,
+		&s.ScheduledPostStore,
+		&s.PostRevisionStore,
+		&s.ReminderStore,
+		&s.PollVoteStore,
+		&s.ChannelBookmarkStore,
+		&s.WebAuthnCredentialStore,
+		&s.MfaRecoveryCodeStore,
 	)
 }
@@ -15,6 +15,7 @@ import (
 
 func TestTokensStore(t *testing.T, ss store.Store) {
 	t.Run("TokensCleanup", func(t *testing.T) { testTokensCleanup(t, ss) })
+	t.Run("TokensCleanupKeptTypes", func(t *testing.T) { testTokensCleanupKeptTypes(t, ss) })
 }
 
 func testTokensCleanup(t *testing.T, ss store.Store) {
@@ -34,9 +35,36 @@ func testTokensCleanup(t *testing.T, ss store.Store) {
 	require.NoError(t, err)
 	assert.Len(t, tokens, 10)
 
-	ss.Token().Cleanup(now + int64(1))
+	ss.Token().Cleanup(now+int64(1), nil)
 
 	tokens, err = ss.Token().GetAllTokensByType(model.TokenTypeOAuth)
 	require.NoError(t, err)
 	assert.Len(t, tokens, 0)
 }
+
+func testTokensCleanupKeptTypes(t *testing.T, ss store.Store) {
+	now := model.GetMillis()
+	keptType := "kept_" + model.NewId()
+
+	for _, tokenType := range []string{model.TokenTypeOAuth, keptType} {
+		err := ss.Token().Save(&model.Token{
+			Token:    model.NewRandomString(model.TokenSize),
+			CreateAt: now - 1,
+			Type:     tokenType,
+			Extra:    "",
+		})
+		require.NoError(t, err)
+	}
+
+	ss.Token().Cleanup(now, []string{keptType})
+
+	tokens, err := ss.Token().GetAllTokensByType(model.TokenTypeOAuth)
+	require.NoError(t, err)
+	assert.Len(t, tokens, 0)
+
+	tokens, err = ss.Token().GetAllTokensByType(keptType)
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	require.NoError(t, ss.Token().RemoveAllTokensByType(keptType))
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cjdelisle/matterfoss-server/v6/model"
+	"github.com/cjdelisle/matterfoss-server/v6/store"
+)
+
+func TestWebAuthnCredentialStore(t *testing.T, ss store.Store) {
+	t.Run("Save", func(t *testing.T) { testWebAuthnCredentialStoreSave(t, ss) })
+	t.Run("GetByCredentialId", func(t *testing.T) { testWebAuthnCredentialStoreGetByCredentialId(t, ss) })
+	t.Run("GetForUser", func(t *testing.T) { testWebAuthnCredentialStoreGetForUser(t, ss) })
+	t.Run("Update", func(t *testing.T) { testWebAuthnCredentialStoreUpdate(t, ss) })
+	t.Run("UpdateSignCount", func(t *testing.T) { testWebAuthnCredentialStoreUpdateSignCount(t, ss) })
+	t.Run("Delete", func(t *testing.T) { testWebAuthnCredentialStoreDelete(t, ss) })
+	t.Run("PermanentDeleteByUser", func(t *testing.T) { testWebAuthnCredentialStorePermanentDeleteByUser(t, ss) })
+}
+
+func makeWebAuthnCredential(userID string) *model.WebAuthnCredential {
+	return &model.WebAuthnCredential{
+		UserId:       userID,
+		Name:         "Security key",
+		CredentialId: model.NewRandomString(43),
+		PublicKey:    model.NewRandomString(100),
+		Transports:   "usb,nfc",
+	}
+}
+
+func testWebAuthnCredentialStoreSave(t *testing.T, ss store.Store) {
+	credential, err := ss.WebAuthnCredential().Save(makeWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+	assert.True(t, model.IsValidId(credential.Id))
+	assert.NotZero(t, credential.CreateAt)
+	assert.Equal(t, credential.CreateAt, credential.UpdateAt)
+
+	saved, err := ss.WebAuthnCredential().Get(credential.Id)
+	require.NoError(t, err)
+	assert.Equal(t, credential, saved)
+
+	t.Run("should not save a credential with an id", func(t *testing.T) {
+		credential := makeWebAuthnCredential(model.NewId())
+		credential.Id = model.NewId()
+		_, err := ss.WebAuthnCredential().Save(credential)
+		var invErr *store.ErrInvalidInput
+		require.True(t, errors.As(err, &invErr))
+	})
+
+	t.Run("should not save an invalid credential", func(t *testing.T) {
+		credential := makeWebAuthnCredential(model.NewId())
+		credential.Name = ""
+		_, err := ss.WebAuthnCredential().Save(credential)
+		require.Error(t, err)
+	})
+
+	t.Run("should not register a credential twice", func(t *testing.T) {
+		duplicate := makeWebAuthnCredential(model.NewId())
+		duplicate.CredentialId = credential.CredentialId
+		_, err := ss.WebAuthnCredential().Save(duplicate)
+		var conflictErr *store.ErrConflict
+		require.True(t, errors.As(err, &conflictErr))
+	})
+}
+
+func testWebAuthnCredentialStoreGetByCredentialId(t *testing.T, ss store.Store) {
+	credential, err := ss.WebAuthnCredential().Save(makeWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+
+	found, err := ss.WebAuthnCredential().GetByCredentialId(credential.CredentialId)
+	require.NoError(t, err)
+	assert.Equal(t, credential, found)
+
+	_, err = ss.WebAuthnCredential().GetByCredentialId(model.NewRandomString(43))
+	var nfErr *store.ErrNotFound
+	require.True(t, errors.As(err, &nfErr))
+}
+
+func testWebAuthnCredentialStoreGetForUser(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	first, err := ss.WebAuthnCredential().Save(makeWebAuthnCredential(userID))
+	require.NoError(t, err)
+	second := makeWebAuthnCredential(userID)
+	second.CreateAt = first.CreateAt + 1
+	second, err = ss.WebAuthnCredential().Save(second)
+	require.NoError(t, err)
+	_, err = ss.WebAuthnCredential().Save(makeWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+
+	credentials, err := ss.WebAuthnCredential().GetForUser(userID)
+	require.NoError(t, err)
+	require.Len(t, credentials, 2)
+	assert.Equal(t, first.Id, credentials[0].Id)
+	assert.Equal(t, second.Id, credentials[1].Id)
+
+	count, err := ss.WebAuthnCredential().CountForUser(userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	credentials, err = ss.WebAuthnCredential().GetForUser(model.NewId())
+	require.NoError(t, err)
+	assert.Empty(t, credentials)
+}
+
+func testWebAuthnCredentialStoreUpdate(t *testing.T, ss store.Store) {
+	credential, err := ss.WebAuthnCredential().Save(makeWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+
+	credential.Name = "Laptop"
+	updated, err := ss.WebAuthnCredential().Update(credential)
+	require.NoError(t, err)
+	assert.Equal(t, "Laptop", updated.Name)
+
+	saved, err := ss.WebAuthnCredential().Get(credential.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "Laptop", saved.Name)
+	assert.Equal(t, credential.UpdateAt, saved.UpdateAt)
+
+	t.Run("should not update a missing credential", func(t *testing.T) {
+		missing := makeWebAuthnCredential(model.NewId())
+		missing.PreSave()
+		_, err := ss.WebAuthnCredential().Update(missing)
+		var nfErr *store.ErrNotFound
+		require.True(t, errors.As(err, &nfErr))
+	})
+}
+
+func testWebAuthnCredentialStoreUpdateSignCount(t *testing.T, ss store.Store) {
+	credential, err := ss.WebAuthnCredential().Save(makeWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+
+	lastUsedAt := model.GetMillis()
+	require.NoError(t, ss.WebAuthnCredential().UpdateSignCount(credential.Id, 42, lastUsedAt))
+
+	saved, err := ss.WebAuthnCredential().Get(credential.Id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), saved.SignCount)
+	assert.Equal(t, lastUsedAt, saved.LastUsedAt)
+
+	err = ss.WebAuthnCredential().UpdateSignCount(model.NewId(), 1, lastUsedAt)
+	var nfErr *store.ErrNotFound
+	require.True(t, errors.As(err, &nfErr))
+}
+
+func testWebAuthnCredentialStoreDelete(t *testing.T, ss store.Store) {
+	credential, err := ss.WebAuthnCredential().Save(makeWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.WebAuthnCredential().Delete(credential.Id))
+
+	_, err = ss.WebAuthnCredential().Get(credential.Id)
+	var nfErr *store.ErrNotFound
+	require.True(t, errors.As(err, &nfErr))
+
+	err = ss.WebAuthnCredential().Delete(credential.Id)
+	require.True(t, errors.As(err, &nfErr))
+}
+
+func testWebAuthnCredentialStorePermanentDeleteByUser(t *testing.T, ss store.Store) {
+	userID := model.NewId()
+	_, err := ss.WebAuthnCredential().Save(makeWebAuthnCredential(userID))
+	require.NoError(t, err)
+	_, err = ss.WebAuthnCredential().Save(makeWebAuthnCredential(userID))
+	require.NoError(t, err)
+	other, err := ss.WebAuthnCredential().Save(makeWebAuthnCredential(model.NewId()))
+	require.NoError(t, err)
+
+	require.NoError(t, ss.WebAuthnCredential().PermanentDeleteByUser(userID))
+
+	credentials, err := ss.WebAuthnCredential().GetForUser(userID)
+	require.NoError(t, err)
+	assert.Empty(t, credentials)
+
+	_, err = ss.WebAuthnCredential().Get(other.Id)
+	require.NoError(t, err)
+}